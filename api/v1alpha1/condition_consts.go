@@ -132,3 +132,14 @@ const (
 	// VirtualMachineImage provider doesn't meet security compliance requirements.
 	VirtualMachineImageProviderSecurityNotCompliantReason = "VirtualMachineImageProviderSecurityNotCompliant"
 )
+
+// Condition types and reasons for the ContentSource object.
+const (
+	// ContentLibrarySyncedCondition documents that the subscribed content library backing the ContentSource
+	// has synced with its publisher recently enough that its items are not stale.
+	ContentLibrarySyncedCondition ConditionType = "ContentLibrarySynced"
+
+	// ContentLibrarySyncStaleReason (Severity=Warning) documents that the subscribed content library backing the
+	// ContentSource has not synced with its publisher within the staleness threshold.
+	ContentLibrarySyncStaleReason = "ContentLibrarySyncStale"
+)
@@ -7,6 +7,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// SyncContentLibraryAnnotation is an annotation that may be set on a ContentSource to request a
+	// one-shot sync of its subscribed content library. The annotation is removed once the sync is
+	// triggered. The sync is a no-op for a local content library.
+	SyncContentLibraryAnnotation = "contentsource." + GroupName + "/sync"
+)
+
 // ContentProviderReference contains the info to locate a content provider resource.
 type ContentProviderReference struct {
 	// API version of the referent.
@@ -27,10 +34,14 @@ type ContentSourceSpec struct {
 
 // ContentSourceStatus defines the observed state of ContentSource.
 type ContentSourceStatus struct {
+	// Conditions describes the current condition information of the ContentSource.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
 
 // ContentSource is the Schema for the contentsources API.
 // A ContentSource represents the desired specification and the observed status of a ContentSource instance.
@@ -42,6 +53,14 @@ type ContentSource struct {
 	Status ContentSourceStatus `json:"status,omitempty"`
 }
 
+func (contentSource *ContentSource) GetConditions() Conditions {
+	return contentSource.Status.Conditions
+}
+
+func (contentSource *ContentSource) SetConditions(conditions Conditions) {
+	contentSource.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // ContentSourceList contains a list of ContentSource.
@@ -196,6 +196,9 @@ func overrideVirtualMachineImageFieldsFuncs(codecs runtimeserializer.CodecFactor
 			overrideConditionsObservedGeneration(imageStatus.Conditions)
 			// TODO: Need to save serialized object to support lossless conversions.
 			imageStatus.Capabilities = nil
+			imageStatus.Disks = nil
+			imageStatus.NetworkInterfaceTypes = nil
+			imageStatus.RecommendedResources = nil
 		},
 	}
 }
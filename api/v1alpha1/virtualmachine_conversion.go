@@ -795,6 +795,7 @@ func restore_v1alpha2_VirtualMachineBootstrapSpec(
 			dstCloudInit.CloudConfig = srcCloudInit.CloudConfig
 			dstCloudInit.RawCloudConfig = mergeSecretKeySelector(dstCloudInit.RawCloudConfig, srcCloudInit.RawCloudConfig)
 			dstCloudInit.SSHAuthorizedKeys = srcCloudInit.SSHAuthorizedKeys
+			dstCloudInit.NetworkConfigMode = srcCloudInit.NetworkConfigMode
 		}
 	}
 
@@ -832,6 +833,8 @@ func restore_v1alpha2_VirtualMachineNetwork(
 
 	dstNetwork.HostName = srcNetwork.HostName
 	dstNetwork.Disabled = srcNetwork.Disabled
+	dstNetwork.Nameservers = srcNetwork.Nameservers
+	dstNetwork.SearchDomains = srcNetwork.SearchDomains
 
 	if len(dstNetwork.Interfaces) == 0 {
 		// No interfaces so nothing to fixup (the interfaces were removed): we ignore the restored interfaces.
@@ -858,6 +861,41 @@ func restore_v1alpha2_VirtualMachineNetwork(
 	}
 }
 
+func restore_v1alpha2_VirtualMachineVolumes(
+	dst, src *v1alpha2.VirtualMachine) {
+
+	// A FirstClassDisk volume does not have a source in v1a1 so restore it if the volume is still
+	// present and was not changed to a different source.
+	srcFCDs := map[string]*v1alpha2.FirstClassDiskVolumeSource{}
+	for _, vol := range src.Spec.Volumes {
+		if vol.FirstClassDisk != nil {
+			srcFCDs[vol.Name] = vol.FirstClassDisk
+		}
+	}
+
+	for i := range dst.Spec.Volumes {
+		vol := &dst.Spec.Volumes[i]
+		if fcd, ok := srcFCDs[vol.Name]; ok && vol.PersistentVolumeClaim == nil {
+			vol.FirstClassDisk = fcd
+		}
+	}
+
+	srcStatus := map[string]v1alpha2.VirtualMachineVolumeStatus{}
+	for _, vol := range src.Status.Volumes {
+		srcStatus[vol.Name] = vol
+	}
+
+	for i := range dst.Status.Volumes {
+		vol := &dst.Status.Volumes[i]
+		if srcVol, ok := srcStatus[vol.Name]; ok {
+			vol.DiskID = srcVol.DiskID
+			vol.ControllerKey = srcVol.ControllerKey
+			vol.UnitNumber = srcVol.UnitNumber
+			vol.Capacity = srcVol.Capacity
+		}
+	}
+}
+
 // ConvertTo converts this VirtualMachine to the Hub version.
 func (src *VirtualMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha2.VirtualMachine)
@@ -873,6 +911,7 @@ func (src *VirtualMachine) ConvertTo(dstRaw conversion.Hub) error {
 
 	restore_v1alpha2_VirtualMachineBootstrapSpec(dst, restored)
 	restore_v1alpha2_VirtualMachineNetwork(dst, restored)
+	restore_v1alpha2_VirtualMachineVolumes(dst, restored)
 
 	if restored.Spec.ReadinessProbe != nil {
 		if dst.Spec.ReadinessProbe == nil {
@@ -881,6 +920,67 @@ func (src *VirtualMachine) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.ReadinessProbe.GuestInfo = restored.Spec.ReadinessProbe.GuestInfo
 	}
 	dst.Spec.ReadinessGates = restored.Spec.ReadinessGates
+	dst.Spec.VAppProperties = restored.Spec.VAppProperties
+	if restored.Spec.Advanced != nil && len(restored.Spec.Advanced.CPUAffinity) > 0 {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.CPUAffinity = restored.Spec.Advanced.CPUAffinity
+	}
+	if restored.Spec.Advanced != nil && restored.Spec.Advanced.SwapPlacement != "" {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.SwapPlacement = restored.Spec.Advanced.SwapPlacement
+	}
+	if restored.Spec.Advanced != nil && restored.Spec.Advanced.HARestartPriority != "" {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.HARestartPriority = restored.Spec.Advanced.HARestartPriority
+	}
+	if restored.Spec.Advanced != nil && restored.Spec.Advanced.HAIsolationResponse != "" {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.HAIsolationResponse = restored.Spec.Advanced.HAIsolationResponse
+	}
+	if restored.Spec.Advanced != nil && len(restored.Spec.Advanced.BootOrder) > 0 {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.BootOrder = restored.Spec.Advanced.BootOrder
+	}
+	if restored.Spec.Advanced != nil && len(restored.Spec.Advanced.SerialPorts) > 0 {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.SerialPorts = restored.Spec.Advanced.SerialPorts
+	}
+	if restored.Spec.Advanced != nil && restored.Spec.Advanced.ToolsUpgradePolicy != "" {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.ToolsUpgradePolicy = restored.Spec.Advanced.ToolsUpgradePolicy
+	}
+	if restored.Spec.Advanced != nil && restored.Spec.Advanced.BootDiskStorageClass != "" {
+		if dst.Spec.Advanced == nil {
+			dst.Spec.Advanced = &v1alpha2.VirtualMachineAdvancedSpec{}
+		}
+		dst.Spec.Advanced.BootDiskStorageClass = restored.Spec.Advanced.BootDiskStorageClass
+	}
+	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+	dst.Status.ObservedClassGeneration = restored.Status.ObservedClassGeneration
+	dst.Status.Devices = restored.Status.Devices
+	dst.Status.Task = restored.Status.Task
+	dst.Status.HostMoID = restored.Status.HostMoID
+	dst.Status.BootTime = restored.Status.BootTime
+	dst.Status.ResourceAllocation = restored.Status.ResourceAllocation
+	dst.Status.Tags = restored.Status.Tags
+	dst.Status.ReconfigurePlan = restored.Status.ReconfigurePlan
+	dst.Status.GuestDisks = restored.Status.GuestDisks
+	dst.Status.SerialPorts = restored.Status.SerialPorts
+	dst.Status.DestroyTime = restored.Status.DestroyTime
 
 	return nil
 }
@@ -26,6 +26,7 @@ func (src *VirtualMachineClass) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 
+	dst.Spec.InheritsFrom = restored.Spec.InheritsFrom
 	dst.Status = restored.Status
 	return nil
 }
@@ -53,6 +54,12 @@ func (dst *VirtualMachineClassList) ConvertFrom(srcRaw conversion.Hub) error {
 	return Convert_v1alpha2_VirtualMachineClassList_To_v1alpha1_VirtualMachineClassList(src, dst, nil)
 }
 
+func Convert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec(
+	in *v1alpha2.VirtualMachineClassSpec, out *VirtualMachineClassSpec, s apiconversion.Scope) error {
+
+	return autoConvert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec(in, out, s)
+}
+
 func Convert_v1alpha2_VirtualMachineClassStatus_To_v1alpha1_VirtualMachineClassStatus(
 	in *v1alpha2.VirtualMachineClassStatus, out *VirtualMachineClassStatus, s apiconversion.Scope) error {
 
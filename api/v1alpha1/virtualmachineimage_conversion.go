@@ -240,6 +240,9 @@ func Convert_v1alpha2_VirtualMachineImageStatus_To_v1alpha1_VirtualMachineImageS
 	// out.ContentLibraryRef =
 
 	// in.Capabilities
+	// in.Disks
+	// in.NetworkInterfaceTypes
+	// in.RecommendedResources
 
 	out.Conditions = convert_v1alpha2_VirtualMachineImageStatusConditions_To_v1alpha1_VirtualMachineImageStatusConditions(in.Conditions)
 
@@ -252,11 +252,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha2.VirtualMachineClassSpec)(nil), (*VirtualMachineClassSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec(a.(*v1alpha2.VirtualMachineClassSpec), b.(*VirtualMachineClassSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VirtualMachineClassStatus)(nil), (*v1alpha2.VirtualMachineClassStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_VirtualMachineClassStatus_To_v1alpha2_VirtualMachineClassStatus(a.(*VirtualMachineClassStatus), b.(*v1alpha2.VirtualMachineClassStatus), scope)
 	}); err != nil {
@@ -552,6 +547,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha2.VirtualMachineClassSpec)(nil), (*VirtualMachineClassSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec(a.(*v1alpha2.VirtualMachineClassSpec), b.(*VirtualMachineClassSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha2.VirtualMachineClassStatus)(nil), (*VirtualMachineClassStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VirtualMachineClassStatus_To_v1alpha1_VirtualMachineClassStatus(a.(*v1alpha2.VirtualMachineClassStatus), b.(*VirtualMachineClassStatus), scope)
 	}); err != nil {
@@ -1250,14 +1250,10 @@ func autoConvert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClas
 	}
 	out.Description = in.Description
 	out.ConfigSpec = *(*json.RawMessage)(unsafe.Pointer(&in.ConfigSpec))
+	// WARNING: in.InheritsFrom requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec is an autogenerated conversion function.
-func Convert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec(in *v1alpha2.VirtualMachineClassSpec, out *VirtualMachineClassSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha2_VirtualMachineClassSpec_To_v1alpha1_VirtualMachineClassSpec(in, out, s)
-}
-
 func autoConvert_v1alpha1_VirtualMachineClassStatus_To_v1alpha2_VirtualMachineClassStatus(in *VirtualMachineClassStatus, out *v1alpha2.VirtualMachineClassStatus, s conversion.Scope) error {
 	return nil
 }
@@ -1444,12 +1440,15 @@ func autoConvert_v1alpha1_VirtualMachineImageStatus_To_v1alpha2_VirtualMachineIm
 func autoConvert_v1alpha2_VirtualMachineImageStatus_To_v1alpha1_VirtualMachineImageStatus(in *v1alpha2.VirtualMachineImageStatus, out *VirtualMachineImageStatus, s conversion.Scope) error {
 	// WARNING: in.Name requires manual conversion: does not exist in peer-type
 	// WARNING: in.Capabilities requires manual conversion: does not exist in peer-type
+	// WARNING: in.Disks requires manual conversion: does not exist in peer-type
 	out.Firmware = in.Firmware
+	// WARNING: in.NetworkInterfaceTypes requires manual conversion: does not exist in peer-type
 	// WARNING: in.HardwareVersion requires manual conversion: does not exist in peer-type
 	// WARNING: in.OSInfo requires manual conversion: does not exist in peer-type
 	// WARNING: in.OVFProperties requires manual conversion: does not exist in peer-type
 	// WARNING: in.VMwareSystemProperties requires manual conversion: does not exist in peer-type
 	// WARNING: in.ProductInfo requires manual conversion: does not exist in peer-type
+	// WARNING: in.RecommendedResources requires manual conversion: does not exist in peer-type
 	// WARNING: in.ProviderContentVersion requires manual conversion: does not exist in peer-type
 	// WARNING: in.ProviderItemID requires manual conversion: does not exist in peer-type
 	if in.Conditions != nil {
@@ -2083,6 +2082,7 @@ func autoConvert_v1alpha2_VirtualMachineSpec_To_v1alpha1_VirtualMachineSpec(in *
 	out.ClassName = in.ClassName
 	out.StorageClass = in.StorageClass
 	// WARNING: in.Bootstrap requires manual conversion: does not exist in peer-type
+	// WARNING: in.VAppProperties requires manual conversion: does not exist in peer-type
 	// WARNING: in.Network requires manual conversion: does not exist in peer-type
 	out.PowerState = VirtualMachinePowerState(in.PowerState)
 	out.PowerOffMode = VirtualMachinePowerOpMode(in.PowerOffMode)
@@ -2158,6 +2158,8 @@ func autoConvert_v1alpha2_VirtualMachineStatus_To_v1alpha1_VirtualMachineStatus(
 	// WARNING: in.Image requires manual conversion: does not exist in peer-type
 	// WARNING: in.Class requires manual conversion: does not exist in peer-type
 	out.Host = in.Host
+	// WARNING: in.HostMoID requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootTime requires manual conversion: does not exist in peer-type
 	out.PowerState = VirtualMachinePowerState(in.PowerState)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -2188,7 +2190,17 @@ func autoConvert_v1alpha2_VirtualMachineStatus_To_v1alpha1_VirtualMachineStatus(
 	out.ChangeBlockTracking = (*bool)(unsafe.Pointer(in.ChangeBlockTracking))
 	out.Zone = in.Zone
 	out.LastRestartTime = (*v1.Time)(unsafe.Pointer(in.LastRestartTime))
+	// WARNING: in.DestroyTime requires manual conversion: does not exist in peer-type
 	out.HardwareVersion = in.HardwareVersion
+	// WARNING: in.ObservedGeneration requires manual conversion: does not exist in peer-type
+	// WARNING: in.ObservedClassGeneration requires manual conversion: does not exist in peer-type
+	// WARNING: in.Devices requires manual conversion: does not exist in peer-type
+	// WARNING: in.Task requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceAllocation requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tags requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReconfigurePlan requires manual conversion: does not exist in peer-type
+	// WARNING: in.GuestDisks requires manual conversion: does not exist in peer-type
+	// WARNING: in.SerialPorts requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -2259,6 +2271,10 @@ func autoConvert_v1alpha2_VirtualMachineVolumeStatus_To_v1alpha1_VirtualMachineV
 	out.Name = in.Name
 	out.Attached = in.Attached
 	// WARNING: in.DiskUUID requires manual conversion: does not exist in peer-type
+	// WARNING: in.DiskID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControllerKey requires manual conversion: does not exist in peer-type
+	// WARNING: in.UnitNumber requires manual conversion: does not exist in peer-type
+	// WARNING: in.Capacity requires manual conversion: does not exist in peer-type
 	out.Error = in.Error
 	return nil
 }
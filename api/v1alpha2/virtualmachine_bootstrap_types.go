@@ -111,8 +111,40 @@ type VirtualMachineBootstrapCloudInitSpec struct {
 	//
 	// +optional
 	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+
+	// NetworkConfigMode describes how the guest's network configuration is
+	// provided to Cloud-Init.
+	//
+	// When set to Inline, the network configuration is inlined in the
+	// Cloud-Init metadata, as it always has been.
+	//
+	// When set to NetworkConfigV2, a complete Cloud-Init network-config v2
+	// document is rendered for all of the VM's network interfaces, including
+	// their routes and nameservers. The gateway of each interface is rendered
+	// as a default route, with the interfaces after the first having a higher
+	// metric, which is better suited to VMs with multiple network interfaces.
+	//
+	// If omitted, the mode defaults to Inline.
+	//
+	// +optional
+	NetworkConfigMode CloudInitNetworkConfigMode `json:"networkConfigMode,omitempty"`
 }
 
+// CloudInitNetworkConfigMode describes how the guest's network configuration
+// is provided to Cloud-Init.
+// +kubebuilder:validation:Enum=Inline;NetworkConfigV2
+type CloudInitNetworkConfigMode string
+
+const (
+	// CloudInitNetworkConfigModeInline indicates the network configuration is
+	// inlined in the Cloud-Init metadata.
+	CloudInitNetworkConfigModeInline CloudInitNetworkConfigMode = "Inline"
+
+	// CloudInitNetworkConfigModeV2 indicates the network configuration is a
+	// Cloud-Init network-config v2 document.
+	CloudInitNetworkConfigModeV2 CloudInitNetworkConfigMode = "NetworkConfigV2"
+)
+
 // VirtualMachineBootstrapLinuxPrepSpec describes the LinuxPrep configuration
 // used to bootstrap the VM.
 type VirtualMachineBootstrapLinuxPrepSpec struct {
@@ -122,8 +122,11 @@ type VirtualMachineNetworkInterfaceSpec struct {
 
 	// MTU is the Maximum Transmission Unit size in bytes.
 	//
+	// Please note the MTU must be between 576 and 9000.
+	//
 	// Please note this feature is available only with the following bootstrap
-	// providers: CloudInit.
+	// providers: CloudInit. The guest customization used by the Sysprep
+	// bootstrap provider ignores the MTU.
 	//
 	// +optional
 	MTU *int64 `json:"mtu,omitempty"`
@@ -160,8 +163,10 @@ type VirtualMachineNetworkInterfaceSpec struct {
 
 // VirtualMachineNetworkSpec defines a VM's desired network configuration.
 type VirtualMachineNetworkSpec struct {
-	// HostName is the value the guest uses as its host name.
-	// If omitted then the name of the VM will be used.
+	// HostName is the value the guest uses as its host name. It may also be
+	// a fully qualified domain name (FQDN), such as "my-vm.domain.local", in
+	// which case the guest's domain name is also set. The value must be a
+	// valid DNS name. If omitted then the name of the VM will be used.
 	//
 	// Please note this feature is available only with the following bootstrap
 	// providers: CloudInit, LinuxPrep, and Sysprep (except for RawSysprep).
@@ -187,6 +192,35 @@ type VirtualMachineNetworkSpec struct {
 	// +listType=map
 	// +listMapKey=name
 	Interfaces []VirtualMachineNetworkInterfaceSpec `json:"interfaces,omitempty"`
+
+	// Nameservers is a list of IP4 and/or IP6 addresses used as DNS
+	// nameservers for this VM.
+	//
+	// The guest's nameservers are taken from the first of the following that
+	// is not empty: the interface's Nameservers field, this field, and the
+	// nameservers from the global vmoperator-network-config ConfigMap.
+	//
+	// Please note this feature is available only with the following bootstrap
+	// providers: CloudInit, LinuxPrep, and Sysprep (except for RawSysprep).
+	//
+	// Please note that Linux allows only three nameservers
+	// (https://linux.die.net/man/5/resolv.conf).
+	//
+	// +optional
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// SearchDomains is a list of search domains used when resolving IP
+	// addresses with DNS for this VM.
+	//
+	// The guest's search domains are taken from the first of the following
+	// that is not empty: the interface's SearchDomains field, this field, and
+	// the search domains from the global vmoperator-network-config ConfigMap.
+	//
+	// Please note this feature is available only with the following bootstrap
+	// providers: CloudInit, LinuxPrep, and Sysprep (except for RawSysprep).
+	//
+	// +optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
 }
 
 // VirtualMachineNetworkDNSStatus describes the observed state of the guest's
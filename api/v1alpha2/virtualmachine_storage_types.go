@@ -42,6 +42,20 @@ type VirtualMachineVolumeSource struct {
 	//
 	// +optional
 	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// FirstClassDisk represents a reference to an existing First Class Disk
+	// (FCD) that is managed outside of Kubernetes. The disk is attached to
+	// the VM as-is, and it is detached but not deleted when the volume is
+	// removed from the VM.
+	//
+	// +optional
+	FirstClassDisk *FirstClassDiskVolumeSource `json:"firstClassDisk,omitempty"`
+}
+
+// FirstClassDiskVolumeSource describes an existing First Class Disk.
+type FirstClassDiskVolumeSource struct {
+	// DiskID is the ID of the First Class Disk.
+	DiskID string `json:"diskID"`
 }
 
 // PersistentVolumeClaimVolumeSource is a composite for the Kubernetes
@@ -81,6 +95,26 @@ type VirtualMachineVolumeStatus struct {
 	// +optional
 	DiskUUID string `json:"diskUUID,omitempty"`
 
+	// DiskID is the ID of the First Class Disk and is only present for
+	// FirstClassDisk volumes.
+	// +optional
+	DiskID string `json:"diskID,omitempty"`
+
+	// ControllerKey is the device key of the controller the disk is attached
+	// to. It is only present for FirstClassDisk volumes.
+	// +optional
+	ControllerKey int32 `json:"controllerKey,omitempty"`
+
+	// UnitNumber is the unit number of the disk on its controller. It is only
+	// present for FirstClassDisk volumes.
+	// +optional
+	UnitNumber *int32 `json:"unitNumber,omitempty"`
+
+	// Capacity is the capacity of the disk. It is only present for
+	// FirstClassDisk volumes.
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
 	// Error represents the last error seen when attaching or detaching a
 	// volume.  Error will be empty if attachment succeeds.
 	// +optional
@@ -23,6 +23,21 @@ const (
 	// For more information please see VirtualMachineImage.Status.Ready.
 	VirtualMachineConditionImageReady = "VirtualMachineImageReady"
 
+	// VirtualMachineImageNotFoundReason documents that the referenced
+	// VirtualMachineImage does not exist or is not available in the VM's
+	// namespace.
+	VirtualMachineImageNotFoundReason = "VirtualMachineImageNotFound"
+
+	// VirtualMachineImageNotReadyReason documents that the referenced
+	// VirtualMachineImage exists but is not yet Ready, ex. it is still being
+	// imported.
+	VirtualMachineImageNotReadyReason = "VirtualMachineImageNotReady"
+
+	// VirtualMachineImageAmbiguousReason documents that the VM's image name
+	// is not the name of a VirtualMachineImage, and more than one image was
+	// created from a content library item with the name.
+	VirtualMachineImageAmbiguousReason = "VirtualMachineImageAmbiguous"
+
 	// VirtualMachineConditionVMSetResourcePolicyReady indicates that a referenced
 	// VirtualMachineSetResourcePolicy is Ready.
 	VirtualMachineConditionVMSetResourcePolicyReady = "VirtualMachineConditionVMSetResourcePolicyReady"
@@ -36,6 +51,11 @@ const (
 	// VirtualMachineConditionNetworkReady indicates that the network prerequisites for the VM are ready.
 	VirtualMachineConditionNetworkReady = "VirtualMachineNetworkReady"
 
+	// VirtualMachineNetworkInterfaceNotReadyReason documents that the network
+	// for one or more of the VM's network interfaces could not be resolved.
+	// The condition's message names the interfaces that failed.
+	VirtualMachineNetworkInterfaceNotReadyReason = "NetworkInterfaceNotReady"
+
 	// VirtualMachineConditionPlacementReady indicates that the placement decision for the VM is ready.
 	VirtualMachineConditionPlacementReady = "VirtualMachineConditionPlacementReady"
 
@@ -43,6 +63,218 @@ const (
 	VirtualMachineConditionCreated = "VirtualMachineCreated"
 )
 
+const (
+	// VirtualMachineConditionClassConfigurationSynced indicates that the VM's
+	// configuration matches its VirtualMachineClass.
+	VirtualMachineConditionClassConfigurationSynced = "VirtualMachineClassConfigurationSynced"
+
+	// VirtualMachineClassConfigurationPendingPowerCycleReason documents that
+	// the VirtualMachineClass changes cannot be applied while the VM is
+	// powered on, and will be applied the next time the VM is powered on.
+	VirtualMachineClassConfigurationPendingPowerCycleReason = "PendingPowerCycle"
+
+	// VirtualMachineClassConfigurationReconfigureFailedReason documents that
+	// the reconfigure to apply the VirtualMachineClass changes failed.
+	VirtualMachineClassConfigurationReconfigureFailedReason = "ReconfigureFailed"
+)
+
+const (
+	// VirtualMachineConditionNetworkInterfaceCompatible indicates that the
+	// network interface types declared by the VM's image are compatible with
+	// the network interfaces that will back the VM's network. A false
+	// condition is only a warning, and does not prevent the VM from being
+	// created.
+	VirtualMachineConditionNetworkInterfaceCompatible = "VirtualMachineNetworkInterfaceCompatible"
+
+	// VirtualMachineNetworkInterfaceIncompatibleReason documents that a
+	// network interface type is not one the VM's image declares.
+	VirtualMachineNetworkInterfaceIncompatibleReason = "NetworkInterfaceIncompatible"
+)
+
+const (
+	// VirtualMachineConditionHardwareVersionUpgraded indicates that the VM's
+	// hardware version has been upgraded to the hardware version targeted by
+	// the provider's hardware version upgrade policy.
+	VirtualMachineConditionHardwareVersionUpgraded = "VirtualMachineHardwareVersionUpgraded"
+
+	// VirtualMachineHardwareVersionUpgradePendingPowerOffReason documents that
+	// the VM's hardware version is below the target, and the VM will be
+	// upgraded the next time it is powered off.
+	VirtualMachineHardwareVersionUpgradePendingPowerOffReason = "PendingPowerOff"
+
+	// VirtualMachineHardwareVersionUpgradeFailedReason documents that the
+	// upgrade of the VM's hardware version failed.
+	VirtualMachineHardwareVersionUpgradeFailedReason = "UpgradeFailed"
+
+	// VirtualMachineHardwareVersionUpgradeNotSupportedReason documents that
+	// the VM's cluster does not support upgrading the VM to the target
+	// hardware version.
+	VirtualMachineHardwareVersionUpgradeNotSupportedReason = "UpgradeNotSupported"
+)
+
+const (
+	// VirtualMachineConditionBootOrderSynced indicates that the VM's boot order
+	// matches the boot order in its spec.
+	VirtualMachineConditionBootOrderSynced = "VirtualMachineBootOrderSynced"
+
+	// VirtualMachineBootOrderPendingPowerOffReason documents that the boot
+	// order cannot be changed while the VM is powered on, and will be changed
+	// the next time the VM is powered off.
+	VirtualMachineBootOrderPendingPowerOffReason = "PendingPowerOff"
+
+	// VirtualMachineBootOrderDeviceNotFoundReason documents that the VM does
+	// not have any device of a type in the boot order, so that type was left
+	// out of the VM's boot order.
+	VirtualMachineBootOrderDeviceNotFoundReason = "DeviceNotFound"
+)
+
+const (
+	// VirtualMachineConditionSerialPortsSynced indicates that the VM's network
+	// serial ports match its spec.
+	VirtualMachineConditionSerialPortsSynced = "VirtualMachineSerialPortsSynced"
+
+	// VirtualMachineSerialPortsPendingPowerOffReason documents that the serial
+	// ports cannot be added to or removed from the powered on VM, and will be
+	// changed the next time the VM is powered off.
+	VirtualMachineSerialPortsPendingPowerOffReason = "PendingPowerOff"
+)
+
+const (
+	// VirtualMachineConditionDisplayNameSynced indicates that the name of the
+	// vSphere VM in the vCenter inventory matches the VM's display name
+	// annotation.
+	VirtualMachineConditionDisplayNameSynced = "VirtualMachineDisplayNameSynced"
+
+	// VirtualMachineDisplayNameConflictReason documents that the VM was not
+	// renamed because another object in the VM's folder already has the
+	// display name.
+	VirtualMachineDisplayNameConflictReason = "DisplayNameConflict"
+
+	// VirtualMachineDisplayNameRenameFailedReason documents that the VM could
+	// not be renamed to its display name.
+	VirtualMachineDisplayNameRenameFailedReason = "RenameFailed"
+)
+
+const (
+	// VirtualMachineConditionTagsSynced indicates that the vSphere tags of the
+	// VM's labels with a configured tag category are attached to the VM.
+	VirtualMachineConditionTagsSynced = "VirtualMachineTagsSynced"
+
+	// VirtualMachineTagsSyncFailedReason documents that the vSphere tags of
+	// the VM's labels could not be synced.
+	VirtualMachineTagsSyncFailedReason = "SyncFailed"
+)
+
+const (
+	// VirtualMachineConditionHAOverrideSynced indicates that the VM's vSphere
+	// HA override in its cluster matches the HA settings in its spec.
+	VirtualMachineConditionHAOverrideSynced = "VirtualMachineHAOverrideSynced"
+
+	// VirtualMachineHAOverrideSyncFailedReason documents that the VM's vSphere
+	// HA override could not be synced, ex. because vSphere HA is not enabled
+	// on the VM's cluster.
+	VirtualMachineHAOverrideSyncFailedReason = "SyncFailed"
+)
+
+const (
+	// VirtualMachineConditionHARestarted is an informational condition that
+	// indicates vSphere HA restarted the VM on another host after the host it
+	// was running on failed. The condition is removed once it has been
+	// reported by a status update.
+	VirtualMachineConditionHARestarted = "VirtualMachineHARestarted"
+
+	// VirtualMachineHARestartedHostFailureReason documents that vSphere HA
+	// restarted the VM on another host because its previous host failed.
+	VirtualMachineHARestartedHostFailureReason = "HostFailure"
+)
+
+const (
+	// VirtualMachineConditionDryRun indicates that the provider is in read-only
+	// mode, and the VM was only validated. The reason documents the change that
+	// would otherwise have been made to the VM.
+	VirtualMachineConditionDryRun = "VirtualMachineDryRun"
+
+	// VirtualMachineDryRunWouldCreateReason documents that the VM would have
+	// been created.
+	VirtualMachineDryRunWouldCreateReason = "WouldCreate"
+
+	// VirtualMachineDryRunWouldUpdateReason documents that the VM would have
+	// been updated.
+	VirtualMachineDryRunWouldUpdateReason = "WouldUpdate"
+
+	// VirtualMachineDryRunNoChangeReason documents that the VM already matches
+	// its spec and would not have been changed.
+	VirtualMachineDryRunNoChangeReason = "NoChange"
+)
+
+const (
+	// VirtualMachineConditionZoneCompatible indicates that the availability
+	// zones the VM may be placed in can create VMs with the hardware version
+	// that the VM's image requires. When only some of the zones are
+	// incompatible, the condition is true and the VM is placed in a compatible
+	// zone, but the condition's reason and message name the incompatible zones.
+	VirtualMachineConditionZoneCompatible = "VirtualMachineZoneCompatible"
+
+	// VirtualMachineZoneIncompatibleReason documents that one or more of the
+	// zones cannot create VMs with the image's hardware version. The
+	// condition's message names the zones and their incompatible clusters.
+	VirtualMachineZoneIncompatibleReason = "ZoneIncompatible"
+)
+
+const (
+	// VirtualMachineConditionInstanceStoragePlacement indicates whether the
+	// VM's instance storage volumes were placed on the VM's selected host. The
+	// condition is false when the volumes failed to be placed on one or more
+	// hosts, and the condition's message names the attempted hosts. The
+	// condition is true once the volumes are placed.
+	VirtualMachineConditionInstanceStoragePlacement = "VirtualMachineInstanceStoragePlacement"
+
+	// VirtualMachineInstanceStoragePlacementRetryingReason documents that the
+	// instance storage volumes failed to be placed on the attempted hosts, and
+	// placement selects another host.
+	VirtualMachineInstanceStoragePlacementRetryingReason = "RetryingOnAnotherHost"
+
+	// VirtualMachineInstanceStorageHostsExhaustedReason documents that the
+	// instance storage volumes failed to be placed on every candidate host, so
+	// placement is not retried. Remove the
+	// vmoperator.vmware.com/instance-storage-failed-node-moids annotation from
+	// the VM to retry placement on all the hosts.
+	VirtualMachineInstanceStorageHostsExhaustedReason = "HostsExhausted"
+)
+
+const (
+	// VirtualMachineConditionVolumesAttached indicates whether the VM's
+	// PersistentVolumeClaim volumes are attached to the VM. The condition is
+	// false when a volume's attachment failed, or the volume was not attached
+	// within the attach timeout, and the condition's message names the
+	// volumes. The condition is removed when the VM has no such volumes.
+	VirtualMachineConditionVolumesAttached = "VirtualMachineVolumesAttached"
+
+	// VirtualMachineVolumesAttachPendingReason documents that one or more of
+	// the volumes are still being attached.
+	VirtualMachineVolumesAttachPendingReason = "AttachPending"
+
+	// VirtualMachineVolumesAttachFailedReason documents that the attachment
+	// of one or more of the volumes failed.
+	VirtualMachineVolumesAttachFailedReason = "AttachFailed"
+
+	// VirtualMachineVolumesAttachTimedOutReason documents that one or more of
+	// the volumes were not attached within the attach timeout.
+	VirtualMachineVolumesAttachTimedOutReason = "AttachTimedOut"
+)
+
+const (
+	// VirtualMachineConditionConverged indicates that the VM's observed state
+	// matches its spec. When the VM is not converged, the condition's reason
+	// and message describe the signal that is blocking the VM, ex. that its
+	// image is not ready.
+	VirtualMachineConditionConverged = "VirtualMachineConverged"
+
+	// VirtualMachineReconcileFailedReason documents that the VM failed to be
+	// reconciled, and none of the VM's diagnostic checks found the cause.
+	VirtualMachineReconcileFailedReason = "ReconcileFailed"
+)
+
 const (
 	// GuestCustomizationCondition exposes the status of guest customization
 	// from within the guest OS, when available.
@@ -69,6 +301,18 @@ const (
 	GuestCustomizationFailedReason = "GuestCustomizationFailed"
 )
 
+const (
+	// VirtualMachineConditionBootstrapGuestOSFamily indicates whether the
+	// family of the guest OS reported by VMware Tools is the family that the
+	// VM's bootstrap provider customizes, ex. Sysprep customizes Windows
+	// guests.
+	VirtualMachineConditionBootstrapGuestOSFamily = "VirtualMachineBootstrapGuestOSFamily"
+
+	// VirtualMachineGuestOSFamilyMismatchReason documents that the family of
+	// the guest OS does not match the VM's bootstrap provider.
+	VirtualMachineGuestOSFamilyMismatchReason = "GuestOSFamilyMismatch"
+)
+
 const (
 	// VirtualMachineToolsCondition exposes the status of VMware Tools running
 	// in the guest OS, when available.
@@ -83,6 +327,22 @@ const (
 	VirtualMachineToolsRunningReason = "VirtualMachineToolsRunning"
 )
 
+const (
+	// VirtualMachineConditionToolsVersionCurrent indicates that the version
+	// of VMware Tools in the guest OS is not behind the version available on
+	// the VM's host.
+	VirtualMachineConditionToolsVersionCurrent = "VirtualMachineToolsVersionCurrent"
+
+	// VirtualMachineToolsUpgradeAvailableReason documents that a newer
+	// version of VMware Tools is available on the VM's host.
+	VirtualMachineToolsUpgradeAvailableReason = "UpgradeAvailable"
+
+	// VirtualMachineToolsUnsupportedReason documents that the version of
+	// VMware Tools in the guest OS is too old to be supported by the VM's
+	// host.
+	VirtualMachineToolsUnsupportedReason = "Unsupported"
+)
+
 const (
 	// PauseAnnotation is an annotation that prevents a VM from being
 	// reconciled.
@@ -107,6 +367,39 @@ const (
 	// booted at least once. This annotation cannot be set by users and will not
 	// be removed once set until the VM is deleted.
 	FirstBootDoneAnnotation = "virtualmachine." + GroupName + "/first-boot-done"
+
+	// DeletePolicyAnnotation is an annotation that specifies what happens to
+	// the underlying vSphere VM when the VM is deleted. The supported values
+	// are DeletePolicyDelete, the default, and DeletePolicyOrphan.
+	//
+	// When set to DeletePolicyOrphan, deleting the VM removes only the VM
+	// resource, and the vSphere VM is left intact so it can be handed off.
+	DeletePolicyAnnotation = GroupName + "/delete-policy"
+
+	// DeletePolicyDelete is the DeletePolicyAnnotation value that deletes the
+	// vSphere VM when the VM is deleted.
+	DeletePolicyDelete = "delete"
+
+	// DeletePolicyOrphan is the DeletePolicyAnnotation value that leaves the
+	// vSphere VM intact when the VM is deleted.
+	DeletePolicyOrphan = "orphan"
+
+	// DeletionGracePeriodAnnotation is an annotation that specifies how long
+	// the underlying vSphere VM is kept powered off after the VM is deleted
+	// before the vSphere VM is destroyed, ex. 24h. The value is a duration as
+	// parsed by time.ParseDuration.
+	//
+	// The annotation may be set on the VM or on its namespace, and the VM's
+	// annotation takes precedence. When unset, the vSphere VM is destroyed
+	// when the VM is deleted.
+	//
+	// A VM that is created again with the same name before the grace period
+	// expires is reattached to the vSphere VM instead of being redeployed.
+	//
+	// Please note the grace period may not exceed the maximum configured by
+	// the administrator, and the annotation is ignored when no maximum is
+	// configured.
+	DeletionGracePeriodAnnotation = GroupName + "/deletion-grace-period"
 )
 
 // VirtualMachine backup/restore related constants.
@@ -234,6 +527,25 @@ type VirtualMachineSpec struct {
 	// +optional
 	Bootstrap *VirtualMachineBootstrapSpec `json:"bootstrap,omitempty"`
 
+	// VAppProperties is a map of the image's user configurable vApp/OVF
+	// property keys to the values the VM's properties are set to.
+	//
+	// The keys must be properties of the image's OVF descriptor, as described
+	// by the image's status.ovfProperties. The value of a property the OVF
+	// descriptor marks as a password must reference a Secret with the From
+	// field instead of being specified with the Value field.
+	//
+	// The properties are set before the VM is first powered on, and changes
+	// to this field are applied the next time the VM is powered on.
+	//
+	// Please note this field is mutually exclusive with the VAppConfig and
+	// CloudInit bootstrap providers. The CloudInit bootstrap provider removes
+	// the VM's vApp configuration so cloud-init in the guest does not prefer
+	// the OVF datasource.
+	//
+	// +optional
+	VAppProperties map[string]common.ValueOrSecretKeySelector `json:"vAppProperties,omitempty"`
+
 	// Network describes the desired network configuration for the VM.
 	//
 	// Please note this value may be omitted entirely and the VM will be
@@ -424,6 +736,18 @@ type VirtualMachineAdvancedSpec struct {
 	// +optional
 	BootDiskCapacity *resource.Quantity `json:"bootDiskCapacity,omitempty"`
 
+	// BootDiskStorageClass is the name of the StorageClass whose storage
+	// policy the VM's boot disk is placed on, ex. an encrypted policy. When
+	// unset, the boot disk is placed on the storage policy of the VM's
+	// StorageClass like the VM's other disks.
+	//
+	// Please note the StorageClass must be assigned to the VM's namespace.
+	// The boot disk is placed on the policy when the VM is created, and when
+	// the VM is updated if placing the boot disk failed during the create.
+	//
+	// +optional
+	BootDiskStorageClass string `json:"bootDiskStorageClass,omitempty"`
+
 	// DefaultVolumeProvisioningMode specifies the default provisioning mode for
 	// persistent volumes managed by this VM.
 	//
@@ -436,6 +760,476 @@ type VirtualMachineAdvancedSpec struct {
 	//
 	// +optional
 	ChangeBlockTracking bool `json:"changeBlockTracking,omitempty"`
+
+	// CPUAffinity is the set of the host's physical CPUs, by their zero-based
+	// index, that the VM's virtual CPUs are pinned to. When empty, the VM's
+	// virtual CPUs may be scheduled on any of the host's physical CPUs.
+	//
+	// Please note the affinity may only be changed while the VM is powered
+	// off, and pinning the VM's virtual CPUs prevents the VM from being
+	// migrated by DRS.
+	//
+	// +optional
+	// +listType=set
+	CPUAffinity []int32 `json:"cpuAffinity,omitempty"`
+
+	// SwapPlacement is where the VM's swap file is placed. When HostLocal, the
+	// swap file is placed on the swap datastore of the VM's host instead of in
+	// the VM's home directory. When unset, the VM's existing swap placement is
+	// not changed.
+	//
+	// Please note the swap placement may only be changed while the VM is
+	// powered off.
+	//
+	// +optional
+	SwapPlacement VirtualMachineSwapPlacement `json:"swapPlacement,omitempty"`
+
+	// BootOrder is the order of the types of devices the VM tries to boot
+	// from, ex. Network and then Disk. Each of the VM's devices of a type is
+	// tried in the order of the VM's devices. When empty, the VM's existing
+	// boot order is not changed.
+	//
+	// Please note the boot order may only be changed while the VM is powered
+	// off, so the change is deferred until the VM is next powered off.
+	//
+	// +optional
+	// +listType=set
+	BootOrder []VirtualMachineBootDeviceType `json:"bootOrder,omitempty"`
+
+	// HARestartPriority is the priority with which vSphere HA restarts the VM
+	// when its host fails, relative to the other VMs in the cluster. When
+	// unset, the VM's existing restart priority is not changed.
+	//
+	// Please note vSphere HA must be enabled on the VM's cluster, otherwise
+	// the VirtualMachineHAOverrideSynced condition is false.
+	//
+	// +optional
+	HARestartPriority VirtualMachineHARestartPriority `json:"haRestartPriority,omitempty"`
+
+	// HAIsolationResponse is how vSphere HA responds when the VM's host is
+	// isolated from the rest of the cluster's network. When unset, the VM's
+	// existing isolation response is not changed.
+	//
+	// Please note vSphere HA must be enabled on the VM's cluster, otherwise
+	// the VirtualMachineHAOverrideSynced condition is false.
+	//
+	// +optional
+	HAIsolationResponse VirtualMachineHAIsolationResponse `json:"haIsolationResponse,omitempty"`
+
+	// SerialPorts are the VM's serial ports that are backed by a network
+	// connection, ex. to a virtual serial port concentrator (vSPC). The VM's
+	// other serial ports, like those from its image, are not changed.
+	//
+	// Please note a serial port may only be added to or removed from a
+	// powered on VM when the VM's hardware supports hot-plugging serial
+	// ports, otherwise the change is deferred until the VM is next powered
+	// off.
+	//
+	// Please note only privileged users may add a serial port or change its
+	// URIs.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SerialPorts []VirtualMachineSerialPortSpec `json:"serialPorts,omitempty"`
+
+	// ToolsUpgradePolicy is when VMware Tools in the guest is upgraded to
+	// the version available on the VM's host. When unset, the VM's existing
+	// upgrade policy is not changed.
+	//
+	// +optional
+	ToolsUpgradePolicy VirtualMachineToolsUpgradePolicy `json:"toolsUpgradePolicy,omitempty"`
+}
+
+// VirtualMachineToolsUpgradePolicy is the type used to express when VMware
+// Tools in a VM's guest is upgraded.
+//
+// +kubebuilder:validation:Enum=Manual;UpgradeAtPowerCycle
+type VirtualMachineToolsUpgradePolicy string
+
+const (
+	// VirtualMachineToolsUpgradePolicyManual leaves VMware Tools to be
+	// upgraded manually.
+	VirtualMachineToolsUpgradePolicyManual VirtualMachineToolsUpgradePolicy = "Manual"
+
+	// VirtualMachineToolsUpgradePolicyUpgradeAtPowerCycle upgrades VMware
+	// Tools when the VM is power cycled, if a newer version is available on
+	// the VM's host.
+	VirtualMachineToolsUpgradePolicyUpgradeAtPowerCycle VirtualMachineToolsUpgradePolicy = "UpgradeAtPowerCycle"
+)
+
+// VirtualMachineSerialPortDirection is the type used to express whether a
+// VM's network serial port connects to its URI or listens on it.
+//
+// +kubebuilder:validation:Enum=client;server
+type VirtualMachineSerialPortDirection string
+
+const (
+	// VirtualMachineSerialPortDirectionClient has the VM connect to the URI.
+	VirtualMachineSerialPortDirectionClient VirtualMachineSerialPortDirection = "client"
+
+	// VirtualMachineSerialPortDirectionServer has the VM listen on the URI.
+	VirtualMachineSerialPortDirectionServer VirtualMachineSerialPortDirection = "server"
+)
+
+// VirtualMachineSerialPortSpec describes a serial port of the VM that is
+// backed by a network connection.
+type VirtualMachineSerialPortSpec struct {
+	// Name is the name of the serial port, which is unique within the VM.
+	Name string `json:"name"`
+
+	// URI is the network URI of the serial port, ex. telnet://:13370 or
+	// tcp://console.example.com:13370. The scheme must be telnet or tcp.
+	URI string `json:"uri"`
+
+	// Direction is whether the VM connects to the URI or listens on it.
+	// Defaults to client.
+	//
+	// +optional
+	// +kubebuilder:default=client
+	Direction VirtualMachineSerialPortDirection `json:"direction,omitempty"`
+
+	// ProxyURI is the URI of the virtual serial port concentrator (vSPC)
+	// the connection is made through, ex. telnet://vspc.example.com:13370.
+	// The scheme must be telnet or tcp.
+	//
+	// +optional
+	ProxyURI string `json:"proxyURI,omitempty"`
+}
+
+// VirtualMachineSerialPortStatus describes the observed state of one of the
+// VM's network serial ports.
+type VirtualMachineSerialPortStatus struct {
+	// Name is the name of the serial port in the VM's spec.
+	Name string `json:"name"`
+
+	// Key is the vSphere key of the serial port device.
+	Key int32 `json:"key"`
+
+	// Connected describes whether the serial port is currently connected.
+	//
+	// +optional
+	Connected bool `json:"connected,omitempty"`
+}
+
+// VirtualMachineHARestartPriority is the type used to express the priority
+// with which vSphere HA restarts a VM.
+//
+// +kubebuilder:validation:Enum=Disabled;Lowest;Low;Medium;High;Highest
+type VirtualMachineHARestartPriority string
+
+const (
+	// VirtualMachineHARestartPriorityDisabled does not restart the VM when
+	// its host fails.
+	VirtualMachineHARestartPriorityDisabled VirtualMachineHARestartPriority = "Disabled"
+
+	// VirtualMachineHARestartPriorityLowest restarts the VM after the VMs of
+	// every other priority.
+	VirtualMachineHARestartPriorityLowest VirtualMachineHARestartPriority = "Lowest"
+
+	// VirtualMachineHARestartPriorityLow restarts the VM with low priority.
+	VirtualMachineHARestartPriorityLow VirtualMachineHARestartPriority = "Low"
+
+	// VirtualMachineHARestartPriorityMedium restarts the VM with medium
+	// priority.
+	VirtualMachineHARestartPriorityMedium VirtualMachineHARestartPriority = "Medium"
+
+	// VirtualMachineHARestartPriorityHigh restarts the VM with high priority.
+	VirtualMachineHARestartPriorityHigh VirtualMachineHARestartPriority = "High"
+
+	// VirtualMachineHARestartPriorityHighest restarts the VM before the VMs
+	// of every other priority.
+	VirtualMachineHARestartPriorityHighest VirtualMachineHARestartPriority = "Highest"
+)
+
+// VirtualMachineHAIsolationResponse is the type used to express how vSphere
+// HA responds when a VM's host is isolated.
+//
+// +kubebuilder:validation:Enum=None;PowerOff;Shutdown
+type VirtualMachineHAIsolationResponse string
+
+const (
+	// VirtualMachineHAIsolationResponseNone leaves the VM powered on.
+	VirtualMachineHAIsolationResponseNone VirtualMachineHAIsolationResponse = "None"
+
+	// VirtualMachineHAIsolationResponsePowerOff powers off the VM so it can
+	// be restarted on another host.
+	VirtualMachineHAIsolationResponsePowerOff VirtualMachineHAIsolationResponse = "PowerOff"
+
+	// VirtualMachineHAIsolationResponseShutdown shuts down the VM's guest so
+	// the VM can be restarted on another host.
+	VirtualMachineHAIsolationResponseShutdown VirtualMachineHAIsolationResponse = "Shutdown"
+)
+
+// VirtualMachineBootDeviceType is the type used to express a type of device
+// that a VM boots from.
+//
+// +kubebuilder:validation:Enum=Disk;Network;CDROM
+type VirtualMachineBootDeviceType string
+
+const (
+	// VirtualMachineBootDeviceTypeDisk boots the VM from its disks.
+	VirtualMachineBootDeviceTypeDisk VirtualMachineBootDeviceType = "Disk"
+
+	// VirtualMachineBootDeviceTypeNetwork boots the VM from its network
+	// interfaces, ex. with PXE.
+	VirtualMachineBootDeviceTypeNetwork VirtualMachineBootDeviceType = "Network"
+
+	// VirtualMachineBootDeviceTypeCDROM boots the VM from its CD-ROM.
+	VirtualMachineBootDeviceTypeCDROM VirtualMachineBootDeviceType = "CDROM"
+)
+
+// VirtualMachineSwapPlacement is the type used to express where a VM's swap
+// file is placed.
+//
+// +kubebuilder:validation:Enum=Inherit;VMDirectory;HostLocal
+type VirtualMachineSwapPlacement string
+
+const (
+	// VirtualMachineSwapPlacementInherit places the swap file per the policy
+	// of the VM's cluster or host.
+	VirtualMachineSwapPlacementInherit VirtualMachineSwapPlacement = "Inherit"
+
+	// VirtualMachineSwapPlacementVMDirectory places the swap file in the VM's
+	// home directory.
+	VirtualMachineSwapPlacementVMDirectory VirtualMachineSwapPlacement = "VMDirectory"
+
+	// VirtualMachineSwapPlacementHostLocal places the swap file on the swap
+	// datastore of the VM's host.
+	VirtualMachineSwapPlacementHostLocal VirtualMachineSwapPlacement = "HostLocal"
+)
+
+// VirtualMachineTagStatus describes a vSphere tag that is attached to the VM.
+type VirtualMachineTagStatus struct {
+	// Category is the name of the tag's category.
+	Category string `json:"category"`
+
+	// Name is the name of the tag.
+	Name string `json:"name"`
+}
+
+// VirtualMachineGuestDiskStatus describes the usage of one of the guest's
+// filesystems as reported by VMware Tools.
+type VirtualMachineGuestDiskStatus struct {
+	// DiskPath is the path where the filesystem is mounted in the guest, ex.
+	// / or C:\.
+	DiskPath string `json:"diskPath"`
+
+	// FilesystemType is the type of the filesystem, ex. ext4 or NTFS, when
+	// VMware Tools reports it.
+	//
+	// +optional
+	FilesystemType string `json:"filesystemType,omitempty"`
+
+	// Capacity is the total capacity of the filesystem.
+	//
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// FreeSpace is the free space of the filesystem.
+	//
+	// +optional
+	FreeSpace *resource.Quantity `json:"freeSpace,omitempty"`
+}
+
+// VirtualMachineReconfigurePlanDevice describes a virtual device that would
+// be added, removed, or edited by a reconfigure of the VM.
+type VirtualMachineReconfigurePlanDevice struct {
+	// Key is the vSphere key of the device. A new device has the negative key
+	// it would be assigned in the reconfigure.
+	Key int32 `json:"key"`
+
+	// Type is the vSphere type of the device, ex. VirtualVmxnet3.
+	Type string `json:"type"`
+
+	// Label is the device's label.
+	//
+	// +optional
+	Label string `json:"label,omitempty"`
+}
+
+// VirtualMachineReconfigurePlan describes the changes that a reconcile of the
+// VM would make with a reconfigure of the vSphere VM.
+type VirtualMachineReconfigurePlan struct {
+	// AddedDevices describes the devices that would be added to the VM.
+	//
+	// +optional
+	AddedDevices []VirtualMachineReconfigurePlanDevice `json:"addedDevices,omitempty"`
+
+	// RemovedDevices describes the devices that would be removed from the VM.
+	//
+	// +optional
+	RemovedDevices []VirtualMachineReconfigurePlanDevice `json:"removedDevices,omitempty"`
+
+	// EditedDevices describes the devices of the VM that would be edited.
+	//
+	// +optional
+	EditedDevices []VirtualMachineReconfigurePlanDevice `json:"editedDevices,omitempty"`
+
+	// ConfigKeys are the names of the fields of the vSphere
+	// VirtualMachineConfigSpec, other than deviceChange and extraConfig, that
+	// would be changed, ex. numCPUs.
+	//
+	// +optional
+	ConfigKeys []string `json:"configKeys,omitempty"`
+
+	// ExtraConfigKeys are the keys of the VM's extraConfig entries that would
+	// be changed.
+	//
+	// +optional
+	ExtraConfigKeys []string `json:"extraConfigKeys,omitempty"`
+
+	// ConfigSpecs are the JSON encoded vSphere VirtualMachineConfigSpecs that
+	// would be sent with each reconfigure of the VM, in the order that they
+	// would be sent.
+	//
+	// +optional
+	ConfigSpecs []string `json:"configSpecs,omitempty"`
+
+	// HardwareVersion is the hardware version that the VM would be upgraded
+	// to.
+	//
+	// +optional
+	HardwareVersion int32 `json:"hardwareVersion,omitempty"`
+
+	// ClusterConfigSpecs are the JSON encoded vSphere ClusterConfigSpecExs
+	// that the VM's cluster would be reconfigured with, ex. to change the VM's
+	// vSphere HA override.
+	//
+	// +optional
+	ClusterConfigSpecs []string `json:"clusterConfigSpecs,omitempty"`
+
+	// PowerState is the power state that the VM would be changed to.
+	//
+	// +optional
+	PowerState VirtualMachinePowerState `json:"powerState,omitempty"`
+
+	// DisplayName is the name that the vSphere VM would be renamed to.
+	//
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// BootDiskStorageProfileID is the ID of the storage policy that the VM's
+	// boot disk would be placed on.
+	//
+	// +optional
+	BootDiskStorageProfileID string `json:"bootDiskStorageProfileID,omitempty"`
+
+	// AttachedTags are the vSphere tags that would be attached to the VM for
+	// its labels.
+	//
+	// +optional
+	AttachedTags []VirtualMachineTagStatus `json:"attachedTags,omitempty"`
+
+	// DetachedTags are the vSphere tags that would be detached from the VM for
+	// its labels.
+	//
+	// +optional
+	DetachedTags []VirtualMachineTagStatus `json:"detachedTags,omitempty"`
+}
+
+// VirtualMachineDeviceStatus describes the observed connection state of one
+// of the VM's connectable virtual devices, ex. a NIC, disk, or CD-ROM.
+type VirtualMachineDeviceStatus struct {
+	// Key is the vSphere key of the device, which is unique within the VM.
+	Key int32 `json:"key"`
+
+	// Type is the vSphere type of the device, ex. VirtualVmxnet3.
+	Type string `json:"type"`
+
+	// Label is the device's label.
+	//
+	// +optional
+	Label string `json:"label,omitempty"`
+
+	// Connected describes whether the device is currently connected.
+	//
+	// +optional
+	Connected bool `json:"connected,omitempty"`
+
+	// StartConnected describes whether the device is connected when the VM
+	// is powered on.
+	//
+	// +optional
+	StartConnected bool `json:"startConnected,omitempty"`
+
+	// AllowGuestControl describes whether the guest may connect and
+	// disconnect the device.
+	//
+	// +optional
+	AllowGuestControl bool `json:"allowGuestControl,omitempty"`
+}
+
+// VirtualMachineTaskStatus describes the progress of the vSphere task that is
+// currently in-flight for the VM.
+type VirtualMachineTaskStatus struct {
+	// Operation is the VM operation the task is performing, ex. create.
+	Operation string `json:"operation"`
+
+	// Phase is the phase of the task, ex. queued or running.
+	Phase string `json:"phase"`
+
+	// Description identifies what the task is doing, ex. VirtualMachine.clone.
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Progress is the percentage of the task that has completed.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Progress int32 `json:"progress,omitempty"`
+}
+
+// VirtualMachineResourceAllocation describes the reservation, limit, and
+// shares of the VM's CPU, in MHz, or memory, in MiB.
+type VirtualMachineResourceAllocation struct {
+	// Reservation is the amount of the resource that is guaranteed to the VM.
+	//
+	// +optional
+	Reservation int64 `json:"reservation,omitempty"`
+
+	// Limit is the maximum amount of the resource the VM may use. The VM's
+	// use of the resource is unlimited when this field is omitted.
+	//
+	// +optional
+	Limit *int64 `json:"limit,omitempty"`
+
+	// Shares is the relative priority of the VM when competing with its
+	// siblings for the resource.
+	//
+	// +optional
+	Shares int32 `json:"shares,omitempty"`
+}
+
+// VirtualMachineResourceAllocationStatus describes the CPU and memory
+// allocation configured on the VM, and the allocation that is in effect once
+// the constraints of the VM's parent resource pools are applied.
+type VirtualMachineResourceAllocationStatus struct {
+	// ConfiguredCPU is the CPU allocation configured on the VM.
+	//
+	// +optional
+	ConfiguredCPU VirtualMachineResourceAllocation `json:"configuredCPU,omitempty"`
+
+	// ConfiguredMemory is the memory allocation configured on the VM.
+	//
+	// +optional
+	ConfiguredMemory VirtualMachineResourceAllocation `json:"configuredMemory,omitempty"`
+
+	// EffectiveCPU is the CPU allocation in effect for the VM. Its limit is
+	// the upper bound vSphere reports on the CPU usage of the VM's resource
+	// pool when that is less than the configured limit.
+	//
+	// +optional
+	EffectiveCPU VirtualMachineResourceAllocation `json:"effectiveCPU,omitempty"`
+
+	// EffectiveMemory is the memory allocation in effect for the VM. Its limit
+	// is the upper bound vSphere reports on the memory usage of the VM's
+	// resource pool when that is less than the configured limit.
+	//
+	// +optional
+	EffectiveMemory VirtualMachineResourceAllocation `json:"effectiveMemory,omitempty"`
 }
 
 // VirtualMachineStatus defines the observed state of a VirtualMachine instance.
@@ -458,6 +1252,18 @@ type VirtualMachineStatus struct {
 	// +optional
 	Host string `json:"host,omitempty"`
 
+	// HostMoID describes the managed object ID of the ESXi host where the VM
+	// is executed. It is cleared when the VM is not powered on.
+	//
+	// +optional
+	HostMoID string `json:"hostMoID,omitempty"`
+
+	// BootTime describes the last time the VM was powered on. It is kept when
+	// the VM is powered off.
+	//
+	// +optional
+	BootTime *metav1.Time `json:"bootTime,omitempty"`
+
 	// PowerState describes the observed power state of the VirtualMachine.
 	// +optional
 	PowerState VirtualMachinePowerState `json:"powerState,omitempty"`
@@ -516,6 +1322,13 @@ type VirtualMachineStatus struct {
 	// +optional
 	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"`
 
+	// DestroyTime describes when the VM's vSphere VM is destroyed after the
+	// VM was deleted with a deletion grace period. Until then, the vSphere VM
+	// is kept powered off.
+	//
+	// +optional
+	DestroyTime *metav1.Time `json:"destroyTime,omitempty"`
+
 	// HardwareVersion describes the VirtualMachine resource's observed
 	// hardware version.
 	//
@@ -524,6 +1337,86 @@ type VirtualMachineStatus struct {
 	//
 	// +optional
 	HardwareVersion int32 `json:"hardwareVersion,omitempty"`
+
+	// ObservedGeneration describes the generation of the VM's spec that was
+	// last successfully reconciled.
+	//
+	// When this value is less than the VM's metadata.generation, the VM has
+	// not yet been reconciled to match its spec.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ObservedClassGeneration describes the generation of the
+	// VirtualMachineClass that was last applied to the VM.
+	//
+	// When this value is less than the generation of the referenced
+	// VirtualMachineClass, the VM has not yet been reconfigured to match the
+	// VirtualMachineClass.
+	//
+	// A change to a class the referenced VirtualMachineClass inherits from
+	// does not change its generation, so whether such a change has been
+	// applied to the VM is reported by the VM's
+	// VirtualMachineClassConfigurationSynced condition.
+	//
+	// +optional
+	ObservedClassGeneration int64 `json:"observedClassGeneration,omitempty"`
+
+	// Devices describes the observed connection state of the VM's
+	// connectable virtual devices.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=key
+	Devices []VirtualMachineDeviceStatus `json:"devices,omitempty"`
+
+	// Task describes the progress of the vSphere task that is currently
+	// in-flight for the VM.
+	//
+	// Please note the deploy of a content library item is done by the
+	// content library service, which does not provide a vSphere task for
+	// the deploy. The deploy is reported as running, without its progress,
+	// until it has completed.
+	//
+	// +optional
+	Task *VirtualMachineTaskStatus `json:"task,omitempty"`
+
+	// ResourceAllocation describes the CPU and memory allocation configured on
+	// the VM, and the allocation that is in effect for the VM.
+	//
+	// +optional
+	ResourceAllocation *VirtualMachineResourceAllocationStatus `json:"resourceAllocation,omitempty"`
+
+	// Tags describes the vSphere tags that are attached to the VM, including
+	// the tags that were attached outside of VM Operator.
+	//
+	// +optional
+	Tags []VirtualMachineTagStatus `json:"tags,omitempty"`
+
+	// ReconfigurePlan describes the changes that a reconcile of the VM would
+	// make to the vSphere VM. It is only set while the VM has the
+	// vmoperator.vmware.com/dry-run-reconfigure annotation, in which case the
+	// changes are not applied to the VM.
+	//
+	// +optional
+	ReconfigurePlan *VirtualMachineReconfigurePlan `json:"reconfigurePlan,omitempty"`
+
+	// GuestDisks describes the usage of the guest's filesystems. Please note
+	// this information is only available when the guest has VMware Tools
+	// installed and running.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=diskPath
+	GuestDisks []VirtualMachineGuestDiskStatus `json:"guestDisks,omitempty"`
+
+	// SerialPorts describes the VM's network serial ports from its spec that
+	// have been added to the VM.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SerialPorts []VirtualMachineSerialPortStatus `json:"serialPorts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -534,6 +1427,7 @@ type VirtualMachineStatus struct {
 // +kubebuilder:printcolumn:name="Image",type="string",priority=1,JSONPath=".status.image.name"
 // +kubebuilder:printcolumn:name="PowerState",type="string",JSONPath=".status.powerState"
 // +kubebuilder:printcolumn:name="Primary-IP4",type="string",priority=1,JSONPath=".status.network.primaryIP4"
+// +kubebuilder:printcolumn:name="Task-Progress",type="integer",priority=1,JSONPath=".status.task.progress"
 
 // VirtualMachine is the schema for the virtualmachines API and represents the
 // desired state and observed status of a virtualmachines resource.
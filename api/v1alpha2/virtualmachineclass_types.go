@@ -203,6 +203,18 @@ type VirtualMachineClassSpec struct {
 	// +kubebuilder:validation:Type=object
 	// +kubebuilder:pruning:PreserveUnknownFields
 	ConfigSpec json.RawMessage `json:"configSpec,omitempty"`
+
+	// InheritsFrom is the name of a VirtualMachineClass in the same namespace
+	// that this class is based on. The base class's hardware, policies, and
+	// ConfigSpec are merged beneath this class's own, so any value this class
+	// specifies takes precedence over the base class's value. The ExtraConfig
+	// and devices of the ConfigSpecs are merged by key.
+	//
+	// The base class may itself inherit from another class, but a class may
+	// not, directly or indirectly, inherit from itself.
+	//
+	// +optional
+	InheritsFrom string `json:"inheritsFrom,omitempty"`
 }
 
 // VirtualMachineClassStatus defines the observed state of VirtualMachineClass.
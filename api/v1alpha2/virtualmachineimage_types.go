@@ -4,6 +4,7 @@
 package v1alpha2
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
@@ -46,6 +47,39 @@ const (
 	VirtualMachineImageProviderSecurityNotCompliantReason = "VirtualMachineImageProviderSecurityNotCompliant"
 )
 
+// VirtualMachineImageDiskInfo describes a disk of an image.
+type VirtualMachineImageDiskInfo struct {
+	// Capacity is the virtual disk capacity in bytes.
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// Size is the estimated populated size of the virtual disk in bytes.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// VirtualMachineImageRecommendedResources describes the minimum resources an
+// image recommends for the VMs deployed from it.
+type VirtualMachineImageRecommendedResources struct {
+	// MinCPUs describes the minimum number of virtual CPUs.
+	// +optional
+	MinCPUs *int64 `json:"minCPUs,omitempty"`
+
+	// MinCPUReservation describes the minimum CPU frequency to reserve for
+	// the VM, for example 1500M for 1500 MHz.
+	// +optional
+	MinCPUReservation *resource.Quantity `json:"minCPUReservation,omitempty"`
+
+	// MinMemory describes the minimum amount of memory.
+	// +optional
+	MinMemory *resource.Quantity `json:"minMemory,omitempty"`
+
+	// VMClassName describes the name of the VirtualMachineClass recommended
+	// for the image.
+	// +optional
+	VMClassName string `json:"vmClassName,omitempty"`
+}
+
 // VirtualMachineImageProductInfo describes product information for an image.
 type VirtualMachineImageProductInfo struct {
 	// Product is a general descriptor for the image.
@@ -105,6 +139,10 @@ type OVFProperty struct {
 	// Default describes the OVF property's default value.
 	// +optional
 	Default *string `json:"default,omitempty"`
+
+	// Password describes whether the OVF property's value is a password.
+	// +optional
+	Password bool `json:"password,omitempty"`
 }
 
 // VirtualMachineImageSpec defines the desired state of VirtualMachineImage.
@@ -145,10 +183,27 @@ type VirtualMachineImageStatus struct {
 	// +listType=set
 	Capabilities []string `json:"capabilities,omitempty"`
 
+	// Disks describes the observed disks of this image, in the order they are
+	// declared by the image. The first disk is the boot disk.
+	//
+	// +optional
+	Disks []VirtualMachineImageDiskInfo `json:"disks,omitempty"`
+
 	// Firmware describe the firmware type used by this image, ex. BIOS, EFI.
 	// +optional
 	Firmware string `json:"firmware,omitempty"`
 
+	// NetworkInterfaceTypes describes the types of the network interfaces
+	// declared by the image, ex. e1000, vmxnet3.
+	//
+	// If the source of an image is an OVF in Content Library, then the types
+	// are parsed from the ResourceSubType of the OVF's ethernet adapter items.
+	// When empty, the image is assumed to support any network interface type.
+	//
+	// +optional
+	// +listType=set
+	NetworkInterfaceTypes []string `json:"networkInterfaceTypes,omitempty"`
+
 	// HardwareVersion describes the observed hardware version of this image.
 	//
 	// +optional
@@ -180,6 +235,17 @@ type VirtualMachineImageStatus struct {
 	// +optional
 	ProductInfo VirtualMachineImageProductInfo `json:"productInfo,omitempty"`
 
+	// RecommendedResources describes the observed minimum resources recommended
+	// for the VMs deployed from this image.
+	//
+	// If the source of an image is an OVF in Content Library, then the minimums
+	// are parsed from the OVF's ResourceAllocationSection and the virtual
+	// hardware items bound to "min", and the VirtualMachineClass name from the
+	// OVF property vmclass.image.vmoperator.vmware.com.
+	//
+	// +optional
+	RecommendedResources *VirtualMachineImageRecommendedResources `json:"recommendedResources,omitempty"`
+
 	// ProviderContentVersion describes the content version from the provider item
 	// that this image corresponds to. If the provider of this image is a Content
 	// Library, this will be the version of the corresponding Content Library item.
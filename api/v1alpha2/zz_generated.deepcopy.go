@@ -92,6 +92,21 @@ func (in *DynamicDirectPathIODevice) DeepCopy() *DynamicDirectPathIODevice {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirstClassDiskVolumeSource) DeepCopyInto(out *FirstClassDiskVolumeSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirstClassDiskVolumeSource.
+func (in *FirstClassDiskVolumeSource) DeepCopy() *FirstClassDiskVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FirstClassDiskVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GuestHeartbeatAction) DeepCopyInto(out *GuestHeartbeatAction) {
 	*out = *in
@@ -422,6 +437,22 @@ func (in *VirtualMachineAdvancedSpec) DeepCopyInto(out *VirtualMachineAdvancedSp
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.CPUAffinity != nil {
+		in, out := &in.CPUAffinity, &out.CPUAffinity
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = make([]VirtualMachineBootDeviceType, len(*in))
+		copy(*out, *in)
+	}
+
+	if in.SerialPorts != nil {
+		in, out := &in.SerialPorts, &out.SerialPorts
+		*out = make([]VirtualMachineSerialPortSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineAdvancedSpec.
@@ -735,6 +766,46 @@ func (in *VirtualMachineConfigSpec) DeepCopy() *VirtualMachineConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineDeviceStatus) DeepCopyInto(out *VirtualMachineDeviceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineDeviceStatus.
+func (in *VirtualMachineDeviceStatus) DeepCopy() *VirtualMachineDeviceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineDeviceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineGuestDiskStatus) DeepCopyInto(out *VirtualMachineGuestDiskStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.FreeSpace != nil {
+		in, out := &in.FreeSpace, &out.FreeSpace
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineGuestDiskStatus.
+func (in *VirtualMachineGuestDiskStatus) DeepCopy() *VirtualMachineGuestDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineGuestDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImage) DeepCopyInto(out *VirtualMachineImage) {
 	*out = *in
@@ -762,6 +833,31 @@ func (in *VirtualMachineImage) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageDiskInfo) DeepCopyInto(out *VirtualMachineImageDiskInfo) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageDiskInfo.
+func (in *VirtualMachineImageDiskInfo) DeepCopy() *VirtualMachineImageDiskInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageDiskInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImageList) DeepCopyInto(out *VirtualMachineImageList) {
 	*out = *in
@@ -824,6 +920,36 @@ func (in *VirtualMachineImageProductInfo) DeepCopy() *VirtualMachineImageProduct
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageRecommendedResources) DeepCopyInto(out *VirtualMachineImageRecommendedResources) {
+	*out = *in
+	if in.MinCPUs != nil {
+		in, out := &in.MinCPUs, &out.MinCPUs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinCPUReservation != nil {
+		in, out := &in.MinCPUReservation, &out.MinCPUReservation
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MinMemory != nil {
+		in, out := &in.MinMemory, &out.MinMemory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageRecommendedResources.
+func (in *VirtualMachineImageRecommendedResources) DeepCopy() *VirtualMachineImageRecommendedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageRecommendedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImageSpec) DeepCopyInto(out *VirtualMachineImageSpec) {
 	*out = *in
@@ -848,6 +974,18 @@ func (in *VirtualMachineImageStatus) DeepCopyInto(out *VirtualMachineImageStatus
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]VirtualMachineImageDiskInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkInterfaceTypes != nil {
+		in, out := &in.NetworkInterfaceTypes, &out.NetworkInterfaceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.HardwareVersion != nil {
 		in, out := &in.HardwareVersion, &out.HardwareVersion
 		*out = new(int32)
@@ -867,6 +1005,11 @@ func (in *VirtualMachineImageStatus) DeepCopyInto(out *VirtualMachineImageStatus
 		copy(*out, *in)
 	}
 	out.ProductInfo = in.ProductInfo
+	if in.RecommendedResources != nil {
+		in, out := &in.RecommendedResources, &out.RecommendedResources
+		*out = new(VirtualMachineImageRecommendedResources)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -1185,6 +1328,16 @@ func (in *VirtualMachineNetworkSpec) DeepCopyInto(out *VirtualMachineNetworkSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Nameservers != nil {
+		in, out := &in.Nameservers, &out.Nameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineNetworkSpec.
@@ -1443,6 +1596,81 @@ func (in *VirtualMachineReadinessProbeSpec) DeepCopy() *VirtualMachineReadinessP
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineReconfigurePlan) DeepCopyInto(out *VirtualMachineReconfigurePlan) {
+	*out = *in
+	if in.AddedDevices != nil {
+		in, out := &in.AddedDevices, &out.AddedDevices
+		*out = make([]VirtualMachineReconfigurePlanDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedDevices != nil {
+		in, out := &in.RemovedDevices, &out.RemovedDevices
+		*out = make([]VirtualMachineReconfigurePlanDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.EditedDevices != nil {
+		in, out := &in.EditedDevices, &out.EditedDevices
+		*out = make([]VirtualMachineReconfigurePlanDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigKeys != nil {
+		in, out := &in.ConfigKeys, &out.ConfigKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraConfigKeys != nil {
+		in, out := &in.ExtraConfigKeys, &out.ExtraConfigKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigSpecs != nil {
+		in, out := &in.ConfigSpecs, &out.ConfigSpecs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterConfigSpecs != nil {
+		in, out := &in.ClusterConfigSpecs, &out.ClusterConfigSpecs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttachedTags != nil {
+		in, out := &in.AttachedTags, &out.AttachedTags
+		*out = make([]VirtualMachineTagStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DetachedTags != nil {
+		in, out := &in.DetachedTags, &out.DetachedTags
+		*out = make([]VirtualMachineTagStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineReconfigurePlan.
+func (in *VirtualMachineReconfigurePlan) DeepCopy() *VirtualMachineReconfigurePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineReconfigurePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineReconfigurePlanDevice) DeepCopyInto(out *VirtualMachineReconfigurePlanDevice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineReconfigurePlanDevice.
+func (in *VirtualMachineReconfigurePlanDevice) DeepCopy() *VirtualMachineReconfigurePlanDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineReconfigurePlanDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineReservedSpec) DeepCopyInto(out *VirtualMachineReservedSpec) {
 	*out = *in
@@ -1458,6 +1686,45 @@ func (in *VirtualMachineReservedSpec) DeepCopy() *VirtualMachineReservedSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineResourceAllocation) DeepCopyInto(out *VirtualMachineResourceAllocation) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineResourceAllocation.
+func (in *VirtualMachineResourceAllocation) DeepCopy() *VirtualMachineResourceAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineResourceAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineResourceAllocationStatus) DeepCopyInto(out *VirtualMachineResourceAllocationStatus) {
+	*out = *in
+	in.ConfiguredCPU.DeepCopyInto(&out.ConfiguredCPU)
+	in.ConfiguredMemory.DeepCopyInto(&out.ConfiguredMemory)
+	in.EffectiveCPU.DeepCopyInto(&out.EffectiveCPU)
+	in.EffectiveMemory.DeepCopyInto(&out.EffectiveMemory)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineResourceAllocationStatus.
+func (in *VirtualMachineResourceAllocationStatus) DeepCopy() *VirtualMachineResourceAllocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineResourceAllocationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineResourceSpec) DeepCopyInto(out *VirtualMachineResourceSpec) {
 	*out = *in
@@ -1475,6 +1742,36 @@ func (in *VirtualMachineResourceSpec) DeepCopy() *VirtualMachineResourceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineSerialPortSpec) DeepCopyInto(out *VirtualMachineSerialPortSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineSerialPortSpec.
+func (in *VirtualMachineSerialPortSpec) DeepCopy() *VirtualMachineSerialPortSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineSerialPortSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineSerialPortStatus) DeepCopyInto(out *VirtualMachineSerialPortStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineSerialPortStatus.
+func (in *VirtualMachineSerialPortStatus) DeepCopy() *VirtualMachineSerialPortStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineSerialPortStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineService) DeepCopyInto(out *VirtualMachineService) {
 	*out = *in
@@ -1705,6 +2002,13 @@ func (in *VirtualMachineSpec) DeepCopyInto(out *VirtualMachineSpec) {
 		*out = new(VirtualMachineBootstrapSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VAppProperties != nil {
+		in, out := &in.VAppProperties, &out.VAppProperties
+		*out = make(map[string]common.ValueOrSecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.Network != nil {
 		in, out := &in.Network, &out.Network
 		*out = new(VirtualMachineNetworkSpec)
@@ -1762,6 +2066,10 @@ func (in *VirtualMachineStatus) DeepCopyInto(out *VirtualMachineStatus) {
 		*out = new(common.LocalObjectRef)
 		**out = **in
 	}
+	if in.BootTime != nil {
+		in, out := &in.BootTime, &out.BootTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -1777,7 +2085,9 @@ func (in *VirtualMachineStatus) DeepCopyInto(out *VirtualMachineStatus) {
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make([]VirtualMachineVolumeStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ChangeBlockTracking != nil {
 		in, out := &in.ChangeBlockTracking, &out.ChangeBlockTracking
@@ -1788,6 +2098,47 @@ func (in *VirtualMachineStatus) DeepCopyInto(out *VirtualMachineStatus) {
 		in, out := &in.LastRestartTime, &out.LastRestartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.DestroyTime != nil {
+		in, out := &in.DestroyTime, &out.DestroyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]VirtualMachineDeviceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Task != nil {
+		in, out := &in.Task, &out.Task
+		*out = new(VirtualMachineTaskStatus)
+		**out = **in
+	}
+	if in.ResourceAllocation != nil {
+		in, out := &in.ResourceAllocation, &out.ResourceAllocation
+		*out = new(VirtualMachineResourceAllocationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]VirtualMachineTagStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReconfigurePlan != nil {
+		in, out := &in.ReconfigurePlan, &out.ReconfigurePlan
+		*out = new(VirtualMachineReconfigurePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GuestDisks != nil {
+		in, out := &in.GuestDisks, &out.GuestDisks
+		*out = make([]VirtualMachineGuestDiskStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SerialPorts != nil {
+		in, out := &in.SerialPorts, &out.SerialPorts
+		*out = make([]VirtualMachineSerialPortStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineStatus.
@@ -1800,6 +2151,36 @@ func (in *VirtualMachineStatus) DeepCopy() *VirtualMachineStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTagStatus) DeepCopyInto(out *VirtualMachineTagStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTagStatus.
+func (in *VirtualMachineTagStatus) DeepCopy() *VirtualMachineTagStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTagStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTaskStatus) DeepCopyInto(out *VirtualMachineTaskStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTaskStatus.
+func (in *VirtualMachineTaskStatus) DeepCopy() *VirtualMachineTaskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTaskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineTemplate) DeepCopyInto(out *VirtualMachineTemplate) {
 	*out = *in
@@ -1845,6 +2226,11 @@ func (in *VirtualMachineVolumeSource) DeepCopyInto(out *VirtualMachineVolumeSour
 		*out = new(PersistentVolumeClaimVolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FirstClassDisk != nil {
+		in, out := &in.FirstClassDisk, &out.FirstClassDisk
+		*out = new(FirstClassDiskVolumeSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeSource.
@@ -1860,6 +2246,16 @@ func (in *VirtualMachineVolumeSource) DeepCopy() *VirtualMachineVolumeSource {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineVolumeStatus) DeepCopyInto(out *VirtualMachineVolumeStatus) {
 	*out = *in
+	if in.UnitNumber != nil {
+		in, out := &in.UnitNumber, &out.UnitNumber
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeStatus.
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -24,6 +25,7 @@ import (
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
 
+	"github.com/vmware-tanzu/vm-operator/pkg/conditions"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/metrics"
 	"github.com/vmware-tanzu/vm-operator/pkg/record"
@@ -32,6 +34,10 @@ import (
 
 const (
 	finalizerName = "contentsource.vmoperator.vmware.com"
+
+	// DefaultSyncStaleThreshold is how long a subscribed content library may go without syncing
+	// with its publisher before the ContentSource's ContentLibrarySynced condition is marked false.
+	DefaultSyncStaleThreshold = 24 * time.Hour
 )
 
 // AddToManager adds this package's controller to the provided manager.
@@ -69,6 +75,8 @@ func NewReconciler(
 		Recorder:   recorder,
 		VMProvider: vmProvider,
 		CSMetrics:  metrics.NewContentSourceMetrics(),
+
+		SyncStaleThreshold: DefaultSyncStaleThreshold,
 	}
 }
 
@@ -79,6 +87,9 @@ type Reconciler struct {
 	Recorder   record.Recorder
 	VMProvider vmprovider.VirtualMachineProviderInterface
 	CSMetrics  *metrics.ContentSourceMetrics
+
+	// SyncStaleThreshold is how long a subscribed content library may go without syncing before it is stale.
+	SyncStaleThreshold time.Duration
 }
 
 // CreateImage creates thr VirtualMachineImage. If a VirtualMachineImage with the same name alreay exists,
@@ -248,9 +259,15 @@ func (r *Reconciler) SyncImagesFromContentProvider(
 	logger := r.Logger.WithValues("clProviderName", clProvider.Name, "clProviderUUID", clProvider.Spec.UUID)
 	logger.V(4).Info("listing images from content library")
 
+	startTime := time.Now()
+	defer func() {
+		r.CSMetrics.RegisterContentLibrarySync(logger, *clProvider, time.Since(startTime))
+	}()
+
 	// List the existing images from the supervisor cluster.
 	k8sManagedImageList := &vmopv1.VirtualMachineImageList{}
 	if err := r.List(ctx, k8sManagedImageList); err != nil {
+		r.CSMetrics.RegisterContentLibraryDiscoveryError(logger, *clProvider, metrics.ContentLibraryDiscoveryErrorListImages)
 		return errors.Wrap(err, "failed to list VirtualMachineImages from control plane")
 	}
 
@@ -274,13 +291,17 @@ func (r *Reconciler) SyncImagesFromContentProvider(
 
 	libItemList, err := r.VMProvider.ListItemsFromContentLibrary(ctx, clProvider)
 	if err != nil {
+		r.CSMetrics.RegisterContentLibraryDiscoveryError(logger, *clProvider, metrics.ContentLibraryDiscoveryErrorListItems)
 		return err
 	}
 
+	r.CSMetrics.RegisterContentLibraryImagesDiscovered(logger, *clProvider, len(libItemList))
+
 	retErrs := make([]error, 0)
 	for _, item := range libItemList {
 		err := r.ProcessItemFromContentLibrary(ctx, logger, clProvider, item, currentCLImages)
 		if err != nil {
+			r.CSMetrics.RegisterContentLibraryDiscoveryError(logger, *clProvider, metrics.ContentLibraryDiscoveryErrorProcessItem)
 			retErrs = append(retErrs, err)
 			continue
 		}
@@ -298,6 +319,7 @@ func (r *Reconciler) SyncImagesFromContentProvider(
 	for _, currentImage := range currentCLImages {
 		err := r.DeleteImage(ctx, currentImage)
 		if err != nil {
+			r.CSMetrics.RegisterContentLibraryDiscoveryError(logger, *clProvider, metrics.ContentLibraryDiscoveryErrorDeleteImage)
 			retErrs = append(retErrs, err)
 		}
 		r.CSMetrics.RegisterVMImageDelete(logger, currentImage, err == nil)
@@ -358,6 +380,72 @@ func (r *Reconciler) ReconcileProviderRef(ctx goctx.Context,
 	return contentLibrary, nil
 }
 
+// ReconcileSyncRequest triggers a sync of the content library backing a ContentSource when the sync
+// annotation is present, and then removes the annotation so the sync only happens once.
+func (r *Reconciler) ReconcileSyncRequest(ctx goctx.Context,
+	contentSource *vmopv1.ContentSource, clProvider *vmopv1.ContentLibraryProvider) error {
+	if _, ok := contentSource.Annotations[vmopv1.SyncContentLibraryAnnotation]; !ok {
+		return nil
+	}
+
+	logger := r.Logger.WithValues("contentSourceName", contentSource.Name, "contentLibraryUUID", clProvider.Spec.UUID)
+	logger.Info("Syncing content library as requested by annotation")
+
+	err := r.VMProvider.SyncContentLibrary(ctx, clProvider.Spec.UUID)
+	r.Recorder.EmitEvent(contentSource, "SyncContentLibrary", err, false)
+	if err != nil {
+		logger.Error(err, "failed to sync content library")
+		return err
+	}
+
+	delete(contentSource.Annotations, vmopv1.SyncContentLibraryAnnotation)
+	if err := r.Update(ctx, contentSource); err != nil {
+		logger.Error(err, "failed to remove the sync annotation from the ContentSource")
+		return err
+	}
+
+	return nil
+}
+
+// ReconcileSyncStaleness reconciles the ContentLibrarySynced condition of a ContentSource from when its
+// subscribed content library last synced. The condition is removed if the library has never synced, as
+// is the case for a local library.
+func (r *Reconciler) ReconcileSyncStaleness(ctx goctx.Context,
+	contentSource *vmopv1.ContentSource, clProvider *vmopv1.ContentLibraryProvider) error {
+	logger := r.Logger.WithValues("contentSourceName", contentSource.Name, "contentLibraryUUID", clProvider.Spec.UUID)
+
+	lastSyncTime, err := r.VMProvider.GetContentLibraryLastSyncTime(ctx, clProvider.Spec.UUID)
+	if err != nil {
+		logger.Error(err, "failed to get the last sync time of the content library")
+		return err
+	}
+
+	beforeObj := contentSource.DeepCopy()
+
+	switch {
+	case lastSyncTime == nil:
+		conditions.Delete(contentSource, vmopv1.ContentLibrarySyncedCondition)
+	case time.Since(*lastSyncTime) > r.SyncStaleThreshold:
+		logger.Info("Content library sync is stale", "lastSyncTime", *lastSyncTime)
+		conditions.MarkFalse(contentSource, vmopv1.ContentLibrarySyncedCondition,
+			vmopv1.ContentLibrarySyncStaleReason, vmopv1.ConditionSeverityWarning,
+			"Content library last synced at %s", lastSyncTime.UTC().Format(time.RFC3339))
+		r.CSMetrics.RegisterContentLibrarySyncStale(logger, contentSource.Spec.ProviderRef, true)
+	default:
+		conditions.MarkTrue(contentSource, vmopv1.ContentLibrarySyncedCondition)
+		r.CSMetrics.RegisterContentLibrarySyncStale(logger, contentSource.Spec.ProviderRef, false)
+	}
+
+	if !equality.Semantic.DeepEqual(beforeObj.Status, contentSource.Status) {
+		if err := r.Status().Update(ctx, contentSource); err != nil {
+			logger.Error(err, "failed to update status sub resource for ContentSource")
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ReconcileDeleteProviderRef reconciles a delete for a provider reference. Currently, no op.
 func (r *Reconciler) ReconcileDeleteProviderRef(ctx goctx.Context, contentSource *vmopv1.ContentSource) error {
 	logger := r.Logger.WithValues("contentSourceName", contentSource.Name)
@@ -395,6 +483,11 @@ func (r *Reconciler) ReconcileNormal(ctx goctx.Context, contentSource *vmopv1.Co
 		return err
 	}
 
+	if err := r.ReconcileSyncRequest(ctx, contentSource, clProvider); err != nil {
+		logger.Error(err, "Error in reconciling the content library sync request")
+		return err
+	}
+
 	// Currently, the only supported content provider is content library, so we assume that the providerRef
 	// is of ContentLibraryProvider kind.
 	if err := r.SyncImagesFromContentProvider(ctx, clProvider); err != nil {
@@ -403,6 +496,11 @@ func (r *Reconciler) ReconcileNormal(ctx goctx.Context, contentSource *vmopv1.Co
 		return err
 	}
 
+	if err := r.ReconcileSyncStaleness(ctx, contentSource, clProvider); err != nil {
+		logger.Error(err, "Error in reconciling the content library sync staleness")
+		return err
+	}
+
 	logger.Info("Finished reconciling ContentSource")
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -15,6 +16,7 @@ import (
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
 	"github.com/vmware-tanzu/vm-operator/controllers/contentlibrary/v1alpha1/contentsource"
+	"github.com/vmware-tanzu/vm-operator/pkg/conditions"
 	providerfake "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/fake"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -98,6 +100,114 @@ func reconcileProviderRef() {
 			})
 		})
 	})
+
+	Context("ReconcileSyncRequest", func() {
+		var (
+			syncedCLUUID string
+		)
+
+		BeforeEach(func() {
+			syncedCLUUID = ""
+		})
+
+		JustBeforeEach(func() {
+			fakeVMProvider.SyncContentLibraryFn = func(_ context.Context, clUUID string) error {
+				syncedCLUUID = clUUID
+				return nil
+			}
+		})
+
+		When("the ContentSource does not have the sync annotation", func() {
+			BeforeEach(func() {
+				initObjects = []client.Object{&cs, &cl}
+			})
+
+			It("does not sync the content library", func() {
+				Expect(reconciler.ReconcileSyncRequest(ctx, &cs, &cl)).To(Succeed())
+				Expect(syncedCLUUID).To(BeEmpty())
+			})
+		})
+
+		When("the ContentSource has the sync annotation", func() {
+			BeforeEach(func() {
+				cs.Annotations = map[string]string{
+					vmopv1.SyncContentLibraryAnnotation: "",
+				}
+				initObjects = []client.Object{&cs, &cl}
+			})
+
+			It("syncs the content library once and removes the annotation", func() {
+				Expect(reconciler.ReconcileSyncRequest(ctx, &cs, &cl)).To(Succeed())
+				Expect(syncedCLUUID).To(Equal(cl.Spec.UUID))
+
+				csAfterReconcile := &vmopv1.ContentSource{}
+				Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: cs.Name}, csAfterReconcile)).To(Succeed())
+				Expect(csAfterReconcile.Annotations).ToNot(HaveKey(vmopv1.SyncContentLibraryAnnotation))
+
+				syncedCLUUID = ""
+				Expect(reconciler.ReconcileSyncRequest(ctx, csAfterReconcile, &cl)).To(Succeed())
+				Expect(syncedCLUUID).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("ReconcileSyncStaleness", func() {
+		var (
+			lastSyncTime *time.Time
+		)
+
+		BeforeEach(func() {
+			initObjects = []client.Object{&cs, &cl}
+			lastSyncTime = nil
+		})
+
+		JustBeforeEach(func() {
+			fakeVMProvider.GetContentLibraryLastSyncTimeFn = func(_ context.Context, clUUID string) (*time.Time, error) {
+				Expect(clUUID).To(Equal(cl.Spec.UUID))
+				return lastSyncTime, nil
+			}
+		})
+
+		getContentSource := func() *vmopv1.ContentSource {
+			csAfterReconcile := &vmopv1.ContentSource{}
+			Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: cs.Name}, csAfterReconcile)).To(Succeed())
+			return csAfterReconcile
+		}
+
+		When("the content library has never synced", func() {
+			It("does not set the condition", func() {
+				Expect(reconciler.ReconcileSyncStaleness(ctx, &cs, &cl)).To(Succeed())
+				Expect(conditions.Has(getContentSource(), vmopv1.ContentLibrarySyncedCondition)).To(BeFalse())
+			})
+		})
+
+		When("the content library synced recently", func() {
+			BeforeEach(func() {
+				t := time.Now().Add(-time.Hour)
+				lastSyncTime = &t
+			})
+
+			It("marks the condition true", func() {
+				Expect(reconciler.ReconcileSyncStaleness(ctx, &cs, &cl)).To(Succeed())
+				Expect(conditions.IsTrue(getContentSource(), vmopv1.ContentLibrarySyncedCondition)).To(BeTrue())
+			})
+		})
+
+		When("the content library last synced before the staleness threshold", func() {
+			BeforeEach(func() {
+				t := time.Now().Add(-2 * contentsource.DefaultSyncStaleThreshold)
+				lastSyncTime = &t
+			})
+
+			It("marks the condition false with the stale reason", func() {
+				Expect(reconciler.ReconcileSyncStaleness(ctx, &cs, &cl)).To(Succeed())
+
+				csAfterReconcile := getContentSource()
+				Expect(conditions.IsFalse(csAfterReconcile, vmopv1.ContentLibrarySyncedCondition)).To(BeTrue())
+				Expect(conditions.GetReason(csAfterReconcile, vmopv1.ContentLibrarySyncedCondition)).To(Equal(vmopv1.ContentLibrarySyncStaleReason))
+			})
+		})
+	})
 }
 
 func unitTestIsImageOwnedByContentLibrary() {
@@ -444,6 +554,59 @@ func unitTestsCRUDImage() {
 				})
 			})
 		})
+
+		Context("content library discovery metrics", func() {
+			BeforeEach(func() {
+				cl.Name = "dummy-metrics-cl"
+				cl.Spec.UUID = "dummy-metrics-cl-uuid"
+				initObjects = append(initObjects, &cl)
+			})
+
+			JustBeforeEach(func() {
+				reconciler.CSMetrics.DeleteMetrics(ctx.Logger, vmopv1.ContentProviderReference{Name: cl.Name})
+			})
+
+			It("records the sync duration and the images discovered", func() {
+				fakeVMProvider.Lock()
+				fakeVMProvider.ListItemsFromContentLibraryFn = func(_ context.Context, _ *vmopv1.ContentLibraryProvider) ([]string, error) {
+					return []string{"item-1", "item-2"}, nil
+				}
+				fakeVMProvider.GetVirtualMachineImageFromContentLibraryFn = func(_ context.Context, _ *vmopv1.ContentLibraryProvider, itemID string,
+					_ map[string]vmopv1.VirtualMachineImage) (*vmopv1.VirtualMachineImage, error) {
+					return &vmopv1.VirtualMachineImage{
+						ObjectMeta: metav1.ObjectMeta{Name: itemID},
+						Spec:       vmopv1.VirtualMachineImageSpec{ImageID: itemID},
+						Status:     vmopv1.VirtualMachineImageStatus{ImageName: itemID},
+					}, nil
+				}
+				fakeVMProvider.Unlock()
+
+				Expect(reconciler.SyncImagesFromContentProvider(ctx.Context, &cl)).To(Succeed())
+
+				labels := map[string]string{"provider_name": cl.Name, "library_id": cl.Spec.UUID}
+				Expect(builder.GatherMetric("vmservice_contentlibrary_sync_duration_seconds", labels)).To(HaveValue(BeEquivalentTo(1)))
+				Expect(builder.GatherMetric("vmservice_contentlibrary_images_discovered", labels)).To(HaveValue(BeEquivalentTo(2)))
+				Expect(builder.GatherMetric("vmservice_contentlibrary_discovery_errors_total", labels)).To(BeNil())
+			})
+
+			It("counts the discovery errors by error class", func() {
+				fakeVMProvider.Lock()
+				fakeVMProvider.ListItemsFromContentLibraryFn = func(_ context.Context, _ *vmopv1.ContentLibraryProvider) ([]string, error) {
+					return nil, fmt.Errorf("list items error")
+				}
+				fakeVMProvider.Unlock()
+
+				Expect(reconciler.SyncImagesFromContentProvider(ctx.Context, &cl)).ToNot(Succeed())
+				Expect(reconciler.SyncImagesFromContentProvider(ctx.Context, &cl)).ToNot(Succeed())
+
+				labels := map[string]string{"provider_name": cl.Name, "library_id": cl.Spec.UUID}
+				Expect(builder.GatherMetric("vmservice_contentlibrary_sync_duration_seconds", labels)).To(HaveValue(BeEquivalentTo(2)))
+				Expect(builder.GatherMetric("vmservice_contentlibrary_images_discovered", labels)).To(BeNil())
+
+				labels["error_class"] = "list_items"
+				Expect(builder.GatherMetric("vmservice_contentlibrary_discovery_errors_total", labels)).To(HaveValue(BeEquivalentTo(2)))
+			})
+		})
 	})
 
 	Context("DeleteImage", func() {
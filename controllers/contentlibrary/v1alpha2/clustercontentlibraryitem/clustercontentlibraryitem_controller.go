@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -122,6 +123,9 @@ func (r *Reconciler) Reconcile(ctx goctx.Context, req ctrl.Request) (_ ctrl.Resu
 func (r *Reconciler) ReconcileDelete(ctx *context.ClusterContentLibraryItemContextA2) error {
 	if controllerutil.ContainsFinalizer(ctx.CCLItem, utils.ClusterContentLibraryItemVmopFinalizer) {
 		r.Metrics.DeleteMetrics(ctx.Logger, ctx.ImageObjName, "")
+		if ref := ctx.CCLItem.Status.ContentLibraryRef; ref != nil {
+			r.Metrics.DeleteContentLibraryItemMetrics(ctx.Logger, ref.Name, "", ref.Kind, ctx.CCLItem.Name)
+		}
 		controllerutil.RemoveFinalizer(ctx.CCLItem, utils.ClusterContentLibraryItemVmopFinalizer)
 		return r.Update(ctx, ctx.CCLItem)
 	}
@@ -139,6 +143,8 @@ func (r *Reconciler) ReconcileNormal(ctx *context.ClusterContentLibraryItemConte
 		return r.Update(ctx, ctx.CCLItem)
 	}
 
+	start := time.Now()
+
 	// Do not set additional fields here as they will be overwritten in CreateOrPatch below.
 	cvmi := &vmopv1.ClusterVirtualMachineImage{
 		ObjectMeta: metav1.ObjectMeta{
@@ -202,6 +208,7 @@ func (r *Reconciler) ReconcileNormal(ctx *context.ClusterContentLibraryItemConte
 	defer func() {
 		r.Metrics.RegisterVMIResourceResolve(ctx.Logger, cvmi.Name, "", createOrPatchErr == nil)
 		r.Metrics.RegisterVMIContentSync(ctx.Logger, cvmi.Name, "", didSync && syncErr == nil)
+		r.registerLibraryItemSyncMetrics(ctx, time.Since(start), createOrPatchErr, syncErr, didSync)
 	}()
 
 	if createOrPatchErr != nil {
@@ -228,6 +235,31 @@ func (r *Reconciler) ReconcileNormal(ctx *context.ClusterContentLibraryItemConte
 	return nil
 }
 
+// registerLibraryItemSyncMetrics registers the metrics of the item's content library for the sync
+// of the item to its image.
+func (r *Reconciler) registerLibraryItemSyncMetrics(
+	ctx *context.ClusterContentLibraryItemContextA2,
+	duration time.Duration,
+	createOrPatchErr, syncErr error,
+	didSync bool) {
+
+	ref := ctx.CCLItem.Status.ContentLibraryRef
+	if ref == nil {
+		return
+	}
+
+	var errorClass metrics.ContentLibraryItemSyncErrorClass
+	switch {
+	case createOrPatchErr != nil:
+		errorClass = metrics.ContentLibraryItemSyncErrorResolveImage
+	case syncErr != nil:
+		errorClass = metrics.ContentLibraryItemSyncErrorSyncContent
+	}
+
+	r.Metrics.RegisterContentLibraryItemSync(ctx.Logger, ref.Name, "", ref.Kind, ctx.CCLItem.Name,
+		duration, errorClass, didSync && errorClass == "")
+}
+
 // setUpCVMIFromCCLItem sets up the ClusterVirtualMachineImage fields that
 // are retrievable from the given ClusterContentLibraryItem resource.
 func (r *Reconciler) setUpCVMIFromCCLItem(ctx *context.ClusterContentLibraryItemContextA2) error {
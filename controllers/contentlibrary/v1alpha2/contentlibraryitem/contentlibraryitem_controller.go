@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -122,6 +123,10 @@ func (r *Reconciler) Reconcile(ctx goctx.Context, req ctrl.Request) (_ ctrl.Resu
 func (r *Reconciler) ReconcileDelete(ctx *context.ContentLibraryItemContextA2) error {
 	if controllerutil.ContainsFinalizer(ctx.CLItem, utils.ContentLibraryItemVmopFinalizer) {
 		r.Metrics.DeleteMetrics(ctx.Logger, ctx.ImageObjName, ctx.CLItem.Namespace)
+		if ref := ctx.CLItem.Status.ContentLibraryRef; ref != nil {
+			r.Metrics.DeleteContentLibraryItemMetrics(ctx.Logger, ref.Name, ctx.CLItem.Namespace, ref.Kind,
+				client.ObjectKeyFromObject(ctx.CLItem).String())
+		}
 		controllerutil.RemoveFinalizer(ctx.CLItem, utils.ContentLibraryItemVmopFinalizer)
 		return r.Update(ctx, ctx.CLItem)
 	}
@@ -139,6 +144,8 @@ func (r *Reconciler) ReconcileNormal(ctx *context.ContentLibraryItemContextA2) e
 		return r.Update(ctx, ctx.CLItem)
 	}
 
+	start := time.Now()
+
 	// Do not set additional fields here as they will be overwritten in CreateOrPatch below.
 	vmi := &vmopv1.VirtualMachineImage{
 		ObjectMeta: metav1.ObjectMeta{
@@ -203,6 +210,7 @@ func (r *Reconciler) ReconcileNormal(ctx *context.ContentLibraryItemContextA2) e
 	defer func() {
 		r.Metrics.RegisterVMIResourceResolve(ctx.Logger, vmi.Name, vmi.Namespace, createOrPatchErr == nil)
 		r.Metrics.RegisterVMIContentSync(ctx.Logger, vmi.Name, vmi.Namespace, didSync && syncErr == nil)
+		r.registerLibraryItemSyncMetrics(ctx, time.Since(start), createOrPatchErr, syncErr, didSync)
 	}()
 
 	if createOrPatchErr != nil {
@@ -228,6 +236,31 @@ func (r *Reconciler) ReconcileNormal(ctx *context.ContentLibraryItemContextA2) e
 	return nil
 }
 
+// registerLibraryItemSyncMetrics registers the metrics of the item's content library for the sync
+// of the item to its image.
+func (r *Reconciler) registerLibraryItemSyncMetrics(
+	ctx *context.ContentLibraryItemContextA2,
+	duration time.Duration,
+	createOrPatchErr, syncErr error,
+	didSync bool) {
+
+	ref := ctx.CLItem.Status.ContentLibraryRef
+	if ref == nil {
+		return
+	}
+
+	var errorClass metrics.ContentLibraryItemSyncErrorClass
+	switch {
+	case createOrPatchErr != nil:
+		errorClass = metrics.ContentLibraryItemSyncErrorResolveImage
+	case syncErr != nil:
+		errorClass = metrics.ContentLibraryItemSyncErrorSyncContent
+	}
+
+	r.Metrics.RegisterContentLibraryItemSync(ctx.Logger, ref.Name, ctx.CLItem.Namespace, ref.Kind,
+		client.ObjectKeyFromObject(ctx.CLItem).String(), duration, errorClass, didSync && errorClass == "")
+}
+
 // setUpVMIFromCLItem sets up the VirtualMachineImage fields that
 // are retrievable from the given ContentLibraryItem resource.
 func (r *Reconciler) setUpVMIFromCLItem(ctx *context.ContentLibraryItemContextA2) error {
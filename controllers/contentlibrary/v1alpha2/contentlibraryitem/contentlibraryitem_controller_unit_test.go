@@ -7,6 +7,7 @@ import (
 	goctx "context"
 	"fmt"
 
+	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -253,6 +254,57 @@ func unitTestsReconcile() {
 		})
 	})
 
+	Context("Content library metrics", func() {
+		var libraryLabels map[string]string
+
+		BeforeEach(func() {
+			// The metrics are global so each test uses its own library.
+			libraryName := "metrics-cl-" + uuid.NewString()
+			clItem.Status.ContentLibraryRef = &imgregv1a1.NameAndKindRef{
+				Kind: "ContentLibrary",
+				Name: libraryName,
+			}
+			libraryLabels = map[string]string{
+				"library_name":      libraryName,
+				"library_namespace": clItem.Namespace,
+				"library_kind":      "ContentLibrary",
+			}
+		})
+
+		It("should count the synced images of the library", func() {
+			Expect(reconciler.ReconcileNormal(clItemCtx)).To(Succeed())
+
+			Expect(builder.GatherMetric("vmservice_contentlibrary_item_sync_duration_seconds", libraryLabels)).To(HaveValue(BeEquivalentTo(1)))
+			Expect(builder.GatherMetric("vmservice_contentlibrary_images", libraryLabels)).To(HaveValue(BeEquivalentTo(1)))
+			Expect(builder.GatherMetric("vmservice_contentlibrary_item_sync_errors_total", libraryLabels)).To(BeNil())
+
+			By("Deleting the item", func() {
+				Expect(reconciler.ReconcileDelete(clItemCtx)).To(Succeed())
+				Expect(builder.GatherMetric("vmservice_contentlibrary_images", libraryLabels)).To(HaveValue(BeEquivalentTo(0)))
+			})
+		})
+
+		When("SyncVirtualMachineImage returns an error", func() {
+
+			BeforeEach(func() {
+				fakeVMProvider.SyncVirtualMachineImageFn = func(_ goctx.Context, _, _ client.Object) error {
+					return fmt.Errorf("sync-error")
+				}
+			})
+
+			It("should count the sync error", func() {
+				Expect(reconciler.ReconcileNormal(clItemCtx)).To(MatchError("sync-error"))
+
+				errLabels := map[string]string{"error_class": "sync_content"}
+				for k, v := range libraryLabels {
+					errLabels[k] = v
+				}
+				Expect(builder.GatherMetric("vmservice_contentlibrary_item_sync_errors_total", errLabels)).To(HaveValue(BeEquivalentTo(1)))
+				Expect(builder.GatherMetric("vmservice_contentlibrary_images", libraryLabels)).To(HaveValue(BeEquivalentTo(0)))
+			})
+		})
+	})
+
 	Context("ReconcileDelete", func() {
 
 		It("should remove the finalizer from ContentLibraryItem resource", func() {
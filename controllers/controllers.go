@@ -11,7 +11,9 @@ import (
 	"github.com/vmware-tanzu/vm-operator/controllers/contentlibrary"
 	"github.com/vmware-tanzu/vm-operator/controllers/infracluster"
 	"github.com/vmware-tanzu/vm-operator/controllers/infraprovider"
+	"github.com/vmware-tanzu/vm-operator/controllers/orphanedvirtualmachine"
 	"github.com/vmware-tanzu/vm-operator/controllers/providerconfigmap"
+	"github.com/vmware-tanzu/vm-operator/controllers/retainednetworkinterface"
 	"github.com/vmware-tanzu/vm-operator/controllers/virtualmachine"
 	"github.com/vmware-tanzu/vm-operator/controllers/virtualmachineclass"
 	"github.com/vmware-tanzu/vm-operator/controllers/virtualmachinepublishrequest"
@@ -37,6 +39,12 @@ func AddToManager(ctx *context.ControllerManagerContext, mgr manager.Manager) er
 	if err := virtualmachine.AddToManager(ctx, mgr); err != nil {
 		return errors.Wrap(err, "failed to initialize VirtualMachine controller")
 	}
+	if err := orphanedvirtualmachine.AddToManager(ctx, mgr); err != nil {
+		return errors.Wrap(err, "failed to initialize orphaned VirtualMachine collector")
+	}
+	if err := retainednetworkinterface.AddToManager(ctx, mgr); err != nil {
+		return errors.Wrap(err, "failed to initialize retained network interface collector")
+	}
 	if err := virtualmachineclass.AddToManager(ctx, mgr); err != nil {
 		return errors.Wrap(err, "failed to initialize VirtualMachineClass controller")
 	}
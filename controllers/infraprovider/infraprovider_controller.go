@@ -8,6 +8,7 @@ import (
 	"reflect"
 
 	"github.com/go-logr/logr"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
 
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -24,6 +25,11 @@ type infraProvider interface {
 	ComputeCPUMinFrequency(ctx goctx.Context) error
 }
 
+// privilegeChecker is implemented by the providers that can check the vCenter user's privileges.
+type privilegeChecker interface {
+	GetMissingPrivileges(ctx goctx.Context) (map[vimtypes.ManagedObjectReference][]string, error)
+}
+
 // AddToManager adds this package's controller to the provided manager.
 func AddToManager(ctx *context.ControllerManagerContext, mgr manager.Manager) error {
 	var (
@@ -94,5 +100,27 @@ func (r *Reconciler) Reconcile(ctx goctx.Context, req ctrl.Request) (ctrl.Result
 		return ctrl.Result{}, err
 	}
 
+	r.checkPrivileges(ctx)
+
 	return ctrl.Result{}, nil
 }
+
+// checkPrivileges reports the privileges the vCenter user is missing on the configured objects, so
+// a misconfigured role shows up when the controller starts instead of as a confusing failure of a
+// later VM operation. A failed check does not fail the reconcile.
+func (r *Reconciler) checkPrivileges(ctx goctx.Context) {
+	checker, ok := r.provider.(privilegeChecker)
+	if !ok {
+		return
+	}
+
+	missing, err := checker.GetMissingPrivileges(ctx)
+	if err != nil {
+		r.Logger.Error(err, "Failed to check the vCenter user's privileges")
+		return
+	}
+
+	for entity, privileges := range missing {
+		r.Logger.Info("vCenter user is missing required privileges", "entity", entity, "privileges", privileges)
+	}
+}
@@ -27,7 +27,7 @@ var suite = builder.NewTestSuiteForController(
 )
 
 func TestInfraProvider(t *testing.T) {
-	suite.Register(t, "InfraProvider controller suite", intgTests, nil)
+	suite.Register(t, "InfraProvider controller suite", intgTests, unitTests)
 }
 
 var _ = BeforeSuite(suite.BeforeSuite)
@@ -0,0 +1,87 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package infraprovider_test
+
+import (
+	goctx "context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/vim25/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/vm-operator/controllers/infraprovider"
+	providerfake "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/fake"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func unitTests() {
+	Describe("Invoking Reconcile", unitTestsReconcile)
+}
+
+func unitTestsReconcile() {
+	var (
+		ctx *builder.UnitTestContextForController
+
+		reconciler     *infraprovider.Reconciler
+		fakeVMProvider *providerfake.VMProviderA2
+
+		checkCalled bool
+		checkErr    error
+	)
+
+	BeforeEach(func() {
+		fakeVMProvider = providerfake.NewVMProviderA2()
+		fakeVMProvider.GetMissingPrivilegesFn = func(_ goctx.Context) (map[types.ManagedObjectReference][]string, error) {
+			checkCalled = true
+			if checkErr != nil {
+				return nil, checkErr
+			}
+			return map[types.ManagedObjectReference][]string{
+				{Type: "ResourcePool", Value: "resgroup-1"}: {"Resource.AssignVMToPool"},
+			}, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewUnitTestContextForController()
+		reconciler = infraprovider.NewReconciler(
+			ctx.Client,
+			ctx.Logger,
+			fakeVMProvider,
+		)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		reconciler = nil
+		checkCalled = false
+		checkErr = nil
+	})
+
+	reconcile := func() error {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "dummy-node"}})
+		return err
+	}
+
+	It("checks the vCenter user's privileges", func() {
+		Expect(reconcile()).To(Succeed())
+		Expect(checkCalled).To(BeTrue())
+	})
+
+	When("the privileges cannot be checked", func() {
+		BeforeEach(func() {
+			checkErr = errors.New("check failed")
+		})
+
+		It("does not fail the reconcile", func() {
+			Expect(reconcile()).To(Succeed())
+			Expect(checkCalled).To(BeTrue())
+		})
+	})
+}
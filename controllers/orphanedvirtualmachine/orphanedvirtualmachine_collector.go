@@ -0,0 +1,176 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package orphanedvirtualmachine
+
+import (
+	goctx "context"
+	"time"
+
+	"github.com/go-logr/logr"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	metrics "github.com/vmware-tanzu/vm-operator/pkg/metrics2"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+)
+
+const collectorName = "orphaned-virtualmachine-collector"
+
+// AddToManager adds the collector to the provided manager. The collector always runs since it
+// also destroys the VMs of the deleted VirtualMachine CRs whose deletion grace period expired.
+func AddToManager(ctx *context.ControllerManagerContext, mgr manager.Manager) error {
+	if !lib.IsVMServiceV1Alpha2FSSEnabled() {
+		return nil
+	}
+
+	c := NewCollector(
+		mgr.GetAPIReader(),
+		ctx.Logger.WithName(collectorName),
+		ctx.VMProviderA2,
+		lib.GetOrphanedVMCheckInterval(),
+		lib.IsOrphanedVMDeletionEnabled())
+
+	return mgr.Add(c)
+}
+
+// NewCollector returns a new Collector.
+func NewCollector(
+	reader ctrlclient.Reader,
+	logger logr.Logger,
+	vmProvider vmprovider.VirtualMachineProviderInterfaceA2,
+	interval time.Duration,
+	deletionEnabled bool) *Collector {
+
+	return &Collector{
+		Reader:          reader,
+		Logger:          logger,
+		VMProvider:      vmProvider,
+		Interval:        interval,
+		DeletionEnabled: deletionEnabled,
+		Metrics:         metrics.NewOrphanedVMMetrics(),
+	}
+}
+
+// Collector periodically checks for the vSphere VMs that VM Operator created for a VirtualMachine
+// CR that no longer exists, ex. because the CR's finalizer was removed. The orphaned VMs are
+// reported in the logs and the metrics. The orphaned VMs are only deleted when the deletion is
+// enabled, and then only when a VM was also orphaned in the previous check.
+//
+// The VM of a CR that was deleted with a deletion grace period is kept powered off with the time
+// its grace period expires, and is always destroyed once that time has passed.
+//
+// The check is conservative: only the VMs that are managed by VM Operator and have the ExtraConfig
+// with their VirtualMachine CR are considered, and a VM is not orphaned while there is a CR with
+// the VM's MoID as its unique ID or with the namespace and name in the VM's ExtraConfig.
+type Collector struct {
+	Reader          ctrlclient.Reader
+	Logger          logr.Logger
+	VMProvider      vmprovider.VirtualMachineProviderInterfaceA2
+	Interval        time.Duration
+	DeletionEnabled bool
+	Metrics         *metrics.OrphanedVMMetrics
+
+	lastOrphanedVMs map[vimtypes.ManagedObjectReference]struct{}
+}
+
+// Start runs the check for orphaned VMs every interval until the context is done.
+func (c *Collector) Start(ctx goctx.Context) error {
+	c.Logger.Info("Starting orphaned VM collector", "interval", c.Interval, "deletionEnabled", c.DeletionEnabled)
+	wait.UntilWithContext(ctx, c.collect, c.Interval)
+	return nil
+}
+
+// NeedLeaderElection returns true so only the leader can delete the orphaned VMs.
+func (c *Collector) NeedLeaderElection() bool {
+	return true
+}
+
+func (c *Collector) collect(ctx goctx.Context) {
+	if _, err := c.Collect(ctx); err != nil {
+		c.Logger.Error(err, "Failed to check for orphaned VMs")
+	}
+}
+
+// Collect checks for the orphaned VMs and returns them. The VMs whose deletion grace period
+// expired are deleted, and when the deletion is enabled, so are the VMs that were also orphaned
+// in the previous check.
+func (c *Collector) Collect(ctx goctx.Context) ([]vmprovider.ManagedVirtualMachine, error) {
+	managedVMs, err := c.VMProvider.ListManagedVirtualMachines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The VMs are listed after the vSphere VMs so a VM that is created in between has its CR.
+	vmList := &vmopv1.VirtualMachineList{}
+	if err := c.Reader.List(ctx, vmList); err != nil {
+		return nil, err
+	}
+
+	uniqueIDs := make(map[string]struct{}, len(vmList.Items))
+	vmNames := make(map[types.NamespacedName]struct{}, len(vmList.Items))
+	for _, vm := range vmList.Items {
+		if vm.Status.UniqueID != "" {
+			uniqueIDs[vm.Status.UniqueID] = struct{}{}
+		}
+		vmNames[types.NamespacedName{Namespace: vm.Namespace, Name: vm.Name}] = struct{}{}
+	}
+
+	var orphanedVMs []vmprovider.ManagedVirtualMachine
+	var metricVMs []metrics.OrphanedVM
+	orphanedVMRefs := map[vimtypes.ManagedObjectReference]struct{}{}
+
+	for _, managedVM := range managedVMs {
+		if _, ok := uniqueIDs[managedVM.VM.Value]; ok {
+			continue
+		}
+		if _, ok := vmNames[types.NamespacedName{Namespace: managedVM.Namespace, Name: managedVM.VMName}]; ok {
+			continue
+		}
+
+		c.Logger.Info("Found orphaned VM without a VirtualMachine",
+			"vmMoID", managedVM.VM.Value, "vmName", managedVM.Name,
+			"virtualMachine", types.NamespacedName{Namespace: managedVM.Namespace, Name: managedVM.VMName})
+
+		orphanedVMs = append(orphanedVMs, managedVM)
+		orphanedVMRefs[managedVM.VM] = struct{}{}
+		metricVMs = append(metricVMs, metrics.OrphanedVM{
+			MoID:      managedVM.VM.Value,
+			Name:      managedVM.VMName,
+			Namespace: managedVM.Namespace,
+		})
+	}
+
+	c.Metrics.SetOrphanedVMs(metricVMs)
+
+	for _, orphanedVM := range orphanedVMs {
+		if !orphanedVM.DestroyTime.IsZero() {
+			// The VM of a CR that was deleted with a deletion grace period is kept until the
+			// period expires so the CR may still be created again.
+			if time.Now().Before(orphanedVM.DestroyTime) {
+				continue
+			}
+		} else if _, ok := c.lastOrphanedVMs[orphanedVM.VM]; !ok || !c.DeletionEnabled {
+			continue
+		}
+
+		logger := c.Logger.WithValues("vmMoID", orphanedVM.VM.Value, "vmName", orphanedVM.Name)
+		if err := c.VMProvider.DeleteOrphanedVirtualMachine(ctx, orphanedVM.VM.Value); err != nil {
+			logger.Error(err, "Failed to delete orphaned VM")
+			continue
+		}
+
+		logger.Info("Deleted orphaned VM")
+		c.Metrics.RegisterOrphanedVMDeleted()
+	}
+
+	c.lastOrphanedVMs = orphanedVMRefs
+
+	return orphanedVMs, nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package orphanedvirtualmachine_test
+
+import (
+	goctx "context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/vim25/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/vm-operator/controllers/orphanedvirtualmachine"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	providerfake "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/fake"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func unitTests() {
+	Describe("Invoking Collect", unitTestsCollect)
+}
+
+func unitTestsCollect() {
+	const namespace = "dummy-ns"
+
+	var (
+		initObjects []client.Object
+		ctx         *builder.UnitTestContextForController
+
+		collector       *orphanedvirtualmachine.Collector
+		fakeVMProvider  *providerfake.VMProviderA2
+		deletionEnabled bool
+
+		managedVMs   []vmprovider.ManagedVirtualMachine
+		deletedVMIDs []string
+	)
+
+	managedVM := func(moID, vmName string) vmprovider.ManagedVirtualMachine {
+		return vmprovider.ManagedVirtualMachine{
+			VM:        types.ManagedObjectReference{Type: "VirtualMachine", Value: moID},
+			Name:      vmName,
+			Namespace: namespace,
+			VMName:    vmName,
+		}
+	}
+
+	BeforeEach(func() {
+		vm := builder.DummyBasicVirtualMachineA2("with-unique-id", namespace)
+		vm.Status.UniqueID = "vm-1"
+		vm2 := builder.DummyBasicVirtualMachineA2("without-unique-id", namespace)
+		initObjects = append(initObjects, vm, vm2)
+
+		managedVMs = []vmprovider.ManagedVirtualMachine{
+			managedVM("vm-1", "renamed"),
+			managedVM("vm-2", "without-unique-id"),
+			managedVM("vm-3", "orphaned"),
+		}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewUnitTestContextForController(initObjects...)
+
+		fakeVMProvider = ctx.VMProviderA2.(*providerfake.VMProviderA2)
+		fakeVMProvider.ListManagedVirtualMachinesFn = func(_ goctx.Context) ([]vmprovider.ManagedVirtualMachine, error) {
+			return managedVMs, nil
+		}
+		fakeVMProvider.DeleteOrphanedVirtualMachineFn = func(_ goctx.Context, vmMoID string) error {
+			deletedVMIDs = append(deletedVMIDs, vmMoID)
+			return nil
+		}
+
+		collector = orphanedvirtualmachine.NewCollector(
+			ctx.Client,
+			ctx.Logger,
+			ctx.VMProviderA2,
+			time.Minute,
+			deletionEnabled)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		initObjects = nil
+		collector = nil
+		deletionEnabled = false
+		managedVMs = nil
+		deletedVMIDs = nil
+	})
+
+	It("returns the VMs without a VirtualMachine", func() {
+		orphanedVMs, err := collector.Collect(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(orphanedVMs).To(ConsistOf(managedVM("vm-3", "orphaned")))
+
+		By("does not delete the orphaned VMs", func() {
+			_, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedVMIDs).To(BeEmpty())
+		})
+	})
+
+	When("the managed VMs cannot be listed", func() {
+		JustBeforeEach(func() {
+			fakeVMProvider.ListManagedVirtualMachinesFn = func(_ goctx.Context) ([]vmprovider.ManagedVirtualMachine, error) {
+				return nil, errors.New("fake error")
+			}
+		})
+
+		It("returns the error", func() {
+			_, err := collector.Collect(ctx)
+			Expect(err).To(MatchError("fake error"))
+		})
+	})
+
+	When("the deletion is enabled", func() {
+		BeforeEach(func() {
+			deletionEnabled = true
+		})
+
+		It("deletes the VMs that were also orphaned in the previous check", func() {
+			_, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedVMIDs).To(BeEmpty())
+
+			managedVMs = append(managedVMs, managedVM("vm-4", "orphaned-since-previous-check"))
+
+			orphanedVMs, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphanedVMs).To(HaveLen(2))
+			Expect(deletedVMIDs).To(ConsistOf("vm-3"))
+		})
+
+		It("does not delete a VM that has a VirtualMachine again", func() {
+			_, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			vm := builder.DummyBasicVirtualMachineA2("orphaned", namespace)
+			Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
+
+			orphanedVMs, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphanedVMs).To(BeEmpty())
+			Expect(deletedVMIDs).To(BeEmpty())
+		})
+	})
+
+	When("the VirtualMachine was deleted with a deletion grace period", func() {
+		It("does not delete the VM until the grace period expires", func() {
+			managedVMs[2].DestroyTime = time.Now().Add(time.Hour)
+
+			_, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			orphanedVMs, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphanedVMs).To(HaveLen(1))
+			Expect(deletedVMIDs).To(BeEmpty())
+
+			managedVMs[2].DestroyTime = time.Now().Add(-time.Minute)
+
+			_, err = collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedVMIDs).To(ConsistOf("vm-3"))
+		})
+
+		It("deletes the VM once the grace period expires even when the deletion is disabled", func() {
+			managedVMs[2].DestroyTime = time.Now().Add(-time.Minute)
+
+			_, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedVMIDs).To(ConsistOf("vm-3"))
+		})
+
+		It("does not delete the VM when the VirtualMachine was created again", func() {
+			managedVMs[2].DestroyTime = time.Now().Add(-time.Minute)
+
+			vm := builder.DummyBasicVirtualMachineA2("orphaned", namespace)
+			Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
+
+			orphanedVMs, err := collector.Collect(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphanedVMs).To(BeEmpty())
+			Expect(deletedVMIDs).To(BeEmpty())
+		})
+	})
+}
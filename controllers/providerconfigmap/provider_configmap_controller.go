@@ -11,6 +11,8 @@ package providerconfigmap
 
 import (
 	goctx "context"
+	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,8 +36,9 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	pkgmgr "github.com/vmware-tanzu/vm-operator/pkg/manager"
-	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	"github.com/vmware-tanzu/vm-operator/pkg/record"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere/config"
 )
 
@@ -46,6 +49,30 @@ const (
 	UserWorkloadNamespaceLabel = "vSphereClusterID"
 )
 
+// BindingGCStrategy is how the ContentSourceBindings of a TKG ContentSource are cleaned up once
+// they are no longer needed.
+type BindingGCStrategy string
+
+const (
+	// BindingGCStrategyOwnerRef sets the ContentSource as the owner of its ContentSourceBindings so they
+	// are cascade deleted with it. This is the default.
+	BindingGCStrategyOwnerRef BindingGCStrategy = "owner-ref"
+
+	// BindingGCStrategyLabel labels the ContentSourceBindings instead, and the controller explicitly
+	// deletes the labeled bindings that are not for the configured ContentSource. This is for clusters
+	// where cross-namespace owner references to the cluster-scoped ContentSource are not reliable.
+	BindingGCStrategyLabel BindingGCStrategy = "label"
+)
+
+var supportedBindingGCStrategy = map[BindingGCStrategy]struct{}{
+	BindingGCStrategyOwnerRef: {},
+	BindingGCStrategyLabel:    {},
+}
+
+// ContentLibraryNotFoundReason is the reason of the warning event emitted on the provider ConfigMap
+// when its ContentSource key is not the UUID of an existing content library.
+const ContentLibraryNotFoundReason = "ContentLibraryNotFound"
+
 // AddToManager adds the ConfigMap controller to the manager.
 func AddToManager(ctx *context.ControllerManagerContext, mgr manager.Manager) error {
 	controllerName := "provider-configmap"
@@ -53,7 +80,8 @@ func AddToManager(ctx *context.ControllerManagerContext, mgr manager.Manager) er
 	r := NewReconciler(
 		mgr.GetClient(),
 		ctrl.Log.WithName("controllers").WithName(controllerName),
-		ctx.VMProvider,
+		record.New(mgr.GetEventRecorderFor(ctx.Namespace+"/"+ctx.Name+"/"+controllerName)),
+		contentLibraryProvider(ctx),
 	)
 
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
@@ -132,13 +160,30 @@ func addConfigMapWatch(mgr manager.Manager, c controller.Controller, syncPeriod
 	)
 }
 
+// ContentLibraryProvider is the part of the VM provider the reconciler uses. Both the v1alpha1
+// and v1alpha2 VM providers implement it.
+type ContentLibraryProvider interface {
+	DoesContentLibraryExist(ctx goctx.Context, clUUID string) (bool, error)
+}
+
+// contentLibraryProvider returns the VM provider of the enabled API version since only that
+// one is set in the controller manager context.
+func contentLibraryProvider(ctx *context.ControllerManagerContext) ContentLibraryProvider {
+	if lib.IsVMServiceV1Alpha2FSSEnabled() {
+		return ctx.VMProviderA2
+	}
+	return ctx.VMProvider
+}
+
 func NewReconciler(
 	client client.Client,
 	logger logr.Logger,
-	vmProvider vmprovider.VirtualMachineProviderInterface) *ConfigMapReconciler {
+	recorder record.Recorder,
+	vmProvider ContentLibraryProvider) *ConfigMapReconciler {
 	return &ConfigMapReconciler{
 		Client:     client,
 		Logger:     logger,
+		Recorder:   recorder,
 		vmProvider: vmProvider,
 	}
 }
@@ -146,7 +191,8 @@ func NewReconciler(
 type ConfigMapReconciler struct {
 	client.Client
 	Logger     logr.Logger
-	vmProvider vmprovider.VirtualMachineProviderInterface
+	Recorder   record.Recorder
+	vmProvider ContentLibraryProvider
 }
 
 func (r *ConfigMapReconciler) CreateOrUpdateContentSourceResources(ctx goctx.Context, clUUID string) error {
@@ -204,8 +250,23 @@ func (r *ConfigMapReconciler) CreateOrUpdateContentSourceResources(ctx goctx.Con
 	return nil
 }
 
+// GetBindingGCStrategy returns the ContentSourceBinding garbage collection strategy from the provider ConfigMap.
+func GetBindingGCStrategy(cm *corev1.ConfigMap) (BindingGCStrategy, error) {
+	v, ok := cm.Data[config.ContentSourceBindingGCStrategyKey]
+	if !ok || v == "" {
+		return BindingGCStrategyOwnerRef, nil
+	}
+
+	strategy := BindingGCStrategy(strings.ToLower(v))
+	if _, ok := supportedBindingGCStrategy[strategy]; !ok {
+		return "", fmt.Errorf("unsupported value of %s %q", config.ContentSourceBindingGCStrategyKey, v)
+	}
+
+	return strategy, nil
+}
+
 // CreateContentSourceBindings creates ContentSourceBindings in all the user workload namespaces for the configured TKG ContentSource.
-func (r *ConfigMapReconciler) CreateContentSourceBindings(ctx goctx.Context, clUUID string) error {
+func (r *ConfigMapReconciler) CreateContentSourceBindings(ctx goctx.Context, clUUID string, strategy BindingGCStrategy) error {
 	nsList := &corev1.NamespaceList{}
 	// Presence of the UserWorkloadNamespaceLabel label indicates that a namespace is a user namespace (and not a reserved one). We use
 	// this filtration to create ContentSourceBindings for TKG content source in user namespaces.
@@ -236,9 +297,27 @@ func (r *ConfigMapReconciler) CreateContentSourceBindings(ctx goctx.Context, clU
 		}
 
 		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, csBinding, func() error {
-			// Set OwnerRef to the ContentSource so the bindings get cleaned up when the ContentSource is deleted.
-			if err := controllerutil.SetOwnerReference(cs, csBinding, r.Client.Scheme()); err != nil {
-				return err
+			switch strategy {
+			case BindingGCStrategyLabel:
+				// Label the binding so it can be found and deleted once the ContentSource changes.
+				if csBinding.Labels == nil {
+					csBinding.Labels = map[string]string{}
+				}
+				csBinding.Labels[TKGContentSourceLabelKey] = TKGContentSourceLabelValue
+
+				// Remove any OwnerRef to the ContentSource set while using the owner-ref strategy.
+				ownerRefs := csBinding.OwnerReferences[:0]
+				for _, ref := range csBinding.OwnerReferences {
+					if ref.Kind != gvk.Kind || ref.Name != cs.Name {
+						ownerRefs = append(ownerRefs, ref)
+					}
+				}
+				csBinding.OwnerReferences = ownerRefs
+			default:
+				// Set OwnerRef to the ContentSource so the bindings get cleaned up when the ContentSource is deleted.
+				if err := controllerutil.SetOwnerReference(cs, csBinding, r.Client.Scheme()); err != nil {
+					return err
+				}
 			}
 
 			csBinding.ContentSourceRef = vmopv1.ContentSourceReference{
@@ -258,6 +337,35 @@ func (r *ConfigMapReconciler) CreateContentSourceBindings(ctx goctx.Context, clU
 	return k8serrors.NewAggregate(resErr)
 }
 
+// DeleteStaleContentSourceBindings deletes the labeled TKG ContentSourceBindings that are not for the
+// configured content library. This is only needed with the label garbage collection strategy.
+func (r *ConfigMapReconciler) DeleteStaleContentSourceBindings(ctx goctx.Context, clUUID string) error {
+	bindingList := &vmopv1.ContentSourceBindingList{}
+	labels := map[string]string{TKGContentSourceLabelKey: TKGContentSourceLabelValue}
+
+	if err := r.List(ctx, bindingList, client.MatchingLabels(labels)); err != nil {
+		r.Logger.Error(err, "Error in listing ContentSourceBindings")
+		return err
+	}
+
+	resErr := make([]error, 0)
+	for i := range bindingList.Items {
+		csBinding := &bindingList.Items[i]
+		if csBinding.Name == clUUID {
+			continue
+		}
+
+		r.Logger.Info("Deleting stale ContentSourceBinding", "contentSourceBinding", csBinding.Name, "namespace", csBinding.Namespace)
+		if err := client.IgnoreNotFound(r.Delete(ctx, csBinding)); err != nil {
+			r.Logger.Error(err, "Error in deleting the ContentSourceBinding resource",
+				"contentSourceBinding", csBinding.Name, "namespace", csBinding.Namespace)
+			resErr = append(resErr, err)
+		}
+	}
+
+	return k8serrors.NewAggregate(resErr)
+}
+
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=contentlibraryproviders,verbs=get;list;create;update;delete
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=contentsources,verbs=get;list;create;update;delete
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=contentsourcebindings,verbs=get;list;create;update;delete
@@ -291,6 +399,12 @@ func (r *ConfigMapReconciler) ReconcileNormal(ctx goctx.Context, cm *corev1.Conf
 		return err
 	}
 
+	strategy, err := GetBindingGCStrategy(cm)
+	if err != nil {
+		r.Logger.Error(err, "Invalid ContentSourceBinding garbage collection strategy in provider ConfigMap")
+		return err
+	}
+
 	// Assume that the ContentSource name is the content library UUID.
 	clUUID := cm.Data[config.ContentSourceKey]
 	for _, cs := range csList.Items {
@@ -305,19 +419,40 @@ func (r *ConfigMapReconciler) ReconcileNormal(ctx goctx.Context, cm *corev1.Conf
 		}
 	}
 
+	if strategy == BindingGCStrategyLabel {
+		// The bindings of the deleted ContentSources are not cascade deleted.
+		if err := r.DeleteStaleContentSourceBindings(ctx, clUUID); err != nil {
+			return err
+		}
+	}
+
 	if clUUID == "" {
 		r.Logger.V(4).Info("ContentSource key not found/unset in provider ConfigMap. No op reconcile",
 			"configMapNamespace", cm.Namespace, "configMapName", cm.Name)
 		return nil
 	}
 
+	// Do not create a ContentSource that can never sync because there is no such content library.
+	exists, err := r.vmProvider.DoesContentLibraryExist(ctx, clUUID)
+	if err != nil {
+		r.Logger.Error(err, "Error in checking if the content library exists", "contentLibraryUUID", clUUID)
+		return err
+	}
+	if !exists {
+		r.Logger.Info("ContentSource key in provider ConfigMap is not an existing content library",
+			"contentLibraryUUID", clUUID, "configMapNamespace", cm.Namespace, "configMapName", cm.Name)
+		r.Recorder.Warnf(cm, ContentLibraryNotFoundReason,
+			"ContentSource content library %q does not exist", clUUID)
+		return nil
+	}
+
 	// Ensure that the ContentSource and ContentLibraryProviders exist and are up to date.
 	if err := r.CreateOrUpdateContentSourceResources(ctx, clUUID); err != nil {
 		return err
 	}
 
 	// Ensure that all workload namespaces have access to the TKG ContentSource by creating ContentSourceBindings.
-	if err := r.CreateContentSourceBindings(ctx, clUUID); err != nil {
+	if err := r.CreateContentSourceBindings(ctx, clUUID, strategy); err != nil {
 		return err
 	}
 
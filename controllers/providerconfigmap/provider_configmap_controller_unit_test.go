@@ -4,16 +4,20 @@
 package providerconfigmap_test
 
 import (
+	goctx "context"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
 
 	"github.com/vmware-tanzu/vm-operator/controllers/providerconfigmap"
+	providerfake "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/fake"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere/config"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -47,6 +51,7 @@ func unitTestsCM() {
 			reconciler = providerconfigmap.NewReconciler(
 				ctx.Client,
 				ctx.Logger,
+				ctx.Recorder,
 				ctx.VMProvider,
 			)
 		})
@@ -92,7 +97,7 @@ func unitTestsCM() {
 			})
 		})
 
-		Context("CreateContentSourceBindings", func() {
+		Context("ReconcileNormal", func() {
 			var (
 				workloadNS *corev1.Namespace
 			)
@@ -110,20 +115,162 @@ func unitTestsCM() {
 				initObjects = append(initObjects, cm, workloadNS)
 			})
 
+			When("the CL UUID is not an existing content library", func() {
+				JustBeforeEach(func() {
+					fakeVMProvider := ctx.VMProvider.(*providerfake.VMProvider)
+					fakeVMProvider.DoesContentLibraryExistFn = func(_ goctx.Context, _ string) (bool, error) {
+						return false, nil
+					}
+				})
+
+				It("emits a warning and does not create the ContentSource resources", func() {
+					err := reconciler.ReconcileNormal(ctx, cm)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(ctx.Events).Should(Receive(ContainSubstring(providerconfigmap.ContentLibraryNotFoundReason)))
+
+					objKey := client.ObjectKey{Name: clUUID}
+					Expect(apiErrors.IsNotFound(ctx.Client.Get(ctx, objKey, &vmopv1.ContentSource{}))).To(BeTrue())
+					Expect(apiErrors.IsNotFound(ctx.Client.Get(ctx, objKey, &vmopv1.ContentLibraryProvider{}))).To(BeTrue())
+
+					ctx.ExpectContentSourceBindings(clUUID)
+				})
+			})
+		})
+
+		Context("CreateContentSourceBindings", func() {
+			var (
+				workloadNS, otherWorkloadNS, systemNS *corev1.Namespace
+			)
+			BeforeEach(func() {
+				cm.Data[config.ContentSourceKey] = clUUID
+
+				workloadNS = &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-ns",
+						Labels: map[string]string{
+							providerconfigmap.UserWorkloadNamespaceLabel: "cluster-moid",
+						},
+					},
+				}
+				otherWorkloadNS = &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "other-test-ns",
+						Labels: map[string]string{
+							providerconfigmap.UserWorkloadNamespaceLabel: "cluster-moid",
+						},
+					},
+				}
+				systemNS = &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "system-ns",
+					},
+				}
+				initObjects = append(initObjects, cm, workloadNS, otherWorkloadNS, systemNS)
+			})
+
 			When("called with a CL UUID", func() {
 				It("creates ContentSource and ContentLibraryProvider resources", func() {
 					// So the ContentSource and the ContentLibraryProvider resources are created.
 					err := reconciler.CreateOrUpdateContentSourceResources(ctx, clUUID)
 					Expect(err).NotTo(HaveOccurred())
 
-					err = reconciler.CreateContentSourceBindings(ctx, clUUID)
+					err = reconciler.CreateContentSourceBindings(ctx, clUUID, providerconfigmap.BindingGCStrategyOwnerRef)
 					Expect(err).NotTo(HaveOccurred())
 
+					By("ContentSourceBindings should only be created in the user workload namespaces")
+					ctx.ExpectContentSourceBindings(clUUID, workloadNS.Name, otherWorkloadNS.Name)
+				})
+			})
+
+			When("using the owner-ref garbage collection strategy", func() {
+				It("sets the ContentSource as the owner of the bindings so they are cascade deleted", func() {
+					Expect(reconciler.ReconcileNormal(ctx, cm)).To(Succeed())
+
+					cs := &vmopv1.ContentSource{}
+					Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: clUUID}, cs)).To(Succeed())
+
 					binding := &vmopv1.ContentSourceBinding{}
-					err = ctx.Client.Get(ctx, client.ObjectKey{Name: clUUID, Namespace: workloadNS.Name}, binding)
-					Expect(err).NotTo(HaveOccurred())
+					Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: clUUID, Namespace: workloadNS.Name}, binding)).To(Succeed())
+					Expect(binding.OwnerReferences).To(HaveLen(1))
+					Expect(binding.OwnerReferences[0].Kind).To(Equal(cs.Kind))
+					Expect(binding.OwnerReferences[0].Name).To(Equal(cs.Name))
+					Expect(binding.Labels).ToNot(HaveKey(providerconfigmap.TKGContentSourceLabelKey))
+				})
+			})
+
+			When("using the label garbage collection strategy", func() {
+				BeforeEach(func() {
+					cm.Data[config.ContentSourceBindingGCStrategyKey] = string(providerconfigmap.BindingGCStrategyLabel)
+				})
+
+				AfterEach(func() {
+					delete(cm.Data, config.ContentSourceBindingGCStrategyKey)
+				})
+
+				It("labels the bindings and deletes them once the ContentSource changes", func() {
+					// Start with bindings created with the owner-ref strategy to check they are converted.
+					Expect(reconciler.CreateOrUpdateContentSourceResources(ctx, clUUID)).To(Succeed())
+					Expect(reconciler.CreateContentSourceBindings(ctx, clUUID, providerconfigmap.BindingGCStrategyOwnerRef)).To(Succeed())
+
+					Expect(reconciler.ReconcileNormal(ctx, cm)).To(Succeed())
+					ctx.ExpectContentSourceBindings(clUUID, workloadNS.Name, otherWorkloadNS.Name)
+
+					binding := &vmopv1.ContentSourceBinding{}
+					Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: clUUID, Namespace: workloadNS.Name}, binding)).To(Succeed())
+					Expect(binding.OwnerReferences).To(BeEmpty())
+					Expect(binding.Labels).To(HaveKeyWithValue(providerconfigmap.TKGContentSourceLabelKey, providerconfigmap.TKGContentSourceLabelValue))
+
+					By("changing the ContentSource the old bindings are deleted", func() {
+						newCLUUID := "new-cl"
+						cm.Data[config.ContentSourceKey] = newCLUUID
+						Expect(reconciler.ReconcileNormal(ctx, cm)).To(Succeed())
+
+						ctx.ExpectContentSourceBindings(clUUID)
+						ctx.ExpectContentSourceBindings(newCLUUID, workloadNS.Name, otherWorkloadNS.Name)
+					})
+
+					By("unsetting the ContentSource all the bindings are deleted", func() {
+						delete(cm.Data, config.ContentSourceKey)
+						Expect(reconciler.ReconcileNormal(ctx, cm)).To(Succeed())
+
+						bindingList := &vmopv1.ContentSourceBindingList{}
+						Expect(ctx.Client.List(ctx, bindingList)).To(Succeed())
+						Expect(bindingList.Items).To(BeEmpty())
+					})
 				})
 			})
 		})
+
+		Context("GetBindingGCStrategy", func() {
+			var (
+				strategyCM *corev1.ConfigMap
+			)
+
+			BeforeEach(func() {
+				strategyCM = &corev1.ConfigMap{
+					Data: map[string]string{},
+				}
+			})
+
+			It("defaults to the owner-ref strategy", func() {
+				strategy, err := providerconfigmap.GetBindingGCStrategy(strategyCM)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(strategy).To(Equal(providerconfigmap.BindingGCStrategyOwnerRef))
+			})
+
+			It("returns the label strategy", func() {
+				strategyCM.Data[config.ContentSourceBindingGCStrategyKey] = "Label"
+				strategy, err := providerconfigmap.GetBindingGCStrategy(strategyCM)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(strategy).To(Equal(providerconfigmap.BindingGCStrategyLabel))
+			})
+
+			It("returns an error for an unsupported strategy", func() {
+				strategyCM.Data[config.ContentSourceBindingGCStrategyKey] = "bogus"
+				_, err := providerconfigmap.GetBindingGCStrategy(strategyCM)
+				Expect(err).To(MatchError(`unsupported value of ContentSourceBindingGCStrategy "bogus"`))
+			})
+		})
 	})
 }
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package retainednetworkinterface
+
+import (
+	goctx "context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
+)
+
+const collectorName = "retained-networkinterface-collector"
+
+// AddToManager adds the collector to the provided manager. The collector always runs, even when
+// the network provider CRs of the deleted VMs are no longer retained, so that the CRs retained
+// before then are still deleted once their grace period expires.
+func AddToManager(ctx *context.ControllerManagerContext, mgr manager.Manager) error {
+	c := NewCollector(
+		mgr.GetClient(),
+		ctx.Logger.WithName(collectorName),
+		lib.GetRetainedNetworkInterfaceCollectInterval())
+
+	return mgr.Add(c)
+}
+
+// NewCollector returns a new Collector.
+func NewCollector(
+	client ctrlclient.Client,
+	logger logr.Logger,
+	interval time.Duration) *Collector {
+
+	return &Collector{
+		Client:   client,
+		Logger:   logger,
+		Interval: interval,
+	}
+}
+
+// Collector periodically deletes the retained network provider CRs of the deleted VMs whose grace
+// period has expired. The CRs are otherwise only deleted when another VM in their namespace is
+// reconciled, so the CRs, and the IPs allocated to them, would leak in a namespace without VMs.
+type Collector struct {
+	Client   ctrlclient.Client
+	Logger   logr.Logger
+	Interval time.Duration
+}
+
+// Start deletes the expired network provider CRs every interval until the context is done.
+func (c *Collector) Start(ctx goctx.Context) error {
+	c.Logger.Info("Starting retained network interface collector", "interval", c.Interval)
+	wait.UntilWithContext(ctx, c.collect, c.Interval)
+	return nil
+}
+
+// NeedLeaderElection returns true so only the leader deletes the expired CRs.
+func (c *Collector) NeedLeaderElection() bool {
+	return true
+}
+
+func (c *Collector) collect(ctx goctx.Context) {
+	if err := c.Collect(ctx); err != nil {
+		c.Logger.Error(err, "Failed to delete the expired retained network interfaces")
+	}
+}
+
+// Collect deletes the retained network provider CRs whose grace period has expired.
+func (c *Collector) Collect(ctx goctx.Context) error {
+	return network.DeleteExpiredNetworkInterfaces(ctx, c.Logger, c.Client)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package retainednetworkinterface_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netopv1alpha1 "github.com/vmware-tanzu/vm-operator/external/net-operator/api/v1alpha1"
+
+	"github.com/vmware-tanzu/vm-operator/controllers/retainednetworkinterface"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func unitTests() {
+	Describe("Invoking Collect", unitTestsCollect)
+}
+
+func unitTestsCollect() {
+	var (
+		initObjects []client.Object
+		ctx         *builder.UnitTestContextForController
+
+		collector     *retainednetworkinterface.Collector
+		expiredNetIf  *netopv1alpha1.NetworkInterface
+		retainedNetIf *netopv1alpha1.NetworkInterface
+	)
+
+	retainedNetworkInterface := func(name, namespace string, retainedUntil time.Time) *netopv1alpha1.NetworkInterface {
+		return &netopv1alpha1.NetworkInterface{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					network.RetainedUntilAnnotation: retainedUntil.UTC().Format(time.RFC3339),
+					network.RetainedForAnnotation:   "deleted-vm-uid",
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		Expect(os.Setenv(lib.NetworkProviderType, lib.NetworkProviderTypeVDS)).To(Succeed())
+
+		expiredNetIf = retainedNetworkInterface("expired", "ns-1", time.Now().Add(-time.Minute))
+		retainedNetIf = retainedNetworkInterface("retained", "ns-2", time.Now().Add(time.Hour))
+		initObjects = append(initObjects, expiredNetIf, retainedNetIf)
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewUnitTestContextForController(initObjects...)
+
+		collector = retainednetworkinterface.NewCollector(
+			ctx.Client,
+			ctx.Logger,
+			time.Minute)
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(lib.NetworkProviderType)).To(Succeed())
+		ctx.AfterEach()
+		ctx = nil
+		initObjects = nil
+		collector = nil
+	})
+
+	It("deletes the network interfaces whose grace period expired in every namespace", func() {
+		Expect(collector.Collect(ctx)).To(Succeed())
+
+		err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(expiredNetIf), &netopv1alpha1.NetworkInterface{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(retainedNetIf), &netopv1alpha1.NetworkInterface{})).To(Succeed())
+	})
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +36,7 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/record"
 	kubeutil "github.com/vmware-tanzu/vm-operator/pkg/util/kube"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
 )
 
 const (
@@ -138,6 +141,14 @@ func classToVMMapperFn(ctx *context.ControllerManagerContext, c client.Client) f
 
 		logger.V(4).Info("Reconciling all VMs referencing a VM class because of a VirtualMachineClass watch")
 
+		// A change to the class does not change the classes that inherit from it, so the VMs
+		// that reference those classes are reconciled as well.
+		classNames, err := inheritingClassNames(ctx, c, class)
+		if err != nil {
+			logger.Error(err, "Failed to list VirtualMachineClasses for reconciliation due to VirtualMachineClass watch")
+			return nil
+		}
+
 		// Find all VM resources that reference this VM Class.
 		vmList := &vmopv1.VirtualMachineList{}
 		if err := c.List(ctx, vmList, client.InNamespace(class.Namespace)); err != nil {
@@ -148,7 +159,7 @@ func classToVMMapperFn(ctx *context.ControllerManagerContext, c client.Client) f
 		// Populate reconcile requests for VMs that reference this VM Class.
 		var reconcileRequests []reconcile.Request
 		for _, vm := range vmList.Items {
-			if vm.Spec.ClassName == class.Name {
+			if _, ok := classNames[vm.Spec.ClassName]; ok {
 				key := client.ObjectKey{Namespace: vm.Namespace, Name: vm.Name}
 				reconcileRequests = append(reconcileRequests, reconcile.Request{NamespacedName: key})
 			}
@@ -159,6 +170,36 @@ func classToVMMapperFn(ctx *context.ControllerManagerContext, c client.Client) f
 	}
 }
 
+// inheritingClassNames returns the names of the class and of the classes in its namespace that
+// inherit from it, either directly or through a chain of base classes.
+func inheritingClassNames(
+	ctx goctx.Context,
+	c client.Client,
+	class *vmopv1.VirtualMachineClass) (map[string]struct{}, error) {
+
+	classNames := map[string]struct{}{class.Name: {}}
+
+	classList := &vmopv1.VirtualMachineClassList{}
+	if err := c.List(ctx, classList, client.InNamespace(class.Namespace)); err != nil {
+		return nil, err
+	}
+
+	for added := true; added; {
+		added = false
+		for _, item := range classList.Items {
+			if _, ok := classNames[item.Name]; ok {
+				continue
+			}
+			if _, ok := classNames[item.Spec.InheritsFrom]; ok && item.Spec.InheritsFrom != "" {
+				classNames[item.Name] = struct{}{}
+				added = true
+			}
+		}
+	}
+
+	return classNames, nil
+}
+
 func NewReconciler(
 	client client.Client,
 	logger logr.Logger,
@@ -238,6 +279,13 @@ func (r *Reconciler) Reconcile(ctx goctx.Context, req ctrl.Request) (_ ctrl.Resu
 	}
 
 	if err := r.ReconcileNormal(vmCtx); err != nil {
+		// A transient error, ex. because the VM's create or update from an earlier reconcile is
+		// still in progress, is retried shortly instead of after the error backoff.
+		if errors.Is(err, providererrors.ErrTransient) {
+			vmCtx.Logger.Info("Re-queueing VirtualMachine reconcile", "reason", err.Error())
+			return ctrl.Result{RequeueAfter: transientErrorRequeueDelay}, nil
+		}
+
 		vmCtx.Logger.Error(err, "Failed to reconcile VirtualMachine")
 		return ctrl.Result{}, err
 	}
@@ -245,6 +293,10 @@ func (r *Reconciler) Reconcile(ctx goctx.Context, req ctrl.Request) (_ ctrl.Resu
 	return ctrl.Result{RequeueAfter: requeueDelay(vmCtx)}, nil
 }
 
+// transientErrorRequeueDelay is the delay before the reconcile of a VM that failed with a
+// transient error is retried.
+const transientErrorRequeueDelay = 10 * time.Second
+
 // Determine if we should request a non-zero requeue delay in order to trigger a non-rate limited reconcile
 // at some point in the future.  Use this delay-based reconcile to trigger a specific reconcile to discovery the VM IP
 // address rather than relying on the resync period to do.
@@ -259,6 +311,11 @@ func requeueDelay(ctx *context.VirtualMachineContextA2) time.Duration {
 		return 10 * time.Second
 	}
 
+	// Refresh the progress of the VM's in-flight task until it has completed.
+	if ctx.VM.Status.Task != nil {
+		return 10 * time.Second
+	}
+
 	if ctx.VM.Status.PowerState == vmopv1.VirtualMachinePowerStateOn {
 		network := ctx.VM.Status.Network
 		if network == nil || (network.PrimaryIP4 == "" && network.PrimaryIP6 == "") {
@@ -269,6 +326,61 @@ func requeueDelay(ctx *context.VirtualMachineContextA2) time.Duration {
 	return 0
 }
 
+// updateConvergedCondition sets the VM's Converged condition. When the VM failed to be reconciled
+// or is not yet in its desired state, the provider's diagnostic of the VM is used to report what is
+// blocking the VM.
+func (r *Reconciler) updateConvergedCondition(ctx *context.VirtualMachineContextA2, reconcileErr error) {
+	if reconcileErr == nil && conditions.IsTrue(ctx.VM, vmopv1.VirtualMachineConditionCreated) &&
+		(ctx.VM.Spec.PowerState == "" || ctx.VM.Spec.PowerState == ctx.VM.Status.PowerState) &&
+		requeueDelay(ctx) == 0 {
+
+		conditions.MarkTrue(ctx.VM, vmopv1.VirtualMachineConditionConverged)
+		return
+	}
+
+	diagnostic, err := r.VMProvider.GetVirtualMachineDiagnostic(ctx, ctx.VM)
+	if err != nil {
+		ctx.Logger.Error(err, "Failed to get VirtualMachine diagnostic")
+		return
+	}
+
+	if blocking := diagnostic.Blocking(); blocking != nil {
+		conditions.MarkFalse(ctx.VM, vmopv1.VirtualMachineConditionConverged, blocking.Reason,
+			"%s: %s", blocking.Check, blocking.Message)
+	} else if reconcileErr != nil {
+		conditions.MarkFalse(ctx.VM, vmopv1.VirtualMachineConditionConverged,
+			vmopv1.VirtualMachineReconcileFailedReason, "%s", reconcileErr.Error())
+	} else {
+		conditions.MarkTrue(ctx.VM, vmopv1.VirtualMachineConditionConverged)
+	}
+}
+
+// updateReadyCondition sets the VM's Ready condition to the summary of the conditions that gate
+// whether the VM can be ready. When the VM is not ready, the Ready condition has the reason and
+// message of the condition earliest in the list below. The conditions that only report on the
+// guest or on the VM's configuration, like VMware Tools running or the boot order being synced,
+// do not change whether the VM is ready. The Ready condition is left as-is until the VM has one
+// of the gating conditions.
+//
+// The Ready condition of a VM with a readiness probe is owned by the prober, so is left as-is.
+func updateReadyCondition(vm *vmopv1.VirtualMachine) {
+	if probe := vm.Spec.ReadinessProbe; probe != nil && (probe.TCPSocket != nil || probe.GuestHeartbeat != nil || len(probe.GuestInfo) != 0) {
+		return
+	}
+
+	conditions.SetSummary(vm,
+		conditions.WithConditions(
+			vmopv1.VirtualMachineConditionClassReady,
+			vmopv1.VirtualMachineConditionImageReady,
+			vmopv1.VirtualMachineConditionVMSetResourcePolicyReady,
+			vmopv1.VirtualMachineConditionStorageReady,
+			vmopv1.VirtualMachineConditionBootstrapReady,
+			vmopv1.VirtualMachineConditionNetworkReady,
+			vmopv1.VirtualMachineConditionZoneCompatible,
+			vmopv1.VirtualMachineConditionPlacementReady,
+			vmopv1.VirtualMachineConditionCreated))
+}
+
 func (r *Reconciler) ReconcileDelete(ctx *context.VirtualMachineContextA2) (reterr error) {
 	ctx.Logger.Info("Reconciling VirtualMachine Deletion")
 
@@ -277,6 +389,11 @@ func (r *Reconciler) ReconcileDelete(ctx *context.VirtualMachineContextA2) (rete
 			r.Recorder.EmitEvent(ctx.VM, "Delete", reterr, false)
 		}()
 
+		if err := r.setDestroyTime(ctx); err != nil {
+			ctx.Logger.Error(err, "Failed to get VirtualMachine deletion grace period")
+			return err
+		}
+
 		if err := r.VMProvider.DeleteVirtualMachine(ctx, ctx.VM); err != nil {
 			ctx.Logger.Error(err, "Failed to delete VirtualMachine")
 			return err
@@ -294,6 +411,56 @@ func (r *Reconciler) ReconcileDelete(ctx *context.VirtualMachineContextA2) (rete
 	return nil
 }
 
+// setDestroyTime sets the time after which the deleted VM's vSphere VM is destroyed when the VM,
+// or otherwise its namespace, has a deletion grace period. The provider records the time on the
+// powered off vSphere VM, and the orphaned VM collector destroys it once the time has passed, so
+// the finalizer is not held for the grace period. The time is only set once so the grace period
+// is not extended by later reconciles, and the grace period is capped by the configured maximum.
+func (r *Reconciler) setDestroyTime(ctx *context.VirtualMachineContextA2) error {
+	if ctx.VM.Status.DestroyTime != nil {
+		return nil
+	}
+
+	maxGracePeriod := lib.GetMaxDeletionGracePeriod()
+	if maxGracePeriod == 0 {
+		return nil
+	}
+
+	value, ok := ctx.VM.Annotations[vmopv1.DeletionGracePeriodAnnotation]
+	if !ok {
+		ns := &corev1.Namespace{}
+		if err := r.Get(ctx, client.ObjectKey{Name: ctx.VM.Namespace}, ns); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		if value, ok = ns.Annotations[vmopv1.DeletionGracePeriodAnnotation]; !ok {
+			return nil
+		}
+	}
+
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation value %q: %w", vmopv1.DeletionGracePeriodAnnotation, value, err)
+	}
+
+	if gracePeriod <= 0 {
+		return nil
+	}
+	if gracePeriod > maxGracePeriod {
+		gracePeriod = maxGracePeriod
+	}
+
+	deletionTime := time.Now()
+	if ctx.VM.DeletionTimestamp != nil {
+		deletionTime = ctx.VM.DeletionTimestamp.Time
+	}
+
+	destroyTime := metav1.NewTime(deletionTime.Add(gracePeriod))
+	ctx.VM.Status.DestroyTime = &destroyTime
+
+	return nil
+}
+
 // ReconcileNormal processes a level trigger for this VM: create if it doesn't exist otherwise update the existing VM.
 func (r *Reconciler) ReconcileNormal(ctx *context.VirtualMachineContextA2) (reterr error) {
 	if !controllerutil.ContainsFinalizer(ctx.VM, finalizerName) {
@@ -328,10 +495,24 @@ func (r *Reconciler) ReconcileNormal(ctx *context.VirtualMachineContextA2) (rete
 	}()
 
 	if err := r.VMProvider.CreateOrUpdateVirtualMachine(ctx, ctx.VM); err != nil {
-		r.Recorder.EmitEvent(ctx.VM, "CreateOrUpdate", err, false)
+		if !errors.Is(err, providererrors.ErrTransient) {
+			r.Recorder.EmitEvent(ctx.VM, "CreateOrUpdate", err, false)
+		} else {
+			// The VM's create or update may still be in-flight from an earlier reconcile, so
+			// report the progress of its vSphere task. The provider also writes the progress
+			// to the status while the task runs.
+			if err := r.VMProvider.UpdateVirtualMachineTaskStatus(ctx, ctx.VM); err != nil {
+				ctx.Logger.Error(err, "Failed to update the status of the VM's in-flight task")
+			}
+		}
+		r.updateConvergedCondition(ctx, err)
+		updateReadyCondition(ctx.VM)
 		return err
 	}
 
+	r.updateConvergedCondition(ctx, nil)
+	updateReadyCondition(ctx.VM)
+
 	// Add this VM to prober manager if ReconcileNormal succeeds.
 	r.Prober.AddToProberManager(ctx.VM)
 
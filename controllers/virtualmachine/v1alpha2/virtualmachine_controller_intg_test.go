@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -15,6 +16,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 
@@ -170,6 +172,56 @@ func intgTests() {
 			})
 		})
 
+		When("the VM's class inherits from a base class", func() {
+			var (
+				baseClass  *vmopv1.VirtualMachineClass
+				vmClass    *vmopv1.VirtualMachineClass
+				reconciles int32
+			)
+
+			BeforeEach(func() {
+				baseClass = builder.DummyVirtualMachineClass2A2("dummy-base-class")
+				baseClass.Namespace = ctx.Namespace
+				Expect(ctx.Client.Create(ctx, baseClass)).To(Succeed())
+
+				vmClass = builder.DummyVirtualMachineClass2A2(vm.Spec.ClassName)
+				vmClass.Namespace = ctx.Namespace
+				vmClass.Spec.InheritsFrom = baseClass.Name
+				Expect(ctx.Client.Create(ctx, vmClass)).To(Succeed())
+
+				reconciles = 0
+				intgFakeVMProvider.Lock()
+				intgFakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+					atomic.AddInt32(&reconciles, 1)
+					return nil
+				}
+				intgFakeVMProvider.Unlock()
+			})
+
+			AfterEach(func() {
+				Expect(ctx.Client.Delete(ctx, vmClass)).To(Succeed())
+				Expect(ctx.Client.Delete(ctx, baseClass)).To(Succeed())
+			})
+
+			It("Reconciles the VM when the base class changes", func() {
+				Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
+				waitForVirtualMachineFinalizer(ctx, vmKey)
+
+				Eventually(func() int32 {
+					return atomic.LoadInt32(&reconciles)
+				}).Should(BeNumerically(">", 0))
+				previous := atomic.LoadInt32(&reconciles)
+
+				Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(baseClass), baseClass)).To(Succeed())
+				baseClass.Spec.Hardware.Cpus++
+				Expect(ctx.Client.Update(ctx, baseClass)).To(Succeed())
+
+				Eventually(func() int32 {
+					return atomic.LoadInt32(&reconciles)
+				}).Should(BeNumerically(">", previous))
+			})
+		})
+
 		It("Reconciles after VirtualMachine deletion", func() {
 			Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
 			// Wait for initial reconcile.
@@ -6,19 +6,27 @@ package v1alpha2_test
 import (
 	"context"
 	"errors"
+	"os"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 
 	virtualmachine "github.com/vmware-tanzu/vm-operator/controllers/virtualmachine/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	vmopContext "github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	proberfake "github.com/vmware-tanzu/vm-operator/pkg/prober2/fake"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
 	providerfake "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/fake"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -125,6 +133,141 @@ func unitTestsReconcile() {
 			expectEvent(ctx, "CreateOrUpdateFailure")
 		})
 
+		It("will requeue the VM without an error when the provider's operation on the VM is in progress", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				return providererrors.NewTransient(errors.New("update of VirtualMachine is already in progress"))
+			}
+
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vm)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+			Expect(ctx.Events).ToNot(Receive(ContainSubstring("CreateOrUpdateFailure")))
+		})
+
+		It("will report what is blocking the VM when provider fails to CreateOrUpdate VM", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				return errors.New(providerError)
+			}
+			fakeVMProvider.GetVirtualMachineDiagnosticFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineDiagnostic, error) {
+				return vmprovider.VirtualMachineDiagnostic{
+					Results: []vmprovider.DiagnosticResult{
+						{
+							Check:   vmprovider.DiagnosticCheckImage,
+							Reason:  vmopv1.VirtualMachineImageNotReadyReason,
+							Message: "image is importing",
+						},
+						{Check: vmprovider.DiagnosticCheckPowerState, Ready: true},
+					},
+				}, nil
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).ToNot(Succeed())
+			c := conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionConverged)
+			Expect(c).ToNot(BeNil())
+			Expect(c.Status).To(Equal(metav1.ConditionFalse))
+			Expect(c.Reason).To(Equal(vmopv1.VirtualMachineImageNotReadyReason))
+			Expect(c.Message).To(Equal("Image: image is importing"))
+		})
+
+		It("will report the provider error when nothing is blocking the VM", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				return errors.New(providerError)
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).ToNot(Succeed())
+			Expect(conditions.IsFalse(vmCtx.VM, vmopv1.VirtualMachineConditionConverged)).To(BeTrue())
+			Expect(conditions.GetReason(vmCtx.VM, vmopv1.VirtualMachineConditionConverged)).To(Equal(vmopv1.VirtualMachineReconcileFailedReason))
+			Expect(conditions.GetMessage(vmCtx.VM, vmopv1.VirtualMachineConditionConverged)).To(Equal(providerError))
+		})
+
+		It("will mark the VM as converged when it is in its desired state", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionCreated)
+				return nil
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).To(Succeed())
+			Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionConverged)).To(BeTrue())
+		})
+
+		It("will summarize the first of the VM's gating conditions that is not true in the Ready condition", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				conditions.MarkFalse(vm, vmopv1.VirtualMachineToolsCondition, vmopv1.VirtualMachineToolsNotRunningReason, "")
+				conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionPlacementReady, "NotPlaced", "")
+				conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionNetworkReady, vmopv1.VirtualMachineNetworkInterfaceNotReadyReason, "nic is not ready")
+				return nil
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).To(Succeed())
+			c := conditions.Get(vmCtx.VM, vmopv1.ReadyConditionType)
+			Expect(c).ToNot(BeNil())
+			Expect(c.Status).To(Equal(metav1.ConditionFalse))
+			Expect(c.Reason).To(Equal(vmopv1.VirtualMachineNetworkInterfaceNotReadyReason))
+			Expect(c.Message).To(Equal("nic is not ready"))
+		})
+
+		It("will not mark the VM as not ready for the conditions that do not gate its readiness", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionCreated)
+				conditions.MarkFalse(vm, vmopv1.VirtualMachineToolsCondition, vmopv1.VirtualMachineToolsNotRunningReason, "")
+				conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionNetworkInterfaceCompatible, "Incompatible", "")
+				conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionBootOrderSynced, "NotSynced", "")
+				return nil
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).To(Succeed())
+			Expect(conditions.IsTrue(vmCtx.VM, vmopv1.ReadyConditionType)).To(BeTrue())
+		})
+
+		It("will mark the VM as ready when all of its gating conditions are true", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionCreated)
+				conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionNetworkReady)
+				return nil
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).To(Succeed())
+			Expect(conditions.IsTrue(vmCtx.VM, vmopv1.ReadyConditionType)).To(BeTrue())
+		})
+
+		It("will not set the Ready condition of a VM without any of its gating conditions", func() {
+			Expect(reconciler.ReconcileNormal(vmCtx)).To(Succeed())
+			Expect(conditions.Has(vmCtx.VM, vmopv1.ReadyConditionType)).To(BeFalse())
+		})
+
+		It("will not set the Ready condition of a VM with a readiness probe", func() {
+			vmCtx.VM.Spec.ReadinessProbe = &vmopv1.VirtualMachineReadinessProbeSpec{
+				TCPSocket: &vmopv1.TCPSocketAction{},
+			}
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionCreated)
+				return nil
+			}
+
+			Expect(reconciler.ReconcileNormal(vmCtx)).To(Succeed())
+			Expect(conditions.Has(vmCtx.VM, vmopv1.ReadyConditionType)).To(BeFalse())
+		})
+
+		It("will report the progress of the VM's in-flight task", func() {
+			fakeVMProvider.CreateOrUpdateVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				return providererrors.NewTransient(errors.New("create is already in progress"))
+			}
+			fakeVMProvider.UpdateVirtualMachineTaskStatusFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+				vm.Status.Task = &vmopv1.VirtualMachineTaskStatus{
+					Operation:   "create",
+					Phase:       "running",
+					Description: "VirtualMachine.clone",
+					Progress:    42,
+				}
+				return nil
+			}
+
+			err := reconciler.ReconcileNormal(vmCtx)
+			Expect(errors.Is(err, providererrors.ErrTransient)).To(BeTrue())
+			Expect(vmCtx.VM.Status.Task).ToNot(BeNil())
+			Expect(vmCtx.VM.Status.Task.Progress).To(BeEquivalentTo(42))
+		})
+
 		It("can be called multiple times", func() {
 			err := reconciler.ReconcileNormal(vmCtx)
 			Expect(err).ToNot(HaveOccurred())
@@ -203,6 +346,101 @@ func unitTestsReconcile() {
 			Expect(reconciler.ReconcileDelete(vmCtx)).Should(Succeed())
 			Expect(fakeProbeManager.IsRemoveFromProberManagerCalled).Should(BeTrue())
 		})
+
+		Context("Deletion grace period", func() {
+			var providerDestroyTime *metav1.Time
+
+			BeforeEach(func() {
+				Expect(os.Setenv(lib.MaxDeletionGracePeriodEnv, "24h")).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv(lib.MaxDeletionGracePeriodEnv)).To(Succeed())
+			})
+
+			JustBeforeEach(func() {
+				providerDestroyTime = nil
+				fakeVMProvider.DeleteVirtualMachineFn = func(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+					providerDestroyTime = vm.Status.DestroyTime
+					return nil
+				}
+			})
+
+			When("the VM has a deletion grace period", func() {
+				BeforeEach(func() {
+					vm.Annotations = map[string]string{vmopv1.DeletionGracePeriodAnnotation: "1h"}
+				})
+
+				It("will pass the destroy time to the provider and remove the finalizer", func() {
+					Expect(reconciler.ReconcileDelete(vmCtx)).To(Succeed())
+					Expect(vm.Finalizers).ToNot(ContainElement(finalizer))
+					Expect(vm.Status.DestroyTime).ToNot(BeNil())
+					Expect(vm.Status.DestroyTime.Time).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+					Expect(providerDestroyTime).To(Equal(vm.Status.DestroyTime))
+				})
+
+				It("will not extend the grace period", func() {
+					destroyTime := metav1.NewTime(time.Now().Add(time.Minute))
+					vm.Status.DestroyTime = &destroyTime
+
+					Expect(reconciler.ReconcileDelete(vmCtx)).To(Succeed())
+					Expect(vm.Status.DestroyTime).To(Equal(&destroyTime))
+					Expect(providerDestroyTime).To(Equal(&destroyTime))
+				})
+
+				It("will cap the grace period at the configured maximum", func() {
+					Expect(os.Setenv(lib.MaxDeletionGracePeriodEnv, "10m")).To(Succeed())
+
+					Expect(reconciler.ReconcileDelete(vmCtx)).To(Succeed())
+					Expect(vm.Status.DestroyTime).ToNot(BeNil())
+					Expect(vm.Status.DestroyTime.Time).To(BeTemporally("~", time.Now().Add(10*time.Minute), time.Minute))
+				})
+
+				It("will ignore the grace period when no maximum is configured", func() {
+					Expect(os.Unsetenv(lib.MaxDeletionGracePeriodEnv)).To(Succeed())
+
+					Expect(reconciler.ReconcileDelete(vmCtx)).To(Succeed())
+					Expect(vm.Finalizers).ToNot(ContainElement(finalizer))
+					Expect(vm.Status.DestroyTime).To(BeNil())
+				})
+			})
+
+			When("the VM has an invalid deletion grace period", func() {
+				BeforeEach(func() {
+					vm.Annotations = map[string]string{vmopv1.DeletionGracePeriodAnnotation: "1 day"}
+				})
+
+				It("will not delete the VM", func() {
+					Expect(reconciler.ReconcileDelete(vmCtx)).ToNot(Succeed())
+					Expect(vm.Finalizers).To(ContainElement(finalizer))
+					Expect(providerDestroyTime).To(BeNil())
+				})
+			})
+
+			When("the VM's namespace has a deletion grace period", func() {
+				BeforeEach(func() {
+					initObjects = append(initObjects, &corev1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        vm.Namespace,
+							Annotations: map[string]string{vmopv1.DeletionGracePeriodAnnotation: "1h"},
+						},
+					})
+				})
+
+				It("will use the namespace's grace period", func() {
+					Expect(reconciler.ReconcileDelete(vmCtx)).To(Succeed())
+					Expect(vm.Status.DestroyTime).ToNot(BeNil())
+				})
+
+				It("will use the VM's deletion grace period instead", func() {
+					vm.Annotations = map[string]string{vmopv1.DeletionGracePeriodAnnotation: "0s"}
+
+					Expect(reconciler.ReconcileDelete(vmCtx)).To(Succeed())
+					Expect(vm.Finalizers).ToNot(ContainElement(finalizer))
+					Expect(vm.Status.DestroyTime).To(BeNil())
+				})
+			})
+		})
 	})
 }
 
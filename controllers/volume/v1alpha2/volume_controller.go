@@ -29,13 +29,14 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 
 	cnsv1alpha1 "github.com/vmware-tanzu/vm-operator/external/vsphere-csi-driver/pkg/syncer/cnsoperator/apis/cnsnodevmattachment/v1alpha1"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	patch "github.com/vmware-tanzu/vm-operator/pkg/patch2"
 	"github.com/vmware-tanzu/vm-operator/pkg/record"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere/constants"
-	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere/instancestorage"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
 )
 
 const (
@@ -185,6 +186,11 @@ func (r *Reconciler) reconcileResult(ctx *context.VolumeContextA2) ctrl.Result {
 		}
 	}
 
+	// Requeue so the VolumesAttached condition reports a pending attachment once it times out.
+	if ctx.VolumeAttachRequeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: ctx.VolumeAttachRequeueAfter}
+	}
+
 	return ctrl.Result{}
 }
 
@@ -333,6 +339,9 @@ func (r *Reconciler) reconcileInstanceStoragePVCs(ctx *context.VolumeContextA2)
 	if fullyBound {
 		// All of our instance storage volumes are bound. This is our final state.
 		ctx.VM.Annotations[constants.InstanceStoragePVCsBoundAnnotationKey] = lib.TrueString
+		if conditions.Has(ctx.VM, vmopv1.VirtualMachineConditionInstanceStoragePlacement) {
+			conditions.MarkTrue(ctx.VM, vmopv1.VirtualMachineConditionInstanceStoragePlacement)
+		}
 	}
 
 	// There are some implicit relationship between these values. Like there should have been
@@ -366,6 +375,16 @@ func (r *Reconciler) instanceStoragePlacementFailed(
 	ctx *context.VolumeContextA2,
 	failedVolumesMap map[string]struct{}) error {
 
+	// Record the failed host so placement excludes it when selecting another host.
+	if hostMoID := ctx.VM.Annotations[constants.InstanceStorageSelectedNodeMOIDAnnotationKey]; hostMoID != "" {
+		failedHostMoIDs := instancestorage.AddFailedHostMoID(ctx.VM, hostMoID)
+		ctx.Logger.Info("Instance storage placement failed on host", "hostMoID", hostMoID,
+			"failedHostMoIDs", failedHostMoIDs)
+		conditions.MarkFalse(ctx.VM, vmopv1.VirtualMachineConditionInstanceStoragePlacement,
+			vmopv1.VirtualMachineInstanceStoragePlacementRetryingReason,
+			"Instance storage volumes failed to be placed on hosts: %s", strings.Join(failedHostMoIDs, ", "))
+	}
+
 	// Tell the VM controller that it needs to compute placement again.
 	delete(ctx.VM.Annotations, constants.InstanceStorageSelectedNodeAnnotationKey)
 	delete(ctx.VM.Annotations, constants.InstanceStorageSelectedNodeMOIDAnnotationKey)
@@ -597,15 +616,85 @@ func (r *Reconciler) processAttachments(
 	// still exist are included in the Status. This is more than a little odd.
 	volumeStatus = append(volumeStatus, r.preserveOrphanedAttachmentStatus(ctx, orphanedAttachments)...)
 
+	// The FirstClassDisk volumes are attached by the VM provider, which also owns their Status entries.
+	volumeStatus = append(volumeStatus, preserveFirstClassDiskStatus(ctx)...)
+
 	// This is how the previous code sorted, but IMO keeping in Spec order makes more sense.
 	sort.Slice(volumeStatus, func(i, j int) bool {
 		return volumeStatus[i].DiskUUID < volumeStatus[j].DiskUUID
 	})
 	ctx.VM.Status.Volumes = volumeStatus
 
+	markVolumesAttachedCondition(ctx, attachments, time.Now())
+
 	return k8serrors.NewAggregate(createErrs)
 }
 
+// markVolumesAttachedCondition sets the VM's VolumesAttached condition from the attachments of
+// its PersistentVolumeClaim volumes. An attachment that is not attached within the attach timeout
+// of its creation is reported as timed out, and the context's VolumeAttachRequeueAfter is set to
+// the time until the earliest pending attachment times out.
+func markVolumesAttachedCondition(
+	ctx *context.VolumeContextA2,
+	attachments map[string]cnsv1alpha1.CnsNodeVmAttachment,
+	now time.Time) {
+
+	timeout := lib.GetVolumeAttachTimeout()
+	ctx.VolumeAttachRequeueAfter = 0
+
+	var hasPVCVolume bool
+	var pending, failed, timedOut []string
+
+	for _, volume := range ctx.VM.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		hasPVCVolume = true
+
+		attachment, ok := attachments[CNSAttachmentNameForVolume(ctx.VM.Name, volume.Name)]
+		switch {
+		case !ok:
+			// The attachment was just created or is waiting on a previous volume's attachment.
+			pending = append(pending, volume.Name)
+		case attachment.Status.Attached:
+		case attachment.Status.Error != "":
+			failed = append(failed, volume.Name)
+		case attachment.CreationTimestamp.IsZero():
+			pending = append(pending, volume.Name)
+		default:
+			remaining := attachment.CreationTimestamp.Add(timeout).Sub(now)
+			if remaining <= 0 {
+				timedOut = append(timedOut, volume.Name)
+				continue
+			}
+
+			pending = append(pending, volume.Name)
+			if ctx.VolumeAttachRequeueAfter == 0 || remaining < ctx.VolumeAttachRequeueAfter {
+				ctx.VolumeAttachRequeueAfter = remaining
+			}
+		}
+	}
+
+	switch {
+	case !hasPVCVolume:
+		conditions.Delete(ctx.VM, vmopv1.VirtualMachineConditionVolumesAttached)
+	case len(failed) > 0:
+		conditions.MarkFalse(ctx.VM, vmopv1.VirtualMachineConditionVolumesAttached,
+			vmopv1.VirtualMachineVolumesAttachFailedReason,
+			"Volumes failed to attach: %s", strings.Join(failed, ", "))
+	case len(timedOut) > 0:
+		conditions.MarkFalse(ctx.VM, vmopv1.VirtualMachineConditionVolumesAttached,
+			vmopv1.VirtualMachineVolumesAttachTimedOutReason,
+			"Volumes not attached within %s: %s", timeout, strings.Join(timedOut, ", "))
+	case len(pending) > 0:
+		conditions.MarkFalse(ctx.VM, vmopv1.VirtualMachineConditionVolumesAttached,
+			vmopv1.VirtualMachineVolumesAttachPendingReason,
+			"Volumes are being attached: %s", strings.Join(pending, ", "))
+	default:
+		conditions.MarkTrue(ctx.VM, vmopv1.VirtualMachineConditionVolumesAttached)
+	}
+}
+
 func (r *Reconciler) createCNSAttachment(
 	ctx *context.VolumeContextA2,
 	attachmentName string,
@@ -717,6 +806,17 @@ func (r *Reconciler) preserveOrphanedAttachmentStatus(
 	return volumeStatus
 }
 
+func preserveFirstClassDiskStatus(ctx *context.VolumeContextA2) []vmopv1.VirtualMachineVolumeStatus {
+	var volumeStatus []vmopv1.VirtualMachineVolumeStatus
+	for _, volume := range ctx.VM.Status.Volumes {
+		if volume.DiskID != "" {
+			volumeStatus = append(volumeStatus, volume)
+		}
+	}
+
+	return volumeStatus
+}
+
 func (r *Reconciler) attachmentsToDelete(
 	ctx *context.VolumeContextA2,
 	attachments map[string]cnsv1alpha1.CnsNodeVmAttachment) []cnsv1alpha1.CnsNodeVmAttachment {
@@ -23,6 +23,7 @@ import (
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	volume "github.com/vmware-tanzu/vm-operator/controllers/volume/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	volContext "github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	providerfake "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/fake"
@@ -207,6 +208,39 @@ func unitTestsReconcile() {
 					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
 					expectPVCsStatus(volCtx, ctx, false, false, 0)
 				})
+
+				By("Failed host is recorded", func() {
+					Expect(vm.Annotations).To(HaveKeyWithValue(constants.InstanceStorageFailedNodeMOIDsAnnotationKey, "host-88"))
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionInstanceStoragePlacement)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineInstanceStoragePlacementRetryingReason))
+					Expect(c.Message).To(ContainSubstring("host-88"))
+				})
+			})
+
+			It("PVCs placement failed on a previous host - records all failed hosts", func() {
+				vm.Annotations[constants.InstanceStorageFailedNodeMOIDsAnnotationKey] = "host-42"
+
+				By("create PVCs and not realized", func() {
+					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
+					expectPVCsStatus(volCtx, ctx, true, false, len(vm.Spec.Volumes))
+				})
+
+				By("Adjust PVC CreationTimestamp", func() {
+					adjustPVCCreationTimestamp(volCtx, ctx)
+				})
+
+				By("PVCs realization turned into error - remove all PVCs", func() {
+					patchInstanceStoragePVCs(volCtx, ctx, false, true)
+					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
+					expectPVCsStatus(volCtx, ctx, false, false, 0)
+				})
+
+				By("Failed hosts are recorded", func() {
+					Expect(vm.Annotations).To(HaveKeyWithValue(constants.InstanceStorageFailedNodeMOIDsAnnotationKey, "host-42,host-88"))
+					Expect(conditions.GetMessage(vm, vmopv1.VirtualMachineConditionInstanceStoragePlacement)).To(ContainSubstring("host-42, host-88"))
+				})
 			})
 
 			It("PVCs are created and realized", func() {
@@ -719,6 +753,43 @@ FaultMessage: ([]types.LocalizableMessage) \u003cnil\u003e\\n }\\n },\\n Type: (
 			})
 		})
 
+		When("VM has FirstClassDisk volume in Status.Volumes", func() {
+			var fcdVolStatus vmopv1.VirtualMachineVolumeStatus
+
+			BeforeEach(func() {
+				vm.Spec.Volumes = append(vm.Spec.Volumes, vmopv1.VirtualMachineVolume{
+					Name: "fcd-volume",
+					VirtualMachineVolumeSource: vmopv1.VirtualMachineVolumeSource{
+						FirstClassDisk: &vmopv1.FirstClassDiskVolumeSource{
+							DiskID: "fcd-disk-id",
+						},
+					},
+				})
+
+				fcdVolStatus = vmopv1.VirtualMachineVolumeStatus{
+					Name:     "fcd-volume",
+					Attached: true,
+					DiskUUID: dummyDiskUUID,
+					DiskID:   "fcd-disk-id",
+				}
+				vm.Status.Volumes = append(vm.Status.Volumes, fcdVolStatus)
+			})
+
+			It("returns success", func() {
+				err := reconciler.ReconcileNormal(volCtx)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Did not create CnsNodeVmAttachment", func() {
+					Expect(getCNSAttachmentForVolumeName(vm, "fcd-volume")).To(BeNil())
+				})
+
+				By("FirstClassDisk volume preserved in Status.Volumes", func() {
+					Expect(vm.Status.Volumes).To(HaveLen(1))
+					Expect(vm.Status.Volumes[0]).To(Equal(fcdVolStatus))
+				})
+			})
+		})
+
 		When("VM Status.Volumes is sorted as expected", func() {
 			var vmVol1 vmopv1.VirtualMachineVolume
 			var vmVol2 vmopv1.VirtualMachineVolume
@@ -793,6 +864,115 @@ FaultMessage: ([]types.LocalizableMessage) \u003cnil\u003e\\n }\\n },\\n Type: (
 				})
 			})
 		})
+
+		When("VM Spec.Volumes has CNS volumes that are attached and missing", func() {
+			var attachedVol, missingVol vmopv1.VirtualMachineVolume
+
+			BeforeEach(func() {
+				attachedVol = *vmVolumeWithPVC1
+				missingVol = *vmVolumeWithPVC2
+				vm.Spec.Volumes = append(vm.Spec.Volumes, attachedVol, missingVol)
+				vm.Status.PowerState = vmopv1.VirtualMachinePowerStateOn
+
+				attachment := cnsAttachmentForVMVolume(vm, attachedVol)
+				builder.AttachVolumeToVM(attachment, dummyDiskUUID)
+				initObjects = append(initObjects, attachment)
+			})
+
+			When("missing volume is within the attach timeout", func() {
+				BeforeEach(func() {
+					attachment := cnsAttachmentForVMVolume(vm, missingVol)
+					attachment.CreationTimestamp = metav1.NewTime(time.Now())
+					initObjects = append(initObjects, attachment)
+				})
+
+				It("reports the attached volume and the pending volume", func() {
+					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
+
+					By("Attached volume is in VM Status.Volumes", func() {
+						Expect(vm.Status.Volumes).To(HaveLen(2))
+						Expect(vm.Status.Volumes).To(ContainElement(vmopv1.VirtualMachineVolumeStatus{
+							Name:     attachedVol.Name,
+							Attached: true,
+							DiskUUID: dummyDiskUUID,
+						}))
+					})
+
+					By("Condition is pending", func() {
+						c := conditions.Get(vm, vmopv1.VirtualMachineConditionVolumesAttached)
+						Expect(c).ToNot(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionFalse))
+						Expect(c.Reason).To(Equal(vmopv1.VirtualMachineVolumesAttachPendingReason))
+						Expect(c.Message).To(ContainSubstring(missingVol.Name))
+						Expect(c.Message).ToNot(ContainSubstring(attachedVol.Name))
+					})
+
+					By("Requeues for when the attachment times out", func() {
+						Expect(volCtx.VolumeAttachRequeueAfter).To(BeNumerically(">", 0))
+						Expect(volCtx.VolumeAttachRequeueAfter).To(BeNumerically("<=", lib.GetVolumeAttachTimeout()))
+					})
+				})
+			})
+
+			When("missing volume is not attached within the attach timeout", func() {
+				BeforeEach(func() {
+					attachment := cnsAttachmentForVMVolume(vm, missingVol)
+					attachment.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * lib.GetVolumeAttachTimeout()))
+					initObjects = append(initObjects, attachment)
+				})
+
+				It("reports the missing volume in the condition", func() {
+					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
+
+					Expect(vm.Status.Volumes).To(ContainElement(vmopv1.VirtualMachineVolumeStatus{
+						Name:     attachedVol.Name,
+						Attached: true,
+						DiskUUID: dummyDiskUUID,
+					}))
+
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionVolumesAttached)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineVolumesAttachTimedOutReason))
+					Expect(c.Message).To(ContainSubstring(missingVol.Name))
+					Expect(c.Message).ToNot(ContainSubstring(attachedVol.Name))
+					Expect(volCtx.VolumeAttachRequeueAfter).To(BeZero())
+				})
+			})
+
+			When("missing volume failed to attach", func() {
+				BeforeEach(func() {
+					attachment := cnsAttachmentForVMVolume(vm, missingVol)
+					attachment.Status.Error = "attach failed"
+					initObjects = append(initObjects, attachment)
+				})
+
+				It("reports the failed volume in the condition", func() {
+					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
+
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionVolumesAttached)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineVolumesAttachFailedReason))
+					Expect(c.Message).To(ContainSubstring(missingVol.Name))
+				})
+			})
+
+			When("missing volume is then attached", func() {
+				BeforeEach(func() {
+					attachment := cnsAttachmentForVMVolume(vm, missingVol)
+					builder.AttachVolumeToVM(attachment, "other-disk-uuid")
+					initObjects = append(initObjects, attachment)
+				})
+
+				It("marks the condition true", func() {
+					Expect(reconciler.ReconcileNormal(volCtx)).To(Succeed())
+
+					Expect(vm.Status.Volumes).To(HaveLen(2))
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionVolumesAttached)).To(BeTrue())
+				})
+			})
+		})
 	})
 
 	Context("ReconcileDelete", func() {
@@ -215,6 +215,24 @@ func TestSummary(t *testing.T) {
 			options: []MergeOption{WithConditions("baz", "bar")}, // baz should take precedence on bar
 			want:    FalseCondition(vmopv1.ReadyConditionType, "reason falseInfo2", "message falseInfo2"),
 		},
+		{
+			name:    "Ready condition reflects the condition with the highest severity",
+			from:    getterWithConditions(foo, bar, baz),
+			options: []MergeOption{WithSeverity(SeverityInfo, "bar"), WithSeverity(SeverityWarning, "baz")}, // baz should take precedence on bar
+			want:    FalseCondition(vmopv1.ReadyConditionType, "reason falseInfo2", "message falseInfo2"),
+		},
+		{
+			name:    "Ready condition defaults to the error severity",
+			from:    getterWithConditions(foo, bar, baz),
+			options: []MergeOption{WithSeverity(SeverityWarning, "baz")}, // bar should take precedence on baz
+			want:    FalseCondition(vmopv1.ReadyConditionType, "reason falseInfo1", "message falseInfo1"),
+		},
+		{
+			name:    "Ready condition respects merge order within the same severity",
+			from:    getterWithConditions(foo, bar, baz),
+			options: []MergeOption{WithConditions("foo", "baz", "bar"), WithSeverity(SeverityWarning, "bar", "baz")}, // baz should take precedence on bar
+			want:    FalseCondition(vmopv1.ReadyConditionType, "reason falseInfo2", "message falseInfo2"),
+		},
 		{
 			name: "Ignores existing Ready condition when computing the summary",
 			from: getterWithConditions(existingReady, foo, bar),
@@ -50,7 +50,7 @@ type localizedCondition struct {
 // for summarizing many Reason/Message into single Reason/Message.
 // mergeOptions allows the user to adapt this process to the specific needs by exposing a set of merge strategies.
 func merge(conditions []localizedCondition, targetCondition string, options *mergeOptions) *metav1.Condition {
-	g := getConditionGroups(conditions)
+	g := getConditionGroups(conditions, options.severities)
 	if len(g) == 0 {
 		return nil
 	}
@@ -69,7 +69,10 @@ func merge(conditions []localizedCondition, targetCondition string, options *mer
 
 // getConditionGroups groups a list of conditions according to status, severity values.
 // Additionally, the resulting groups are sorted by mergePriority.
-func getConditionGroups(conditions []localizedCondition) conditionGroups {
+//
+// The severity only applies to conditions with Status=False; the severity of a condition type
+// that is not in severities is SeverityError.
+func getConditionGroups(conditions []localizedCondition, severities map[string]Severity) conditionGroups {
 	groups := conditionGroups{}
 
 	for _, condition := range conditions {
@@ -77,9 +80,17 @@ func getConditionGroups(conditions []localizedCondition) conditionGroups {
 			continue
 		}
 
+		severity := SeverityNone
+		if condition.Status == metav1.ConditionFalse {
+			severity = SeverityError
+			if s, ok := severities[condition.Type]; ok {
+				severity = s
+			}
+		}
+
 		added := false
 		for i := range groups {
-			if groups[i].status == condition.Status {
+			if groups[i].status == condition.Status && groups[i].severity == severity {
 				groups[i].conditions = append(groups[i].conditions, condition)
 				added = true
 				break
@@ -89,6 +100,7 @@ func getConditionGroups(conditions []localizedCondition) conditionGroups {
 			groups = append(groups, conditionGroup{
 				conditions: []localizedCondition{condition},
 				status:     condition.Status,
+				severity:   severity,
 			})
 		}
 	}
@@ -142,7 +154,7 @@ func (g conditionGroups) TrueGroup() *conditionGroup {
 	return g.getByStatusAndSeverity(metav1.ConditionTrue)
 }
 
-// FalseGroup returns the condition group with status False, if any.
+// FalseGroup returns the condition group with status False and the highest severity, if any.
 func (g conditionGroups) FalseGroup() *conditionGroup {
 	return g.getByStatusAndSeverity(metav1.ConditionFalse)
 }
@@ -159,10 +171,11 @@ func (g conditionGroups) getByStatusAndSeverity(status metav1.ConditionStatus) *
 	return nil
 }
 
-// conditionGroup define a group of conditions with the same status,
+// conditionGroup define a group of conditions with the same status and severity,
 // and thus with the same priority when merging into a Ready condition.
 type conditionGroup struct {
 	status     metav1.ConditionStatus
+	severity   Severity
 	conditions []localizedCondition
 }
 
@@ -171,7 +184,14 @@ type conditionGroup struct {
 func (g conditionGroup) mergePriority() int {
 	switch g.status {
 	case metav1.ConditionFalse:
-		return 2
+		switch g.severity {
+		case SeverityWarning:
+			return 1
+		case SeverityInfo:
+			return 2
+		default:
+			return 0
+		}
 	case metav1.ConditionTrue:
 		return 3
 	case metav1.ConditionUnknown:
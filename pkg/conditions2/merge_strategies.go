@@ -25,6 +25,7 @@ import (
 // and more specifically for computing the target Reason and the target Message.
 type mergeOptions struct {
 	conditionTypes                     []string
+	severities                         map[string]Severity
 	addSourceRef                       bool
 	addStepCounter                     bool
 	addStepCounterIfOnlyConditionTypes []string
@@ -48,6 +49,39 @@ func WithConditions(t ...string) MergeOption {
 	}
 }
 
+// Severity expresses the severity of a condition with Status=False.
+type Severity string
+
+const (
+	// SeverityError specifies that a condition with Status=False is an error.
+	SeverityError Severity = "Error"
+
+	// SeverityWarning specifies that a condition with Status=False is a warning.
+	SeverityWarning Severity = "Warning"
+
+	// SeverityInfo specifies that a condition with Status=False is informative.
+	SeverityInfo Severity = "Info"
+
+	// SeverityNone should apply only to conditions with Status=True or Status=Unknown.
+	SeverityNone Severity = ""
+)
+
+// WithSeverity instructs merge about the severity of the given condition types when their status is
+// False; this option may be specified multiple times. Conditions with a higher severity take priority
+// when determining the Reason and Message for the target condition, with SeverityError being the
+// highest and SeverityInfo the lowest. The severity of a condition type not specified with this option
+// is SeverityError.
+func WithSeverity(severity Severity, t ...string) MergeOption {
+	return func(c *mergeOptions) {
+		if c.severities == nil {
+			c.severities = map[string]Severity{}
+		}
+		for _, conditionType := range t {
+			c.severities[conditionType] = severity
+		}
+	}
+}
+
 // WithStepCounter instructs merge to add a "x of y completed" string to the message,
 // where x is the number of conditions with Status=true and y is the number of conditions in scope.
 func WithStepCounter() MergeOption {
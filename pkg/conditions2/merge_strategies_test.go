@@ -35,7 +35,7 @@ func TestGetStepCounterMessage(t *testing.T) {
 		falseWarning1, falseWarning1,
 		falseError1,
 		unknown1,
-	))
+	), nil)
 
 	got := getStepCounterMessage(groups, 8)
 
@@ -79,7 +79,7 @@ func TestGetFirstReasonAndMessage(t *testing.T) {
 		},
 	}
 
-	groups := getConditionGroups(conditionsWithSource(getter, foo, bar))
+	groups := getConditionGroups(conditionsWithSource(getter, foo, bar), nil)
 
 	// getFirst should report first condition in lexicografical order if no order is specified
 	gotReason := getFirstReason(groups, nil, false)
@@ -31,7 +31,7 @@ func TestNewConditionsGroup(t *testing.T) {
 
 	conditions := []*metav1.Condition{nil1, true1, true1, falseInfo1, falseWarning1, falseWarning1, falseError1, unknown1}
 
-	got := getConditionGroups(conditionsWithSource(&vmopv1.VirtualMachine{}, conditions...))
+	got := getConditionGroups(conditionsWithSource(&vmopv1.VirtualMachine{}, conditions...), nil)
 
 	g.Expect(got).ToNot(BeNil())
 	g.Expect(got).To(HaveLen(3))
@@ -63,6 +63,50 @@ func TestNewConditionsGroup(t *testing.T) {
 	// nil conditions are ignored
 }
 
+func TestNewConditionsGroupWithSeverities(t *testing.T) {
+	g := NewWithT(t)
+
+	conditions := []*metav1.Condition{nil1, true1, true1, falseInfo1, falseWarning1, falseWarning1, falseError1, unknown1}
+	severities := map[string]Severity{
+		"falseInfo1":    SeverityInfo,
+		"falseWarning1": SeverityWarning,
+		"true1":         SeverityError, // ignored since the condition is not false
+	}
+
+	got := getConditionGroups(conditionsWithSource(&vmopv1.VirtualMachine{}, conditions...), severities)
+
+	g.Expect(got).ToNot(BeNil())
+	g.Expect(got).To(HaveLen(5))
+
+	// got[0] should be False/Error and it should have one condition, since a condition type without
+	// a severity defaults to Error
+	g.Expect(got[0].status).To(Equal(metav1.ConditionFalse))
+	g.Expect(got[0].severity).To(Equal(SeverityError))
+	g.Expect(got[0].conditions).To(HaveLen(1))
+
+	// got[1] should be False/Warning and it should have two conditions
+	g.Expect(got[1].status).To(Equal(metav1.ConditionFalse))
+	g.Expect(got[1].severity).To(Equal(SeverityWarning))
+	g.Expect(got[1].conditions).To(HaveLen(2))
+
+	// got[2] should be False/Info and it should have one condition
+	g.Expect(got[2].status).To(Equal(metav1.ConditionFalse))
+	g.Expect(got[2].severity).To(Equal(SeverityInfo))
+	g.Expect(got[2].conditions).To(HaveLen(1))
+
+	// got[3] should be True and it should have two conditions
+	g.Expect(got[3].status).To(Equal(metav1.ConditionTrue))
+	g.Expect(got[3].severity).To(Equal(SeverityNone))
+	g.Expect(got[3].conditions).To(HaveLen(2))
+
+	// got[4] should be Unknown and it should have one condition
+	g.Expect(got[4].status).To(Equal(metav1.ConditionUnknown))
+	g.Expect(got[4].conditions).To(HaveLen(1))
+
+	// The false group is the one with the highest severity
+	g.Expect(got.FalseGroup().severity).To(Equal(SeverityError))
+}
+
 func TestMergeRespectPriority(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -6,6 +6,7 @@ package context
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -31,6 +32,9 @@ type VolumeContextA2 struct {
 	Logger                    logr.Logger
 	VM                        *vmopv1.VirtualMachine
 	InstanceStorageFSSEnabled bool
+
+	// VolumeAttachRequeueAfter is the time until the earliest pending volume attachment times out.
+	VolumeAttachRequeueAfter time.Duration
 }
 
 func (v *VolumeContextA2) String() string {
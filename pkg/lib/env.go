@@ -37,6 +37,13 @@ const (
 	// to certain restricted annotations on a VirtualMachine resource.
 	PrivilegedUsersEnv = "PRIVILEGED_USERS"
 
+	// LabelTagCategoriesEnv is the key for the environment variable
+	// containing the comma separated labels of managed VMs to sync to their
+	// vSphere VMs as tags. Each entry is a label key, optionally followed by
+	// "=" and the name of the tag category to use for the label, for example
+	// "backup-policy,dr-tier=DisasterRecoveryTier".
+	LabelTagCategoriesEnv = "LABEL_TAG_CATEGORIES"
+
 	InstanceStoragePVPlacementFailedTTLEnv = "INSTANCE_STORAGE_PV_PLACEMENT_FAILED_TTL"
 	// DefaultInstanceStoragePVPlacementFailedTTL is the default wait time before declaring PV placement failed
 	// after error annotation is set on PVC.
@@ -57,6 +64,77 @@ const (
 	// DefaultInstanceStorageSeedRequeueDuration is the default seed requeue duration for instance storage.
 	DefaultInstanceStorageSeedRequeueDuration = 10 * time.Second
 
+	// TransientFaultRetryStepsEnv is the env variable for setting the number of times an operation that
+	// fails with a transient vSphere fault is attempted.
+	TransientFaultRetryStepsEnv = "TRANSIENT_FAULT_RETRY_STEPS"
+	// DefaultTransientFaultRetrySteps is the default number of times an operation that fails with a
+	// transient vSphere fault is attempted.
+	DefaultTransientFaultRetrySteps = 3
+	// TransientFaultRetryDelayEnv is the env variable for setting the initial delay before an operation
+	// that failed with a transient vSphere fault is retried. The delay doubles after each attempt.
+	TransientFaultRetryDelayEnv = "TRANSIENT_FAULT_RETRY_DELAY"
+	// DefaultTransientFaultRetryDelay is the default initial delay before an operation that failed with
+	// a transient vSphere fault is retried.
+	DefaultTransientFaultRetryDelay = 500 * time.Millisecond
+
+	// VMDeletePowerOffTimeoutEnv is the env variable for setting how long deleting a VM waits for the
+	// guest to shut down before falling back to a hard power off.
+	VMDeletePowerOffTimeoutEnv = "VM_DELETE_POWER_OFF_TIMEOUT"
+	// DefaultVMDeletePowerOffTimeout is the default time deleting a VM waits for the guest to shut down.
+	DefaultVMDeletePowerOffTimeout = 5 * time.Minute
+
+	// VMFullReconcileIntervalEnv is the env variable for setting how often, in resyncs, an
+	// already converged VM is fully reconciled instead of only having its power state checked.
+	VMFullReconcileIntervalEnv = "VM_FULL_RECONCILE_INTERVAL"
+	// DefaultVMFullReconcileInterval is the default number of resyncs between the full reconciles
+	// of an already converged VM.
+	DefaultVMFullReconcileInterval = 10
+
+	// VMTaskProgressIntervalEnv is the env variable for setting how often the progress of the vSphere
+	// task that clones a VM is written to the VM's status.
+	VMTaskProgressIntervalEnv = "VM_TASK_PROGRESS_INTERVAL"
+	// DefaultVMTaskProgressInterval is the default interval between the updates of the progress of
+	// the vSphere task that clones a VM.
+	DefaultVMTaskProgressInterval = 10 * time.Second
+
+	// VolumeAttachTimeoutEnv is the env variable for setting how long a VM's PersistentVolumeClaim
+	// volume may take to be attached before the VM's VolumesAttached condition reports it.
+	VolumeAttachTimeoutEnv = "VOLUME_ATTACH_TIMEOUT"
+	// DefaultVolumeAttachTimeout is the default time a volume may take to be attached.
+	DefaultVolumeAttachTimeout = 5 * time.Minute
+
+	// OrphanedVMCheckIntervalEnv is the env variable for setting how often the vSphere VMs created by
+	// VM Operator are checked for VMs that no longer have a VirtualMachine CR.
+	OrphanedVMCheckIntervalEnv = "ORPHANED_VM_CHECK_INTERVAL"
+	// DefaultOrphanedVMCheckInterval is the default interval between the checks for orphaned VMs.
+	DefaultOrphanedVMCheckInterval = 5 * time.Minute
+	// OrphanedVMDeletionEnv is the env variable that, when true, has the orphaned VMs deleted instead
+	// of only reported.
+	OrphanedVMDeletionEnv = "ORPHANED_VM_DELETION"
+
+	// DefaultRetainedNetworkInterfaceCollectInterval is the longest interval between the deletions
+	// of the retained network provider CRs of the deleted VMs whose grace period expired.
+	DefaultRetainedNetworkInterfaceCollectInterval = 5 * time.Minute
+
+	// MaxDeletionGracePeriodEnv is the env variable for setting the longest deletion grace period a
+	// deleted VM's vSphere VM may be kept for before it is destroyed by the orphaned VM collector.
+	// The collector always destroys the VMs whose grace period expired, even when the deletion of
+	// the other orphaned VMs is disabled.
+	// The deletion grace period annotation is ignored when unset.
+	MaxDeletionGracePeriodEnv = "MAX_DELETION_GRACE_PERIOD"
+
+	// MaxNetworkInterfacesPerVMEnv is the env variable for setting the maximum number of network
+	// interfaces a VM may have.
+	MaxNetworkInterfacesPerVMEnv = "MAX_NETWORK_INTERFACES_PER_VM"
+	// DefaultMaxNetworkInterfacesPerVM is the maximum number of NICs vSphere supports for a VM.
+	DefaultMaxNetworkInterfacesPerVM = 10
+
+	// NetworkInterfaceDeleteGracePeriodEnv is the env variable for setting how long the network
+	// provider CRs of a deleted VM are retained so they are reused if the VM is recreated with the
+	// same name and the reuse-network-interfaces-of annotation, ex. during a rolling update. The
+	// expired CRs are deleted every grace period. The CRs are deleted with the VM when unset.
+	NetworkInterfaceDeleteGracePeriodEnv = "NETWORK_INTERFACE_DELETE_GRACE_PERIOD"
+
 	// NetworkProviderType is the cluster network provider type. Valid values
 	// include: NAMED, NSXT, VSPHERE_NETWORK. Please note that NAMED is only
 	// used for testing and is not supported in production environments.
@@ -65,6 +143,10 @@ const (
 	NetworkProviderTypeNSXT  = "NSXT"
 	NetworkProviderTypeVDS   = "VSPHERE_NETWORK"
 
+	// NamedNetworkCrossDatacenterLookupEnv is the env variable that, when true, has the NAMED network
+	// provider search the other datacenters for a network that is not found in the VM's datacenter.
+	NamedNetworkCrossDatacenterLookupEnv = "NAMED_NETWORK_CROSS_DATACENTER_LOOKUP"
+
 	// DefaultVirtualMachineClassControllerNameEnv is the name of the
 	// environment variable that contains the name of the default value for
 	// the VirtualMachineClass field spec.controllerName.
@@ -100,6 +182,12 @@ func GetNetworkProviderType() string {
 	return os.Getenv(NetworkProviderType)
 }
 
+// IsNamedNetworkCrossDatacenterLookupEnabled returns true if the NAMED network provider may look up
+// networks in datacenters other than the VM's.
+func IsNamedNetworkCrossDatacenterLookupEnabled() bool {
+	return os.Getenv(NamedNetworkCrossDatacenterLookupEnv) == TrueString
+}
+
 var IsWcpFaultDomainsFSSEnabled = func() bool {
 	return os.Getenv(WcpFaultDomainsFSS) == TrueString
 }
@@ -156,6 +244,29 @@ func GetPrivilegedUsers() map[string]struct{} {
 	return privilegedUsers
 }
 
+// GetLabelTagCategories returns the mapping of label keys to the names of
+// the tag categories that the labels are synced to, specified via the
+// environment variable "LABEL_TAG_CATEGORIES". A label without an explicit
+// category is synced to the category named for the label's key.
+func GetLabelTagCategories() map[string]string {
+	labelTagCategories := make(map[string]string)
+
+	parts := strings.Split(strings.TrimSpace(os.Getenv(LabelTagCategoriesEnv)), ",")
+	for _, part := range parts {
+		key, category, _ := strings.Cut(part, "=")
+		key, category = strings.TrimSpace(key), strings.TrimSpace(category)
+		if len(key) == 0 {
+			continue
+		}
+		if len(category) == 0 {
+			category = key
+		}
+		labelTagCategories[key] = category
+	}
+
+	return labelTagCategories
+}
+
 // MaxConcurrentCreateVMsOnProvider returns the percentage of reconciler
 // threads that can be used to create VMs on the provider concurrently. The
 // default is 80.
@@ -185,6 +296,131 @@ func GetInstanceStoragePVPlacementFailedTTL() time.Duration {
 	return DefaultInstanceStoragePVPlacementFailedTTL
 }
 
+// GetTransientFaultRetrySteps returns the configured number of times an operation that fails with a
+// transient vSphere fault is attempted.
+func GetTransientFaultRetrySteps() int {
+	if s := os.Getenv(TransientFaultRetryStepsEnv); len(s) > 0 {
+		if steps, err := strconv.Atoi(s); err == nil && steps > 0 {
+			return steps
+		}
+	}
+	return DefaultTransientFaultRetrySteps
+}
+
+// GetTransientFaultRetryDelay returns the configured initial delay before an operation that failed
+// with a transient vSphere fault is retried.
+func GetTransientFaultRetryDelay() time.Duration {
+	if s := os.Getenv(TransientFaultRetryDelayEnv); len(s) > 0 {
+		if duration, err := time.ParseDuration(s); err == nil {
+			return duration
+		}
+	}
+	return DefaultTransientFaultRetryDelay
+}
+
+// GetVMDeletePowerOffTimeout returns the configured time deleting a VM waits for the guest to shut
+// down before falling back to a hard power off.
+func GetVMDeletePowerOffTimeout() time.Duration {
+	if timeout := os.Getenv(VMDeletePowerOffTimeoutEnv); len(timeout) > 0 {
+		if duration, err := time.ParseDuration(timeout); err == nil {
+			return duration
+		}
+	}
+	return DefaultVMDeletePowerOffTimeout
+}
+
+// GetVolumeAttachTimeout returns the configured time a VM's PersistentVolumeClaim volume may take
+// to be attached before it is reported as not attached.
+func GetVolumeAttachTimeout() time.Duration {
+	if timeout := os.Getenv(VolumeAttachTimeoutEnv); len(timeout) > 0 {
+		if duration, err := time.ParseDuration(timeout); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return DefaultVolumeAttachTimeout
+}
+
+// GetVMFullReconcileInterval returns the configured number of resyncs between the full reconciles
+// of an already converged VM. A value of 1 fully reconciles the VM on every resync.
+func GetVMFullReconcileInterval() int {
+	if s := os.Getenv(VMFullReconcileIntervalEnv); len(s) > 0 {
+		if interval, err := strconv.Atoi(s); err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return DefaultVMFullReconcileInterval
+}
+
+// GetVMTaskProgressInterval returns the configured interval between the updates of the progress
+// of the vSphere task that clones a VM.
+func GetVMTaskProgressInterval() time.Duration {
+	if s := os.Getenv(VMTaskProgressIntervalEnv); len(s) > 0 {
+		if duration, err := time.ParseDuration(s); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return DefaultVMTaskProgressInterval
+}
+
+// GetMaxNetworkInterfacesPerVM returns the configured maximum number of network interfaces a VM
+// may have. The configured value cannot exceed the number of NICs vSphere supports for a VM.
+func GetMaxNetworkInterfacesPerVM() int {
+	if s := os.Getenv(MaxNetworkInterfacesPerVMEnv); len(s) > 0 {
+		if max, err := strconv.Atoi(s); err == nil && max > 0 && max < DefaultMaxNetworkInterfacesPerVM {
+			return max
+		}
+	}
+	return DefaultMaxNetworkInterfacesPerVM
+}
+
+// GetNetworkInterfaceDeleteGracePeriod returns the configured time the network provider CRs of a
+// deleted VM are retained, or zero if they are deleted with the VM.
+func GetNetworkInterfaceDeleteGracePeriod() time.Duration {
+	if s := os.Getenv(NetworkInterfaceDeleteGracePeriodEnv); len(s) > 0 {
+		if duration, err := time.ParseDuration(s); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return 0
+}
+
+// GetRetainedNetworkInterfaceCollectInterval returns the interval between the deletions of the
+// expired retained network provider CRs, which is the grace period when that is shorter than the
+// default interval. The CRs are deleted even when the grace period is no longer set, so the CRs
+// that were retained before it was unset do not leak.
+func GetRetainedNetworkInterfaceCollectInterval() time.Duration {
+	if gracePeriod := GetNetworkInterfaceDeleteGracePeriod(); gracePeriod > 0 && gracePeriod < DefaultRetainedNetworkInterfaceCollectInterval {
+		return gracePeriod
+	}
+	return DefaultRetainedNetworkInterfaceCollectInterval
+}
+
+// GetOrphanedVMCheckInterval returns the configured interval between the checks for orphaned VMs.
+func GetOrphanedVMCheckInterval() time.Duration {
+	if s := os.Getenv(OrphanedVMCheckIntervalEnv); len(s) > 0 {
+		if duration, err := time.ParseDuration(s); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return DefaultOrphanedVMCheckInterval
+}
+
+// IsOrphanedVMDeletionEnabled returns true if the orphaned VMs are deleted instead of only reported.
+func IsOrphanedVMDeletionEnabled() bool {
+	return os.Getenv(OrphanedVMDeletionEnv) == TrueString
+}
+
+// GetMaxDeletionGracePeriod returns the configured longest deletion grace period of a deleted VM,
+// or zero if deletion grace periods are disabled.
+func GetMaxDeletionGracePeriod() time.Duration {
+	if s := os.Getenv(MaxDeletionGracePeriodEnv); len(s) > 0 {
+		if duration, err := time.ParseDuration(s); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return 0
+}
+
 // GetInstanceStorageRequeueDelay returns requeue delay for instance storage.
 func GetInstanceStorageRequeueDelay() time.Duration {
 	maxFactor := DefaultInstanceStorageJitterMaxFactor
@@ -6,6 +6,7 @@ package lib
 import (
 	"os"
 	"strconv"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -42,3 +43,41 @@ var _ = Describe("MaxAllowedConcurrentVMsOnProvider", func() {
 		})
 	})
 })
+
+var _ = Describe("GetLabelTagCategories", func() {
+	AfterEach(func() {
+		Expect(os.Unsetenv(LabelTagCategoriesEnv)).To(Succeed())
+	})
+
+	It("returns an empty mapping when the env is not set", func() {
+		Expect(GetLabelTagCategories()).To(BeEmpty())
+	})
+
+	It("returns the category of each label", func() {
+		Expect(os.Setenv(LabelTagCategoriesEnv, " backup-policy, dr-tier = DisasterRecoveryTier,,owner=")).To(Succeed())
+
+		Expect(GetLabelTagCategories()).To(Equal(map[string]string{
+			"backup-policy": "backup-policy",
+			"dr-tier":       "DisasterRecoveryTier",
+			"owner":         "owner",
+		}))
+	})
+})
+
+var _ = Describe("GetRetainedNetworkInterfaceCollectInterval", func() {
+	AfterEach(func() {
+		Expect(os.Unsetenv(NetworkInterfaceDeleteGracePeriodEnv)).To(Succeed())
+	})
+
+	It("returns the default interval when the grace period is not set", func() {
+		Expect(GetRetainedNetworkInterfaceCollectInterval()).To(Equal(DefaultRetainedNetworkInterfaceCollectInterval))
+	})
+
+	It("returns the grace period when it is shorter than the default interval", func() {
+		Expect(os.Setenv(NetworkInterfaceDeleteGracePeriodEnv, "1m")).To(Succeed())
+		Expect(GetRetainedNetworkInterfaceCollectInterval()).To(Equal(time.Minute))
+
+		Expect(os.Setenv(NetworkInterfaceDeleteGracePeriodEnv, "24h")).To(Succeed())
+		Expect(GetRetainedNetworkInterfaceCollectInterval()).To(Equal(DefaultRetainedNetworkInterfaceCollectInterval))
+	})
+})
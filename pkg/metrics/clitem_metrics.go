@@ -5,6 +5,7 @@ package metrics
 
 import (
 	"sync"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -17,9 +18,34 @@ var (
 	clItemMetrics     *ContentLibraryItemMetrics
 )
 
+// ContentLibraryItemSyncErrorClass is the step of syncing a content library item to its image
+// that failed. It is used as a label instead of the error itself so that the number of label
+// values is bounded.
+type ContentLibraryItemSyncErrorClass string
+
+const (
+	ContentLibraryItemSyncErrorResolveImage ContentLibraryItemSyncErrorClass = "resolve_image"
+	ContentLibraryItemSyncErrorSyncContent  ContentLibraryItemSyncErrorClass = "sync_content"
+)
+
 type ContentLibraryItemMetrics struct {
 	vmiResourceResolve *prometheus.GaugeVec
 	vmiContentSync     *prometheus.GaugeVec
+
+	// The content library metrics are labeled by library and never by item, so their cardinality
+	// is bounded by the number of content libraries.
+	libraryItemSyncDuration *prometheus.HistogramVec
+	libraryItemSyncErrors   *prometheus.CounterVec
+	libraryImages           *prometheus.GaugeVec
+
+	// libraryImagesLock protects libraryImageKeys, which is the keys of the items of each content
+	// library that were last synced to their image.
+	libraryImagesLock sync.Mutex
+	libraryImageKeys  map[libraryRef]map[string]struct{}
+}
+
+type libraryRef struct {
+	name, namespace, kind string
 }
 
 // NewContentLibraryItemMetrics initializes a singleton and registers all the defined metrics.
@@ -44,11 +70,47 @@ func NewContentLibraryItemMetrics() *ContentLibraryItemMetrics {
 				vmiNameLabel,
 				vmiNamespaceLabel,
 			}),
+			libraryItemSyncDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentlibrary",
+				Name:      "item_sync_duration_seconds",
+				Help:      "Duration of syncing the items of a content library to their images",
+				Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+			}, []string{
+				libraryNameLabel,
+				libraryNamespaceLabel,
+				libraryKindLabel,
+			}),
+			libraryItemSyncErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentlibrary",
+				Name:      "item_sync_errors_total",
+				Help:      "Number of errors syncing the items of a content library to their images",
+			}, []string{
+				libraryNameLabel,
+				libraryNamespaceLabel,
+				libraryKindLabel,
+				errorClassLabel,
+			}),
+			libraryImages: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentlibrary",
+				Name:      "images",
+				Help:      "Number of items of a content library that were last synced to their images",
+			}, []string{
+				libraryNameLabel,
+				libraryNamespaceLabel,
+				libraryKindLabel,
+			}),
+			libraryImageKeys: map[libraryRef]map[string]struct{}{},
 		}
 
 		metrics.Registry.MustRegister(
 			clItemMetrics.vmiResourceResolve,
 			clItemMetrics.vmiContentSync,
+			clItemMetrics.libraryItemSyncDuration,
+			clItemMetrics.libraryItemSyncErrors,
+			clItemMetrics.libraryImages,
 		)
 	})
 
@@ -83,6 +145,63 @@ func (m *ContentLibraryItemMetrics) RegisterVMIContentSync(logger logr.Logger, v
 	logger.V(5).WithValues("labels", labels).Info("Set metrics for VMImage content sync status")
 }
 
+// RegisterContentLibraryItemSync registers the metrics for a sync of the item with the given key to
+// its image, where the item is of the content library with the given name, namespace, and kind.
+// The namespace is empty for a cluster content library. The errorClass is empty if the sync did
+// not fail, and synced is whether the item's image is ready.
+func (m *ContentLibraryItemMetrics) RegisterContentLibraryItemSync(
+	logger logr.Logger,
+	libraryName, libraryNamespace, libraryKind, itemKey string,
+	duration time.Duration,
+	errorClass ContentLibraryItemSyncErrorClass,
+	synced bool) {
+
+	labels := getLibraryMetricsLabels(libraryName, libraryNamespace, libraryKind)
+	m.libraryItemSyncDuration.With(labels).Observe(duration.Seconds())
+
+	if errorClass != "" {
+		errLabels := getLibraryMetricsLabels(libraryName, libraryNamespace, libraryKind)
+		errLabels[errorClassLabel] = string(errorClass)
+		m.libraryItemSyncErrors.With(errLabels).Inc()
+	}
+
+	m.setLibraryImage(libraryName, libraryNamespace, libraryKind, itemKey, synced)
+
+	logger.V(5).WithValues("labels", labels, "errorClass", errorClass).Info("Set metrics for content library item sync")
+}
+
+// DeleteContentLibraryItemMetrics deletes the item with the given key from the metrics of the
+// content library with the given name, namespace, and kind.
+func (m *ContentLibraryItemMetrics) DeleteContentLibraryItemMetrics(
+	logger logr.Logger,
+	libraryName, libraryNamespace, libraryKind, itemKey string) {
+
+	m.setLibraryImage(libraryName, libraryNamespace, libraryKind, itemKey, false)
+
+	logger.V(5).WithValues("labels", getLibraryMetricsLabels(libraryName, libraryNamespace, libraryKind)).Info("Deleted content library item metrics")
+}
+
+// setLibraryImage adds or removes the item from the images of the content library.
+func (m *ContentLibraryItemMetrics) setLibraryImage(libraryName, libraryNamespace, libraryKind, itemKey string, synced bool) {
+	m.libraryImagesLock.Lock()
+	defer m.libraryImagesLock.Unlock()
+
+	ref := libraryRef{name: libraryName, namespace: libraryNamespace, kind: libraryKind}
+	keys, ok := m.libraryImageKeys[ref]
+	if !ok {
+		keys = map[string]struct{}{}
+		m.libraryImageKeys[ref] = keys
+	}
+
+	if synced {
+		keys[itemKey] = struct{}{}
+	} else {
+		delete(keys, itemKey)
+	}
+
+	m.libraryImages.With(getLibraryMetricsLabels(libraryName, libraryNamespace, libraryKind)).Set(float64(len(keys)))
+}
+
 // DeleteMetrics deletes all the related ContentLibraryItem metrics from the given name and namespace.
 func (m *ContentLibraryItemMetrics) DeleteMetrics(logger logr.Logger, vmiName, ns string) {
 	labels := getVMIMetricsLabels(vmiName, ns)
@@ -92,6 +211,14 @@ func (m *ContentLibraryItemMetrics) DeleteMetrics(logger logr.Logger, vmiName, n
 	logger.V(5).WithValues("labels", labels).Info("Deleted all VMImage related Metrics")
 }
 
+func getLibraryMetricsLabels(name, ns, kind string) prometheus.Labels {
+	return prometheus.Labels{
+		libraryNameLabel:      name,
+		libraryNamespaceLabel: ns,
+		libraryKindLabel:      kind,
+	}
+}
+
 func getVMIMetricsLabels(name, ns string) prometheus.Labels {
 	return prometheus.Labels{
 		vmiNameLabel:      name,
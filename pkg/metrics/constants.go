@@ -22,6 +22,15 @@ const (
 	providerNameLabel = "provider_name"
 	providerKindLabel = "provider_kind"
 
+	// Content library discovery related metrics labels.
+	libraryIDLabel  = "library_id"
+	errorClassLabel = "error_class"
+
+	// Content library item sync related metrics labels.
+	libraryNameLabel      = "library_name"
+	libraryNamespaceLabel = "library_namespace"
+	libraryKindLabel      = "library_kind"
+
 	// VMImage related metrics labels (from image registry service).
 	vmiNameLabel      = "vmi_name"
 	vmiNamespaceLabel = "vmi_namespace"
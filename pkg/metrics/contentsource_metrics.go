@@ -5,6 +5,7 @@ package metrics
 
 import (
 	"sync"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -19,8 +20,27 @@ var (
 	contentSourceMetrics     *ContentSourceMetrics
 )
 
+// ContentLibraryDiscoveryErrorClass is the step of discovering the images in a content library
+// that failed. It is used as a label instead of the error itself so that the number of label
+// values is bounded.
+type ContentLibraryDiscoveryErrorClass string
+
+const (
+	ContentLibraryDiscoveryErrorListImages  ContentLibraryDiscoveryErrorClass = "list_images"
+	ContentLibraryDiscoveryErrorListItems   ContentLibraryDiscoveryErrorClass = "list_items"
+	ContentLibraryDiscoveryErrorProcessItem ContentLibraryDiscoveryErrorClass = "process_item"
+	ContentLibraryDiscoveryErrorDeleteImage ContentLibraryDiscoveryErrorClass = "delete_image"
+)
+
 type ContentSourceMetrics struct {
-	vmImage *prometheus.GaugeVec
+	vmImage          *prometheus.GaugeVec
+	librarySyncStale *prometheus.GaugeVec
+
+	// The content library discovery metrics are labeled by library and never by image, so their
+	// cardinality is bounded by the number of content libraries.
+	librarySyncDuration     *prometheus.HistogramVec
+	libraryDiscoveryErrors  *prometheus.CounterVec
+	libraryImagesDiscovered *prometheus.GaugeVec
 }
 
 // NewContentSourceMetrics initializes a singleton and registers all the defined metrics.
@@ -38,10 +58,52 @@ func NewContentSourceMetrics() *ContentSourceMetrics {
 				providerNameLabel,
 				providerKindLabel,
 			}),
+			librarySyncStale: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentsource",
+				Name:      "library_sync_stale",
+				Help:      "Whether the subscribed content library of a ContentSource has not synced within the staleness threshold",
+			}, []string{
+				providerNameLabel,
+				providerKindLabel,
+			}),
+			librarySyncDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentlibrary",
+				Name:      "sync_duration_seconds",
+				Help:      "Duration of discovering the images in a content library",
+				Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+			}, []string{
+				providerNameLabel,
+				libraryIDLabel,
+			}),
+			libraryDiscoveryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentlibrary",
+				Name:      "discovery_errors_total",
+				Help:      "Number of errors discovering the images in a content library",
+			}, []string{
+				providerNameLabel,
+				libraryIDLabel,
+				errorClassLabel,
+			}),
+			libraryImagesDiscovered: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Subsystem: "contentlibrary",
+				Name:      "images_discovered",
+				Help:      "Number of images discovered in a content library by its last sync",
+			}, []string{
+				providerNameLabel,
+				libraryIDLabel,
+			}),
 		}
 
 		metrics.Registry.MustRegister(
 			contentSourceMetrics.vmImage,
+			contentSourceMetrics.librarySyncStale,
+			contentSourceMetrics.librarySyncDuration,
+			contentSourceMetrics.libraryDiscoveryErrors,
+			contentSourceMetrics.libraryImagesDiscovered,
 		)
 	})
 
@@ -74,6 +136,49 @@ func (csm *ContentSourceMetrics) RegisterVMImageDelete(logger logr.Logger, vmIma
 	}
 }
 
+// RegisterContentLibrarySyncStale registers the metrics for the sync staleness of the content library
+// referenced by the given ContentProviderReference. If stale is true, it sets the value to 1 else to 0.
+func (csm *ContentSourceMetrics) RegisterContentLibrarySyncStale(logger logr.Logger, providerRef vmopv1.ContentProviderReference, stale bool) {
+	logger.V(5).Info("Adding metrics for a content library sync staleness check")
+	labels := prometheus.Labels{
+		providerNameLabel: providerRef.Name,
+		providerKindLabel: providerRef.Kind,
+	}
+	csm.librarySyncStale.With(labels).Set(func() float64 {
+		if stale {
+			return 1
+		}
+		return 0
+	}())
+}
+
+// RegisterContentLibrarySync registers the metrics for a discovery of the images in the content
+// library of the given ContentLibraryProvider.
+func (csm *ContentSourceMetrics) RegisterContentLibrarySync(logger logr.Logger, clProvider vmopv1.ContentLibraryProvider, duration time.Duration) {
+	logger.V(5).Info("Adding metrics for a content library sync", "duration", duration)
+	csm.librarySyncDuration.With(getContentLibraryLabels(clProvider)).Observe(duration.Seconds())
+}
+
+// RegisterContentLibraryDiscoveryError registers the metrics for an error discovering the images in
+// the content library of the given ContentLibraryProvider.
+func (csm *ContentSourceMetrics) RegisterContentLibraryDiscoveryError(
+	logger logr.Logger,
+	clProvider vmopv1.ContentLibraryProvider,
+	errorClass ContentLibraryDiscoveryErrorClass) {
+
+	logger.V(5).Info("Adding metrics for a content library discovery error", "errorClass", errorClass)
+	labels := getContentLibraryLabels(clProvider)
+	labels[errorClassLabel] = string(errorClass)
+	csm.libraryDiscoveryErrors.With(labels).Inc()
+}
+
+// RegisterContentLibraryImagesDiscovered registers the metrics for the number of images discovered
+// in the content library of the given ContentLibraryProvider.
+func (csm *ContentSourceMetrics) RegisterContentLibraryImagesDiscovered(logger logr.Logger, clProvider vmopv1.ContentLibraryProvider, count int) {
+	logger.V(5).Info("Adding metrics for the images discovered in a content library", "count", count)
+	csm.libraryImagesDiscovered.With(getContentLibraryLabels(clProvider)).Set(float64(count))
+}
+
 // DeleteMetrics deletes the related metrics from the given ContentProviderReference.
 func (csm *ContentSourceMetrics) DeleteMetrics(logger logr.Logger, providerRef vmopv1.ContentProviderReference) {
 	logger.V(5).Info("Deleting all VMImage metrics from the given provider name and kind")
@@ -82,6 +187,13 @@ func (csm *ContentSourceMetrics) DeleteMetrics(logger logr.Logger, providerRef v
 		providerKindLabel: providerRef.Kind,
 	}
 	csm.vmImage.DeletePartialMatch(labels)
+	csm.librarySyncStale.Delete(labels)
+
+	// The content library discovery metrics are not labeled with the provider kind.
+	clLabels := prometheus.Labels{providerNameLabel: providerRef.Name}
+	csm.librarySyncDuration.DeletePartialMatch(clLabels)
+	csm.libraryDiscoveryErrors.DeletePartialMatch(clLabels)
+	csm.libraryImagesDiscovered.DeletePartialMatch(clLabels)
 }
 
 // getVMImageLabels is a helper function to return all the required labels for the given VMImage.
@@ -94,3 +206,12 @@ func getVMImageLabels(vmImage vmopv1.VirtualMachineImage) prometheus.Labels {
 		providerKindLabel: vmImage.Spec.ProviderRef.Kind,
 	}
 }
+
+// getContentLibraryLabels is a helper function to return the labels of the content library
+// discovery metrics for the given ContentLibraryProvider.
+func getContentLibraryLabels(clProvider vmopv1.ContentLibraryProvider) prometheus.Labels {
+	return prometheus.Labels{
+		providerNameLabel: clProvider.Name,
+		libraryIDLabel:    clProvider.Spec.UUID,
+	}
+}
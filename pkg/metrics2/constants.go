@@ -14,8 +14,17 @@ const (
 	conditionReasonLabel = "condition_reason"
 	specLabel            = "spec"
 	statusLabel          = "status"
+	reconcileTypeLabel   = "reconcile_type"
+	networkProviderLabel = "network_provider"
+	vmMoIDLabel          = "vm_moid"
 
 	// VMImage related metrics labels (from image registry service).
 	vmiNameLabel      = "vmi_name"
 	vmiNamespaceLabel = "vmi_namespace"
+
+	// Content library item sync related metrics labels.
+	libraryNameLabel      = "library_name"
+	libraryNamespaceLabel = "library_namespace"
+	libraryKindLabel      = "library_kind"
+	errorClassLabel       = "error_class"
 )
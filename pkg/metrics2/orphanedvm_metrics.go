@@ -0,0 +1,77 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics2
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	orphanedVMMetricsOnce sync.Once
+	orphanedVMMetrics     *OrphanedVMMetrics
+)
+
+type OrphanedVMMetrics struct {
+	orphanedVMs *prometheus.GaugeVec
+	deletedVMs  prometheus.Counter
+}
+
+// NewOrphanedVMMetrics initializes a singleton and registers all the defined metrics.
+func NewOrphanedVMMetrics() *OrphanedVMMetrics {
+	orphanedVMMetricsOnce.Do(func() {
+		orphanedVMMetrics = &OrphanedVMMetrics{
+			orphanedVMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "orphaned_vm",
+				Help:      "vSphere VM created by VM Operator that does not have a VirtualMachine CR",
+			}, []string{
+				vmMoIDLabel,
+				vmNameLabel,
+				vmNamespaceLabel,
+			}),
+			deletedVMs: prometheus.NewCounter(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "orphaned_vm_deleted_total",
+				Help:      "Total number of orphaned vSphere VMs deleted",
+			}),
+		}
+
+		metrics.Registry.MustRegister(
+			orphanedVMMetrics.orphanedVMs,
+			orphanedVMMetrics.deletedVMs,
+		)
+	})
+
+	return orphanedVMMetrics
+}
+
+// OrphanedVM is a vSphere VM that does not have a VirtualMachine CR.
+type OrphanedVM struct {
+	MoID string
+	// Name and Namespace are of the VirtualMachine CR the vSphere VM was created for.
+	Name      string
+	Namespace string
+}
+
+// SetOrphanedVMs sets the orphaned VMs metrics to the orphaned VMs, replacing the VMs that were
+// previously set.
+func (m *OrphanedVMMetrics) SetOrphanedVMs(vms []OrphanedVM) {
+	m.orphanedVMs.Reset()
+	for _, vm := range vms {
+		m.orphanedVMs.With(prometheus.Labels{
+			vmMoIDLabel:      vm.MoID,
+			vmNameLabel:      vm.Name,
+			vmNamespaceLabel: vm.Namespace,
+		}).Set(1)
+	}
+}
+
+// RegisterOrphanedVMDeleted increments the number of orphaned VMs that have been deleted.
+func (m *OrphanedVMMetrics) RegisterOrphanedVMDeleted() {
+	m.deletedVMs.Inc()
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics2
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	vmNetworkMetricsOnce sync.Once
+	vmNetworkMetrics     *VMNetworkMetrics
+)
+
+type VMNetworkMetrics struct {
+	interfaceReadyWait *prometheus.HistogramVec
+}
+
+// NewVMNetworkMetrics initializes a singleton and registers all the defined metrics.
+func NewVMNetworkMetrics() *VMNetworkMetrics {
+	vmNetworkMetricsOnce.Do(func() {
+		vmNetworkMetrics = &VMNetworkMetrics{
+			interfaceReadyWait: prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Namespace: metricsNamespace,
+					Name:      "vm_network_interface_ready_wait_seconds",
+					Help:      "Time the provider waited for a VM network interface CR to be reconciled by the network provider",
+					Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15},
+				},
+				[]string{networkProviderLabel},
+			),
+		}
+
+		metrics.Registry.MustRegister(
+			vmNetworkMetrics.interfaceReadyWait,
+		)
+	})
+
+	return vmNetworkMetrics
+}
+
+// RegisterInterfaceReadyWait records how long the provider waited for a network interface CR
+// of the network provider type to be ready.
+func (m *VMNetworkMetrics) RegisterInterfaceReadyWait(providerType string, wait time.Duration) {
+	m.interfaceReadyWait.With(prometheus.Labels{networkProviderLabel: providerType}).Observe(wait.Seconds())
+}
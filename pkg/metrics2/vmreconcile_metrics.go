@@ -0,0 +1,57 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics2
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ReconcileType string
+
+const (
+	// ReconcileFastPath is a reconcile of an already converged VM that only checked the VM's
+	// power state.
+	ReconcileFastPath ReconcileType = "fast_path"
+	// ReconcileFull is a reconcile that compared the VM against its spec.
+	ReconcileFull ReconcileType = "full"
+)
+
+var (
+	vmReconcileMetricsOnce sync.Once
+	vmReconcileMetrics     *VMReconcileMetrics
+)
+
+type VMReconcileMetrics struct {
+	reconcileTotal *prometheus.CounterVec
+}
+
+// NewVMReconcileMetrics initializes a singleton and registers all the defined metrics.
+func NewVMReconcileMetrics() *VMReconcileMetrics {
+	vmReconcileMetricsOnce.Do(func() {
+		vmReconcileMetrics = &VMReconcileMetrics{
+			reconcileTotal: prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace: metricsNamespace,
+					Name:      "vm_reconcile_total",
+					Help:      "Number of VM updates reconciled by the provider, by whether the fast-path was taken"},
+				[]string{reconcileTypeLabel},
+			),
+		}
+
+		metrics.Registry.MustRegister(
+			vmReconcileMetrics.reconcileTotal,
+		)
+	})
+
+	return vmReconcileMetrics
+}
+
+// RegisterReconcile counts a VM update reconciled by the provider.
+func (m *VMReconcileMetrics) RegisterReconcile(reconcileType ReconcileType) {
+	m.reconcileTotal.With(prometheus.Labels{reconcileTypeLabel: string(reconcileType)}).Inc()
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/vmware/govmomi/vim25"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
@@ -205,13 +206,19 @@ func ExtraConfigToMap(input []vimTypes.BaseOptionValue) (output map[string]strin
 }
 
 // MergeExtraConfig adds the key/value to the ExtraConfig if the key is not present.
-// It returns the newly added ExtraConfig.
+// It returns the newly added ExtraConfig, sorted by key so the result is stable.
 func MergeExtraConfig(extraConfig []vimTypes.BaseOptionValue, newMap map[string]string) []vimTypes.BaseOptionValue {
+	keys := make([]string, 0, len(newMap))
+	for k := range newMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	merged := make([]vimTypes.BaseOptionValue, 0)
 	ecMap := ExtraConfigToMap(extraConfig)
-	for k, v := range newMap {
+	for _, k := range keys {
 		if _, exists := ecMap[k]; !exists {
-			merged = append(merged, &vimTypes.OptionValue{Key: k, Value: v})
+			merged = append(merged, &vimTypes.OptionValue{Key: k, Value: newMap[k]})
 		}
 	}
 	return merged
@@ -233,3 +240,98 @@ func EnsureMinHardwareVersionInConfigSpec(configSpec *vimTypes.VirtualMachineCon
 	}
 	configSpec.Version = fmt.Sprintf("vmx-%d", configSpecHwVersion)
 }
+
+// MergeConfigSpecs returns a new ConfigSpec that is the result of merging the VM,
+// VM class, and image ConfigSpecs, any of which may be nil. Only the fields
+// below are merged, and the precedence is VM > class > image:
+//   - NumCPUs, MemoryMB, CpuAllocation, and MemoryAllocation are taken from the
+//     highest precedence ConfigSpec that sets them.
+//   - ExtraConfig is the union of all the ExtraConfig, where a key from a higher
+//     precedence ConfigSpec overrides the same key from a lower one.
+//   - DeviceChange is the union of all the DeviceChange, where a device with a
+//     non-zero key from a higher precedence ConfigSpec overrides the device with
+//     the same key from a lower one. Devices without a key are always included.
+func MergeConfigSpecs(
+	vmConfigSpec, classConfigSpec, imageConfigSpec *vimTypes.VirtualMachineConfigSpec) *vimTypes.VirtualMachineConfigSpec {
+
+	merged := &vimTypes.VirtualMachineConfigSpec{}
+
+	// Ordered from the highest to the lowest precedence.
+	var configSpecs []*vimTypes.VirtualMachineConfigSpec
+	for _, cs := range []*vimTypes.VirtualMachineConfigSpec{vmConfigSpec, classConfigSpec, imageConfigSpec} {
+		if cs != nil {
+			configSpecs = append(configSpecs, cs)
+		}
+	}
+
+	ecKeys := map[string]struct{}{}
+	deviceKeys := map[int32]struct{}{}
+
+	for _, cs := range configSpecs {
+		if merged.NumCPUs == 0 {
+			merged.NumCPUs = cs.NumCPUs
+		}
+		if merged.MemoryMB == 0 {
+			merged.MemoryMB = cs.MemoryMB
+		}
+		if merged.CpuAllocation == nil {
+			merged.CpuAllocation = cs.CpuAllocation
+		}
+		if merged.MemoryAllocation == nil {
+			merged.MemoryAllocation = cs.MemoryAllocation
+		}
+
+		for _, opt := range cs.ExtraConfig {
+			if optValue := opt.GetOptionValue(); optValue != nil {
+				if _, exists := ecKeys[optValue.Key]; exists {
+					continue
+				}
+				ecKeys[optValue.Key] = struct{}{}
+			}
+			merged.ExtraConfig = append(merged.ExtraConfig, opt)
+		}
+
+		for _, devChange := range cs.DeviceChange {
+			if spec := devChange.GetVirtualDeviceConfigSpec(); spec != nil && spec.Device != nil {
+				if key := spec.Device.GetVirtualDevice().Key; key != 0 {
+					if _, exists := deviceKeys[key]; exists {
+						continue
+					}
+					deviceKeys[key] = struct{}{}
+				}
+			}
+			merged.DeviceChange = append(merged.DeviceChange, devChange)
+		}
+	}
+
+	return merged
+}
+
+// MergeBaseClassConfigSpec returns a new ConfigSpec that is the result of merging the ConfigSpec
+// of a VM class beneath the class's own ConfigSpec, either of which may be nil. Each field that
+// the class's ConfigSpec sets takes precedence over the base's, except for the ExtraConfig and
+// DeviceChange, which are merged like MergeConfigSpecs does.
+func MergeBaseClassConfigSpec(
+	classConfigSpec, baseConfigSpec *vimTypes.VirtualMachineConfigSpec) *vimTypes.VirtualMachineConfigSpec {
+
+	merged := &vimTypes.VirtualMachineConfigSpec{}
+	if baseConfigSpec != nil {
+		*merged = *baseConfigSpec
+	}
+
+	if classConfigSpec != nil {
+		csValue := reflect.ValueOf(*classConfigSpec)
+		mergedValue := reflect.ValueOf(merged).Elem()
+		for i := 0; i < csValue.NumField(); i++ {
+			if !csValue.Type().Field(i).Anonymous && !csValue.Field(i).IsZero() {
+				mergedValue.Field(i).Set(csValue.Field(i))
+			}
+		}
+	}
+
+	mergedLists := MergeConfigSpecs(nil, classConfigSpec, baseConfigSpec)
+	merged.ExtraConfig = mergedLists.ExtraConfig
+	merged.DeviceChange = mergedLists.DeviceChange
+
+	return merged
+}
@@ -1002,3 +1002,229 @@ var virtualMachineConfigInfoForTests vimTypes.VirtualMachineConfigInfo = vimType
 	Pmem:         nil,
 	DeviceGroups: &vimTypes.VirtualMachineVirtualDeviceGroups{},
 }
+
+var _ = Describe("MergeConfigSpecs", func() {
+	var (
+		vmConfigSpec    *vimTypes.VirtualMachineConfigSpec
+		classConfigSpec *vimTypes.VirtualMachineConfigSpec
+		imageConfigSpec *vimTypes.VirtualMachineConfigSpec
+		merged          *vimTypes.VirtualMachineConfigSpec
+	)
+
+	deviceChange := func(dev vimTypes.BaseVirtualDevice) vimTypes.BaseVirtualDeviceConfigSpec {
+		return &vimTypes.VirtualDeviceConfigSpec{
+			Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+			Device:    dev,
+		}
+	}
+
+	BeforeEach(func() {
+		vmConfigSpec = &vimTypes.VirtualMachineConfigSpec{}
+		classConfigSpec = &vimTypes.VirtualMachineConfigSpec{}
+		imageConfigSpec = &vimTypes.VirtualMachineConfigSpec{}
+	})
+
+	JustBeforeEach(func() {
+		merged = util.MergeConfigSpecs(vmConfigSpec, classConfigSpec, imageConfigSpec)
+	})
+
+	It("returns empty ConfigSpec when all are nil", func() {
+		Expect(util.MergeConfigSpecs(nil, nil, nil)).To(Equal(&vimTypes.VirtualMachineConfigSpec{}))
+	})
+
+	Context("CPU and memory", func() {
+		BeforeEach(func() {
+			imageConfigSpec.NumCPUs = 1
+			imageConfigSpec.MemoryMB = 1024
+			imageConfigSpec.CpuAllocation = &vimTypes.ResourceAllocationInfo{Reservation: pointer.Int64(1)}
+		})
+
+		It("uses the image values when nothing else is set", func() {
+			Expect(merged.NumCPUs).To(BeEquivalentTo(1))
+			Expect(merged.MemoryMB).To(BeEquivalentTo(1024))
+			Expect(merged.CpuAllocation.Reservation).To(Equal(pointer.Int64(1)))
+		})
+
+		When("class sets the values", func() {
+			BeforeEach(func() {
+				classConfigSpec.NumCPUs = 2
+				classConfigSpec.MemoryMB = 2048
+				classConfigSpec.CpuAllocation = &vimTypes.ResourceAllocationInfo{Reservation: pointer.Int64(2)}
+			})
+
+			It("class overrides the image", func() {
+				Expect(merged.NumCPUs).To(BeEquivalentTo(2))
+				Expect(merged.MemoryMB).To(BeEquivalentTo(2048))
+				Expect(merged.CpuAllocation.Reservation).To(Equal(pointer.Int64(2)))
+			})
+
+			When("VM sets the values", func() {
+				BeforeEach(func() {
+					vmConfigSpec.NumCPUs = 4
+					vmConfigSpec.MemoryMB = 4096
+				})
+
+				It("VM overrides the class", func() {
+					Expect(merged.NumCPUs).To(BeEquivalentTo(4))
+					Expect(merged.MemoryMB).To(BeEquivalentTo(4096))
+					Expect(merged.CpuAllocation.Reservation).To(Equal(pointer.Int64(2)))
+				})
+			})
+		})
+	})
+
+	Context("ExtraConfig", func() {
+		BeforeEach(func() {
+			imageConfigSpec.ExtraConfig = []vimTypes.BaseOptionValue{
+				&vimTypes.OptionValue{Key: "image-key", Value: "image"},
+				&vimTypes.OptionValue{Key: "shared-key", Value: "image"},
+			}
+			classConfigSpec.ExtraConfig = []vimTypes.BaseOptionValue{
+				&vimTypes.OptionValue{Key: "class-key", Value: "class"},
+				&vimTypes.OptionValue{Key: "shared-key", Value: "class"},
+			}
+			vmConfigSpec.ExtraConfig = []vimTypes.BaseOptionValue{
+				&vimTypes.OptionValue{Key: "shared-key", Value: "vm"},
+			}
+		})
+
+		It("merges the keys with VM > class > image precedence", func() {
+			Expect(util.ExtraConfigToMap(merged.ExtraConfig)).To(Equal(map[string]string{
+				"image-key":  "image",
+				"class-key":  "class",
+				"shared-key": "vm",
+			}))
+		})
+	})
+
+	Context("DeviceChange", func() {
+		BeforeEach(func() {
+			imageConfigSpec.DeviceChange = []vimTypes.BaseVirtualDeviceConfigSpec{
+				deviceChange(&vimTypes.VirtualVmxnet3{VirtualVmxnet: vimTypes.VirtualVmxnet{VirtualEthernetCard: vimTypes.VirtualEthernetCard{
+					VirtualDevice: vimTypes.VirtualDevice{Key: 4000}}}}),
+				deviceChange(&vimTypes.VirtualCdrom{VirtualDevice: vimTypes.VirtualDevice{Key: 3000}}),
+			}
+			classConfigSpec.DeviceChange = []vimTypes.BaseVirtualDeviceConfigSpec{
+				deviceChange(&vimTypes.VirtualE1000{VirtualEthernetCard: vimTypes.VirtualEthernetCard{
+					VirtualDevice: vimTypes.VirtualDevice{Key: 4000}}}),
+				deviceChange(&vimTypes.VirtualPCIPassthrough{}),
+			}
+			vmConfigSpec.DeviceChange = []vimTypes.BaseVirtualDeviceConfigSpec{
+				deviceChange(&vimTypes.VirtualCdrom{VirtualDevice: vimTypes.VirtualDevice{
+					Key:        3000,
+					DeviceInfo: &vimTypes.Description{Label: "vm-cdrom"},
+				}}),
+			}
+		})
+
+		It("overrides devices by key with VM > class > image precedence", func() {
+			devices := util.DevicesFromConfigSpec(merged)
+			Expect(devices).To(HaveLen(3))
+
+			Expect(devices[0]).To(BeAssignableToTypeOf(&vimTypes.VirtualCdrom{}))
+			Expect(devices[0].GetVirtualDevice().DeviceInfo.GetDescription().Label).To(Equal("vm-cdrom"))
+			Expect(devices[1]).To(BeAssignableToTypeOf(&vimTypes.VirtualE1000{}))
+			Expect(devices[1].GetVirtualDevice().Key).To(BeEquivalentTo(4000))
+			Expect(devices[2]).To(BeAssignableToTypeOf(&vimTypes.VirtualPCIPassthrough{}))
+		})
+	})
+})
+
+var _ = Describe("MergeBaseClassConfigSpec", func() {
+	var (
+		classConfigSpec *vimTypes.VirtualMachineConfigSpec
+		baseConfigSpec  *vimTypes.VirtualMachineConfigSpec
+		merged          *vimTypes.VirtualMachineConfigSpec
+	)
+
+	BeforeEach(func() {
+		classConfigSpec = &vimTypes.VirtualMachineConfigSpec{}
+		baseConfigSpec = &vimTypes.VirtualMachineConfigSpec{}
+	})
+
+	JustBeforeEach(func() {
+		merged = util.MergeBaseClassConfigSpec(classConfigSpec, baseConfigSpec)
+	})
+
+	It("returns empty ConfigSpec when both are nil", func() {
+		Expect(util.MergeBaseClassConfigSpec(nil, nil)).To(Equal(&vimTypes.VirtualMachineConfigSpec{}))
+	})
+
+	Context("Fields", func() {
+		BeforeEach(func() {
+			baseConfigSpec.Version = "vmx-19"
+			baseConfigSpec.Firmware = "efi"
+			baseConfigSpec.NumCPUs = 2
+			baseConfigSpec.NestedHVEnabled = pointer.Bool(true)
+
+			classConfigSpec.NumCPUs = 4
+			classConfigSpec.MemoryMB = 4096
+			classConfigSpec.NestedHVEnabled = pointer.Bool(false)
+		})
+
+		It("class fields override the base", func() {
+			Expect(merged.Version).To(Equal("vmx-19"))
+			Expect(merged.Firmware).To(Equal("efi"))
+			Expect(merged.NumCPUs).To(BeEquivalentTo(4))
+			Expect(merged.MemoryMB).To(BeEquivalentTo(4096))
+			Expect(merged.NestedHVEnabled).To(Equal(pointer.Bool(false)))
+		})
+
+		It("does not modify the ConfigSpecs", func() {
+			Expect(baseConfigSpec.NumCPUs).To(BeEquivalentTo(2))
+			Expect(classConfigSpec.Firmware).To(BeEmpty())
+		})
+	})
+
+	Context("ExtraConfig", func() {
+		BeforeEach(func() {
+			baseConfigSpec.ExtraConfig = []vimTypes.BaseOptionValue{
+				&vimTypes.OptionValue{Key: "base-key", Value: "base"},
+				&vimTypes.OptionValue{Key: "shared-key", Value: "base"},
+			}
+			classConfigSpec.ExtraConfig = []vimTypes.BaseOptionValue{
+				&vimTypes.OptionValue{Key: "shared-key", Value: "class"},
+			}
+		})
+
+		It("merges the keys with class > base precedence", func() {
+			Expect(util.ExtraConfigToMap(merged.ExtraConfig)).To(Equal(map[string]string{
+				"base-key":   "base",
+				"shared-key": "class",
+			}))
+		})
+	})
+
+	Context("DeviceChange", func() {
+		BeforeEach(func() {
+			baseConfigSpec.DeviceChange = []vimTypes.BaseVirtualDeviceConfigSpec{
+				&vimTypes.VirtualDeviceConfigSpec{
+					Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+					Device:    &vimTypes.VirtualCdrom{VirtualDevice: vimTypes.VirtualDevice{Key: 3000}},
+				},
+				&vimTypes.VirtualDeviceConfigSpec{
+					Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+					Device:    &vimTypes.VirtualPCIPassthrough{},
+				},
+			}
+			classConfigSpec.DeviceChange = []vimTypes.BaseVirtualDeviceConfigSpec{
+				&vimTypes.VirtualDeviceConfigSpec{
+					Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+					Device: &vimTypes.VirtualCdrom{VirtualDevice: vimTypes.VirtualDevice{
+						Key:        3000,
+						DeviceInfo: &vimTypes.Description{Label: "class-cdrom"},
+					}},
+				},
+			}
+		})
+
+		It("overrides devices by key with class > base precedence", func() {
+			devices := util.DevicesFromConfigSpec(merged)
+			Expect(devices).To(HaveLen(2))
+
+			Expect(devices[0]).To(BeAssignableToTypeOf(&vimTypes.VirtualCdrom{}))
+			Expect(devices[0].GetVirtualDevice().DeviceInfo.GetDescription().Label).To(Equal("class-cdrom"))
+			Expect(devices[1]).To(BeAssignableToTypeOf(&vimTypes.VirtualPCIPassthrough{}))
+		})
+	})
+})
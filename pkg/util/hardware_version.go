@@ -8,7 +8,10 @@ import (
 	"strconv"
 )
 
-var vmxRe = regexp.MustCompile(`vmx-(\d+)`)
+var (
+	vmxRe    = regexp.MustCompile(`vmx-(\d+)`)
+	vmxAllRe = regexp.MustCompile(`\bvmx-(\d+)\b`)
+)
 
 // ParseVirtualHardwareVersion parses the virtual hardware version
 // For eg. "vmx-15" returns 15.
@@ -27,3 +30,24 @@ func ParseVirtualHardwareVersion(vmxVersion string) int32 {
 
 	return int32(version)
 }
+
+// ParseOvfVirtualHardwareVersion parses the virtual hardware version from an
+// OVF VirtualSystemType, which may list multiple types that the virtual system
+// is compatible with. The lowest listed hardware version is returned because
+// the image can be deployed with any of them. For eg. "vmx-10 vmx-13" returns
+// 10. Zero is returned when no hardware version is listed.
+func ParseOvfVirtualHardwareVersion(virtualSystemType string) int32 {
+	var lowest int32
+	for _, obj := range vmxAllRe.FindAllStringSubmatch(virtualSystemType, -1) {
+		version, err := strconv.ParseInt(obj[1], 10, 32)
+		if err != nil || version == 0 {
+			continue
+		}
+
+		if lowest == 0 || int32(version) < lowest {
+			lowest = int32(version)
+		}
+	}
+
+	return lowest
+}
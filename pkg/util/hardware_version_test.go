@@ -26,3 +26,26 @@ var _ = Describe("ParseVirtualHardwareVersion", func() {
 		Expect(util.ParseVirtualHardwareVersion(vmxHwVersionString)).To(Equal(int32(15)))
 	})
 })
+
+var _ = Describe("ParseOvfVirtualHardwareVersion", func() {
+	It("empty virtual system type", func() {
+		Expect(util.ParseOvfVirtualHardwareVersion("")).To(BeZero())
+	})
+
+	It("virtual system type without a hardware version", func() {
+		Expect(util.ParseOvfVirtualHardwareVersion("xen-3")).To(BeZero())
+	})
+
+	It("single hardware version eg. vmx-15", func() {
+		Expect(util.ParseOvfVirtualHardwareVersion("vmx-15")).To(Equal(int32(15)))
+	})
+
+	It("multiple hardware versions returns the lowest", func() {
+		Expect(util.ParseOvfVirtualHardwareVersion("vmx-13 vmx-10 vmx-19")).To(Equal(int32(10)))
+		Expect(util.ParseOvfVirtualHardwareVersion("vmx-13,vmx-10")).To(Equal(int32(10)))
+	})
+
+	It("ignores other virtual system types", func() {
+		Expect(util.ParseOvfVirtualHardwareVersion("xen-3 vmx-17")).To(Equal(int32(17)))
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+)
+
+// Backoff returns the configured backoff used to retry an operation that failed
+// with a transient fault.
+func Backoff() wait.Backoff {
+	return wait.Backoff{
+		Steps:    lib.GetTransientFaultRetrySteps(),
+		Duration: lib.GetTransientFaultRetryDelay(),
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
+}
+
+// OnTransientFault calls fn, retrying it with the configured backoff for as long
+// as it fails with a transient fault. Any other error is returned immediately.
+// If fn still fails after the backoff is exhausted, its last error is returned.
+func OnTransientFault(fn func() error) error {
+	return retry.OnError(Backoff(), IsTransientFault, fn)
+}
+
+// IsTransientFault returns true if the error is a vSphere fault that is likely
+// to succeed if the operation is retried shortly.
+func IsTransientFault(err error) bool {
+	switch Fault(err).(type) {
+	case *types.InvalidState, *types.ResourceInUse, *types.TaskInProgress:
+		return true
+	default:
+		return false
+	}
+}
+
+// Fault returns the vSphere fault of the error, or nil if the error is not, and
+// does not wrap, a vSphere fault. This includes both the faults returned when a
+// method is invoked and the faults of failed tasks.
+func Fault(err error) types.BaseMethodFault {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch {
+		case soap.IsSoapFault(err):
+			if fault := methodFault(soap.ToSoapFault(err).VimFault()); fault != nil {
+				return fault
+			}
+		case soap.IsVimFault(err):
+			return soap.ToVimFault(err)
+		}
+
+		if taskErr, ok := err.(task.Error); ok {
+			return taskErr.Fault()
+		}
+	}
+
+	return nil
+}
+
+func methodFault(fault types.AnyType) types.BaseMethodFault {
+	if f, ok := fault.(types.BaseMethodFault); ok {
+		return f
+	}
+
+	// The fault of a SOAP response is decoded as a value rather than a pointer.
+	if v := reflect.ValueOf(fault); v.IsValid() && v.Kind() == reflect.Struct {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		if f, ok := p.Interface().(types.BaseMethodFault); ok {
+			return f
+		}
+	}
+
+	return nil
+}
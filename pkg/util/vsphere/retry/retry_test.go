@@ -0,0 +1,96 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package retry_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/retry"
+)
+
+func retryTests() {
+	Context("IsTransientFault", func() {
+		DescribeTable("classifies the error",
+			func(err error, expected bool) {
+				Expect(retry.IsTransientFault(err)).To(Equal(expected))
+			},
+			Entry("nil", nil, false),
+			Entry("non-fault error", errors.New("error"), false),
+			Entry("InvalidState vim fault", soap.WrapVimFault(&types.InvalidState{}), true),
+			Entry("ResourceInUse vim fault", soap.WrapVimFault(&types.ResourceInUse{}), true),
+			Entry("TaskInProgress vim fault", soap.WrapVimFault(&types.TaskInProgress{}), true),
+			Entry("NotSupported vim fault", soap.WrapVimFault(&types.NotSupported{}), false),
+			Entry("InvalidPowerState vim fault", soap.WrapVimFault(&types.InvalidPowerState{}), false),
+			Entry("ResourceInUse soap fault", soap.WrapSoapFault(&soap.Fault{
+				Detail: struct {
+					Fault types.AnyType `xml:",any,typeattr"`
+				}{Fault: types.ResourceInUse{}},
+			}), true),
+			Entry("NotSupported soap fault", soap.WrapSoapFault(&soap.Fault{
+				Detail: struct {
+					Fault types.AnyType `xml:",any,typeattr"`
+				}{Fault: types.NotSupported{}},
+			}), false),
+			Entry("TaskInProgress task error", task.Error{
+				LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.TaskInProgress{}},
+			}, true),
+			Entry("wrapped TaskInProgress task error", pkgerrors.Wrap(task.Error{
+				LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.TaskInProgress{}},
+			}, "task failed"), true),
+		)
+	})
+
+	Context("OnTransientFault", func() {
+		BeforeEach(func() {
+			Expect(os.Setenv(lib.TransientFaultRetryDelayEnv, "1ms")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv(lib.TransientFaultRetryDelayEnv)).To(Succeed())
+		})
+
+		It("retries a transient fault until it succeeds", func() {
+			attempts := 0
+			Expect(retry.OnTransientFault(func() error {
+				attempts++
+				if attempts == 1 {
+					return soap.WrapVimFault(&types.ResourceInUse{})
+				}
+				return nil
+			})).To(Succeed())
+			Expect(attempts).To(Equal(2))
+		})
+
+		It("returns the last transient fault after the configured attempts", func() {
+			attempts := 0
+			err := retry.OnTransientFault(func() error {
+				attempts++
+				return soap.WrapVimFault(&types.TaskInProgress{})
+			})
+			Expect(retry.IsTransientFault(err)).To(BeTrue())
+			Expect(attempts).To(Equal(lib.DefaultTransientFaultRetrySteps))
+		})
+
+		It("returns a non-transient fault immediately", func() {
+			attempts := 0
+			err := retry.OnTransientFault(func() error {
+				attempts++
+				return soap.WrapVimFault(&types.NotSupported{})
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(attempts).To(Equal(1))
+		})
+	})
+}
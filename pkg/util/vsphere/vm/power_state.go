@@ -27,6 +27,13 @@ import (
 // operation.
 const DefaultTrySoftTimeout = 5 * time.Minute
 
+// WithSoftTimeout returns a context with the amount of time a Soft or TrySoft
+// operation waits for a desired power state to be realized. Otherwise,
+// DefaultTrySoftTimeout is used.
+func WithSoftTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, internal.SoftTimeoutKey, timeout)
+}
+
 // PowerOpBehavior indicates the three behaviors for powering off or suspending
 // a VM.
 type PowerOpBehavior uint8
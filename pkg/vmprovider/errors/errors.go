@@ -0,0 +1,137 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package errors classifies the faults returned by vSphere into typed errors so
+// the callers of a VM provider can branch on them.
+package errors
+
+import (
+	"errors"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/retry"
+)
+
+// Reason is the classification of a vSphere fault.
+type Reason string
+
+const (
+	// ReasonNotFound is a fault for an object that does not exist.
+	ReasonNotFound Reason = "NotFound"
+	// ReasonNotAuthenticated is a fault for a session that is not, or is no
+	// longer, authenticated.
+	ReasonNotAuthenticated Reason = "NotAuthenticated"
+	// ReasonNoPermission is a fault for an operation the session's user is
+	// not permitted to perform.
+	ReasonNoPermission Reason = "NoPermission"
+	// ReasonDuplicateName is a fault for an object created with the name of
+	// an existing object.
+	ReasonDuplicateName Reason = "DuplicateName"
+	// ReasonInsufficientResources is a fault for an operation that would
+	// violate a resource usage policy, ex. not enough memory or CPU.
+	ReasonInsufficientResources Reason = "InsufficientResources"
+	// ReasonTransient is a fault that is likely to not occur if the operation
+	// is retried shortly, ex. the object is busy with another task.
+	ReasonTransient Reason = "Transient"
+)
+
+var (
+	// ErrNotFound matches, with errors.Is, an error classified as ReasonNotFound.
+	ErrNotFound = &Error{Reason: ReasonNotFound}
+	// ErrNotAuthenticated matches, with errors.Is, an error classified as ReasonNotAuthenticated.
+	ErrNotAuthenticated = &Error{Reason: ReasonNotAuthenticated}
+	// ErrNoPermission matches, with errors.Is, an error classified as ReasonNoPermission.
+	ErrNoPermission = &Error{Reason: ReasonNoPermission}
+	// ErrDuplicateName matches, with errors.Is, an error classified as ReasonDuplicateName.
+	ErrDuplicateName = &Error{Reason: ReasonDuplicateName}
+	// ErrInsufficientResources matches, with errors.Is, an error classified as ReasonInsufficientResources.
+	ErrInsufficientResources = &Error{Reason: ReasonInsufficientResources}
+	// ErrTransient matches, with errors.Is, an error classified as ReasonTransient.
+	ErrTransient = &Error{Reason: ReasonTransient}
+)
+
+// Error is an error caused by a classified vSphere fault.
+type Error struct {
+	// Reason is the classification of the fault.
+	Reason Reason
+	// Fault is the vSphere fault, if the error was caused by one.
+	Fault types.BaseMethodFault
+
+	err error
+}
+
+func (e *Error) Error() string {
+	if e.err == nil {
+		return string(e.Reason)
+	}
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is returns true if the target is an Error with the same Reason.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+// NewTransient returns err wrapped in an Error classified as ReasonTransient, for
+// an operation that should be retried shortly even though it did not fail with
+// a vSphere fault, ex. because another operation on the VM is in progress.
+func NewTransient(err error) error {
+	return &Error{Reason: ReasonTransient, err: err}
+}
+
+// Classify returns the error wrapped in an Error if it is, or wraps, a vSphere
+// fault with a known classification. Otherwise, the error is returned as is.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	var notFoundErr *find.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return &Error{Reason: ReasonNotFound, err: err}
+	}
+
+	fault := retry.Fault(err)
+	if fault == nil {
+		return err
+	}
+
+	reason, ok := faultReason(fault)
+	if !ok {
+		if !retry.IsTransientFault(err) {
+			return err
+		}
+		reason = ReasonTransient
+	}
+
+	return &Error{Reason: reason, Fault: fault, err: err}
+}
+
+func faultReason(fault types.BaseMethodFault) (Reason, bool) {
+	switch fault.(type) {
+	case *types.ManagedObjectNotFound, *types.NotFound, *types.FileNotFound:
+		return ReasonNotFound, true
+	case *types.NotAuthenticated, *types.InvalidLogin:
+		return ReasonNotAuthenticated, true
+	case *types.NoPermission:
+		return ReasonNoPermission, true
+	case *types.DuplicateName:
+		return ReasonDuplicateName, true
+	case types.BaseInsufficientResourcesFault:
+		return ReasonInsufficientResources, true
+	default:
+		return "", false
+	}
+}
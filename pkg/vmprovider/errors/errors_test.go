@@ -0,0 +1,126 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package errors_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
+)
+
+func soapFault(fault types.AnyType) error {
+	return soap.WrapSoapFault(&soap.Fault{
+		Detail: struct {
+			Fault types.AnyType `xml:",any,typeattr"`
+		}{Fault: fault},
+	})
+}
+
+func errorsTests() {
+	Context("NewTransient", func() {
+		It("returns a transient error that wraps the error", func() {
+			err := errors.New("operation in progress")
+			transientErr := providererrors.NewTransient(err)
+			Expect(errors.Is(transientErr, providererrors.ErrTransient)).To(BeTrue())
+			Expect(errors.Is(transientErr, err)).To(BeTrue())
+			Expect(transientErr.Error()).To(Equal(err.Error()))
+			Expect(providererrors.Classify(transientErr)).To(BeIdenticalTo(transientErr))
+		})
+	})
+
+	Context("Classify", func() {
+		It("returns nil for a nil error", func() {
+			Expect(providererrors.Classify(nil)).To(BeNil())
+		})
+
+		It("returns an unclassified error as is", func() {
+			err := errors.New("error")
+			Expect(providererrors.Classify(err)).To(BeIdenticalTo(err))
+
+			err = soap.WrapVimFault(&types.NotSupported{})
+			Expect(providererrors.Classify(err)).To(BeIdenticalTo(err))
+		})
+
+		It("does not reclassify a classified error", func() {
+			err := providererrors.Classify(soap.WrapVimFault(&types.DuplicateName{}))
+			Expect(providererrors.Classify(err)).To(BeIdenticalTo(err))
+		})
+
+		DescribeTable("classifies the fault",
+			func(err error, expected error) {
+				classified := providererrors.Classify(err)
+				Expect(errors.Is(classified, expected)).To(BeTrue())
+				Expect(classified.Error()).To(Equal(err.Error()))
+
+				var providerErr *providererrors.Error
+				Expect(errors.As(classified, &providerErr)).To(BeTrue())
+				Expect(errors.Unwrap(providerErr)).To(BeIdenticalTo(err))
+
+				for _, other := range []error{
+					providererrors.ErrNotFound,
+					providererrors.ErrNotAuthenticated,
+					providererrors.ErrNoPermission,
+					providererrors.ErrDuplicateName,
+					providererrors.ErrInsufficientResources,
+					providererrors.ErrTransient,
+				} {
+					if other != expected {
+						Expect(errors.Is(classified, other)).To(BeFalse())
+					}
+				}
+			},
+			Entry("ManagedObjectNotFound soap fault",
+				soapFault(types.ManagedObjectNotFound{}), providererrors.ErrNotFound),
+			Entry("NotFound vim fault",
+				soap.WrapVimFault(&types.NotFound{}), providererrors.ErrNotFound),
+			Entry("FileNotFound task error",
+				task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.FileNotFound{}}},
+				providererrors.ErrNotFound),
+			Entry("find NotFoundError",
+				&find.NotFoundError{}, providererrors.ErrNotFound),
+			Entry("NotAuthenticated soap fault",
+				soapFault(types.NotAuthenticated{}), providererrors.ErrNotAuthenticated),
+			Entry("InvalidLogin soap fault",
+				soapFault(types.InvalidLogin{}), providererrors.ErrNotAuthenticated),
+			Entry("NoPermission soap fault",
+				soapFault(types.NoPermission{}), providererrors.ErrNoPermission),
+			Entry("DuplicateName soap fault",
+				soapFault(types.DuplicateName{}), providererrors.ErrDuplicateName),
+			Entry("wrapped DuplicateName task error",
+				pkgerrors.Wrap(task.Error{
+					LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.DuplicateName{}},
+				}, "clone failed"),
+				providererrors.ErrDuplicateName),
+			Entry("InsufficientResourcesFault soap fault",
+				soapFault(types.InsufficientResourcesFault{}), providererrors.ErrInsufficientResources),
+			Entry("InsufficientMemoryResourcesFault task error",
+				task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.InsufficientMemoryResourcesFault{}}},
+				providererrors.ErrInsufficientResources),
+			Entry("InsufficientHostCpuCapacityFault vim fault",
+				soap.WrapVimFault(&types.InsufficientHostCpuCapacityFault{}), providererrors.ErrInsufficientResources),
+			Entry("ResourceInUse soap fault",
+				soapFault(types.ResourceInUse{}), providererrors.ErrTransient),
+			Entry("TaskInProgress task error",
+				task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.TaskInProgress{}}},
+				providererrors.ErrTransient),
+		)
+
+		It("exposes the fault of a classified error", func() {
+			var providerErr *providererrors.Error
+			Expect(errors.As(providererrors.Classify(soapFault(types.DuplicateName{Name: "my-vm"})), &providerErr)).To(BeTrue())
+			Expect(providerErr.Reason).To(Equal(providererrors.ReasonDuplicateName))
+			Expect(providerErr.Fault).To(Equal(&types.DuplicateName{Name: "my-vm"}))
+		})
+	})
+}
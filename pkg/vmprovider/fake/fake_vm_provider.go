@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/vmware/govmomi/vapi/library"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
@@ -35,6 +36,9 @@ type funcs struct {
 	GetVirtualMachineWebMKSTicketFn    func(ctx context.Context, vm *vmopv1.VirtualMachine, pubKey string) (string, error)
 	GetVirtualMachineHardwareVersionFn func(ctx context.Context, vm *vmopv1.VirtualMachine) (int32, error)
 
+	DoesContentLibraryExistFn                  func(ctx context.Context, clUUID string) (bool, error)
+	GetContentLibraryLastSyncTimeFn            func(ctx context.Context, clUUID string) (*time.Time, error)
+	SyncContentLibraryFn                       func(ctx context.Context, clUUID string) error
 	ListItemsFromContentLibraryFn              func(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider) ([]string, error)
 	GetVirtualMachineImageFromContentLibraryFn func(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider, itemID string,
 		currentCLImages map[string]vmopv1.VirtualMachineImage) (*vmopv1.VirtualMachineImage, error)
@@ -207,6 +211,39 @@ func (s *VMProvider) ResetVcClient(ctx context.Context) {
 	}
 }
 
+func (s *VMProvider) DoesContentLibraryExist(ctx context.Context, clUUID string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.DoesContentLibraryExistFn != nil {
+		return s.DoesContentLibraryExistFn(ctx, clUUID)
+	}
+
+	return true, nil
+}
+
+func (s *VMProvider) GetContentLibraryLastSyncTime(ctx context.Context, clUUID string) (*time.Time, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.GetContentLibraryLastSyncTimeFn != nil {
+		return s.GetContentLibraryLastSyncTimeFn(ctx, clUUID)
+	}
+
+	return nil, nil
+}
+
+func (s *VMProvider) SyncContentLibrary(ctx context.Context, clUUID string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.SyncContentLibraryFn != nil {
+		return s.SyncContentLibraryFn(ctx, clUUID)
+	}
+
+	return nil
+}
+
 func (s *VMProvider) ListItemsFromContentLibrary(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider) ([]string, error) {
 	s.Lock()
 	defer s.Unlock()
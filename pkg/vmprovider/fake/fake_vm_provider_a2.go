@@ -9,8 +9,10 @@ import (
 	"sync"
 
 	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vim25/mo"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
 
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	imgregv1a1 "github.com/vmware-tanzu/image-registry-operator-api/api/v1alpha1"
@@ -31,14 +33,39 @@ type funcsA2 struct {
 	DeleteVirtualMachineFn         func(ctx context.Context, vm *vmopv1.VirtualMachine) error
 	PublishVirtualMachineFn        func(ctx context.Context, vm *vmopv1.VirtualMachine,
 		vmPub *vmopv1.VirtualMachinePublishRequest, cl *imgregv1a1.ContentLibrary, actID string) (string, error)
-	GetVirtualMachineGuestHeartbeatFn  func(ctx context.Context, vm *vmopv1.VirtualMachine) (vmopv1.GuestHeartbeatStatus, error)
-	GetVirtualMachineWebMKSTicketFn    func(ctx context.Context, vm *vmopv1.VirtualMachine, pubKey string) (string, error)
-	GetVirtualMachineHardwareVersionFn func(ctx context.Context, vm *vmopv1.VirtualMachine) (int32, error)
+	GetVirtualMachineGuestHeartbeatFn                func(ctx context.Context, vm *vmopv1.VirtualMachine) (vmopv1.GuestHeartbeatStatus, error)
+	GetVirtualMachineWebMKSTicketFn                  func(ctx context.Context, vm *vmopv1.VirtualMachine, pubKey string) (string, error)
+	GetVirtualMachineHardwareVersionFn               func(ctx context.Context, vm *vmopv1.VirtualMachine) (int32, error)
+	GetVirtualMachineHardwareVersionUpgradeTargetsFn func(ctx context.Context, vm *vmopv1.VirtualMachine) (int32, []int32, error)
+	GetVirtualMachineCryptoKeyProviderFn             func(ctx context.Context, vm *vmopv1.VirtualMachine) (string, error)
+	GetVirtualMachineDeviceConnectionStatusFn        func(ctx context.Context, vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineDeviceStatus, error)
+	GetVirtualMachineTagsFn                          func(ctx context.Context, vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineTagStatus, error)
+	GetVirtualMachineDiagnosticFn                    func(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineDiagnostic, error)
+	GetVirtualMachineResourceAllocationFn            func(ctx context.Context, vm *vmopv1.VirtualMachine) (*vmopv1.VirtualMachineResourceAllocationStatus, error)
+	GetVirtualMachineGuestDisksFn                    func(ctx context.Context, vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineGuestDiskStatus, error)
+	GetVirtualMachineIdentityFn                      func(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineIdentity, error)
+	GetVirtualMachineFileLayoutFn                    func(ctx context.Context, vm *vmopv1.VirtualMachine) (string, []vimTypes.VirtualMachineFileLayoutExFileInfo, error)
+	GetVirtualMachineHotPlugFn                       func(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.HotPlug, error)
+	SetVirtualMachineHotPlugFn                       func(ctx context.Context, vm *vmopv1.VirtualMachine, hotPlug vmprovider.HotPlug) error
+	MarkAsTemplateFn                                 func(ctx context.Context, vm *vmopv1.VirtualMachine) error
+	MarkAsVirtualMachineFn                           func(ctx context.Context, vm *vmopv1.VirtualMachine, resourcePoolMoID string) error
+	GetVirtualMachineStatusPropertiesFn              func(ctx context.Context, vmRefs []vimTypes.ManagedObjectReference) (map[vimTypes.ManagedObjectReference]mo.VirtualMachine, error)
+	ConnectVirtualMachineDeviceFn                    func(ctx context.Context, vm *vmopv1.VirtualMachine, deviceKey int32) error
+	DisconnectVirtualMachineDeviceFn                 func(ctx context.Context, vm *vmopv1.VirtualMachine, deviceKey int32) error
+	DetachAndDeleteVirtualMachineDiskFn              func(ctx context.Context, vm *vmopv1.VirtualMachine, diskKey int32, deleteFile bool) error
+	UpdateVirtualMachineTaskStatusFn                 func(ctx context.Context, vm *vmopv1.VirtualMachine) error
+	ReapplyVirtualMachineCustomizationFn             func(ctx context.Context, vm *vmopv1.VirtualMachine) error
+	ReconcileVirtualMachineResourcePoolFn            func(ctx context.Context, vm *vmopv1.VirtualMachine, move bool) (vmprovider.ResourcePoolMembership, error)
+	ValidateVMAgainstImageFn                         func(ctx context.Context, namespace string, spec vmopv1.VirtualMachineSpec, imageName string) (field.ErrorList, error)
 
 	// ListItemsFromContentLibraryFn              func(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider) ([]string, error)
 	// GetVirtualMachineImageFromContentLibraryFn func(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider, itemID string,
 	//	currentCLImages map[string]vmopv1.VirtualMachineImage) (*vmopv1.VirtualMachineImage, error)
 
+	ResolveImageFn             func(ctx context.Context, imageName, namespace string) (vmprovider.ResolvedImage, error)
+	CacheImageFn               func(ctx context.Context, imageName, namespace, datastoreMoID string) error
+	ListContentLibrariesFn     func(ctx context.Context) ([]library.Library, error)
+	DoesContentLibraryExistFn  func(ctx context.Context, clUUID string) (bool, error)
 	GetItemFromLibraryByNameFn func(ctx context.Context, contentLibrary, itemName string) (*library.Item, error)
 	UpdateContentLibraryItemFn func(ctx context.Context, itemID, newName string, newDescription *string) error
 	SyncVirtualMachineImageFn  func(ctx context.Context, cli, vmi client.Object) error
@@ -50,6 +77,13 @@ type funcsA2 struct {
 	IsVirtualMachineSetResourcePolicyReadyFn        func(ctx context.Context, azName string, rp *vmopv1.VirtualMachineSetResourcePolicy) (bool, error)
 	DeleteVirtualMachineSetResourcePolicyFn         func(ctx context.Context, rp *vmopv1.VirtualMachineSetResourcePolicy) error
 	ComputeCPUMinFrequencyFn                        func(ctx context.Context) error
+	GetClusterSettingsFn                            func(ctx context.Context) ([]vmprovider.ClusterSettings, error)
+	ResolveClusterForRPFn                           func(ctx context.Context, rpMoID string) (vimTypes.ManagedObjectReference, error)
+	GetImageZoneCompatibilityFn                     func(ctx context.Context, imageStatus *vmopv1.VirtualMachineImageStatus, zoneName string) ([]vmprovider.ZoneCompatibility, error)
+	GetMissingPrivilegesFn                          func(ctx context.Context) (map[vimTypes.ManagedObjectReference][]string, error)
+	EvacuateHostFn                                  func(ctx context.Context, hostMoID string) ([]vmprovider.HostEvacuationResult, error)
+	ListManagedVirtualMachinesFn                    func(ctx context.Context) ([]vmprovider.ManagedVirtualMachine, error)
+	DeleteOrphanedVirtualMachineFn                  func(ctx context.Context, vmMoID string) error
 
 	GetTasksByActIDFn func(ctx context.Context, actID string) (tasksInfo []vimTypes.TaskInfo, retErr error)
 }
@@ -139,6 +173,201 @@ func (s *VMProviderA2) GetVirtualMachineHardwareVersion(ctx context.Context, vm
 	return 15, nil
 }
 
+func (s *VMProviderA2) GetVirtualMachineHardwareVersionUpgradeTargets(ctx context.Context, vm *vmopv1.VirtualMachine) (int32, []int32, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineHardwareVersionUpgradeTargetsFn != nil {
+		return s.GetVirtualMachineHardwareVersionUpgradeTargetsFn(ctx, vm)
+	}
+	return 15, nil, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineCryptoKeyProvider(ctx context.Context, vm *vmopv1.VirtualMachine) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineCryptoKeyProviderFn != nil {
+		return s.GetVirtualMachineCryptoKeyProviderFn(ctx, vm)
+	}
+	return "", nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineDeviceConnectionStatus(ctx context.Context, vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineDeviceStatus, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineDeviceConnectionStatusFn != nil {
+		return s.GetVirtualMachineDeviceConnectionStatusFn(ctx, vm)
+	}
+	return nil, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineTags(ctx context.Context, vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineTagStatus, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineTagsFn != nil {
+		return s.GetVirtualMachineTagsFn(ctx, vm)
+	}
+	return nil, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineDiagnostic(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineDiagnostic, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineDiagnosticFn != nil {
+		return s.GetVirtualMachineDiagnosticFn(ctx, vm)
+	}
+	return vmprovider.VirtualMachineDiagnostic{}, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineResourceAllocation(ctx context.Context, vm *vmopv1.VirtualMachine) (*vmopv1.VirtualMachineResourceAllocationStatus, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineResourceAllocationFn != nil {
+		return s.GetVirtualMachineResourceAllocationFn(ctx, vm)
+	}
+	return nil, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineGuestDisks(ctx context.Context, vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineGuestDiskStatus, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineGuestDisksFn != nil {
+		return s.GetVirtualMachineGuestDisksFn(ctx, vm)
+	}
+	return nil, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineIdentity(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineIdentity, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineIdentityFn != nil {
+		return s.GetVirtualMachineIdentityFn(ctx, vm)
+	}
+	return vmprovider.VirtualMachineIdentity{
+		VM:           vimTypes.ManagedObjectReference{Type: "VirtualMachine", Value: vm.Status.UniqueID},
+		BiosUUID:     vm.Status.BiosUUID,
+		InstanceUUID: vm.Status.InstanceUUID,
+	}, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineFileLayout(ctx context.Context, vm *vmopv1.VirtualMachine) (string, []vimTypes.VirtualMachineFileLayoutExFileInfo, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineFileLayoutFn != nil {
+		return s.GetVirtualMachineFileLayoutFn(ctx, vm)
+	}
+	return "", nil, nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineHotPlug(ctx context.Context, vm *vmopv1.VirtualMachine) (vmprovider.HotPlug, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineHotPlugFn != nil {
+		return s.GetVirtualMachineHotPlugFn(ctx, vm)
+	}
+	return vmprovider.HotPlug{}, nil
+}
+
+func (s *VMProviderA2) SetVirtualMachineHotPlug(ctx context.Context, vm *vmopv1.VirtualMachine, hotPlug vmprovider.HotPlug) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.SetVirtualMachineHotPlugFn != nil {
+		return s.SetVirtualMachineHotPlugFn(ctx, vm, hotPlug)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) MarkAsTemplate(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.MarkAsTemplateFn != nil {
+		return s.MarkAsTemplateFn(ctx, vm)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) MarkAsVirtualMachine(ctx context.Context, vm *vmopv1.VirtualMachine, resourcePoolMoID string) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.MarkAsVirtualMachineFn != nil {
+		return s.MarkAsVirtualMachineFn(ctx, vm, resourcePoolMoID)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) GetVirtualMachineStatusProperties(ctx context.Context, vmRefs []vimTypes.ManagedObjectReference) (map[vimTypes.ManagedObjectReference]mo.VirtualMachine, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetVirtualMachineStatusPropertiesFn != nil {
+		return s.GetVirtualMachineStatusPropertiesFn(ctx, vmRefs)
+	}
+	return map[vimTypes.ManagedObjectReference]mo.VirtualMachine{}, nil
+}
+
+func (s *VMProviderA2) ConnectVirtualMachineDevice(ctx context.Context, vm *vmopv1.VirtualMachine, deviceKey int32) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.ConnectVirtualMachineDeviceFn != nil {
+		return s.ConnectVirtualMachineDeviceFn(ctx, vm, deviceKey)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) DisconnectVirtualMachineDevice(ctx context.Context, vm *vmopv1.VirtualMachine, deviceKey int32) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.DisconnectVirtualMachineDeviceFn != nil {
+		return s.DisconnectVirtualMachineDeviceFn(ctx, vm, deviceKey)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) DetachAndDeleteVirtualMachineDisk(ctx context.Context, vm *vmopv1.VirtualMachine, diskKey int32, deleteFile bool) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.DetachAndDeleteVirtualMachineDiskFn != nil {
+		return s.DetachAndDeleteVirtualMachineDiskFn(ctx, vm, diskKey, deleteFile)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) UpdateVirtualMachineTaskStatus(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.UpdateVirtualMachineTaskStatusFn != nil {
+		return s.UpdateVirtualMachineTaskStatusFn(ctx, vm)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) ReapplyVirtualMachineCustomization(ctx context.Context, vm *vmopv1.VirtualMachine) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.ReapplyVirtualMachineCustomizationFn != nil {
+		return s.ReapplyVirtualMachineCustomizationFn(ctx, vm)
+	}
+	return nil
+}
+
+func (s *VMProviderA2) ReconcileVirtualMachineResourcePool(ctx context.Context, vm *vmopv1.VirtualMachine,
+	move bool) (vmprovider.ResourcePoolMembership, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.ReconcileVirtualMachineResourcePoolFn != nil {
+		return s.ReconcileVirtualMachineResourcePoolFn(ctx, vm, move)
+	}
+	return vmprovider.ResourcePoolMembership{}, nil
+}
+
+func (s *VMProviderA2) ValidateVMAgainstImage(ctx context.Context, namespace string,
+	spec vmopv1.VirtualMachineSpec, imageName string) (field.ErrorList, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.ValidateVMAgainstImageFn != nil {
+		return s.ValidateVMAgainstImageFn(ctx, namespace, spec, imageName)
+	}
+	return nil, nil
+}
+
 func (s *VMProviderA2) CreateOrUpdateVirtualMachineSetResourcePolicy(ctx context.Context, resourcePolicy *vmopv1.VirtualMachineSetResourcePolicy) error {
 	s.Lock()
 	defer s.Unlock()
@@ -189,6 +418,81 @@ func (s *VMProviderA2) ComputeCPUMinFrequency(ctx context.Context) error {
 	return nil
 }
 
+func (s *VMProviderA2) GetClusterSettings(ctx context.Context) ([]vmprovider.ClusterSettings, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.GetClusterSettingsFn != nil {
+		return s.GetClusterSettingsFn(ctx)
+	}
+
+	return nil, nil
+}
+
+func (s *VMProviderA2) ResolveClusterForRP(ctx context.Context, rpMoID string) (vimTypes.ManagedObjectReference, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.ResolveClusterForRPFn != nil {
+		return s.ResolveClusterForRPFn(ctx, rpMoID)
+	}
+
+	return vimTypes.ManagedObjectReference{}, nil
+}
+
+func (s *VMProviderA2) GetImageZoneCompatibility(
+	ctx context.Context,
+	imageStatus *vmopv1.VirtualMachineImageStatus,
+	zoneName string) ([]vmprovider.ZoneCompatibility, error) {
+
+	s.Lock()
+	defer s.Unlock()
+	if s.GetImageZoneCompatibilityFn != nil {
+		return s.GetImageZoneCompatibilityFn(ctx, imageStatus, zoneName)
+	}
+
+	return nil, nil
+}
+
+func (s *VMProviderA2) GetMissingPrivileges(ctx context.Context) (map[vimTypes.ManagedObjectReference][]string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.GetMissingPrivilegesFn != nil {
+		return s.GetMissingPrivilegesFn(ctx)
+	}
+
+	return map[vimTypes.ManagedObjectReference][]string{}, nil
+}
+
+func (s *VMProviderA2) EvacuateHost(ctx context.Context, hostMoID string) ([]vmprovider.HostEvacuationResult, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.EvacuateHostFn != nil {
+		return s.EvacuateHostFn(ctx, hostMoID)
+	}
+
+	return nil, nil
+}
+
+func (s *VMProviderA2) ListManagedVirtualMachines(ctx context.Context) ([]vmprovider.ManagedVirtualMachine, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.ListManagedVirtualMachinesFn != nil {
+		return s.ListManagedVirtualMachinesFn(ctx)
+	}
+
+	return nil, nil
+}
+
+func (s *VMProviderA2) DeleteOrphanedVirtualMachine(ctx context.Context, vmMoID string) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.DeleteOrphanedVirtualMachineFn != nil {
+		return s.DeleteOrphanedVirtualMachineFn(ctx, vmMoID)
+	}
+
+	return nil
+}
+
 func (s *VMProviderA2) UpdateVcPNID(ctx context.Context, vcPNID, vcPort string) error {
 	s.Lock()
 	defer s.Unlock()
@@ -245,6 +549,50 @@ func (s *VMProviderA2) SyncVirtualMachineImage(ctx context.Context, cli, vmi cli
 	return nil
 }
 
+func (s *VMProviderA2) ResolveImage(ctx context.Context, imageName, namespace string) (vmprovider.ResolvedImage, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.ResolveImageFn != nil {
+		return s.ResolveImageFn(ctx, imageName, namespace)
+	}
+
+	return vmprovider.ResolvedImage{}, nil
+}
+
+func (s *VMProviderA2) CacheImage(ctx context.Context, imageName, namespace, datastoreMoID string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.CacheImageFn != nil {
+		return s.CacheImageFn(ctx, imageName, namespace, datastoreMoID)
+	}
+
+	return nil
+}
+
+func (s *VMProviderA2) ListContentLibraries(ctx context.Context) ([]library.Library, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.ListContentLibrariesFn != nil {
+		return s.ListContentLibrariesFn(ctx)
+	}
+
+	return nil, nil
+}
+
+func (s *VMProviderA2) DoesContentLibraryExist(ctx context.Context, clUUID string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.DoesContentLibraryExistFn != nil {
+		return s.DoesContentLibraryExistFn(ctx, clUUID)
+	}
+
+	return true, nil
+}
+
 func (s *VMProviderA2) GetItemFromLibraryByName(ctx context.Context,
 	contentLibrary, itemName string) (*library.Item, error) {
 	s.Lock()
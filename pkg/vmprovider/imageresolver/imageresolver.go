@@ -0,0 +1,354 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package imageresolver resolves the image a VM references to its content library item,
+// through either the v1alpha1 ContentSource model or the v1alpha2 ContentLibrary model
+// depending on which API is enabled.
+package imageresolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	imgregv1a1 "github.com/vmware-tanzu/image-registry-operator-api/api/v1alpha1"
+
+	vmopv1a1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
+	vmopv1a2 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
+)
+
+// StatusNameField is the field index of the v1alpha2 VirtualMachineImage and
+// ClusterVirtualMachineImage by the name of the content library item they were created
+// from. The index is added to the manager by the v1alpha2 VirtualMachine mutation webhook.
+const StatusNameField = "status.name"
+
+// ErrAmbiguousImageName is returned when an image name matches more than one image.
+var ErrAmbiguousImageName = errors.New("VM image name is ambiguous")
+
+// Result is the content library item an image name resolved to.
+type Result struct {
+	// ImageKind and ImageName are the kind and name of the image resource.
+	ImageKind string
+	ImageName string
+
+	LibraryUUID string
+	ItemID      string
+	ItemName    string
+
+	// Storage is the storage backing of the item's content library.
+	Storage []library.StorageBackings
+}
+
+// Resolve returns the content library item of the image with the name that is available to
+// the namespace. When the v1alpha2 API is enabled, the image is resolved through its
+// provider item. Otherwise, it is resolved through the ContentSource bound to the namespace,
+// or with the WCP VM Image Registry the ContentLibrary referenced by the image.
+// With the v1alpha2 API, a name that is not of an image resource is resolved as the name of
+// the content library item an image was created from when the resolution opts into it, in the
+// same way as a VM's image name.
+func Resolve(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	restClient *rest.Client,
+	imageName, namespace string,
+	resolution vcconfig.ImageNameResolution) (*Result, error) {
+
+	var (
+		libMgr = library.NewManager(restClient)
+		result *Result
+		err    error
+	)
+
+	if lib.IsVMServiceV1Alpha2FSSEnabled() {
+		result, err = resolveV1A2(ctx, k8sClient, libMgr, imageName, namespace, resolution)
+	} else {
+		result, err = resolveV1A1(ctx, k8sClient, libMgr, imageName, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := libMgr.GetLibraryByID(ctx, result.LibraryUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library %s: %w", result.LibraryUUID, err)
+	}
+	result.Storage = cl.Storage
+
+	return result, nil
+}
+
+func resolveV1A2(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	libMgr *library.Manager,
+	imageName, namespace string,
+	resolution vcconfig.ImageNameResolution) (*Result, error) {
+
+	result := &Result{ImageName: imageName}
+	var status *vmopv1a2.VirtualMachineImageStatus
+
+	vmImage := &vmopv1a2.VirtualMachineImage{}
+	if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: imageName, Namespace: namespace}, vmImage); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		clusterVMImage := &vmopv1a2.ClusterVirtualMachineImage{}
+		if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: imageName}, clusterVMImage); err != nil {
+			if !apierrors.IsNotFound(err) || !resolution.ResolvesItemNames() {
+				return nil, err
+			}
+
+			img, resolveErr := ResolveByItemName(ctx, k8sClient, imageName, namespace, resolution)
+			switch {
+			case resolveErr == nil:
+			case apierrors.IsNotFound(resolveErr):
+				// Keep the NotFound error of the image name.
+				return nil, err
+			default:
+				return nil, resolveErr
+			}
+
+			result.ImageName = img.GetName()
+			switch img := img.(type) {
+			case *vmopv1a2.VirtualMachineImage:
+				result.ImageKind, status = "VirtualMachineImage", &img.Status
+			case *vmopv1a2.ClusterVirtualMachineImage:
+				result.ImageKind, status = "ClusterVirtualMachineImage", &img.Status
+			}
+		} else {
+			result.ImageKind, status = "ClusterVirtualMachineImage", &clusterVMImage.Status
+		}
+	} else {
+		result.ImageKind, status = "VirtualMachineImage", &vmImage.Status
+	}
+
+	if status.ProviderItemID == "" {
+		return nil, fmt.Errorf("%s %s does not have a provider item", result.ImageKind, imageName)
+	}
+
+	item, err := libMgr.GetLibraryItem(ctx, status.ProviderItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library item %s: %w", status.ProviderItemID, err)
+	}
+
+	result.LibraryUUID = item.LibraryID
+	result.ItemID = item.ID
+	result.ItemName = item.Name
+
+	return result, nil
+}
+
+// ResolveByItemName returns the v1alpha2 namespace or cluster image that was created from the
+// content library item with the name. It returns a NotFound error if no image matches, and
+// ErrAmbiguousImageName if more than one image matches and the resolution is not
+// ImageNameResolutionPreferBindingOrder. The images are looked up through the StatusNameField
+// index, so the client must have it.
+func ResolveByItemName(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	name, namespace string,
+	resolution vcconfig.ImageNameResolution) (ctrlclient.Object, error) {
+
+	// This is the binding order used by ImageNameResolutionPreferBindingOrder: the namespace
+	// images before the cluster images, and then the oldest image first.
+	var nsMatches, clusterMatches []ctrlclient.Object
+
+	vmImageList := &vmopv1a2.VirtualMachineImageList{}
+	if err := k8sClient.List(ctx, vmImageList, ctrlclient.InNamespace(namespace),
+		ctrlclient.MatchingFields{StatusNameField: name}); err != nil {
+		return nil, err
+	}
+	for i := range vmImageList.Items {
+		img := &vmImageList.Items[i]
+		// List does not set the TypeMeta of the items, but the callers expect it like from a Get.
+		img.SetGroupVersionKind(vmopv1a2.SchemeGroupVersion.WithKind("VirtualMachineImage"))
+		nsMatches = append(nsMatches, img)
+	}
+
+	clusterVMImageList := &vmopv1a2.ClusterVirtualMachineImageList{}
+	if err := k8sClient.List(ctx, clusterVMImageList, ctrlclient.MatchingFields{StatusNameField: name}); err != nil {
+		return nil, err
+	}
+	for i := range clusterVMImageList.Items {
+		img := &clusterVMImageList.Items[i]
+		img.SetGroupVersionKind(vmopv1a2.SchemeGroupVersion.WithKind("ClusterVirtualMachineImage"))
+		clusterMatches = append(clusterMatches, img)
+	}
+
+	for _, m := range [][]ctrlclient.Object{nsMatches, clusterMatches} {
+		sort.SliceStable(m, func(i, j int) bool {
+			ti, tj := m[i].GetCreationTimestamp(), m[j].GetCreationTimestamp()
+			if !ti.Equal(&tj) {
+				return ti.Before(&tj)
+			}
+			return m[i].GetName() < m[j].GetName()
+		})
+	}
+	matches := append(nsMatches, clusterMatches...) //nolint:gocritic
+
+	switch {
+	case len(matches) == 0:
+		return nil, apierrors.NewNotFound(vmopv1a2.SchemeGroupVersion.WithResource("virtualmachineimages").GroupResource(), name)
+	case len(matches) == 1 || resolution == vcconfig.ImageNameResolutionPreferBindingOrder:
+		return matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+	for _, m := range matches {
+		candidates = append(candidates, m.GetObjectKind().GroupVersionKind().Kind+" "+m.GetName())
+	}
+
+	return nil, fmt.Errorf("%w: %q matches %s", ErrAmbiguousImageName, name, strings.Join(candidates, ", "))
+}
+
+func resolveV1A1(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	libMgr *library.Manager,
+	imageName, namespace string) (*Result, error) {
+
+	var (
+		result = &Result{ImageName: imageName}
+		status *vmopv1a1.VirtualMachineImageStatus
+		err    error
+	)
+
+	if lib.IsWCPVMImageRegistryEnabled() {
+		status, err = resolveV1A1ImageRegistry(ctx, k8sClient, result, namespace)
+	} else {
+		status, err = resolveV1A1ContentSource(ctx, k8sClient, result, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs, err := libMgr.FindLibraryItems(ctx, library.FindItem{LibraryID: result.LibraryUUID, Name: status.ImageName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find library item %s: %w", status.ImageName, err)
+	}
+	if len(itemIDs) != 1 {
+		return nil, fmt.Errorf("expected one library item named %s in library %s but found %d",
+			status.ImageName, result.LibraryUUID, len(itemIDs))
+	}
+
+	result.ItemID = itemIDs[0]
+	result.ItemName = status.ImageName
+
+	return result, nil
+}
+
+// resolveV1A1ContentSource resolves the cluster scoped image through its ContentLibraryProvider,
+// whose ContentSource must be bound to the namespace.
+func resolveV1A1ContentSource(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	result *Result,
+	namespace string) (*vmopv1a1.VirtualMachineImageStatus, error) {
+
+	vmImage := &vmopv1a1.VirtualMachineImage{}
+	if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: result.ImageName}, vmImage); err != nil {
+		return nil, err
+	}
+	result.ImageKind = "VirtualMachineImage"
+
+	clProviderName := ownerName(vmImage.OwnerReferences, "ContentLibraryProvider")
+	if clProviderName == "" {
+		return nil, fmt.Errorf("VirtualMachineImage %s does not have a ContentLibraryProvider OwnerReference", vmImage.Name)
+	}
+
+	clProvider := &vmopv1a1.ContentLibraryProvider{}
+	if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: clProviderName}, clProvider); err != nil {
+		return nil, err
+	}
+
+	contentSourceName := ownerName(clProvider.OwnerReferences, "ContentSource")
+	if contentSourceName == "" {
+		return nil, fmt.Errorf("ContentLibraryProvider %s does not have a ContentSource OwnerReference", clProvider.Name)
+	}
+
+	csBindingList := &vmopv1a1.ContentSourceBindingList{}
+	if err := k8sClient.List(ctx, csBindingList, ctrlclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	for _, csBinding := range csBindingList.Items {
+		if csBinding.ContentSourceRef.Kind == "ContentSource" && csBinding.ContentSourceRef.Name == contentSourceName {
+			result.LibraryUUID = clProvider.Spec.UUID
+			return &vmImage.Status, nil
+		}
+	}
+
+	return nil, fmt.Errorf("namespace %s does not have access to ContentSource %s for VirtualMachineImage %s",
+		namespace, contentSourceName, vmImage.Name)
+}
+
+// resolveV1A1ImageRegistry resolves the namespace or cluster scoped image through the
+// ContentLibrary or ClusterContentLibrary it references.
+func resolveV1A1ImageRegistry(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	result *Result,
+	namespace string) (*vmopv1a1.VirtualMachineImageStatus, error) {
+
+	var status *vmopv1a1.VirtualMachineImageStatus
+
+	vmImage := &vmopv1a1.VirtualMachineImage{}
+	if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: result.ImageName, Namespace: namespace}, vmImage); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		clusterVMImage := &vmopv1a1.ClusterVirtualMachineImage{}
+		if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: result.ImageName}, clusterVMImage); err != nil {
+			return nil, err
+		}
+		result.ImageKind, status = "ClusterVirtualMachineImage", &clusterVMImage.Status
+	} else {
+		result.ImageKind, status = "VirtualMachineImage", &vmImage.Status
+	}
+
+	libraryRef := status.ContentLibraryRef
+	if libraryRef == nil {
+		return nil, fmt.Errorf("%s %s does not have a ContentLibraryRef", result.ImageKind, result.ImageName)
+	}
+
+	switch libraryRef.Kind {
+	case "ContentLibrary":
+		cl := &imgregv1a1.ContentLibrary{}
+		if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: libraryRef.Name, Namespace: namespace}, cl); err != nil {
+			return nil, err
+		}
+		result.LibraryUUID = string(cl.Spec.UUID)
+	case "ClusterContentLibrary":
+		ccl := &imgregv1a1.ClusterContentLibrary{}
+		if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: libraryRef.Name}, ccl); err != nil {
+			return nil, err
+		}
+		result.LibraryUUID = string(ccl.Spec.UUID)
+	default:
+		return nil, fmt.Errorf("%s %s has an invalid ContentLibraryRef kind: %s",
+			result.ImageKind, result.ImageName, libraryRef.Kind)
+	}
+
+	return status, nil
+}
+
+func ownerName(ownerRefs []metav1.OwnerReference, kind string) string {
+	for _, ownerRef := range ownerRefs {
+		if ownerRef.Kind == kind {
+			return ownerRef.Name
+		}
+	}
+	return ""
+}
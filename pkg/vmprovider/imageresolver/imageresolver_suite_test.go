@@ -0,0 +1,27 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imageresolver_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func vcSimTests() {
+	Describe("Resolve", resolveTests)
+	Describe("GetImagesForNamespace", getImagesForNamespaceTests)
+}
+
+var suite = builder.NewTestSuite()
+
+func TestImageResolver(t *testing.T) {
+	suite.Register(t, "VM Provider Image Resolver Suite", nil, vcSimTests)
+}
+
+var _ = BeforeSuite(suite.BeforeSuite)
+
+var _ = AfterSuite(suite.AfterSuite)
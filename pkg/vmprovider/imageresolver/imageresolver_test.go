@@ -0,0 +1,143 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imageresolver_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmopv1a2 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/imageresolver"
+	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func resolveTests() {
+
+	var (
+		ctx        *builder.TestContextForVCSim
+		testConfig builder.VCSimTestConfig
+		nsInfo     builder.WorkloadNamespaceInfo
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithContentLibrary: true}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig)
+		nsInfo = ctx.CreateWorkloadNamespace()
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	assertResolves := func(expectedKind string) {
+		It("resolves the image to its library item", func() {
+			result, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, ctx.ContentLibraryImageName, nsInfo.Namespace, vcconfig.ImageNameResolutionRequireFullyQualified)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeNil())
+			Expect(result.ImageKind).To(Equal(expectedKind))
+			Expect(result.ImageName).To(Equal(ctx.ContentLibraryImageName))
+			Expect(result.LibraryUUID).To(Equal(ctx.ContentLibraryID))
+			Expect(result.ItemID).ToNot(BeEmpty())
+			Expect(result.ItemName).To(Equal(ctx.ContentLibraryImageName))
+			Expect(result.Storage).To(HaveLen(1))
+			Expect(result.Storage[0].DatastoreID).ToNot(BeEmpty())
+		})
+
+		It("returns NotFound for an image that does not exist", func() {
+			_, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, "does-not-exist", nsInfo.Namespace, vcconfig.ImageNameResolutionRequireFullyQualified)
+			Expect(err).To(HaveOccurred())
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	}
+
+	Context("v1alpha1 ContentSource", func() {
+		assertResolves("VirtualMachineImage")
+
+		It("returns an error when the ContentSource is not bound to the namespace", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "unbound-"}}
+			Expect(ctx.Client.Create(ctx, ns)).To(Succeed())
+
+			_, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, ctx.ContentLibraryImageName, ns.Name, vcconfig.ImageNameResolutionRequireFullyQualified)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not have access to ContentSource"))
+		})
+	})
+
+	Context("v1alpha2 ContentLibrary", func() {
+		BeforeEach(func() {
+			testConfig.WithV1A2 = true
+		})
+
+		assertResolves("ClusterVirtualMachineImage")
+
+		It("resolves the namespace image before the cluster image of the same name", func() {
+			ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, ctx.ContentLibraryImageName)
+
+			result, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, ctx.ContentLibraryImageName, nsInfo.Namespace, vcconfig.ImageNameResolutionRequireFullyQualified)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.ImageKind).To(Equal("VirtualMachineImage"))
+			Expect(result.LibraryUUID).To(Equal(ctx.ContentLibraryID))
+			Expect(result.ItemID).To(Equal(ctx.ContentLibraryImageItemID))
+		})
+
+		It("resolves a namespace image", func() {
+			vmImage := ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, "ns-image")
+
+			result, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, vmImage.Name, nsInfo.Namespace, vcconfig.ImageNameResolutionRequireFullyQualified)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.ImageKind).To(Equal("VirtualMachineImage"))
+			Expect(result.ImageName).To(Equal(vmImage.Name))
+			Expect(result.ItemName).To(Equal(ctx.ContentLibraryImageName))
+
+			_, err = imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, vmImage.Name, ctx.CreateWorkloadNamespace().Namespace, vcconfig.ImageNameResolutionRequireFullyQualified)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		Context("the name of the content library item an image was created from", func() {
+			const itemName = "item-name"
+
+			var vmImage *vmopv1a2.VirtualMachineImage
+
+			JustBeforeEach(func() {
+				vmImage = ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, "ns-image")
+				vmImage.Status.Name = itemName
+				Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
+			})
+
+			It("is only resolved when the resolution opts into it", func() {
+				_, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, itemName, nsInfo.Namespace, vcconfig.ImageNameResolutionRequireFullyQualified)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				result, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, itemName, nsInfo.Namespace, vcconfig.ImageNameResolutionErrorOnAmbiguous)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.ImageKind).To(Equal("VirtualMachineImage"))
+				Expect(result.ImageName).To(Equal(vmImage.Name))
+				Expect(result.ItemID).To(Equal(ctx.ContentLibraryImageItemID))
+			})
+
+			It("returns an error when the name matches more than one image", func() {
+				otherVMImage := ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, "other-ns-image")
+				otherVMImage.Status.Name = itemName
+				Expect(ctx.Client.Status().Update(ctx, otherVMImage)).To(Succeed())
+
+				_, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, itemName, nsInfo.Namespace, vcconfig.ImageNameResolutionErrorOnAmbiguous)
+				Expect(err).To(MatchError(imageresolver.ErrAmbiguousImageName))
+
+				result, err := imageresolver.Resolve(ctx, ctx.Client, ctx.RestClient, itemName, nsInfo.Namespace, vcconfig.ImageNameResolutionPreferBindingOrder)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.ImageName).To(Equal(vmImage.Name))
+			})
+		})
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imageresolver
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmopv1a1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
+	vmopv1a2 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+)
+
+// Image is an image that a VM in the namespace may be deployed from.
+type Image struct {
+	// Kind and Name are the kind and name of the image resource, and are what a VM's
+	// ImageName is resolved to.
+	Kind string
+	Name string
+
+	OSType string
+	Ready  bool
+}
+
+// GetImagesForNamespace returns the images that are available to the namespace, sorted by name.
+// This is the same set of images that Resolve resolves a VM's image name against: when a
+// namespace scoped image and a cluster scoped image have the same name, only the namespace
+// scoped image is returned.
+func GetImagesForNamespace(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	namespace string) ([]Image, error) {
+
+	var (
+		images []Image
+		err    error
+	)
+
+	switch {
+	case lib.IsVMServiceV1Alpha2FSSEnabled():
+		images, err = getImagesV1A2(ctx, k8sClient, namespace)
+	case lib.IsWCPVMImageRegistryEnabled():
+		images, err = getImagesV1A1ImageRegistry(ctx, k8sClient, namespace)
+	default:
+		images, err = getImagesV1A1ContentSource(ctx, k8sClient, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Name < images[j].Name
+	})
+
+	return images, nil
+}
+
+func getImagesV1A2(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	namespace string) ([]Image, error) {
+
+	vmImageList := &vmopv1a2.VirtualMachineImageList{}
+	if err := k8sClient.List(ctx, vmImageList, ctrlclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	clusterVMImageList := &vmopv1a2.ClusterVirtualMachineImageList{}
+	if err := k8sClient.List(ctx, clusterVMImageList); err != nil {
+		return nil, err
+	}
+
+	images := make([]Image, 0, len(vmImageList.Items)+len(clusterVMImageList.Items))
+	names := map[string]struct{}{}
+
+	for i := range vmImageList.Items {
+		vmImage := &vmImageList.Items[i]
+		names[vmImage.Name] = struct{}{}
+		images = append(images, Image{
+			Kind:   "VirtualMachineImage",
+			Name:   vmImage.Name,
+			OSType: vmImage.Status.OSInfo.Type,
+			Ready:  conditions.IsTrue(vmImage, vmopv1a2.ReadyConditionType),
+		})
+	}
+
+	for i := range clusterVMImageList.Items {
+		clusterVMImage := &clusterVMImageList.Items[i]
+		if _, ok := names[clusterVMImage.Name]; ok {
+			continue
+		}
+		images = append(images, Image{
+			Kind:   "ClusterVirtualMachineImage",
+			Name:   clusterVMImage.Name,
+			OSType: clusterVMImage.Status.OSInfo.Type,
+			Ready:  conditions.IsTrue(clusterVMImage, vmopv1a2.ReadyConditionType),
+		})
+	}
+
+	return images, nil
+}
+
+// getImagesV1A1ImageRegistry returns the namespace scoped images and the cluster scoped images,
+// which are available to every namespace with the WCP VM Image Registry.
+func getImagesV1A1ImageRegistry(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	namespace string) ([]Image, error) {
+
+	vmImageList := &vmopv1a1.VirtualMachineImageList{}
+	if err := k8sClient.List(ctx, vmImageList, ctrlclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	clusterVMImageList := &vmopv1a1.ClusterVirtualMachineImageList{}
+	if err := k8sClient.List(ctx, clusterVMImageList); err != nil {
+		return nil, err
+	}
+
+	images := make([]Image, 0, len(vmImageList.Items)+len(clusterVMImageList.Items))
+	names := map[string]struct{}{}
+
+	for i := range vmImageList.Items {
+		vmImage := &vmImageList.Items[i]
+		names[vmImage.Name] = struct{}{}
+		images = append(images, Image{
+			Kind:   "VirtualMachineImage",
+			Name:   vmImage.Name,
+			OSType: vmImage.Spec.OSInfo.Type,
+			Ready:  isV1A1ImageReady(vmImage.Status.Conditions),
+		})
+	}
+
+	for i := range clusterVMImageList.Items {
+		clusterVMImage := &clusterVMImageList.Items[i]
+		if _, ok := names[clusterVMImage.Name]; ok {
+			continue
+		}
+		images = append(images, Image{
+			Kind:   "ClusterVirtualMachineImage",
+			Name:   clusterVMImage.Name,
+			OSType: clusterVMImage.Spec.OSInfo.Type,
+			Ready:  isV1A1ImageReady(clusterVMImage.Status.Conditions),
+		})
+	}
+
+	return images, nil
+}
+
+// getImagesV1A1ContentSource returns the cluster scoped images whose ContentLibraryProvider is
+// owned by a ContentSource that is bound to the namespace.
+func getImagesV1A1ContentSource(
+	ctx context.Context,
+	k8sClient ctrlclient.Client,
+	namespace string) ([]Image, error) {
+
+	csBindingList := &vmopv1a1.ContentSourceBindingList{}
+	if err := k8sClient.List(ctx, csBindingList, ctrlclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	boundContentSources := map[string]struct{}{}
+	for _, csBinding := range csBindingList.Items {
+		if csBinding.ContentSourceRef.Kind == "ContentSource" {
+			boundContentSources[csBinding.ContentSourceRef.Name] = struct{}{}
+		}
+	}
+
+	if len(boundContentSources) == 0 {
+		return nil, nil
+	}
+
+	vmImageList := &vmopv1a1.VirtualMachineImageList{}
+	if err := k8sClient.List(ctx, vmImageList); err != nil {
+		return nil, err
+	}
+
+	// The ContentSource of each ContentLibraryProvider, which is shared by all of its images.
+	clProviderContentSources := map[string]string{}
+
+	var images []Image
+	for i := range vmImageList.Items {
+		vmImage := &vmImageList.Items[i]
+
+		// Namespace scoped images are only resolved with the WCP VM Image Registry.
+		if vmImage.Namespace != "" {
+			continue
+		}
+
+		clProviderName := ownerName(vmImage.OwnerReferences, "ContentLibraryProvider")
+		if clProviderName == "" {
+			continue
+		}
+
+		contentSourceName, ok := clProviderContentSources[clProviderName]
+		if !ok {
+			clProvider := &vmopv1a1.ContentLibraryProvider{}
+			if err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: clProviderName}, clProvider); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+			} else {
+				contentSourceName = ownerName(clProvider.OwnerReferences, "ContentSource")
+			}
+			clProviderContentSources[clProviderName] = contentSourceName
+		}
+
+		if _, ok := boundContentSources[contentSourceName]; !ok {
+			continue
+		}
+
+		images = append(images, Image{
+			Kind:   "VirtualMachineImage",
+			Name:   vmImage.Name,
+			OSType: vmImage.Spec.OSInfo.Type,
+			Ready:  isV1A1ImageReady(vmImage.Status.Conditions),
+		})
+	}
+
+	return images, nil
+}
+
+// isV1A1ImageReady returns true if none of the v1alpha1 image conditions that are folded into
+// the v1alpha2 Ready condition are false.
+func isV1A1ImageReady(imageConditions vmopv1a1.Conditions) bool {
+	for _, c := range imageConditions {
+		switch c.Type {
+		case vmopv1a1.VirtualMachineImageSyncedCondition,
+			vmopv1a1.VirtualMachineImageProviderReadyCondition,
+			vmopv1a1.VirtualMachineImageProviderSecurityComplianceCondition:
+			if c.Status == corev1.ConditionFalse {
+				return false
+			}
+		}
+	}
+	return true
+}
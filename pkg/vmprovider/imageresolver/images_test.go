@@ -0,0 +1,110 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imageresolver_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/imageresolver"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func getImagesForNamespaceTests() {
+
+	var (
+		ctx        *builder.TestContextForVCSim
+		testConfig builder.VCSimTestConfig
+		nsInfo     builder.WorkloadNamespaceInfo
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithContentLibrary: true}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig)
+		nsInfo = ctx.CreateWorkloadNamespace()
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	Context("v1alpha1 ContentSource", func() {
+		It("returns the images of the ContentSources bound to the namespace", func() {
+			images, err := imageresolver.GetImagesForNamespace(ctx, ctx.Client, nsInfo.Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(images).To(ConsistOf(imageresolver.Image{
+				Kind:   "VirtualMachineImage",
+				Name:   ctx.ContentLibraryImageName,
+				OSType: builder.DummyOSType,
+				Ready:  true,
+			}))
+		})
+
+		It("returns no images when a ContentSource is not bound to the namespace", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "unbound-"}}
+			Expect(ctx.Client.Create(ctx, ns)).To(Succeed())
+
+			images, err := imageresolver.GetImagesForNamespace(ctx, ctx.Client, ns.Name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(images).To(BeEmpty())
+		})
+	})
+
+	Context("v1alpha2 ContentLibrary", func() {
+		BeforeEach(func() {
+			testConfig.WithV1A2 = true
+		})
+
+		It("returns the cluster images", func() {
+			images, err := imageresolver.GetImagesForNamespace(ctx, ctx.Client, nsInfo.Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(images).To(ConsistOf(imageresolver.Image{
+				Kind:   "ClusterVirtualMachineImage",
+				Name:   ctx.ContentLibraryImageName,
+				OSType: builder.DummyOSType,
+				Ready:  true,
+			}))
+		})
+
+		It("returns the namespace images instead of the cluster images of the same name", func() {
+			ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, ctx.ContentLibraryImageName)
+			vmImage := ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, "a-ns-image")
+			conditions.MarkFalse(vmImage, vmopv1.ReadyConditionType, vmopv1.VirtualMachineImageNotSyncedReason, "")
+			Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
+
+			images, err := imageresolver.GetImagesForNamespace(ctx, ctx.Client, nsInfo.Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(images).To(Equal([]imageresolver.Image{
+				{
+					Kind:   "VirtualMachineImage",
+					Name:   "a-ns-image",
+					OSType: builder.DummyOSType,
+					Ready:  false,
+				},
+				{
+					Kind:   "VirtualMachineImage",
+					Name:   ctx.ContentLibraryImageName,
+					OSType: builder.DummyOSType,
+					Ready:  true,
+				},
+			}))
+
+			By("the namespace images are not returned for another namespace", func() {
+				images, err := imageresolver.GetImagesForNamespace(ctx, ctx.Client, ctx.CreateWorkloadNamespace().Namespace)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(images).To(HaveLen(1))
+				Expect(images[0].Kind).To(Equal("ClusterVirtualMachineImage"))
+			})
+		})
+	})
+}
@@ -5,6 +5,7 @@ package vmprovider
 
 import (
 	"context"
+	"time"
 
 	"github.com/vmware/govmomi/vapi/library"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
@@ -35,6 +36,9 @@ type VirtualMachineProviderInterface interface {
 	ResetVcClient(ctx context.Context)
 	ComputeCPUMinFrequency(ctx context.Context) error
 
+	DoesContentLibraryExist(ctx context.Context, clUUID string) (bool, error)
+	GetContentLibraryLastSyncTime(ctx context.Context, clUUID string) (*time.Time, error)
+	SyncContentLibrary(ctx context.Context, clUUID string) error
 	ListItemsFromContentLibrary(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider) ([]string, error)
 	GetVirtualMachineImageFromContentLibrary(ctx context.Context, contentLibrary *vmopv1.ContentLibraryProvider, itemID string,
 		currentCLImages map[string]vmopv1.VirtualMachineImage) (*vmopv1.VirtualMachineImage, error)
@@ -5,10 +5,13 @@ package vmprovider
 
 import (
 	"context"
+	"time"
 
 	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vim25/mo"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
 
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	imgregv1a1 "github.com/vmware-tanzu/image-registry-operator-api/api/v1alpha1"
@@ -24,6 +27,27 @@ type VirtualMachineProviderInterfaceA2 interface {
 	GetVirtualMachineGuestHeartbeat(ctx context.Context, vm *v1alpha2.VirtualMachine) (v1alpha2.GuestHeartbeatStatus, error)
 	GetVirtualMachineWebMKSTicket(ctx context.Context, vm *v1alpha2.VirtualMachine, pubKey string) (string, error)
 	GetVirtualMachineHardwareVersion(ctx context.Context, vm *v1alpha2.VirtualMachine) (int32, error)
+	GetVirtualMachineHardwareVersionUpgradeTargets(ctx context.Context, vm *v1alpha2.VirtualMachine) (int32, []int32, error)
+	GetVirtualMachineCryptoKeyProvider(ctx context.Context, vm *v1alpha2.VirtualMachine) (string, error)
+	GetVirtualMachineDeviceConnectionStatus(ctx context.Context, vm *v1alpha2.VirtualMachine) ([]v1alpha2.VirtualMachineDeviceStatus, error)
+	GetVirtualMachineTags(ctx context.Context, vm *v1alpha2.VirtualMachine) ([]v1alpha2.VirtualMachineTagStatus, error)
+	GetVirtualMachineDiagnostic(ctx context.Context, vm *v1alpha2.VirtualMachine) (VirtualMachineDiagnostic, error)
+	GetVirtualMachineResourceAllocation(ctx context.Context, vm *v1alpha2.VirtualMachine) (*v1alpha2.VirtualMachineResourceAllocationStatus, error)
+	GetVirtualMachineGuestDisks(ctx context.Context, vm *v1alpha2.VirtualMachine) ([]v1alpha2.VirtualMachineGuestDiskStatus, error)
+	GetVirtualMachineIdentity(ctx context.Context, vm *v1alpha2.VirtualMachine) (VirtualMachineIdentity, error)
+	GetVirtualMachineFileLayout(ctx context.Context, vm *v1alpha2.VirtualMachine) (string, []vimTypes.VirtualMachineFileLayoutExFileInfo, error)
+	GetVirtualMachineHotPlug(ctx context.Context, vm *v1alpha2.VirtualMachine) (HotPlug, error)
+	SetVirtualMachineHotPlug(ctx context.Context, vm *v1alpha2.VirtualMachine, hotPlug HotPlug) error
+	MarkAsTemplate(ctx context.Context, vm *v1alpha2.VirtualMachine) error
+	MarkAsVirtualMachine(ctx context.Context, vm *v1alpha2.VirtualMachine, resourcePoolMoID string) error
+	GetVirtualMachineStatusProperties(ctx context.Context, vmRefs []vimTypes.ManagedObjectReference) (map[vimTypes.ManagedObjectReference]mo.VirtualMachine, error)
+	ConnectVirtualMachineDevice(ctx context.Context, vm *v1alpha2.VirtualMachine, deviceKey int32) error
+	DisconnectVirtualMachineDevice(ctx context.Context, vm *v1alpha2.VirtualMachine, deviceKey int32) error
+	DetachAndDeleteVirtualMachineDisk(ctx context.Context, vm *v1alpha2.VirtualMachine, diskKey int32, deleteFile bool) error
+	UpdateVirtualMachineTaskStatus(ctx context.Context, vm *v1alpha2.VirtualMachine) error
+	ReapplyVirtualMachineCustomization(ctx context.Context, vm *v1alpha2.VirtualMachine) error
+	ReconcileVirtualMachineResourcePool(ctx context.Context, vm *v1alpha2.VirtualMachine, move bool) (ResourcePoolMembership, error)
+	ValidateVMAgainstImage(ctx context.Context, namespace string, spec v1alpha2.VirtualMachineSpec, imageName string) (field.ErrorList, error)
 
 	CreateOrUpdateVirtualMachineSetResourcePolicy(ctx context.Context, resourcePolicy *v1alpha2.VirtualMachineSetResourcePolicy) error
 	IsVirtualMachineSetResourcePolicyReady(ctx context.Context, availabilityZoneName string, resourcePolicy *v1alpha2.VirtualMachineSetResourcePolicy) (bool, error)
@@ -33,10 +57,137 @@ type VirtualMachineProviderInterfaceA2 interface {
 	UpdateVcPNID(ctx context.Context, vcPNID, vcPort string) error
 	ResetVcClient(ctx context.Context)
 	ComputeCPUMinFrequency(ctx context.Context) error
+	GetClusterSettings(ctx context.Context) ([]ClusterSettings, error)
+	ResolveClusterForRP(ctx context.Context, rpMoID string) (vimTypes.ManagedObjectReference, error)
+	GetImageZoneCompatibility(ctx context.Context, imageStatus *v1alpha2.VirtualMachineImageStatus, zoneName string) ([]ZoneCompatibility, error)
+	GetMissingPrivileges(ctx context.Context) (map[vimTypes.ManagedObjectReference][]string, error)
+	EvacuateHost(ctx context.Context, hostMoID string) ([]HostEvacuationResult, error)
+	ListManagedVirtualMachines(ctx context.Context) ([]ManagedVirtualMachine, error)
+	DeleteOrphanedVirtualMachine(ctx context.Context, vmMoID string) error
 
+	ResolveImage(ctx context.Context, imageName, namespace string) (ResolvedImage, error)
+	CacheImage(ctx context.Context, imageName, namespace, datastoreMoID string) error
+	ListContentLibraries(ctx context.Context) ([]library.Library, error)
+	DoesContentLibraryExist(ctx context.Context, clUUID string) (bool, error)
 	GetItemFromLibraryByName(ctx context.Context, contentLibrary, itemName string) (*library.Item, error)
 	UpdateContentLibraryItem(ctx context.Context, itemID, newName string, newDescription *string) error
 	SyncVirtualMachineImage(ctx context.Context, cli, vmi client.Object) error
 
 	GetTasksByActID(ctx context.Context, actID string) (tasksInfo []vimTypes.TaskInfo, retErr error)
 }
+
+// ResolvedImage is the content library item a VirtualMachineImage was created from.
+type ResolvedImage struct {
+	LibraryID string
+	ItemID    string
+	// Storage is the storage backing of the item's content library.
+	Storage []library.StorageBackings
+}
+
+// HotPlug is whether CPUs and memory can be added to, and CPUs removed from, a powered on VM.
+type HotPlug struct {
+	CPUHotAdd    bool
+	CPUHotRemove bool
+	MemoryHotAdd bool
+}
+
+// ResourcePoolMembership is whether a VM is in the ResourcePool dictated by its namespace, zone,
+// and VirtualMachineSetResourcePolicy.
+type ResourcePoolMembership struct {
+	// ResourcePool is the VM's parent ResourcePool.
+	ResourcePool vimTypes.ManagedObjectReference
+	// ExpectedResourcePool is the ResourcePool in the VM's cluster that the VM is expected to be in.
+	// It is unset when none of the expected ResourcePools are in the VM's cluster.
+	ExpectedResourcePool vimTypes.ManagedObjectReference
+	// Diverged is true when the VM's parent ResourcePool is not one of the expected ResourcePools.
+	Diverged bool
+	// Moved is true when the VM was moved into the ExpectedResourcePool.
+	Moved bool
+}
+
+// VirtualMachineIdentity is the vSphere identity of a VM that the CSI driver attaches volumes to
+// the VM with. The same values are reported in the VM's status as UniqueID, BiosUUID, and
+// InstanceUUID.
+type VirtualMachineIdentity struct {
+	// VM is the VM's managed object reference.
+	VM vimTypes.ManagedObjectReference
+	// BiosUUID is the node UUID of the VM's CnsNodeVmAttachments.
+	BiosUUID     string
+	InstanceUUID string
+}
+
+// ClusterSettings is the DRS and vSphere HA configuration of a vSphere cluster.
+type ClusterSettings struct {
+	ClusterMoID string
+	DRSEnabled  bool
+	// DRSAutomationLevel is the default DRS automation level of the cluster's VMs,
+	// ex. "fullyAutomated".
+	DRSAutomationLevel vimTypes.DrsBehavior
+	HAEnabled          bool
+}
+
+// ZoneCompatibility is whether the vSphere clusters of an availability zone can create VMs with
+// the hardware version that an image requires.
+type ZoneCompatibility struct {
+	ZoneName   string
+	Compatible bool
+	// IncompatibleClusterMoIDs are the zone's clusters that cannot create VMs with the hardware version.
+	IncompatibleClusterMoIDs []string
+}
+
+// HostEvacuationResult is the result of relocating a VM off of a host that is being evacuated.
+type HostEvacuationResult struct {
+	VM vimTypes.ManagedObjectReference
+	// Host is the host the VM was relocated to. It is unset when Err is set.
+	Host vimTypes.ManagedObjectReference
+	Err  error
+}
+
+// ManagedVirtualMachine is a vSphere VM that VM Operator created for a VirtualMachine CR.
+type ManagedVirtualMachine struct {
+	VM   vimTypes.ManagedObjectReference
+	Name string
+	// Namespace and VMName are of the VirtualMachine CR the vSphere VM was created for.
+	Namespace string
+	VMName    string
+	// DestroyTime is when the deletion grace period of the VirtualMachine CR expires, or the zero
+	// time when the CR was not deleted with a grace period.
+	DestroyTime time.Time
+}
+
+// DiagnosticCheck is a signal that is checked to diagnose why a VM is not ready.
+type DiagnosticCheck string
+
+const (
+	DiagnosticCheckImage      DiagnosticCheck = "Image"
+	DiagnosticCheckPlacement  DiagnosticCheck = "Placement"
+	DiagnosticCheckNetwork    DiagnosticCheck = "Network"
+	DiagnosticCheckTask       DiagnosticCheck = "Task"
+	DiagnosticCheckPowerState DiagnosticCheck = "PowerState"
+)
+
+// DiagnosticResult is the result of checking one of the signals of a VM's readiness.
+type DiagnosticResult struct {
+	Check DiagnosticCheck
+	Ready bool
+	// Reason and Message describe why the signal is not ready. They are unset when Ready is true.
+	Reason  string
+	Message string
+}
+
+// VirtualMachineDiagnostic is the result of checking each of the signals of a VM's readiness,
+// in the order that the signals block the VM.
+type VirtualMachineDiagnostic struct {
+	Results []DiagnosticResult
+}
+
+// Blocking returns the first result that is not ready, or nil if none of the signals are
+// blocking the VM.
+func (d VirtualMachineDiagnostic) Blocking() *DiagnosticResult {
+	for i := range d.Results {
+		if !d.Results[i].Ready {
+			return &d.Results[i]
+		}
+	}
+	return nil
+}
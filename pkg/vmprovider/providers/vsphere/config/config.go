@@ -61,6 +61,9 @@ const (
 	caFilePathKey            = "CAFilePath"
 	ContentSourceKey         = "ContentSource"
 
+	// ContentSourceBindingGCStrategyKey is how the TKG ContentSourceBindings are garbage collected.
+	ContentSourceBindingGCStrategyKey = "ContentSourceBindingGCStrategy"
+
 	NetworkConfigMapName = "vmoperator-network-config"
 	NameserversKey       = "nameservers"    // Key in the NetworkConfigMapName.
 	SearchSuffixesKey    = "searchsuffixes" // Key in the NetworkConfigMapName.
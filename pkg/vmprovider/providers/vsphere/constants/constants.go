@@ -83,6 +83,9 @@ const (
 	InstanceStorageSelectedNodeMOIDAnnotationKey = "vmoperator.vmware.com/instance-storage-selected-node-moid"
 	// InstanceStorageSelectedNodeAnnotationKey value corresponds to FQDN of ESXi node that is elected to place instance storage volumes.
 	InstanceStorageSelectedNodeAnnotationKey = "vmoperator.vmware.com/instance-storage-selected-node"
+	// InstanceStorageFailedNodeMOIDsAnnotationKey value is the comma separated MOIDs of the ESXi nodes that instance
+	// storage volumes failed to be placed on. These nodes are excluded when placement selects another node.
+	InstanceStorageFailedNodeMOIDsAnnotationKey = "vmoperator.vmware.com/instance-storage-failed-node-moids"
 	// KubernetesSelectedNodeAnnotationKey annotation key to set selected node on PVC.
 	KubernetesSelectedNodeAnnotationKey = "volume.kubernetes.io/selected-node"
 	// InstanceStoragePVPlacementErrorPrefix indicates prefix of error value.
@@ -27,6 +27,9 @@ import (
 )
 
 type Provider interface {
+	GetLibrary(ctx context.Context, libraryUUID string) (*library.Library, error)
+	DoesLibraryExist(ctx context.Context, libraryUUID string) (bool, error)
+	SyncLibrary(ctx context.Context, libraryUUID string) error
 	GetLibraryItems(ctx context.Context, clUUID string) ([]library.Item, error)
 	GetLibraryItem(ctx context.Context, libraryUUID, itemName string,
 		notFoundReturnErr bool) (*library.Item, error)
@@ -80,6 +83,49 @@ func NewProviderWithWaitSec(restClient *rest.Client, waitSeconds int) Provider {
 	}
 }
 
+func (cs *provider) GetLibrary(ctx context.Context, libraryUUID string) (*library.Library, error) {
+	cl, err := cs.libMgr.GetLibraryByID(ctx, libraryUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get library: %s", libraryUUID)
+	}
+
+	return cl, nil
+}
+
+// DoesLibraryExist returns true if the content library with the UUID exists.
+func (cs *provider) DoesLibraryExist(ctx context.Context, libraryUUID string) (bool, error) {
+	if _, err := cs.libMgr.GetLibraryByID(ctx, libraryUUID); err != nil {
+		if lib.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SyncLibrary triggers a sync of a subscribed content library with its publisher. This is a no-op for
+// a library that is not subscribed since only subscribed libraries have something to sync with.
+func (cs *provider) SyncLibrary(ctx context.Context, libraryUUID string) error {
+	logger := log.WithValues("libraryUUID", libraryUUID)
+
+	cl, err := cs.GetLibrary(ctx, libraryUUID)
+	if err != nil {
+		return err
+	}
+
+	if cl.Type != "SUBSCRIBED" {
+		logger.V(4).Info("Skipping sync of content library that is not subscribed", "type", cl.Type)
+		return nil
+	}
+
+	logger.Info("Syncing subscribed content library")
+	if err := cs.libMgr.SyncLibrary(ctx, cl); err != nil {
+		return errors.Wrapf(err, "failed to sync library: %s", libraryUUID)
+	}
+
+	return nil
+}
+
 func (cs *provider) ListLibraryItems(ctx context.Context, libraryUUID string) ([]string, error) {
 	logger := log.WithValues("libraryUUID", libraryUUID)
 	itemList, err := cs.libMgr.ListLibraryItems(ctx, libraryUUID)
@@ -46,8 +46,52 @@ func clTests() {
 			initObjects = nil
 		})
 
+		Context("SyncLibrary", func() {
+
+			BeforeEach(func() {
+				testConfig.WithSubscribedContentLibrary = true
+			})
+
+			It("Triggers a sync of a subscribed library", func() {
+				Expect(ctx.ContentLibraryLastSyncTime(ctx.SubscribedContentLibraryID)).To(BeNil())
+				Expect(clProvider.SyncLibrary(ctx, ctx.SubscribedContentLibraryID)).To(Succeed())
+				Expect(ctx.ContentLibraryLastSyncTime(ctx.SubscribedContentLibraryID)).ToNot(BeNil())
+			})
+
+			It("Does not return error for a local library", func() {
+				Expect(clProvider.SyncLibrary(ctx, ctx.ContentLibraryID)).To(Succeed())
+				Expect(ctx.ContentLibraryLastSyncTime(ctx.ContentLibraryID)).To(BeNil())
+			})
+
+			It("Returns error when library does not exist", func() {
+				Expect(clProvider.SyncLibrary(ctx, "dummy-cl")).ToNot(Succeed())
+			})
+		})
+
 		Context("when items are present in library", func() {
 
+			It("Gets the library", func() {
+				cl, err := clProvider.GetLibrary(ctx, ctx.ContentLibraryID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cl).ToNot(BeNil())
+				Expect(cl.ID).To(Equal(ctx.ContentLibraryID))
+				By("local library has never synced", func() {
+					Expect(cl.LastSyncTime).To(BeNil())
+				})
+			})
+
+			It("Library exists", func() {
+				exists, err := clProvider.DoesLibraryExist(ctx, ctx.ContentLibraryID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeTrue())
+			})
+
+			It("Library does not exist", func() {
+				exists, err := clProvider.DoesLibraryExist(ctx, "dummy-cl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeFalse())
+			})
+
 			It("List items id in library", func() {
 				items, err := clProvider.GetLibraryItems(ctx, ctx.ContentLibraryID)
 				Expect(err).ToNot(HaveOccurred())
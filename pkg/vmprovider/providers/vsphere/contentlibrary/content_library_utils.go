@@ -149,7 +149,7 @@ func updateImageSpecWithOvfVirtualSystem(imageSpec *vmopv1.VirtualMachineImageSp
 	if virtualHwSection := ovfVirtualSystem.VirtualHardware; len(virtualHwSection) > 0 {
 		hw := virtualHwSection[0]
 		if hw.System != nil && hw.System.VirtualSystemType != nil {
-			hwVersion = util.ParseVirtualHardwareVersion(*hw.System.VirtualSystemType)
+			hwVersion = util.ParseOvfVirtualHardwareVersion(*hw.System.VirtualSystemType)
 		}
 	}
 
@@ -181,6 +181,42 @@ func (vs *vSphereVMProvider) clearAndLogoutVcClient(ctx goctx.Context) {
 	}
 }
 
+// DoesContentLibraryExist checks if a content library with the UUID exists.
+func (vs *vSphereVMProvider) DoesContentLibraryExist(ctx goctx.Context, clUUID string) (bool, error) {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return client.ContentLibClient().DoesLibraryExist(ctx, clUUID)
+}
+
+// GetContentLibraryLastSyncTime returns when a subscribed content library last synced with its
+// publisher. Nil is returned for a library that has never synced, such as a local library.
+func (vs *vSphereVMProvider) GetContentLibraryLastSyncTime(ctx goctx.Context, clUUID string) (*time.Time, error) {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := client.ContentLibClient().GetLibrary(ctx, clUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.LastSyncTime, nil
+}
+
+// SyncContentLibrary triggers a sync of a subscribed content library. This is a no-op for a local library.
+func (vs *vSphereVMProvider) SyncContentLibrary(ctx goctx.Context, clUUID string) error {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.ContentLibClient().SyncLibrary(ctx, clUUID)
+}
+
 // ListItemsFromContentLibrary list items from a content library.
 func (vs *vSphereVMProvider) ListItemsFromContentLibrary(
 	ctx goctx.Context,
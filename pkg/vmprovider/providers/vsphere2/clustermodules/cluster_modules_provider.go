@@ -17,6 +17,7 @@ type Provider interface {
 	CreateModule(ctx context.Context, clusterRef types.ManagedObjectReference) (string, error)
 	DeleteModule(ctx context.Context, moduleID string) error
 	DoesModuleExist(ctx context.Context, moduleID string, cluster types.ManagedObjectReference) (bool, error)
+	ListModuleMembers(ctx context.Context, clusterRef types.ManagedObjectReference) (map[string][]types.ManagedObjectReference, error)
 
 	IsMoRefModuleMember(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) (bool, error)
 	AddMoRefToModule(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) error
@@ -80,6 +81,32 @@ func (cm *provider) DoesModuleExist(ctx context.Context, moduleID string, cluste
 	return false, nil
 }
 
+// ListModuleMembers returns the members of each of the cluster's modules, keyed by the module ID.
+func (cm *provider) ListModuleMembers(ctx context.Context, clusterRef types.ManagedObjectReference) (map[string][]types.ManagedObjectReference, error) {
+	modules, err := cm.manager.ListModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleMembers := map[string][]types.ManagedObjectReference{}
+	for _, mod := range modules {
+		if mod.Cluster != clusterRef.Value {
+			continue
+		}
+
+		members, err := cm.manager.ListModuleMembers(ctx, mod.Module)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range members {
+			moduleMembers[mod.Module] = append(moduleMembers[mod.Module], member.Reference())
+		}
+	}
+
+	return moduleMembers, nil
+}
+
 func (cm *provider) IsMoRefModuleMember(ctx context.Context, moduleID string, moRef types.ManagedObjectReference) (bool, error) {
 	moduleMembers, err := cm.manager.ListModuleMembers(ctx, moduleID)
 	if err != nil {
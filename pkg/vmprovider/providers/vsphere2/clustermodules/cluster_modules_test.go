@@ -89,6 +89,11 @@ func cmTests() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(isMember).To(BeTrue())
 
+				By("Verify cluster module members")
+				moduleMembers, err := cmProvider.ListModuleMembers(ctx, clusterRef)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(moduleMembers).To(HaveKeyWithValue(moduleStatus.ModuleUuid, ConsistOf(vmRef)))
+
 				By("Remove the association")
 				err = cmProvider.RemoveMoRefFromModule(ctx, moduleStatus.ModuleUuid, vmRef)
 				Expect(err).NotTo(HaveOccurred())
@@ -21,6 +21,58 @@ import (
 
 var log = logf.Log.WithName("vsphere").WithName("config")
 
+// supportedEthernetCardTypes are the allowed values of the DefaultEthernetCardType key.
+var supportedEthernetCardTypes = map[string]struct{}{
+	"vmxnet3": {},
+	"e1000e":  {},
+}
+
+// ImageNameResolution is whether and how a VM's image name that is not the name of an image
+// resource is resolved as the name of the content library item an image was created from.
+type ImageNameResolution string
+
+const (
+	// ImageNameResolutionErrorOnAmbiguous fails to resolve a name that matches more than one image.
+	ImageNameResolutionErrorOnAmbiguous ImageNameResolution = "error-on-ambiguous"
+	// ImageNameResolutionPreferBindingOrder resolves a name that matches more than one image to the
+	// image that was bound first: namespace images are preferred over cluster images, and then
+	// older images over newer ones.
+	ImageNameResolutionPreferBindingOrder ImageNameResolution = "prefer-binding-order"
+	// ImageNameResolutionRequireFullyQualified only resolves the name of an image resource. This is
+	// the default.
+	ImageNameResolutionRequireFullyQualified ImageNameResolution = "require-fully-qualified"
+)
+
+// ResolvesItemNames returns true if a name that is not the name of an image resource is resolved
+// as the name of a content library item.
+func (r ImageNameResolution) ResolvesItemNames() bool {
+	return r == ImageNameResolutionErrorOnAmbiguous || r == ImageNameResolutionPreferBindingOrder
+}
+
+// supportedImageNameResolutions are the allowed values of the ImageNameResolution key.
+var supportedImageNameResolutions = map[ImageNameResolution]struct{}{
+	ImageNameResolutionErrorOnAmbiguous:      {},
+	ImageNameResolutionPreferBindingOrder:    {},
+	ImageNameResolutionRequireFullyQualified: {},
+}
+
+// AdoptedVMPlacement is where an adopted VM, a VM that is not in its namespace's Folder such as
+// a VM that predates the operator, is placed in the vCenter inventory.
+type AdoptedVMPlacement string
+
+const (
+	// AdoptedVMPlacementLeaveInPlace leaves an adopted VM in the Folder it is in.
+	AdoptedVMPlacementLeaveInPlace AdoptedVMPlacement = "leave-in-place"
+	// AdoptedVMPlacementMoveToFolder moves an adopted VM into the AdoptedVMFolder.
+	AdoptedVMPlacementMoveToFolder AdoptedVMPlacement = "move-to-folder"
+)
+
+// supportedAdoptedVMPlacements are the allowed values of the AdoptedVMPlacement key.
+var supportedAdoptedVMPlacements = map[AdoptedVMPlacement]struct{}{
+	AdoptedVMPlacementLeaveInPlace: {},
+	AdoptedVMPlacementMoveToFolder: {},
+}
+
 // VSphereVMProviderConfig represents the configuration for a Vsphere VM Provider instance.
 // Contains information enabling integration with a backend vSphere instance for VM management.
 type VSphereVMProviderConfig struct {
@@ -33,6 +85,30 @@ type VSphereVMProviderConfig struct {
 	CAFilePath                  string
 	InsecureSkipTLSVerify       bool // Always false in WCP env.
 
+	// DefaultEthernetCardType is the type of the ethernet card created for a VM network
+	// interface when nothing else specifies a type. Empty means the network default.
+	DefaultEthernetCardType string
+
+	// ImageNameResolution is how a VM's image name that is not the name of an image resource is
+	// resolved. Defaults to ImageNameResolutionRequireFullyQualified, so that resolving the name
+	// of a content library item must be opted into.
+	ImageNameResolution ImageNameResolution
+
+	// HardwareVersionUpgradeTarget is the hardware version that powered off VMs with a lower
+	// hardware version are upgraded to when reconciled. Zero means VMs are never upgraded.
+	HardwareVersionUpgradeTarget int32
+
+	// AdoptedVMPlacement is where adopted VMs are placed in the inventory. Defaults to
+	// AdoptedVMPlacementLeaveInPlace.
+	AdoptedVMPlacement AdoptedVMPlacement
+	// AdoptedVMFolder is the MoID of the Folder adopted VMs are moved into when the
+	// AdoptedVMPlacement is AdoptedVMPlacementMoveToFolder.
+	AdoptedVMFolder string
+
+	// ReadOnly is true when the provider only validates and reports on VMs, and does not
+	// make any changes to vCenter.
+	ReadOnly bool
+
 	// These are Zone and/or Namespace specific.
 	ResourcePool string
 	Folder       string
@@ -59,6 +135,12 @@ const (
 	useInventoryKey          = "UseInventoryAsContentSource"
 	insecureSkipTLSVerifyKey = "InsecureSkipTLSVerify"
 	caFilePathKey            = "CAFilePath"
+	ethCardTypeKey           = "DefaultEthernetCardType"
+	imageNameResolutionKey   = "ImageNameResolution"
+	hwVersionUpgradeKey      = "HardwareVersionUpgradeTarget"
+	adoptedVMPlacementKey    = "AdoptedVMPlacement"
+	adoptedVMFolderKey       = "AdoptedVMFolder"
+	readOnlyKey              = "ReadOnly"
 	ContentSourceKey         = "ContentSource"
 
 	NetworkConfigMapName = "vmoperator-network-config"
@@ -116,19 +198,73 @@ func ConfigMapToProviderConfig( //nolint: revive // Ignore linter error about st
 		caFilePath = ca
 	}
 
+	var ethCardType string
+	if t, ok := configMap.Data[ethCardTypeKey]; ok && t != "" {
+		ethCardType = strings.ToLower(t)
+		if _, ok := supportedEthernetCardTypes[ethCardType]; !ok {
+			return nil, errors.Errorf("unsupported value of DefaultEthernetCardType %q", t)
+		}
+	}
+
+	imageNameResolution := ImageNameResolutionRequireFullyQualified
+	if r, ok := configMap.Data[imageNameResolutionKey]; ok && r != "" {
+		imageNameResolution = ImageNameResolution(strings.ToLower(r))
+		if _, ok := supportedImageNameResolutions[imageNameResolution]; !ok {
+			return nil, errors.Errorf("unsupported value of ImageNameResolution %q", r)
+		}
+	}
+
+	var hwVersionUpgradeTarget int32
+	if v, ok := configMap.Data[hwVersionUpgradeKey]; ok && v != "" {
+		target, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || target <= 0 {
+			return nil, errors.Errorf("unsupported value of HardwareVersionUpgradeTarget %q", v)
+		}
+		hwVersionUpgradeTarget = int32(target)
+	}
+
+	adoptedVMPlacement := AdoptedVMPlacementLeaveInPlace
+	if p, ok := configMap.Data[adoptedVMPlacementKey]; ok && p != "" {
+		adoptedVMPlacement = AdoptedVMPlacement(strings.ToLower(p))
+		if _, ok := supportedAdoptedVMPlacements[adoptedVMPlacement]; !ok {
+			return nil, errors.Errorf("unsupported value of AdoptedVMPlacement %q", p)
+		}
+	}
+
+	adoptedVMFolder := configMap.Data[adoptedVMFolderKey]
+	if adoptedVMPlacement == AdoptedVMPlacementMoveToFolder && adoptedVMFolder == "" {
+		return nil, errors.Errorf("missing configMap data field AdoptedVMFolder required by AdoptedVMPlacement %q",
+			adoptedVMPlacement)
+	}
+
+	readOnly := false
+	if r, ok := configMap.Data[readOnlyKey]; ok {
+		var err error
+		readOnly, err = strconv.ParseBool(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse value of ReadOnly")
+		}
+	}
+
 	ret := &VSphereVMProviderConfig{
-		VcPNID:                      vcPNID,
-		VcPort:                      vcPort,
-		VcCreds:                     vcCreds,
-		Datacenter:                  configMap.Data[datacenterKey],
-		ResourcePool:                configMap.Data[resourcePoolKey],
-		Folder:                      configMap.Data[folderKey],
-		Datastore:                   configMap.Data[datastoreKey],
-		Network:                     configMap.Data[networkNameKey],
-		StorageClassRequired:        scRequired,
-		UseInventoryAsContentSource: useInventory,
-		InsecureSkipTLSVerify:       insecureSkipTLSVerify,
-		CAFilePath:                  caFilePath,
+		VcPNID:                       vcPNID,
+		VcPort:                       vcPort,
+		VcCreds:                      vcCreds,
+		Datacenter:                   configMap.Data[datacenterKey],
+		ResourcePool:                 configMap.Data[resourcePoolKey],
+		Folder:                       configMap.Data[folderKey],
+		Datastore:                    configMap.Data[datastoreKey],
+		Network:                      configMap.Data[networkNameKey],
+		StorageClassRequired:         scRequired,
+		UseInventoryAsContentSource:  useInventory,
+		InsecureSkipTLSVerify:        insecureSkipTLSVerify,
+		CAFilePath:                   caFilePath,
+		DefaultEthernetCardType:      ethCardType,
+		ImageNameResolution:          imageNameResolution,
+		HardwareVersionUpgradeTarget: hwVersionUpgradeTarget,
+		AdoptedVMPlacement:           adoptedVMPlacement,
+		AdoptedVMFolder:              adoptedVMFolder,
+		ReadOnly:                     readOnly,
 	}
 
 	return ret, nil
@@ -244,6 +380,9 @@ func setConfigMapData(configMap *corev1.ConfigMap, config *VSphereVMProviderConf
 	configMap.Data[useInventoryKey] = strconv.FormatBool(config.UseInventoryAsContentSource)
 	configMap.Data[caFilePathKey] = config.CAFilePath
 	configMap.Data[insecureSkipTLSVerifyKey] = strconv.FormatBool(config.InsecureSkipTLSVerify)
+	if config.DefaultEthernetCardType != "" {
+		configMap.Data[ethCardTypeKey] = config.DefaultEthernetCardType
+	}
 }
 
 // ProviderConfigToConfigMap returns the ConfigMap for the config.
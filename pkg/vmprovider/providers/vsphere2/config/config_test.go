@@ -164,6 +164,126 @@ var _ = Describe("ConfigMapToProviderConfig", func() {
 		})
 	})
 
+	Context("DefaultEthernetCardType", func() {
+		It("DefaultEthernetCardType is unset in configMap", func() {
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.DefaultEthernetCardType).To(BeEmpty())
+		})
+
+		It("DefaultEthernetCardType is set in configMap", func() {
+			configMap.Data["DefaultEthernetCardType"] = "E1000E"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.DefaultEthernetCardType).To(Equal("e1000e"))
+		})
+
+		It("DefaultEthernetCardType is not supported", func() {
+			configMap.Data["DefaultEthernetCardType"] = "pcnet32"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).To(MatchError(`unsupported value of DefaultEthernetCardType "pcnet32"`))
+			Expect(providerConfig).To(BeNil())
+		})
+	})
+
+	Context("ImageNameResolution", func() {
+		It("ImageNameResolution is unset in configMap", func() {
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.ImageNameResolution).To(Equal(config.ImageNameResolutionRequireFullyQualified))
+		})
+
+		It("ImageNameResolution is set in configMap", func() {
+			configMap.Data["ImageNameResolution"] = "Prefer-Binding-Order"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.ImageNameResolution).To(Equal(config.ImageNameResolutionPreferBindingOrder))
+		})
+
+		It("ImageNameResolution is not supported", func() {
+			configMap.Data["ImageNameResolution"] = "random"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).To(MatchError(`unsupported value of ImageNameResolution "random"`))
+			Expect(providerConfig).To(BeNil())
+		})
+	})
+
+	Context("HardwareVersionUpgradeTarget", func() {
+		It("HardwareVersionUpgradeTarget is unset in configMap", func() {
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.HardwareVersionUpgradeTarget).To(BeZero())
+		})
+
+		It("HardwareVersionUpgradeTarget is set in configMap", func() {
+			configMap.Data["HardwareVersionUpgradeTarget"] = "19"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.HardwareVersionUpgradeTarget).To(BeEquivalentTo(19))
+		})
+
+		It("HardwareVersionUpgradeTarget is not supported", func() {
+			configMap.Data["HardwareVersionUpgradeTarget"] = "vmx-19"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).To(MatchError(`unsupported value of HardwareVersionUpgradeTarget "vmx-19"`))
+			Expect(providerConfig).To(BeNil())
+		})
+	})
+
+	Context("AdoptedVMPlacement", func() {
+		It("AdoptedVMPlacement is unset in configMap", func() {
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.AdoptedVMPlacement).To(Equal(config.AdoptedVMPlacementLeaveInPlace))
+			Expect(providerConfig.AdoptedVMFolder).To(BeEmpty())
+		})
+
+		It("AdoptedVMPlacement and AdoptedVMFolder are set in configMap", func() {
+			configMap.Data["AdoptedVMPlacement"] = "Move-To-Folder"
+			configMap.Data["AdoptedVMFolder"] = "group-v42"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.AdoptedVMPlacement).To(Equal(config.AdoptedVMPlacementMoveToFolder))
+			Expect(providerConfig.AdoptedVMFolder).To(Equal("group-v42"))
+		})
+
+		It("AdoptedVMPlacement is not supported", func() {
+			configMap.Data["AdoptedVMPlacement"] = "random"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).To(MatchError(`unsupported value of AdoptedVMPlacement "random"`))
+			Expect(providerConfig).To(BeNil())
+		})
+
+		It("AdoptedVMFolder is required to move adopted VMs", func() {
+			configMap.Data["AdoptedVMPlacement"] = "move-to-folder"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).To(MatchError(`missing configMap data field AdoptedVMFolder required by AdoptedVMPlacement "move-to-folder"`))
+			Expect(providerConfig).To(BeNil())
+		})
+	})
+
+	Context("ReadOnly", func() {
+		It("ReadOnly is unset in configMap", func() {
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.ReadOnly).To(BeFalse())
+		})
+
+		It("ReadOnly is set in configMap", func() {
+			configMap.Data["ReadOnly"] = "true"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerConfig.ReadOnly).To(BeTrue())
+		})
+
+		It("ReadOnly is not a bool", func() {
+			configMap.Data["ReadOnly"] = "maybe"
+			providerConfig, err := config.ConfigMapToProviderConfig(configMap, providerCreds)
+			Expect(err).To(MatchError(ContainSubstring("unable to parse value of ReadOnly")))
+			Expect(providerConfig).To(BeNil())
+		})
+	})
+
 	Describe("Tests for TLS configuration", func() {
 
 		Context("when no TLS configuration is specified", func() {
@@ -15,11 +15,25 @@ const (
 
 	// VCVMAnnotation Annotation placed on the VM.
 	VCVMAnnotation = "Virtual Machine managed by the vSphere Virtual Machine service"
+	// VCVMReleasedAnnotation Annotation placed on the VM when it is orphaned on delete.
+	VCVMReleasedAnnotation = "Virtual Machine released by the vSphere Virtual Machine service"
+	// VCVMImageCacheAnnotation Annotation placed on the VM an image is cached to.
+	VCVMImageCacheAnnotation = "Image cached by the vSphere Virtual Machine service"
 
 	// VSphereCustomizationBypassKey Annotation to skip applying VMware Tools Guest Customization.
 	VSphereCustomizationBypassKey     = pkg.VMOperatorKey + "/vsphere-customization"
 	VSphereCustomizationBypassDisable = "disable"
 
+	// VSphereNetworkCustomizationKey Annotation to skip customizing the guest's network interfaces, for
+	// guests that manage their own networking. The interfaces are still created and backed, and the
+	// LinuxPrep and Sysprep customizations map each of them to DHCP.
+	VSphereNetworkCustomizationKey     = pkg.VMOperatorKey + "/vsphere-network-customization"
+	VSphereNetworkCustomizationDisable = "disable"
+
+	// ReapplyCustomizationAnnotation Annotation to re-apply the guest customization to an existing VM. The
+	// customization is issued the next time the VM is powered off, and the annotation is then removed.
+	ReapplyCustomizationAnnotation = pkg.VMOperatorKey + "/reapply-customization"
+
 	// VMOperatorV1Alpha1ExtraConfigKey Special ExtraConfig key for v1alpha1 images.
 	VMOperatorV1Alpha1ExtraConfigKey = "guestinfo.vmservice.defer-cloud-init"
 	VMOperatorV1Alpha1ConfigReady    = "ready"
@@ -29,6 +43,22 @@ const (
 	GOSCPendingExtraConfigKey          = "tools.deployPkg.fileName"
 	GOSCIgnoreToolsCheckExtraConfigKey = "vmware.tools.gosc.ignoretoolscheck"
 
+	// ImageCacheItemIDExtraConfigKey ExtraConfig key with the content library item ID of the image
+	// cached to a VM.
+	ImageCacheItemIDExtraConfigKey = "vmservice.imagecache.itemID"
+
+	// VMNamespacedNameExtraConfigKey ExtraConfig key with the namespace and name, "namespace/name", of
+	// the VirtualMachine CR that VM Operator created the VM for.
+	VMNamespacedNameExtraConfigKey = "vmservice.vm.namespacedName"
+
+	// VMDestroyTimeExtraConfigKey ExtraConfig key with the RFC3339 time after which the VM is destroyed,
+	// set on a VM that is kept powered off for the deletion grace period of its deleted VirtualMachine CR.
+	VMDestroyTimeExtraConfigKey = "vmservice.vm.destroyTime"
+
+	// BootDiskStorageProfileIDExtraConfigKey ExtraConfig key with the ID of the storage policy the VM's
+	// boot disk was placed on for the VirtualMachine's BootDiskStorageClass.
+	BootDiskStorageProfileIDExtraConfigKey = "vmservice.vm.bootDiskStorageProfileID"
+
 	// EnableDiskUUIDExtraConfigKey Enable UUID ExtraConfig key.
 	EnableDiskUUIDExtraConfigKey = "disk.enableUUID"
 
@@ -58,6 +88,31 @@ const (
 	// FirmwareOverrideAnnotation is the annotation key used for firmware override.
 	FirmwareOverrideAnnotation = pkg.VMOperatorKey + "/firmware"
 
+	// SyncLabelsAsTagsAnnotation is the annotation key with the comma separated keys of the VM's labels
+	// to sync to the vSphere VM as tags. Only the labels with a tag category configured by the
+	// administrator are synced, so keys without one are ignored. When the annotation is not set,
+	// all of the VM's labels with a configured tag category are synced.
+	SyncLabelsAsTagsAnnotation = pkg.VMOperatorKey + "/sync-labels-as-tags"
+
+	// DisplayNameAnnotation is the annotation key with the name of the vSphere VM in the vCenter
+	// inventory. When set, the vSphere VM is renamed to follow the annotation; the VM's name in
+	// Kubernetes is unchanged.
+	DisplayNameAnnotation = pkg.VMOperatorKey + "/display-name"
+
+	// DryRunReconfigureAnnotation is the annotation key that, when present on a VM, causes the VM's
+	// reconcile to record the reconfigure of the vSphere VM it would make in the VM's status
+	// instead of applying it.
+	DryRunReconfigureAnnotation = pkg.VMOperatorKey + "/dry-run-reconfigure"
+
+	// ReuseNetworkInterfacesAnnotation is the annotation key with the UID of a deleted VM with the
+	// same name whose retained network interfaces the VM reuses, ex. when the VM is recreated by a
+	// rolling update. Otherwise, the retained network interfaces are deleted and new ones created.
+	ReuseNetworkInterfacesAnnotation = pkg.VMOperatorKey + "/reuse-network-interfaces-of"
+
+	// CryptoKeyProviderAnnotation is the annotation key used to request the VM be encrypted with a
+	// key from the named crypto key provider.
+	CryptoKeyProviderAnnotation = pkg.VMOperatorKey + "/crypto-key-provider"
+
 	CloudInitTypeAnnotation         = pkg.VMOperatorKey + "/cloudinit-type"
 	CloudInitTypeValueCloudInitPrep = "cloudinitprep"
 	CloudInitTypeValueGuestInfo     = "guestinfo"
@@ -83,6 +138,9 @@ const (
 	InstanceStorageSelectedNodeMOIDAnnotationKey = "vmoperator.vmware.com/instance-storage-selected-node-moid"
 	// InstanceStorageSelectedNodeAnnotationKey value corresponds to FQDN of ESXi node that is elected to place instance storage volumes.
 	InstanceStorageSelectedNodeAnnotationKey = "vmoperator.vmware.com/instance-storage-selected-node"
+	// InstanceStorageFailedNodeMOIDsAnnotationKey value is the comma separated MOIDs of the ESXi nodes that instance
+	// storage volumes failed to be placed on. These nodes are excluded when placement selects another node.
+	InstanceStorageFailedNodeMOIDsAnnotationKey = "vmoperator.vmware.com/instance-storage-failed-node-moids"
 	// KubernetesSelectedNodeAnnotationKey annotation key to set selected node on PVC.
 	KubernetesSelectedNodeAnnotationKey = "volume.kubernetes.io/selected-node"
 	// InstanceStoragePVPlacementErrorPrefix indicates prefix of error value.
@@ -26,6 +26,8 @@ import (
 )
 
 type Provider interface {
+	ListLibraries(ctx context.Context) ([]library.Library, error)
+	DoesLibraryExist(ctx context.Context, libraryUUID string) (bool, error)
 	GetLibraryItems(ctx context.Context, clUUID string) ([]library.Item, error)
 	GetLibraryItem(ctx context.Context, libraryUUID, itemName string,
 		notFoundReturnErr bool) (*library.Item, error)
@@ -88,6 +90,27 @@ func (cs *provider) ListLibraryItems(ctx context.Context, libraryUUID string) ([
 	return itemList, err
 }
 
+// ListLibraries returns the content libraries that are visible to the vCenter user.
+func (cs *provider) ListLibraries(ctx context.Context) ([]library.Library, error) {
+	libraries, err := cs.libMgr.GetLibraries(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list libraries")
+	}
+
+	return libraries, nil
+}
+
+// DoesLibraryExist returns true if the content library with the UUID exists.
+func (cs *provider) DoesLibraryExist(ctx context.Context, libraryUUID string) (bool, error) {
+	if _, err := cs.libMgr.GetLibraryByID(ctx, libraryUUID); err != nil {
+		if lib.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (cs *provider) GetLibraryItems(ctx context.Context, libraryUUID string) ([]library.Item, error) {
 	logger := log.WithValues("libraryUUID", libraryUUID)
 	itemList, err := cs.libMgr.ListLibraryItems(ctx, libraryUUID)
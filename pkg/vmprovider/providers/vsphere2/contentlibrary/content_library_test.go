@@ -44,8 +44,40 @@ func clTests() {
 			initObjects = nil
 		})
 
+		Context("ListLibraries", func() {
+
+			It("Lists the library with its type and storage backing", func() {
+				libraries, err := clProvider.ListLibraries(ctx)
+				Expect(err).ToNot(HaveOccurred())
+
+				var cl *library.Library
+				for i := range libraries {
+					if libraries[i].ID == ctx.ContentLibraryID {
+						cl = &libraries[i]
+					}
+				}
+				Expect(cl).ToNot(BeNil())
+				Expect(cl.Name).ToNot(BeEmpty())
+				Expect(cl.Type).To(Equal("LOCAL"))
+				Expect(cl.Storage).To(HaveLen(1))
+				Expect(cl.Storage[0].Type).To(Equal("DATASTORE"))
+			})
+		})
+
 		Context("when items are present in library", func() {
 
+			It("Library exists", func() {
+				exists, err := clProvider.DoesLibraryExist(ctx, ctx.ContentLibraryID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeTrue())
+			})
+
+			It("Library does not exist", func() {
+				exists, err := clProvider.DoesLibraryExist(ctx, "dummy-cl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeFalse())
+			})
+
 			It("List items id in library", func() {
 				items, err := clProvider.GetLibraryItems(ctx, ctx.ContentLibraryID)
 				Expect(err).ToNot(HaveOccurred())
@@ -9,13 +9,33 @@ import (
 	"strings"
 
 	"github.com/vmware/govmomi/ovf"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 )
 
-var vmxRe = regexp.MustCompile(`vmx-(\d+)`)
+const (
+	// The CIM ResourceTypes of the virtual hardware items.
+	ovfProcessorResourceType       = 3
+	ovfMemoryResourceType          = 4
+	ovfEthernetAdapterResourceType = 10
+
+	// ovfMinBound is the bound of a virtual hardware item that describes a minimum.
+	ovfMinBound = "min"
+
+	// ovfRecommendedVMClassPropertyKey is the OVF property with the name of the
+	// VirtualMachineClass recommended for the image.
+	ovfRecommendedVMClassPropertyKey = "vmclass.image.vmoperator.vmware.com"
+)
+
+var (
+	vmxRe             = regexp.MustCompile(`vmx-(\d+)`)
+	allocationUnitsRe = regexp.MustCompile(`^byte\s*\*\s*2\^(\d+)$`)
+	frequencyUnitsRe  = regexp.MustCompile(`^hertz\s*\*\s*10\^(\d+)$`)
+)
 
 // ParseVirtualHardwareVersion parses the virtual hardware version
 // For eg. "vmx-15" returns 15.
@@ -50,6 +70,8 @@ func UpdateVmiWithOvfEnvelope(vmi client.Object, ovfEnvelope ovf.Envelope) {
 	if ovfEnvelope.VirtualSystem != nil {
 		initImageStatusFromOVFVirtualSystem(status, ovfEnvelope.VirtualSystem)
 	}
+	status.Disks = getDisks(ovfEnvelope)
+	status.RecommendedResources = getRecommendedResources(ovfEnvelope)
 }
 
 func initImageStatusFromOVFVirtualSystem(
@@ -84,9 +106,11 @@ func initImageStatusFromOVFVirtualSystem(
 	// Use hardware section info from the VM image, if one exists.
 	if virtualHW := ovfVirtualSystem.VirtualHardware; len(virtualHW) > 0 {
 		imageStatus.Firmware = getFirmwareType(virtualHW[0])
+		imageStatus.NetworkInterfaceTypes = getNetworkInterfaceTypes(virtualHW[0])
 
 		if sys := virtualHW[0].System; sys != nil && sys.VirtualSystemType != nil {
-			ver := ParseVirtualHardwareVersion(*sys.VirtualSystemType)
+			// Leave the HardwareVersion unset when the OVF does not declare one.
+			ver := util.ParseOvfVirtualHardwareVersion(*sys.VirtualSystemType)
 			if ver != 0 {
 				imageStatus.HardwareVersion = &ver
 			}
@@ -98,9 +122,10 @@ func initImageStatusFromOVFVirtualSystem(
 			// Only show user configurable properties
 			if prop.UserConfigurable != nil && *prop.UserConfigurable {
 				property := vmopv1.OVFProperty{
-					Key:     prop.Key,
-					Type:    prop.Type,
-					Default: prop.Default,
+					Key:      prop.Key,
+					Type:     prop.Type,
+					Default:  prop.Default,
+					Password: prop.Password != nil && *prop.Password,
 				}
 				imageStatus.OVFProperties = append(imageStatus.OVFProperties, property)
 			}
@@ -146,3 +171,178 @@ func getFirmwareType(hardware ovf.VirtualHardwareSection) string {
 	}
 	return ""
 }
+
+// getNetworkInterfaceTypes returns the lowercased types (eg: "e1000", "vmxnet3") of the ethernet adapters
+// present in the virtual hardware section of the OVF. The ResourceSubType of an adapter may list more
+// than one type, separated by spaces, when the guest supports any of them.
+func getNetworkInterfaceTypes(hardware ovf.VirtualHardwareSection) []string {
+	var types []string
+	seen := map[string]struct{}{}
+
+	for _, item := range hardware.Item {
+		if item.ResourceType == nil || *item.ResourceType != ovfEthernetAdapterResourceType {
+			continue
+		}
+		if item.ResourceSubType == nil {
+			continue
+		}
+
+		for _, t := range strings.Fields(strings.ToLower(*item.ResourceSubType)) {
+			if _, ok := seen[t]; !ok {
+				seen[t] = struct{}{}
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+// getRecommendedResources returns the minimum CPU and memory declared by the OVF, either in its
+// ResourceAllocationSection or by the virtual hardware items bound to "min", and the recommended
+// VirtualMachineClass name. The number of CPUs is the VirtualQuantity of a processor item, and its
+// Reservation is a frequency. Nil is returned when the OVF does not declare any of them.
+func getRecommendedResources(ovfEnvelope ovf.Envelope) *vmopv1.VirtualMachineImageRecommendedResources {
+	var items []ovf.ResourceAllocationSettingData
+	var vmClassName string
+
+	if ra := ovfEnvelope.ResourceAllocation; ra != nil {
+		items = append(items, ra.Item...)
+	}
+
+	if vs := ovfEnvelope.VirtualSystem; vs != nil {
+		for _, hardware := range vs.VirtualHardware {
+			for _, item := range hardware.Item {
+				if item.Bound != nil && *item.Bound == ovfMinBound {
+					items = append(items, item)
+				}
+			}
+		}
+
+		for _, product := range vs.Product {
+			for _, prop := range product.Property {
+				if prop.Key == ovfRecommendedVMClassPropertyKey && prop.Default != nil {
+					vmClassName = *prop.Default
+				}
+			}
+		}
+	}
+
+	recommended := &vmopv1.VirtualMachineImageRecommendedResources{
+		VMClassName: vmClassName,
+	}
+
+	for _, item := range items {
+		if item.ResourceType == nil {
+			continue
+		}
+
+		switch *item.ResourceType {
+		case ovfProcessorResourceType:
+			if item.VirtualQuantity != nil && *item.VirtualQuantity != 0 {
+				cpus := int64(*item.VirtualQuantity)
+				recommended.MinCPUs = &cpus
+			}
+			if item.Reservation != nil && *item.Reservation != 0 {
+				hertz := int64(*item.Reservation) * getFrequencyUnitsMultiplier(item.AllocationUnits, 1e6)
+				recommended.MinCPUReservation = resource.NewQuantity(hertz, resource.DecimalSI)
+			}
+		case ovfMemoryResourceType:
+			// A ResourceAllocationSection item may describe the minimum memory as a reservation.
+			quantity := uint64(0)
+			if item.VirtualQuantity != nil {
+				quantity = uint64(*item.VirtualQuantity)
+			} else if item.Reservation != nil {
+				quantity = *item.Reservation
+			}
+			if quantity != 0 {
+				bytes := int64(quantity) * getAllocationUnitsMultiplier(item.AllocationUnits, 1<<20)
+				recommended.MinMemory = resource.NewQuantity(bytes, resource.BinarySI)
+			}
+		}
+	}
+
+	if recommended.MinCPUs == nil && recommended.MinCPUReservation == nil && recommended.MinMemory == nil && recommended.VMClassName == "" {
+		return nil
+	}
+
+	return recommended
+}
+
+// getDisks returns the capacity and populated size of the disks in the DiskSection of the OVF.
+func getDisks(ovfEnvelope ovf.Envelope) []vmopv1.VirtualMachineImageDiskInfo {
+	if ovfEnvelope.Disk == nil {
+		return nil
+	}
+
+	var disks []vmopv1.VirtualMachineImageDiskInfo
+	for _, disk := range ovfEnvelope.Disk.Disks {
+		var diskInfo vmopv1.VirtualMachineImageDiskInfo
+
+		// The capacity may instead reference an OVF property, in which case it is omitted.
+		if capacity, err := strconv.ParseInt(disk.Capacity, 10, 64); err == nil {
+			bytes := capacity * getAllocationUnitsMultiplier(disk.CapacityAllocationUnits, 1)
+			diskInfo.Capacity = resource.NewQuantity(bytes, resource.BinarySI)
+		}
+		if disk.PopulatedSize != nil {
+			diskInfo.Size = resource.NewQuantity(int64(*disk.PopulatedSize), resource.BinarySI)
+		}
+
+		disks = append(disks, diskInfo)
+	}
+
+	return disks
+}
+
+// getAllocationUnitsMultiplier returns the number of bytes in the OVF allocation units, for
+// example "byte * 2^20", or the default when the units are not set.
+func getAllocationUnitsMultiplier(units *string, defaultMultiplier int64) int64 {
+	if units != nil {
+		u := strings.TrimSpace(strings.ToLower(*units))
+
+		switch u {
+		case "byte", "bytes":
+			return 1
+		case "kilobytes":
+			return 1 << 10
+		case "gigabytes":
+			return 1 << 30
+		}
+
+		if obj := allocationUnitsRe.FindStringSubmatch(u); len(obj) == 2 {
+			if exp, err := strconv.Atoi(obj[1]); err == nil && exp < 63 {
+				return 1 << exp
+			}
+		}
+	}
+
+	return defaultMultiplier
+}
+
+// getFrequencyUnitsMultiplier returns the number of hertz in the OVF allocation units, for example
+// "hertz * 10^6", or the default when the units are not set.
+func getFrequencyUnitsMultiplier(units *string, defaultMultiplier int64) int64 {
+	if units != nil {
+		u := strings.TrimSpace(strings.ToLower(*units))
+
+		switch u {
+		case "hertz", "hz":
+			return 1
+		case "mhz":
+			return 1e6
+		case "ghz":
+			return 1e9
+		}
+
+		if obj := frequencyUnitsRe.FindStringSubmatch(u); len(obj) == 2 {
+			if exp, err := strconv.Atoi(obj[1]); err == nil && exp <= 18 {
+				multiplier := int64(1)
+				for i := 0; i < exp; i++ {
+					multiplier *= 10
+				}
+				return multiplier
+			}
+		}
+	}
+
+	return defaultMultiplier
+}
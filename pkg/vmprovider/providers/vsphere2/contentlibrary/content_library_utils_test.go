@@ -4,10 +4,13 @@
 package contentlibrary_test
 
 import (
+	"os"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/vmware/govmomi/ovf"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2"
@@ -37,6 +40,7 @@ var _ = Describe("UpdateVmiWithOvfEnvelope", func() {
 		ovfStringType          = "string"
 		userConfigurableKey    = "dummy-key-configurable"
 		notUserConfigurableKey = "dummy-key-not-configurable"
+		passwordKey            = "dummy-key-password"
 		defaultValue           = "dummy-value"
 		versionKey             = "vmware-system.tkr.os-version"
 		versionVal             = "1.15"
@@ -48,6 +52,8 @@ var _ = Describe("UpdateVmiWithOvfEnvelope", func() {
 	)
 
 	BeforeEach(func() {
+		ethernetResourceType := uint16(10)
+
 		ovfEnvelope = ovf.Envelope{
 			VirtualSystem: &ovf.VirtualSystem{
 				Product: []ovf.ProductSection{
@@ -80,6 +86,12 @@ var _ = Describe("UpdateVmiWithOvfEnvelope", func() {
 								Default:          pointer.String(defaultValue),
 								UserConfigurable: pointer.Bool(false),
 							},
+							{
+								Key:              passwordKey,
+								Type:             ovfStringType,
+								UserConfigurable: pointer.Bool(true),
+								Password:         pointer.Bool(true),
+							},
 						},
 					},
 				},
@@ -104,6 +116,23 @@ var _ = Describe("UpdateVmiWithOvfEnvelope", func() {
 								VirtualSystemType: pointer.String("vmx-10"),
 							},
 						},
+
+						Item: []ovf.ResourceAllocationSettingData{
+							{
+								CIMResourceAllocationSettingData: ovf.CIMResourceAllocationSettingData{
+									ElementName:     "ethernet0",
+									ResourceType:    &ethernetResourceType,
+									ResourceSubType: pointer.String("E1000"),
+								},
+							},
+							{
+								CIMResourceAllocationSettingData: ovf.CIMResourceAllocationSettingData{
+									ElementName:     "ethernet1",
+									ResourceType:    &ethernetResourceType,
+									ResourceSubType: pointer.String("VmxNet3 E1000"),
+								},
+							},
+						},
 					},
 				},
 			},
@@ -136,14 +165,107 @@ var _ = Describe("UpdateVmiWithOvfEnvelope", func() {
 
 		Expect(image.Status.HardwareVersion).Should(Equal(pointer.Int32(10)))
 		Expect(image.Status.Firmware).Should(Equal("efi"))
+		Expect(image.Status.NetworkInterfaceTypes).Should(Equal([]string{"e1000", "vmxnet3"}))
 
-		Expect(image.Status.OVFProperties).Should(HaveLen(1))
+		Expect(image.Status.OVFProperties).Should(HaveLen(2))
 		Expect(image.Status.OVFProperties[0].Key).Should(Equal(userConfigurableKey))
 		Expect(image.Status.OVFProperties[0].Type).Should(Equal(ovfStringType))
 		Expect(image.Status.OVFProperties[0].Default).Should(Equal(pointer.String(defaultValue)))
+		Expect(image.Status.OVFProperties[0].Password).Should(BeFalse())
+		Expect(image.Status.OVFProperties[1].Key).Should(Equal(passwordKey))
+		Expect(image.Status.OVFProperties[1].Password).Should(BeTrue())
 
 		Expect(image.Status.VMwareSystemProperties).Should(HaveLen(1))
 		Expect(image.Status.VMwareSystemProperties[0].Key).Should(Equal(versionKey))
 		Expect(image.Status.VMwareSystemProperties[0].Value).Should(Equal(versionVal))
+
+		Expect(image.Status.Disks).To(BeEmpty())
+		Expect(image.Status.RecommendedResources).To(BeNil())
+	})
+
+	Context("OVF declares multiple virtual system types", func() {
+		BeforeEach(func() {
+			ovfEnvelope.VirtualSystem.VirtualHardware[0].System.VirtualSystemType = pointer.String("vmx-13 vmx-10 vmx-19")
+		})
+
+		It("Image status should have the lowest hardware version", func() {
+			Expect(image.Status.HardwareVersion).To(Equal(pointer.Int32(10)))
+		})
+	})
+
+	Context("OVF does not declare a virtual system type", func() {
+		BeforeEach(func() {
+			ovfEnvelope.VirtualSystem.VirtualHardware[0].System = nil
+		})
+
+		It("Image status should not have a hardware version", func() {
+			Expect(image.Status.HardwareVersion).To(BeNil())
+		})
+	})
+
+	Context("OVF declares minimum resources", func() {
+		BeforeEach(func() {
+			f, err := os.Open("./testdata/ovf-with-minimums.ovf")
+			Expect(err).ToNot(HaveOccurred())
+			defer f.Close()
+
+			envelope, err := ovf.Unmarshal(f)
+			Expect(err).ToNot(HaveOccurred())
+			ovfEnvelope = *envelope
+		})
+
+		It("Image status should have the recommended resources", func() {
+			recommended := image.Status.RecommendedResources
+			Expect(recommended).ToNot(BeNil())
+			Expect(recommended.MinCPUs).To(Equal(pointer.Int64(2)))
+			Expect(recommended.MinCPUReservation).To(BeNil())
+			Expect(recommended.MinMemory).ToNot(BeNil())
+			Expect(recommended.MinMemory.Cmp(resource.MustParse("4Gi"))).To(BeZero())
+			Expect(recommended.VMClassName).To(Equal("best-effort-medium"))
+		})
+
+		It("Image status should have the disks", func() {
+			Expect(image.Status.Disks).To(HaveLen(2))
+			Expect(image.Status.Disks[0].Capacity.Cmp(resource.MustParse("30Gi"))).To(BeZero())
+			Expect(image.Status.Disks[0].Size.Value()).To(Equal(int64(18743296)))
+			Expect(image.Status.Disks[1].Capacity.Cmp(resource.MustParse("1Gi"))).To(BeZero())
+			Expect(image.Status.Disks[1].Size).To(BeNil())
+		})
+	})
+
+	Context("OVF declares minimum resources in the ResourceAllocationSection", func() {
+		BeforeEach(func() {
+			cpuResourceType, memoryResourceType := uint16(3), uint16(4)
+
+			ovfEnvelope.ResourceAllocation = &ovf.ResourceAllocationSection{
+				Item: []ovf.ResourceAllocationSettingData{
+					{
+						CIMResourceAllocationSettingData: ovf.CIMResourceAllocationSettingData{
+							ResourceType:    &cpuResourceType,
+							AllocationUnits: pointer.String("hertz * 10^6"),
+							VirtualQuantity: pointer.Uint(4),
+							Reservation:     pointer.Uint64(1500),
+						},
+					},
+					{
+						CIMResourceAllocationSettingData: ovf.CIMResourceAllocationSettingData{
+							ResourceType: &memoryResourceType,
+							Reservation:  pointer.Uint64(2048),
+						},
+					},
+				},
+			}
+		})
+
+		It("Image status should have the recommended resources", func() {
+			recommended := image.Status.RecommendedResources
+			Expect(recommended).ToNot(BeNil())
+			Expect(recommended.MinCPUs).To(Equal(pointer.Int64(4)))
+			Expect(recommended.MinCPUReservation).ToNot(BeNil())
+			Expect(recommended.MinCPUReservation.Cmp(resource.MustParse("1500M"))).To(BeZero())
+			Expect(recommended.MinMemory).ToNot(BeNil())
+			Expect(recommended.MinMemory.Cmp(resource.MustParse("2Gi"))).To(BeZero())
+			Expect(recommended.VMClassName).To(BeEmpty())
+		})
 	})
 })
@@ -9,6 +9,7 @@ import (
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 )
 
 // IsPresent checks if VM Spec has instance volumes added to its Volumes list.
@@ -39,3 +40,32 @@ func IsInsufficientQuota(err error) bool {
 	}
 	return false
 }
+
+// FailedHostMoIDs returns the MoIDs of the hosts that the VM's instance storage volumes failed
+// to be placed on.
+func FailedHostMoIDs(vm *vmopv1.VirtualMachine) []string {
+	value := vm.Annotations[constants.InstanceStorageFailedNodeMOIDsAnnotationKey]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// AddFailedHostMoID records that the VM's instance storage volumes failed to be placed on the
+// host, and returns the MoIDs of all the hosts the volumes failed to be placed on.
+func AddFailedHostMoID(vm *vmopv1.VirtualMachine, hostMoID string) []string {
+	hostMoIDs := FailedHostMoIDs(vm)
+	for _, moID := range hostMoIDs {
+		if moID == hostMoID {
+			return hostMoIDs
+		}
+	}
+
+	hostMoIDs = append(hostMoIDs, hostMoID)
+	if vm.Annotations == nil {
+		vm.Annotations = map[string]string{}
+	}
+	vm.Annotations[constants.InstanceStorageFailedNodeMOIDsAnnotationKey] = strings.Join(hostMoIDs, ",")
+
+	return hostMoIDs
+}
@@ -4,11 +4,20 @@
 package network
 
 import (
+	"fmt"
 	"strings"
 
+	"gopkg.in/yaml.v2"
+
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 )
 
+const (
+	defaultRouteIPv4       = "0.0.0.0/0"
+	defaultRouteIPv6       = "::/0"
+	defaultRouteMetricStep = 100
+)
+
 // Netplan representation described in https://via.vmw.com/cloud-init-netplan // FIXME: 404.
 type Netplan struct {
 	Version   int                        `yaml:"version,omitempty"`
@@ -16,16 +25,22 @@ type Netplan struct {
 }
 
 type NetplanEthernet struct {
-	Match       NetplanEthernetMatch      `yaml:"match,omitempty"`
-	SetName     string                    `yaml:"set-name,omitempty"`
-	Dhcp4       bool                      `yaml:"dhcp4,omitempty"`
-	Dhcp6       bool                      `yaml:"dhcp6,omitempty"`
-	Addresses   []string                  `yaml:"addresses,omitempty"`
-	Gateway4    string                    `yaml:"gateway4,omitempty"`
-	Gateway6    string                    `yaml:"gateway6,omitempty"`
-	MTU         int64                     `yaml:"mtu,omitempty"`
-	Nameservers NetplanEthernetNameserver `yaml:"nameservers,omitempty"`
-	Routes      []NetplanEthernetRoute    `yaml:"routes,omitempty"`
+	Match          NetplanEthernetMatch          `yaml:"match,omitempty"`
+	SetName        string                        `yaml:"set-name,omitempty"`
+	Dhcp4          bool                          `yaml:"dhcp4,omitempty"`
+	Dhcp6          bool                          `yaml:"dhcp6,omitempty"`
+	Dhcp4Overrides *NetplanEthernetDhcpOverrides `yaml:"dhcp4-overrides,omitempty"`
+	Dhcp6Overrides *NetplanEthernetDhcpOverrides `yaml:"dhcp6-overrides,omitempty"`
+	Addresses      []string                      `yaml:"addresses,omitempty"`
+	Gateway4       string                        `yaml:"gateway4,omitempty"`
+	Gateway6       string                        `yaml:"gateway6,omitempty"`
+	MTU            int64                         `yaml:"mtu,omitempty"`
+	Nameservers    NetplanEthernetNameserver     `yaml:"nameservers,omitempty"`
+	Routes         []NetplanEthernetRoute        `yaml:"routes,omitempty"`
+}
+
+type NetplanEthernetDhcpOverrides struct {
+	RouteMetric int32 `yaml:"route-metric,omitempty"`
 }
 
 type NetplanEthernetMatch struct {
@@ -50,50 +65,110 @@ func NetPlanCustomization(result NetworkInterfaceResults) (*Netplan, error) {
 	}
 
 	for _, r := range result.Results {
-		npEth := NetplanEthernet{
-			Match: NetplanEthernetMatch{
-				MacAddress: NormalizeNetplanMac(r.MacAddress),
-			},
-			SetName: r.Name,
-			MTU:     r.MTU,
-			Nameservers: NetplanEthernetNameserver{
-				Addresses: r.Nameservers,
-				Search:    r.SearchDomains,
-			},
+		npEth := netplanEthernet(r)
+		netPlan.Ethernets[npEth.SetName] = npEth
+	}
+
+	return netPlan, nil
+}
+
+// NetworkConfigV2 is a Cloud-Init network-config version 2 document, described in
+// https://cloudinit.readthedocs.io/en/latest/reference/network-config-format-v2.html.
+type NetworkConfigV2 struct {
+	Network Netplan `yaml:"network"`
+}
+
+// RenderNetworkConfigV2 renders the results as a Cloud-Init network-config version 2 YAML document.
+//
+// Unlike NetPlanCustomization, the gateways of each interface are rendered as default routes, so
+// that a multi-homed VM does not end up with conflicting gateways. The default routes, and the
+// routes learned via DHCP, of each interface after the first are given an increasing metric so
+// that the first interface remains the preferred one.
+func RenderNetworkConfigV2(result NetworkInterfaceResults) ([]byte, error) {
+	networkConfig := NetworkConfigV2{
+		Network: Netplan{
+			Version:   constants.NetPlanVersion,
+			Ethernets: make(map[string]NetplanEthernet),
+		},
+	}
+
+	for i, r := range result.Results {
+		npEth := netplanEthernet(r)
+		metric := int32(i) * defaultRouteMetricStep
+
+		var defaultRoutes []NetplanEthernetRoute
+		if npEth.Gateway4 != "" {
+			defaultRoutes = append(defaultRoutes, NetplanEthernetRoute{To: defaultRouteIPv4, Via: npEth.Gateway4, Metric: metric})
+			npEth.Gateway4 = ""
 		}
+		if npEth.Gateway6 != "" {
+			defaultRoutes = append(defaultRoutes, NetplanEthernetRoute{To: defaultRouteIPv6, Via: npEth.Gateway6, Metric: metric})
+			npEth.Gateway6 = ""
+		}
+		npEth.Routes = append(defaultRoutes, npEth.Routes...)
 
-		npEth.Dhcp4 = r.DHCP4
-		npEth.Dhcp6 = r.DHCP6
+		if metric != 0 {
+			if npEth.Dhcp4 {
+				npEth.Dhcp4Overrides = &NetplanEthernetDhcpOverrides{RouteMetric: metric}
+			}
+			if npEth.Dhcp6 {
+				npEth.Dhcp6Overrides = &NetplanEthernetDhcpOverrides{RouteMetric: metric}
+			}
+		}
 
-		if !npEth.Dhcp4 {
-			for _, ipConfig := range r.IPConfigs {
-				if ipConfig.IsIPv4 {
-					if npEth.Gateway4 == "" {
-						npEth.Gateway4 = ipConfig.Gateway
-					}
-					npEth.Addresses = append(npEth.Addresses, ipConfig.IPCIDR)
+		networkConfig.Network.Ethernets[npEth.SetName] = npEth
+	}
+
+	data, err := yaml.Marshal(networkConfig)
+	if err != nil {
+		return nil, fmt.Errorf("yaml marshalling of network-config failed: %w", err)
+	}
+
+	return data, nil
+}
+
+func netplanEthernet(r NetworkInterfaceResult) NetplanEthernet {
+	npEth := NetplanEthernet{
+		Match: NetplanEthernetMatch{
+			MacAddress: NormalizeNetplanMac(r.MacAddress),
+		},
+		SetName: r.Name,
+		MTU:     r.MTU,
+		Nameservers: NetplanEthernetNameserver{
+			Addresses: r.Nameservers,
+			Search:    r.SearchDomains,
+		},
+	}
+
+	npEth.Dhcp4 = r.DHCP4
+	npEth.Dhcp6 = r.DHCP6
+
+	if !npEth.Dhcp4 {
+		for _, ipConfig := range r.IPConfigs {
+			if ipConfig.IsIPv4 {
+				if npEth.Gateway4 == "" {
+					npEth.Gateway4 = ipConfig.Gateway
 				}
+				npEth.Addresses = append(npEth.Addresses, ipConfig.IPCIDR)
 			}
 		}
-		if !npEth.Dhcp6 {
-			for _, ipConfig := range r.IPConfigs {
-				if !ipConfig.IsIPv4 {
-					if npEth.Gateway6 == "" {
-						npEth.Gateway6 = ipConfig.Gateway
-					}
-					npEth.Addresses = append(npEth.Addresses, ipConfig.IPCIDR)
+	}
+	if !npEth.Dhcp6 {
+		for _, ipConfig := range r.IPConfigs {
+			if !ipConfig.IsIPv4 {
+				if npEth.Gateway6 == "" {
+					npEth.Gateway6 = ipConfig.Gateway
 				}
+				npEth.Addresses = append(npEth.Addresses, ipConfig.IPCIDR)
 			}
 		}
+	}
 
-		for _, route := range r.Routes {
-			npEth.Routes = append(npEth.Routes, NetplanEthernetRoute(route))
-		}
-
-		netPlan.Ethernets[npEth.SetName] = npEth
+	for _, route := range r.Routes {
+		npEth.Routes = append(npEth.Routes, NetplanEthernetRoute(route))
 	}
 
-	return netPlan, nil
+	return npEth
 }
 
 // NormalizeNetplanMac normalizes the mac address format to one compatible with netplan.
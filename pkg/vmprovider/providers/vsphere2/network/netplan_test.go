@@ -8,6 +8,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
@@ -140,4 +141,101 @@ var _ = Describe("Netplan", func() {
 			})
 		})
 	})
+
+	Context("RenderNetworkConfigV2", func() {
+		const (
+			ifName2      = "eth1"
+			macAddr2     = "50-8A-80-9D-28-23"
+			macAddr2Norm = "50:8a:80:9d:28:23"
+		)
+
+		var (
+			results       network.NetworkInterfaceResults
+			networkConfig network.NetworkConfigV2
+			err           error
+		)
+
+		BeforeEach(func() {
+			results.Results = []network.NetworkInterfaceResult{
+				{
+					IPConfigs: []network.NetworkInterfaceIPConfig{
+						{
+							IPCIDR:  ipv4CIDR,
+							IsIPv4:  true,
+							Gateway: ipv4Gateway,
+						},
+					},
+					MacAddress:    macAddr1,
+					Name:          ifName,
+					Nameservers:   []string{dnsServer1},
+					SearchDomains: []string{searchDomain1},
+					Routes: []network.NetworkInterfaceRoute{
+						{
+							To:     "185.107.56.59",
+							Via:    "10.1.1.1",
+							Metric: 42,
+						},
+					},
+				},
+				{
+					IPConfigs: []network.NetworkInterfaceIPConfig{
+						{
+							IPCIDR:  ipv6 + fmt.Sprintf("/%d", ipv6Subnet),
+							IsIPv4:  false,
+							Gateway: ipv6Gateway,
+						},
+					},
+					MacAddress: macAddr2,
+					Name:       ifName2,
+					DHCP4:      true,
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			var data []byte
+			data, err = network.RenderNetworkConfigV2(results)
+			Expect(err).ToNot(HaveOccurred())
+
+			networkConfig = network.NetworkConfigV2{}
+			Expect(yaml.Unmarshal(data, &networkConfig)).To(Succeed())
+		})
+
+		It("renders the gateways as default routes", func() {
+			Expect(networkConfig.Network.Version).To(Equal(constants.NetPlanVersion))
+			Expect(networkConfig.Network.Ethernets).To(HaveLen(2))
+			Expect(networkConfig.Network.Ethernets).To(HaveKey(ifName))
+
+			np := networkConfig.Network.Ethernets[ifName]
+			Expect(np.Match.MacAddress).To(Equal(macAddr1Norm))
+			Expect(np.SetName).To(Equal(ifName))
+			Expect(np.Dhcp4).To(BeFalse())
+			Expect(np.Dhcp4Overrides).To(BeNil())
+			Expect(np.Addresses).To(Equal([]string{ipv4CIDR}))
+			Expect(np.Gateway4).To(BeEmpty())
+			Expect(np.Nameservers.Addresses).To(Equal([]string{dnsServer1}))
+			Expect(np.Nameservers.Search).To(Equal([]string{searchDomain1}))
+			Expect(np.Routes).To(Equal([]network.NetplanEthernetRoute{
+				{To: "0.0.0.0/0", Via: ipv4Gateway},
+				{To: "185.107.56.59", Via: "10.1.1.1", Metric: 42},
+			}))
+		})
+
+		It("renders an interface with both DHCP4 and a static IPv6 address", func() {
+			Expect(networkConfig.Network.Ethernets).To(HaveKey(ifName2))
+
+			np := networkConfig.Network.Ethernets[ifName2]
+			Expect(np.Match.MacAddress).To(Equal(macAddr2Norm))
+			Expect(np.Dhcp4).To(BeTrue())
+			Expect(np.Dhcp6).To(BeFalse())
+			Expect(np.Addresses).To(Equal([]string{ipv6 + fmt.Sprintf("/%d", ipv6Subnet)}))
+			Expect(np.Gateway6).To(BeEmpty())
+			Expect(np.Routes).To(Equal([]network.NetplanEthernetRoute{
+				{To: "::/0", Via: ipv6Gateway, Metric: 100},
+			}))
+			Expect(np.Dhcp4Overrides).ToNot(BeNil())
+			Expect(np.Dhcp4Overrides.RouteMetric).To(BeEquivalentTo(100))
+			Expect(np.Dhcp6Overrides).To(BeNil())
+		})
+	})
 })
@@ -6,11 +6,13 @@ package network
 
 import (
 	goctx "context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25"
@@ -19,7 +21,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
 	ctrlruntime "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -29,6 +33,8 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	metrics "github.com/vmware-tanzu/vm-operator/pkg/metrics2"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 )
 
 type NetworkInterfaceResults struct {
@@ -52,6 +58,22 @@ type NetworkInterfaceResult struct {
 	Nameservers   []string
 	SearchDomains []string
 	Routes        []NetworkInterfaceRoute
+
+	// Timing is when the network provider's CR of the interface was created and became ready.
+	// It is not set for a named network since it does not have a CR.
+	Timing *NetworkInterfaceTiming
+}
+
+// NetworkInterfaceTiming is the readiness timing of the network provider's CR of an interface.
+type NetworkInterfaceTiming struct {
+	// ProviderType is the type of the network provider that reconciled the CR, ex. VSPHERE_NETWORK.
+	ProviderType string
+	// CreatedAt is when the CR was created.
+	CreatedAt time.Time
+	// ReadyAt is when the CR was observed to be ready.
+	ReadyAt time.Time
+	// Wait is how long CreateAndWaitForNetworkInterfaces waited for the CR to be ready.
+	Wait time.Duration
 }
 
 type NetworkInterfaceIPConfig struct {
@@ -66,6 +88,51 @@ type NetworkInterfaceRoute struct {
 	Metric int32
 }
 
+// NetworkInterfaceError is the error for a network interface whose network could not be
+// resolved into a backing.
+type NetworkInterfaceError struct {
+	// Name is the name of the network interface from the InterfaceSpec.
+	Name string
+	Err  error
+}
+
+func (e *NetworkInterfaceError) Error() string {
+	return fmt.Sprintf("network interface %q error: %v", e.Name, e.Err)
+}
+
+func (e *NetworkInterfaceError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkInterfaceErrors are the errors for the network interfaces that failed, when the
+// VM's other network interfaces may have succeeded.
+type NetworkInterfaceErrors []*NetworkInterfaceError
+
+func (e NetworkInterfaceErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e NetworkInterfaceErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// InterfaceNames returns the names of the network interfaces that failed.
+func (e NetworkInterfaceErrors) InterfaceNames() []string {
+	names := make([]string, 0, len(e))
+	for _, err := range e {
+		names = append(names, err.Name)
+	}
+	return names
+}
+
 const (
 	retryInterval           = 100 * time.Millisecond
 	defaultEthernetCardType = "vmxnet3"
@@ -74,6 +141,20 @@ const (
 var (
 	// RetryTimeout is var so tests can change it to shorten tests until we get rid of the poll.
 	RetryTimeout = 15 * time.Second
+
+	// Clock is var so tests can use a fake clock for the network interface timing.
+	Clock clock.PassiveClock = clock.RealClock{}
+)
+
+const (
+	// RetainedUntilAnnotation is the annotation on a network provider CR of a deleted VM with when
+	// the CR's grace period expires. The annotation is removed when the CR is reused.
+	RetainedUntilAnnotation = "vmoperator.vmware.com/network-interface-retained-until"
+
+	// RetainedForAnnotation is the annotation on a network provider CR of a deleted VM with the UID
+	// of the deleted VM. The CR is only reused by a VM with the same name that has the UID in its
+	// constants.ReuseNetworkInterfacesAnnotation. The annotation is removed when the CR is reused.
+	RetainedForAnnotation = "vmoperator.vmware.com/network-interface-retained-for"
 )
 
 // CreateAndWaitForNetworkInterfaces creates the appropriate CRs for the VM's network
@@ -108,48 +189,305 @@ func CreateAndWaitForNetworkInterfaces(
 	clusterMoRef *vimtypes.ManagedObjectReference,
 	interfaces []vmopv1.VirtualMachineNetworkInterfaceSpec) (NetworkInterfaceResults, error) {
 
+	return networkInterfaceResults(vmCtx, interfaces,
+		func(networkType string, interfaceSpec *vmopv1.VirtualMachineNetworkInterfaceSpec) (*NetworkInterfaceResult, error) {
+			switch networkType {
+			case lib.NetworkProviderTypeVDS:
+				return createNetOPNetworkInterface(vmCtx, client, vimClient, interfaceSpec)
+			case lib.NetworkProviderTypeNSXT:
+				return createNCPNetworkInterface(vmCtx, client, vimClient, clusterMoRef, interfaceSpec)
+			case lib.NetworkProviderTypeNamed:
+				return createNamedNetworkInterface(vmCtx, finder, interfaceSpec)
+			default:
+				return nil, fmt.Errorf("unsupported network provider envvar value: %q", networkType)
+			}
+		})
+}
+
+// GetNetworkInterfaces returns the results of the VM's existing network interface CRs like
+// CreateAndWaitForNetworkInterfaces but without creating, updating, or waiting for the CRs,
+// so it can be used to preview a reconfigure of the VM. An error is returned for the
+// interfaces whose CR does not exist or is not ready.
+func GetNetworkInterfaces(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	vimClient *vim25.Client,
+	finder *find.Finder,
+	clusterMoRef *vimtypes.ManagedObjectReference,
+	interfaces []vmopv1.VirtualMachineNetworkInterfaceSpec) (NetworkInterfaceResults, error) {
+
+	return networkInterfaceResults(vmCtx, interfaces,
+		func(networkType string, interfaceSpec *vmopv1.VirtualMachineNetworkInterfaceSpec) (*NetworkInterfaceResult, error) {
+			switch networkType {
+			case lib.NetworkProviderTypeVDS:
+				return getNetOPNetworkInterface(vmCtx, client, vimClient, interfaceSpec)
+			case lib.NetworkProviderTypeNSXT:
+				return getNCPNetworkInterface(vmCtx, client, vimClient, clusterMoRef, interfaceSpec)
+			case lib.NetworkProviderTypeNamed:
+				return createNamedNetworkInterface(vmCtx, finder, interfaceSpec)
+			default:
+				return nil, fmt.Errorf("unsupported network provider envvar value: %q", networkType)
+			}
+		})
+}
+
+func networkInterfaceResults(
+	vmCtx context.VirtualMachineContextA2,
+	interfaces []vmopv1.VirtualMachineNetworkInterfaceSpec,
+	interfaceResultFn func(string, *vmopv1.VirtualMachineNetworkInterfaceSpec) (*NetworkInterfaceResult, error)) (NetworkInterfaceResults, error) {
+
 	networkType := lib.GetNetworkProviderType()
 	if networkType == "" {
 		return NetworkInterfaceResults{}, fmt.Errorf("no network provider set")
 	}
 
+	if maxInterfaces := lib.GetMaxNetworkInterfacesPerVM(); len(interfaces) > maxInterfaces {
+		return NetworkInterfaceResults{}, fmt.Errorf("VM has %d network interfaces but at most %d are supported", len(interfaces), maxInterfaces)
+	}
+
 	results := make([]NetworkInterfaceResult, 0, len(interfaces))
+	var interfaceErrs NetworkInterfaceErrors
 
 	for i := range interfaces {
 		interfaceSpec := &interfaces[i]
 
-		var result *NetworkInterfaceResult
-		var err error
-
-		switch networkType {
-		case lib.NetworkProviderTypeVDS:
-			result, err = createNetOPNetworkInterface(vmCtx, client, vimClient, interfaceSpec)
-		case lib.NetworkProviderTypeNSXT:
-			result, err = createNCPNetworkInterface(vmCtx, client, vimClient, clusterMoRef, interfaceSpec)
-		case lib.NetworkProviderTypeNamed:
-			result, err = createNamedNetworkInterface(vmCtx, finder, interfaceSpec)
-		default:
-			err = fmt.Errorf("unsupported network provider envvar value: %q", networkType)
+		result, err := interfaceResultFn(networkType, interfaceSpec)
+		if err != nil {
+			// Keep going so every interface that failed is reported, not just the first one.
+			interfaceErrs = append(interfaceErrs, &NetworkInterfaceError{Name: interfaceSpec.Name, Err: err})
+			continue
 		}
 
-		if err != nil {
-			return NetworkInterfaceResults{},
-				fmt.Errorf("network interface %q error: %w", interfaceSpec.Name, err)
+		if timing := result.Timing; timing != nil {
+			vmCtx.Logger.V(4).Info("Network interface is ready", "name", interfaceSpec.Name,
+				"createdAt", timing.CreatedAt, "readyAt", timing.ReadyAt, "wait", timing.Wait)
+			metrics.NewVMNetworkMetrics().RegisterInterfaceReadyWait(timing.ProviderType, timing.Wait)
 		}
 
 		applyInterfaceSpecToResult(interfaceSpec, result)
 		results = append(results, *result)
 	}
 
-	// TODO: Once we really support network changing on the fly, we need to keep track of now
-	// unused network interface CRDs so they can be deleted after they're removed from the VM
-	// via Reconfigure, instead of delaying that until the VM is deleted via GC.
+	if len(interfaceErrs) > 0 {
+		// The results of the interfaces that succeeded are still returned so the caller can tell
+		// which of the interfaces failed.
+		return NetworkInterfaceResults{Results: results}, interfaceErrs
+	}
 
 	return NetworkInterfaceResults{
 		Results: results,
 	}, nil
 }
 
+// DeleteStaleNetworkInterfaces deletes the network provider CRs owned by the VM that are no longer
+// referenced by any of the VM's desired interfaces, like those of a network interface that was
+// removed from the VM. The CRs of the desired interfaces are identified by their deterministic
+// name from NetOPCRName or NCPCRName, including the v1a1 naming convention. The retained CRs of
+// the deleted VMs in the namespace whose grace period has expired are deleted as well.
+func DeleteStaleNetworkInterfaces(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	interfaces []vmopv1.VirtualMachineNetworkInterfaceSpec) error {
+
+	objects, crName, err := listNetworkInterfaces(vmCtx, client, ctrlruntime.InNamespace(vmCtx.VM.Namespace))
+	if err != nil || crName == nil {
+		return err
+	}
+
+	desiredNames := map[string]struct{}{}
+	for _, interfaceSpec := range interfaces {
+		desiredNames[crName(vmCtx.VM.Name, interfaceSpec.Network.Name, interfaceSpec.Name, true)] = struct{}{}
+		desiredNames[crName(vmCtx.VM.Name, interfaceSpec.Network.Name, interfaceSpec.Name, false)] = struct{}{}
+	}
+
+	var errs []error
+	for _, obj := range objects {
+		if _, ok := desiredNames[obj.GetName()]; ok || !isOwnedByVM(obj, vmCtx.VM) {
+			continue
+		}
+
+		vmCtx.Logger.Info("Deleting stale network interface", "name", obj.GetName())
+		if err := client.Delete(vmCtx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete stale network interface %s: %w", obj.GetName(), err))
+		}
+	}
+
+	errs = append(errs, deleteExpiredNetworkInterfaces(vmCtx, vmCtx.Logger, client, objects)...)
+
+	return k8serrors.NewAggregate(errs)
+}
+
+// RetainNetworkInterfaces removes the deleted VM's owner reference from its network provider CRs
+// so the CRs are not garbage collected with the VM, and annotates the CRs with the deleted VM's
+// UID and with when the grace period expires. If the VM is recreated with the same name and the
+// deleted VM's UID in its constants.ReuseNetworkInterfacesAnnotation within the grace period, its
+// CRs are found by their deterministic name and reused instead of churning the network provider.
+// Otherwise, the expired CRs are deleted by DeleteExpiredNetworkInterfaces, which the retained
+// network interface collector periodically runs for all namespaces.
+func RetainNetworkInterfaces(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	gracePeriod time.Duration) error {
+
+	objects, crName, err := listNetworkInterfaces(vmCtx, client, ctrlruntime.InNamespace(vmCtx.VM.Namespace))
+	if err != nil || crName == nil {
+		return err
+	}
+
+	retainedUntil := Clock.Now().Add(gracePeriod).UTC().Format(time.RFC3339)
+
+	var errs []error
+	for _, obj := range objects {
+		if !isOwnedByVM(obj, vmCtx.VM) {
+			continue
+		}
+
+		patch := ctrlruntime.MergeFromWithOptions(obj.DeepCopyObject().(ctrlruntime.Object), ctrlruntime.MergeFromWithOptimisticLock{})
+
+		var ownerRefs []metav1.OwnerReference
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.UID != vmCtx.VM.UID {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+		obj.SetOwnerReferences(ownerRefs)
+
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[RetainedUntilAnnotation] = retainedUntil
+		annotations[RetainedForAnnotation] = string(vmCtx.VM.UID)
+		obj.SetAnnotations(annotations)
+
+		vmCtx.Logger.Info("Retaining network interface of deleted VM", "name", obj.GetName(), "retainedUntil", retainedUntil)
+		if err := client.Patch(vmCtx, obj, patch); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to retain network interface %s: %w", obj.GetName(), err))
+		}
+	}
+
+	errs = append(errs, deleteExpiredNetworkInterfaces(vmCtx, vmCtx.Logger, client, objects)...)
+
+	return k8serrors.NewAggregate(errs)
+}
+
+// DeleteExpiredNetworkInterfaces deletes the retained network provider CRs in all namespaces whose
+// grace period has expired, so the CRs of a deleted VM are not leaked when no other VM in the
+// namespace is reconciled.
+func DeleteExpiredNetworkInterfaces(
+	ctx goctx.Context,
+	logger logr.Logger,
+	client ctrlruntime.Client) error {
+
+	objects, _, err := listNetworkInterfaces(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	return k8serrors.NewAggregate(deleteExpiredNetworkInterfaces(ctx, logger, client, objects))
+}
+
+// listNetworkInterfaces returns the network provider CRs, and the function that returns the
+// deterministic name of the CRs. The function is nil for the network providers that do not
+// have CRs.
+func listNetworkInterfaces(
+	ctx goctx.Context,
+	client ctrlruntime.Client,
+	opts ...ctrlruntime.ListOption) ([]ctrlruntime.Object, func(vmName, networkName, interfaceName string, isV1A1 bool) string, error) {
+
+	var objects []ctrlruntime.Object
+
+	switch lib.GetNetworkProviderType() {
+	case lib.NetworkProviderTypeVDS:
+		list := &netopv1alpha1.NetworkInterfaceList{}
+		if err := client.List(ctx, list, opts...); err != nil {
+			return nil, nil, err
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+		return objects, NetOPCRName, nil
+	case lib.NetworkProviderTypeNSXT:
+		list := &ncpv1alpha1.VirtualNetworkInterfaceList{}
+		if err := client.List(ctx, list, opts...); err != nil {
+			return nil, nil, err
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+		return objects, NCPCRName, nil
+	default:
+		// The other network providers do not have CRs.
+		return nil, nil, nil
+	}
+}
+
+// deleteExpiredNetworkInterfaces deletes the retained CRs whose grace period has expired and that
+// were not reused by a recreated VM.
+func deleteExpiredNetworkInterfaces(
+	ctx goctx.Context,
+	logger logr.Logger,
+	client ctrlruntime.Client,
+	objects []ctrlruntime.Object) []error {
+
+	now := Clock.Now()
+
+	var errs []error
+	for _, obj := range objects {
+		retainedUntil, ok := obj.GetAnnotations()[RetainedUntilAnnotation]
+		if !ok || len(obj.GetOwnerReferences()) != 0 {
+			continue
+		}
+
+		// A malformed annotation is treated as expired so the CR is not retained forever.
+		if t, err := time.Parse(time.RFC3339, retainedUntil); err == nil && now.Before(t) {
+			continue
+		}
+
+		logger.Info("Deleting retained network interface",
+			"name", obj.GetName(), "namespace", obj.GetNamespace(), "retainedUntil", retainedUntil)
+		if err := client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete retained network interface %s: %w", obj.GetName(), err))
+		}
+	}
+
+	return errs
+}
+
+// deleteNetworkInterfaceRetainedForOtherVM deletes the existing CR with the object's name if the CR
+// was retained for a deleted VM that the VM does not reuse the CRs of, so the VM does not inherit
+// the IPs of a deleted VM that had the same name. An error is returned until the CR is deleted.
+func deleteNetworkInterfaceRetainedForOtherVM(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	obj ctrlruntime.Object) error {
+
+	if err := client.Get(vmCtx, ctrlruntime.ObjectKeyFromObject(obj), obj); err != nil {
+		return ctrlruntime.IgnoreNotFound(err)
+	}
+
+	retainedFor, ok := obj.GetAnnotations()[RetainedForAnnotation]
+	if !ok || retainedFor == string(vmCtx.VM.UID) || retainedFor == vmCtx.VM.Annotations[constants.ReuseNetworkInterfacesAnnotation] {
+		return nil
+	}
+
+	vmCtx.Logger.Info("Deleting network interface retained for another VM", "name", obj.GetName(), "retainedFor", retainedFor)
+	if err := client.Delete(vmCtx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return fmt.Errorf("network interface %s retained for deleted VM %s is being deleted", obj.GetName(), retainedFor)
+}
+
+func isOwnedByVM(obj ctrlruntime.Object, vm *vmopv1.VirtualMachine) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == vm.UID && ref.Kind == "VirtualMachine" {
+			return true
+		}
+	}
+	return false
+}
+
 // applyInterfaceSpecToResult applies the InterfaceSpec to results. Much of the InterfaceSpec - like DHCP -
 // cannot be specified to the underlying network provider so apply those overrides to the results.
 func applyInterfaceSpecToResult(
@@ -219,7 +557,15 @@ func createNamedNetworkInterface(
 
 	backing, err := finder.Network(vmCtx, networkName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find named network %q: %w", networkName, err)
+		var notFoundErr *find.NotFoundError
+		if !lib.IsNamedNetworkCrossDatacenterLookupEnabled() || !errors.As(err, &notFoundErr) {
+			return nil, fmt.Errorf("unable to find named network %q: %w", networkName, err)
+		}
+
+		backing, err = findNamedNetworkInAnyDatacenter(vmCtx, finder, networkName)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &NetworkInterfaceResult{
@@ -228,6 +574,51 @@ func createNamedNetworkInterface(
 	}, nil
 }
 
+// findNamedNetworkInAnyDatacenter looks up the network in every datacenter, returning an error if
+// the network is not found in exactly one of them.
+func findNamedNetworkInAnyDatacenter(
+	vmCtx context.VirtualMachineContextA2,
+	finder *find.Finder,
+	networkName string) (object.NetworkReference, error) {
+
+	dcs, err := finder.DatacenterList(vmCtx, "*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list datacenters to find named network %q: %w", networkName, err)
+	}
+
+	var backing object.NetworkReference
+	var dcNames []string
+
+	for _, dc := range dcs {
+		dcFinder := find.NewFinder(dc.Client())
+		dcFinder.SetDatacenter(dc)
+
+		network, err := dcFinder.Network(vmCtx, networkName)
+		if err != nil {
+			var notFoundErr *find.NotFoundError
+			if errors.As(err, &notFoundErr) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to find named network %q in datacenter %q: %w", networkName, dc.Name(), err)
+		}
+
+		backing = network
+		dcNames = append(dcNames, dc.Name())
+	}
+
+	switch len(dcNames) {
+	case 0:
+		return nil, fmt.Errorf("unable to find named network %q in any datacenter", networkName)
+	case 1:
+		vmCtx.Logger.V(4).Info("Found named network in another datacenter",
+			"networkName", networkName, "datacenter", dcNames[0])
+		return backing, nil
+	default:
+		return nil, fmt.Errorf("named network %q is ambiguous: found in datacenters %s",
+			networkName, strings.Join(dcNames, ", "))
+	}
+}
+
 // NetOPCRName returns the name to be used for the NetOP NetworkInterface CR.
 func NetOPCRName(vmName, networkName, interfaceName string, isV1A1 bool) string {
 	var name string
@@ -281,11 +672,19 @@ func createNetOPNetworkInterface(
 		}
 	}
 
+	if err := deleteNetworkInterfaceRetainedForOtherVM(vmCtx, client, netIf); err != nil {
+		return nil, err
+	}
+
 	_, err := controllerutil.CreateOrUpdate(vmCtx, client, netIf, func() error {
+		// The VM owns the CR so the CR is garbage collected with the VM. The owner references are
+		// matched by group and kind, so the owner reference of a v1a1 VM is updated in place.
 		if err := controllerutil.SetOwnerReference(vmCtx.VM, netIf, client.Scheme()); err != nil {
 			// If this fails we likely have an object name collision, and we're in a tough spot.
 			return err
 		}
+		delete(netIf.Annotations, RetainedUntilAnnotation)
+		delete(netIf.Annotations, RetainedForAnnotation)
 
 		netIf.Spec.NetworkName = networkName
 		// NetOP only defines a VMXNet3 type, but it doesn't really matter for our purposes.
@@ -297,14 +696,76 @@ func createNetOPNetworkInterface(
 		return nil, err
 	}
 
+	waitStart := Clock.Now()
 	netIf, err = waitForReadyNetworkInterface(vmCtx, client, netIf.Name)
 	if err != nil {
 		return nil, err
 	}
 
+	result := netOpNetIfToResult(vimClient, netIf)
+	result.Timing = newNetworkInterfaceTiming(lib.NetworkProviderTypeVDS, netIf.CreationTimestamp, waitStart)
+	return result, nil
+}
+
+func getNetOPNetworkInterface(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	vimClient *vim25.Client,
+	interfaceSpec *vmopv1.VirtualMachineNetworkInterfaceSpec) (*NetworkInterfaceResult, error) {
+
+	if kind := interfaceSpec.Network.Kind; kind != "" && kind != "Network" {
+		return nil, fmt.Errorf("network kind %q is not supported for VDS", kind)
+	}
+
+	networkName := interfaceSpec.Network.Name
+	netIf := &netopv1alpha1.NetworkInterface{}
+	if err := getNetworkInterfaceCR(vmCtx, client, netIf,
+		NetOPCRName(vmCtx.VM.Name, networkName, interfaceSpec.Name, true),
+		NetOPCRName(vmCtx.VM.Name, networkName, interfaceSpec.Name, false)); err != nil {
+		return nil, err
+	}
+
+	if cond := findNetOPCondition(netIf, netopv1alpha1.NetworkInterfaceReady); cond == nil || cond.Status != corev1.ConditionTrue {
+		return nil, fmt.Errorf("network interface %s is not ready yet", netIf.Name)
+	}
+
 	return netOpNetIfToResult(vimClient, netIf), nil
 }
 
+// getNetworkInterfaceCR gets the network provider CR with the older (v1a1) name, or with the
+// v1a2 name if it does not exist.
+func getNetworkInterfaceCR(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	obj ctrlruntime.Object,
+	v1a1Name, name string) error {
+
+	err := client.Get(vmCtx, types.NamespacedName{Namespace: vmCtx.VM.Namespace, Name: v1a1Name}, obj)
+	if apierrors.IsNotFound(err) {
+		err = client.Get(vmCtx, types.NamespacedName{Namespace: vmCtx.VM.Namespace, Name: name}, obj)
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("network interface %s does not exist yet", name)
+	}
+	return err
+}
+
+// newNetworkInterfaceTiming returns the timing of the network provider's CR that was just observed
+// to be ready after waiting since waitStart.
+func newNetworkInterfaceTiming(
+	providerType string,
+	createdAt metav1.Time,
+	waitStart time.Time) *NetworkInterfaceTiming {
+
+	readyAt := Clock.Now()
+	return &NetworkInterfaceTiming{
+		ProviderType: providerType,
+		CreatedAt:    createdAt.Time,
+		ReadyAt:      readyAt,
+		Wait:         readyAt.Sub(waitStart),
+	}
+}
+
 func netOpNetIfToResult(
 	vimClient *vim25.Client,
 	netIf *netopv1alpha1.NetworkInterface) *NetworkInterfaceResult {
@@ -435,10 +896,17 @@ func createNCPNetworkInterface(
 		}
 	}
 
+	if err := deleteNetworkInterfaceRetainedForOtherVM(vmCtx, client, vnetIf); err != nil {
+		return nil, err
+	}
+
 	_, err := controllerutil.CreateOrUpdate(vmCtx, client, vnetIf, func() error {
+		// See createNetOPNetworkInterface() for the VM owner reference.
 		if err := controllerutil.SetOwnerReference(vmCtx.VM, vnetIf, client.Scheme()); err != nil {
 			return err
 		}
+		delete(vnetIf.Annotations, RetainedUntilAnnotation)
+		delete(vnetIf.Annotations, RetainedForAnnotation)
 
 		vnetIf.Spec.VirtualNetwork = networkName
 		return nil
@@ -448,10 +916,44 @@ func createNCPNetworkInterface(
 		return nil, err
 	}
 
+	waitStart := Clock.Now()
 	vnetIf, err = waitForReadyNCPNetworkInterface(vmCtx, client, vnetIf.Name)
 	if err != nil {
 		return nil, err
 	}
+	timing := newNetworkInterfaceTiming(lib.NetworkProviderTypeNSXT, vnetIf.CreationTimestamp, waitStart)
+
+	result, err := ncpNetIfToResult(vmCtx, vimClient, clusterMoRef, vnetIf)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Timing = timing
+	return result, nil
+}
+
+func getNCPNetworkInterface(
+	vmCtx context.VirtualMachineContextA2,
+	client ctrlruntime.Client,
+	vimClient *vim25.Client,
+	clusterMoRef *vimtypes.ManagedObjectReference,
+	interfaceSpec *vmopv1.VirtualMachineNetworkInterfaceSpec) (*NetworkInterfaceResult, error) {
+
+	if kind := interfaceSpec.Network.Kind; kind != "" && kind != "VirtualNetwork" {
+		return nil, fmt.Errorf("network kind %q is not supported for NCP", kind)
+	}
+
+	networkName := interfaceSpec.Network.Name
+	vnetIf := &ncpv1alpha1.VirtualNetworkInterface{}
+	if err := getNetworkInterfaceCR(vmCtx, client, vnetIf,
+		NCPCRName(vmCtx.VM.Name, networkName, interfaceSpec.Name, true),
+		NCPCRName(vmCtx.VM.Name, networkName, interfaceSpec.Name, false)); err != nil {
+		return nil, err
+	}
+
+	if !isNCPNetworkInterfaceReady(vnetIf) || vnetIf.Status.ProviderStatus == nil {
+		return nil, fmt.Errorf("network interface %s is not ready yet", vnetIf.Name)
+	}
 
 	return ncpNetIfToResult(vmCtx, vimClient, clusterMoRef, vnetIf)
 }
@@ -465,8 +967,8 @@ func ncpNetIfToResult(
 	// NSX-T makes the backing determination difficult: NsxLogicalSwitchID must be mapped to an
 	// actual DVPG since that is the backing, but the DVPG can, in some very rare but supported
 	// configurations, vary between CCRs. If we know the CCR - ether the VM already exists, or
-	// (for later work) we might pre-determine CCR w/o placement if there is only one possibility -
-	// get that backing now.
+	// the VM does not need placement so the CCR is determined by its namespace and zone - get
+	// that backing now.
 	// Otherwise, we'll do it post-placement via ResolveNCPBackingPostPlacement() so that we create
 	// the VM with the correct backing. That means we cannot make this a part of the PlaceVMxCluster()
 	// ConfigSpec since we don't know the backing: we'd have to pre-filter the placement candidates.
@@ -532,14 +1034,7 @@ func waitForReadyNCPNetworkInterface(
 			return false, ctrlruntime.IgnoreNotFound(err)
 		}
 
-		for _, condition := range vnetIf.Status.Conditions {
-			// TODO: Does NCP define condition constants?
-			if strings.Contains(condition.Type, "Ready") && strings.Contains(condition.Status, "True") {
-				return true, nil
-			}
-		}
-
-		return false, nil
+		return isNCPNetworkInterfaceReady(vnetIf), nil
 	})
 
 	if err != nil {
@@ -564,6 +1059,16 @@ func waitForReadyNCPNetworkInterface(
 	return vnetIf, nil
 }
 
+func isNCPNetworkInterfaceReady(vnetIf *ncpv1alpha1.VirtualNetworkInterface) bool {
+	for _, condition := range vnetIf.Status.Conditions {
+		// TODO: Does NCP define condition constants?
+		if strings.Contains(condition.Type, "Ready") && strings.Contains(condition.Status, "True") {
+			return true
+		}
+	}
+	return false
+}
+
 // ipCIDRNotation takes the IP and subnet mask and returns the IP in CIDR notation.
 // TODO: Better error checking. Nail down exactly how we want handle IPv4inV6 addresses.
 func ipCIDRNotation(ip string, mask string, isIPv4 bool) string {
@@ -585,10 +1090,11 @@ func ipCIDRNotation(ip string, mask string, isIPv4 bool) string {
 
 // CreateDefaultEthCard creates a default Ethernet card attached to the backing. This is used
 // when the VM Class ConfigSpec does not have a device entry for a VM Spec network interface,
-// so we need a new device.
+// so we need a new device. The card is of ethCardType, or vmxnet3 when ethCardType is empty.
 func CreateDefaultEthCard(
 	ctx goctx.Context,
-	result *NetworkInterfaceResult) (vimtypes.BaseVirtualDevice, error) {
+	result *NetworkInterfaceResult,
+	ethCardType string) (vimtypes.BaseVirtualDevice, error) {
 
 	// We may not have the backing yet if this is NSX-T. The backing will be resolved after placement
 	// when we'll know the CCR, so we can resolve the correct DVPG.
@@ -601,7 +1107,11 @@ func CreateDefaultEthCard(
 		return nil, fmt.Errorf("unable to get ethernet card backing info for network %v: %w", result.Backing.Reference(), err)
 	}
 
-	dev, err := object.EthernetCardTypes().CreateEthernetCard(defaultEthernetCardType, backing)
+	if ethCardType == "" {
+		ethCardType = defaultEthernetCardType
+	}
+
+	dev, err := object.EthernetCardTypes().CreateEthernetCard(ethCardType, backing)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create ethernet card network %v: %w", result.Backing.Reference(), err)
 	}
@@ -618,6 +1128,64 @@ func CreateDefaultEthCard(
 	return dev, nil
 }
 
+// EthernetCardType returns the type of the ethernet card device in the form used by CreateEthernetCard
+// and an OVF's ResourceSubType, ex. "e1000", "vmxnet3".
+func EthernetCardType(device vimtypes.BaseVirtualDevice) string {
+	name := object.VirtualDeviceList{}.TypeName(device)
+	name = strings.TrimPrefix(name, "Virtual")
+	name = strings.TrimSuffix(name, "EthernetCard")
+	return strings.ToLower(name)
+}
+
+// The types of the network an ethernet card is connected to, as returned by EthernetCardBackingType.
+const (
+	NetworkBackingTypeDVPG     = "DistributedVirtualPortgroup"
+	NetworkBackingTypeNSX      = "OpaqueNetwork"
+	NetworkBackingTypeStandard = "Network"
+)
+
+// ethernetCardTypeBackingTypes are the network backing types that an ethernet card type can be
+// connected to. A type that is not listed can be connected to every backing type.
+var ethernetCardTypeBackingTypes = map[string][]string{
+	// The NSX opaque networks do not support SR-IOV.
+	"sriov": {NetworkBackingTypeDVPG, NetworkBackingTypeStandard},
+}
+
+// EthernetCardBackingType returns the type of the network the ethernet card device is connected
+// to, or empty if the device does not have a backing yet, ex. an NSX-T interface before placement.
+func EthernetCardBackingType(device vimtypes.BaseVirtualDevice) string {
+	card, ok := device.(vimtypes.BaseVirtualEthernetCard)
+	if !ok {
+		return ""
+	}
+
+	switch card.GetVirtualEthernetCard().Backing.(type) {
+	case *vimtypes.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		return NetworkBackingTypeDVPG
+	case *vimtypes.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		return NetworkBackingTypeNSX
+	case *vimtypes.VirtualEthernetCardNetworkBackingInfo:
+		return NetworkBackingTypeStandard
+	}
+	return ""
+}
+
+// EthernetCardTypeSupportsBacking returns true if an ethernet card of the type, in the form
+// returned by EthernetCardType, can be connected to a network of the backing type.
+func EthernetCardTypeSupportsBacking(cardType, backingType string) bool {
+	backingTypes, ok := ethernetCardTypeBackingTypes[cardType]
+	if !ok {
+		return true
+	}
+
+	for _, t := range backingTypes {
+		if t == backingType {
+			return true
+		}
+	}
+	return false
+}
+
 // ApplyInterfaceResultToVirtualEthCard applies the interface result from the NetOP/NCP
 // provider to an existing Ethernet device from the class ConfigSpec.
 func ApplyInterfaceResultToVirtualEthCard(
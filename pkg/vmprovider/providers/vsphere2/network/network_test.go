@@ -5,14 +5,20 @@ package network_test
 
 import (
 	goctx "context"
+	"errors"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ncpv1alpha1 "github.com/vmware-tanzu/vm-operator/external/ncp/api/v1alpha1"
@@ -21,6 +27,8 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -35,9 +43,13 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 		vm             *vmopv1.VirtualMachine
 		interfaceSpecs []vmopv1.VirtualMachineNetworkInterfaceSpec
 
-		results     network.NetworkInterfaceResults
-		err         error
-		initObjects []client.Object
+		results          network.NetworkInterfaceResults
+		err              error
+		initObjects      []client.Object
+		withClusterMoRef bool
+
+		vmOwnerRef     metav1.OwnerReference
+		v1a1VMOwnerRef metav1.OwnerReference
 	)
 
 	BeforeEach(func() {
@@ -47,9 +59,19 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "network-test-vm",
 				Namespace: "network-test-ns",
+				UID:       "network-test-vm-uid",
 			},
 		}
 
+		vmOwnerRef = metav1.OwnerReference{
+			APIVersion: vmopv1.SchemeGroupVersion.String(),
+			Kind:       "VirtualMachine",
+			Name:       vm.Name,
+			UID:        vm.UID,
+		}
+		v1a1VMOwnerRef = vmOwnerRef
+		v1a1VMOwnerRef.APIVersion = vmopv1.GroupName + "/v1alpha1"
+
 		vmCtx = context.VirtualMachineContextA2{
 			Context: goctx.Background(),
 			Logger:  suite.GetLogger().WithName("network_test"),
@@ -62,12 +84,18 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 	JustBeforeEach(func() {
 		ctx = suite.NewTestContextForVCSim(testConfig, initObjects...)
 
+		var clusterMoRef *types.ManagedObjectReference
+		if withClusterMoRef {
+			ccrMoRef := ctx.GetSingleClusterCompute().Reference()
+			clusterMoRef = &ccrMoRef
+		}
+
 		results, err = network.CreateAndWaitForNetworkInterfaces(
 			vmCtx,
 			ctx.Client,
 			ctx.VCClient.Client,
 			ctx.Finder,
-			nil,
+			clusterMoRef,
 			interfaceSpecs)
 	})
 
@@ -75,6 +103,7 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 		ctx.AfterEach()
 		ctx = nil
 		initObjects = nil
+		withClusterMoRef = false
 	})
 
 	Context("Named Network", func() {
@@ -131,6 +160,136 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 				Expect(results.Results).To(BeEmpty())
 			})
 		})
+
+		Context("one of the networks does not exist", func() {
+			BeforeEach(func() {
+				interfaceSpecs = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name:    "eth0",
+						Network: common.PartialObjectRef{Name: networkName},
+					},
+					{
+						Name:    "eth1",
+						Network: common.PartialObjectRef{Name: "bogus"},
+					},
+				}
+			})
+
+			It("returns error for just that interface", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix(`network interface "eth1" error: unable to find named network "bogus"`))
+
+				var interfaceErrs network.NetworkInterfaceErrors
+				Expect(errors.As(err, &interfaceErrs)).To(BeTrue())
+				Expect(interfaceErrs.InterfaceNames()).To(Equal([]string{"eth1"}))
+
+				By("returns the result of the other interface", func() {
+					Expect(results.Results).To(HaveLen(1))
+					Expect(results.Results[0].Name).To(Equal("eth0"))
+					Expect(results.Results[0].Backing).ToNot(BeNil())
+				})
+			})
+		})
+
+		Context("network exists in other datacenters", func() {
+			const otherNetworkName = "other-dc-network"
+			var otherDatacenters []string
+
+			BeforeEach(func() {
+				interfaceSpecs = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name:    "eth0",
+						Network: common.PartialObjectRef{Name: otherNetworkName},
+					},
+				}
+				otherDatacenters = []string{"DC1"}
+			})
+
+			JustBeforeEach(func() {
+				rootFolder := object.NewRootFolder(ctx.VCClient.Client)
+
+				for _, dcName := range otherDatacenters {
+					dc, err := rootFolder.CreateDatacenter(ctx, dcName)
+					Expect(err).ToNot(HaveOccurred())
+					folders, err := dc.Folders(ctx)
+					Expect(err).ToNot(HaveOccurred())
+
+					dvsSpec := types.DVSCreateSpec{
+						ConfigSpec: &types.VMwareDVSConfigSpec{
+							DVSConfigSpec: types.DVSConfigSpec{Name: dcName + "_DVS"},
+						},
+					}
+					task, err := folders.NetworkFolder.CreateDVS(ctx, dvsSpec)
+					Expect(err).ToNot(HaveOccurred())
+					info, err := task.WaitForResult(ctx, nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					dvs := object.NewDistributedVirtualSwitch(ctx.VCClient.Client, info.Result.(types.ManagedObjectReference))
+					pgSpec := types.DVPortgroupConfigSpec{
+						Name:     otherNetworkName,
+						Type:     string(types.DistributedVirtualPortgroupPortgroupTypeEarlyBinding),
+						NumPorts: 1,
+					}
+					task, err = dvs.AddPortgroup(ctx, []types.DVPortgroupConfigSpec{pgSpec})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(task.Wait(ctx)).To(Succeed())
+				}
+
+				results, err = network.CreateAndWaitForNetworkInterfaces(
+					vmCtx,
+					ctx.Client,
+					ctx.VCClient.Client,
+					ctx.Finder,
+					nil,
+					interfaceSpecs)
+			})
+
+			It("returns error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to find named network"))
+				Expect(results.Results).To(BeEmpty())
+			})
+
+			When("cross datacenter lookup is enabled", func() {
+				BeforeEach(func() {
+					testConfig.WithNamedNetworkCrossDatacenterLookup = true
+				})
+
+				It("returns success", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(results.Results).To(HaveLen(1))
+
+					result := results.Results[0]
+					Expect(result.NetworkID).To(Equal(otherNetworkName))
+					Expect(result.Backing).ToNot(BeNil())
+					Expect(result.Backing.Reference()).ToNot(Equal(ctx.NetworkRef.Reference()))
+				})
+
+				When("network exists in multiple other datacenters", func() {
+					BeforeEach(func() {
+						otherDatacenters = []string{"DC1", "DC2"}
+					})
+
+					It("returns ambiguous error", func() {
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("is ambiguous: found in datacenters DC1, DC2"))
+						Expect(results.Results).To(BeEmpty())
+					})
+				})
+
+				When("network does not exist in any datacenter", func() {
+					BeforeEach(func() {
+						otherDatacenters = nil
+					})
+
+					It("returns error", func() {
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("unable to find named network \"other-dc-network\" in any datacenter"))
+						Expect(results.Results).To(BeEmpty())
+					})
+				})
+			})
+		})
 	})
 
 	Context("VDS", func() {
@@ -144,6 +303,26 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 			testConfig.WithNetworkEnv = builder.NetworkEnvVDS
 		})
 
+		Context("more than the maximum number of interfaces", func() {
+			BeforeEach(func() {
+				for i := 0; i < 11; i++ {
+					interfaceSpecs = append(interfaceSpecs, vmopv1.VirtualMachineNetworkInterfaceSpec{
+						Name:    fmt.Sprintf("eth%d", i),
+						Network: common.PartialObjectRef{Name: networkName},
+					})
+				}
+			})
+
+			It("returns error before creating the network interfaces", func() {
+				Expect(err).To(MatchError("VM has 11 network interfaces but at most 10 are supported"))
+				Expect(results.Results).To(BeEmpty())
+
+				netInterfaces := &netopv1alpha1.NetworkInterfaceList{}
+				Expect(ctx.Client.List(ctx, netInterfaces, client.InNamespace(vm.Namespace))).To(Succeed())
+				Expect(netInterfaces.Items).To(BeEmpty())
+			})
+		})
+
 		Context("Simulate workflow", func() {
 			BeforeEach(func() {
 				interfaceSpecs = []vmopv1.VirtualMachineNetworkInterfaceSpec{
@@ -228,12 +407,68 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 				Expect(ipConfig.Gateway).To(Equal("fd1a:6c85:79fe:7c98:0000:0000:0000:0001"))
 			})
 
+			It("records the network interface timing", func() {
+				createdAt := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+				fakeClock := clocktesting.NewFakePassiveClock(createdAt)
+				network.Clock = fakeClock
+				defer func() { network.Clock = clock.RealClock{} }()
+
+				netInterface := &netopv1alpha1.NetworkInterface{}
+				key := client.ObjectKey{Namespace: vm.Namespace, Name: network.NetOPCRName(vm.Name, networkName, interfaceName, false)}
+				Expect(ctx.Client.Get(ctx, key, netInterface)).To(Succeed())
+				netInterface.CreationTimestamp = metav1.NewTime(createdAt)
+				Expect(ctx.Client.Update(ctx, netInterface)).To(Succeed())
+
+				// Simulate NetOP reconciling the network interface 5 seconds after the wait starts.
+				go func() {
+					defer GinkgoRecover()
+					time.Sleep(250 * time.Millisecond)
+					fakeClock.SetTime(createdAt.Add(5 * time.Second))
+
+					netInterface := &netopv1alpha1.NetworkInterface{}
+					Expect(ctx.Client.Get(ctx, key, netInterface)).To(Succeed())
+					netInterface.Status.NetworkID = ctx.NetworkRef.Reference().Value
+					netInterface.Status.Conditions = []netopv1alpha1.NetworkInterfaceCondition{
+						{
+							Type:   netopv1alpha1.NetworkInterfaceReady,
+							Status: corev1.ConditionTrue,
+						},
+					}
+					Expect(ctx.Client.Status().Update(ctx, netInterface)).To(Succeed())
+				}()
+
+				results, err = network.CreateAndWaitForNetworkInterfaces(
+					vmCtx,
+					ctx.Client,
+					ctx.VCClient.Client,
+					ctx.Finder,
+					nil,
+					interfaceSpecs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results.Results).To(HaveLen(1))
+
+				timing := results.Results[0].Timing
+				Expect(timing).ToNot(BeNil())
+				Expect(timing.ProviderType).To(Equal(lib.NetworkProviderTypeVDS))
+				Expect(timing.CreatedAt).To(BeTemporally("==", createdAt))
+				Expect(timing.ReadyAt).To(BeTemporally("==", createdAt.Add(5*time.Second)))
+				Expect(timing.Wait).To(Equal(5 * time.Second))
+			})
+
+			It("sets the VM as the owner of the network interface", func() {
+				netInterface := &netopv1alpha1.NetworkInterface{}
+				key := client.ObjectKey{Namespace: vm.Namespace, Name: network.NetOPCRName(vm.Name, networkName, interfaceName, false)}
+				Expect(ctx.Client.Get(ctx, key, netInterface)).To(Succeed())
+				Expect(netInterface.OwnerReferences).To(Equal([]metav1.OwnerReference{vmOwnerRef}))
+			})
+
 			When("v1a1 network interface exists", func() {
 				BeforeEach(func() {
 					netIf := &netopv1alpha1.NetworkInterface{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      network.NetOPCRName(vm.Name, networkName, interfaceName, true),
-							Namespace: vm.Namespace,
+							Name:            network.NetOPCRName(vm.Name, networkName, interfaceName, true),
+							Namespace:       vm.Namespace,
+							OwnerReferences: []metav1.OwnerReference{v1a1VMOwnerRef},
 						},
 						Spec: netopv1alpha1.NetworkInterfaceSpec{
 							NetworkName: networkName,
@@ -244,6 +479,13 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 					initObjects = append(initObjects, netIf)
 				})
 
+				It("replaces the v1a1 VM owner reference", func() {
+					netInterface := &netopv1alpha1.NetworkInterface{}
+					key := client.ObjectKey{Namespace: vm.Namespace, Name: network.NetOPCRName(vm.Name, networkName, interfaceName, true)}
+					Expect(ctx.Client.Get(ctx, key, netInterface)).To(Succeed())
+					Expect(netInterface.OwnerReferences).To(Equal([]metav1.OwnerReference{vmOwnerRef}))
+				})
+
 				It("returns success", func() {
 					// Assert test env is what we expect.
 					Expect(ctx.NetworkRef.Reference().Type).To(Equal("DistributedVirtualPortgroup"))
@@ -431,21 +673,76 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 				Expect(results.Results[0].Backing.Reference()).To(Equal(ctx.NetworkRef.Reference()))
 			})
 
-			When("v1a1 NCP network interface exists", func() {
+			It("sets the VM as the owner of the network interface", func() {
+				vnetIf := &ncpv1alpha1.VirtualNetworkInterface{}
+				key := client.ObjectKey{Namespace: vm.Namespace, Name: network.NCPCRName(vm.Name, networkName, interfaceName, false)}
+				Expect(ctx.Client.Get(ctx, key, vnetIf)).To(Succeed())
+				Expect(vnetIf.OwnerReferences).To(Equal([]metav1.OwnerReference{vmOwnerRef}))
+			})
+
+			When("the network interface is ready and the ClusterMoRef is known up front", func() {
 				BeforeEach(func() {
+					withClusterMoRef = true
+
 					vnetIf := &ncpv1alpha1.VirtualNetworkInterface{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      network.NCPCRName(vm.Name, networkName, interfaceName, true),
+							Name:      network.NCPCRName(vm.Name, networkName, interfaceName, false),
 							Namespace: vm.Namespace,
 						},
 						Spec: ncpv1alpha1.VirtualNetworkInterfaceSpec{
 							VirtualNetwork: networkName,
 						},
+						Status: ncpv1alpha1.VirtualNetworkInterfaceStatus{
+							InterfaceID: interfaceID,
+							MacAddress:  macAddress,
+							ProviderStatus: &ncpv1alpha1.VirtualNetworkInterfaceProviderStatus{
+								NsxLogicalSwitchID: builder.NsxTLogicalSwitchUUID,
+							},
+							Conditions: []ncpv1alpha1.VirtualNetworkCondition{
+								{
+									Type:   "Ready",
+									Status: "True",
+								},
+							},
+						},
+					}
+
+					initObjects = append(initObjects, vnetIf)
+				})
+
+				It("returns the backing from the first call", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(results.Results).To(HaveLen(1))
+					result := results.Results[0]
+					Expect(result.NetworkID).To(Equal(builder.NsxTLogicalSwitchUUID))
+					Expect(result.Backing).ToNot(BeNil())
+					Expect(result.Backing.Reference()).To(Equal(ctx.NetworkRef.Reference()))
+				})
+			})
+
+			When("v1a1 NCP network interface exists", func() {
+				BeforeEach(func() {
+					vnetIf := &ncpv1alpha1.VirtualNetworkInterface{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            network.NCPCRName(vm.Name, networkName, interfaceName, true),
+							Namespace:       vm.Namespace,
+							OwnerReferences: []metav1.OwnerReference{v1a1VMOwnerRef},
+						},
+						Spec: ncpv1alpha1.VirtualNetworkInterfaceSpec{
+							VirtualNetwork: networkName,
+						},
 					}
 
 					initObjects = append(initObjects, vnetIf)
 				})
 
+				It("replaces the v1a1 VM owner reference", func() {
+					vnetIf := &ncpv1alpha1.VirtualNetworkInterface{}
+					key := client.ObjectKey{Namespace: vm.Namespace, Name: network.NCPCRName(vm.Name, networkName, interfaceName, true)}
+					Expect(ctx.Client.Get(ctx, key, vnetIf)).To(Succeed())
+					Expect(vnetIf.OwnerReferences).To(Equal([]metav1.OwnerReference{vmOwnerRef}))
+				})
+
 				It("returns success", func() {
 					// Assert test env is what we expect.
 					Expect(ctx.NetworkRef.Reference().Type).To(Equal("DistributedVirtualPortgroup"))
@@ -536,3 +833,463 @@ var _ = Describe("CreateAndWaitForNetworkInterfaces", func() {
 		})
 	})
 })
+
+var _ = Describe("GetNetworkInterfaces", func() {
+	const (
+		interfaceName = "eth0"
+		networkName   = "my-vds-network"
+	)
+
+	var (
+		testConfig builder.VCSimTestConfig
+		ctx        *builder.TestContextForVCSim
+
+		vmCtx          context.VirtualMachineContextA2
+		vm             *vmopv1.VirtualMachine
+		interfaceSpecs []vmopv1.VirtualMachineNetworkInterfaceSpec
+
+		results     network.NetworkInterfaceResults
+		err         error
+		initObjects []client.Object
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithV1A2: true, WithNetworkEnv: builder.NetworkEnvVDS}
+
+		vm = &vmopv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "network-test-vm",
+				Namespace: "network-test-ns",
+				UID:       "network-test-vm-uid",
+			},
+		}
+
+		vmCtx = context.VirtualMachineContextA2{
+			Context: goctx.Background(),
+			Logger:  suite.GetLogger().WithName("network_test"),
+			VM:      vm,
+		}
+
+		interfaceSpecs = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+			{
+				Name:    interfaceName,
+				Network: common.PartialObjectRef{Name: networkName},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig, initObjects...)
+
+		results, err = network.GetNetworkInterfaces(
+			vmCtx,
+			ctx.Client,
+			ctx.VCClient.Client,
+			ctx.Finder,
+			nil,
+			interfaceSpecs)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		initObjects = nil
+	})
+
+	It("returns error without creating the network interface", func() {
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not exist yet"))
+		Expect(results.Results).To(BeEmpty())
+
+		netInterfaces := &netopv1alpha1.NetworkInterfaceList{}
+		Expect(ctx.Client.List(ctx, netInterfaces, client.InNamespace(vm.Namespace))).To(Succeed())
+		Expect(netInterfaces.Items).To(BeEmpty())
+	})
+
+	When("the network interface exists", func() {
+		var netInterface *netopv1alpha1.NetworkInterface
+
+		BeforeEach(func() {
+			netInterface = &netopv1alpha1.NetworkInterface{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      network.NetOPCRName(vm.Name, networkName, interfaceName, false),
+					Namespace: vm.Namespace,
+				},
+				Spec: netopv1alpha1.NetworkInterfaceSpec{
+					NetworkName: "other-network",
+				},
+			}
+			initObjects = append(initObjects, netInterface)
+		})
+
+		It("returns error when the network interface is not ready", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not ready yet"))
+		})
+
+		When("the network interface is ready", func() {
+			BeforeEach(func() {
+				netInterface.Status.NetworkID = "my-network-id"
+				netInterface.Status.Conditions = []netopv1alpha1.NetworkInterfaceCondition{
+					{
+						Type:   netopv1alpha1.NetworkInterfaceReady,
+						Status: corev1.ConditionTrue,
+					},
+				}
+			})
+
+			It("returns success without updating the network interface", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results.Results).To(HaveLen(1))
+				Expect(results.Results[0].Name).To(Equal(interfaceName))
+				Expect(results.Results[0].NetworkID).To(Equal("my-network-id"))
+
+				Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(netInterface), netInterface)).To(Succeed())
+				Expect(netInterface.Spec.NetworkName).To(Equal("other-network"))
+				Expect(netInterface.OwnerReferences).To(BeEmpty())
+			})
+		})
+	})
+})
+
+var _ = Describe("DeleteStaleNetworkInterfaces", func() {
+	const (
+		interfaceName = "eth0"
+		networkName   = "my-network"
+	)
+
+	var (
+		testConfig builder.VCSimTestConfig
+		ctx        *builder.TestContextForVCSim
+
+		vmCtx          context.VirtualMachineContextA2
+		vm             *vmopv1.VirtualMachine
+		interfaceSpecs []vmopv1.VirtualMachineNetworkInterfaceSpec
+		ownerRef       metav1.OwnerReference
+
+		err         error
+		initObjects []client.Object
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithV1A2: true}
+
+		vm = &vmopv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "network-test-vm",
+				Namespace: "network-test-ns",
+				UID:       "network-test-vm-uid",
+			},
+		}
+
+		vmCtx = context.VirtualMachineContextA2{
+			Context: goctx.Background(),
+			Logger:  suite.GetLogger().WithName("network_test"),
+			VM:      vm,
+		}
+
+		interfaceSpecs = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+			{
+				Name: interfaceName,
+				Network: common.PartialObjectRef{
+					Name: networkName,
+				},
+			},
+		}
+
+		ownerRef = metav1.OwnerReference{
+			APIVersion: vmopv1.SchemeGroupVersion.String(),
+			Kind:       "VirtualMachine",
+			Name:       vm.Name,
+			UID:        vm.UID,
+		}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig, initObjects...)
+
+		err = network.DeleteStaleNetworkInterfaces(vmCtx, ctx.Client, interfaceSpecs)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		initObjects = nil
+	})
+
+	Context("VDS", func() {
+		var activeNetIf, staleNetIf, otherNetIf *netopv1alpha1.NetworkInterface
+
+		BeforeEach(func() {
+			testConfig.WithNetworkEnv = builder.NetworkEnvVDS
+
+			activeNetIf = &netopv1alpha1.NetworkInterface{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            network.NetOPCRName(vm.Name, networkName, interfaceName, false),
+					Namespace:       vm.Namespace,
+					OwnerReferences: []metav1.OwnerReference{ownerRef},
+				},
+			}
+			staleNetIf = &netopv1alpha1.NetworkInterface{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            network.NetOPCRName(vm.Name, networkName, "eth1", false),
+					Namespace:       vm.Namespace,
+					OwnerReferences: []metav1.OwnerReference{ownerRef},
+				},
+			}
+			otherNetIf = &netopv1alpha1.NetworkInterface{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      network.NetOPCRName("other-vm", networkName, "eth1", false),
+					Namespace: vm.Namespace,
+				},
+			}
+			initObjects = append(initObjects, activeNetIf, staleNetIf, otherNetIf)
+		})
+
+		It("deletes the stale network interface", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(activeNetIf), activeNetIf)).To(Succeed())
+			Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(otherNetIf), otherNetIf)).To(Succeed())
+			err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(staleNetIf), staleNetIf)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("NCP", func() {
+		var activeVNetIf, staleVNetIf *ncpv1alpha1.VirtualNetworkInterface
+
+		BeforeEach(func() {
+			testConfig.WithNetworkEnv = builder.NetworkEnvNSXT
+
+			// The active interface still has the v1a1 name.
+			activeVNetIf = &ncpv1alpha1.VirtualNetworkInterface{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            network.NCPCRName(vm.Name, networkName, interfaceName, true),
+					Namespace:       vm.Namespace,
+					OwnerReferences: []metav1.OwnerReference{ownerRef},
+				},
+			}
+			staleVNetIf = &ncpv1alpha1.VirtualNetworkInterface{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            network.NCPCRName(vm.Name, "other-network", interfaceName, false),
+					Namespace:       vm.Namespace,
+					OwnerReferences: []metav1.OwnerReference{ownerRef},
+				},
+			}
+			initObjects = append(initObjects, activeVNetIf, staleVNetIf)
+		})
+
+		It("deletes the stale network interface", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(activeVNetIf), activeVNetIf)).To(Succeed())
+			err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(staleVNetIf), staleVNetIf)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("RetainNetworkInterfaces", func() {
+	const (
+		interfaceName = "eth0"
+		networkName   = "my-network"
+	)
+
+	var (
+		testConfig builder.VCSimTestConfig
+		ctx        *builder.TestContextForVCSim
+
+		vmCtx    context.VirtualMachineContextA2
+		vm       *vmopv1.VirtualMachine
+		ownerRef metav1.OwnerReference
+
+		err         error
+		initObjects []client.Object
+
+		netIf, expiredNetIf, retainedNetIf *netopv1alpha1.NetworkInterface
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithV1A2: true, WithNetworkEnv: builder.NetworkEnvVDS}
+		network.RetryTimeout = 1 * time.Second
+
+		vm = &vmopv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "network-test-vm",
+				Namespace: "network-test-ns",
+				UID:       "network-test-vm-uid",
+			},
+		}
+
+		vmCtx = context.VirtualMachineContextA2{
+			Context: goctx.Background(),
+			Logger:  suite.GetLogger().WithName("network_test"),
+			VM:      vm,
+		}
+
+		ownerRef = metav1.OwnerReference{
+			APIVersion: vmopv1.SchemeGroupVersion.String(),
+			Kind:       "VirtualMachine",
+			Name:       vm.Name,
+			UID:        vm.UID,
+		}
+
+		netIf = &netopv1alpha1.NetworkInterface{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            network.NetOPCRName(vm.Name, networkName, interfaceName, false),
+				Namespace:       vm.Namespace,
+				OwnerReferences: []metav1.OwnerReference{ownerRef},
+			},
+			Spec: netopv1alpha1.NetworkInterfaceSpec{
+				NetworkName: networkName,
+				Type:        netopv1alpha1.NetworkInterfaceTypeVMXNet3,
+			},
+			Status: netopv1alpha1.NetworkInterfaceStatus{
+				IPConfigs: []netopv1alpha1.IPConfig{
+					{
+						IP:         "192.168.1.110",
+						IPFamily:   netopv1alpha1.IPv4Protocol,
+						Gateway:    "192.168.1.1",
+						SubnetMask: "255.255.255.0",
+					},
+				},
+				Conditions: []netopv1alpha1.NetworkInterfaceCondition{
+					{
+						Type:   netopv1alpha1.NetworkInterfaceReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
+		expiredNetIf = &netopv1alpha1.NetworkInterface{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      network.NetOPCRName("expired-vm", networkName, interfaceName, false),
+				Namespace: vm.Namespace,
+				Annotations: map[string]string{
+					network.RetainedUntilAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+				},
+			},
+		}
+		retainedNetIf = &netopv1alpha1.NetworkInterface{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      network.NetOPCRName("retained-vm", networkName, interfaceName, false),
+				Namespace: vm.Namespace,
+				Annotations: map[string]string{
+					network.RetainedUntilAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+				},
+			},
+		}
+		initObjects = append(initObjects, netIf, expiredNetIf, retainedNetIf)
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig, initObjects...)
+
+		err = network.RetainNetworkInterfaces(vmCtx, ctx.Client, 5*time.Minute)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		initObjects = nil
+	})
+
+	It("removes the VM as the owner of the network interface", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(netIf), netIf)).To(Succeed())
+		Expect(netIf.OwnerReferences).To(BeEmpty())
+		Expect(netIf.Annotations).To(HaveKey(network.RetainedUntilAnnotation))
+		retainedUntil, err := time.Parse(time.RFC3339, netIf.Annotations[network.RetainedUntilAnnotation])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retainedUntil).To(BeTemporally("~", time.Now().Add(5*time.Minute), time.Minute))
+		Expect(netIf.Annotations).To(HaveKeyWithValue(network.RetainedForAnnotation, string(vm.UID)))
+	})
+
+	It("deletes the network interfaces whose grace period expired", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(retainedNetIf), retainedNetIf)).To(Succeed())
+		err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(expiredNetIf), expiredNetIf)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("reuses the network interface when the VM is recreated within the grace period", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(netIf), netIf)).To(Succeed())
+		creationTimestamp := netIf.CreationTimestamp
+
+		vmCtx.VM = &vmopv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      vm.Name,
+				Namespace: vm.Namespace,
+				UID:       "network-test-vm-recreated-uid",
+				Annotations: map[string]string{
+					constants.ReuseNetworkInterfacesAnnotation: string(vm.UID),
+				},
+			},
+		}
+
+		results, err := network.CreateAndWaitForNetworkInterfaces(
+			vmCtx,
+			ctx.Client,
+			ctx.VCClient.Client,
+			ctx.Finder,
+			nil,
+			[]vmopv1.VirtualMachineNetworkInterfaceSpec{
+				{
+					Name:    interfaceName,
+					Network: common.PartialObjectRef{Name: networkName},
+				},
+			})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results.Results).To(HaveLen(1))
+		Expect(results.Results[0].IPConfigs).To(HaveLen(1))
+		Expect(results.Results[0].IPConfigs[0].IPCIDR).To(Equal("192.168.1.110/24"))
+
+		Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(netIf), netIf)).To(Succeed())
+		Expect(netIf.CreationTimestamp).To(Equal(creationTimestamp))
+		Expect(netIf.Annotations).ToNot(HaveKey(network.RetainedUntilAnnotation))
+		Expect(netIf.Annotations).ToNot(HaveKey(network.RetainedForAnnotation))
+		Expect(netIf.OwnerReferences).To(HaveLen(1))
+		Expect(netIf.OwnerReferences[0].UID).To(Equal(vmCtx.VM.UID))
+	})
+
+	It("deletes the network interface when a VM with the same name does not reuse it", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		vmCtx.VM = &vmopv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      vm.Name,
+				Namespace: vm.Namespace,
+				UID:       "network-test-vm-new-uid",
+			},
+		}
+
+		interfaces := []vmopv1.VirtualMachineNetworkInterfaceSpec{
+			{
+				Name:    interfaceName,
+				Network: common.PartialObjectRef{Name: networkName},
+			},
+		}
+
+		_, err := network.CreateAndWaitForNetworkInterfaces(vmCtx, ctx.Client, ctx.VCClient.Client, ctx.Finder, nil, interfaces)
+		Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("network interface %s retained for deleted VM %s is being deleted", netIf.Name, vm.UID))))
+		err = ctx.Client.Get(ctx, client.ObjectKeyFromObject(netIf), &netopv1alpha1.NetworkInterface{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("creates a new network interface", func() {
+			_, err := network.CreateAndWaitForNetworkInterfaces(vmCtx, ctx.Client, ctx.VCClient.Client, ctx.Finder, nil, interfaces)
+			// The new network interface is not ready since there is no network provider.
+			Expect(err).To(HaveOccurred())
+
+			newNetIf := &netopv1alpha1.NetworkInterface{}
+			Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(netIf), newNetIf)).To(Succeed())
+			Expect(newNetIf.Status.IPConfigs).To(BeEmpty())
+			Expect(newNetIf.OwnerReferences).To(HaveLen(1))
+			Expect(newNetIf.OwnerReferences[0].UID).To(Equal(vmCtx.VM.UID))
+		})
+	})
+})
@@ -107,15 +107,18 @@ func CloneVMRelocateSpec(
 	return rSpec, nil
 }
 
-// PlaceVMForCreate determines the suitable placement candidates in the cluster.
+// PlaceVMForCreate determines the suitable placement candidates in the cluster. When hosts is
+// not empty, only those hosts of the cluster are candidates.
 func PlaceVMForCreate(
 	ctx goctx.Context,
 	cluster *object.ClusterComputeResource,
-	configSpec *types.VirtualMachineConfigSpec) ([]Recommendation, error) {
+	configSpec *types.VirtualMachineConfigSpec,
+	hosts []types.ManagedObjectReference) ([]Recommendation, error) {
 
 	placementSpec := types.PlacementSpec{
 		PlacementType: string(types.PlacementSpecPlacementTypeCreate),
 		ConfigSpec:    configSpec,
+		Hosts:         hosts,
 	}
 
 	resp, err := cluster.PlaceVm(ctx, placementSpec)
@@ -5,6 +5,7 @@ package placement
 
 import (
 	goctx "context"
+	"errors"
 	"fmt"
 	"math/rand"
 
@@ -22,6 +23,10 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
 )
 
+// ErrInstanceStorageHostsExhausted is returned when the VM's instance storage volumes failed to be
+// placed on every candidate host, so there is no other host to select.
+var ErrInstanceStorageHostsExhausted = errors.New("instance storage volumes failed to be placed on all candidate hosts")
+
 type Result struct {
 	ZonePlacement            bool
 	InstanceStoragePlacement bool
@@ -61,6 +66,13 @@ func doesVMNeedPlacement(vmCtx context.VirtualMachineContextA2) (res Result, nee
 	return
 }
 
+// IsPlacementNeeded returns true if the VM still needs placement to select its zone or its
+// host. Otherwise, the VM's ResourcePool is already determined by its namespace and zone.
+func IsPlacementNeeded(vmCtx context.VirtualMachineContextA2) bool {
+	_, needZonePlacement, needInstanceStoragePlacement := doesVMNeedPlacement(vmCtx)
+	return needZonePlacement || needInstanceStoragePlacement
+}
+
 // lookupChildRPs lookups the child ResourcePool under each parent ResourcePool. A VM with a ResourcePolicy
 // may specify a child ResourcePool that the VM will be created under.
 func lookupChildRPs(
@@ -93,7 +105,8 @@ func getPlacementCandidates(
 	client ctrlclient.Client,
 	vcClient *vim25.Client,
 	zonePlacement bool,
-	childRPName string) (map[string][]string, error) {
+	childRPName string,
+	excludedZones map[string]struct{}) (map[string][]string, error) {
 
 	var zones []topologyv1.AvailabilityZone
 
@@ -118,6 +131,11 @@ func getPlacementCandidates(
 	candidates := map[string][]string{}
 
 	for _, zone := range zones {
+		if _, ok := excludedZones[zone.Name]; ok {
+			vmCtx.Logger.V(4).Info("Zone is excluded from placement", "zone", zone.Name)
+			continue
+		}
+
 		nsInfo, ok := zone.Spec.Namespaces[vmCtx.VM.Namespace]
 		if !ok {
 			continue
@@ -159,12 +177,94 @@ func rpMoIDToCluster(
 	return object.NewClusterComputeResource(vcClient, cluster.Reference()), nil
 }
 
+// getCandidateHosts returns the cluster's hosts that are not excluded.
+func getCandidateHosts(
+	ctx goctx.Context,
+	cluster *object.ClusterComputeResource,
+	excludedHosts map[string]struct{}) ([]types.ManagedObjectReference, error) {
+
+	hosts, err := cluster.Hosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostMoRefs []types.ManagedObjectReference
+	for _, host := range hosts {
+		if _, ok := excludedHosts[host.Reference().Value]; !ok {
+			hostMoRefs = append(hostMoRefs, host.Reference())
+		}
+	}
+
+	return hostMoRefs, nil
+}
+
+// areCandidateHostsExhausted returns true if none of the candidates' clusters has a host that is
+// not excluded and that DRS recommends for the VM. An error is returned if DRS fails, since then
+// the hosts that are not excluded may still be suitable.
+func areCandidateHostsExhausted(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vim25.Client,
+	candidates map[string][]string,
+	configSpec *types.VirtualMachineConfigSpec,
+	excludedHosts map[string]struct{}) (bool, error) {
+
+	for _, rpMoIDs := range candidates {
+		for _, rpMoID := range rpMoIDs {
+			cluster, err := rpMoIDToCluster(vmCtx, vcClient, types.ManagedObjectReference{Type: "ResourcePool", Value: rpMoID})
+			if err != nil {
+				return false, err
+			}
+
+			hostMoRefs, err := getCandidateHosts(vmCtx, cluster, excludedHosts)
+			if err != nil {
+				return false, err
+			}
+
+			if len(hostMoRefs) == 0 {
+				continue
+			}
+
+			recs, err := PlaceVMForCreate(vmCtx, cluster, configSpec, hostMoRefs)
+			if err != nil {
+				return false, err
+			}
+
+			if len(filterExcludedHosts(recs, excludedHosts)) > 0 {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// filterExcludedHosts removes the recommendations for the excluded hosts.
+func filterExcludedHosts(recs []Recommendation, excludedHosts map[string]struct{}) []Recommendation {
+	if len(excludedHosts) == 0 {
+		return recs
+	}
+
+	filtered := recs[:0]
+	for _, rec := range recs {
+		if rec.HostMoRef != nil {
+			if _, ok := excludedHosts[rec.HostMoRef.Value]; ok {
+				continue
+			}
+		}
+		filtered = append(filtered, rec)
+	}
+
+	return filtered
+}
+
 // getPlacementRecommendations calls DRS PlaceVM to determine clusters suitable for placement.
+// The excluded hosts are not candidates for placement.
 func getPlacementRecommendations(
 	vmCtx context.VirtualMachineContextA2,
 	vcClient *vim25.Client,
 	candidates map[string][]string,
-	configSpec *types.VirtualMachineConfigSpec) map[string][]Recommendation {
+	configSpec *types.VirtualMachineConfigSpec,
+	excludedHosts map[string]struct{}) map[string][]Recommendation {
 
 	recommendations := map[string][]Recommendation{}
 
@@ -178,13 +278,31 @@ func getPlacementRecommendations(
 				continue
 			}
 
-			recs, err := PlaceVMForCreate(vmCtx, cluster, configSpec)
+			var hostMoRefs []types.ManagedObjectReference
+			if len(excludedHosts) > 0 {
+				hostMoRefs, err = getCandidateHosts(vmCtx, cluster, excludedHosts)
+				if err != nil {
+					vmCtx.Logger.Error(err, "failed to get CCR hosts", "zone", zoneName,
+						"clusterMoID", cluster.Reference().Value)
+					continue
+				}
+
+				if len(hostMoRefs) == 0 {
+					vmCtx.Logger.Info("All of the cluster's hosts are excluded from placement", "zone", zoneName,
+						"clusterMoID", cluster.Reference().Value, "rpMoID", rpMoID)
+					continue
+				}
+			}
+
+			recs, err := PlaceVMForCreate(vmCtx, cluster, configSpec, hostMoRefs)
 			if err != nil {
 				vmCtx.Logger.Error(err, "PlaceVM failed", "zone", zoneName,
 					"clusterMoID", cluster.Reference().Value, "rpMoID", rpMoID)
 				continue
 			}
 
+			recs = filterExcludedHosts(recs, excludedHosts)
+
 			if len(recs) == 0 {
 				vmCtx.Logger.Info("No placement recommendations", "zone", zoneName,
 					"clusterMoID", cluster.Reference().Value, "rpMoID", rpMoID)
@@ -209,12 +327,14 @@ func getPlacementRecommendations(
 }
 
 // getZonalPlacementRecommendations calls DRS PlaceVmsXCluster to determine clusters suitable for placement.
+// When hosts are excluded, DRS PlaceVM is called for each cluster with the hosts that are not excluded.
 func getZonalPlacementRecommendations(
 	vmCtx context.VirtualMachineContextA2,
 	vcClient *vim25.Client,
 	candidates map[string][]string,
 	configSpec *types.VirtualMachineConfigSpec,
-	needsHost bool) map[string][]Recommendation {
+	needsHost bool,
+	excludedHosts map[string]struct{}) map[string][]Recommendation {
 
 	rpMOToZone := map[types.ManagedObjectReference]string{}
 	var candidateRPMoRefs []types.ManagedObjectReference
@@ -227,6 +347,14 @@ func getZonalPlacementRecommendations(
 		}
 	}
 
+	if len(excludedHosts) > 0 {
+		// PlaceVmsXCluster() cannot be constrained to the hosts that are not excluded, so it could
+		// keep recommending an excluded host.
+		vmCtx.Logger.Info("Falling back into non-zonal placement since hosts are excluded from placement",
+			"excludedHosts", len(excludedHosts))
+		return getPlacementRecommendations(vmCtx, vcClient, candidates, configSpec, excludedHosts)
+	}
+
 	var recs []Recommendation
 
 	if len(candidateRPMoRefs) == 1 {
@@ -236,7 +364,7 @@ func getZonalPlacementRecommendations(
 			// This is a hack until PlaceVmsXCluster() supports instance storage disks.
 			vmCtx.Logger.Info("Falling back into non-zonal placement since the only candidate needs host selected",
 				"rpMoID", candidateRPMoRefs[0].Value)
-			return getPlacementRecommendations(vmCtx, vcClient, candidates, configSpec)
+			return getPlacementRecommendations(vmCtx, vcClient, candidates, configSpec, excludedHosts)
 		}
 
 		recs = append(recs, Recommendation{
@@ -283,20 +411,22 @@ func MakePlacementDecision(recommendations map[string][]Recommendation) (string,
 }
 
 // Placement determines if the VM needs placement, and if so, determines where to place the VM
-// and updates the Labels and Annotations with the placement decision.
+// and updates the Labels and Annotations with the placement decision. The VM is not placed in
+// any of the excluded zones.
 func Placement(
 	vmCtx context.VirtualMachineContextA2,
 	client ctrlclient.Client,
 	vcClient *vim25.Client,
 	configSpec *types.VirtualMachineConfigSpec,
-	childRPName string) (*Result, error) {
+	childRPName string,
+	excludedZones map[string]struct{}) (*Result, error) {
 
 	existingRes, zonePlacement, instanceStoragePlacement := doesVMNeedPlacement(vmCtx)
 	if !zonePlacement && !instanceStoragePlacement {
 		return &existingRes, nil
 	}
 
-	candidates, err := getPlacementCandidates(vmCtx, client, vcClient, zonePlacement, childRPName)
+	candidates, err := getPlacementCandidates(vmCtx, client, vcClient, zonePlacement, childRPName, excludedZones)
 	if err != nil {
 		return nil, err
 	}
@@ -308,13 +438,33 @@ func Placement(
 	// TBD: May want to get the host for vGPU and other passthru devices too.
 	needsHost := instanceStoragePlacement
 
+	// Do not select a host that the VM's instance storage volumes already failed to be placed on.
+	var excludedHosts map[string]struct{}
+	if instanceStoragePlacement {
+		if failedHostMoIDs := instancestorage.FailedHostMoIDs(vmCtx.VM); len(failedHostMoIDs) > 0 {
+			excludedHosts = make(map[string]struct{}, len(failedHostMoIDs))
+			for _, hostMoID := range failedHostMoIDs {
+				excludedHosts[hostMoID] = struct{}{}
+			}
+		}
+	}
+
 	var recommendations map[string][]Recommendation
 	if zonePlacement {
-		recommendations = getZonalPlacementRecommendations(vmCtx, vcClient, candidates, configSpec, needsHost)
+		recommendations = getZonalPlacementRecommendations(vmCtx, vcClient, candidates, configSpec, needsHost, excludedHosts)
 	} else /* instanceStoragePlacement */ {
-		recommendations = getPlacementRecommendations(vmCtx, vcClient, candidates, configSpec)
+		recommendations = getPlacementRecommendations(vmCtx, vcClient, candidates, configSpec, excludedHosts)
 	}
 	if len(recommendations) == 0 {
+		if len(excludedHosts) > 0 {
+			exhausted, err := areCandidateHostsExhausted(vmCtx, vcClient, candidates, configSpec, excludedHosts)
+			if err != nil {
+				return nil, err
+			}
+			if exhausted {
+				return nil, ErrInstanceStorageHostsExhausted
+			}
+		}
 		return nil, fmt.Errorf("no placement recommendations available")
 	}
 
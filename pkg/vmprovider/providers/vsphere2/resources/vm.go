@@ -10,11 +10,14 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/retry"
 	vmutil "github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/vm"
 )
 
@@ -77,16 +80,62 @@ func (vm *VirtualMachine) Clone(ctx context.Context, folder *object.Folder, clon
 }
 
 func (vm *VirtualMachine) Reconfigure(ctx context.Context, configSpec *types.VirtualMachineConfigSpec) error {
+	return vm.ReconfigureWithTaskFn(ctx, configSpec, nil)
+}
+
+// ReconfigureWithTaskFn is the same as Reconfigure but when not nil, taskFn is called with the
+// reference of each reconfigure task after it is created.
+func (vm *VirtualMachine) ReconfigureWithTaskFn(
+	ctx context.Context,
+	configSpec *types.VirtualMachineConfigSpec,
+	taskFn func(types.ManagedObjectReference)) error {
+
 	vm.logger.V(5).Info("Reconfiguring VM", "configSpec", configSpec)
 
-	reconfigureTask, err := vm.vcVirtualMachine.Reconfigure(ctx, *configSpec)
+	return retry.OnTransientFault(func() error {
+		reconfigureTask, err := vm.vcVirtualMachine.Reconfigure(ctx, *configSpec)
+		if err != nil {
+			return err
+		}
+
+		if taskFn != nil {
+			taskFn(reconfigureTask.Reference())
+		}
+
+		_, err = reconfigureTask.WaitForResult(ctx, nil)
+		if err != nil {
+			return errors.Wrapf(err, "reconfigure VM task failed")
+		}
+
+		return nil
+	})
+}
+
+func (vm *VirtualMachine) UpgradeHardwareVersion(ctx context.Context, version string) error {
+	vm.logger.V(5).Info("Upgrading VM hardware version", "version", version)
+
+	upgradeTask, err := vm.vcVirtualMachine.UpgradeVM(ctx, version)
 	if err != nil {
 		return err
 	}
 
-	_, err = reconfigureTask.WaitForResult(ctx, nil)
+	if _, err := upgradeTask.WaitForResult(ctx, nil); err != nil {
+		return errors.Wrapf(err, "upgrade VM task failed")
+	}
+
+	return nil
+}
+
+func (vm *VirtualMachine) Rename(ctx context.Context, name string) error {
+	vm.logger.V(5).Info("Renaming VM", "name", name)
+
+	renameTask, err := vm.vcVirtualMachine.Rename(ctx, name)
 	if err != nil {
-		return errors.Wrapf(err, "reconfigure VM task failed")
+		return err
+	}
+
+	if _, err := renameTask.WaitForResult(ctx, nil); err != nil {
+		return errors.Wrapf(err, "rename VM task failed")
 	}
 
 	return nil
@@ -166,6 +215,41 @@ func (vm *VirtualMachine) SetPowerState(
 	return err
 }
 
+// PowerOn powers on the VM. When not nil, the VM is powered on the host, instead of the host DRS
+// selects, and taskFn is called with the reference of the power on task after it is created. It
+// is not an error if the VM is already powered on.
+func (vm *VirtualMachine) PowerOn(
+	ctx context.Context,
+	host *types.ManagedObjectReference,
+	taskFn func(types.ManagedObjectReference)) error {
+
+	vm.logger.V(5).Info("Powering on VM", "host", host)
+
+	res, err := methods.PowerOnVM_Task(ctx, vm.vcVirtualMachine.Client(), &types.PowerOnVM_Task{
+		This: vm.vcVirtualMachine.Reference(),
+		Host: host,
+	})
+	if err != nil {
+		return err
+	}
+	powerOnTask := object.NewTask(vm.vcVirtualMachine.Client(), res.Returnval)
+
+	if taskFn != nil {
+		taskFn(powerOnTask.Reference())
+	}
+
+	if _, err := powerOnTask.WaitForResult(ctx, nil); err != nil {
+		if taskErr, ok := err.(task.Error); ok {
+			if ips, ok := taskErr.Fault().(*types.InvalidPowerState); ok && ips.ExistingState == ips.RequestedState {
+				return nil
+			}
+		}
+		return errors.Wrapf(err, "power on VM task failed")
+	}
+
+	return nil
+}
+
 // GetVirtualDevices returns the VMs VirtualDeviceList.
 func (vm *VirtualMachine) GetVirtualDevices(ctx context.Context) (object.VirtualDeviceList, error) {
 	vm.logger.V(5).Info("GetVirtualDevices")
@@ -6,21 +6,38 @@ package session
 import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
 	ctrlruntime "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/record"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/internal"
 	res "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/resources"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
 )
 
 type Session struct {
 	Client    *client.Client
 	K8sClient ctrlruntime.Client
 	Finder    *find.Finder
+	Recorder  record.Recorder
+	TagCache  *virtualmachine.TagCache
 
 	// Fields only used during Update
 	Cluster *object.ClusterComputeResource
+
+	// ReadOnly makes the Update a dry run, as if the VM had the dry run reconfigure annotation,
+	// since the provider must not change vCenter.
+	ReadOnly bool
+
+	// TrackTaskFn, when set, is called with the vSphere task that reconfigures or powers on the
+	// VM once it has been started, and the returned func is called once the task has completed.
+	TrackTaskFn func(task vimTypes.ManagedObjectReference) func()
+
+	// dryRun is set during a dry run of the Update, and records the changes that would be made
+	// instead of making them.
+	dryRun *dryRunPlan
 }
 
 func (s *Session) invokeFsrVirtualMachine(vmCtx context.VirtualMachineContextA2, resVM *res.VirtualMachine) error {
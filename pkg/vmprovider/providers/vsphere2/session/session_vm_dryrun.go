@@ -0,0 +1,178 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+	apiEquality "k8s.io/apimachinery/pkg/api/equality"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	res "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/resources"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
+)
+
+// dryRunPlan records the changes that a dry run of the update of the VM would make. The dry run
+// computes the changes the same way as the update, and only the calls that would change the VM,
+// its cluster, or its tags record the change here instead of making it.
+type dryRunPlan struct {
+	configSpecs              []*vimTypes.VirtualMachineConfigSpec
+	clusterSpecs             []*vimTypes.ClusterConfigSpecEx
+	hardwareVersion          int32
+	powerState               vmopv1.VirtualMachinePowerState
+	displayName              string
+	bootDiskStorageProfileID string
+	tagChanges               virtualmachine.LabelTagChanges
+}
+
+// dryRunUpdate does a dry run of the update of the VM, and records the changes the update would
+// make in the VM's status instead of making them. A VM that would be powered on gets the device
+// keys and backings of its NICs from its existing network interface CRs. Neither the VM, its
+// cluster, nor the network interface CRs are changed, and the VM's conditions and annotations
+// are left as they were.
+func (s *Session) dryRunUpdate(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	resVM *res.VirtualMachine,
+	moVM *mo.VirtualMachine,
+	getUpdateArgsFn func() (*VMUpdateArgs, error)) error {
+
+	vm := vmCtx.VM.DeepCopy()
+
+	s.dryRun = &dryRunPlan{}
+	defer func() {
+		s.dryRun = nil
+	}()
+
+	err := s.updateVirtualMachine(vmCtx, vcVM, resVM, moVM, getUpdateArgsFn)
+
+	vmCtx.VM.Annotations = vm.Annotations
+	vmCtx.VM.Status = vm.Status
+	if err != nil {
+		return err
+	}
+
+	plan, err := s.dryRun.reconfigurePlan()
+	if err != nil {
+		return err
+	}
+
+	vmCtx.Logger.Info("Dry run of VM reconfigure", "reconfigurePlan", plan)
+	vmCtx.VM.Status.ReconfigurePlan = plan
+	return nil
+}
+
+func (p *dryRunPlan) reconfigurePlan() (*vmopv1.VirtualMachineReconfigurePlan, error) {
+	plan, err := ReconfigurePlan(p.configSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, clusterSpec := range p.clusterSpecs {
+		var buf bytes.Buffer
+		if err := vimTypes.NewJSONEncoder(&buf).Encode(clusterSpec); err != nil {
+			return nil, err
+		}
+		plan.ClusterConfigSpecs = append(plan.ClusterConfigSpecs, string(bytes.TrimSpace(buf.Bytes())))
+	}
+
+	plan.HardwareVersion = p.hardwareVersion
+	plan.PowerState = p.powerState
+	plan.DisplayName = p.displayName
+	plan.BootDiskStorageProfileID = p.bootDiskStorageProfileID
+	plan.AttachedTags = p.tagChanges.Attach
+	plan.DetachedTags = p.tagChanges.Detach
+
+	return plan, nil
+}
+
+// ReconfigurePlan returns the plan of the reconfigures of the VM with the ConfigSpecs. The
+// ConfigSpecs that are nil or empty are omitted since the VM is not reconfigured with them.
+func ReconfigurePlan(configSpecs []*vimTypes.VirtualMachineConfigSpec) (*vmopv1.VirtualMachineReconfigurePlan, error) {
+	plan := &vmopv1.VirtualMachineReconfigurePlan{}
+	configKeys := map[string]struct{}{}
+	extraConfigKeys := map[string]struct{}{}
+
+	defaultConfigSpec := &vimTypes.VirtualMachineConfigSpec{}
+	for _, configSpec := range configSpecs {
+		if configSpec == nil || apiEquality.Semantic.DeepEqual(configSpec, defaultConfigSpec) {
+			continue
+		}
+
+		for _, deviceChange := range configSpec.DeviceChange {
+			spec := deviceChange.GetVirtualDeviceConfigSpec()
+			if spec.Device == nil {
+				continue
+			}
+
+			vd := spec.Device.GetVirtualDevice()
+			device := vmopv1.VirtualMachineReconfigurePlanDevice{
+				Key:  vd.Key,
+				Type: object.VirtualDeviceList{}.TypeName(spec.Device),
+			}
+			if info := vd.DeviceInfo; info != nil {
+				device.Label = info.GetDescription().Label
+			}
+
+			switch spec.Operation {
+			case vimTypes.VirtualDeviceConfigSpecOperationAdd:
+				plan.AddedDevices = append(plan.AddedDevices, device)
+			case vimTypes.VirtualDeviceConfigSpecOperationRemove:
+				plan.RemovedDevices = append(plan.RemovedDevices, device)
+			case vimTypes.VirtualDeviceConfigSpecOperationEdit:
+				plan.EditedDevices = append(plan.EditedDevices, device)
+			}
+		}
+
+		for _, ec := range configSpec.ExtraConfig {
+			extraConfigKeys[ec.GetOptionValue().Key] = struct{}{}
+		}
+
+		// The names of the changed fields are the JSON fields of the ConfigSpec since the empty
+		// fields are omitted.
+		fields := *configSpec
+		fields.DeviceChange, fields.ExtraConfig = nil, nil
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var fieldsMap map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fieldsMap); err != nil {
+			return nil, err
+		}
+		for k := range fieldsMap {
+			configKeys[k] = struct{}{}
+		}
+
+		var buf bytes.Buffer
+		if err := vimTypes.NewJSONEncoder(&buf).Encode(configSpec); err != nil {
+			return nil, err
+		}
+		plan.ConfigSpecs = append(plan.ConfigSpecs, string(bytes.TrimSpace(buf.Bytes())))
+	}
+
+	plan.ConfigKeys = sortedKeys(configKeys)
+	plan.ExtraConfigKeys = sortedKeys(extraConfigKeys)
+
+	return plan, nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	res "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/resources"
+)
+
+// reconfigureVM reconfigures the VM, and records an event when the reconfigure task is started
+// and a warning event with the task's fault if it fails. The task is tracked while it runs. In a
+// dry run, the ConfigSpec is only recorded in the plan.
+func (s *Session) reconfigureVM(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	configSpec *vimTypes.VirtualMachineConfigSpec) error {
+
+	if s.dryRun != nil {
+		s.dryRun.configSpecs = append(s.dryRun.configSpecs, configSpec)
+		return nil
+	}
+
+	var taskRef vimTypes.ManagedObjectReference
+	untrackFn := func() {}
+	err := resVM.ReconfigureWithTaskFn(vmCtx, configSpec, func(ref vimTypes.ManagedObjectReference) {
+		taskRef = ref
+		s.recordEventf(vmCtx, "ReconfigureStarted", "Reconfigure VM task %s started", ref.Value)
+		// The reconfigure is retried with a new task on a transient fault.
+		untrackFn()
+		untrackFn = s.trackTask(ref)
+	})
+	untrackFn()
+	if err != nil {
+		s.recordTaskWarning(vmCtx, "ReconfigureFailed", "Reconfigure VM", taskRef, err)
+	}
+
+	return err
+}
+
+// powerOnVM powers on the VM, on the host when not nil, and records an event when the power on
+// task is started and a warning event with the task's fault if it fails. The task is tracked while
+// it runs. In a dry run, the power on is only recorded in the plan.
+func (s *Session) powerOnVM(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	host *vimTypes.ManagedObjectReference) error {
+
+	if s.dryRun != nil {
+		s.dryRun.powerState = vmopv1.VirtualMachinePowerStateOn
+		return nil
+	}
+
+	var taskRef vimTypes.ManagedObjectReference
+	untrackFn := func() {}
+	err := resVM.PowerOn(vmCtx, host, func(ref vimTypes.ManagedObjectReference) {
+		taskRef = ref
+		s.recordEventf(vmCtx, "PowerOnStarted", "Power on VM task %s started", ref.Value)
+		untrackFn = s.trackTask(ref)
+	})
+	untrackFn()
+	if err != nil {
+		s.recordTaskWarning(vmCtx, "PowerOnFailed", "Power on VM", taskRef, err)
+	}
+
+	return err
+}
+
+// recordGuestEvents records events for the changes to the VM's guest that were observed by the
+// latest status update: VMware Tools starting to send the guest heartbeat, and the completion or
+// failure of the guest customization. The conditions are from before the status update.
+func (s *Session) recordGuestEvents(
+	vmCtx context.VirtualMachineContextA2,
+	prevToolsCondition *metav1.Condition,
+	prevCustomizationCondition *metav1.Condition) {
+
+	if vmCtx.VM.Status.PowerState != vmopv1.VirtualMachinePowerStateOn {
+		return
+	}
+
+	if c := conditions.Get(vmCtx.VM, vmopv1.VirtualMachineToolsCondition); c != nil && c.Status == metav1.ConditionTrue {
+		if prevToolsCondition == nil || prevToolsCondition.Status != metav1.ConditionTrue {
+			s.recordEventf(vmCtx, "GuestHeartbeatDetected", "VMware Tools is running in the guest")
+		}
+	}
+
+	// Only a customization that was observed to be pending or running is reported as completed,
+	// since the condition is also true when the VM was never customized.
+	if c := conditions.Get(vmCtx.VM, vmopv1.GuestCustomizationCondition); c != nil && prevCustomizationCondition != nil {
+		wasInProgress := prevCustomizationCondition.Status == metav1.ConditionFalse &&
+			(prevCustomizationCondition.Reason == vmopv1.GuestCustomizationPendingReason ||
+				prevCustomizationCondition.Reason == vmopv1.GuestCustomizationRunningReason)
+
+		switch {
+		case c.Status == metav1.ConditionTrue && wasInProgress:
+			s.recordEventf(vmCtx, "CustomizationCompleted", "Guest customization completed")
+		case c.Status == metav1.ConditionFalse && c.Reason == vmopv1.GuestCustomizationFailedReason &&
+			prevCustomizationCondition.Reason != vmopv1.GuestCustomizationFailedReason:
+			s.recordWarningf(vmCtx, "CustomizationFailed", "Guest customization failed: %s", c.Message)
+		}
+	}
+}
+
+// trackTask calls TrackTaskFn, if set, with the task, and returns the func to call once the task
+// has completed.
+func (s *Session) trackTask(taskRef vimTypes.ManagedObjectReference) func() {
+	if s.TrackTaskFn == nil {
+		return func() {}
+	}
+	return s.TrackTaskFn(taskRef)
+}
+
+func (s *Session) recordTaskWarning(
+	vmCtx context.VirtualMachineContextA2,
+	reason, op string,
+	taskRef vimTypes.ManagedObjectReference,
+	err error) {
+
+	if taskRef.Value == "" {
+		s.recordWarningf(vmCtx, reason, "%s failed: %v", op, err)
+		return
+	}
+
+	s.recordWarningf(vmCtx, reason, "%s task %s failed: %v", op, taskRef.Value, err)
+}
+
+func (s *Session) recordEventf(vmCtx context.VirtualMachineContextA2, reason, message string, args ...interface{}) {
+	if s.Recorder != nil {
+		s.Recorder.Eventf(vmCtx.VM, reason, message, args...)
+	}
+}
+
+func (s *Session) recordWarningf(vmCtx context.VirtualMachineContextA2, reason, message string, args ...interface{}) {
+	if s.Recorder != nil {
+		s.Recorder.Warnf(vmCtx.VM, reason, message, args...)
+	}
+}
@@ -6,18 +6,22 @@ package session
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"k8s.io/utils/pointer"
 
 	"github.com/go-logr/logr"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
 	apiEquality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
@@ -27,6 +31,8 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
 	network2 "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
 	res "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/resources"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/storage"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vmlifecycle"
 )
@@ -44,6 +50,10 @@ type VMUpdateArgs struct {
 
 	NetworkResults network2.NetworkInterfaceResults
 
+	// PowerOnHost is the host the VM's CPU affinity was validated against, so the VM must be
+	// powered on that host instead of the host DRS selects.
+	PowerOnHost *vimTypes.ManagedObjectReference
+
 	// hack. Remove after VMSVC-1261.
 	// indicating if this VM image used is VM service v1alpha1 compatible.
 	VirtualMachineImageV1Alpha1Compatible bool
@@ -332,6 +342,13 @@ func UpdateConfigSpecExtraConfig(
 
 	configSpec.ExtraConfig = util.MergeExtraConfig(config.ExtraConfig, extraConfig)
 
+	// A VM that was kept for the deletion grace period of its deleted CR has been reattached to a
+	// CR, so it is no longer destroyed. An empty value removes the key.
+	if _, ok := util.ExtraConfigToMap(config.ExtraConfig)[constants.VMDestroyTimeExtraConfigKey]; ok {
+		configSpec.ExtraConfig = append(configSpec.ExtraConfig,
+			&vimTypes.OptionValue{Key: constants.VMDestroyTimeExtraConfigKey, Value: ""})
+	}
+
 	// Enabling the defer-cloud-init extraConfig key for V1Alpha1Compatible images defers cloud-init from running on first boot
 	// and disables networking configurations by cloud-init. Therefore, only set the extraConfig key to enabled
 	// when the vmMetadata is nil or when the transport requested is not CloudInit.
@@ -391,6 +408,186 @@ func UpdateConfigSpecChangeBlockTracking(
 	}
 }
 
+func UpdateConfigSpecCPUAffinity(
+	config *vimTypes.VirtualMachineConfigInfo,
+	configSpec *vimTypes.VirtualMachineConfigSpec,
+	vmSpec vmopv1.VirtualMachineSpec) {
+
+	var affinity, current []int32
+	if adv := vmSpec.Advanced; adv != nil {
+		affinity = adv.CPUAffinity
+	}
+	if config.CpuAffinity != nil {
+		current = config.CpuAffinity.AffinitySet
+	}
+
+	if !sets.New(affinity...).Equal(sets.New(current...)) {
+		// An empty AffinitySet clears the VM's CPU affinity.
+		configSpec.CpuAffinity = &vimTypes.VirtualMachineAffinityInfo{
+			AffinitySet: affinity,
+		}
+	}
+}
+
+// vmSwapPlacements are the ConfigSpec swap placements of the VM's spec swap placements.
+var vmSwapPlacements = map[vmopv1.VirtualMachineSwapPlacement]vimTypes.VirtualMachineConfigInfoSwapPlacementType{
+	vmopv1.VirtualMachineSwapPlacementInherit:     vimTypes.VirtualMachineConfigInfoSwapPlacementTypeInherit,
+	vmopv1.VirtualMachineSwapPlacementVMDirectory: vimTypes.VirtualMachineConfigInfoSwapPlacementTypeVmDirectory,
+	vmopv1.VirtualMachineSwapPlacementHostLocal:   vimTypes.VirtualMachineConfigInfoSwapPlacementTypeHostLocal,
+}
+
+func UpdateConfigSpecSwapPlacement(
+	config *vimTypes.VirtualMachineConfigInfo,
+	configSpec *vimTypes.VirtualMachineConfigSpec,
+	vmSpec vmopv1.VirtualMachineSpec) {
+
+	if vmSpec.Advanced == nil || vmSpec.Advanced.SwapPlacement == "" {
+		return
+	}
+
+	if placement, ok := vmSwapPlacements[vmSpec.Advanced.SwapPlacement]; ok && config.SwapPlacement != string(placement) {
+		configSpec.SwapPlacement = string(placement)
+	}
+}
+
+// vmToolsUpgradePolicies are the ConfigSpec tools upgrade policies of the VM's spec tools
+// upgrade policies.
+var vmToolsUpgradePolicies = map[vmopv1.VirtualMachineToolsUpgradePolicy]vimTypes.UpgradePolicy{
+	vmopv1.VirtualMachineToolsUpgradePolicyManual:              vimTypes.UpgradePolicyManual,
+	vmopv1.VirtualMachineToolsUpgradePolicyUpgradeAtPowerCycle: vimTypes.UpgradePolicyUpgradeAtPowerCycle,
+}
+
+func UpdateConfigSpecToolsUpgradePolicy(
+	config *vimTypes.VirtualMachineConfigInfo,
+	configSpec *vimTypes.VirtualMachineConfigSpec,
+	vmSpec vmopv1.VirtualMachineSpec) {
+
+	if vmSpec.Advanced == nil || vmSpec.Advanced.ToolsUpgradePolicy == "" {
+		return
+	}
+
+	policy, ok := vmToolsUpgradePolicies[vmSpec.Advanced.ToolsUpgradePolicy]
+	if !ok {
+		return
+	}
+
+	if config.Tools == nil || config.Tools.ToolsUpgradePolicy != string(policy) {
+		if configSpec.Tools == nil {
+			configSpec.Tools = &vimTypes.ToolsConfigInfo{}
+		}
+		configSpec.Tools.ToolsUpgradePolicy = string(policy)
+	}
+}
+
+// UpdateConfigSpecBootOrder sets the ConfigSpec boot order to the VM's disks and network
+// interfaces, and CD-ROM, in the order of the spec's boot order device types. The types of
+// devices the VM does not have any device of are left out of the boot order, and returned.
+func UpdateConfigSpecBootOrder(
+	config *vimTypes.VirtualMachineConfigInfo,
+	configSpec *vimTypes.VirtualMachineConfigSpec,
+	vmSpec vmopv1.VirtualMachineSpec) []string {
+
+	if vmSpec.Advanced == nil || len(vmSpec.Advanced.BootOrder) == 0 {
+		return nil
+	}
+
+	devices := object.VirtualDeviceList(config.Hardware.Device)
+
+	var bootOrder []vimTypes.BaseVirtualMachineBootOptionsBootableDevice
+	var missing []string
+
+	for _, deviceType := range vmSpec.Advanced.BootOrder {
+		n := len(bootOrder)
+
+		switch deviceType {
+		case vmopv1.VirtualMachineBootDeviceTypeDisk:
+			for _, d := range devices.SelectByType((*vimTypes.VirtualDisk)(nil)) {
+				bootOrder = append(bootOrder, &vimTypes.VirtualMachineBootOptionsBootableDiskDevice{
+					DeviceKey: d.GetVirtualDevice().Key,
+				})
+			}
+		case vmopv1.VirtualMachineBootDeviceTypeNetwork:
+			for _, d := range devices.SelectByType((*vimTypes.VirtualEthernetCard)(nil)) {
+				bootOrder = append(bootOrder, &vimTypes.VirtualMachineBootOptionsBootableEthernetDevice{
+					DeviceKey: d.GetVirtualDevice().Key,
+				})
+			}
+		case vmopv1.VirtualMachineBootDeviceTypeCDROM:
+			if len(devices.SelectByType((*vimTypes.VirtualCdrom)(nil))) > 0 {
+				bootOrder = append(bootOrder, &vimTypes.VirtualMachineBootOptionsBootableCdromDevice{})
+			}
+		}
+
+		if len(bootOrder) == n {
+			missing = append(missing, string(deviceType))
+		}
+	}
+
+	var bootOptions vimTypes.VirtualMachineBootOptions
+	if config.BootOptions != nil {
+		bootOptions = *config.BootOptions
+	}
+
+	if !apiEquality.Semantic.DeepEqual(bootOptions.BootOrder, bootOrder) {
+		// Keep the VM's other boot options.
+		bootOptions.BootOrder = bootOrder
+		configSpec.BootOptions = &bootOptions
+	}
+
+	return missing
+}
+
+// UpdateSerialPortDeviceChanges returns the device changes that add the spec's network serial ports
+// the VM does not have, and remove the VM's network serial ports that are not in the spec. The
+// VM's other serial ports are not changed.
+func UpdateSerialPortDeviceChanges(
+	vmSpec vmopv1.VirtualMachineSpec,
+	currentDevices object.VirtualDeviceList) []vimTypes.BaseVirtualDeviceConfigSpec {
+
+	var ports []vmopv1.VirtualMachineSerialPortSpec
+	if vmSpec.Advanced != nil {
+		ports = vmSpec.Advanced.SerialPorts
+	}
+
+	currentSerialPorts := currentDevices.Select(virtualmachine.IsNetworkSerialPort)
+
+	var missing []vmopv1.VirtualMachineSerialPortSpec
+	for _, port := range ports {
+		matchingIdx := -1
+		for idx, dev := range currentSerialPorts {
+			if virtualmachine.SerialPortMatch(dev, port) {
+				matchingIdx = idx
+				break
+			}
+		}
+
+		if matchingIdx == -1 {
+			missing = append(missing, port)
+		} else {
+			currentSerialPorts = append(currentSerialPorts[:matchingIdx], currentSerialPorts[matchingIdx+1:]...)
+		}
+	}
+
+	deviceChanges := make([]vimTypes.BaseVirtualDeviceConfigSpec, 0, len(currentSerialPorts)+len(missing))
+
+	// Process any removes first.
+	for _, dev := range currentSerialPorts {
+		deviceChanges = append(deviceChanges, &vimTypes.VirtualDeviceConfigSpec{
+			Device:    dev,
+			Operation: vimTypes.VirtualDeviceConfigSpecOperationRemove,
+		})
+	}
+
+	for _, dev := range virtualmachine.CreateSerialPortDevices(missing) {
+		deviceChanges = append(deviceChanges, &vimTypes.VirtualDeviceConfigSpec{
+			Device:    dev,
+			Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+		})
+	}
+
+	return deviceChanges
+}
+
 func UpdateHardwareConfigSpec(
 	config *vimTypes.VirtualMachineConfigInfo,
 	configSpec *vimTypes.VirtualMachineConfigSpec,
@@ -447,11 +644,12 @@ func updateConfigSpec(
 
 	// Before VM Class as Config, VMs were deployed from the OVA, and are then
 	// reconfigured to match the desired CPU and memory reservation.  Maintain that
-	// behavior.  With the FSS enabled, VMs will be _created_ with desired HW spec, and we
-	// will not modify the hardware of the VM post creation.  So, don't populate the
-	// Hardware config and CPU/Memory reservation.
+	// behavior.  With the FSS enabled, VMs will be _created_ with desired HW spec, so
+	// only the CPU and memory of the VM are updated post creation when the VM Class
+	// changes. Those changes are either hot applied to a powered on VM, or deferred
+	// until here if the VM must be power cycled.
+	UpdateHardwareConfigSpec(config, configSpec, &vmClassSpec)
 	if !lib.IsVMClassAsConfigFSSDaynDateEnabled() {
-		UpdateHardwareConfigSpec(config, configSpec, &vmClassSpec)
 		UpdateConfigSpecCPUAllocation(config, configSpec, &vmClassSpec, updateArgs.MinCPUFreq)
 		UpdateConfigSpecMemoryAllocation(config, configSpec, &vmClassSpec)
 	}
@@ -461,6 +659,9 @@ func updateConfigSpec(
 	UpdateConfigSpecExtraConfig(config, configSpec, updateArgs.ConfigSpec, &vmClassSpec,
 		vmCtx.VM, updateArgs.ExtraConfig, updateArgs.VirtualMachineImageV1Alpha1Compatible)
 	UpdateConfigSpecChangeBlockTracking(config, configSpec, updateArgs.ConfigSpec, vmCtx.VM.Spec)
+	UpdateConfigSpecCPUAffinity(config, configSpec, vmCtx.VM.Spec)
+	UpdateConfigSpecSwapPlacement(config, configSpec, vmCtx.VM.Spec)
+	UpdateConfigSpecToolsUpgradePolicy(config, configSpec, vmCtx.VM.Spec)
 	UpdateConfigSpecFirmware(config, configSpec, vmCtx.VM)
 
 	return configSpec
@@ -511,6 +712,13 @@ func (s *Session) prePowerOnVMConfigSpec(
 	}
 	configSpec.DeviceChange = append(configSpec.DeviceChange, pciDeviceChanges...)
 
+	// The guest reads its vApp properties when it boots, so the changes to the VM's vApp
+	// properties are applied before the VM is powered on.
+	if vAppConfigSpec := vmlifecycle.GetVAppPropertiesConfigSpec(
+		config, vmCtx.VM.Spec.VAppProperties, updateArgs.BootstrapData.VAppExData); vAppConfigSpec != nil {
+		configSpec.VAppConfig = vAppConfigSpec
+	}
+
 	return configSpec, nil
 }
 
@@ -525,203 +733,920 @@ func (s *Session) prePowerOnVMReconfigure(
 		return err
 	}
 
+	if adv := vmCtx.VM.Spec.Advanced; adv != nil && len(adv.CPUAffinity) > 0 {
+		host, err := validateCPUAffinity(vmCtx, resVM, adv.CPUAffinity)
+		if err != nil {
+			return err
+		}
+		updateArgs.PowerOnHost = &host
+	}
+
+	if configSpec.SwapPlacement == string(vimTypes.VirtualMachineConfigInfoSwapPlacementTypeHostLocal) {
+		if err := validateHostLocalSwapDatastore(vmCtx, resVM, config, configSpec); err != nil {
+			return err
+		}
+	}
+
 	defaultConfigSpec := &vimTypes.VirtualMachineConfigSpec{}
 	if !apiEquality.Semantic.DeepEqual(configSpec, defaultConfigSpec) {
 		vmCtx.Logger.Info("Pre PowerOn Reconfigure", "configSpec", configSpec)
-		if err := resVM.Reconfigure(vmCtx, configSpec); err != nil {
+		if err := s.reconfigureVM(vmCtx, resVM, configSpec); err != nil {
 			vmCtx.Logger.Error(err, "pre power on reconfigure failed")
+			if lib.IsVMClassAsConfigFSSDaynDateEnabled() {
+				conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionClassConfigurationSynced,
+					vmopv1.VirtualMachineClassConfigurationReconfigureFailedReason, err.Error())
+			}
 			return err
 		}
 	}
 
-	return nil
-}
-
-func (s *Session) ensureNetworkInterfaces(
-	vmCtx context.VirtualMachineContextA2,
-	configSpec *vimTypes.VirtualMachineConfigSpec) (network2.NetworkInterfaceResults, error) {
-
-	networkSpec := vmCtx.VM.Spec.Network
-	if networkSpec == nil || networkSpec.Disabled {
-		return network2.NetworkInterfaceResults{}, nil
+	if err := s.reconfigureSerialPorts(vmCtx, resVM, false); err != nil {
+		return err
 	}
 
-	// This negative device key is the traditional range used for network interfaces.
-	deviceKey := int32(-100)
-
-	var networkDevices []vimTypes.BaseVirtualDevice
-	if lib.IsVMClassAsConfigFSSDaynDateEnabled() && configSpec != nil {
-		networkDevices = util.SelectDevicesByTypes(
-			util.DevicesFromConfigSpec(configSpec),
-			&vimTypes.VirtualE1000{},
-			&vimTypes.VirtualE1000e{},
-			&vimTypes.VirtualPCNet32{},
-			&vimTypes.VirtualVmxnet2{},
-			&vimTypes.VirtualVmxnet3{},
-			&vimTypes.VirtualVmxnet3Vrdma{},
-			&vimTypes.VirtualSriovEthernetCard{},
-		)
+	if err := s.reconfigureBootOrder(vmCtx, resVM, false); err != nil {
+		return err
 	}
 
-	clusterMoRef := s.Cluster.Reference()
-	results, err := network2.CreateAndWaitForNetworkInterfaces(
-		vmCtx,
-		s.K8sClient,
-		s.Client.VimClient(),
-		s.Finder,
-		&clusterMoRef,
-		networkSpec.Interfaces)
-	if err != nil {
-		return network2.NetworkInterfaceResults{}, err
+	if lib.IsVMClassAsConfigFSSDaynDateEnabled() {
+		conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionClassConfigurationSynced)
 	}
+	vmCtx.VM.Status.ObservedClassGeneration = updateArgs.VMClass.Generation
 
-	// XXX: The following logic assumes that the order of network interfaces specified in the
-	// VM spec matches one to one with the device changes in the ConfigSpec in VM class.
-	// This is a safe assumption for now since VM service only supports one network interface.
-	// TODO: Needs update when VM Service supports VMs with more then one network interface.
-	for idx := range results.Results {
-		result := &results.Results[idx]
-
-		dev, err := network2.CreateDefaultEthCard(vmCtx, result)
-		if err != nil {
-			return network2.NetworkInterfaceResults{}, err
-		}
-
-		if lib.IsVMClassAsConfigFSSDaynDateEnabled() {
-			// If VM Class-as-a-Config is supported, we use the network device from the Class.
-			// If the VM class doesn't specify enough number of network devices, we fall back to default behavior.
-			if idx < len(networkDevices) {
-				ethCardFromNetProvider := dev.(vimTypes.BaseVirtualEthernetCard)
-
-				if mac := ethCardFromNetProvider.GetVirtualEthernetCard().MacAddress; mac != "" {
-					networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().MacAddress = mac
-					networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().AddressType = string(vimTypes.VirtualEthernetCardMacTypeManual)
-				}
-
-				networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().ExternalId =
-					ethCardFromNetProvider.GetVirtualEthernetCard().ExternalId
-				// If the device from VM class has a DVX backing, this should still work if the backing as well
-				// as the DVX backing are set. VPXD checks for DVX backing before checking for normal device backings.
-				networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().Backing =
-					ethCardFromNetProvider.GetVirtualEthernetCard().Backing
-
-				dev = networkDevices[idx]
-			}
-		}
+	return nil
+}
 
-		// govmomi assigns a random device key. Fix that up here.
-		dev.GetVirtualDevice().Key = deviceKey
-		deviceKey--
+// reconfigureBootOrder changes the VM's boot order to match its spec. The boot order may only be
+// changed while the VM is powered off, so for a powered on VM the change is deferred until the
+// VM is next powered off. A type of device in the boot order the VM does not have any device of
+// does not fail the reconfigure, but leaves the VM's boot order degraded.
+func (s *Session) reconfigureBootOrder(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	poweredOn bool) error {
 
-		result.Device = dev
+	if vmCtx.VM.Spec.Advanced == nil || len(vmCtx.VM.Spec.Advanced.BootOrder) == 0 {
+		conditions.Delete(vmCtx.VM, vmopv1.VirtualMachineConditionBootOrderSynced)
+		return nil
 	}
 
-	return results, nil
-}
+	configSpec, missing, err := bootOrderConfigSpec(vmCtx, resVM)
+	if err != nil {
+		return err
+	}
 
-func (s *Session) ensureCNSVolumes(vmCtx context.VirtualMachineContextA2) error {
-	// If VM spec has a PVC, check if the volume is attached before powering on
-	for _, volume := range vmCtx.VM.Spec.Volumes {
-		if volume.PersistentVolumeClaim == nil {
-			// Only handle PVC volumes here. In v1a1 we had non-PVC ("vsphereVolumes") but those are gone.
-			continue
+	if configSpec.BootOptions != nil {
+		if poweredOn {
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootOrderSynced,
+				vmopv1.VirtualMachineBootOrderPendingPowerOffReason,
+				"Boot order will be changed when the VM is powered off")
+			return nil
 		}
 
-		// BMV: We should not use the Status as the SoT here. What a mess.
-		found := false
-		for _, volumeStatus := range vmCtx.VM.Status.Volumes {
-			if volumeStatus.Name == volume.Name {
-				found = true
-				if !volumeStatus.Attached {
-					return fmt.Errorf("persistent volume: %s not attached to VM", volume.Name)
-				}
-				break
-			}
+		vmCtx.Logger.Info("Boot order reconfigure", "bootOrder", configSpec.BootOptions.BootOrder)
+		if err := s.reconfigureVM(vmCtx, resVM, configSpec); err != nil {
+			vmCtx.Logger.Error(err, "boot order reconfigure failed")
+			return err
 		}
+	}
 
-		if !found {
-			return fmt.Errorf("status update pending for persistent volume: %s on VM", volume.Name)
-		}
+	if len(missing) > 0 {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootOrderSynced,
+			vmopv1.VirtualMachineBootOrderDeviceNotFoundReason,
+			"VM does not have a %s device to boot from", strings.Join(missing, " or "))
+		return nil
 	}
 
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootOrderSynced)
 	return nil
 }
 
-func (s *Session) customize(
+// bootOrderConfigSpec returns the ConfigSpec that changes the VM's boot order to match its spec,
+// and the types of devices in the boot order that the VM does not have any device of.
+func bootOrderConfigSpec(
 	vmCtx context.VirtualMachineContextA2,
-	resVM *res.VirtualMachine,
-	cfg *vimTypes.VirtualMachineConfigInfo,
-	updateArgs *VMUpdateArgs) error {
-
-	{
-		// Hack: the old code only populated the first nic address - getFirstNicMacAddr() - so just keep
-		// doing the same here. We need a generalized UpdateEthCardDeviceChanges() to match up the Spec
-		// with the actual devices. Old code also made this best effort so do that here too.
-		// I've got a larger change that removes the old session stuff, and improves on all this behavior
-		// but I didn't have the BW to sort out all the changes.
-		if len(updateArgs.NetworkResults.Results) > 0 {
-			mac := updateArgs.NetworkResults.Results[0].MacAddress
-			if mac == "" {
-				ethCards, _ := resVM.GetNetworkDevices(vmCtx)
-				if len(ethCards) > 0 {
-					curNic := ethCards[0].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard()
-					updateArgs.NetworkResults.Results[0].MacAddress = curNic.GetVirtualEthernetCard().MacAddress
-				}
-			}
-		}
-	}
+	resVM *res.VirtualMachine) (*vimTypes.VirtualMachineConfigSpec, []string, error) {
 
-	err := vmlifecycle.DoBootstrap(vmCtx, resVM.VcVM(), cfg, s.K8sClient, updateArgs.NetworkResults, updateArgs.BootstrapData)
+	// The boot order refers to the VM's devices by key, so get the devices here since they
+	// may have just changed.
+	moVM, err := resVM.GetProperties(vmCtx, []string{"config.hardware.device", "config.bootOptions"})
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	return nil
+	configSpec := &vimTypes.VirtualMachineConfigSpec{}
+	missing := UpdateConfigSpecBootOrder(moVM.Config, configSpec, vmCtx.VM.Spec)
+	return configSpec, missing, nil
 }
 
-func (s *Session) prepareVMForPowerOn(
+// reconfigureSerialPorts adds and removes the VM's network serial ports to match its spec. A
+// powered on VM is only reconfigured when its hardware supports hot-plugging the serial ports,
+// otherwise the change is deferred until the VM is next powered off.
+func (s *Session) reconfigureSerialPorts(
 	vmCtx context.VirtualMachineContextA2,
 	resVM *res.VirtualMachine,
-	cfg *vimTypes.VirtualMachineConfigInfo,
-	updateArgs *VMUpdateArgs) error {
+	poweredOn bool) error {
 
-	netIfList, err := s.ensureNetworkInterfaces(vmCtx, updateArgs.ConfigSpec)
+	configSpec, pendingPowerOff, err := s.serialPortsConfigSpec(vmCtx, resVM, poweredOn)
 	if err != nil {
 		return err
 	}
 
-	updateArgs.NetworkResults = netIfList
-
-	err = s.prePowerOnVMReconfigure(vmCtx, resVM, cfg, updateArgs)
-	if err != nil {
-		return err
+	if configSpec == nil {
+		if vmCtx.VM.Spec.Advanced == nil || len(vmCtx.VM.Spec.Advanced.SerialPorts) == 0 {
+			conditions.Delete(vmCtx.VM, vmopv1.VirtualMachineConditionSerialPortsSynced)
+		} else {
+			conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionSerialPortsSynced)
+		}
+		return nil
 	}
 
-	err = s.customize(vmCtx, resVM, cfg, updateArgs)
-	if err != nil {
-		return err
+	if pendingPowerOff {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionSerialPortsSynced,
+			vmopv1.VirtualMachineSerialPortsPendingPowerOffReason,
+			"Serial ports will be changed when the VM is powered off")
+		return nil
 	}
 
-	err = s.ensureCNSVolumes(vmCtx)
-	if err != nil {
+	vmCtx.Logger.Info("Serial ports reconfigure", "deviceChanges", configSpec.DeviceChange)
+	if err := s.reconfigureVM(vmCtx, resVM, configSpec); err != nil {
+		vmCtx.Logger.Error(err, "serial ports reconfigure failed")
 		return err
 	}
 
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionSerialPortsSynced)
 	return nil
 }
 
-func (s *Session) poweredOnVMReconfigure(
+// serialPortsConfigSpec returns the ConfigSpec that adds and removes the VM's network serial ports
+// to match its spec, or nil if the serial ports already match. For a powered on VM, it also returns
+// whether the change must wait until the VM is powered off since its hardware does not support
+// hot-plugging the serial ports.
+func (s *Session) serialPortsConfigSpec(
 	vmCtx context.VirtualMachineContextA2,
 	resVM *res.VirtualMachine,
-	config *vimTypes.VirtualMachineConfigInfo) error {
+	poweredOn bool) (*vimTypes.VirtualMachineConfigSpec, bool, error) {
 
-	configSpec := &vimTypes.VirtualMachineConfigSpec{}
-	UpdateConfigSpecChangeBlockTracking(config, configSpec, nil, vmCtx.VM.Spec)
+	moVM, err := resVM.GetProperties(vmCtx, []string{"config.hardware.device", "config.version", "config.guestId"})
+	if err != nil {
+		return nil, false, err
+	}
+
+	deviceChanges := UpdateSerialPortDeviceChanges(vmCtx.VM.Spec, moVM.Config.Hardware.Device)
+	if len(deviceChanges) == 0 {
+		return nil, false, nil
+	}
+
+	configSpec := &vimTypes.VirtualMachineConfigSpec{DeviceChange: deviceChanges}
+
+	if poweredOn {
+		hotPlug, err := s.serialPortsHotPlugSupported(vmCtx, moVM.Config, deviceChanges)
+		if err != nil {
+			return nil, false, err
+		}
+		return configSpec, !hotPlug, nil
+	}
+
+	return configSpec, false, nil
+}
+
+// serialPortsHotPlugSupported returns true if the VM's hardware supports hot adding and hot
+// removing the serial ports in the device changes.
+func (s *Session) serialPortsHotPlugSupported(
+	vmCtx context.VirtualMachineContextA2,
+	config *vimTypes.VirtualMachineConfigInfo,
+	deviceChanges []vimTypes.BaseVirtualDeviceConfigSpec) (bool, error) {
+
+	if s.Cluster == nil {
+		return false, nil
+	}
+
+	option, err := virtualmachine.GetSerialPortOption(vmCtx, s.Cluster, config)
+	if err != nil || option == nil {
+		return false, err
+	}
+
+	for _, dc := range deviceChanges {
+		switch dc.GetVirtualDeviceConfigSpec().Operation {
+		case vimTypes.VirtualDeviceConfigSpecOperationAdd:
+			if !option.PlugAndPlay {
+				return false, nil
+			}
+		case vimTypes.VirtualDeviceConfigSpecOperationRemove:
+			if !pointer.BoolDeref(option.HotRemoveSupported, false) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// reconfigureFirstClassDisks attaches the VM's FirstClassDisk volumes that are not yet attached
+// to the VM, and detaches the FirstClassDisk volumes that were removed from the Spec. A detached
+// disk is not deleted since it is managed outside of VM Operator. Before a disk is attached, it
+// must exist and not be attached to another VM.
+func (s *Session) reconfigureFirstClassDisks(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	poweredOn bool) error {
+
+	configSpec, err := s.firstClassDisksConfigSpec(vmCtx, resVM, poweredOn)
+	if err != nil || configSpec == nil {
+		return err
+	}
+
+	vmCtx.Logger.Info("First class disks reconfigure", "configSpec", configSpec)
+	if err := s.reconfigureVM(vmCtx, resVM, configSpec); err != nil {
+		vmCtx.Logger.Error(err, "first class disks reconfigure failed")
+		return err
+	}
+
+	return nil
+}
+
+// firstClassDisksConfigSpec returns the ConfigSpec that attaches and detaches the VM's
+// FirstClassDisk volumes to match its spec, or nil if the attached disks already match.
+func (s *Session) firstClassDisksConfigSpec(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	poweredOn bool) (*vimTypes.VirtualMachineConfigSpec, error) {
+
+	diskIDs := virtualmachine.GetFirstClassDiskIDs(vmCtx.VM)
+	if len(diskIDs) == 0 {
+		return nil, nil
+	}
+
+	specDiskIDs := map[string]struct{}{}
+	for _, vol := range vmCtx.VM.Spec.Volumes {
+		if fcd := vol.FirstClassDisk; fcd != nil {
+			specDiskIDs[fcd.DiskID] = struct{}{}
+		}
+	}
+
+	devices, err := resVM.GetVirtualDevices(vmCtx)
+	if err != nil {
+		return nil, err
+	}
+	attachedDisks := virtualmachine.GetFirstClassDisks(devices)
+
+	var deviceChanges []vimTypes.BaseVirtualDeviceConfigSpec
+	var datastores []*object.Datastore
+
+	for _, diskID := range diskIDs {
+		disk, attached := attachedDisks[diskID]
+		_, inSpec := specDiskIDs[diskID]
+
+		if !inSpec {
+			if attached {
+				// The FileOperation is not set so the disk is not deleted.
+				deviceChanges = append(deviceChanges, &vimTypes.VirtualDeviceConfigSpec{
+					Operation: vimTypes.VirtualDeviceConfigSpecOperationRemove,
+					Device:    disk,
+				})
+			}
+			continue
+		}
+
+		if attached {
+			continue
+		}
+
+		// Check for a controller first since an IDE controller cannot have a disk hot added.
+		controller, err := virtualmachine.FindFirstClassDiskController(devices, poweredOn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find a controller for first class disk %s: %w", diskID, err)
+		}
+
+		if datastores == nil {
+			datastores, err = s.Finder.DatastoreList(vmCtx, "*")
+			if err != nil {
+				return nil, fmt.Errorf("failed to list datastores: %w", err)
+			}
+		}
+
+		obj, err := virtualmachine.FindFirstClassDisk(vmCtx, s.Client.VimClient(), datastores, diskID)
+		if err != nil {
+			return nil, err
+		}
+
+		vmRef, err := virtualmachine.GetFirstClassDiskVM(vmCtx, s.Client.VimClient(), obj, resVM.MoRef())
+		if err != nil {
+			return nil, err
+		}
+		if vmRef != nil {
+			return nil, fmt.Errorf("first class disk %s is already attached to VM %s", diskID, vmRef.Value)
+		}
+
+		newDisk, err := virtualmachine.CreateFirstClassDiskDevice(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		devices.AssignController(newDisk, controller)
+		// Include the new disk so the next disk is assigned a different unit number.
+		devices = append(devices, newDisk)
+
+		deviceChanges = append(deviceChanges, &vimTypes.VirtualDeviceConfigSpec{
+			Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+			Device:    newDisk,
+		})
+	}
+
+	if len(deviceChanges) == 0 {
+		return nil, nil
+	}
+
+	return &vimTypes.VirtualMachineConfigSpec{DeviceChange: deviceChanges}, nil
+}
+
+// vmHARestartPriorities are the cluster DAS VM settings restart priorities of the VM's spec HA
+// restart priorities.
+var vmHARestartPriorities = map[vmopv1.VirtualMachineHARestartPriority]vimTypes.ClusterDasVmSettingsRestartPriority{
+	vmopv1.VirtualMachineHARestartPriorityDisabled: vimTypes.ClusterDasVmSettingsRestartPriorityDisabled,
+	vmopv1.VirtualMachineHARestartPriorityLowest:   vimTypes.ClusterDasVmSettingsRestartPriorityLowest,
+	vmopv1.VirtualMachineHARestartPriorityLow:      vimTypes.ClusterDasVmSettingsRestartPriorityLow,
+	vmopv1.VirtualMachineHARestartPriorityMedium:   vimTypes.ClusterDasVmSettingsRestartPriorityMedium,
+	vmopv1.VirtualMachineHARestartPriorityHigh:     vimTypes.ClusterDasVmSettingsRestartPriorityHigh,
+	vmopv1.VirtualMachineHARestartPriorityHighest:  vimTypes.ClusterDasVmSettingsRestartPriorityHighest,
+}
+
+// vmHAIsolationResponses are the cluster DAS VM settings isolation responses of the VM's spec HA
+// isolation responses.
+var vmHAIsolationResponses = map[vmopv1.VirtualMachineHAIsolationResponse]vimTypes.ClusterDasVmSettingsIsolationResponse{
+	vmopv1.VirtualMachineHAIsolationResponseNone:     vimTypes.ClusterDasVmSettingsIsolationResponseNone,
+	vmopv1.VirtualMachineHAIsolationResponsePowerOff: vimTypes.ClusterDasVmSettingsIsolationResponsePowerOff,
+	vmopv1.VirtualMachineHAIsolationResponseShutdown: vimTypes.ClusterDasVmSettingsIsolationResponseShutdown,
+}
+
+// reconfigureHAOverride creates or updates the VM's vSphere HA override in the cluster's DAS VM
+// configuration when the VM specifies its HA restart priority or isolation response. The
+// cluster must have vSphere HA enabled. The settings that are not specified are left unchanged.
+// A failure is reported in the HAOverrideSynced condition rather than failing the update, so
+// it does not block the VM's other changes.
+func (s *Session) reconfigureHAOverride(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine) {
+
+	advanced := vmCtx.VM.Spec.Advanced
+	if advanced == nil || (advanced.HARestartPriority == "" && advanced.HAIsolationResponse == "") {
+		conditions.Delete(vmCtx.VM, vmopv1.VirtualMachineConditionHAOverrideSynced)
+		return
+	}
+
+	if err := s.reconfigureHAOverrideCluster(vmCtx, resVM); err != nil {
+		vmCtx.Logger.Error(err, "Failed to reconfigure vSphere HA override")
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionHAOverrideSynced,
+			vmopv1.VirtualMachineHAOverrideSyncFailedReason, "%v", err)
+		return
+	}
+
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionHAOverrideSynced)
+}
+
+func (s *Session) reconfigureHAOverrideCluster(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine) error {
+
+	clusterSpec, err := s.haOverrideClusterConfigSpec(vmCtx, resVM)
+	if err != nil || clusterSpec == nil {
+		return err
+	}
+
+	if s.dryRun != nil {
+		s.dryRun.clusterSpecs = append(s.dryRun.clusterSpecs, clusterSpec)
+		return nil
+	}
+
+	settings := clusterSpec.DasVmConfigSpec[0].Info.DasSettings
+	vmCtx.Logger.Info("Reconfiguring vSphere HA override", "operation", clusterSpec.DasVmConfigSpec[0].Operation,
+		"restartPriority", settings.RestartPriority, "isolationResponse", settings.IsolationResponse)
+	task, err := s.Cluster.Reconfigure(vmCtx, clusterSpec, true)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Wait(vmCtx); err != nil {
+		return fmt.Errorf("failed to reconfigure vSphere HA override of VM in cluster %s: %w", s.Cluster.Reference().Value, err)
+	}
+
+	return nil
+}
+
+// haOverrideClusterConfigSpec returns the ClusterConfigSpecEx that creates or updates the VM's
+// vSphere HA override to match its spec, or nil if the override already matches.
+func (s *Session) haOverrideClusterConfigSpec(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine) (*vimTypes.ClusterConfigSpecEx, error) {
+
+	advanced := vmCtx.VM.Spec.Advanced
+	if advanced == nil || (advanced.HARestartPriority == "" && advanced.HAIsolationResponse == "") {
+		return nil, nil
+	}
+
+	if s.Cluster == nil {
+		return nil, fmt.Errorf("VM is not in a cluster so vSphere HA settings cannot be configured")
+	}
+
+	clusterConfig, err := vcenter.GetClusterConfigInfoEx(vmCtx, s.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s configuration: %w", s.Cluster.Reference().Value, err)
+	}
+
+	if !pointer.BoolDeref(clusterConfig.DasConfig.Enabled, false) {
+		return nil, fmt.Errorf("cluster %s does not have vSphere HA enabled", s.Cluster.Reference().Value)
+	}
+
+	vmRef := resVM.MoRef()
+	var existing *vimTypes.ClusterDasVmConfigInfo
+	for i := range clusterConfig.DasVmConfig {
+		if clusterConfig.DasVmConfig[i].Key == vmRef {
+			existing = &clusterConfig.DasVmConfig[i]
+			break
+		}
+	}
+
+	settings := vimTypes.ClusterDasVmSettings{}
+	if existing != nil && existing.DasSettings != nil {
+		settings = *existing.DasSettings
+	}
+
+	if priority, ok := vmHARestartPriorities[advanced.HARestartPriority]; ok {
+		settings.RestartPriority = string(priority)
+	}
+	if response, ok := vmHAIsolationResponses[advanced.HAIsolationResponse]; ok {
+		settings.IsolationResponse = string(response)
+	}
+
+	operation := vimTypes.ArrayUpdateOperationAdd
+	if existing != nil {
+		if existing.DasSettings != nil && reflect.DeepEqual(*existing.DasSettings, settings) {
+			return nil, nil
+		}
+		operation = vimTypes.ArrayUpdateOperationEdit
+	}
+
+	return &vimTypes.ClusterConfigSpecEx{
+		DasVmConfigSpec: []vimTypes.ClusterDasVmConfigSpec{
+			{
+				ArrayUpdateSpec: vimTypes.ArrayUpdateSpec{Operation: operation},
+				Info: &vimTypes.ClusterDasVmConfigInfo{
+					Key:         vmRef,
+					DasSettings: &settings,
+				},
+			},
+		},
+	}, nil
+}
+
+// validateCPUAffinity returns an error if the CPU affinity set has an index that is not one of
+// the physical CPUs of the VM's host. Otherwise, the host is returned.
+func validateCPUAffinity(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	affinity []int32) (vimTypes.ManagedObjectReference, error) {
+
+	host, err := resVM.VcVM().HostSystem(vmCtx)
+	if err != nil {
+		return vimTypes.ManagedObjectReference{}, err
+	}
+
+	var moHost mo.HostSystem
+	if err := host.Properties(vmCtx, host.Reference(), []string{"summary.hardware"}, &moHost); err != nil {
+		return vimTypes.ManagedObjectReference{}, err
+	}
+
+	if moHost.Summary.Hardware == nil {
+		return vimTypes.ManagedObjectReference{},
+			fmt.Errorf("unable to determine the physical CPU count of host %s", host.Reference().Value)
+	}
+
+	numCPUs := int32(moHost.Summary.Hardware.NumCpuThreads)
+	for _, cpu := range affinity {
+		if cpu < 0 || cpu >= numCPUs {
+			return vimTypes.ManagedObjectReference{},
+				fmt.Errorf("CPU affinity index %d is not within the %d physical CPUs of host %s",
+					cpu, numCPUs, host.Reference().Value)
+		}
+	}
+
+	return host.Reference(), nil
+}
+
+// validateHostLocalSwapDatastore returns an error if the VM's host does not have a swap datastore,
+// or the datastore does not have the free space for the VM's swap file.
+func validateHostLocalSwapDatastore(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	config *vimTypes.VirtualMachineConfigInfo,
+	configSpec *vimTypes.VirtualMachineConfigSpec) error {
+
+	host, err := resVM.VcVM().HostSystem(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	var moHost mo.HostSystem
+	if err := host.Properties(vmCtx, host.Reference(), []string{"config.localSwapDatastore"}, &moHost); err != nil {
+		return err
+	}
+
+	if moHost.Config == nil || moHost.Config.LocalSwapDatastore == nil {
+		return fmt.Errorf("host %s does not have a swap datastore", host.Reference().Value)
+	}
+
+	dsRef := *moHost.Config.LocalSwapDatastore
+	var moDS mo.Datastore
+	if err := object.NewDatastore(host.Client(), dsRef).Properties(vmCtx, dsRef, []string{"summary"}, &moDS); err != nil {
+		return fmt.Errorf("failed to get swap datastore %s of host %s: %w", dsRef.Value, host.Reference().Value, err)
+	}
+
+	if !moDS.Summary.Accessible {
+		return fmt.Errorf("swap datastore %s of host %s is not accessible", dsRef.Value, host.Reference().Value)
+	}
+
+	// The swap file is the size of the VM's memory less its memory reservation.
+	memoryMB := int64(config.Hardware.MemoryMB)
+	if configSpec.MemoryMB != 0 {
+		memoryMB = configSpec.MemoryMB
+	}
+	if config.MemoryAllocation != nil {
+		memoryMB -= pointer.Int64Deref(config.MemoryAllocation.Reservation, 0)
+	}
+
+	if swapSize := memoryMB * 1024 * 1024; moDS.Summary.FreeSpace < swapSize {
+		return fmt.Errorf("swap datastore %s of host %s has %d bytes free but the VM's swap file requires %d bytes",
+			dsRef.Value, host.Reference().Value, moDS.Summary.FreeSpace, swapSize)
+	}
+
+	return nil
+}
+
+// ensureNetworkInterfaces creates or updates the VM's network interface CRs, and returns their
+// results. In a dry run, the results are from the VM's existing network interface CRs.
+func (s *Session) ensureNetworkInterfaces(
+	vmCtx context.VirtualMachineContextA2,
+	configSpec *vimTypes.VirtualMachineConfigSpec) (network2.NetworkInterfaceResults, error) {
+
+	return s.networkInterfaces(vmCtx, configSpec, s.dryRun == nil)
+}
+
+// getNetworkInterfaces returns the same results as ensureNetworkInterfaces from the VM's existing
+// network interface CRs, without creating or updating the CRs.
+func (s *Session) getNetworkInterfaces(
+	vmCtx context.VirtualMachineContextA2,
+	configSpec *vimTypes.VirtualMachineConfigSpec) (network2.NetworkInterfaceResults, error) {
+
+	return s.networkInterfaces(vmCtx, configSpec, false)
+}
+
+func (s *Session) networkInterfaces(
+	vmCtx context.VirtualMachineContextA2,
+	configSpec *vimTypes.VirtualMachineConfigSpec,
+	create bool) (network2.NetworkInterfaceResults, error) {
+
+	networkSpec := vmCtx.VM.Spec.Network
+	if networkSpec == nil || networkSpec.Disabled {
+		return network2.NetworkInterfaceResults{}, nil
+	}
+
+	// This negative device key is the traditional range used for network interfaces.
+	deviceKey := int32(-100)
+
+	var networkDevices []vimTypes.BaseVirtualDevice
+	if lib.IsVMClassAsConfigFSSDaynDateEnabled() && configSpec != nil {
+		networkDevices = util.SelectDevicesByTypes(
+			util.DevicesFromConfigSpec(configSpec),
+			&vimTypes.VirtualE1000{},
+			&vimTypes.VirtualE1000e{},
+			&vimTypes.VirtualPCNet32{},
+			&vimTypes.VirtualVmxnet2{},
+			&vimTypes.VirtualVmxnet3{},
+			&vimTypes.VirtualVmxnet3Vrdma{},
+			&vimTypes.VirtualSriovEthernetCard{},
+		)
+	}
+
+	networkInterfacesFn := network2.GetNetworkInterfaces
+	if create {
+		networkInterfacesFn = network2.CreateAndWaitForNetworkInterfaces
+	}
+
+	clusterMoRef := s.Cluster.Reference()
+	results, err := networkInterfacesFn(
+		vmCtx,
+		s.K8sClient,
+		s.Client.VimClient(),
+		s.Finder,
+		&clusterMoRef,
+		networkSpec.Interfaces)
+	if err != nil {
+		return network2.NetworkInterfaceResults{}, err
+	}
+
+	// XXX: The following logic assumes that the order of network interfaces specified in the
+	// VM spec matches one to one with the device changes in the ConfigSpec in VM class.
+	// This is a safe assumption for now since VM service only supports one network interface.
+	// TODO: Needs update when VM Service supports VMs with more then one network interface.
+	for idx := range results.Results {
+		result := &results.Results[idx]
+
+		dev, err := network2.CreateDefaultEthCard(vmCtx, result, s.Client.Config().DefaultEthernetCardType)
+		if err != nil {
+			return network2.NetworkInterfaceResults{}, err
+		}
+
+		if lib.IsVMClassAsConfigFSSDaynDateEnabled() {
+			// If VM Class-as-a-Config is supported, we use the network device from the Class.
+			// If the VM class doesn't specify enough number of network devices, we fall back to default behavior.
+			if idx < len(networkDevices) {
+				ethCardFromNetProvider := dev.(vimTypes.BaseVirtualEthernetCard)
+
+				if mac := ethCardFromNetProvider.GetVirtualEthernetCard().MacAddress; mac != "" {
+					networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().MacAddress = mac
+					networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().AddressType = string(vimTypes.VirtualEthernetCardMacTypeManual)
+				}
+
+				networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().ExternalId =
+					ethCardFromNetProvider.GetVirtualEthernetCard().ExternalId
+				// If the device from VM class has a DVX backing, this should still work if the backing as well
+				// as the DVX backing are set. VPXD checks for DVX backing before checking for normal device backings.
+				networkDevices[idx].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard().Backing =
+					ethCardFromNetProvider.GetVirtualEthernetCard().Backing
+
+				dev = networkDevices[idx]
+			}
+		}
+
+		// govmomi assigns a random device key. Fix that up here.
+		dev.GetVirtualDevice().Key = deviceKey
+		deviceKey--
+
+		result.Device = dev
+	}
+
+	return results, nil
+}
+
+// deleteStaleNetworkInterfaces deletes the VM's network interfaces that were removed from its
+// spec. The stale interfaces do not prevent the VM from being powered on, and are otherwise
+// deleted when the VM is deleted, so a failure is only logged.
+func (s *Session) deleteStaleNetworkInterfaces(vmCtx context.VirtualMachineContextA2) {
+	networkSpec := vmCtx.VM.Spec.Network
+	if networkSpec == nil || networkSpec.Disabled || s.dryRun != nil {
+		return
+	}
+
+	if err := network2.DeleteStaleNetworkInterfaces(vmCtx, s.K8sClient, networkSpec.Interfaces); err != nil {
+		vmCtx.Logger.Error(err, "Failed to delete stale network interfaces")
+	}
+}
+
+func (s *Session) ensureCNSVolumes(vmCtx context.VirtualMachineContextA2) error {
+	// If VM spec has a PVC, check if the volume is attached before powering on
+	for _, volume := range vmCtx.VM.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			// Only handle PVC volumes here. In v1a1 we had non-PVC ("vsphereVolumes") but those are gone.
+			continue
+		}
+
+		// BMV: We should not use the Status as the SoT here. What a mess.
+		found := false
+		for _, volumeStatus := range vmCtx.VM.Status.Volumes {
+			if volumeStatus.Name == volume.Name {
+				found = true
+				if !volumeStatus.Attached {
+					return fmt.Errorf("persistent volume: %s not attached to VM", volume.Name)
+				}
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("status update pending for persistent volume: %s on VM", volume.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) customize(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	cfg *vimTypes.VirtualMachineConfigInfo,
+	updateArgs *VMUpdateArgs) error {
+
+	// The guest is not customized in a dry run.
+	if s.dryRun != nil {
+		return nil
+	}
+
+	{
+		// Hack: the old code only populated the first nic address - getFirstNicMacAddr() - so just keep
+		// doing the same here. We need a generalized UpdateEthCardDeviceChanges() to match up the Spec
+		// with the actual devices. Old code also made this best effort so do that here too.
+		// I've got a larger change that removes the old session stuff, and improves on all this behavior
+		// but I didn't have the BW to sort out all the changes.
+		if len(updateArgs.NetworkResults.Results) > 0 {
+			mac := updateArgs.NetworkResults.Results[0].MacAddress
+			if mac == "" {
+				ethCards, _ := resVM.GetNetworkDevices(vmCtx)
+				if len(ethCards) > 0 {
+					curNic := ethCards[0].(vimTypes.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+					updateArgs.NetworkResults.Results[0].MacAddress = curNic.GetVirtualEthernetCard().MacAddress
+				}
+			}
+		}
+	}
+
+	err := vmlifecycle.DoBootstrap(vmCtx, resVM.VcVM(), cfg, s.K8sClient, updateArgs.NetworkResults, updateArgs.BootstrapData)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Session) prepareVMForPowerOn(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	cfg *vimTypes.VirtualMachineConfigInfo,
+	updateArgs *VMUpdateArgs) error {
+
+	netIfList, err := s.ensureNetworkInterfaces(vmCtx, updateArgs.ConfigSpec)
+	if err != nil {
+		return err
+	}
+
+	updateArgs.NetworkResults = netIfList
+	s.deleteStaleNetworkInterfaces(vmCtx)
+
+	err = s.prePowerOnVMReconfigure(vmCtx, resVM, cfg, updateArgs)
+	if err != nil {
+		return err
+	}
+
+	err = s.reconfigureFirstClassDisks(vmCtx, resVM, false)
+	if err != nil {
+		return err
+	}
+
+	err = s.customize(vmCtx, resVM, cfg, updateArgs)
+	if err != nil {
+		return err
+	}
+
+	err = s.ensureCNSVolumes(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// upgradeHardwareVersion upgrades the VM to the provider's target hardware version when the VM's
+// hardware version is below it, and the VM's cluster supports upgrading the VM to the target. A
+// powered on VM is never upgraded, and the upgrade instead waits until the VM is next powered
+// off. Failures are reported in the HardwareVersionUpgraded condition rather than failing the
+// update.
+func (s *Session) upgradeHardwareVersion(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	config *vimTypes.VirtualMachineConfigInfo,
+	powerState vmopv1.VirtualMachinePowerState) {
+
+	target := s.Client.Config().HardwareVersionUpgradeTarget
+	if target == 0 {
+		return
+	}
+
+	if util.ParseVirtualHardwareVersion(config.Version) >= target {
+		if conditions.Has(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded) {
+			conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)
+		}
+		return
+	}
+
+	if powerState != vmopv1.VirtualMachinePowerStateOff {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded,
+			vmopv1.VirtualMachineHardwareVersionUpgradePendingPowerOffReason,
+			"The VM will be upgraded to hardware version %d when it is powered off", target)
+		return
+	}
+
+	_, targets, err := virtualmachine.GetHardwareVersionUpgradeTargets(vmCtx, resVM.VcVM())
+	if err != nil {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded,
+			vmopv1.VirtualMachineHardwareVersionUpgradeFailedReason,
+			"Failed to get the hardware versions the VM can be upgraded to: %v", err)
+		return
+	}
+
+	supported := false
+	for _, t := range targets {
+		if t == target {
+			supported = true
+			break
+		}
+	}
+
+	if !supported {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded,
+			vmopv1.VirtualMachineHardwareVersionUpgradeNotSupportedReason,
+			"The VM's cluster does not support upgrading the VM to hardware version %d", target)
+		return
+	}
+
+	if s.dryRun != nil {
+		s.dryRun.hardwareVersion = target
+		return
+	}
+
+	version := fmt.Sprintf("vmx-%02d", target)
+	vmCtx.Logger.Info("Upgrading VM hardware version", "currentVersion", config.Version, "targetVersion", version)
+
+	if err := resVM.UpgradeHardwareVersion(vmCtx, version); err != nil {
+		vmCtx.Logger.Error(err, "Failed to upgrade VM hardware version", "targetVersion", version)
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded,
+			vmopv1.VirtualMachineHardwareVersionUpgradeFailedReason, "%v", err)
+		return
+	}
+
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)
+}
+
+// ReapplyCustomization issues a CustomizeVM_Task with a freshly rendered customization spec,
+// so the guest is customized again when the VM is next powered on. vSphere only customizes
+// powered off VMs, and the guest must have VMware Tools installed to apply the customization.
+func (s *Session) ReapplyCustomization(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	getUpdateArgsFn func() (*VMUpdateArgs, error)) error {
+
+	resVM := res.NewVMFromObject(vcVM)
+
+	moVM, err := resVM.GetProperties(vmCtx, []string{"config", "runtime", "guest"})
+	if err != nil {
+		return err
+	}
+
+	if moVM.Config == nil {
+		return fmt.Errorf("VM config is not available, connectionState=%s", moVM.Runtime.ConnectionState)
+	}
+
+	if moVM.Runtime.PowerState != vimTypes.VirtualMachinePowerStatePoweredOff {
+		return fmt.Errorf("customization can only be re-applied to a powered off VM, powerState=%s", moVM.Runtime.PowerState)
+	}
+
+	if moVM.Guest != nil &&
+		moVM.Guest.ToolsVersionStatus2 == string(vimTypes.VirtualMachineToolsVersionStatusGuestToolsNotInstalled) {
+		return fmt.Errorf("customization cannot be re-applied because VMware Tools is not installed in the guest")
+	}
+
+	updateArgs, err := getUpdateArgsFn()
+	if err != nil {
+		return err
+	}
+
+	netIfList, err := s.ensureNetworkInterfaces(vmCtx, updateArgs.ConfigSpec)
+	if err != nil {
+		return err
+	}
+
+	updateArgs.NetworkResults = netIfList
+	s.deleteStaleNetworkInterfaces(vmCtx)
+
+	// Reconcile the devices first so the customization matches the VM's network interfaces.
+	if err := s.prePowerOnVMReconfigure(vmCtx, resVM, moVM.Config, updateArgs); err != nil {
+		return err
+	}
+
+	return s.customize(vmCtx, resVM, moVM.Config, updateArgs)
+}
+
+// poweredOnVMConfigSpec returns the ConfigSpec with the changes that are applied to a powered on
+// VM, other than the VM Class changes.
+func poweredOnVMConfigSpec(
+	vmCtx context.VirtualMachineContextA2,
+	config *vimTypes.VirtualMachineConfigInfo) *vimTypes.VirtualMachineConfigSpec {
+
+	configSpec := &vimTypes.VirtualMachineConfigSpec{}
+	UpdateConfigSpecChangeBlockTracking(config, configSpec, nil, vmCtx.VM.Spec)
+	UpdateConfigSpecToolsUpgradePolicy(config, configSpec, vmCtx.VM.Spec)
+	return configSpec
+}
+
+func (s *Session) poweredOnVMReconfigure(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	config *vimTypes.VirtualMachineConfigInfo) error {
+
+	configSpec := poweredOnVMConfigSpec(vmCtx, config)
 
 	defaultConfigSpec := &vimTypes.VirtualMachineConfigSpec{}
 	if !apiEquality.Semantic.DeepEqual(configSpec, defaultConfigSpec) {
 		vmCtx.Logger.Info("PoweredOn Reconfigure", "configSpec", configSpec)
-		if err := resVM.Reconfigure(vmCtx, configSpec); err != nil {
+		if err := s.reconfigureVM(vmCtx, resVM, configSpec); err != nil {
 			vmCtx.Logger.Error(err, "powered on reconfigure failed")
 			return err
 		}
@@ -729,7 +1654,7 @@ func (s *Session) poweredOnVMReconfigure(
 		// Special case for CBT: in order for CBT change take effect for a powered on VM,
 		// a checkpoint save/restore is needed.  tracks the implementation of
 		// this FSR internally to vSphere.
-		if configSpec.ChangeTrackingEnabled != nil {
+		if configSpec.ChangeTrackingEnabled != nil && s.dryRun == nil {
 			if err := s.invokeFsrVirtualMachine(vmCtx, resVM); err != nil {
 				vmCtx.Logger.Error(err, "Failed to invoke FSR for CBT update")
 				return err
@@ -740,6 +1665,227 @@ func (s *Session) poweredOnVMReconfigure(
 	return nil
 }
 
+// hotReconfigureHardwareConfigSpec returns the ConfigSpec with the CPU and memory changes that
+// can be applied to the powered on VM, and the names of the changes that must be deferred until
+// the VM is power cycled. A change can only be hot applied when it is an increase, hot add is
+// enabled on the VM, and the VM's guest OS supports hot add.
+func hotReconfigureHardwareConfigSpec(
+	config *vimTypes.VirtualMachineConfigInfo,
+	configSpec *vimTypes.VirtualMachineConfigSpec,
+	guestOS *vimTypes.GuestOsDescriptor) (*vimTypes.VirtualMachineConfigSpec, []string) {
+
+	var guestCPUHotAdd, guestMemoryHotAdd bool
+	if guestOS != nil {
+		guestCPUHotAdd = pointer.BoolDeref(guestOS.SupportsCpuHotAdd, false)
+		guestMemoryHotAdd = pointer.BoolDeref(guestOS.SupportsMemoryHotAdd, false)
+	}
+
+	hotConfigSpec := &vimTypes.VirtualMachineConfigSpec{}
+	var deferred []string
+
+	if configSpec.NumCPUs != 0 {
+		if configSpec.NumCPUs > config.Hardware.NumCPU && guestCPUHotAdd && pointer.BoolDeref(config.CpuHotAddEnabled, false) {
+			hotConfigSpec.NumCPUs = configSpec.NumCPUs
+		} else {
+			deferred = append(deferred, "CPU")
+		}
+	}
+
+	// Memory is never removed from a powered on VM.
+	if configSpec.MemoryMB != 0 {
+		if configSpec.MemoryMB > int64(config.Hardware.MemoryMB) && guestMemoryHotAdd && pointer.BoolDeref(config.MemoryHotAddEnabled, false) {
+			hotConfigSpec.MemoryMB = configSpec.MemoryMB
+		} else {
+			deferred = append(deferred, "memory")
+		}
+	}
+
+	return hotConfigSpec, deferred
+}
+
+// poweredOnVMClassConfigSpec returns the ConfigSpec with the VM Class CPU and memory changes
+// that can be hot applied to the powered on VM, and the names of the changes that are deferred
+// until the VM is power cycled.
+func (s *Session) poweredOnVMClassConfigSpec(
+	vmCtx context.VirtualMachineContextA2,
+	config *vimTypes.VirtualMachineConfigInfo,
+	updateArgs *VMUpdateArgs) (*vimTypes.VirtualMachineConfigSpec, []string, error) {
+
+	configSpec := &vimTypes.VirtualMachineConfigSpec{}
+	UpdateHardwareConfigSpec(config, configSpec, &updateArgs.VMClass.Spec)
+
+	defaultConfigSpec := &vimTypes.VirtualMachineConfigSpec{}
+	if apiEquality.Semantic.DeepEqual(configSpec, defaultConfigSpec) {
+		return configSpec, nil, nil
+	}
+
+	var guestOS *vimTypes.GuestOsDescriptor
+	if s.Cluster != nil && (pointer.BoolDeref(config.CpuHotAddEnabled, false) || pointer.BoolDeref(config.MemoryHotAddEnabled, false)) {
+		var err error
+		guestOS, err = virtualmachine.GetGuestOSDescriptor(vmCtx, s.Cluster, config)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	hotConfigSpec, deferred := hotReconfigureHardwareConfigSpec(config, configSpec, guestOS)
+	return hotConfigSpec, deferred, nil
+}
+
+// poweredOnVMClassReconfigure applies the VM Class CPU and memory changes to a powered on VM.
+// The changes that can be hot applied are, and the rest are deferred until the next time the
+// VM is powered on.
+func (s *Session) poweredOnVMClassReconfigure(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	config *vimTypes.VirtualMachineConfigInfo,
+	updateArgs *VMUpdateArgs) error {
+
+	hotConfigSpec, deferred, err := s.poweredOnVMClassConfigSpec(vmCtx, config, updateArgs)
+	if err != nil {
+		return err
+	}
+
+	defaultConfigSpec := &vimTypes.VirtualMachineConfigSpec{}
+	if !apiEquality.Semantic.DeepEqual(hotConfigSpec, defaultConfigSpec) {
+		vmCtx.Logger.Info("PoweredOn VM Class Reconfigure", "configSpec", hotConfigSpec)
+		if err := s.reconfigureVM(vmCtx, resVM, hotConfigSpec); err != nil {
+			vmCtx.Logger.Error(err, "powered on VM Class reconfigure failed")
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionClassConfigurationSynced,
+				vmopv1.VirtualMachineClassConfigurationReconfigureFailedReason, err.Error())
+			return err
+		}
+	}
+
+	if len(deferred) > 0 {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionClassConfigurationSynced,
+			vmopv1.VirtualMachineClassConfigurationPendingPowerCycleReason,
+			"VM Class %s changes will be applied when the VM is power cycled", strings.Join(deferred, " and "))
+		return nil
+	}
+
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionClassConfigurationSynced)
+	vmCtx.VM.Status.ObservedClassGeneration = updateArgs.VMClass.Generation
+	return nil
+}
+
+// reconcileDisplayName renames the vSphere VM when its name in the vCenter inventory differs from
+// the VM's display name annotation. The VM is not renamed if another object in its folder already
+// has the display name. A failure to rename the VM is reported in the DisplayNameSynced condition
+// rather than failing the update.
+func (s *Session) reconcileDisplayName(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	moVM *mo.VirtualMachine) {
+
+	displayName := vmCtx.VM.Annotations[constants.DisplayNameAnnotation]
+	if displayName == "" {
+		conditions.Delete(vmCtx.VM, vmopv1.VirtualMachineConditionDisplayNameSynced)
+		return
+	}
+
+	if moVM.Name == displayName {
+		conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionDisplayNameSynced)
+		return
+	}
+
+	if moVM.Parent != nil {
+		ref, err := object.NewSearchIndex(s.Client.VimClient()).FindChild(vmCtx, *moVM.Parent, displayName)
+		if err != nil {
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionDisplayNameSynced,
+				vmopv1.VirtualMachineDisplayNameRenameFailedReason,
+				"failed to check the VM's folder for the display name: %v", err)
+			return
+		}
+
+		if ref != nil {
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionDisplayNameSynced,
+				vmopv1.VirtualMachineDisplayNameConflictReason,
+				"%s %s in the VM's folder is already named %q", ref.Reference().Type, ref.Reference().Value, displayName)
+			return
+		}
+	}
+
+	if s.dryRun != nil {
+		s.dryRun.displayName = displayName
+		return
+	}
+
+	if err := resVM.Rename(vmCtx, displayName); err != nil {
+		vmCtx.Logger.Error(err, "Failed to rename VM to its display name", "displayName", displayName)
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionDisplayNameSynced,
+			vmopv1.VirtualMachineDisplayNameRenameFailedReason, "%v", err)
+		return
+	}
+
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionDisplayNameSynced)
+}
+
+// reconcileBootDiskStoragePolicy places the VM's boot disk on the storage policy of its
+// BootDiskStorageClass when the VM's ExtraConfig does not record that the boot disk was placed on
+// the policy, ex. because the relocate after the VM was deployed failed.
+func (s *Session) reconcileBootDiskStoragePolicy(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	moVM *mo.VirtualMachine) error {
+
+	advanced := vmCtx.VM.Spec.Advanced
+	if advanced == nil || advanced.BootDiskStorageClass == "" {
+		return nil
+	}
+
+	storageProfileID, err := storage.GetStoragePolicyID(vmCtx, s.K8sClient, advanced.BootDiskStorageClass)
+	if err != nil {
+		return err
+	}
+
+	if moVM.Config != nil {
+		ecMap := util.ExtraConfigToMap(moVM.Config.ExtraConfig)
+		if ecMap[constants.BootDiskStorageProfileIDExtraConfigKey] == storageProfileID {
+			return nil
+		}
+	}
+
+	if s.dryRun != nil {
+		s.dryRun.bootDiskStorageProfileID = storageProfileID
+		return nil
+	}
+
+	vmCtx.Logger.Info("Placing boot disk on its storage policy", "storageProfileID", storageProfileID)
+	if err := vmlifecycle.RelocateBootDisk(vmCtx, resVM.VcVM(), storageProfileID); err != nil {
+		return fmt.Errorf("failed to place boot disk on its storage policy: %w", err)
+	}
+
+	return nil
+}
+
+// reconcileLabelTags syncs the VM's labels with a configured tag category to the vSphere VM as
+// tags. A failure is reported in the TagsSynced condition rather than failing the update.
+func (s *Session) reconcileLabelTags(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine) {
+
+	if len(lib.GetLabelTagCategories()) == 0 {
+		conditions.Delete(vmCtx.VM, vmopv1.VirtualMachineConditionTagsSynced)
+		return
+	}
+
+	var err error
+	if s.dryRun != nil {
+		s.dryRun.tagChanges, err = virtualmachine.PlanLabelTags(vmCtx, s.Client.RestClient(), s.TagCache, resVM.MoRef())
+	} else {
+		err = virtualmachine.SyncLabelTags(vmCtx, s.Client.RestClient(), s.TagCache, resVM.MoRef())
+	}
+	if err != nil {
+		vmCtx.Logger.Error(err, "Failed to sync the VM's labels to vSphere tags")
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionTagsSynced,
+			vmopv1.VirtualMachineTagsSyncFailedReason, "%v", err)
+		return
+	}
+
+	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionTagsSynced)
+}
+
 func (s *Session) attachClusterModule(
 	vmCtx context.VirtualMachineContextA2,
 	resVM *res.VirtualMachine,
@@ -757,9 +1903,34 @@ func (s *Session) attachClusterModule(
 		return fmt.Errorf("ClusterModule %s not found", clusterModuleName)
 	}
 
+	if s.dryRun != nil {
+		return nil
+	}
+
 	return s.Client.ClusterModuleClient().AddMoRefToModule(vmCtx, moduleUUID, resVM.MoRef())
 }
 
+// setPowerState changes the power state of the VM. In a dry run, the change is only recorded in
+// the plan.
+func (s *Session) setPowerState(
+	vmCtx context.VirtualMachineContextA2,
+	resVM *res.VirtualMachine,
+	currentPowerState,
+	desiredPowerState vmopv1.VirtualMachinePowerState,
+	desiredPowerOpMode vmopv1.VirtualMachinePowerOpMode) error {
+
+	if s.dryRun != nil {
+		s.dryRun.powerState = desiredPowerState
+		return nil
+	}
+
+	return resVM.SetPowerState(
+		logr.NewContext(vmCtx, vmCtx.Logger),
+		currentPowerState,
+		desiredPowerState,
+		desiredPowerOpMode)
+}
+
 func (s *Session) UpdateVirtualMachine(
 	vmCtx context.VirtualMachineContextA2,
 	vcVM *object.VirtualMachine,
@@ -767,11 +1938,14 @@ func (s *Session) UpdateVirtualMachine(
 
 	resVM := res.NewVMFromObject(vcVM)
 
-	moVM, err := resVM.GetProperties(vmCtx, []string{"config", "runtime"})
+	moVM, err := resVM.GetProperties(vmCtx, []string{"config", "name", "parent", "runtime"})
 	if err != nil {
 		return err
 	}
 
+	prevToolsCondition := conditions.Get(vmCtx.VM, vmopv1.VirtualMachineToolsCondition).DeepCopy()
+	prevCustomizationCondition := conditions.Get(vmCtx.VM, vmopv1.GuestCustomizationCondition).DeepCopy()
+
 	defer func() {
 		updateErr := vmlifecycle.UpdateStatus(vmCtx, s.K8sClient, vcVM, nil)
 		if updateErr != nil {
@@ -779,9 +1953,46 @@ func (s *Session) UpdateVirtualMachine(
 			if err == nil {
 				err = updateErr
 			}
+			return
+		}
+
+		if tagStatus, tagErr := virtualmachine.GetCachedTagStatus(vmCtx, s.Client.RestClient(), s.TagCache, vcVM.Reference()); tagErr != nil {
+			// Leave the current Tags unchanged since the tagging service may just be unavailable.
+			vmCtx.Logger.Error(tagErr, "Updating VM tags status failed")
+		} else {
+			vmCtx.VM.Status.Tags = tagStatus
 		}
+
+		s.recordGuestEvents(vmCtx, prevToolsCondition, prevCustomizationCondition)
 	}()
 
+	if _, ok := vmCtx.VM.Annotations[constants.DryRunReconfigureAnnotation]; ok || s.ReadOnly {
+		return s.dryRunUpdate(vmCtx, vcVM, resVM, moVM, getUpdateArgsFn)
+	}
+	vmCtx.VM.Status.ReconfigurePlan = nil
+
+	return s.updateVirtualMachine(vmCtx, vcVM, resVM, moVM, getUpdateArgsFn)
+}
+
+// updateVirtualMachine makes the changes to the VM, its cluster, and its tags so they match the
+// VM's spec. It is the update for both the actual update and its dry run.
+func (s *Session) updateVirtualMachine(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	resVM *res.VirtualMachine,
+	moVM *mo.VirtualMachine,
+	getUpdateArgsFn func() (*VMUpdateArgs, error)) error {
+
+	s.reconfigureHAOverride(vmCtx, resVM)
+
+	s.reconcileLabelTags(vmCtx, resVM)
+
+	s.reconcileDisplayName(vmCtx, resVM, moVM)
+
+	if err := s.reconcileBootDiskStoragePolicy(vmCtx, resVM, moVM); err != nil {
+		return err
+	}
+
 	// Translate the VM's current power state into the VM Op power state value.
 	var existingPowerState vmopv1.VirtualMachinePowerState
 	switch moVM.Runtime.PowerState {
@@ -804,8 +2015,9 @@ func (s *Session) UpdateVirtualMachine(
 			}
 		}
 		if powerOff {
-			return resVM.SetPowerState(
-				logr.NewContext(vmCtx, vmCtx.Logger),
+			return s.setPowerState(
+				vmCtx,
+				resVM,
 				existingPowerState,
 				vmCtx.VM.Spec.PowerState,
 				vmCtx.VM.Spec.PowerOffMode)
@@ -815,10 +2027,36 @@ func (s *Session) UpdateVirtualMachine(
 		// we'll defer that until the pre power on (and until more people complain
 		// that the UI appears wrong).
 
+		if existingPowerState == vmopv1.VirtualMachinePowerStateOff {
+			if moVM.Config != nil {
+				s.upgradeHardwareVersion(vmCtx, resVM, moVM.Config, existingPowerState)
+			}
+
+			if err := s.reconfigureFirstClassDisks(vmCtx, resVM, false); err != nil {
+				return err
+			}
+
+			if err := s.reconfigureSerialPorts(vmCtx, resVM, false); err != nil {
+				return err
+			}
+
+			if err := s.reconfigureBootOrder(vmCtx, resVM, false); err != nil {
+				return err
+			}
+
+			if _, ok := vmCtx.VM.Annotations[constants.ReapplyCustomizationAnnotation]; ok {
+				if err := s.ReapplyCustomization(vmCtx, vcVM, getUpdateArgsFn); err != nil {
+					return err
+				}
+				delete(vmCtx.VM.Annotations, constants.ReapplyCustomizationAnnotation)
+			}
+		}
+
 	case vmopv1.VirtualMachinePowerStateSuspended:
 		if existingPowerState == vmopv1.VirtualMachinePowerStateOn {
-			return resVM.SetPowerState(
-				logr.NewContext(vmCtx, vmCtx.Logger),
+			return s.setPowerState(
+				vmCtx,
+				resVM,
 				existingPowerState,
 				vmCtx.VM.Spec.PowerState,
 				vmCtx.VM.Spec.SuspendMode)
@@ -837,7 +2075,7 @@ func (s *Session) UpdateVirtualMachine(
 
 			// Check to see if a possible restart is required.
 			// Please note a VM may only be restarted if it is powered on.
-			if vmCtx.VM.Spec.NextRestartTime != "" {
+			if vmCtx.VM.Spec.NextRestartTime != "" && s.dryRun == nil {
 
 				// If non-empty, the value of spec.nextRestartTime is guaranteed
 				// to be a valid RFC3339Nano timestamp due to the webhooks,
@@ -868,6 +2106,33 @@ func (s *Session) UpdateVirtualMachine(
 				}
 			}
 
+			// With VM Class as Config DaynDate, changes to the VM Class are
+			// applied to the powered on VM.
+			if lib.IsVMClassAsConfigFSSDaynDateEnabled() {
+				updateArgs, err := getUpdateArgsFn()
+				if err != nil {
+					return err
+				}
+
+				if err := s.poweredOnVMClassReconfigure(vmCtx, resVM, config, updateArgs); err != nil {
+					return err
+				}
+			}
+
+			s.upgradeHardwareVersion(vmCtx, resVM, config, existingPowerState)
+
+			if err := s.reconfigureFirstClassDisks(vmCtx, resVM, true); err != nil {
+				return err
+			}
+
+			if err := s.reconfigureSerialPorts(vmCtx, resVM, true); err != nil {
+				return err
+			}
+
+			if err := s.reconfigureBootOrder(vmCtx, resVM, true); err != nil {
+				return err
+			}
+
 			// Do not pass classConfigSpec to poweredOnVMReconfigure when VM is
 			// already powered on since we do not have to get VM class at this
 			// point.
@@ -875,8 +2140,9 @@ func (s *Session) UpdateVirtualMachine(
 
 		case vmopv1.VirtualMachinePowerStateSuspended:
 			// A suspended VM cannot be reconfigured.
-			return resVM.SetPowerState(
-				logr.NewContext(vmCtx, vmCtx.Logger),
+			return s.setPowerState(
+				vmCtx,
+				resVM,
 				existingPowerState,
 				vmCtx.VM.Spec.PowerState,
 				vmopv1.VirtualMachinePowerOpModeHard)
@@ -892,15 +2158,13 @@ func (s *Session) UpdateVirtualMachine(
 			return err
 		}
 
+		s.upgradeHardwareVersion(vmCtx, resVM, config, existingPowerState)
+
 		if err := s.prepareVMForPowerOn(vmCtx, resVM, config, updateArgs); err != nil {
 			return err
 		}
 
-		if err := resVM.SetPowerState(
-			logr.NewContext(vmCtx, vmCtx.Logger),
-			existingPowerState,
-			vmCtx.VM.Spec.PowerState,
-			vmopv1.VirtualMachinePowerOpModeHard); err != nil {
+		if err := s.powerOnVM(vmCtx, resVM, updateArgs.PowerOnHost); err != nil {
 			return err
 		}
 
@@ -908,6 +2172,8 @@ func (s *Session) UpdateVirtualMachine(
 			vmCtx.VM.Annotations = map[string]string{}
 		}
 		vmCtx.VM.Annotations[vmopv1.FirstBootDoneAnnotation] = "true"
+		// The customization was just re-rendered and applied by prepareVMForPowerOn.
+		delete(vmCtx.VM.Annotations, constants.ReapplyCustomizationAnnotation)
 	}
 	return nil
 }
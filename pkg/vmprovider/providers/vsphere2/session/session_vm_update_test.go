@@ -632,6 +632,300 @@ var _ = Describe("Update ConfigSpec", func() {
 		})
 	})
 
+	Context("Swap Placement", func() {
+		var vmSpec vmopv1.VirtualMachineSpec
+
+		BeforeEach(func() {
+			vmSpec = vmopv1.VirtualMachineSpec{}
+		})
+
+		It("swap placement unset", func() {
+			config.SwapPlacement = string(vimTypes.VirtualMachineConfigInfoSwapPlacementTypeVmDirectory)
+
+			session.UpdateConfigSpecSwapPlacement(config, configSpec, vmSpec)
+			Expect(configSpec.SwapPlacement).To(BeEmpty())
+		})
+
+		It("sets the swap placement", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SwapPlacement: vmopv1.VirtualMachineSwapPlacementHostLocal,
+			}
+
+			session.UpdateConfigSpecSwapPlacement(config, configSpec, vmSpec)
+			Expect(configSpec.SwapPlacement).To(Equal(string(vimTypes.VirtualMachineConfigInfoSwapPlacementTypeHostLocal)))
+		})
+
+		It("swap placement matches config swap placement", func() {
+			config.SwapPlacement = string(vimTypes.VirtualMachineConfigInfoSwapPlacementTypeInherit)
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SwapPlacement: vmopv1.VirtualMachineSwapPlacementInherit,
+			}
+
+			session.UpdateConfigSpecSwapPlacement(config, configSpec, vmSpec)
+			Expect(configSpec.SwapPlacement).To(BeEmpty())
+		})
+	})
+
+	Context("Tools Upgrade Policy", func() {
+		var vmSpec vmopv1.VirtualMachineSpec
+
+		BeforeEach(func() {
+			vmSpec = vmopv1.VirtualMachineSpec{}
+		})
+
+		It("upgrade policy unset", func() {
+			config.Tools = &vimTypes.ToolsConfigInfo{
+				ToolsUpgradePolicy: string(vimTypes.UpgradePolicyUpgradeAtPowerCycle),
+			}
+
+			session.UpdateConfigSpecToolsUpgradePolicy(config, configSpec, vmSpec)
+			Expect(configSpec.Tools).To(BeNil())
+		})
+
+		It("sets the upgrade policy", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				ToolsUpgradePolicy: vmopv1.VirtualMachineToolsUpgradePolicyUpgradeAtPowerCycle,
+			}
+
+			session.UpdateConfigSpecToolsUpgradePolicy(config, configSpec, vmSpec)
+			Expect(configSpec.Tools).ToNot(BeNil())
+			Expect(configSpec.Tools.ToolsUpgradePolicy).To(Equal(string(vimTypes.UpgradePolicyUpgradeAtPowerCycle)))
+		})
+
+		It("upgrade policy matches config upgrade policy", func() {
+			config.Tools = &vimTypes.ToolsConfigInfo{
+				ToolsUpgradePolicy: string(vimTypes.UpgradePolicyManual),
+			}
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				ToolsUpgradePolicy: vmopv1.VirtualMachineToolsUpgradePolicyManual,
+			}
+
+			session.UpdateConfigSpecToolsUpgradePolicy(config, configSpec, vmSpec)
+			Expect(configSpec.Tools).To(BeNil())
+		})
+	})
+
+	Context("CPU Affinity", func() {
+		var vmSpec vmopv1.VirtualMachineSpec
+
+		BeforeEach(func() {
+			vmSpec = vmopv1.VirtualMachineSpec{}
+		})
+
+		It("affinity and config affinity unset", func() {
+			session.UpdateConfigSpecCPUAffinity(config, configSpec, vmSpec)
+			Expect(configSpec.CpuAffinity).To(BeNil())
+		})
+
+		It("sets the affinity", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				CPUAffinity: []int32{0, 1},
+			}
+
+			session.UpdateConfigSpecCPUAffinity(config, configSpec, vmSpec)
+			Expect(configSpec.CpuAffinity).ToNot(BeNil())
+			Expect(configSpec.CpuAffinity.AffinitySet).To(Equal([]int32{0, 1}))
+		})
+
+		It("affinity matches config affinity in a different order", func() {
+			config.CpuAffinity = &vimTypes.VirtualMachineAffinityInfo{AffinitySet: []int32{1, 0}}
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				CPUAffinity: []int32{0, 1},
+			}
+
+			session.UpdateConfigSpecCPUAffinity(config, configSpec, vmSpec)
+			Expect(configSpec.CpuAffinity).To(BeNil())
+		})
+
+		It("clears the config affinity", func() {
+			config.CpuAffinity = &vimTypes.VirtualMachineAffinityInfo{AffinitySet: []int32{1}}
+
+			session.UpdateConfigSpecCPUAffinity(config, configSpec, vmSpec)
+			Expect(configSpec.CpuAffinity).ToNot(BeNil())
+			Expect(configSpec.CpuAffinity.AffinitySet).To(BeEmpty())
+		})
+	})
+
+	Context("Boot Order", func() {
+		var vmSpec vmopv1.VirtualMachineSpec
+
+		BeforeEach(func() {
+			vmSpec = vmopv1.VirtualMachineSpec{}
+			config.Hardware.Device = []vimTypes.BaseVirtualDevice{
+				&vimTypes.VirtualDisk{VirtualDevice: vimTypes.VirtualDevice{Key: 2000}},
+				&vimTypes.VirtualVmxnet3{VirtualVmxnet: vimTypes.VirtualVmxnet{VirtualEthernetCard: vimTypes.VirtualEthernetCard{
+					VirtualDevice: vimTypes.VirtualDevice{Key: 4000},
+				}}},
+				&vimTypes.VirtualDisk{VirtualDevice: vimTypes.VirtualDevice{Key: 2001}},
+			}
+		})
+
+		It("boot order unset", func() {
+			missing := session.UpdateConfigSpecBootOrder(config, configSpec, vmSpec)
+			Expect(missing).To(BeEmpty())
+			Expect(configSpec.BootOptions).To(BeNil())
+		})
+
+		It("sets the boot order to the devices of each type", func() {
+			config.BootOptions = &vimTypes.VirtualMachineBootOptions{BootDelay: 10}
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				BootOrder: []vmopv1.VirtualMachineBootDeviceType{
+					vmopv1.VirtualMachineBootDeviceTypeNetwork,
+					vmopv1.VirtualMachineBootDeviceTypeDisk,
+				},
+			}
+
+			missing := session.UpdateConfigSpecBootOrder(config, configSpec, vmSpec)
+			Expect(missing).To(BeEmpty())
+			Expect(configSpec.BootOptions).ToNot(BeNil())
+			Expect(configSpec.BootOptions.BootDelay).To(BeEquivalentTo(10))
+			Expect(configSpec.BootOptions.BootOrder).To(Equal([]vimTypes.BaseVirtualMachineBootOptionsBootableDevice{
+				&vimTypes.VirtualMachineBootOptionsBootableEthernetDevice{DeviceKey: 4000},
+				&vimTypes.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: 2000},
+				&vimTypes.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: 2001},
+			}))
+		})
+
+		It("boot order matches config boot order", func() {
+			config.BootOptions = &vimTypes.VirtualMachineBootOptions{
+				BootOrder: []vimTypes.BaseVirtualMachineBootOptionsBootableDevice{
+					&vimTypes.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: 2000},
+					&vimTypes.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: 2001},
+				},
+			}
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				BootOrder: []vmopv1.VirtualMachineBootDeviceType{vmopv1.VirtualMachineBootDeviceTypeDisk},
+			}
+
+			missing := session.UpdateConfigSpecBootOrder(config, configSpec, vmSpec)
+			Expect(missing).To(BeEmpty())
+			Expect(configSpec.BootOptions).To(BeNil())
+		})
+
+		It("leaves out the device types without a device", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				BootOrder: []vmopv1.VirtualMachineBootDeviceType{
+					vmopv1.VirtualMachineBootDeviceTypeCDROM,
+					vmopv1.VirtualMachineBootDeviceTypeNetwork,
+				},
+			}
+
+			missing := session.UpdateConfigSpecBootOrder(config, configSpec, vmSpec)
+			Expect(missing).To(Equal([]string{"CDROM"}))
+			Expect(configSpec.BootOptions).ToNot(BeNil())
+			Expect(configSpec.BootOptions.BootOrder).To(Equal([]vimTypes.BaseVirtualMachineBootOptionsBootableDevice{
+				&vimTypes.VirtualMachineBootOptionsBootableEthernetDevice{DeviceKey: 4000},
+			}))
+		})
+	})
+
+	Context("Serial Port Changes", func() {
+		var vmSpec vmopv1.VirtualMachineSpec
+		var currentList object.VirtualDeviceList
+
+		uriSerialPort := func(key int32, uri, direction string) *vimTypes.VirtualSerialPort {
+			return &vimTypes.VirtualSerialPort{
+				VirtualDevice: vimTypes.VirtualDevice{
+					Key: key,
+					Backing: &vimTypes.VirtualSerialPortURIBackingInfo{
+						VirtualDeviceURIBackingInfo: vimTypes.VirtualDeviceURIBackingInfo{
+							ServiceURI: uri,
+							Direction:  direction,
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			vmSpec = vmopv1.VirtualMachineSpec{}
+			currentList = object.VirtualDeviceList{
+				&vimTypes.VirtualSerialPort{
+					VirtualDevice: vimTypes.VirtualDevice{
+						Key:     9000,
+						Backing: &vimTypes.VirtualSerialPortFileBackingInfo{},
+					},
+				},
+			}
+		})
+
+		It("serial ports unset", func() {
+			Expect(session.UpdateSerialPortDeviceChanges(vmSpec, currentList)).To(BeEmpty())
+		})
+
+		It("adds the missing serial ports", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SerialPorts: []vmopv1.VirtualMachineSerialPortSpec{
+					{
+						Name: "console",
+						URI:  "telnet://:13370",
+					},
+				},
+			}
+
+			deviceChanges := session.UpdateSerialPortDeviceChanges(vmSpec, currentList)
+			Expect(deviceChanges).To(HaveLen(1))
+
+			dc := deviceChanges[0].GetVirtualDeviceConfigSpec()
+			Expect(dc.Operation).To(Equal(vimTypes.VirtualDeviceConfigSpecOperationAdd))
+			serialPort, ok := dc.Device.(*vimTypes.VirtualSerialPort)
+			Expect(ok).To(BeTrue())
+			Expect(serialPort.Key).To(BeNumerically("<", 0))
+			backing, ok := serialPort.Backing.(*vimTypes.VirtualSerialPortURIBackingInfo)
+			Expect(ok).To(BeTrue())
+			Expect(backing.ServiceURI).To(Equal("telnet://:13370"))
+			Expect(backing.Direction).To(Equal("client"))
+		})
+
+		It("serial ports match", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SerialPorts: []vmopv1.VirtualMachineSerialPortSpec{
+					{
+						Name:      "console",
+						URI:       "telnet://:13370",
+						Direction: vmopv1.VirtualMachineSerialPortDirectionServer,
+					},
+				},
+			}
+			currentList = append(currentList, uriSerialPort(9001, "telnet://:13370", "server"))
+
+			Expect(session.UpdateSerialPortDeviceChanges(vmSpec, currentList)).To(BeEmpty())
+		})
+
+		It("replaces the serial port when its backing changes", func() {
+			vmSpec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SerialPorts: []vmopv1.VirtualMachineSerialPortSpec{
+					{
+						Name: "console",
+						URI:  "tcp://console.example.com:13370",
+					},
+				},
+			}
+			currentList = append(currentList, uriSerialPort(9001, "telnet://:13370", "client"))
+
+			deviceChanges := session.UpdateSerialPortDeviceChanges(vmSpec, currentList)
+			Expect(deviceChanges).To(HaveLen(2))
+
+			dc := deviceChanges[0].GetVirtualDeviceConfigSpec()
+			Expect(dc.Operation).To(Equal(vimTypes.VirtualDeviceConfigSpecOperationRemove))
+			Expect(dc.Device.GetVirtualDevice().Key).To(BeEquivalentTo(9001))
+
+			dc = deviceChanges[1].GetVirtualDeviceConfigSpec()
+			Expect(dc.Operation).To(Equal(vimTypes.VirtualDeviceConfigSpecOperationAdd))
+		})
+
+		It("removes the network serial ports that are not in the spec", func() {
+			currentList = append(currentList, uriSerialPort(9001, "telnet://:13370", "client"))
+
+			deviceChanges := session.UpdateSerialPortDeviceChanges(vmSpec, currentList)
+			Expect(deviceChanges).To(HaveLen(1))
+
+			dc := deviceChanges[0].GetVirtualDeviceConfigSpec()
+			Expect(dc.Operation).To(Equal(vimTypes.VirtualDeviceConfigSpecOperationRemove))
+			Expect(dc.Device.GetVirtualDevice().Key).To(BeEquivalentTo(9001))
+		})
+	})
+
 	Context("Firmware", func() {
 		var vm *vmopv1.VirtualMachine
 
@@ -1202,3 +1496,96 @@ var _ = Describe("Update ConfigSpec", func() {
 		})
 	})
 })
+
+var _ = Describe("ReconfigurePlan", func() {
+
+	var (
+		configSpecs []*vimTypes.VirtualMachineConfigSpec
+		plan        *vmopv1.VirtualMachineReconfigurePlan
+		err         error
+	)
+
+	BeforeEach(func() {
+		configSpecs = nil
+	})
+
+	JustBeforeEach(func() {
+		plan, err = session.ReconfigurePlan(configSpecs)
+	})
+
+	Context("No ConfigSpecs", func() {
+		It("returns an empty plan", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan).To(Equal(&vmopv1.VirtualMachineReconfigurePlan{}))
+		})
+	})
+
+	Context("Empty ConfigSpec", func() {
+		BeforeEach(func() {
+			configSpecs = append(configSpecs, &vimTypes.VirtualMachineConfigSpec{})
+		})
+
+		It("omits the ConfigSpec", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.ConfigSpecs).To(BeEmpty())
+		})
+	})
+
+	Context("ConfigSpecs with config and device changes", func() {
+		BeforeEach(func() {
+			configSpecs = append(configSpecs,
+				&vimTypes.VirtualMachineConfigSpec{
+					NumCPUs:  4,
+					MemoryMB: 4096,
+					ExtraConfig: []vimTypes.BaseOptionValue{
+						&vimTypes.OptionValue{Key: "guestinfo.foo", Value: "bar"},
+					},
+					DeviceChange: []vimTypes.BaseVirtualDeviceConfigSpec{
+						&vimTypes.VirtualDeviceConfigSpec{
+							Operation: vimTypes.VirtualDeviceConfigSpecOperationAdd,
+							Device: &vimTypes.VirtualVmxnet3{
+								VirtualVmxnet: vimTypes.VirtualVmxnet{
+									VirtualEthernetCard: vimTypes.VirtualEthernetCard{
+										VirtualDevice: vimTypes.VirtualDevice{Key: -100},
+									},
+								},
+							},
+						},
+						&vimTypes.VirtualDeviceConfigSpec{
+							Operation: vimTypes.VirtualDeviceConfigSpecOperationRemove,
+							Device: &vimTypes.VirtualE1000{
+								VirtualEthernetCard: vimTypes.VirtualEthernetCard{
+									VirtualDevice: vimTypes.VirtualDevice{
+										Key:        4000,
+										DeviceInfo: &vimTypes.Description{Label: "Network adapter 1"},
+									},
+								},
+							},
+						},
+					},
+				},
+				&vimTypes.VirtualMachineConfigSpec{
+					ChangeTrackingEnabled: pointer.Bool(true),
+				},
+			)
+		})
+
+		It("returns the plan of the changes", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.AddedDevices).To(Equal([]vmopv1.VirtualMachineReconfigurePlanDevice{
+				{Key: -100, Type: "VirtualVmxnet3"},
+			}))
+			Expect(plan.RemovedDevices).To(Equal([]vmopv1.VirtualMachineReconfigurePlanDevice{
+				{Key: 4000, Type: "VirtualE1000", Label: "Network adapter 1"},
+			}))
+			Expect(plan.EditedDevices).To(BeEmpty())
+			Expect(plan.ConfigKeys).To(Equal([]string{"changeTrackingEnabled", "memoryMB", "numCPUs"}))
+			Expect(plan.ExtraConfigKeys).To(Equal([]string{"guestinfo.foo"}))
+
+			Expect(plan.ConfigSpecs).To(HaveLen(2))
+			Expect(plan.ConfigSpecs[0]).To(ContainSubstring(`"_typeName":"VirtualVmxnet3"`))
+			Expect(plan.ConfigSpecs[0]).To(ContainSubstring(`"key":-100`))
+			Expect(plan.ConfigSpecs[1]).To(ContainSubstring(`"changeTrackingEnabled":true`))
+		})
+	})
+})
@@ -62,6 +62,10 @@ func getVMStorageClassNames(vm *vmopv1.VirtualMachine) []string {
 		names = append(names, vm.Spec.StorageClass)
 	}
 
+	if adv := vm.Spec.Advanced; adv != nil && adv.BootDiskStorageClass != "" {
+		names = append(names, adv.BootDiskStorageClass)
+	}
+
 	for _, vol := range vm.Spec.Volumes {
 		var storageClass string
 
@@ -5,10 +5,15 @@ package vcenter
 
 import (
 	goctx "context"
+	"fmt"
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 )
 
 // ClusterMinCPUFreq returns the minimum frequency across all the hosts in the cluster. This is needed to
@@ -42,3 +47,51 @@ func ClusterMinCPUFreq(ctx goctx.Context, cluster *object.ClusterComputeResource
 
 	return minFreq, nil
 }
+
+// GetClusterConfigInfoEx returns the cluster's configuration, which includes its DRS and vSphere HA settings.
+func GetClusterConfigInfoEx(ctx goctx.Context, cluster *object.ClusterComputeResource) (*types.ClusterConfigInfoEx, error) {
+	var cr mo.ClusterComputeResource
+	if err := cluster.Properties(ctx, cluster.Reference(), []string{"configurationEx"}, &cr); err != nil {
+		return nil, err
+	}
+
+	config, ok := cr.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s has unexpected configuration type %T", cluster.Reference().Value, cr.ConfigurationEx)
+	}
+
+	return config, nil
+}
+
+// GetClusterMaxHardwareVersion returns the highest hardware version that VMs can be created with on
+// the cluster. Zero is returned when the cluster does not report the hardware versions it supports.
+func GetClusterMaxHardwareVersion(ctx goctx.Context, cluster *object.ClusterComputeResource) (int32, error) {
+	var cr mo.ClusterComputeResource
+	if err := cluster.Properties(ctx, cluster.Reference(), []string{"environmentBrowser"}, &cr); err != nil {
+		return 0, err
+	}
+
+	if cr.EnvironmentBrowser == nil {
+		return 0, nil
+	}
+
+	res, err := methods.QueryConfigOptionDescriptor(ctx, cluster.Client(), &types.QueryConfigOptionDescriptor{
+		This: *cr.EnvironmentBrowser,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var maxVersion int32
+	for _, d := range res.Returnval {
+		if d.CreateSupported == nil || !*d.CreateSupported {
+			continue
+		}
+
+		if version := util.ParseVirtualHardwareVersion(d.Key); version > maxVersion {
+			maxVersion = version
+		}
+	}
+
+	return maxVersion, nil
+}
@@ -7,12 +7,17 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/pointer"
+
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
 
 func clusterTests() {
 	Describe("ClusterMinCPUFreq", minFreq)
+	Describe("GetClusterConfigInfoEx", clusterConfigInfoEx)
+	Describe("GetClusterMaxHardwareVersion", clusterMaxHardwareVersion)
 }
 
 func minFreq() {
@@ -45,3 +50,52 @@ func minFreq() {
 		})
 	})
 }
+
+func clusterConfigInfoEx() {
+	var (
+		ctx *builder.TestContextForVCSim
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true})
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	It("returns the cluster's DRS and HA configuration", func() {
+		ccr := ctx.GetSingleClusterCompute()
+		ctx.SetClusterDRSAndHA(ccr.Reference(), true, types.DrsBehaviorManual, true)
+
+		config, err := vcenter.GetClusterConfigInfoEx(ctx, ccr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.DrsConfig.Enabled).To(Equal(pointer.Bool(true)))
+		Expect(config.DrsConfig.DefaultVmBehavior).To(Equal(types.DrsBehaviorManual))
+		Expect(config.DasConfig.Enabled).To(Equal(pointer.Bool(true)))
+	})
+}
+
+func clusterMaxHardwareVersion() {
+	var (
+		ctx *builder.TestContextForVCSim
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true})
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	It("returns the highest hardware version VMs can be created with", func() {
+		ctx.SetHardwareVersions("vmx-15", "vmx-19", "vmx-17")
+
+		version, err := vcenter.GetClusterMaxHardwareVersion(ctx, ctx.GetSingleClusterCompute())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(BeEquivalentTo(19))
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vcenter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+// GetCryptoKeyProviders returns the IDs of the key providers registered with the CryptoManager.
+func GetCryptoKeyProviders(
+	ctx context.Context,
+	vimClient *vim25.Client) ([]string, error) {
+
+	cmRef := vimClient.ServiceContent.CryptoManager
+	if cmRef == nil {
+		return nil, fmt.Errorf("CryptoManager is not available")
+	}
+
+	var cm mo.CryptoManagerKmip
+	if err := property.DefaultCollector(vimClient).RetrieveOne(ctx, *cmRef, []string{"kmipServers"}, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get CryptoManager kmipServers: %w", err)
+	}
+
+	providerIDs := make([]string, 0, len(cm.KmipServers))
+	for _, kmip := range cm.KmipServers {
+		providerIDs = append(providerIDs, kmip.ClusterId.Id)
+	}
+
+	return providerIDs, nil
+}
+
+// ValidateCryptoKeyProvider returns an error if the key provider does not exist.
+func ValidateCryptoKeyProvider(
+	ctx context.Context,
+	vimClient *vim25.Client,
+	providerID string) error {
+
+	providerIDs, err := GetCryptoKeyProviders(ctx, vimClient)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range providerIDs {
+		if id == providerID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("crypto key provider %q does not exist", providerID)
+}
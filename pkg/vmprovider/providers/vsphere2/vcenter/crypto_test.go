@@ -0,0 +1,63 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vcenter_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func cryptoTests() {
+	Describe("ValidateCryptoKeyProvider", validateCryptoKeyProvider)
+}
+
+func validateCryptoKeyProvider() {
+	var (
+		ctx        *builder.TestContextForVCSim
+		testConfig builder.VCSimTestConfig
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithV1A2: true}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	It("returns error when no key providers are registered", func() {
+		err := vcenter.ValidateCryptoKeyProvider(ctx, ctx.VCClient.Client, "my-key-provider")
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("key providers are registered", func() {
+		JustBeforeEach(func() {
+			ctx.RegisterCryptoKeyProvider("my-key-provider")
+			ctx.RegisterCryptoKeyProvider("other-key-provider")
+		})
+
+		It("returns the registered key providers", func() {
+			providerIDs, err := vcenter.GetCryptoKeyProviders(ctx, ctx.VCClient.Client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerIDs).To(ConsistOf("my-key-provider", "other-key-provider"))
+		})
+
+		It("returns success when key provider exists", func() {
+			Expect(vcenter.ValidateCryptoKeyProvider(ctx, ctx.VCClient.Client, "my-key-provider")).To(Succeed())
+		})
+
+		It("returns error when key provider does not exist", func() {
+			err := vcenter.ValidateCryptoKeyProvider(ctx, ctx.VCClient.Client, "bogus")
+			Expect(err).To(MatchError(`crypto key provider "bogus" does not exist`))
+		})
+	})
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 )
 
 // GetVirtualMachine gets the VM from VC, either by the MoID, UUID, or the inventory path.
@@ -140,7 +142,30 @@ func findVMByInventory(
 	ref, err := object.NewSearchIndex(vimClient).FindChild(vmCtx, folder.Reference(), vmCtx.VM.Name)
 	if err != nil {
 		return nil, err
-	} else if ref == nil {
+	}
+
+	if displayName := vmCtx.VM.Annotations[constants.DisplayNameAnnotation]; ref == nil && displayName != "" {
+		// The VM may have been renamed to its display name.
+		ref, err = object.NewSearchIndex(vimClient).FindChild(vmCtx, folder.Reference(), displayName)
+		if err != nil {
+			return nil, err
+		}
+
+		if ref != nil {
+			owned, err := isVMOwnedBy(vmCtx, vimClient, ref.Reference())
+			if err != nil {
+				return nil, err
+			}
+			if !owned {
+				// Another VM already has the display name so do not adopt it.
+				vmCtx.Logger.Info("Ignoring VM with the display name that belongs to another VM",
+					"displayName", displayName, "moID", ref.Reference().Value)
+				ref = nil
+			}
+		}
+	}
+
+	if ref == nil {
 		// VM does not exist.
 		return nil, nil
 	}
@@ -154,3 +179,38 @@ func findVMByInventory(
 		"parentFolderMoID", folder.Reference().Value, "moID", vm.Reference().Value)
 	return vm, nil
 }
+
+// isVMOwnedBy returns true if the vSphere VM was created for the VM, that is the VM's namespace
+// and name are in the vSphere VM's ExtraConfig, or the vSphere VM's instance UUID is the one
+// in the VM's status.
+func isVMOwnedBy(
+	vmCtx context.VirtualMachineContextA2,
+	vimClient *vim25.Client,
+	ref types.ManagedObjectReference) (bool, error) {
+
+	if ref.Type != "VirtualMachine" {
+		return false, nil
+	}
+
+	var o mo.VirtualMachine
+	if err := object.NewVirtualMachine(vimClient, ref).Properties(
+		vmCtx, ref, []string{"config.extraConfig", "config.instanceUuid"}, &o); err != nil {
+		return false, err
+	}
+
+	if o.Config == nil {
+		return false, nil
+	}
+
+	if uuid := vmCtx.VM.Status.InstanceUUID; uuid != "" && o.Config.InstanceUuid == uuid {
+		return true, nil
+	}
+
+	for _, ec := range o.Config.ExtraConfig {
+		if ov := ec.GetOptionValue(); ov != nil && ov.Key == constants.VMNamespacedNameExtraConfigKey {
+			return ov.Value == vmCtx.VM.NamespacedName(), nil
+		}
+	}
+
+	return false, nil
+}
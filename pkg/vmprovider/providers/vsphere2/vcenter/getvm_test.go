@@ -13,6 +13,7 @@ import (
 
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -70,6 +71,35 @@ func getVM() {
 			Expect(vm).To(BeNil())
 		})
 
+		Context("VM was renamed to its display name", func() {
+			BeforeEach(func() {
+				vmCtx.VM.Annotations[constants.DisplayNameAnnotation] = vmCtx.VM.Name
+				vmCtx.VM.Name = "renamed"
+			})
+
+			It("returns success when the VM has the VM's namespaced name", func() {
+				vm, err := ctx.Finder.VirtualMachine(ctx, vmCtx.VM.Annotations[constants.DisplayNameAnnotation])
+				Expect(err).ToNot(HaveOccurred())
+				task, err := vm.Reconfigure(ctx, vimtypes.VirtualMachineConfigSpec{
+					ExtraConfig: []vimtypes.BaseOptionValue{
+						&vimtypes.OptionValue{Key: constants.VMNamespacedNameExtraConfigKey, Value: vmCtx.VM.NamespacedName()},
+					},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(task.Wait(ctx)).To(Succeed())
+
+				vm, err = vcenter.GetVirtualMachine(vmCtx, ctx.Client, ctx.VCClient.Client, ctx.Datacenter, ctx.Finder)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vm).ToNot(BeNil())
+			})
+
+			It("returns nil when the VM with the display name belongs to another VM", func() {
+				vm, err := vcenter.GetVirtualMachine(vmCtx, ctx.Client, ctx.VCClient.Client, ctx.Datacenter, ctx.Finder)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vm).To(BeNil())
+			})
+		})
+
 		Context("Namespace Folder does not exist", func() {
 			BeforeEach(func() {
 				task, err := nsInfo.Folder.Destroy(vmCtx)
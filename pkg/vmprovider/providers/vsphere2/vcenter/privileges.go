@@ -0,0 +1,92 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vcenter
+
+import (
+	goctx "context"
+	"fmt"
+	"sort"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RequiredPrivileges are the privileges, by the type of the managed object, that VM Operator
+// requires on the vCenter objects it is configured with.
+var RequiredPrivileges = map[string][]string{
+	"Datacenter": {
+		"VirtualMachine.Inventory.Create",
+	},
+	"Folder": {
+		"Folder.Create",
+		"VirtualMachine.Inventory.Create",
+		"VirtualMachine.Inventory.Delete",
+	},
+	"ResourcePool": {
+		"Resource.AssignVMToPool",
+		"Resource.CreatePool",
+	},
+	"Datastore": {
+		"Datastore.AllocateSpace",
+	},
+}
+
+// GetMissingPrivileges returns the RequiredPrivileges that the session does not have on each of
+// the entities. Entities that have all their required privileges are not in the returned map.
+func GetMissingPrivileges(
+	ctx goctx.Context,
+	vimClient *vim25.Client,
+	entities []types.ManagedObjectReference) (map[types.ManagedObjectReference][]string, error) {
+
+	missing := map[types.ManagedObjectReference][]string{}
+
+	privIDs := sets.New[string]()
+	for _, entity := range entities {
+		privIDs.Insert(RequiredPrivileges[entity.Type]...)
+	}
+	if privIDs.Len() == 0 {
+		return missing, nil
+	}
+
+	userSession, err := session.NewManager(vimClient).UserSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userSession == nil {
+		return nil, fmt.Errorf("no current session to check privileges of")
+	}
+
+	req := types.HasPrivilegeOnEntities{
+		This:      *vimClient.ServiceContent.AuthorizationManager,
+		Entity:    entities,
+		SessionId: userSession.Key,
+		PrivId:    sets.List(privIDs),
+	}
+
+	res, err := methods.HasPrivilegeOnEntities(ctx, vimClient, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entityPriv := range res.Returnval {
+		required := sets.New(RequiredPrivileges[entityPriv.Entity.Type]...)
+
+		var notGranted []string
+		for _, avail := range entityPriv.PrivAvailability {
+			if !avail.IsGranted && required.Has(avail.PrivId) {
+				notGranted = append(notGranted, avail.PrivId)
+			}
+		}
+
+		if len(notGranted) > 0 {
+			sort.Strings(notGranted)
+			missing[entityPriv.Entity] = notGranted
+		}
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vcenter_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func privilegesTests() {
+	Describe("GetMissingPrivileges", getMissingPrivileges)
+}
+
+func getMissingPrivileges() {
+
+	var (
+		ctx      *builder.TestContextForVCSim
+		nsInfo   builder.WorkloadNamespaceInfo
+		entities []types.ManagedObjectReference
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true})
+		nsInfo = ctx.CreateWorkloadNamespace()
+		entities = []types.ManagedObjectReference{
+			ctx.Datacenter.Reference(),
+			nsInfo.Folder.Reference(),
+		}
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	It("returns no missing privileges when all are granted", func() {
+		missing, err := vcenter.GetMissingPrivileges(ctx, ctx.VCClient.Client, entities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+	})
+
+	Context("with a restricted role", func() {
+		BeforeEach(func() {
+			ctx.SetRestrictedRole("VirtualMachine.Inventory.Create", "Folder.Create")
+		})
+
+		It("returns the missing privileges of each entity", func() {
+			missing, err := vcenter.GetMissingPrivileges(ctx, ctx.VCClient.Client, entities)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(HaveLen(1))
+			Expect(missing).To(HaveKeyWithValue(nsInfo.Folder.Reference(), []string{"VirtualMachine.Inventory.Delete"}))
+		})
+	})
+
+	It("returns no missing privileges for entities without required privileges", func() {
+		ctx.SetRestrictedRole()
+
+		missing, err := vcenter.GetMissingPrivileges(ctx, ctx.VCClient.Client,
+			[]types.ManagedObjectReference{{Type: "VirtualMachine", Value: "vm-1"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+	})
+}
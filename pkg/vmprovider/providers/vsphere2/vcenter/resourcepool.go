@@ -10,6 +10,7 @@ import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/types"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
@@ -46,6 +47,20 @@ func GetResourcePoolOwnerMoRef(
 	return objRef.Reference(), nil
 }
 
+// MoveIntoResourcePool moves the VMs into the ResourcePool. The VMs must already be in the
+// ResourcePool's cluster.
+func MoveIntoResourcePool(
+	ctx goctx.Context,
+	rp *object.ResourcePool,
+	vmRefs ...types.ManagedObjectReference) error {
+
+	_, err := methods.MoveIntoResourcePool(ctx, rp.Client(), &types.MoveIntoResourcePool{
+		This: rp.Reference(),
+		List: vmRefs,
+	})
+	return err
+}
+
 // GetChildResourcePool gets the named child ResourcePool from the parent ResourcePool.
 func GetChildResourcePool(
 	ctx goctx.Context,
@@ -61,6 +61,16 @@ func getResourcePoolTests() {
 			Expect(ccr).To(Equal(ctx.GetSingleClusterCompute().Reference()))
 		})
 
+		It("returns success for a child of the namespace ResourcePool", func() {
+			resourcePolicy, _ := ctx.CreateVirtualMachineSetResourcePolicyA2("my-child-rp", nsInfo)
+			Expect(resourcePolicy).ToNot(BeNil())
+			childRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", resourcePolicy.Spec.ResourcePool.Name)
+
+			ccr, err := vcenter.GetResourcePoolOwnerMoRef(ctx, ctx.VCClient.Client, childRP.Reference().Value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ccr).To(Equal(ctx.GetSingleClusterCompute().Reference()))
+		})
+
 		It("returns error when MoID does not exist", func() {
 			_, err := vcenter.GetResourcePoolOwnerMoRef(ctx, ctx.VCClient.Client, "bogus")
 			Expect(err).To(HaveOccurred())
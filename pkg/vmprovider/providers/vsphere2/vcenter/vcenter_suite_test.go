@@ -15,9 +15,11 @@ var suite = builder.NewTestSuite()
 
 func vcSimTests() {
 	Describe("Cluster", clusterTests)
+	Describe("Crypto", cryptoTests)
 	Describe("Folder", folderTests)
 	Describe("GetVM", getVMTests)
 	Describe("Host", hostTests)
+	Describe("Privileges", privilegesTests)
 	Describe("ResourcePool", resourcePoolTests)
 }
 
@@ -10,12 +10,14 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
 )
 
 // CreateConfigSpec returns an initial ConfigSpec that is created by overlaying the
-// base ConfigSpec with VM Class spec and other arguments.
+// base ConfigSpec with VM Class spec and other arguments. The CPU, memory, ExtraConfig,
+// and devices are merged with util.MergeConfigSpecs.
 // TODO: We eventually need to de-dupe much of this with the ConfigSpec manipulation that's later done
 // in the "update" pre-power on path. That operates on a ConfigInfo so we'd need to populate that from
 // the config we build here.
@@ -38,10 +40,6 @@ func CreateConfigSpec(
 		// If the class ConfigSpec doesn't specify any annotations, set the default one.
 		configSpec.Annotation = constants.VCVMAnnotation
 	}
-	// CPU and Memory configurations specified in the VM Class standalone fields take
-	// precedence over values in the config spec
-	configSpec.NumCPUs = int32(vmClassSpec.Hardware.Cpus)
-	configSpec.MemoryMB = MemoryQuantityToMb(vmClassSpec.Hardware.Memory)
 	configSpec.ManagedBy = &types.ManagedByInfo{
 		ExtensionKey: vmopv1.ManagedByExtensionKey,
 		Type:         vmopv1.ManagedByExtensionType,
@@ -58,6 +56,10 @@ func CreateConfigSpec(
 		configSpec.Firmware = vmImageStatus.Firmware
 	}
 
+	if providerID := vmCtx.VM.Annotations[constants.CryptoKeyProviderAnnotation]; providerID != "" {
+		configSpec.Crypto = CryptoSpecForKeyProvider(providerID)
+	}
+
 	// TODO: Otherwise leave as-is? Our ChangeBlockTracking could be better as a *bool.
 	if vmCtx.VM.Spec.Advanced == nil {
 		vmCtx.VM.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
@@ -66,11 +68,38 @@ func CreateConfigSpec(
 		configSpec.ChangeTrackingEnabled = pointer.Bool(true)
 	}
 
+	// CPU and Memory configurations specified in the VM Class standalone fields take
+	// precedence over values in the config spec. The image's defaults are applied by
+	// vSphere when the image is deployed, so there is no image ConfigSpec to merge.
+	merged := util.MergeConfigSpecs(
+		vmHardwareConfigSpec(vmClassSpec, minFreq),
+		vmClassConfigSpec,
+		nil)
+	configSpec.NumCPUs = merged.NumCPUs
+	configSpec.MemoryMB = merged.MemoryMB
+	configSpec.CpuAllocation = merged.CpuAllocation
+	configSpec.MemoryAllocation = merged.MemoryAllocation
+	configSpec.ExtraConfig = merged.ExtraConfig
+	configSpec.DeviceChange = merged.DeviceChange
+
+	return &configSpec
+}
+
+// vmHardwareConfigSpec returns the ConfigSpec with the CPU, memory, and their reservations and
+// limits from the VM Class standalone fields.
+func vmHardwareConfigSpec(
+	vmClassSpec *vmopv1.VirtualMachineClassSpec,
+	minFreq uint64) *types.VirtualMachineConfigSpec {
+
+	configSpec := &types.VirtualMachineConfigSpec{
+		NumCPUs:  int32(vmClassSpec.Hardware.Cpus),
+		MemoryMB: MemoryQuantityToMb(vmClassSpec.Hardware.Memory),
+	}
+
 	// Populate the CPU reservation and limits in the ConfigSpec if VAPI fields specify any.
 	// VM Class VAPI does not support Limits, so they will never be non nil.
 	// TODO: Remove limits: issues/56
 	if res := vmClassSpec.Policies.Resources; !res.Requests.Cpu.IsZero() || !res.Limits.Cpu.IsZero() {
-		// TODO: Always override?
 		configSpec.CpuAllocation = &types.ResourceAllocationInfo{
 			Shares: &types.SharesInfo{
 				Level: types.SharesLevelNormal,
@@ -90,7 +119,6 @@ func CreateConfigSpec(
 	// Populate the memory reservation and limits in the ConfigSpec if VAPI fields specify any.
 	// TODO: Remove limits: issues/56
 	if res := vmClassSpec.Policies.Resources; !res.Requests.Memory.IsZero() || !res.Limits.Memory.IsZero() {
-		// TODO: Always override?
 		configSpec.MemoryAllocation = &types.ResourceAllocationInfo{
 			Shares: &types.SharesInfo{
 				Level: types.SharesLevelNormal,
@@ -107,7 +135,7 @@ func CreateConfigSpec(
 		}
 	}
 
-	return &configSpec
+	return configSpec
 }
 
 // CreateConfigSpecForPlacement creates a ConfigSpec that is suitable for Placement.
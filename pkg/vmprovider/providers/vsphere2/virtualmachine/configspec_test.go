@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	vimtypes "github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
@@ -112,6 +113,36 @@ var _ = Describe("CreateConfigSpec", func() {
 			Expect(ok).To(BeTrue())
 		})
 
+		When("class ConfigSpec has CPU, memory, and ExtraConfig", func() {
+			BeforeEach(func() {
+				classConfigSpec.NumCPUs = 8
+				classConfigSpec.MemoryMB = 8 * 1024
+				classConfigSpec.ExtraConfig = []vimtypes.BaseOptionValue{
+					&vimtypes.OptionValue{Key: "class-key", Value: "class-value"},
+				}
+			})
+
+			It("config spec has the CPU and memory from the class standalone fields", func() {
+				Expect(configSpec.NumCPUs).To(BeEquivalentTo(vmClassSpec.Hardware.Cpus))
+				Expect(configSpec.MemoryMB).To(BeEquivalentTo(4 * 1024))
+				Expect(configSpec.ExtraConfig).To(ConsistOf(
+					&vimtypes.OptionValue{Key: "class-key", Value: "class-value"}))
+				Expect(configSpec.DeviceChange).To(HaveLen(1))
+			})
+
+			When("class standalone fields do not set the CPU and memory", func() {
+				BeforeEach(func() {
+					vmClassSpec.Hardware.Cpus = 0
+					vmClassSpec.Hardware.Memory = resource.Quantity{}
+				})
+
+				It("config spec has the CPU and memory from the class ConfigSpec", func() {
+					Expect(configSpec.NumCPUs).To(BeEquivalentTo(8))
+					Expect(configSpec.MemoryMB).To(BeEquivalentTo(8 * 1024))
+				})
+			})
+		})
+
 		When("Image firmware is empty", func() {
 			BeforeEach(func() {
 				vmImageStatus = &vmopv1.VirtualMachineImageStatus{}
@@ -142,6 +173,19 @@ var _ = Describe("CreateConfigSpec", func() {
 				Expect(configSpec.Firmware).ToNot(Equal(vm.Annotations[constants.FirmwareOverrideAnnotation]))
 			})
 		})
+
+		When("vm has a crypto key provider annotation", func() {
+			BeforeEach(func() {
+				vm.Annotations[constants.CryptoKeyProviderAnnotation] = "my-key-provider"
+			})
+
+			It("config spec has the encrypt crypto spec for the key provider", func() {
+				Expect(configSpec.Crypto).To(BeAssignableToTypeOf(&vimtypes.CryptoSpecEncrypt{}))
+				cryptoSpec := configSpec.Crypto.(*vimtypes.CryptoSpecEncrypt)
+				Expect(cryptoSpec.CryptoKeyId.ProviderId).ToNot(BeNil())
+				Expect(cryptoSpec.CryptoKeyId.ProviderId.Id).To(Equal("my-key-provider"))
+			})
+		})
 	})
 })
 
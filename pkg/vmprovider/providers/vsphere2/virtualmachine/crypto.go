@@ -0,0 +1,44 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// CryptoSpecForKeyProvider returns the CryptoSpec to encrypt a VM with a key from the provider.
+func CryptoSpecForKeyProvider(providerID string) types.BaseCryptoSpec {
+	return &types.CryptoSpecEncrypt{
+		CryptoKeyId: types.CryptoKeyId{
+			ProviderId: &types.KeyProviderId{
+				Id: providerID,
+			},
+		},
+	}
+}
+
+// CryptoKeyProviderFromConfigInfo returns the ID of the key provider the VM is encrypted
+// with, or an empty string if the VM is not encrypted.
+func CryptoKeyProviderFromConfigInfo(config *types.VirtualMachineConfigInfo) string {
+	if config == nil || config.KeyId == nil || config.KeyId.ProviderId == nil {
+		return ""
+	}
+	return config.KeyId.ProviderId.Id
+}
+
+func GetCryptoKeyProvider(
+	ctx context.Context,
+	vm *object.VirtualMachine) (string, error) {
+
+	var o mo.VirtualMachine
+	if err := vm.Properties(ctx, vm.Reference(), []string{"config.keyId"}, &o); err != nil {
+		return "", err
+	}
+
+	return CryptoKeyProviderFromConfigInfo(o.Config), nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
+)
+
+var _ = Describe("CryptoKeyProviderFromConfigInfo", func() {
+
+	var (
+		config *vimtypes.VirtualMachineConfigInfo
+	)
+
+	BeforeEach(func() {
+		config = &vimtypes.VirtualMachineConfigInfo{}
+	})
+
+	It("returns empty when the VM is not encrypted", func() {
+		Expect(virtualmachine.CryptoKeyProviderFromConfigInfo(nil)).To(BeEmpty())
+		Expect(virtualmachine.CryptoKeyProviderFromConfigInfo(config)).To(BeEmpty())
+	})
+
+	When("the VM is encrypted", func() {
+		BeforeEach(func() {
+			cryptoSpec := virtualmachine.CryptoSpecForKeyProvider("my-key-provider").(*vimtypes.CryptoSpecEncrypt)
+			config.KeyId = &cryptoSpec.CryptoKeyId
+		})
+
+		It("returns the requested key provider", func() {
+			Expect(virtualmachine.CryptoKeyProviderFromConfigInfo(config)).To(Equal("my-key-provider"))
+		})
+	})
+})
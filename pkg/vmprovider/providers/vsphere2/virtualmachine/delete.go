@@ -4,41 +4,147 @@
 package virtualmachine
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	"github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/retry"
 	vmutil "github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/vm"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 )
 
 func DeleteVirtualMachine(
 	vmCtx context.VirtualMachineContextA2,
 	vcVM *object.VirtualMachine) error {
 
-	if _, err := vmutil.SetAndWaitOnPowerState(
-		logr.NewContext(vmCtx, vmCtx.Logger),
-		vcVM.Client(),
-		vmutil.ManagedObjectFromObject(vcVM),
-		false,
-		types.VirtualMachinePowerStatePoweredOff,
-		vmutil.ParsePowerOpMode(string(vmCtx.VM.Spec.PowerOffMode))); err != nil {
+	// The VM is powered off before it is destroyed so its disks are left in a consistent
+	// state.
+	if err := powerOffForDelete(vmCtx, vcVM); err != nil {
+		return err
+	}
+
+	if err := DetachFirstClassDisks(vmCtx, vcVM); err != nil {
+		return err
+	}
+
+	return retry.OnTransientFault(func() error {
+		t, err := vcVM.Destroy(vmCtx)
+		if err != nil {
+			return err
+		}
+
+		if taskInfo, err := t.WaitForResult(vmCtx); err != nil {
+			if taskInfo != nil {
+				vmCtx.Logger.V(5).Error(err, "destroy VM task failed", "taskInfo", taskInfo)
+			}
+			return errors.Wrapf(err, "destroy VM task failed")
+		}
+
+		return nil
+	})
+}
+
+// PowerOffVirtualMachineUntilDestroy powers off the VM of a deleted VM resource that has a
+// deletion grace period, and records on the VM the time after which it is destroyed so the VM is
+// not garbage collected as an orphan before then.
+func PowerOffVirtualMachineUntilDestroy(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	destroyTime time.Time) error {
+
+	if err := powerOffForDelete(vmCtx, vcVM); err != nil {
+		return err
+	}
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), []string{"config.extraConfig"}, &o); err != nil {
+		return err
+	}
+
+	value := destroyTime.UTC().Format(time.RFC3339)
+
+	var extraConfig []types.BaseOptionValue
+	if o.Config != nil {
+		extraConfig = o.Config.ExtraConfig
+	}
+	if util.ExtraConfigToMap(extraConfig)[constants.VMDestroyTimeExtraConfigKey] == value {
+		return nil
+	}
 
+	configSpec := types.VirtualMachineConfigSpec{
+		ExtraConfig: []types.BaseOptionValue{
+			&types.OptionValue{Key: constants.VMDestroyTimeExtraConfigKey, Value: value},
+		},
+	}
+
+	t, err := vcVM.Reconfigure(vmCtx, configSpec)
+	if err != nil {
 		return err
 	}
 
-	t, err := vcVM.Destroy(vmCtx)
+	if taskInfo, err := t.WaitForResult(vmCtx); err != nil {
+		if taskInfo != nil {
+			vmCtx.Logger.V(5).Error(err, "destroy time reconfigure task failed", "taskInfo", taskInfo)
+		}
+		return errors.Wrapf(err, "destroy time reconfigure task failed")
+	}
+
+	return nil
+}
+
+// ReleaseVirtualMachine leaves the VM intact in vSphere when its VM resource is deleted,
+// marking it as no longer managed by VM Service.
+func ReleaseVirtualMachine(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine) error {
+
+	configSpec := types.VirtualMachineConfigSpec{
+		Annotation: constants.VCVMReleasedAnnotation,
+		// An empty ManagedBy clears the VM's existing ManagedBy.
+		ManagedBy: &types.ManagedByInfo{},
+	}
+
+	t, err := vcVM.Reconfigure(vmCtx, configSpec)
 	if err != nil {
 		return err
 	}
 
 	if taskInfo, err := t.WaitForResult(vmCtx); err != nil {
 		if taskInfo != nil {
-			vmCtx.Logger.V(5).Error(err, "destroy VM task failed", "taskInfo", taskInfo)
+			vmCtx.Logger.V(5).Error(err, "release VM task failed", "taskInfo", taskInfo)
 		}
-		return errors.Wrapf(err, "destroy VM task failed")
+		return errors.Wrapf(err, "release VM task failed")
 	}
 
 	return nil
 }
+
+// powerOffForDelete powers off the VM of a deleted VM resource. A guest shutdown is bounded by
+// the delete timeout, after which the VM is hard powered off so the delete cannot be blocked by
+// an unresponsive guest.
+func powerOffForDelete(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine) error {
+
+	powerOffMode := vmutil.ParsePowerOpMode(string(vmCtx.VM.Spec.PowerOffMode))
+	if powerOffMode == vmutil.PowerOpBehaviorSoft {
+		powerOffMode = vmutil.PowerOpBehaviorTrySoft
+	}
+
+	_, err := vmutil.SetAndWaitOnPowerState(
+		vmutil.WithSoftTimeout(logr.NewContext(vmCtx, vmCtx.Logger), lib.GetVMDeletePowerOffTimeout()),
+		vcVM.Client(),
+		vmutil.ManagedObjectFromObject(vcVM),
+		false,
+		types.VirtualMachinePowerStatePoweredOff,
+		powerOffMode)
+
+	return err
+}
@@ -4,6 +4,11 @@
 package virtualmachine
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
 	vimTypes "github.com/vmware/govmomi/vim25/types"
 	"k8s.io/utils/pointer"
 
@@ -15,6 +20,7 @@ const (
 	// A negative device range is traditionally used.
 	pciDevicesStartDeviceKey      = int32(-200)
 	instanceStorageStartDeviceKey = int32(-300)
+	serialPortsStartDeviceKey     = int32(-400)
 )
 
 func CreatePCIPassThroughDevice(deviceKey int32, backingInfo vimTypes.BaseVirtualDeviceBackingInfo) vimTypes.BaseVirtualDevice {
@@ -98,3 +104,100 @@ func CreateInstanceStorageDiskDevices(isVolumes []vmopv1.VirtualMachineVolume) [
 
 	return devices
 }
+
+// GetDeviceConnectionStatus returns the connection state of each connectable device in the list.
+func GetDeviceConnectionStatus(devices object.VirtualDeviceList) []vmopv1.VirtualMachineDeviceStatus {
+	var out []vmopv1.VirtualMachineDeviceStatus
+
+	for _, device := range devices {
+		vd := device.GetVirtualDevice()
+		if vd.Connectable == nil {
+			continue
+		}
+
+		status := vmopv1.VirtualMachineDeviceStatus{
+			Key:               vd.Key,
+			Type:              devices.TypeName(device),
+			Connected:         vd.Connectable.Connected,
+			StartConnected:    vd.Connectable.StartConnected,
+			AllowGuestControl: vd.Connectable.AllowGuestControl,
+		}
+		if info := vd.DeviceInfo; info != nil {
+			status.Label = info.GetDescription().Label
+		}
+
+		out = append(out, status)
+	}
+
+	return out
+}
+
+func GetVirtualMachineDeviceConnectionStatus(
+	ctx context.Context,
+	vm *object.VirtualMachine) ([]vmopv1.VirtualMachineDeviceStatus, error) {
+
+	var o mo.VirtualMachine
+	if err := vm.Properties(ctx, vm.Reference(), []string{"config.hardware.device"}, &o); err != nil {
+		return nil, err
+	}
+
+	if o.Config == nil {
+		return nil, nil
+	}
+
+	return GetDeviceConnectionStatus(o.Config.Hardware.Device), nil
+}
+
+// SetDeviceConnected connects or disconnects the VM's NIC or CD-ROM with the device key.
+func SetDeviceConnected(
+	ctx context.Context,
+	vm *object.VirtualMachine,
+	deviceKey int32,
+	connected bool) error {
+
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return err
+	}
+
+	device := devices.FindByKey(deviceKey)
+	if device == nil {
+		return fmt.Errorf("device %d not found", deviceKey)
+	}
+
+	vd := device.GetVirtualDevice()
+	if !supportsRuntimeConnect(device) || vd.Connectable == nil {
+		return fmt.Errorf("device %d does not support runtime connect", deviceKey)
+	}
+
+	if vd.Connectable.Connected == connected {
+		return nil
+	}
+
+	connectable := *vd.Connectable
+	connectable.Connected = connected
+	vd.Connectable = &connectable
+
+	task, err := vm.Reconfigure(ctx, vimTypes.VirtualMachineConfigSpec{
+		DeviceChange: []vimTypes.BaseVirtualDeviceConfigSpec{
+			&vimTypes.VirtualDeviceConfigSpec{
+				Operation: vimTypes.VirtualDeviceConfigSpecOperationEdit,
+				Device:    device,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return task.Wait(ctx)
+}
+
+func supportsRuntimeConnect(device vimTypes.BaseVirtualDevice) bool {
+	switch device.(type) {
+	case vimTypes.BaseVirtualEthernetCard, *vimTypes.VirtualCdrom:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+)
+
+// DetachAndDeleteDisk removes the VM's disk with the device key from the VM and, when deleteFile
+// is true, deletes the disk's backing file. The VM's boot disk cannot be removed, nor can First
+// Class Disks, which are managed as the VM's volumes.
+func DetachAndDeleteDisk(
+	ctx context.Context,
+	vm *object.VirtualMachine,
+	diskKey int32,
+	deleteFile bool) error {
+
+	var o mo.VirtualMachine
+	if err := vm.Properties(ctx, vm.Reference(), []string{"config.hardware.device", "config.bootOptions"}, &o); err != nil {
+		return err
+	}
+
+	if o.Config == nil {
+		return fmt.Errorf("disk %d not found", diskKey)
+	}
+
+	devices := object.VirtualDeviceList(o.Config.Hardware.Device)
+
+	device := devices.FindByKey(diskKey)
+	if device == nil {
+		return fmt.Errorf("disk %d not found", diskKey)
+	}
+
+	disk, ok := device.(*vimTypes.VirtualDisk)
+	if !ok {
+		return fmt.Errorf("device %d is not a disk", diskKey)
+	}
+
+	if disk.VDiskId != nil && disk.VDiskId.Id != "" {
+		return fmt.Errorf("disk %d is a First Class Disk", diskKey)
+	}
+
+	if bootDiskKey(devices, o.Config.BootOptions) == diskKey {
+		return fmt.Errorf("disk %d is the boot disk", diskKey)
+	}
+
+	deviceChange := &vimTypes.VirtualDeviceConfigSpec{
+		Operation: vimTypes.VirtualDeviceConfigSpecOperationRemove,
+		Device:    disk,
+	}
+	if deleteFile {
+		deviceChange.FileOperation = vimTypes.VirtualDeviceConfigSpecFileOperationDestroy
+	}
+
+	task, err := vm.Reconfigure(ctx, vimTypes.VirtualMachineConfigSpec{
+		DeviceChange: []vimTypes.BaseVirtualDeviceConfigSpec{deviceChange},
+	})
+	if err != nil {
+		return err
+	}
+
+	return task.Wait(ctx)
+}
+
+// bootDiskKey returns the device key of the first disk in the VM's boot order or, when the boot
+// order does not have a disk, of the VM's first disk.
+func bootDiskKey(devices object.VirtualDeviceList, bootOptions *vimTypes.VirtualMachineBootOptions) int32 {
+	if bootOptions != nil {
+		for _, bootable := range bootOptions.BootOrder {
+			if d, ok := bootable.(*vimTypes.VirtualMachineBootOptionsBootableDiskDevice); ok {
+				return d.DeviceKey
+			}
+		}
+	}
+
+	if disks := devices.SelectByType((*vimTypes.VirtualDisk)(nil)); len(disks) > 0 {
+		return disks[0].GetVirtualDevice().Key
+	}
+
+	return 0
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	goctx "context"
+	"errors"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
+)
+
+// GetFirstClassDiskIDs returns the IDs of the First Class Disks of the VM's FirstClassDisk volumes,
+// including the volumes that were removed from the Spec but are still in the Status.
+func GetFirstClassDiskIDs(vm *vmopv1.VirtualMachine) []string {
+	var diskIDs []string
+	seen := map[string]struct{}{}
+
+	for _, vol := range vm.Spec.Volumes {
+		if fcd := vol.FirstClassDisk; fcd != nil && fcd.DiskID != "" {
+			if _, ok := seen[fcd.DiskID]; !ok {
+				seen[fcd.DiskID] = struct{}{}
+				diskIDs = append(diskIDs, fcd.DiskID)
+			}
+		}
+	}
+
+	for _, vol := range vm.Status.Volumes {
+		if vol.DiskID != "" {
+			if _, ok := seen[vol.DiskID]; !ok {
+				seen[vol.DiskID] = struct{}{}
+				diskIDs = append(diskIDs, vol.DiskID)
+			}
+		}
+	}
+
+	return diskIDs
+}
+
+// GetFirstClassDisks returns the disks in the device list that are First Class Disks, keyed by
+// the disk ID.
+func GetFirstClassDisks(devices object.VirtualDeviceList) map[string]*vimTypes.VirtualDisk {
+	disks := map[string]*vimTypes.VirtualDisk{}
+
+	for _, device := range devices.SelectByType((*vimTypes.VirtualDisk)(nil)) {
+		disk := device.(*vimTypes.VirtualDisk)
+		if disk.VDiskId != nil && disk.VDiskId.Id != "" {
+			disks[disk.VDiskId.Id] = disk
+		}
+	}
+
+	return disks
+}
+
+// FindFirstClassDisk returns the First Class Disk with the ID from the first of the datastores
+// that has it.
+func FindFirstClassDisk(
+	ctx goctx.Context,
+	vimClient *vim25.Client,
+	datastores []*object.Datastore,
+	diskID string) (*vimTypes.VStorageObject, error) {
+
+	m := vslm.NewObjectManager(vimClient)
+
+	for _, ds := range datastores {
+		obj, err := m.Retrieve(ctx, ds, diskID)
+		if err != nil {
+			if errors.Is(providererrors.Classify(err), providererrors.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get first class disk %s from datastore %s: %w", diskID, ds.Reference().Value, err)
+		}
+
+		return obj, nil
+	}
+
+	return nil, fmt.Errorf("first class disk %s not found", diskID)
+}
+
+// GetFirstClassDiskVM returns the VM, other than the excluded VM, that the First Class Disk is
+// attached to, or nil if it is not attached to another VM. The VMs are looked up by the disk's
+// ID with the vStorageObject associations, or, when vCenter does not support the associations,
+// from the VMs on the disk's datastore.
+func GetFirstClassDiskVM(
+	ctx goctx.Context,
+	vimClient *vim25.Client,
+	obj *vimTypes.VStorageObject,
+	excludeVMRef vimTypes.ManagedObjectReference) (*vimTypes.ManagedObjectReference, error) {
+
+	backing, ok := obj.Config.Backing.(*vimTypes.BaseConfigInfoDiskFileBackingInfo)
+	if !ok {
+		return nil, fmt.Errorf("first class disk %s does not have a disk file backing", obj.Config.Id.Id)
+	}
+
+	req := vimTypes.RetrieveVStorageObjectAssociations{
+		This: *vimClient.ServiceContent.VStorageObjectManager,
+		Ids: []vimTypes.RetrieveVStorageObjSpec{
+			{Id: obj.Config.Id, Datastore: backing.Datastore},
+		},
+	}
+
+	res, err := methods.RetrieveVStorageObjectAssociations(ctx, vimClient, &req)
+	if err != nil {
+		if !isMethodNotFound(err) {
+			return nil, fmt.Errorf("failed to get first class disk %s associations: %w", obj.Config.Id.Id, err)
+		}
+		return getFirstClassDiskVMOnDatastore(ctx, vimClient, backing.Datastore, excludeVMRef, obj.Config.Id.Id)
+	}
+
+	for _, association := range res.Returnval {
+		for _, vmDisk := range association.VmDiskAssociations {
+			ref := vimTypes.ManagedObjectReference{Type: "VirtualMachine", Value: vmDisk.VmId}
+			if ref != excludeVMRef {
+				return &ref, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getFirstClassDiskVMOnDatastore returns the VM on the datastore, other than the excluded VM, that
+// the First Class Disk is attached to, or nil if it is not attached to another VM.
+func getFirstClassDiskVMOnDatastore(
+	ctx goctx.Context,
+	vimClient *vim25.Client,
+	dsRef vimTypes.ManagedObjectReference,
+	excludeVMRef vimTypes.ManagedObjectReference,
+	diskID string) (*vimTypes.ManagedObjectReference, error) {
+
+	pc := property.DefaultCollector(vimClient)
+
+	var ds mo.Datastore
+	if err := pc.RetrieveOne(ctx, dsRef, []string{"vm"}, &ds); err != nil {
+		return nil, fmt.Errorf("failed to get datastore %s VMs: %w", dsRef.Value, err)
+	}
+
+	vmRefs := make([]vimTypes.ManagedObjectReference, 0, len(ds.Vm))
+	for _, ref := range ds.Vm {
+		if ref != excludeVMRef {
+			vmRefs = append(vmRefs, ref)
+		}
+	}
+	if len(vmRefs) == 0 {
+		return nil, nil
+	}
+
+	var vms []mo.VirtualMachine
+	if err := pc.Retrieve(ctx, vmRefs, []string{"config.hardware.device"}, &vms); err != nil {
+		return nil, fmt.Errorf("failed to get VM devices: %w", err)
+	}
+
+	for _, vm := range vms {
+		if vm.Config == nil {
+			continue
+		}
+
+		if _, ok := GetFirstClassDisks(vm.Config.Hardware.Device)[diskID]; ok {
+			ref := vm.Self
+			return &ref, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func isMethodNotFound(err error) bool {
+	if !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(vimTypes.MethodNotFound)
+	return ok
+}
+
+// CreateFirstClassDiskDevice returns a disk device that attaches the existing First Class Disk.
+// The caller must assign the disk to a controller.
+func CreateFirstClassDiskDevice(obj *vimTypes.VStorageObject) (*vimTypes.VirtualDisk, error) {
+	backing, ok := obj.Config.Backing.(*vimTypes.BaseConfigInfoDiskFileBackingInfo)
+	if !ok {
+		return nil, fmt.Errorf("first class disk %s does not have a disk file backing", obj.Config.Id.Id)
+	}
+
+	datastore := backing.Datastore
+	disk := &vimTypes.VirtualDisk{
+		CapacityInBytes: obj.Config.CapacityInMB * 1024 * 1024,
+		VirtualDevice: vimTypes.VirtualDevice{
+			Backing: &vimTypes.VirtualDiskFlatVer2BackingInfo{
+				VirtualDeviceFileBackingInfo: vimTypes.VirtualDeviceFileBackingInfo{
+					FileName:  backing.FilePath,
+					Datastore: &datastore,
+				},
+				DiskMode: string(vimTypes.VirtualDiskModePersistent),
+			},
+		},
+		VDiskId: &vimTypes.ID{
+			Id: obj.Config.Id.Id,
+		},
+	}
+
+	return disk, nil
+}
+
+// FindFirstClassDiskController returns a controller with an available slot for another disk. The
+// SCSI and NVMe controllers are preferred since a disk can be hot added to them. When hotAdd is
+// true, the disk is being added to a powered on VM, so the IDE controllers are not used.
+func FindFirstClassDiskController(devices object.VirtualDeviceList, hotAdd bool) (vimTypes.BaseVirtualController, error) {
+	names := []string{"scsi", "nvme", "ide"}
+	if hotAdd {
+		names = names[:2]
+	}
+
+	for _, name := range names {
+		if controller, err := devices.FindDiskController(name); err == nil {
+			return controller, nil
+		}
+	}
+
+	if hotAdd {
+		return nil, fmt.Errorf("no SCSI or NVMe disk controller has an available slot to hot add the disk")
+	}
+	return nil, fmt.Errorf("no disk controller has an available slot")
+}
+
+// DetachFirstClassDisks detaches the VM's FirstClassDisk volumes from the VM. The disks are
+// managed outside of VM Operator, so they must be detached before the VM is destroyed for the
+// disks to not be deleted along with the VM.
+func DetachFirstClassDisks(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine) error {
+
+	diskIDs := GetFirstClassDiskIDs(vmCtx.VM)
+	if len(diskIDs) == 0 {
+		return nil
+	}
+
+	devices, err := vcVM.Device(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	disks := GetFirstClassDisks(devices)
+
+	var deviceChanges []vimTypes.BaseVirtualDeviceConfigSpec
+	for _, diskID := range diskIDs {
+		if disk, ok := disks[diskID]; ok {
+			// The FileOperation is not set so the disk is not deleted.
+			deviceChanges = append(deviceChanges, &vimTypes.VirtualDeviceConfigSpec{
+				Operation: vimTypes.VirtualDeviceConfigSpecOperationRemove,
+				Device:    disk,
+			})
+		}
+	}
+
+	if len(deviceChanges) == 0 {
+		return nil
+	}
+
+	vmCtx.Logger.Info("Detaching first class disks", "diskIDs", diskIDs)
+	t, err := vcVM.Reconfigure(vmCtx, vimTypes.VirtualMachineConfigSpec{DeviceChange: deviceChanges})
+	if err != nil {
+		return err
+	}
+
+	return t.Wait(vmCtx)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+)
+
+// GetFileLayout returns the datastore path of the VM's configuration file, and the files that
+// make up the VM, such as its virtual disks, from the VM's detailed file layout.
+func GetFileLayout(
+	ctx context.Context,
+	vcVM *object.VirtualMachine) (string, []vimTypes.VirtualMachineFileLayoutExFileInfo, error) {
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(ctx, vcVM.Reference(), []string{"config.files.vmPathName", "layoutEx.file"}, &o); err != nil {
+		return "", nil, err
+	}
+
+	var vmxPath string
+	if o.Config != nil {
+		vmxPath = o.Config.Files.VmPathName
+	}
+
+	var files []vimTypes.VirtualMachineFileLayoutExFileInfo
+	if o.LayoutEx != nil {
+		files = o.LayoutEx.File
+	}
+
+	return vmxPath, files, nil
+}
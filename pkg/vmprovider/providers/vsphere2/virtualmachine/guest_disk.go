@@ -0,0 +1,48 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+)
+
+// GetVirtualMachineGuestDiskStatus returns the usage of the VM's guest filesystems, or nil when
+// VMware Tools has not reported them.
+func GetVirtualMachineGuestDiskStatus(
+	ctx context.Context,
+	vcVM *object.VirtualMachine) ([]vmopv1.VirtualMachineGuestDiskStatus, error) {
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(ctx, vcVM.Reference(), []string{"guest.disk"}, &o); err != nil {
+		return nil, err
+	}
+
+	return GetGuestDiskStatus(o.Guest), nil
+}
+
+// GetGuestDiskStatus returns the usage of the guest filesystems in the VM's guest info.
+func GetGuestDiskStatus(guest *vimTypes.GuestInfo) []vmopv1.VirtualMachineGuestDiskStatus {
+	if guest == nil || len(guest.Disk) == 0 {
+		return nil
+	}
+
+	status := make([]vmopv1.VirtualMachineGuestDiskStatus, 0, len(guest.Disk))
+	for _, disk := range guest.Disk {
+		status = append(status, vmopv1.VirtualMachineGuestDiskStatus{
+			DiskPath:       disk.DiskPath,
+			FilesystemType: disk.FilesystemType,
+			Capacity:       resource.NewQuantity(disk.Capacity, resource.BinarySI),
+			FreeSpace:      resource.NewQuantity(disk.FreeSpace, resource.BinarySI),
+		})
+	}
+
+	return status
+}
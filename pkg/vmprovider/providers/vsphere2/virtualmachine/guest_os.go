@@ -0,0 +1,66 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// GetGuestOSDescriptor returns the cluster's descriptor of the VM's guest OS at the VM's hardware
+// version, or nil if the cluster does not have a descriptor for the guest OS.
+func GetGuestOSDescriptor(
+	ctx context.Context,
+	cluster *object.ClusterComputeResource,
+	config *types.VirtualMachineConfigInfo) (*types.GuestOsDescriptor, error) {
+
+	configOption, err := getConfigOption(ctx, cluster, config)
+	if err != nil || configOption == nil {
+		return nil, err
+	}
+
+	for i := range configOption.GuestOSDescriptor {
+		if d := &configOption.GuestOSDescriptor[i]; d.Id == config.GuestId {
+			return d, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getConfigOption returns the cluster's config option for the VM's guest OS at the VM's hardware
+// version, or nil if the cluster does not have an environment browser.
+func getConfigOption(
+	ctx context.Context,
+	cluster *object.ClusterComputeResource,
+	config *types.VirtualMachineConfigInfo) (*types.VirtualMachineConfigOption, error) {
+
+	var ccr mo.ClusterComputeResource
+	pc := property.DefaultCollector(cluster.Client())
+	if err := pc.RetrieveOne(ctx, cluster.Reference(), []string{"environmentBrowser"}, &ccr); err != nil {
+		return nil, err
+	}
+
+	if ccr.EnvironmentBrowser == nil {
+		return nil, nil
+	}
+
+	res, err := methods.QueryConfigOptionEx(ctx, cluster.Client(), &types.QueryConfigOptionEx{
+		This: *ccr.EnvironmentBrowser,
+		Spec: &types.EnvironmentBrowserConfigOptionQuerySpec{
+			Key:     config.Version,
+			GuestId: []string{config.GuestId},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
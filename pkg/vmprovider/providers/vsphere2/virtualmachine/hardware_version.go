@@ -0,0 +1,71 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
+)
+
+// GetHardwareVersionUpgradeTargets returns the VM's hardware version, and the higher hardware
+// versions, in ascending order, that the VM can be upgraded to on its cluster.
+func GetHardwareVersionUpgradeTargets(
+	ctx context.Context,
+	vcVM *object.VirtualMachine) (int32, []int32, error) {
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(ctx, vcVM.Reference(), []string{"config.version"}, &o); err != nil {
+		return 0, nil, err
+	}
+
+	if o.Config == nil {
+		return 0, nil, nil
+	}
+	current := util.ParseVirtualHardwareVersion(o.Config.Version)
+
+	cluster, err := GetVMClusterComputeResource(ctx, vcVM)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var ccr mo.ClusterComputeResource
+	pc := property.DefaultCollector(vcVM.Client())
+	if err := pc.RetrieveOne(ctx, cluster.Reference(), []string{"environmentBrowser"}, &ccr); err != nil {
+		return 0, nil, err
+	}
+
+	if ccr.EnvironmentBrowser == nil {
+		return current, nil, nil
+	}
+
+	res, err := methods.QueryConfigOptionDescriptor(ctx, vcVM.Client(), &types.QueryConfigOptionDescriptor{
+		This: *ccr.EnvironmentBrowser,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var targets []int32
+	for _, d := range res.Returnval {
+		if d.UpgradeSupported == nil || !*d.UpgradeSupported {
+			continue
+		}
+
+		if version := util.ParseVirtualHardwareVersion(d.Key); version > current {
+			targets = append(targets, version)
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	return current, targets, nil
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+// GetUUIDs returns the BIOS UUID and the instance UUID of the VM. The BIOS UUID is the node UUID
+// that the CSI driver attaches volumes to the VM with.
+func GetUUIDs(
+	ctx context.Context,
+	vcVM *object.VirtualMachine) (string, string, error) {
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(ctx, vcVM.Reference(), []string{"summary.config"}, &o); err != nil {
+		return "", "", err
+	}
+
+	return o.Summary.Config.Uuid, o.Summary.Config.InstanceUuid, nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/pointer"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+)
+
+// GetResourceAllocationStatus returns the CPU and memory allocation configured on the VM, and the
+// allocation in effect for the VM once the limits of its parent resource pools are applied. The VM
+// and its parent resource pools are retrieved with a single property collector query.
+func GetResourceAllocationStatus(
+	ctx context.Context,
+	vcVM *object.VirtualMachine) (*vmopv1.VirtualMachineResourceAllocationStatus, error) {
+
+	vmRef := vcVM.Reference()
+	req := vimTypes.RetrieveProperties{
+		SpecSet: []vimTypes.PropertyFilterSpec{
+			{
+				ObjectSet: []vimTypes.ObjectSpec{
+					{
+						Obj: vmRef,
+						SelectSet: []vimTypes.BaseSelectionSpec{
+							&vimTypes.TraversalSpec{
+								Type: "VirtualMachine",
+								Path: "resourcePool",
+								SelectSet: []vimTypes.BaseSelectionSpec{
+									&vimTypes.SelectionSpec{Name: "resourcePoolParent"},
+								},
+							},
+							&vimTypes.TraversalSpec{
+								SelectionSpec: vimTypes.SelectionSpec{Name: "resourcePoolParent"},
+								Type:          "ResourcePool",
+								Path:          "parent",
+								SelectSet: []vimTypes.BaseSelectionSpec{
+									&vimTypes.SelectionSpec{Name: "resourcePoolParent"},
+								},
+							},
+						},
+					},
+				},
+				PropSet: []vimTypes.PropertySpec{
+					{
+						Type:    "VirtualMachine",
+						PathSet: []string{"config.cpuAllocation", "config.memoryAllocation"},
+					},
+					{
+						Type:    "ResourcePool",
+						PathSet: []string{"runtime"},
+					},
+				},
+			},
+		},
+	}
+
+	res, err := property.DefaultCollector(vcVM.Client()).RetrieveProperties(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var o mo.VirtualMachine
+	var resourcePools []mo.ResourcePool
+	for _, c := range res.Returnval {
+		switch c.Obj.Type {
+		case "VirtualMachine":
+			if err := mo.LoadObjectContent([]vimTypes.ObjectContent{c}, &o); err != nil {
+				return nil, err
+			}
+		case "ResourcePool":
+			var rp mo.ResourcePool
+			if err := mo.LoadObjectContent([]vimTypes.ObjectContent{c}, &rp); err != nil {
+				return nil, err
+			}
+			resourcePools = append(resourcePools, rp)
+		}
+	}
+
+	if o.Config == nil || len(resourcePools) == 0 {
+		return nil, nil
+	}
+
+	status := &vmopv1.VirtualMachineResourceAllocationStatus{}
+	if o.Config.CpuAllocation != nil {
+		status.ConfiguredCPU = toResourceAllocation(*o.Config.CpuAllocation)
+	}
+	if o.Config.MemoryAllocation != nil {
+		status.ConfiguredMemory = toResourceAllocation(*o.Config.MemoryAllocation)
+	}
+
+	// vSphere reports the upper bound on the usage of a resource pool, which is based on the limits
+	// of the resource pool and of its parents. The memory usage is in bytes, while the VM's memory
+	// allocation is in MiB.
+	var cpuMaxUsages, memoryMaxUsages []int64
+	for _, rp := range resourcePools {
+		cpuMaxUsages = append(cpuMaxUsages, rp.Runtime.Cpu.MaxUsage)
+		memoryMaxUsages = append(memoryMaxUsages, rp.Runtime.Memory.MaxUsage/(1024*1024))
+	}
+
+	status.EffectiveCPU = effectiveResourceAllocation(status.ConfiguredCPU, cpuMaxUsages)
+	status.EffectiveMemory = effectiveResourceAllocation(status.ConfiguredMemory, memoryMaxUsages)
+
+	return status, nil
+}
+
+func toResourceAllocation(info vimTypes.ResourceAllocationInfo) vmopv1.VirtualMachineResourceAllocation {
+	allocation := vmopv1.VirtualMachineResourceAllocation{
+		Reservation: pointer.Int64Deref(info.Reservation, 0),
+	}
+
+	// A negative limit means the resource is unlimited.
+	if info.Limit != nil && *info.Limit >= 0 {
+		allocation.Limit = pointer.Int64(*info.Limit)
+	}
+
+	if info.Shares != nil {
+		allocation.Shares = info.Shares.Shares
+	}
+
+	return allocation
+}
+
+// effectiveResourceAllocation returns the allocation in effect for the VM. The VM cannot use more
+// of the resource than the upper bound vSphere reports for the usage of its parent resource pools,
+// and so neither can its reservation be more than that. Shares are relative to the VM's siblings so
+// are unchanged. An upper bound of zero is not known, ex. when the resource pool has not been
+// updated by vSphere yet, and is ignored.
+func effectiveResourceAllocation(
+	configured vmopv1.VirtualMachineResourceAllocation,
+	parentMaxUsages []int64) vmopv1.VirtualMachineResourceAllocation {
+
+	effective := *configured.DeepCopy()
+
+	for _, maxUsage := range parentMaxUsages {
+		if maxUsage <= 0 {
+			continue
+		}
+
+		if effective.Limit == nil || maxUsage < *effective.Limit {
+			effective.Limit = pointer.Int64(maxUsage)
+		}
+	}
+
+	if effective.Limit != nil && effective.Reservation > *effective.Limit {
+		effective.Reservation = *effective.Limit
+	}
+
+	return effective
+}
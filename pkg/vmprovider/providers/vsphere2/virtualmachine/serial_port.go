@@ -0,0 +1,127 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/object"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+)
+
+// CreateSerialPortDevices creates the network backed serial port devices for the serial ports.
+func CreateSerialPortDevices(ports []vmopv1.VirtualMachineSerialPortSpec) []vimTypes.BaseVirtualDevice {
+	devices := make([]vimTypes.BaseVirtualDevice, 0, len(ports))
+
+	deviceKey := serialPortsStartDeviceKey
+
+	for _, port := range ports {
+		devices = append(devices, &vimTypes.VirtualSerialPort{
+			VirtualDevice: vimTypes.VirtualDevice{
+				Key:     deviceKey,
+				Backing: serialPortBacking(port),
+				Connectable: &vimTypes.VirtualDeviceConnectInfo{
+					StartConnected:    true,
+					AllowGuestControl: true,
+					Connected:         true,
+				},
+			},
+			YieldOnPoll: true,
+		})
+		deviceKey--
+	}
+
+	return devices
+}
+
+// IsNetworkSerialPort returns true if the device is a serial port that is backed by a network
+// connection, which are the only serial ports that are managed from the VM's spec.
+func IsNetworkSerialPort(device vimTypes.BaseVirtualDevice) bool {
+	serialPort, ok := device.(*vimTypes.VirtualSerialPort)
+	if !ok {
+		return false
+	}
+
+	_, ok = serialPort.Backing.(*vimTypes.VirtualSerialPortURIBackingInfo)
+	return ok
+}
+
+// SerialPortMatch returns true if the device is a network serial port with the backing of the
+// serial port.
+func SerialPortMatch(device vimTypes.BaseVirtualDevice, port vmopv1.VirtualMachineSerialPortSpec) bool {
+	if !IsNetworkSerialPort(device) {
+		return false
+	}
+
+	backing := device.GetVirtualDevice().Backing.(*vimTypes.VirtualSerialPortURIBackingInfo)
+	expected := serialPortBacking(port)
+
+	return backing.ServiceURI == expected.ServiceURI &&
+		backing.Direction == expected.Direction &&
+		backing.ProxyURI == expected.ProxyURI
+}
+
+// GetSerialPortStatus returns the status of the serial ports that have been added to the VM.
+func GetSerialPortStatus(
+	ports []vmopv1.VirtualMachineSerialPortSpec,
+	devices object.VirtualDeviceList) []vmopv1.VirtualMachineSerialPortStatus {
+
+	var status []vmopv1.VirtualMachineSerialPortStatus
+	for _, port := range ports {
+		for _, device := range devices {
+			if !SerialPortMatch(device, port) {
+				continue
+			}
+
+			vd := device.GetVirtualDevice()
+			status = append(status, vmopv1.VirtualMachineSerialPortStatus{
+				Name:      port.Name,
+				Key:       vd.Key,
+				Connected: vd.Connectable != nil && vd.Connectable.Connected,
+			})
+			break
+		}
+	}
+
+	return status
+}
+
+// GetSerialPortOption returns the cluster's option for the serial ports of the VM's guest OS at
+// the VM's hardware version, which describes whether the serial ports may be hot-plugged, or nil
+// if the cluster does not have the option.
+func GetSerialPortOption(
+	ctx context.Context,
+	cluster *object.ClusterComputeResource,
+	config *vimTypes.VirtualMachineConfigInfo) (*vimTypes.VirtualSerialPortOption, error) {
+
+	configOption, err := getConfigOption(ctx, cluster, config)
+	if err != nil || configOption == nil {
+		return nil, err
+	}
+
+	for _, option := range configOption.HardwareOptions.VirtualDeviceOption {
+		if o, ok := option.(*vimTypes.VirtualSerialPortOption); ok {
+			return o, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func serialPortBacking(port vmopv1.VirtualMachineSerialPortSpec) *vimTypes.VirtualSerialPortURIBackingInfo {
+	direction := port.Direction
+	if direction == "" {
+		direction = vmopv1.VirtualMachineSerialPortDirectionClient
+	}
+
+	return &vimTypes.VirtualSerialPortURIBackingInfo{
+		VirtualDeviceURIBackingInfo: vimTypes.VirtualDeviceURIBackingInfo{
+			ServiceURI: port.URI,
+			Direction:  string(direction),
+			ProxyURI:   port.ProxyURI,
+		},
+	}
+}
@@ -0,0 +1,643 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	goctx "context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
+)
+
+const (
+	labelTagCategoryCardinality = "SINGLE"
+	labelTagCategoryDescription = "Kubernetes label synced by the vSphere Virtual Machine service"
+	labelTagDescription         = "Kubernetes label value synced by the vSphere Virtual Machine service"
+)
+
+// tagStatusResyncPeriod is how long the VM's cached tag status is used before the tags attached to
+// the VM are fetched again, so tags that are attached or detached outside of the VM's labels are
+// eventually reflected in its status.
+const tagStatusResyncPeriod = 30 * time.Minute
+
+// GetTagStatus returns the vSphere tags that are attached to the VM, sorted by category and name.
+func GetTagStatus(
+	ctx goctx.Context,
+	restClient *rest.Client,
+	vmRef vimTypes.ManagedObjectReference) ([]vmopv1.VirtualMachineTagStatus, error) {
+
+	m := tags.NewManager(restClient)
+
+	attachedTags, err := m.GetAttachedTags(ctx, vmRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the vSphere tags attached to VM: %w", err)
+	}
+
+	if len(attachedTags) == 0 {
+		return nil, nil
+	}
+
+	categories, err := m.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vSphere tag categories: %w", err)
+	}
+
+	categoryNames := map[string]string{}
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	return toTagStatus(attachedTags, categoryNames), nil
+}
+
+// GetCachedTagStatus returns the VM's tag status like GetTagStatus does, but only fetches the
+// tags attached to the VM when the VM's synced labels have changed, the VM's tags were changed by
+// SyncLabelTags, or the cached status is older than tagStatusResyncPeriod. Otherwise the status
+// is returned from the cache.
+func GetCachedTagStatus(
+	vmCtx context.VirtualMachineContextA2,
+	restClient *rest.Client,
+	tagCache *TagCache,
+	vmRef vimTypes.ManagedObjectReference) ([]vmopv1.VirtualMachineTagStatus, error) {
+
+	labelCategories := lib.GetLabelTagCategories()
+	labels := syncedLabelsKey(getSyncedLabels(vmCtx.VM.Labels, vmCtx.VM.Annotations, labelCategories), labelCategories)
+
+	if status, ok := tagCache.cachedTagStatus(vmRef, labels); ok {
+		return status, nil
+	}
+
+	m := tags.NewManager(restClient)
+
+	attachedTags, err := m.GetAttachedTags(vmCtx, vmRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the vSphere tags attached to VM: %w", err)
+	}
+
+	categoryNames := tagCache.categoryNames()
+	for _, tag := range attachedTags {
+		if _, ok := categoryNames[tag.CategoryID]; ok {
+			continue
+		}
+
+		// Only get the categories when a tag is in a category that is not cached yet.
+		if err := tagCache.fetchCategoryIDs(vmCtx, m); err != nil {
+			return nil, err
+		}
+		categoryNames = tagCache.categoryNames()
+		break
+	}
+
+	status := toTagStatus(attachedTags, categoryNames)
+	tagCache.setTagStatus(vmRef, labels, status)
+
+	return status, nil
+}
+
+func toTagStatus(
+	attachedTags []tags.Tag,
+	categoryNames map[string]string) []vmopv1.VirtualMachineTagStatus {
+
+	if len(attachedTags) == 0 {
+		return nil
+	}
+
+	tagStatus := make([]vmopv1.VirtualMachineTagStatus, 0, len(attachedTags))
+	for _, tag := range attachedTags {
+		tagStatus = append(tagStatus, vmopv1.VirtualMachineTagStatus{
+			Category: categoryNames[tag.CategoryID],
+			Name:     tag.Name,
+		})
+	}
+
+	sort.Slice(tagStatus, func(i, j int) bool {
+		if tagStatus[i].Category != tagStatus[j].Category {
+			return tagStatus[i].Category < tagStatus[j].Category
+		}
+		return tagStatus[i].Name < tagStatus[j].Name
+	})
+
+	return tagStatus
+}
+
+// getSyncedLabels returns the value of each of the labels with a configured tag category that is
+// synced as a tag. When the VM's SyncLabelsAsTagsAnnotation is set, the labels it does not list
+// have an empty value so their tags are detached.
+func getSyncedLabels(labels, annotations, labelCategories map[string]string) map[string]string {
+	selected, hasAnnotation := annotations[constants.SyncLabelsAsTagsAnnotation]
+
+	selectedKeys := map[string]struct{}{}
+	for _, key := range strings.Split(selected, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			selectedKeys[key] = struct{}{}
+		}
+	}
+
+	synced := make(map[string]string, len(labelCategories))
+	for key := range labelCategories {
+		if _, ok := selectedKeys[key]; ok || !hasAnnotation {
+			synced[key] = labels[key]
+		} else {
+			synced[key] = ""
+		}
+	}
+
+	return synced
+}
+
+// syncedLabelsKey returns the VM's labels that are synced as tags in a form that can be compared.
+func syncedLabelsKey(labels, labelCategories map[string]string) string {
+	keys := make([]string, 0, len(labelCategories))
+	for key := range labelCategories {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(key + "=" + labelCategories[key] + ":" + labels[key] + ",")
+	}
+	return sb.String()
+}
+
+// TagCache caches the IDs of the vSphere tag categories and tags that labels are synced to, so
+// they are only looked up the first time a label value is synced. Categories and tags are
+// vCenter-global, so a single cache is shared by all of the provider's VMs. The cache also has
+// the tag status of each VM.
+//
+// The mutex only guards the maps, so the syncs of different VMs do not wait on each other's vCenter
+// calls. The lookup and creation of the same category or tag are serialized by a per-key lock so
+// it is only created once.
+type TagCache struct {
+	mu          sync.Mutex
+	keyLocks    map[string]*sync.Mutex
+	categoryIDs map[string]string
+	tagIDs      map[string]string
+	statuses    map[string]tagStatusEntry
+}
+
+type tagStatusEntry struct {
+	labels  string
+	status  []vmopv1.VirtualMachineTagStatus
+	fetched time.Time
+}
+
+// NewTagCache returns an empty TagCache.
+func NewTagCache() *TagCache {
+	return &TagCache{
+		keyLocks:    map[string]*sync.Mutex{},
+		categoryIDs: map[string]string{},
+		tagIDs:      map[string]string{},
+		statuses:    map[string]tagStatusEntry{},
+	}
+}
+
+// reset drops the cached IDs after a failure since a category or tag may have been deleted.
+func (c *TagCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.categoryIDs = map[string]string{}
+	c.tagIDs = map[string]string{}
+	c.statuses = map[string]tagStatusEntry{}
+}
+
+// lockKey locks the key's lock, and returns the function that unlocks it.
+func (c *TagCache) lockKey(key string) func() {
+	c.mu.Lock()
+	l, ok := c.keyLocks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.keyLocks[key] = l
+	}
+	c.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+func (c *TagCache) cachedTagStatus(
+	vmRef vimTypes.ManagedObjectReference,
+	labels string) ([]vmopv1.VirtualMachineTagStatus, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.statuses[vmRef.Value]; ok &&
+		entry.labels == labels && time.Since(entry.fetched) < tagStatusResyncPeriod {
+		return entry.status, true
+	}
+	return nil, false
+}
+
+func (c *TagCache) setTagStatus(
+	vmRef vimTypes.ManagedObjectReference,
+	labels string,
+	status []vmopv1.VirtualMachineTagStatus) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.statuses[vmRef.Value] = tagStatusEntry{
+		labels:  labels,
+		status:  status,
+		fetched: time.Now(),
+	}
+}
+
+// invalidateTagStatus drops the VM's cached tag status after its tags were changed.
+func (c *TagCache) invalidateTagStatus(vmRef vimTypes.ManagedObjectReference) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.statuses, vmRef.Value)
+}
+
+// categoryNames returns the names of the cached categories by their IDs.
+func (c *TagCache) categoryNames() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make(map[string]string, len(c.categoryIDs))
+	for name, id := range c.categoryIDs {
+		names[id] = name
+	}
+	return names
+}
+
+func (c *TagCache) cachedCategoryID(category string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, ok := c.categoryIDs[category]
+	return id, ok
+}
+
+func (c *TagCache) setCategoryID(category, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.categoryIDs[category] = id
+}
+
+func (c *TagCache) cachedTagID(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, ok := c.tagIDs[key]
+	return id, ok
+}
+
+func (c *TagCache) setTagID(key, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tagIDs[key] = id
+}
+
+// fetchCategoryIDs caches the IDs of all of the categories.
+func (c *TagCache) fetchCategoryIDs(ctx goctx.Context, m *tags.Manager) error {
+	categories, err := m.GetCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get vSphere tag categories: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cat := range categories {
+		c.categoryIDs[cat.Name] = cat.ID
+	}
+	return nil
+}
+
+// findCategoryID returns the ID of the category, or an empty string if the category does not exist.
+func (c *TagCache) findCategoryID(
+	ctx goctx.Context,
+	m *tags.Manager,
+	category string) (string, error) {
+
+	if id, ok := c.cachedCategoryID(category); ok {
+		return id, nil
+	}
+
+	unlock := c.lockKey("category/" + category)
+	defer unlock()
+
+	return c.findCategoryIDKeyLocked(ctx, m, category)
+}
+
+// getCategoryID returns the ID of the category, creating the category if it does not exist.
+func (c *TagCache) getCategoryID(
+	ctx goctx.Context,
+	m *tags.Manager,
+	category, associableType string) (string, error) {
+
+	if id, ok := c.cachedCategoryID(category); ok {
+		return id, nil
+	}
+
+	unlock := c.lockKey("category/" + category)
+	defer unlock()
+
+	if id, err := c.findCategoryIDKeyLocked(ctx, m, category); err != nil || id != "" {
+		return id, err
+	}
+
+	id, err := m.CreateCategory(ctx, &tags.Category{
+		Name:            category,
+		Description:     labelTagCategoryDescription,
+		Cardinality:     labelTagCategoryCardinality,
+		AssociableTypes: []string{associableType},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create vSphere tag category %q: %w", category, err)
+	}
+	c.setCategoryID(category, id)
+
+	return id, nil
+}
+
+// findCategoryIDKeyLocked is findCategoryID with the category's key lock held, so the categories
+// are not fetched again when another sync already cached the category's ID.
+func (c *TagCache) findCategoryIDKeyLocked(
+	ctx goctx.Context,
+	m *tags.Manager,
+	category string) (string, error) {
+
+	if id, ok := c.cachedCategoryID(category); ok {
+		return id, nil
+	}
+
+	if err := c.fetchCategoryIDs(ctx, m); err != nil {
+		return "", err
+	}
+
+	id, _ := c.cachedCategoryID(category)
+	return id, nil
+}
+
+// findTagID returns the ID of the tag in the category, or an empty string if the tag does not exist.
+func (c *TagCache) findTagID(
+	ctx goctx.Context,
+	m *tags.Manager,
+	categoryID, category, name string) (string, error) {
+
+	key := categoryID + "/" + name
+	if id, ok := c.cachedTagID(key); ok {
+		return id, nil
+	}
+
+	unlock := c.lockKey("tag/" + key)
+	defer unlock()
+
+	return c.findTagIDKeyLocked(ctx, m, categoryID, category, name)
+}
+
+// getTagID returns the ID of the tag in the category, creating the tag if it does not exist.
+func (c *TagCache) getTagID(
+	ctx goctx.Context,
+	m *tags.Manager,
+	categoryID, category, name string) (string, error) {
+
+	key := categoryID + "/" + name
+	if id, ok := c.cachedTagID(key); ok {
+		return id, nil
+	}
+
+	unlock := c.lockKey("tag/" + key)
+	defer unlock()
+
+	if id, err := c.findTagIDKeyLocked(ctx, m, categoryID, category, name); err != nil || id != "" {
+		return id, err
+	}
+
+	id, err := m.CreateTag(ctx, &tags.Tag{
+		Name:        name,
+		Description: labelTagDescription,
+		CategoryID:  categoryID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create vSphere tag %q of category %q: %w", name, category, err)
+	}
+	c.setTagID(key, id)
+
+	return id, nil
+}
+
+// findTagIDKeyLocked is findTagID with the tag's key lock held.
+func (c *TagCache) findTagIDKeyLocked(
+	ctx goctx.Context,
+	m *tags.Manager,
+	categoryID, category, name string) (string, error) {
+
+	key := categoryID + "/" + name
+	if id, ok := c.cachedTagID(key); ok {
+		return id, nil
+	}
+
+	categoryTags, err := m.GetTagsForCategory(ctx, categoryID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get vSphere tags of category %q: %w", category, err)
+	}
+	for _, tag := range categoryTags {
+		c.setTagID(categoryID+"/"+tag.Name, tag.ID)
+	}
+
+	id, _ := c.cachedTagID(key)
+	return id, nil
+}
+
+// SyncLabelTags attaches to the vSphere VM the tags for the VM's labels that are configured by
+// lib.GetLabelTagCategories, and detaches the tags of those labels that were removed or changed.
+// Only labels with an admin-configured category are synced, because categories are shared by
+// everything in vCenter, and the VM's SyncLabelsAsTagsAnnotation can narrow them further. The
+// tag of a label is in the label's category, and is named for the label's value. The category and
+// tag are created if they do not exist. A label with an empty value does not have a tag.
+func SyncLabelTags(
+	vmCtx context.VirtualMachineContextA2,
+	restClient *rest.Client,
+	tagCache *TagCache,
+	vmRef vimTypes.ManagedObjectReference) error {
+
+	labelCategories := lib.GetLabelTagCategories()
+	if len(labelCategories) == 0 {
+		return nil
+	}
+
+	if err := syncLabelTags(vmCtx, tags.NewManager(restClient), tagCache, vmRef, labelCategories, nil); err != nil {
+		tagCache.reset()
+		return err
+	}
+
+	return nil
+}
+
+// LabelTagChanges are the tags that SyncLabelTags would attach to and detach from the VM.
+type LabelTagChanges struct {
+	Attach []vmopv1.VirtualMachineTagStatus
+	Detach []vmopv1.VirtualMachineTagStatus
+}
+
+// PlanLabelTags returns the tags that SyncLabelTags would attach to and detach from the VM. The
+// categories and tags that do not exist are not created, and the VM's tags are not changed.
+func PlanLabelTags(
+	vmCtx context.VirtualMachineContextA2,
+	restClient *rest.Client,
+	tagCache *TagCache,
+	vmRef vimTypes.ManagedObjectReference) (LabelTagChanges, error) {
+
+	var changes LabelTagChanges
+
+	labelCategories := lib.GetLabelTagCategories()
+	if len(labelCategories) == 0 {
+		return changes, nil
+	}
+
+	err := syncLabelTags(vmCtx, tags.NewManager(restClient), tagCache, vmRef, labelCategories, &changes)
+	return changes, err
+}
+
+// syncLabelTags syncs the VM's labels to tags, or only records the changes it would make in the
+// changes when it is not nil.
+func syncLabelTags(
+	vmCtx context.VirtualMachineContextA2,
+	m *tags.Manager,
+	tagCache *TagCache,
+	vmRef vimTypes.ManagedObjectReference,
+	labelCategories map[string]string,
+	changes *LabelTagChanges) error {
+
+	labels := getSyncedLabels(vmCtx.VM.Labels, vmCtx.VM.Annotations, labelCategories)
+
+	labelKeys := make([]string, 0, len(labelCategories))
+	for key := range labelCategories {
+		labelKeys = append(labelKeys, key)
+	}
+	sort.Strings(labelKeys)
+
+	attachedTags, err := m.GetAttachedTags(vmCtx, vmRef)
+	if err != nil {
+		return fmt.Errorf("failed to get the vSphere tags attached to VM: %w", err)
+	}
+
+	attachedTagsByCategory := map[string][]tags.Tag{}
+	for _, tag := range attachedTags {
+		attachedTagsByCategory[tag.CategoryID] = append(attachedTagsByCategory[tag.CategoryID], tag)
+	}
+
+	for _, key := range labelKeys {
+		value := labels[key]
+		category := labelCategories[key]
+
+		if value == "" {
+			if len(attachedTags) == 0 {
+				continue
+			}
+			categoryID, err := tagCache.findCategoryID(vmCtx, m, category)
+			if err != nil {
+				return err
+			}
+			if categoryID != "" {
+				if err := detachTags(vmCtx, m, tagCache, vmRef, category, attachedTagsByCategory[categoryID], "", changes); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		var categoryID, tagID string
+		if changes != nil {
+			// The category and tag would be created, so a VM cannot have a tag of them yet.
+			if categoryID, err = tagCache.findCategoryID(vmCtx, m, category); err != nil {
+				return err
+			}
+			if categoryID != "" {
+				if tagID, err = tagCache.findTagID(vmCtx, m, categoryID, category, value); err != nil {
+					return err
+				}
+			}
+		} else {
+			if categoryID, err = tagCache.getCategoryID(vmCtx, m, category, vmRef.Type); err != nil {
+				return err
+			}
+			if tagID, err = tagCache.getTagID(vmCtx, m, categoryID, category, value); err != nil {
+				return err
+			}
+		}
+
+		if err := detachTags(vmCtx, m, tagCache, vmRef, category, attachedTagsByCategory[categoryID], tagID, changes); err != nil {
+			return err
+		}
+
+		if tagID != "" && hasTag(attachedTagsByCategory[categoryID], tagID) {
+			continue
+		}
+
+		if changes != nil {
+			changes.Attach = append(changes.Attach, vmopv1.VirtualMachineTagStatus{Category: category, Name: value})
+			continue
+		}
+
+		vmCtx.Logger.Info("Attaching vSphere tag for label", "label", key, "category", category, "tag", value)
+		if err := m.AttachTag(vmCtx, tagID, vmRef); err != nil {
+			return fmt.Errorf("failed to attach vSphere tag %q of category %q to VM: %w", value, category, err)
+		}
+		tagCache.invalidateTagStatus(vmRef)
+	}
+
+	return nil
+}
+
+// detachTags detaches the tags of the category, except for the tag with the excluded ID, from the
+// VM, or only records them in the changes when it is not nil.
+func detachTags(
+	vmCtx context.VirtualMachineContextA2,
+	m *tags.Manager,
+	tagCache *TagCache,
+	vmRef vimTypes.ManagedObjectReference,
+	category string,
+	attachedTags []tags.Tag,
+	excludeTagID string,
+	changes *LabelTagChanges) error {
+
+	for _, tag := range attachedTags {
+		if tag.ID == excludeTagID {
+			continue
+		}
+
+		if changes != nil {
+			changes.Detach = append(changes.Detach, vmopv1.VirtualMachineTagStatus{Category: category, Name: tag.Name})
+			continue
+		}
+
+		vmCtx.Logger.Info("Detaching vSphere tag for label", "tag", tag.Name, "category", category)
+		if err := m.DetachTag(vmCtx, tag.ID, vmRef); err != nil {
+			return fmt.Errorf("failed to detach vSphere tag %q from VM: %w", tag.Name, err)
+		}
+		tagCache.invalidateTagStatus(vmRef)
+	}
+
+	return nil
+}
+
+func hasTag(attachedTags []tags.Tag, tagID string) bool {
+	for _, tag := range attachedTags {
+		if tag.ID == tagID {
+			return true
+		}
+	}
+	return false
+}
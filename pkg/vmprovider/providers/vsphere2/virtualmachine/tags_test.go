@@ -0,0 +1,299 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/tags"
+	vimTypes "github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func tagsTests() {
+
+	var (
+		ctx      *builder.TestContextForVCSim
+		vcVM     *object.VirtualMachine
+		vmCtx    context.VirtualMachineContextA2
+		m        *tags.Manager
+		tagCache *virtualmachine.TagCache
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true})
+		m = tags.NewManager(ctx.RestClient)
+
+		var err error
+		vcVM, err = ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		Expect(err).ToNot(HaveOccurred())
+
+		vmCtx = context.VirtualMachineContextA2{
+			Context: ctx,
+			Logger:  suite.GetLogger().WithValues("vmName", vcVM.Name()),
+			VM:      builder.DummyVirtualMachineA2(),
+		}
+		vmCtx.VM.Labels = map[string]string{
+			"backup-policy": "daily",
+			"dr-tier":       "gold",
+			"not-synced":    "value",
+		}
+		tagCache = virtualmachine.NewTagCache()
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	getAttachedTags := func() map[string]string {
+		attached, err := m.GetAttachedTags(ctx, vcVM.Reference())
+		ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+		out := map[string]string{}
+		for _, tag := range attached {
+			category, err := m.GetCategory(ctx, tag.CategoryID)
+			ExpectWithOffset(1, err).ToNot(HaveOccurred())
+			out[category.Name] = tag.Name
+		}
+		return out
+	}
+
+	Context("with the configured label tag categories", func() {
+
+		BeforeEach(func() {
+			Expect(os.Setenv(lib.LabelTagCategoriesEnv, "backup-policy,dr-tier=DisasterRecoveryTier")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv(lib.LabelTagCategoriesEnv)).To(Succeed())
+		})
+
+		It("syncs the configured labels to their categories", func() {
+			Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+			Expect(getAttachedTags()).To(Equal(map[string]string{
+				"backup-policy":        "daily",
+				"DisasterRecoveryTier": "gold",
+			}))
+
+			By("syncing again is a no-op", func() {
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				Expect(getAttachedTags()).To(HaveLen(2))
+
+				categories, err := m.GetCategories(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(categories).To(HaveLen(2))
+			})
+
+			By("replaces the tag when the label's value changes", func() {
+				vmCtx.VM.Labels["dr-tier"] = "silver"
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				Expect(getAttachedTags()).To(Equal(map[string]string{
+					"backup-policy":        "daily",
+					"DisasterRecoveryTier": "silver",
+				}))
+			})
+
+			By("detaches the tag when the label is removed", func() {
+				delete(vmCtx.VM.Labels, "backup-policy")
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				Expect(getAttachedTags()).To(Equal(map[string]string{
+					"DisasterRecoveryTier": "silver",
+				}))
+			})
+
+			By("attaches the tag when the label is added back", func() {
+				vmCtx.VM.Labels["backup-policy"] = "weekly"
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				Expect(getAttachedTags()).To(Equal(map[string]string{
+					"backup-policy":        "weekly",
+					"DisasterRecoveryTier": "silver",
+				}))
+			})
+		})
+
+		It("only syncs the configured labels listed by the VM's annotation", func() {
+			vmCtx.VM.Annotations = map[string]string{
+				constants.SyncLabelsAsTagsAnnotation: "dr-tier, not-synced",
+			}
+			Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+			Expect(getAttachedTags()).To(Equal(map[string]string{
+				"DisasterRecoveryTier": "gold",
+			}))
+
+			By("detaches the tag when the label is removed from the annotation", func() {
+				vmCtx.VM.Annotations[constants.SyncLabelsAsTagsAnnotation] = "backup-policy"
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				Expect(getAttachedTags()).To(Equal(map[string]string{
+					"backup-policy": "daily",
+				}))
+			})
+		})
+
+		It("syncs the labels of VMs concurrently", func() {
+			otherVM, err := ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM1")
+			Expect(err).ToNot(HaveOccurred())
+			vmRefs := []vimTypes.ManagedObjectReference{vcVM.Reference(), otherVM.Reference()}
+
+			errs := make(chan error, len(vmRefs))
+			for _, vmRef := range vmRefs {
+				go func(vmRef vimTypes.ManagedObjectReference) {
+					errs <- virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vmRef)
+				}(vmRef)
+			}
+			for range vmRefs {
+				Expect(<-errs).ToNot(HaveOccurred())
+			}
+
+			By("creates each category and tag once", func() {
+				categories, err := m.GetCategories(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(categories).To(HaveLen(2))
+
+				for _, category := range categories {
+					categoryTags, err := m.GetTagsForCategory(ctx, category.ID)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(categoryTags).To(HaveLen(1))
+				}
+			})
+
+			for _, vmRef := range vmRefs {
+				attached, err := m.GetAttachedTags(ctx, vmRef)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(attached).To(HaveLen(2))
+			}
+		})
+
+		It("plans the tag changes without making them", func() {
+			changes, err := virtualmachine.PlanLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes.Attach).To(Equal([]vmopv1.VirtualMachineTagStatus{
+				{Category: "backup-policy", Name: "daily"},
+				{Category: "DisasterRecoveryTier", Name: "gold"},
+			}))
+			Expect(changes.Detach).To(BeEmpty())
+			Expect(getAttachedTags()).To(BeEmpty())
+
+			categories, err := m.GetCategories(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(categories).To(BeEmpty())
+
+			By("plans the replacement of a changed label's tag", func() {
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				vmCtx.VM.Labels["dr-tier"] = "silver"
+
+				changes, err := virtualmachine.PlanLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(changes.Attach).To(Equal([]vmopv1.VirtualMachineTagStatus{
+					{Category: "DisasterRecoveryTier", Name: "silver"},
+				}))
+				Expect(changes.Detach).To(Equal([]vmopv1.VirtualMachineTagStatus{
+					{Category: "DisasterRecoveryTier", Name: "gold"},
+				}))
+				Expect(getAttachedTags()).To(HaveKeyWithValue("DisasterRecoveryTier", "gold"))
+			})
+		})
+
+		It("uses the existing category of a label", func() {
+			categoryID, err := m.CreateCategory(ctx, &tags.Category{
+				Name:            "backup-policy",
+				Cardinality:     "SINGLE",
+				AssociableTypes: []string{"VirtualMachine"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+
+			attached, err := m.GetAttachedTags(ctx, vcVM.Reference())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(attached).To(ContainElement(And(
+				HaveField("CategoryID", categoryID),
+				HaveField("Name", "daily"))))
+		})
+
+		It("looks up the tag again when the cached tag was deleted", func() {
+			Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+
+			category, err := m.GetCategory(ctx, "backup-policy")
+			Expect(err).ToNot(HaveOccurred())
+			tag, err := m.GetTagForCategory(ctx, "daily", category.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(m.DeleteTag(ctx, tag)).To(Succeed())
+
+			Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).ToNot(Succeed())
+			Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+			Expect(getAttachedTags()).To(HaveKeyWithValue("backup-policy", "daily"))
+		})
+	})
+
+	It("returns the attached tags", func() {
+		Expect(virtualmachine.GetTagStatus(ctx, ctx.RestClient, vcVM.Reference())).To(BeEmpty())
+
+		ctx.TagObject(vcVM.Reference(), "security", "pci")
+		ctx.TagObject(vcVM.Reference(), "security", "hipaa")
+		ctx.TagObject(vcVM.Reference(), "backup-policy", "daily")
+
+		Expect(virtualmachine.GetTagStatus(ctx, ctx.RestClient, vcVM.Reference())).To(Equal([]vmopv1.VirtualMachineTagStatus{
+			{Category: "backup-policy", Name: "daily"},
+			{Category: "security", Name: "hipaa"},
+			{Category: "security", Name: "pci"},
+		}))
+	})
+
+	Context("GetCachedTagStatus", func() {
+
+		BeforeEach(func() {
+			Expect(os.Setenv(lib.LabelTagCategoriesEnv, "backup-policy")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv(lib.LabelTagCategoriesEnv)).To(Succeed())
+		})
+
+		It("only fetches the tags again when the synced labels or tags change", func() {
+			ctx.TagObject(vcVM.Reference(), "security", "pci")
+			Expect(virtualmachine.GetCachedTagStatus(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Equal([]vmopv1.VirtualMachineTagStatus{
+				{Category: "security", Name: "pci"},
+			}))
+
+			By("returns the cached status when nothing changed", func() {
+				ctx.TagObject(vcVM.Reference(), "security", "hipaa")
+				Expect(virtualmachine.GetCachedTagStatus(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(HaveLen(1))
+			})
+
+			By("fetches the tags after SyncLabelTags attaches a tag", func() {
+				Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+				Expect(virtualmachine.GetCachedTagStatus(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Equal([]vmopv1.VirtualMachineTagStatus{
+					{Category: "backup-policy", Name: "daily"},
+					{Category: "security", Name: "hipaa"},
+					{Category: "security", Name: "pci"},
+				}))
+			})
+
+			By("fetches the tags when a synced label changes", func() {
+				ctx.TagObject(vcVM.Reference(), "security", "sox")
+				vmCtx.VM.Labels["backup-policy"] = "weekly"
+				Expect(virtualmachine.GetCachedTagStatus(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(HaveLen(4))
+			})
+		})
+	})
+
+	It("does nothing when no label tag categories are configured", func() {
+		Expect(virtualmachine.SyncLabelTags(vmCtx, ctx.RestClient, tagCache, vcVM.Reference())).To(Succeed())
+		Expect(getAttachedTags()).To(BeEmpty())
+
+		categories, err := m.GetCategories(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(categories).To(BeEmpty())
+	})
+}
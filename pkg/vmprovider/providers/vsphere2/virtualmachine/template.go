@@ -0,0 +1,55 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package virtualmachine
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+)
+
+// MarkAsTemplate marks the VM as a template. The VM must be powered off.
+func MarkAsTemplate(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine) error {
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), []string{"config.template", "runtime.powerState"}, &o); err != nil {
+		return err
+	}
+
+	if o.Config != nil && o.Config.Template {
+		return nil
+	}
+
+	if o.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+		return fmt.Errorf("VM must be powered off to be marked as a template")
+	}
+
+	vmCtx.Logger.Info("Marking VM as a template")
+	return vcVM.MarkAsTemplate(vmCtx)
+}
+
+// MarkAsVirtualMachine marks the template as a VM again, placing the VM in the ResourcePool.
+func MarkAsVirtualMachine(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	resourcePool *object.ResourcePool) error {
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), []string{"config.template"}, &o); err != nil {
+		return err
+	}
+
+	if o.Config == nil || !o.Config.Template {
+		return nil
+	}
+
+	vmCtx.Logger.Info("Marking template as a VM", "resourcePool", resourcePool.Reference().Value)
+	return vcVM.MarkAsVirtualMachine(vmCtx, *resourcePool, nil)
+}
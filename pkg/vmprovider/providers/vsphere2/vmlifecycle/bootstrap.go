@@ -5,6 +5,7 @@ package vmlifecycle
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/task"
@@ -30,6 +31,10 @@ type BootstrapData struct {
 	Data       map[string]string
 	VAppData   map[string]string
 	VAppExData map[string]map[string]string
+
+	// SysprepSecretData is the data of the Secrets that an inlined Sysprep's passwords and
+	// product ID are from, by Secret name.
+	SysprepSecretData map[string]map[string]string
 }
 
 type TemplateRenderFunc func(string, string) string
@@ -40,8 +45,51 @@ type BootstrapArgs struct {
 	TemplateRenderFn TemplateRenderFunc
 	NetworkResults   network.NetworkInterfaceResults
 	Hostname         string
+	DomainName       string
 	DNSServers       []string
 	SearchSuffixes   []string
+
+	// SkipNetworkCustomization is true when the guest manages its own networking, so the
+	// customization must not contain any network interface configuration.
+	SkipNetworkCustomization bool
+}
+
+// customizationNetworkResults returns the network interfaces to customize the guest's cloud-init
+// network config with. The NetworkResults are still available to the templates when the network
+// customization is skipped.
+func (b *BootstrapArgs) customizationNetworkResults() network.NetworkInterfaceResults {
+	if b.SkipNetworkCustomization {
+		return network.NetworkInterfaceResults{}
+	}
+	return b.NetworkResults
+}
+
+// guestOSCustomizationAdapterMappings returns the GOSC adapter mappings of the network interfaces
+// to customize the guest with. The customization must have a mapping for each of the VM's NICs,
+// so when the network customization is skipped each NIC is mapped to DHCP instead.
+func (b *BootstrapArgs) guestOSCustomizationAdapterMappings() ([]vimTypes.CustomizationAdapterMapping, error) {
+	if !b.SkipNetworkCustomization {
+		return network.GuestOSCustomization(b.NetworkResults)
+	}
+
+	mappings := make([]vimTypes.CustomizationAdapterMapping, 0, len(b.NetworkResults.Results))
+	for _, r := range b.NetworkResults.Results {
+		mappings = append(mappings, vimTypes.CustomizationAdapterMapping{
+			MacAddress: r.MacAddress,
+			Adapter: vimTypes.CustomizationIPSettings{
+				Ip: &vimTypes.CustomizationDhcpIpGenerator{},
+			},
+		})
+	}
+	return mappings, nil
+}
+
+// fqdn returns the guest's fully qualified domain name, or just its host name when there is no domain.
+func (b *BootstrapArgs) fqdn() string {
+	if b.DomainName == "" {
+		return b.Hostname
+	}
+	return b.Hostname + "." + b.DomainName
 }
 
 func DoBootstrap(
@@ -120,20 +168,51 @@ func getBootstrapArgs(
 	bootstrapArgs := BootstrapArgs{
 		BootstrapData:  bootstrapData,
 		NetworkResults: networkResults,
-		Hostname:       vmCtx.VM.Spec.Network.HostName,
 	}
 
-	if bootstrapArgs.Hostname == "" {
+	// The HostName may be a FQDN, in which case the guest's domain name is the rest of it.
+	if hostName := vmCtx.VM.Spec.Network.HostName; hostName != "" {
+		bootstrapArgs.Hostname, bootstrapArgs.DomainName, _ = strings.Cut(hostName, ".")
+	} else {
 		bootstrapArgs.Hostname = vmCtx.VM.Name
 	}
 
-	// If the VM is missing DNS info - that is, it did not specify DNS for the interfaces - populate that
-	// now from the SV global configuration. Note that the VM is probably OK as long as at least one
+	if vmCtx.VM.Annotations[constants.VSphereNetworkCustomizationKey] == constants.VSphereNetworkCustomizationDisable {
+		// The guest manages its own networking so it does not need any of the DNS info either.
+		bootstrapArgs.SkipNetworkCustomization = true
+		return &bootstrapArgs, nil
+	}
+
+	// The VM's nameservers and search domains apply to the interfaces that did not specify their own,
+	// and take precedence over the SV global configuration.
+	if networkSpec := vmCtx.VM.Spec.Network; len(networkSpec.Nameservers) > 0 || len(networkSpec.SearchDomains) > 0 {
+		bootstrapArgs.DNSServers = networkSpec.Nameservers
+		bootstrapArgs.SearchSuffixes = networkSpec.SearchDomains
+
+		for i := range networkResults.Results {
+			r := &networkResults.Results[i]
+
+			if r.DHCP4 || r.DHCP6 {
+				continue
+			}
+
+			if len(r.Nameservers) == 0 {
+				r.Nameservers = networkSpec.Nameservers
+			}
+			if len(r.SearchDomains) == 0 {
+				r.SearchDomains = networkSpec.SearchDomains
+			}
+		}
+	}
+
+	// If the VM is missing DNS info - that is, it did not specify DNS for the VM or its interfaces - populate
+	// that now from the SV global configuration. Note that the VM is probably OK as long as at least one
 	// interface has DNS info, but we would previously set it for every interface so keep doing that
 	// here. Similarly, we didn't populate SearchDomains for non-TKG VMs so we don't here either. This is
-	// all a little nuts & complicated and probably not correct for every situation.
+	// all a little nuts & complicated and probably not correct for every situation. When the VM specified
+	// just one of its nameservers or search domains, the other one still comes from the global configuration.
 	isTKG := hasTKGLabels(vmCtx.VM.Labels)
-	missingDNSInfo := false
+	missingDNSInfo := (len(bootstrapArgs.DNSServers) > 0) != (len(bootstrapArgs.SearchSuffixes) > 0)
 	for _, r := range networkResults.Results {
 		if r.DHCP4 || r.DHCP6 {
 			continue
@@ -153,8 +232,12 @@ func getBootstrapArgs(
 		}
 
 		// GOSC will use these for its global config.
-		bootstrapArgs.DNSServers = nameservers
-		bootstrapArgs.SearchSuffixes = searchSuffixes
+		if len(bootstrapArgs.DNSServers) == 0 {
+			bootstrapArgs.DNSServers = nameservers
+		}
+		if len(bootstrapArgs.SearchSuffixes) == 0 {
+			bootstrapArgs.SearchSuffixes = searchSuffixes
+		}
 
 		if isCloudInit {
 			// Previously we would apply the global DNS config to every interface so do that here too.
@@ -0,0 +1,167 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vmlifecycle
+
+import (
+	goctx "context"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
+)
+
+var _ = Describe("getBootstrapArgs", func() {
+	const vmopNamespace = "vmop-system"
+
+	var (
+		vmCtx          context.VirtualMachineContextA2
+		vm             *vmopv1.VirtualMachine
+		k8sClient      ctrl.Client
+		isCloudInit    bool
+		networkResults network.NetworkInterfaceResults
+
+		bsArgs *BootstrapArgs
+		err    error
+	)
+
+	BeforeEach(func() {
+		Expect(os.Setenv(lib.VmopNamespaceEnv, vmopNamespace)).To(Succeed())
+
+		vm = &vmopv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dummy-vm",
+				Namespace: "dummy-ns",
+			},
+			Spec: vmopv1.VirtualMachineSpec{
+				Network: &vmopv1.VirtualMachineNetworkSpec{},
+			},
+		}
+
+		vmCtx = context.VirtualMachineContextA2{
+			Context: goctx.Background(),
+			Logger:  logr.Discard(),
+			VM:      vm,
+		}
+
+		k8sClient = fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.NetworkConfigMapName,
+				Namespace: vmopNamespace,
+			},
+			Data: map[string]string{
+				config.NameserversKey:    "9.9.9.9",
+				config.SearchSuffixesKey: "global.local",
+			},
+		}).Build()
+
+		isCloudInit = true
+		networkResults = network.NetworkInterfaceResults{
+			Results: []network.NetworkInterfaceResult{
+				{Name: "eth0"},
+				{Name: "eth1", Nameservers: []string{"1.1.1.1"}, SearchDomains: []string{"eth1.local"}},
+				{Name: "eth2", DHCP4: true},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(lib.VmopNamespaceEnv)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		bsArgs, err = getBootstrapArgs(vmCtx, k8sClient, isCloudInit, networkResults, BootstrapData{})
+	})
+
+	Context("VM does not specify DNS", func() {
+		It("uses the global config", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bsArgs.DNSServers).To(Equal([]string{"9.9.9.9"}))
+			Expect(bsArgs.SearchSuffixes).To(Equal([]string{"global.local"}))
+
+			results := bsArgs.NetworkResults.Results
+			Expect(results[0].Nameservers).To(Equal([]string{"9.9.9.9"}))
+			Expect(results[0].SearchDomains).To(BeEmpty())
+			Expect(results[1].Nameservers).To(Equal([]string{"1.1.1.1"}))
+			Expect(results[1].SearchDomains).To(Equal([]string{"eth1.local"}))
+			Expect(results[2].Nameservers).To(BeEmpty())
+		})
+	})
+
+	Context("VM specifies nameservers and search domains", func() {
+		BeforeEach(func() {
+			vm.Spec.Network.Nameservers = []string{"8.8.8.8", "8.8.4.4"}
+			vm.Spec.Network.SearchDomains = []string{"vm.local"}
+		})
+
+		It("uses the VM's DNS instead of the global config", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bsArgs.DNSServers).To(Equal([]string{"8.8.8.8", "8.8.4.4"}))
+			Expect(bsArgs.SearchSuffixes).To(Equal([]string{"vm.local"}))
+
+			results := bsArgs.NetworkResults.Results
+			Expect(results[0].Nameservers).To(Equal([]string{"8.8.8.8", "8.8.4.4"}))
+			Expect(results[0].SearchDomains).To(Equal([]string{"vm.local"}))
+			Expect(results[2].Nameservers).To(BeEmpty())
+			Expect(results[2].SearchDomains).To(BeEmpty())
+
+			By("interface DNS takes precedence", func() {
+				Expect(results[1].Nameservers).To(Equal([]string{"1.1.1.1"}))
+				Expect(results[1].SearchDomains).To(Equal([]string{"eth1.local"}))
+			})
+		})
+
+		When("not CloudInit", func() {
+			BeforeEach(func() {
+				isCloudInit = false
+			})
+
+			It("uses the VM's DNS for the interfaces", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bsArgs.DNSServers).To(Equal([]string{"8.8.8.8", "8.8.4.4"}))
+				Expect(bsArgs.NetworkResults.Results[0].Nameservers).To(Equal([]string{"8.8.8.8", "8.8.4.4"}))
+			})
+		})
+	})
+
+	Context("VM specifies only nameservers", func() {
+		BeforeEach(func() {
+			vm.Spec.Network.Nameservers = []string{"8.8.8.8"}
+		})
+
+		It("uses the global config for the search domains", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bsArgs.DNSServers).To(Equal([]string{"8.8.8.8"}))
+			Expect(bsArgs.SearchSuffixes).To(Equal([]string{"global.local"}))
+			Expect(bsArgs.NetworkResults.Results[0].Nameservers).To(Equal([]string{"8.8.8.8"}))
+		})
+	})
+
+	Context("Network customization is disabled", func() {
+		BeforeEach(func() {
+			vm.Annotations = map[string]string{
+				constants.VSphereNetworkCustomizationKey: constants.VSphereNetworkCustomizationDisable,
+			}
+			vm.Spec.Network.Nameservers = []string{"8.8.8.8"}
+		})
+
+		It("does not include any DNS", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bsArgs.SkipNetworkCustomization).To(BeTrue())
+			Expect(bsArgs.DNSServers).To(BeEmpty())
+		})
+	})
+})
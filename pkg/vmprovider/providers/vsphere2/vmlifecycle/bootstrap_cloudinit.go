@@ -26,23 +26,29 @@ type CloudInitMetadata struct {
 	PublicKeys    string          `yaml:"public-keys,omitempty"`
 }
 
+// CloudInitNetworkConfigMetadata is the Cloud-Init metadata when the network configuration is
+// a network-config v2 document, which is encoded as described by NetworkEncoding.
+type CloudInitNetworkConfigMetadata struct {
+	InstanceID      string `yaml:"instance-id,omitempty"`
+	LocalHostname   string `yaml:"local-hostname,omitempty"`
+	Hostname        string `yaml:"hostname,omitempty"`
+	Network         string `yaml:"network,omitempty"`
+	NetworkEncoding string `yaml:"network.encoding,omitempty"`
+	PublicKeys      string `yaml:"public-keys,omitempty"`
+}
+
 func BootStrapCloudInit(
 	vmCtx context.VirtualMachineContextA2,
 	config *types.VirtualMachineConfigInfo,
 	cloudInitSpec *vmopv1.VirtualMachineBootstrapCloudInitSpec,
 	bsArgs *BootstrapArgs) (*types.VirtualMachineConfigSpec, *types.CustomizationSpec, error) {
 
-	netPlan, err := network.NetPlanCustomization(bsArgs.NetworkResults)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create NetPlan customization: %w", err)
-	}
-
 	sshPublicKeys := bsArgs.BootstrapData.Data["ssh-public-keys"]
 	if len(cloudInitSpec.SSHAuthorizedKeys) > 0 {
 		sshPublicKeys = strings.Join(cloudInitSpec.SSHAuthorizedKeys, "\n")
 	}
 
-	metadata, err := GetCloudInitMetadata(string(vmCtx.VM.UID), bsArgs.Hostname, netPlan, sshPublicKeys)
+	metadata, err := getCloudInitMetadata(vmCtx, cloudInitSpec, bsArgs, sshPublicKeys)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -87,6 +93,32 @@ func BootStrapCloudInit(
 	return configSpec, customSpec, nil
 }
 
+func getCloudInitMetadata(
+	vmCtx context.VirtualMachineContextA2,
+	cloudInitSpec *vmopv1.VirtualMachineBootstrapCloudInitSpec,
+	bsArgs *BootstrapArgs,
+	sshPublicKeys string) (string, error) {
+
+	switch cloudInitSpec.NetworkConfigMode {
+	case vmopv1.CloudInitNetworkConfigModeV2:
+		networkConfig, err := network.RenderNetworkConfigV2(bsArgs.customizationNetworkResults())
+		if err != nil {
+			return "", fmt.Errorf("failed to render network-config: %w", err)
+		}
+
+		return GetCloudInitNetworkConfigMetadata(string(vmCtx.VM.UID), bsArgs.fqdn(), networkConfig, sshPublicKeys)
+	case vmopv1.CloudInitNetworkConfigModeInline, "":
+		fallthrough
+	default:
+		netPlan, err := network.NetPlanCustomization(bsArgs.customizationNetworkResults())
+		if err != nil {
+			return "", fmt.Errorf("failed to create NetPlan customization: %w", err)
+		}
+
+		return GetCloudInitMetadata(string(vmCtx.VM.UID), bsArgs.fqdn(), netPlan, sshPublicKeys)
+	}
+}
+
 func GetCloudInitMetadata(
 	uid string,
 	hostname string,
@@ -109,6 +141,34 @@ func GetCloudInitMetadata(
 	return string(metadataBytes), nil
 }
 
+func GetCloudInitNetworkConfigMetadata(
+	uid string,
+	hostname string,
+	networkConfig []byte,
+	sshPublicKeys string) (string, error) {
+
+	encodedNetworkConfig, err := util.EncodeGzipBase64(string(networkConfig))
+	if err != nil {
+		return "", fmt.Errorf("encoding cloud-init network-config failed: %w", err)
+	}
+
+	metadata := &CloudInitNetworkConfigMetadata{
+		InstanceID:      uid,
+		LocalHostname:   hostname,
+		Hostname:        hostname,
+		Network:         encodedNetworkConfig,
+		NetworkEncoding: "gzip+base64",
+		PublicKeys:      sshPublicKeys,
+	}
+
+	metadataBytes, err := yaml.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("yaml marshalling of cloud-init metadata failed: %w", err)
+	}
+
+	return string(metadataBytes), nil
+}
+
 func GetCloudInitPrepCustSpec(
 	metadata, userdata string) (*types.VirtualMachineConfigSpec, *types.CustomizationSpec, error) {
 
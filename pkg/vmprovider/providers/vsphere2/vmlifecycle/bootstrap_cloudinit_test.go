@@ -181,6 +181,101 @@ var _ = Describe("CloudInit Bootstrap", func() {
 						Expect(data).To(Equal(otherUserData))
 					})
 				})
+
+				Context("With network interfaces", func() {
+					var metadata vmlifecycle.CloudInitMetadata
+
+					BeforeEach(func() {
+						bsArgs.NetworkResults.Results = []network.NetworkInterfaceResult{
+							{
+								Name:       "eth0",
+								MacAddress: "43:AB:B4:1B:7E:87",
+								DHCP4:      true,
+							},
+						}
+					})
+
+					JustBeforeEach(func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(configSpec).ToNot(BeNil())
+
+						extraConfig := util.ExtraConfigToMap(configSpec.ExtraConfig)
+						data, err := util.TryToDecodeBase64Gzip([]byte(extraConfig[constants.CloudInitGuestInfoMetadata]))
+						Expect(err).ToNot(HaveOccurred())
+
+						metadata = vmlifecycle.CloudInitMetadata{}
+						Expect(yaml.Unmarshal([]byte(data), &metadata)).To(Succeed())
+					})
+
+					It("Metadata has the interfaces", func() {
+						Expect(metadata.Network.Ethernets).To(HaveLen(1))
+						Expect(metadata.Network.Ethernets).To(HaveKey("eth0"))
+					})
+
+					Context("When network customization is skipped", func() {
+						BeforeEach(func() {
+							bsArgs.SkipNetworkCustomization = true
+						})
+
+						It("Metadata does not have any interfaces", func() {
+							Expect(metadata.Network.Ethernets).To(BeEmpty())
+						})
+					})
+
+					Context("When the host name has a domain name", func() {
+						BeforeEach(func() {
+							bsArgs.Hostname = "my-vm"
+							bsArgs.DomainName = "domain.local"
+						})
+
+						It("Metadata has the FQDN", func() {
+							Expect(metadata.LocalHostname).To(Equal("my-vm.domain.local"))
+							Expect(metadata.Hostname).To(Equal("my-vm.domain.local"))
+						})
+					})
+				})
+
+				Context("When the network config mode is NetworkConfigV2", func() {
+					var networkConfig network.NetworkConfigV2
+
+					BeforeEach(func() {
+						cloudInitSpec.NetworkConfigMode = vmopv1.CloudInitNetworkConfigModeV2
+						bsArgs.NetworkResults.Results = []network.NetworkInterfaceResult{
+							{
+								Name:       "eth0",
+								MacAddress: "43:AB:B4:1B:7E:87",
+								DHCP4:      true,
+							},
+						}
+					})
+
+					JustBeforeEach(func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(configSpec).ToNot(BeNil())
+
+						extraConfig := util.ExtraConfigToMap(configSpec.ExtraConfig)
+						data, err := util.TryToDecodeBase64Gzip([]byte(extraConfig[constants.CloudInitGuestInfoMetadata]))
+						Expect(err).ToNot(HaveOccurred())
+
+						ncMetadata := vmlifecycle.CloudInitNetworkConfigMetadata{}
+						Expect(yaml.Unmarshal([]byte(data), &ncMetadata)).To(Succeed())
+						Expect(ncMetadata.InstanceID).To(Equal("my-vm-uuid"))
+						Expect(ncMetadata.NetworkEncoding).To(Equal("gzip+base64"))
+
+						data, err = util.TryToDecodeBase64Gzip([]byte(ncMetadata.Network))
+						Expect(err).ToNot(HaveOccurred())
+
+						networkConfig = network.NetworkConfigV2{}
+						Expect(yaml.Unmarshal([]byte(data), &networkConfig)).To(Succeed())
+					})
+
+					It("Metadata has the network-config document", func() {
+						Expect(networkConfig.Network.Version).To(Equal(constants.NetPlanVersion))
+						Expect(networkConfig.Network.Ethernets).To(HaveLen(1))
+						Expect(networkConfig.Network.Ethernets).To(HaveKey("eth0"))
+						Expect(networkConfig.Network.Ethernets["eth0"].Dhcp4).To(BeTrue())
+					})
+				})
 			})
 		})
 	})
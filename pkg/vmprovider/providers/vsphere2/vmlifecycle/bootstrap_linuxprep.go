@@ -10,7 +10,6 @@ import (
 	vimTypes "github.com/vmware/govmomi/vim25/types"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
-	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
 )
 
 func BootStrapLinuxPrep(
@@ -20,7 +19,7 @@ func BootStrapLinuxPrep(
 	vAppConfigSpec *vmopv1.VirtualMachineBootstrapVAppConfigSpec,
 	bsArgs *BootstrapArgs) (*vimTypes.VirtualMachineConfigSpec, *vimTypes.CustomizationSpec, error) {
 
-	nicSettingMap, err := network.GuestOSCustomization(bsArgs.NetworkResults)
+	nicSettingMap, err := bsArgs.guestOSCustomizationAdapterMappings()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create GOSC NIC mappings: %w", err)
 	}
@@ -30,6 +29,7 @@ func BootStrapLinuxPrep(
 			HostName: &vimTypes.CustomizationFixedName{
 				Name: bsArgs.Hostname,
 			},
+			Domain:     bsArgs.DomainName,
 			TimeZone:   linuxPrepSpec.TimeZone,
 			HwClockUTC: vimTypes.NewBool(linuxPrepSpec.HardwareClockIsUTC),
 		},
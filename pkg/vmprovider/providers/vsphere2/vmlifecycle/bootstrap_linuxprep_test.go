@@ -106,6 +106,7 @@ var _ = Describe("LinuxPrep Bootstrap", func() {
 			linuxSpec := custSpec.Identity.(*types.CustomizationLinuxPrep)
 			hostName := linuxSpec.HostName.(*types.CustomizationFixedName).Name
 			Expect(hostName).To(Equal(bsArgs.Hostname))
+			Expect(linuxSpec.Domain).To(BeEmpty())
 			Expect(linuxSpec.TimeZone).To(Equal(linuxPrepSpec.TimeZone))
 			Expect(linuxSpec.HwClockUTC).ToNot(BeNil())
 			Expect(*linuxSpec.HwClockUTC).To(Equal(linuxPrepSpec.HardwareClockIsUTC))
@@ -114,6 +115,37 @@ var _ = Describe("LinuxPrep Bootstrap", func() {
 			Expect(custSpec.NicSettingMap[0].MacAddress).To(Equal(macAddr))
 		})
 
+		Context("when the host name has a domain name", func() {
+			BeforeEach(func() {
+				bsArgs.DomainName = "domain.local"
+			})
+
+			It("should return the domain name in the customization spec", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(custSpec).ToNot(BeNil())
+
+				linuxSpec := custSpec.Identity.(*types.CustomizationLinuxPrep)
+				Expect(linuxSpec.HostName.(*types.CustomizationFixedName).Name).To(Equal(bsArgs.Hostname))
+				Expect(linuxSpec.Domain).To(Equal("domain.local"))
+			})
+		})
+
+		Context("when network customization is skipped", func() {
+			BeforeEach(func() {
+				bsArgs.SkipNetworkCustomization = true
+			})
+
+			It("should return customization spec with a DHCP mapping for each interface", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(custSpec).ToNot(BeNil())
+				Expect(custSpec.NicSettingMap).To(HaveLen(len(bsArgs.NetworkResults.Results)))
+				Expect(custSpec.NicSettingMap[0].MacAddress).To(Equal(macAddr))
+				Expect(custSpec.NicSettingMap[0].Adapter).To(Equal(types.CustomizationIPSettings{
+					Ip: &types.CustomizationDhcpIpGenerator{},
+				}))
+			})
+		})
+
 		Context("when has vAppConfig", func() {
 			const key, value = "fooKey", "fooValue"
 
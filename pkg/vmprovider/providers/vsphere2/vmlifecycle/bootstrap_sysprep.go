@@ -8,10 +8,11 @@ import (
 	"fmt"
 
 	vimTypes "github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/sysprep"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
-	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
 )
 
 func BootstrapSysPrep(
@@ -21,44 +22,55 @@ func BootstrapSysPrep(
 	vAppConfigSpec *vmopv1.VirtualMachineBootstrapVAppConfigSpec,
 	bsArgs *BootstrapArgs) (*vimTypes.VirtualMachineConfigSpec, *vimTypes.CustomizationSpec, error) {
 
-	var data string
-	key := "unattend"
+	var identity vimTypes.BaseCustomizationIdentitySettings
 
-	if sysPrepSpec.RawSysprep != nil {
+	if sysPrepSpec.Sysprep != nil {
 		var err error
 
-		if sysPrepSpec.RawSysprep.Key != "" {
-			key = sysPrepSpec.RawSysprep.Key
+		identity, err = convertSysprep(sysPrepSpec.Sysprep, bsArgs)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		data = bsArgs.BootstrapData.Data[key]
-		if data == "" {
-			return nil, nil, fmt.Errorf("no Sysprep XML data with key %q", key)
-		}
+	} else {
+		var data string
+		key := "unattend"
 
-		// Ensure the data is normalized first to plain-text.
-		data, err = util.TryToDecodeBase64Gzip([]byte(data))
-		if err != nil {
-			return nil, nil, fmt.Errorf("decoding Sysprep unattend XML failed: %w", err)
+		if sysPrepSpec.RawSysprep != nil {
+			var err error
+
+			if sysPrepSpec.RawSysprep.Key != "" {
+				key = sysPrepSpec.RawSysprep.Key
+			}
+
+			data = bsArgs.BootstrapData.Data[key]
+			if data == "" {
+				return nil, nil, fmt.Errorf("no Sysprep XML data with key %q", key)
+			}
+
+			// Ensure the data is normalized first to plain-text.
+			data, err = util.TryToDecodeBase64Gzip([]byte(data))
+			if err != nil {
+				return nil, nil, fmt.Errorf("decoding Sysprep unattend XML failed: %w", err)
+			}
 		}
 
-	} else if sysPrepSpec.Sysprep != nil {
-		return nil, nil, fmt.Errorf("TODO: inlined Sysprep")
-	}
+		if bsArgs.TemplateRenderFn != nil {
+			data = bsArgs.TemplateRenderFn(key, data)
+		}
 
-	if bsArgs.TemplateRenderFn != nil {
-		data = bsArgs.TemplateRenderFn(key, data)
+		identity = &vimTypes.CustomizationSysprepText{
+			Value: data,
+		}
 	}
 
-	nicSettingMap, err := network.GuestOSCustomization(bsArgs.NetworkResults)
+	nicSettingMap, err := bsArgs.guestOSCustomizationAdapterMappings()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create GSOC adapter mappings: %w", err)
 	}
 
 	customSpec := &vimTypes.CustomizationSpec{
-		Identity: &vimTypes.CustomizationSysprepText{
-			Value: data,
-		},
+		Identity: identity,
 		GlobalIPSettings: vimTypes.CustomizationGlobalIPSettings{
 			DnsSuffixList: bsArgs.SearchSuffixes,
 			DnsServerList: bsArgs.DNSServers,
@@ -79,3 +91,99 @@ func BootstrapSysPrep(
 
 	return configSpec, customSpec, nil
 }
+
+// convertSysprep returns the Sysprep customization of the inlined Sysprep. The passwords and
+// product ID are from the Secrets in the bootstrap data, so they are never inlined in the VM's
+// spec. The guest's computer name is the VM's host name.
+func convertSysprep(
+	sp *sysprep.Sysprep,
+	bsArgs *BootstrapArgs) (*vimTypes.CustomizationSysprep, error) {
+
+	password, err := bsArgs.sysprepSecretValue(sp.GUIUnattended.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Sysprep password: %w", err)
+	}
+
+	domainAdminPassword, err := bsArgs.sysprepSecretValue(sp.Identification.DomainAdminPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Sysprep domain admin password: %w", err)
+	}
+
+	productID, err := bsArgs.sysprepSecretValue(sp.UserData.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Sysprep product ID: %w", err)
+	}
+
+	if sp.Identification.JoinDomain != "" && (sp.Identification.DomainAdmin == "" || domainAdminPassword == "") {
+		return nil, fmt.Errorf("joining domain %q requires the domain admin and domain admin password",
+			sp.Identification.JoinDomain)
+	}
+
+	identity := &vimTypes.CustomizationSysprep{
+		GuiUnattended: vimTypes.CustomizationGuiUnattended{
+			TimeZone:       sp.GUIUnattended.TimeZone,
+			AutoLogon:      sp.GUIUnattended.AutoLogon,
+			AutoLogonCount: sp.GUIUnattended.AutoLogonCount,
+		},
+		UserData: vimTypes.CustomizationUserData{
+			FullName:     sp.UserData.FullName,
+			OrgName:      sp.UserData.OrgName,
+			ComputerName: &vimTypes.CustomizationFixedName{Name: bsArgs.Hostname},
+			ProductId:    productID,
+		},
+		Identification: vimTypes.CustomizationIdentification{
+			JoinWorkgroup: sp.Identification.JoinWorkgroup,
+			JoinDomain:    sp.Identification.JoinDomain,
+			DomainAdmin:   sp.Identification.DomainAdmin,
+		},
+	}
+
+	if password != "" {
+		identity.GuiUnattended.Password = &vimTypes.CustomizationPassword{
+			Value:     password,
+			PlainText: true,
+		}
+	}
+
+	if domainAdminPassword != "" {
+		identity.Identification.DomainAdminPassword = &vimTypes.CustomizationPassword{
+			Value:     domainAdminPassword,
+			PlainText: true,
+		}
+	}
+
+	if len(sp.GUIRunOnce.Commands) > 0 {
+		identity.GuiRunOnce = &vimTypes.CustomizationGuiRunOnce{
+			CommandList: sp.GUIRunOnce.Commands,
+		}
+	}
+
+	if lfpd := sp.LicenseFilePrintData; lfpd != nil {
+		identity.LicenseFilePrintData = &vimTypes.CustomizationLicenseFilePrintData{
+			AutoMode: vimTypes.CustomizationLicenseDataMode(lfpd.AutoMode),
+		}
+		if lfpd.AutoUsers != nil {
+			identity.LicenseFilePrintData.AutoUsers = *lfpd.AutoUsers
+		}
+	}
+
+	return identity, nil
+}
+
+// sysprepSecretValue returns the value of the key in the Secret that an inlined Sysprep value is
+// from, or an empty string when the value is not set.
+func (b *BootstrapArgs) sysprepSecretValue(from corev1.SecretKeySelector) (string, error) {
+	if from.Name == "" {
+		return "", nil
+	}
+
+	value, ok := b.BootstrapData.SysprepSecretData[from.Name][from.Key]
+	if !ok {
+		if from.Optional != nil && *from.Optional {
+			return "", nil
+		}
+		return "", fmt.Errorf("no data with key %q in Secret %q", from.Key, from.Name)
+	}
+
+	return value, nil
+}
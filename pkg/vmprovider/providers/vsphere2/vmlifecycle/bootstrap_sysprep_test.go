@@ -99,13 +99,122 @@ var _ = Describe("SysPrep Bootstrap", func() {
 		})
 
 		Context("Inlined Sysprep", func() {
+			const (
+				secretName = "sysprep-secret"
+				password   = "admin-password"
+				domainPwd  = "domain-password"
+				productID  = "product-id"
+			)
+
 			BeforeEach(func() {
-				sysPrepSpec.Sysprep = &sysprep.Sysprep{}
+				bsArgs.Hostname = "win-host"
+				bsArgs.SysprepSecretData = map[string]map[string]string{
+					secretName: {
+						"password":       password,
+						"domainPassword": domainPwd,
+						"productID":      productID,
+					},
+				}
+
+				sysPrepSpec.Sysprep = &sysprep.Sysprep{
+					GUIRunOnce: sysprep.GUIRunOnce{
+						Commands: []string{"cmd.exe /c echo hello"},
+					},
+					GUIUnattended: sysprep.GUIUnattended{
+						AutoLogon:      true,
+						AutoLogonCount: 2,
+						Password: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Key:                  "password",
+						},
+						TimeZone: 4,
+					},
+					Identification: sysprep.Identification{
+						DomainAdmin: "administrator@example.com",
+						DomainAdminPassword: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Key:                  "domainPassword",
+						},
+						JoinDomain: "example.com",
+					},
+					UserData: sysprep.UserData{
+						FullName: "Full Name",
+						OrgName:  "Org Name",
+						ProductID: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Key:                  "productID",
+						},
+					},
+				}
+			})
+
+			It("should return expected customization spec", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(configSpec).To(BeNil())
+
+				Expect(custSpec).ToNot(BeNil())
+				Expect(custSpec.GlobalIPSettings.DnsSuffixList).To(Equal(bsArgs.SearchSuffixes))
+				Expect(custSpec.NicSettingMap).To(HaveLen(len(bsArgs.NetworkResults.Results)))
+				Expect(custSpec.NicSettingMap[0].MacAddress).To(Equal(macAddr))
+
+				identity, ok := custSpec.Identity.(*types.CustomizationSysprep)
+				Expect(ok).To(BeTrue())
+
+				Expect(identity.GuiUnattended.AutoLogon).To(BeTrue())
+				Expect(identity.GuiUnattended.AutoLogonCount).To(BeEquivalentTo(2))
+				Expect(identity.GuiUnattended.TimeZone).To(BeEquivalentTo(4))
+				Expect(identity.GuiUnattended.Password).ToNot(BeNil())
+				Expect(identity.GuiUnattended.Password.Value).To(Equal(password))
+				Expect(identity.GuiUnattended.Password.PlainText).To(BeTrue())
+
+				Expect(identity.Identification.JoinDomain).To(Equal("example.com"))
+				Expect(identity.Identification.DomainAdmin).To(Equal("administrator@example.com"))
+				Expect(identity.Identification.DomainAdminPassword).ToNot(BeNil())
+				Expect(identity.Identification.DomainAdminPassword.Value).To(Equal(domainPwd))
+
+				Expect(identity.UserData.FullName).To(Equal("Full Name"))
+				Expect(identity.UserData.OrgName).To(Equal("Org Name"))
+				Expect(identity.UserData.ProductId).To(Equal(productID))
+				Expect(identity.UserData.ComputerName).To(Equal(&types.CustomizationFixedName{Name: "win-host"}))
+
+				Expect(identity.GuiRunOnce).ToNot(BeNil())
+				Expect(identity.GuiRunOnce.CommandList).To(Equal([]string{"cmd.exe /c echo hello"}))
+				Expect(identity.LicenseFilePrintData).To(BeNil())
 			})
 
-			It("Returns TODO", func() {
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("TODO"))
+			Context("When the Secret key does not exist", func() {
+				BeforeEach(func() {
+					sysPrepSpec.Sysprep.Identification.DomainAdminPassword.Key = "does-not-exist"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring(`no data with key "does-not-exist" in Secret "sysprep-secret"`)))
+				})
+			})
+
+			Context("When joining a domain without the domain admin password", func() {
+				BeforeEach(func() {
+					sysPrepSpec.Sysprep.Identification.DomainAdminPassword = corev1.SecretKeySelector{}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring(`joining domain "example.com" requires the domain admin and domain admin password`)))
+				})
+			})
+
+			Context("When joining a workgroup", func() {
+				BeforeEach(func() {
+					sysPrepSpec.Sysprep.Identification = sysprep.Identification{
+						JoinWorkgroup: "WORKGROUP",
+					}
+				})
+
+				It("should return expected customization spec", func() {
+					Expect(err).ToNot(HaveOccurred())
+					identity := custSpec.Identity.(*types.CustomizationSysprep)
+					Expect(identity.Identification.JoinWorkgroup).To(Equal("WORKGROUP"))
+					Expect(identity.Identification.DomainAdminPassword).To(BeNil())
+				})
 			})
 		})
 
@@ -130,6 +239,22 @@ var _ = Describe("SysPrep Bootstrap", func() {
 				Expect(custSpec.NicSettingMap).To(HaveLen(len(bsArgs.NetworkResults.Results)))
 				Expect(custSpec.NicSettingMap[0].MacAddress).To(Equal(macAddr))
 			})
+
+			Context("When network customization is skipped", func() {
+				BeforeEach(func() {
+					bsArgs.SkipNetworkCustomization = true
+				})
+
+				It("should return customization spec with a DHCP mapping for each interface", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(custSpec).ToNot(BeNil())
+					Expect(custSpec.NicSettingMap).To(HaveLen(len(bsArgs.NetworkResults.Results)))
+					Expect(custSpec.NicSettingMap[0].MacAddress).To(Equal(macAddr))
+					Expect(custSpec.NicSettingMap[0].Adapter).To(Equal(types.CustomizationIPSettings{
+						Ip: &types.CustomizationDhcpIpGenerator{},
+					}))
+				})
+			})
 		})
 
 		Context("when has vAppConfig", func() {
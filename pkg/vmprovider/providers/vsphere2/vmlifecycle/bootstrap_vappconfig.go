@@ -9,6 +9,7 @@ import (
 	vimTypes "github.com/vmware/govmomi/vim25/types"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
 )
 
 func BootstrapVAppConfig(
@@ -68,6 +69,34 @@ func GetOVFVAppConfigForConfigSpec(
 	return GetMergedvAppConfigSpec(vAppData, vAppConfigInfo.Property)
 }
 
+// GetVAppPropertiesConfigSpec returns the vApp VmConfigSpec that sets the VM's vApp properties to the
+// values of the VM's VAppProperties, or nil if the properties already have those values.
+func GetVAppPropertiesConfigSpec(
+	config *vimTypes.VirtualMachineConfigInfo,
+	vAppProperties map[string]common.ValueOrSecretKeySelector,
+	vAppExData map[string]map[string]string) *vimTypes.VmConfigSpec {
+
+	if len(vAppProperties) == 0 || config.VAppConfig == nil {
+		return nil
+	}
+
+	vAppConfigInfo := config.VAppConfig.GetVmConfigInfo()
+	if vAppConfigInfo == nil {
+		return nil
+	}
+
+	vAppData := make(map[string]string, len(vAppProperties))
+	for key, value := range vAppProperties {
+		if value.Value != nil {
+			vAppData[key] = *value.Value
+		} else if from := value.From; from != nil {
+			vAppData[key] = vAppExData[from.Name][from.Key]
+		}
+	}
+
+	return GetMergedvAppConfigSpec(vAppData, vAppConfigInfo.Property)
+}
+
 // GetMergedvAppConfigSpec prepares a vApp VmConfigSpec which will set the provided key/value fields.
 // Only fields marked userConfigurable and pre-existing on the VM (ie. originated from the OVF Image)
 // will be set, and all others will be ignored.
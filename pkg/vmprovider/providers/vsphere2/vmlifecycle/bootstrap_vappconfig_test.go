@@ -203,6 +203,75 @@ var _ = Describe("VAppConfig Bootstrap", func() {
 	})
 })
 
+var _ = Describe("GetVAppPropertiesConfigSpec", func() {
+
+	var (
+		configInfo     *types.VirtualMachineConfigInfo
+		vAppProperties map[string]common.ValueOrSecretKeySelector
+		vAppExData     map[string]map[string]string
+		vmConfigSpec   *types.VmConfigSpec
+	)
+
+	BeforeEach(func() {
+		configInfo = &types.VirtualMachineConfigInfo{
+			VAppConfig: &types.VmConfigInfo{
+				Property: []types.VAppPropertyInfo{
+					{Key: 1, Id: "hostname", Value: "old-hostname", UserConfigurable: pointer.Bool(true)},
+					{Key: 2, Id: "password", Value: "", UserConfigurable: pointer.Bool(true)},
+				},
+			},
+		}
+		vAppProperties = nil
+		vAppExData = map[string]map[string]string{
+			"my-secret": {"password": "secret-password"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		vmConfigSpec = vmlifecycle.GetVAppPropertiesConfigSpec(configInfo, vAppProperties, vAppExData)
+	})
+
+	Context("No vApp properties", func() {
+		It("returns nil", func() {
+			Expect(vmConfigSpec).To(BeNil())
+		})
+	})
+
+	Context("vApp properties with values and from Secrets", func() {
+		BeforeEach(func() {
+			vAppProperties = map[string]common.ValueOrSecretKeySelector{
+				"hostname": {Value: pointer.String("new-hostname")},
+				"password": {
+					From: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+						Key:                  "password",
+					},
+				},
+			}
+		})
+
+		It("returns the properties to set", func() {
+			Expect(vmConfigSpec).ToNot(BeNil())
+			Expect(vmConfigSpec.Property).To(HaveLen(2))
+			Expect(vmConfigSpec.Property[0].Info.Id).To(Equal("hostname"))
+			Expect(vmConfigSpec.Property[0].Info.Value).To(Equal("new-hostname"))
+			Expect(vmConfigSpec.Property[1].Info.Id).To(Equal("password"))
+			Expect(vmConfigSpec.Property[1].Info.Value).To(Equal("secret-password"))
+		})
+
+		When("the VM already has the property values", func() {
+			BeforeEach(func() {
+				configInfo.VAppConfig.GetVmConfigInfo().Property[0].Value = "new-hostname"
+				configInfo.VAppConfig.GetVmConfigInfo().Property[1].Value = "secret-password"
+			})
+
+			It("returns nil", func() {
+				Expect(vmConfigSpec).To(BeNil())
+			})
+		})
+	})
+})
+
 var _ = Describe("GetMergedvAppConfigSpec", func() {
 
 	DescribeTable("returns expected props",
@@ -6,6 +6,7 @@ package vmlifecycle
 import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
@@ -24,17 +25,54 @@ type CreateArgs struct {
 	HostMoID            string
 	StorageProfileID    string
 	DatastoreMoID       string // gce2e only: used only if StorageProfileID is unset
+
+	// BootDiskStorageProfileID, when set, is the ID of the storage policy the VM's boot disk
+	// is placed on instead of StorageProfileID.
+	BootDiskStorageProfileID string
+
+	// CachedImageVMMoIDs, when set, are the MoIDs, keyed by datastore MoID, of the VMs the
+	// content library item was cached to. When the VM is placed on one of the datastores, the
+	// VM is cloned from the datastore's cached image VM instead of deploying the item.
+	CachedImageVMMoIDs map[string]string
+
+	// CachedImageDatastoreMoID is set to the MoID of the datastore whose cached image VM the VM
+	// was cloned from.
+	CachedImageDatastoreMoID string
+
+	// InventoryTemplateMoID, when set, is the MoID of the VM template in the inventory the image
+	// was created from. The VM is cloned from it.
+	InventoryTemplateMoID string
+
+	// TrackTaskFn, when set, is called with the vSphere task that clones the VM once it has been
+	// started. The returned func is called when the task has completed.
+	TrackTaskFn func(task types.ManagedObjectReference) func()
+
+	// TrackDeployFn, when set, is called with the description of the deploy of a content library
+	// item, ex. vcenter.ovf.library_item.deploy, before the deploy is started. The returned func is
+	// called when the deploy has completed. The deploy does not have a vSphere task, so there is
+	// no task to track.
+	TrackDeployFn func(description string) func()
 }
 
 func CreateVirtualMachine(
 	vmCtx context.VirtualMachineContextA2,
 	clClient contentlibrary.Provider,
+	vimClient *vim25.Client,
 	restClient *rest.Client,
 	finder *find.Finder,
 	createArgs *CreateArgs) (*types.ManagedObjectReference, error) {
 
 	if createArgs.UseContentLibrary {
-		return deployFromContentLibrary(vmCtx, clClient, restClient, createArgs)
+		if len(createArgs.CachedImageVMMoIDs) > 0 {
+			if moRef, ok, err := cloneVMFromCachedImage(vmCtx, finder, createArgs); ok {
+				return moRef, err
+			}
+		}
+		return deployFromContentLibrary(vmCtx, clClient, vimClient, restClient, createArgs)
+	}
+
+	if createArgs.InventoryTemplateMoID != "" {
+		return cloneVMFromInventoryTemplate(vmCtx, finder, createArgs)
 	}
 
 	return cloneVMFromInventory(vmCtx, finder, createArgs)
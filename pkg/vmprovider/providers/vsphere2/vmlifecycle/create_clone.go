@@ -5,6 +5,8 @@ package vmlifecycle
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/find"
@@ -13,6 +15,8 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/placement"
 )
 
@@ -31,11 +35,104 @@ func cloneVMFromInventory(
 		return nil, errors.Wrapf(err, "failed to find clone source VM: %s", srcVMName)
 	}
 
+	return cloneVM(vmCtx, srcVM, createArgs)
+}
+
+// cloneVMFromInventoryTemplate creates a new VM by cloning the VM template in the inventory
+// that the image was created from.
+func cloneVMFromInventoryTemplate(
+	vmCtx context.VirtualMachineContextA2,
+	finder *find.Finder,
+	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, error) {
+
+	ref := vimtypes.ManagedObjectReference{Type: "VirtualMachine", Value: createArgs.InventoryTemplateMoID}
+	objRef, err := finder.ObjectReference(vmCtx, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find inventory template: %s", ref.Value)
+	}
+
+	srcVM, ok := objRef.(*object.VirtualMachine)
+	if !ok {
+		return nil, fmt.Errorf("inventory template %s is not a VM but %T", ref.Value, objRef)
+	}
+
+	vmCtx.Logger.Info("Cloning VM from inventory template", "inventoryTemplate", ref.Value)
+
+	return cloneVM(vmCtx, srcVM, createArgs)
+}
+
+// cloneVMFromCachedImage creates a new VM by cloning the VM the image was cached to on the
+// datastore the new VM is placed on, which is faster than deploying the content library item
+// again. False is returned when the image is not cached to the placement datastore.
+func cloneVMFromCachedImage(
+	vmCtx context.VirtualMachineContextA2,
+	finder *find.Finder,
+	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, bool, error) {
+
+	datastores := make([]string, 0, len(createArgs.CachedImageVMMoIDs))
+	for datastoreMoID := range createArgs.CachedImageVMMoIDs {
+		datastores = append(datastores, datastoreMoID)
+	}
+	sort.Strings(datastores)
+
+	// Creating the CloneSpec changes the ConfigSpec's device changes, so restore them before
+	// trying the next cached image or deploying the item instead.
+	deviceChange := createArgs.ConfigSpec.DeviceChange
+
+	for _, datastoreMoID := range datastores {
+		ref := vimtypes.ManagedObjectReference{Type: "VirtualMachine", Value: createArgs.CachedImageVMMoIDs[datastoreMoID]}
+		objRef, err := finder.ObjectReference(vmCtx, ref)
+		if err != nil {
+			vmCtx.Logger.Error(err, "Failed to find cached image VM", "cachedImageVM", ref.Value)
+			continue
+		}
+
+		srcVM, ok := objRef.(*object.VirtualMachine)
+		if !ok {
+			continue
+		}
+
+		cloneSpec, err := createCloneSpec(vmCtx, createArgs, srcVM)
+		if err != nil {
+			createArgs.ConfigSpec.DeviceChange = deviceChange
+			vmCtx.Logger.Error(err, "Failed to create CloneSpec from cached image", "cachedImageVM", ref.Value)
+			continue
+		}
+
+		if cloneSpec.Location.Datastore == nil || cloneSpec.Location.Datastore.Value != datastoreMoID {
+			createArgs.ConfigSpec.DeviceChange = deviceChange
+			continue
+		}
+
+		vmCtx.Logger.Info("Cloning VM from cached image", "cachedImageVM", ref.Value, "datastore", datastoreMoID)
+
+		createArgs.CachedImageDatastoreMoID = datastoreMoID
+		moRef, err := cloneVMWithSpec(vmCtx, srcVM, cloneSpec, createArgs)
+		return moRef, true, err
+	}
+
+	return nil, false, nil
+}
+
+func cloneVM(
+	vmCtx context.VirtualMachineContextA2,
+	srcVM *object.VirtualMachine,
+	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, error) {
+
 	cloneSpec, err := createCloneSpec(vmCtx, createArgs, srcVM)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create CloneSpec")
 	}
 
+	return cloneVMWithSpec(vmCtx, srcVM, cloneSpec, createArgs)
+}
+
+func cloneVMWithSpec(
+	vmCtx context.VirtualMachineContextA2,
+	srcVM *object.VirtualMachine,
+	cloneSpec *vimtypes.VirtualMachineCloneSpec,
+	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, error) {
+
 	// We always set cloneSpec.Location.Folder so use that to get the parent folder object.
 	folder := object.NewFolder(srcVM.Client(), *cloneSpec.Location.Folder)
 
@@ -44,6 +141,10 @@ func cloneVMFromInventory(
 		return nil, err
 	}
 
+	if createArgs.TrackTaskFn != nil {
+		defer createArgs.TrackTaskFn(cloneTask.Reference())()
+	}
+
 	result, err := cloneTask.WaitForResult(vmCtx, nil)
 	if err != nil {
 		return nil, errors.Wrapf(err, "clone VM task failed")
@@ -70,6 +171,11 @@ func createCloneSpec(
 
 	virtualDisks := virtualDevices.SelectByType((*vimtypes.VirtualDisk)(nil))
 
+	if network := vmCtx.VM.Spec.Network; network == nil || !network.Disabled {
+		srcNICs := virtualDevices.SelectByType((*vimtypes.VirtualEthernetCard)(nil))
+		cloneSpec.Config.DeviceChange = cloneVMNetworkDeviceChanges(srcNICs, cloneSpec.Config.DeviceChange)
+	}
+
 	for _, deviceChange := range resizeBootDiskDeviceChange(vmCtx, virtualDisks) {
 		if deviceChange.GetVirtualDeviceConfigSpec().Operation == vimtypes.VirtualDeviceConfigSpecOperationEdit {
 			cloneSpec.Location.DeviceChange = append(cloneSpec.Location.DeviceChange, deviceChange)
@@ -118,9 +224,28 @@ func createCloneSpec(
 	cloneSpec.Location.Datastore = relocateSpec.Datastore
 	cloneSpec.Location.Disk = cloneVMDiskLocators(virtualDisks, createArgs, cloneSpec.Location)
 
+	if createArgs.BootDiskStorageProfileID != "" && len(virtualDisks) > 0 {
+		// Record the boot disk's storage policy so the update does not relocate the boot disk.
+		cloneSpec.Config.ExtraConfig = setExtraConfigValue(cloneSpec.Config.ExtraConfig,
+			constants.BootDiskStorageProfileIDExtraConfigKey, createArgs.BootDiskStorageProfileID)
+	}
+
 	return cloneSpec, nil
 }
 
+// setExtraConfigValue returns the ExtraConfig with the key set to the value. The CloneSpec may be
+// created more than once from the same ConfigSpec, so an existing key is updated in place.
+func setExtraConfigValue(extraConfig []vimtypes.BaseOptionValue, key, value string) []vimtypes.BaseOptionValue {
+	for _, ec := range extraConfig {
+		if ov := ec.GetOptionValue(); ov.Key == key {
+			ov.Value = value
+			return extraConfig
+		}
+	}
+
+	return append(extraConfig, &vimtypes.OptionValue{Key: key, Value: value})
+}
+
 func cloneVMDiskLocators(
 	disks object.VirtualDeviceList,
 	createArgs *CreateArgs,
@@ -128,7 +253,7 @@ func cloneVMDiskLocators(
 
 	diskLocators := make([]vimtypes.VirtualMachineRelocateSpecDiskLocator, 0, len(disks))
 
-	for _, disk := range disks {
+	for idx, disk := range disks {
 		locator := vimtypes.VirtualMachineRelocateSpecDiskLocator{
 			DiskId:    disk.GetVirtualDevice().Key,
 			Datastore: *location.Datastore,
@@ -137,6 +262,13 @@ func cloneVMDiskLocators(
 			DiskMoveType: string(vimtypes.VirtualMachineRelocateDiskMoveOptionsMoveChildMostDiskBacking),
 		}
 
+		// Assume the first virtual disk - if any - is the boot disk.
+		if idx == 0 && createArgs.BootDiskStorageProfileID != "" {
+			locator.Profile = []vimtypes.BaseVirtualMachineProfileSpec{
+				&vimtypes.VirtualMachineDefinedProfileSpec{ProfileId: createArgs.BootDiskStorageProfileID},
+			}
+		}
+
 		if backing, ok := disk.(*vimtypes.VirtualDisk).Backing.(*vimtypes.VirtualDiskFlatVer2BackingInfo); ok {
 			switch createArgs.StorageProvisioning {
 			case string(vimtypes.OvfCreateImportSpecParamsDiskProvisioningTypeThin):
@@ -155,6 +287,71 @@ func cloneVMDiskLocators(
 	return diskLocators
 }
 
+// cloneVMNetworkDeviceChanges returns the device changes with the NICs to add for the VM's network
+// interfaces mapped to the source VM's NICs, so the clone is connected to the VM's networks
+// instead of to both the source VM's networks and the VM's networks. The NICs to add are in the
+// order of the VM's network interfaces, and are mapped in that order to the source VM's NICs in
+// the order of their device keys, which is the order of their labels, ex. "Network adapter 1".
+// While a mapped source NIC is of the same type as its NIC to add, the source NIC is edited to have
+// the backing and identity of its NIC to add, and the NIC to add is dropped. From the first source
+// NIC of a different type on, the source VM's NICs are removed and the NICs to add are added, so the
+// clone's NICs are of the spec'd types and stay in the order of the network interfaces.
+func cloneVMNetworkDeviceChanges(
+	srcNICs object.VirtualDeviceList,
+	deviceChanges []vimtypes.BaseVirtualDeviceConfigSpec) []vimtypes.BaseVirtualDeviceConfigSpec {
+
+	sort.SliceStable(srcNICs, func(i, j int) bool {
+		return srcNICs[i].GetVirtualDevice().Key < srcNICs[j].GetVirtualDevice().Key
+	})
+
+	var mapped []vimtypes.BaseVirtualDeviceConfigSpec
+	nicIdx := 0
+	typesDiffer := false
+
+	for _, deviceChange := range deviceChanges {
+		spec := deviceChange.GetVirtualDeviceConfigSpec()
+		if spec.Operation != vimtypes.VirtualDeviceConfigSpecOperationAdd || !util.IsEthernetCard(spec.Device) {
+			mapped = append(mapped, deviceChange)
+			continue
+		}
+
+		if nicIdx < len(srcNICs) && reflect.TypeOf(srcNICs[nicIdx]) != reflect.TypeOf(spec.Device) {
+			typesDiffer = true
+		}
+		if nicIdx >= len(srcNICs) || typesDiffer {
+			mapped = append(mapped, deviceChange)
+			continue
+		}
+
+		addNIC := spec.Device.(vimtypes.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		srcNIC := srcNICs[nicIdx]
+		srcEthCard := srcNIC.(vimtypes.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		nicIdx++
+
+		srcEthCard.Backing = addNIC.Backing
+		srcEthCard.ExternalId = addNIC.ExternalId
+		srcEthCard.AddressType = addNIC.AddressType
+		srcEthCard.MacAddress = addNIC.MacAddress
+		if addNIC.Connectable != nil {
+			srcEthCard.Connectable = addNIC.Connectable
+		}
+
+		mapped = append(mapped, &vimtypes.VirtualDeviceConfigSpec{
+			Operation: vimtypes.VirtualDeviceConfigSpecOperationEdit,
+			Device:    srcNIC,
+		})
+	}
+
+	for _, srcNIC := range srcNICs[nicIdx:] {
+		mapped = append(mapped, &vimtypes.VirtualDeviceConfigSpec{
+			Operation: vimtypes.VirtualDeviceConfigSpecOperationRemove,
+			Device:    srcNIC,
+		})
+	}
+
+	return mapped
+}
+
 func resizeBootDiskDeviceChange(
 	vmCtx context.VirtualMachineContextA2,
 	virtualDisks object.VirtualDeviceList) []vimtypes.BaseVirtualDeviceConfigSpec {
@@ -6,11 +6,14 @@ package vmlifecycle
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
 
 	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vapi/library"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
 	vimtypes "github.com/vmware/govmomi/vim25/types"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
@@ -20,6 +23,13 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/contentlibrary"
 )
 
+const (
+	// The descriptions of the deploys of the content library items, which are the names of the
+	// content library service operations.
+	ovfDeployDescription  = "vcenter.ovf.library_item.deploy"
+	vmtxDeployDescription = "vcenter.vm_template.library_items.deploy"
+)
+
 func deployOVF(
 	vmCtx context.VirtualMachineContextA2,
 	restClient *rest.Client,
@@ -60,30 +70,211 @@ func deployOVF(
 	}
 
 	vmCtx.Logger.Info("Deploying OVF Library Item", "itemID", item.ID, "itemName", item.Name, "deploy", deploy)
+	defer trackDeploy(createArgs, ovfDeployDescription)()
 
 	return vcenter.NewManager(restClient).DeployLibraryItem(vmCtx, item.ID, deploy)
 }
 
+// trackDeploy calls the TrackDeployFn, when set, for the deploy with the description, and returns
+// the func to call once the deploy has completed.
+func trackDeploy(createArgs *CreateArgs, description string) func() {
+	if createArgs.TrackDeployFn == nil {
+		return func() {}
+	}
+	return createArgs.TrackDeployFn(description)
+}
+
+// markDeployedVM sets the ExtraConfig that marks the deployed VM as created by VM Operator for
+// the VirtualMachine, and with the storage policy its boot disk was placed on, if any.
+func markDeployedVM(
+	vmCtx context.VirtualMachineContextA2,
+	vm *object.VirtualMachine,
+	bootDiskStorageProfileID string) error {
+
+	configSpec := vimtypes.VirtualMachineConfigSpec{
+		ExtraConfig: []vimtypes.BaseOptionValue{
+			&vimtypes.OptionValue{Key: constants.VMNamespacedNameExtraConfigKey, Value: vmCtx.VM.NamespacedName()},
+		},
+	}
+	if bootDiskStorageProfileID != "" {
+		configSpec.ExtraConfig = append(configSpec.ExtraConfig,
+			&vimtypes.OptionValue{Key: constants.BootDiskStorageProfileIDExtraConfigKey, Value: bootDiskStorageProfileID})
+	}
+
+	task, err := vm.Reconfigure(vmCtx, configSpec)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Wait(vmCtx); err != nil {
+		return errors.Wrapf(err, "mark deployed VM task failed")
+	}
+
+	return nil
+}
+
+// RelocateBootDisk places the boot disk of the VM on the storage policy, and records the policy in
+// the VM's ExtraConfig. The deploy of an OVF places all the disks on the deployment's storage
+// policy, so the boot disk is relocated on its datastore to apply its policy. The update of the VM
+// relocates the boot disk again when its ExtraConfig does not have the policy, ex. because the
+// relocate after the deploy failed.
+func RelocateBootDisk(
+	vmCtx context.VirtualMachineContextA2,
+	vm *object.VirtualMachine,
+	storageProfileID string) error {
+
+	virtualDevices, err := vm.Device(vmCtx)
+	if err != nil {
+		return fmt.Errorf("failed to get VM devices: %w", err)
+	}
+
+	// Assume the first virtual disk - if any - is the boot disk.
+	virtualDisks := virtualDevices.SelectByType((*vimtypes.VirtualDisk)(nil))
+	if len(virtualDisks) == 0 {
+		return nil
+	}
+
+	bootDisk := virtualDisks[0].(*vimtypes.VirtualDisk)
+	backing, ok := bootDisk.Backing.(vimtypes.BaseVirtualDeviceFileBackingInfo)
+	if !ok || backing.GetVirtualDeviceFileBackingInfo().Datastore == nil {
+		return fmt.Errorf("boot disk %d does not have a datastore backing", bootDisk.Key)
+	}
+
+	relocateSpec := vimtypes.VirtualMachineRelocateSpec{
+		Disk: []vimtypes.VirtualMachineRelocateSpecDiskLocator{
+			{
+				DiskId:    bootDisk.Key,
+				Datastore: *backing.GetVirtualDeviceFileBackingInfo().Datastore,
+				Profile: []vimtypes.BaseVirtualMachineProfileSpec{
+					&vimtypes.VirtualMachineDefinedProfileSpec{ProfileId: storageProfileID},
+				},
+			},
+		},
+	}
+
+	vmCtx.Logger.Info("Placing boot disk on storage policy", "diskKey", bootDisk.Key, "storageProfileID", storageProfileID)
+
+	task, err := vm.Relocate(vmCtx, relocateSpec, vimtypes.VirtualMachineMovePriorityDefaultPriority)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Wait(vmCtx); err != nil {
+		return errors.Wrapf(err, "relocate boot disk task failed")
+	}
+
+	configSpec := vimtypes.VirtualMachineConfigSpec{
+		ExtraConfig: []vimtypes.BaseOptionValue{
+			&vimtypes.OptionValue{Key: constants.BootDiskStorageProfileIDExtraConfigKey, Value: storageProfileID},
+		},
+	}
+
+	task, err = vm.Reconfigure(vmCtx, configSpec)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Wait(vmCtx); err != nil {
+		return errors.Wrapf(err, "record boot disk storage policy task failed")
+	}
+
+	return nil
+}
+
+// deployVMTX deploys the VM template, and returns whether the boot disk was placed on the boot
+// disk's storage policy by the deploy.
 func deployVMTX(
 	vmCtx context.VirtualMachineContextA2,
 	restClient *rest.Client,
 	item *library.Item,
-	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, error) {
+	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, bool, error) {
+
+	// The deploy of a VM template doesn't take a ConfigSpec, so the VM's hardware is
+	// reconfigured from its class by the update before the VM is powered on.
+
+	diskStorage := &vcenter.DiskStorage{}
+	if createArgs.StorageProfileID != "" {
+		diskStorage.StoragePolicy = &vcenter.StoragePolicy{
+			Policy: createArgs.StorageProfileID,
+			Type:   "USE_SPECIFIED_POLICY",
+		}
+	} else {
+		// Without a storage profile, fall back to the datastore.
+		diskStorage.Datastore = createArgs.DatastoreMoID
+	}
+
+	deploy := vcenter.DeployTemplate{
+		Name: vmCtx.VM.Name,
+		Placement: &vcenter.Placement{
+			ResourcePool: createArgs.ResourcePoolMoID,
+			Folder:       createArgs.FolderMoID,
+			Host:         createArgs.HostMoID,
+		},
+		DiskStorage:   diskStorage,
+		VMHomeStorage: diskStorage,
+	}
 
-	// Not yet supported. This item type needs to be deployed via DeployTemplateLibraryItem(),
-	// which doesn't take a ConfigSpec so it is a heavy lift.
-	// TODO: We should catch this earlier to avoid a bunch of wasted work.
+	m := vcenter.NewManager(restClient)
 
-	_ = vmCtx
-	_ = restClient
-	_ = createArgs
+	var bootDiskPlaced bool
+	if createArgs.BootDiskStorageProfileID != "" {
+		info, err := m.GetLibraryTemplateInfo(vmCtx, item.ID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get VM template info: %w", err)
+		}
+
+		// Assume the first virtual disk - if any - is the boot disk.
+		if bootDiskKey := firstTemplateDiskKey(info.Disks); bootDiskKey != "" {
+			deploy.DiskStorageOverrides = []vcenter.DiskStorageOverride{
+				{
+					Key: bootDiskKey,
+					Value: vcenter.DiskStorage{
+						Datastore: diskStorage.Datastore,
+						StoragePolicy: &vcenter.StoragePolicy{
+							Policy: createArgs.BootDiskStorageProfileID,
+							Type:   "USE_SPECIFIED_POLICY",
+						},
+					},
+				},
+			}
+			bootDiskPlaced = true
+		}
+	}
+
+	vmCtx.Logger.Info("Deploying VMTX Library Item", "itemID", item.ID, "itemName", item.Name, "deploy", deploy)
+	untrackFn := trackDeploy(createArgs, vmtxDeployDescription)
 
-	return nil, fmt.Errorf("creating VM from VMTX content library type is not supported: %s", item.Name)
+	vmRef, err := m.DeployTemplateLibraryItem(vmCtx, item.ID, deploy)
+	untrackFn()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return vmRef, bootDiskPlaced, nil
+}
+
+// firstTemplateDiskKey returns the key of the VM template's disk with the lowest device key.
+func firstTemplateDiskKey(disks []vcenter.Disks) string {
+	var firstKey string
+	var firstDeviceKey int
+
+	for _, disk := range disks {
+		deviceKey, err := strconv.Atoi(disk.Key)
+		if err != nil {
+			continue
+		}
+		if firstKey == "" || deviceKey < firstDeviceKey {
+			firstKey, firstDeviceKey = disk.Key, deviceKey
+		}
+	}
+
+	return firstKey
 }
 
 func deployFromContentLibrary(
 	vmCtx context.VirtualMachineContextA2,
 	clClient contentlibrary.Provider,
+	vimClient *vim25.Client,
 	restClient *rest.Client,
 	createArgs *CreateArgs) (*vimtypes.ManagedObjectReference, error) {
 
@@ -94,12 +285,44 @@ func deployFromContentLibrary(
 		return nil, err
 	}
 
+	var vmRef *vimtypes.ManagedObjectReference
+	var markVM, bootDiskPlaced bool
+
 	switch item.Type {
 	case library.ItemTypeOVF:
-		return deployOVF(vmCtx, restClient, item, createArgs)
+		vmRef, err = deployOVF(vmCtx, restClient, item, createArgs)
+		// When the deployment did not have the ConfigSpec, the VM is marked as created for the
+		// VirtualMachine after the deploy.
+		markVM = !lib.IsVMClassAsConfigFSSDaynDateEnabled() || createArgs.ConfigSpec == nil
 	case library.ItemTypeVMTX:
-		return deployVMTX(vmCtx, restClient, item, createArgs)
+		vmRef, bootDiskPlaced, err = deployVMTX(vmCtx, restClient, item, createArgs)
+		markVM = true
 	default:
 		return nil, errors.Errorf("item %s not a supported type: %s", item.Name, item.Type)
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	vm := object.NewVirtualMachine(vimClient, *vmRef)
+
+	if markVM {
+		var bootDiskStorageProfileID string
+		if bootDiskPlaced {
+			bootDiskStorageProfileID = createArgs.BootDiskStorageProfileID
+		}
+
+		if err := markDeployedVM(vmCtx, vm, bootDiskStorageProfileID); err != nil {
+			return nil, err
+		}
+	}
+
+	if createArgs.BootDiskStorageProfileID != "" && !bootDiskPlaced {
+		if err := RelocateBootDisk(vmCtx, vm, createArgs.BootDiskStorageProfileID); err != nil {
+			return nil, err
+		}
+	}
+
+	return vmRef, nil
 }
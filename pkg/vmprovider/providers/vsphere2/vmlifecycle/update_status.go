@@ -5,12 +5,19 @@ package vmlifecycle
 
 import (
 	goctx "context"
+	"errors"
 	"fmt"
 	"net"
+	"sort"
+	"time"
 
+	"github.com/vmware/govmomi/event"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8serrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,15 +29,68 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
 )
 
 var (
 	// The minimum properties needed to be retrieved in order to populate the Status. Callers may
 	// provide a MO with more. This often saves us a second round trip in the common steady state.
-	vmStatusPropertiesSelector = []string{"config.changeTrackingEnabled", "guest", "summary"}
+	vmStatusPropertiesSelector = []string{"config.changeTrackingEnabled", "config.hardware.device", "guest", "summary"}
+
+	// vmPowerStateAndGuestPropertiesSelector are the properties UpdatePowerStateAndGuestStatus
+	// retrieves, which are few enough to check a VM that is otherwise unchanged.
+	vmPowerStateAndGuestPropertiesSelector = []string{"summary.runtime.powerState", "guest.ipAddress", "guest.toolsRunningStatus"}
+
+	// vmPoweredOnEventTypeIDs are the types of the events posted when a VM is powered on, including
+	// the VmRestartedOnAlternateHostEvent vSphere HA posts when it restarts a VM on another host
+	// after the VM's host failed.
+	vmPoweredOnEventTypeIDs = []string{"VmPoweredOnEvent", "DrsVmPoweredOnEvent", "VmRestartedOnAlternateHostEvent"}
 )
 
+// GetStatusProperties returns the properties needed to populate the Status of each of the VMs,
+// keyed by the VM's reference, with a single property collector query. The returned MOs may be
+// passed to UpdateStatus. A VM that no longer exists is left out of the returned properties: when
+// the query fails because of a missing VM, the properties of each VM are retrieved on its own.
+func GetStatusProperties(
+	ctx goctx.Context,
+	vimClient *vim25.Client,
+	vmRefs []types.ManagedObjectReference) (map[types.ManagedObjectReference]mo.VirtualMachine, error) {
+
+	props := make(map[types.ManagedObjectReference]mo.VirtualMachine, len(vmRefs))
+	if len(vmRefs) == 0 {
+		return props, nil
+	}
+
+	pc := property.DefaultCollector(vimClient)
+
+	var vmMOs []mo.VirtualMachine
+	err := pc.Retrieve(ctx, vmRefs, vmStatusPropertiesSelector, &vmMOs)
+	if err != nil {
+		if !errors.Is(providererrors.Classify(err), providererrors.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get VM properties for status update: %w", err)
+		}
+
+		vmMOs = vmMOs[:0]
+		for _, vmRef := range vmRefs {
+			var vmMO mo.VirtualMachine
+			if err := pc.RetrieveOne(ctx, vmRef, vmStatusPropertiesSelector, &vmMO); err != nil {
+				if errors.Is(providererrors.Classify(err), providererrors.ErrNotFound) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get VM %s properties for status update: %w", vmRef.Value, err)
+			}
+			vmMOs = append(vmMOs, vmMO)
+		}
+	}
+
+	for _, vmMO := range vmMOs {
+		props[vmMO.Self] = vmMO
+	}
+
+	return props, nil
+}
+
 func UpdateStatus(
 	vmCtx context.VirtualMachineContextA2,
 	k8sClient ctrlclient.Client,
@@ -77,11 +137,12 @@ func UpdateStatus(
 	var err error
 	summary := vmMO.Summary
 
-	vm.Status.PowerState = convertPowerState(summary.Runtime.PowerState)
+	vm.Status.PowerState = ConvertPowerState(summary.Runtime.PowerState)
 	vm.Status.UniqueID = vcVM.Reference().Value
 	vm.Status.BiosUUID = summary.Config.Uuid
 	vm.Status.InstanceUUID = summary.Config.InstanceUuid
 	vm.Status.Network = getGuestNetworkStatus(vmMO.Guest)
+	vm.Status.GuestDisks = virtualmachine.GetGuestDiskStatus(vmMO.Guest)
 	vm.Status.HardwareVersion = util.ParseVirtualHardwareVersion(summary.Config.HwVersion)
 
 	vm.Status.Host, err = getRuntimeHostHostname(vmCtx, vcVM, summary.Runtime.Host)
@@ -89,13 +150,38 @@ func UpdateStatus(
 		errs = append(errs, err)
 	}
 
+	// The runtime host changes when the VM is migrated, and is only meaningful while it is powered on.
+	vm.Status.HostMoID = ""
+	if summary.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn && summary.Runtime.Host != nil {
+		vm.Status.HostMoID = summary.Runtime.Host.Value
+	}
+
+	if err := updateHARestartedCondition(vmCtx, vcVM, summary.Runtime.BootTime); err != nil {
+		errs = append(errs, err)
+	}
+
+	vm.Status.ResourceAllocation, err = virtualmachine.GetResourceAllocationStatus(vmCtx, vcVM)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
 	MarkVMToolsRunningStatusCondition(vmCtx.VM, vmMO.Guest)
+	MarkVMToolsVersionCondition(vmCtx.VM, vmMO.Guest)
 	MarkCustomizationInfoCondition(vmCtx.VM, vmMO.Guest)
+	MarkBootstrapGuestOSFamilyCondition(vmCtx.VM, vmMO.Guest)
 
 	if config := vmMO.Config; config != nil {
 		vm.Status.ChangeBlockTracking = config.ChangeTrackingEnabled
+		vm.Status.Devices = virtualmachine.GetDeviceConnectionStatus(config.Hardware.Device)
+		updateFirstClassDiskVolumeStatus(vm, config.Hardware.Device)
+		vm.Status.SerialPorts = nil
+		if vm.Spec.Advanced != nil {
+			vm.Status.SerialPorts = virtualmachine.GetSerialPortStatus(vm.Spec.Advanced.SerialPorts, config.Hardware.Device)
+		}
 	} else {
 		vm.Status.ChangeBlockTracking = nil
+		vm.Status.Devices = nil
+		vm.Status.SerialPorts = nil
 	}
 
 	if lib.IsWcpFaultDomainsFSSEnabled() {
@@ -125,6 +211,162 @@ func UpdateStatus(
 	return k8serrors.NewAggregate(errs)
 }
 
+// UpdatePowerStateAndGuestStatus updates only the VM's power state and VMware Tools condition in
+// its Status. It returns true if the VM's primary IP differs from the one in its Status, since the
+// VM's network status is then stale and needs the full UpdateStatus.
+func UpdatePowerStateAndGuestStatus(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine) (bool, error) {
+
+	vmMO := &mo.VirtualMachine{}
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), vmPowerStateAndGuestPropertiesSelector, vmMO); err != nil {
+		return false, fmt.Errorf("failed to get VM properties for status update: %w", err)
+	}
+
+	vm := vmCtx.VM
+	vm.Status.PowerState = ConvertPowerState(vmMO.Summary.Runtime.PowerState)
+	MarkVMToolsRunningStatusCondition(vm, vmMO.Guest)
+
+	var primaryIP4, primaryIP6 string
+	if network := getGuestNetworkStatus(vmMO.Guest); network != nil {
+		primaryIP4, primaryIP6 = network.PrimaryIP4, network.PrimaryIP6
+	}
+
+	var oldPrimaryIP4, oldPrimaryIP6 string
+	if vm.Status.Network != nil {
+		oldPrimaryIP4, oldPrimaryIP6 = vm.Status.Network.PrimaryIP4, vm.Status.Network.PrimaryIP6
+	}
+
+	return primaryIP4 != oldPrimaryIP4 || primaryIP6 != oldPrimaryIP6, nil
+}
+
+// updateFirstClassDiskVolumeStatus updates the Status entries of the VM's FirstClassDisk volumes
+// from the VM's disks. The entries of the other volumes are owned by the volume controller and are
+// left unchanged. A FirstClassDisk volume removed from the Spec keeps its entry until its disk is
+// detached from the VM.
+func updateFirstClassDiskVolumeStatus(vm *vmopv1.VirtualMachine, devices object.VirtualDeviceList) {
+	disks := virtualmachine.GetFirstClassDisks(devices)
+
+	var volumeStatus []vmopv1.VirtualMachineVolumeStatus
+	specVolumeNames := map[string]struct{}{}
+	for _, vol := range vm.Spec.Volumes {
+		specVolumeNames[vol.Name] = struct{}{}
+
+		if fcd := vol.FirstClassDisk; fcd != nil {
+			status := vmopv1.VirtualMachineVolumeStatus{
+				Name:   vol.Name,
+				DiskID: fcd.DiskID,
+			}
+			if disk, ok := disks[fcd.DiskID]; ok {
+				setFirstClassDiskVolumeStatus(&status, disk)
+			}
+			volumeStatus = append(volumeStatus, status)
+		}
+	}
+
+	for _, vol := range vm.Status.Volumes {
+		if vol.DiskID == "" {
+			volumeStatus = append(volumeStatus, vol)
+			continue
+		}
+
+		if _, ok := specVolumeNames[vol.Name]; ok {
+			continue
+		}
+
+		if disk, ok := disks[vol.DiskID]; ok {
+			setFirstClassDiskVolumeStatus(&vol, disk)
+			volumeStatus = append(volumeStatus, vol)
+		}
+	}
+
+	// Sort the same as the volume controller.
+	sort.SliceStable(volumeStatus, func(i, j int) bool {
+		return volumeStatus[i].DiskUUID < volumeStatus[j].DiskUUID
+	})
+	vm.Status.Volumes = volumeStatus
+}
+
+func setFirstClassDiskVolumeStatus(status *vmopv1.VirtualMachineVolumeStatus, disk *types.VirtualDisk) {
+	status.Attached = true
+	status.ControllerKey = disk.ControllerKey
+	status.UnitNumber = disk.UnitNumber
+	status.Capacity = resource.NewQuantity(disk.CapacityInBytes, resource.BinarySI)
+
+	if backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+		status.DiskUUID = backing.Uuid
+	}
+}
+
+// updateHARestartedCondition records the VM's boot time, and sets the HARestarted condition when
+// the VM booted again because vSphere HA restarted it on another host, which is when the latest
+// power on event of the VM is the HA restart event. The condition is removed once a later status
+// update has observed it, so it does not go stale.
+func updateHARestartedCondition(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	bootTime *time.Time) error {
+
+	vm := vmCtx.VM
+
+	prevBootTime := vm.Status.BootTime
+	if bootTime == nil || (prevBootTime != nil && !bootTime.After(prevBootTime.Time)) {
+		conditions.Delete(vm, vmopv1.VirtualMachineConditionHARestarted)
+		return nil
+	}
+	vm.Status.BootTime = &metav1.Time{Time: *bootTime}
+
+	// The first boot of the VM cannot be a restart.
+	if prevBootTime == nil {
+		conditions.Delete(vm, vmopv1.VirtualMachineConditionHARestarted)
+		return nil
+	}
+
+	filter := types.EventFilterSpec{
+		Entity: &types.EventFilterSpecByEntity{
+			Entity:    vcVM.Reference(),
+			Recursion: types.EventFilterSpecRecursionOptionSelf,
+		},
+		EventTypeId: vmPoweredOnEventTypeIDs,
+		Time: &types.EventFilterSpecByTime{
+			BeginTime: &prevBootTime.Time,
+		},
+	}
+
+	events, err := event.NewManager(vcVM.Client()).QueryEvents(vmCtx, filter)
+	if err != nil {
+		vm.Status.BootTime = prevBootTime
+		return fmt.Errorf("failed to query the power on events of the VM: %w", err)
+	}
+
+	var lastEvent types.BaseEvent
+	for i := range events {
+		if lastEvent == nil || events[i].GetEvent().Key > lastEvent.GetEvent().Key {
+			lastEvent = events[i]
+		}
+	}
+
+	restartEvent, ok := lastEvent.(*types.VmRestartedOnAlternateHostEvent)
+	if !ok {
+		conditions.Delete(vm, vmopv1.VirtualMachineConditionHARestarted)
+		return nil
+	}
+
+	hostName := vm.Status.Host
+	if restartEvent.Host != nil && restartEvent.Host.Name != "" {
+		hostName = restartEvent.Host.Name
+	}
+
+	vmCtx.Logger.Info("VM was restarted on another host by vSphere HA",
+		"failedHost", restartEvent.SourceHost.Name, "host", hostName)
+	c := conditions.TrueCondition(vmopv1.VirtualMachineConditionHARestarted)
+	c.Reason = vmopv1.VirtualMachineHARestartedHostFailureReason
+	c.Message = fmt.Sprintf("VM was restarted on host %s after host %s failed", hostName, restartEvent.SourceHost.Name)
+	conditions.Set(vm, c)
+
+	return nil
+}
+
 func getRuntimeHostHostname(
 	ctx goctx.Context,
 	vcVM *object.VirtualMachine,
@@ -211,7 +453,8 @@ func guestIPStackInfoToIPStackStatus(guestIPStack *types.GuestStackInfo) vmopv1.
 	return status
 }
 
-func convertPowerState(powerState types.VirtualMachinePowerState) vmopv1.VirtualMachinePowerState {
+// ConvertPowerState returns the VM power state of the vSphere VM power state.
+func ConvertPowerState(powerState types.VirtualMachinePowerState) vmopv1.VirtualMachinePowerState {
 	switch powerState {
 	case types.VirtualMachinePowerStatePoweredOff:
 		return vmopv1.VirtualMachinePowerStateOff
@@ -346,6 +589,60 @@ func MarkVMToolsRunningStatusCondition(
 	}
 }
 
+// MarkVMToolsVersionCondition marks whether the version of VMware Tools in the guest is behind
+// the version available on the VM's host. The condition is removed when the guest does not
+// have VMware Tools installed.
+func MarkVMToolsVersionCondition(
+	vm *vmopv1.VirtualMachine,
+	guestInfo *types.GuestInfo) {
+
+	if guestInfo == nil {
+		conditions.Delete(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent)
+		return
+	}
+
+	versionStatus := guestInfo.ToolsVersionStatus2
+	if versionStatus == "" {
+		versionStatus = guestInfo.ToolsVersionStatus
+	}
+
+	switch versionStatus {
+	case "", string(types.VirtualMachineToolsVersionStatusGuestToolsNotInstalled):
+		conditions.Delete(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent)
+	case string(types.VirtualMachineToolsVersionStatusGuestToolsNeedUpgrade),
+		string(types.VirtualMachineToolsVersionStatusGuestToolsSupportedOld):
+		msg := fmt.Sprintf("VMware Tools version %s is behind the version available on the host", guestInfo.ToolsVersion)
+		conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent, vmopv1.VirtualMachineToolsUpgradeAvailableReason, msg)
+	case string(types.VirtualMachineToolsVersionStatusGuestToolsTooOld):
+		msg := fmt.Sprintf("VMware Tools version %s is too old to be supported by the host", guestInfo.ToolsVersion)
+		conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent, vmopv1.VirtualMachineToolsUnsupportedReason, msg)
+	default:
+		// Current, newer than the host's version, or not managed by the host.
+		conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent)
+	}
+}
+
+// MarkBootstrapGuestOSFamilyCondition marks whether the family of the guest OS reported by
+// VMware Tools is Windows when the VM is bootstrapped with Sysprep. The condition is removed when
+// the VM is not bootstrapped with Sysprep, or the guest OS family has not been reported.
+func MarkBootstrapGuestOSFamilyCondition(
+	vm *vmopv1.VirtualMachine,
+	guestInfo *types.GuestInfo) {
+
+	if vm.Spec.Bootstrap == nil || vm.Spec.Bootstrap.Sysprep == nil || guestInfo == nil || guestInfo.GuestFamily == "" {
+		conditions.Delete(vm, vmopv1.VirtualMachineConditionBootstrapGuestOSFamily)
+		return
+	}
+
+	if guestInfo.GuestFamily != string(types.VirtualMachineGuestOsFamilyWindowsGuest) {
+		msg := fmt.Sprintf("Sysprep requires a Windows guest OS but the guest OS family is %s", guestInfo.GuestFamily)
+		conditions.MarkFalse(vm, vmopv1.VirtualMachineConditionBootstrapGuestOSFamily, vmopv1.VirtualMachineGuestOSFamilyMismatchReason, msg)
+		return
+	}
+
+	conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionBootstrapGuestOSFamily)
+}
+
 func MarkCustomizationInfoCondition(vm *vmopv1.VirtualMachine, guestInfo *types.GuestInfo) {
 	if guestInfo == nil || guestInfo.CustomizationInfo == nil {
 		conditions.MarkUnknown(vm, vmopv1.GuestCustomizationCondition, "NoGuestInfo", "")
@@ -4,12 +4,15 @@
 package vmlifecycle_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
@@ -147,6 +150,264 @@ var _ = Describe("UpdateStatus", func() {
 			Expect(status.HardwareVersion).To(Equal(int32(19)))
 		})
 	})
+
+	Context("FirstClassDisk volumes", func() {
+		var pvcVolStatus vmopv1.VirtualMachineVolumeStatus
+
+		BeforeEach(func() {
+			vmCtx.VM.Spec.Volumes = []vmopv1.VirtualMachineVolume{
+				{
+					Name: "fcd-volume",
+					VirtualMachineVolumeSource: vmopv1.VirtualMachineVolumeSource{
+						FirstClassDisk: &vmopv1.FirstClassDiskVolumeSource{DiskID: "fcd-1"},
+					},
+				},
+				{
+					Name: "detached-fcd-volume",
+					VirtualMachineVolumeSource: vmopv1.VirtualMachineVolumeSource{
+						FirstClassDisk: &vmopv1.FirstClassDiskVolumeSource{DiskID: "fcd-2"},
+					},
+				},
+			}
+
+			pvcVolStatus = vmopv1.VirtualMachineVolumeStatus{
+				Name:     "pvc-volume",
+				Attached: true,
+				DiskUUID: "pvc-disk-uuid",
+			}
+			vmCtx.VM.Status.Volumes = []vmopv1.VirtualMachineVolumeStatus{
+				pvcVolStatus,
+				{Name: "removed-fcd-volume", DiskID: "fcd-3", Attached: true},
+				{Name: "removed-detached-fcd-volume", DiskID: "fcd-4", Attached: true},
+			}
+
+			newDisk := func(diskID, uuid string, unitNumber int32) *types.VirtualDisk {
+				return &types.VirtualDisk{
+					CapacityInBytes: 10 * 1024 * 1024,
+					VirtualDevice: types.VirtualDevice{
+						Key:           2000 + unitNumber,
+						ControllerKey: 1000,
+						UnitNumber:    &unitNumber,
+						Backing: &types.VirtualDiskFlatVer2BackingInfo{
+							Uuid: uuid,
+						},
+					},
+					VDiskId: &types.ID{Id: diskID},
+				}
+			}
+
+			vmMO.Config = &types.VirtualMachineConfigInfo{
+				Hardware: types.VirtualHardware{
+					Device: []types.BaseVirtualDevice{
+						newDisk("fcd-1", "fcd-1-uuid", 1),
+						newDisk("fcd-3", "fcd-3-uuid", 3),
+					},
+				},
+			}
+		})
+
+		It("sets the status of the FirstClassDisk volumes", func() {
+			unitNumber1, unitNumber3 := int32(1), int32(3)
+			capacity := resource.NewQuantity(10*1024*1024, resource.BinarySI)
+
+			Expect(vmCtx.VM.Status.Volumes).To(Equal([]vmopv1.VirtualMachineVolumeStatus{
+				{
+					Name:   "detached-fcd-volume",
+					DiskID: "fcd-2",
+				},
+				{
+					Name:          "fcd-volume",
+					Attached:      true,
+					DiskUUID:      "fcd-1-uuid",
+					DiskID:        "fcd-1",
+					ControllerKey: 1000,
+					UnitNumber:    &unitNumber1,
+					Capacity:      capacity,
+				},
+				{
+					Name:          "removed-fcd-volume",
+					Attached:      true,
+					DiskUUID:      "fcd-3-uuid",
+					DiskID:        "fcd-3",
+					ControllerKey: 1000,
+					UnitNumber:    &unitNumber3,
+					Capacity:      capacity,
+				},
+				pvcVolStatus,
+			}))
+		})
+	})
+
+	Context("HostMoID", func() {
+		var host types.ManagedObjectReference
+
+		BeforeEach(func() {
+			var o mo.VirtualMachine
+			Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"summary"}, &o)).To(Succeed())
+			Expect(o.Summary.Runtime.Host).ToNot(BeNil())
+			host = *o.Summary.Runtime.Host
+
+			vmMO.Summary.Runtime.Host = &host
+			vmMO.Summary.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOn
+		})
+
+		It("sets the runtime host of the powered on VM", func() {
+			Expect(vmCtx.VM.Status.HostMoID).To(Equal(host.Value))
+		})
+
+		When("the VM is powered off", func() {
+			BeforeEach(func() {
+				vmCtx.VM.Status.HostMoID = host.Value
+				vmMO.Summary.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOff
+			})
+
+			It("clears the runtime host", func() {
+				Expect(vmCtx.VM.Status.HostMoID).To(BeEmpty())
+			})
+		})
+
+	})
+
+	Context("HA restart", func() {
+		var prevBootTime metav1.Time
+
+		BeforeEach(func() {
+			prevBootTime = metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			vmCtx.VM.Status.BootTime = &prevBootTime
+
+			bootTime := prevBootTime.Time
+			vmMO.Summary.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOn
+			vmMO.Summary.Runtime.BootTime = &bootTime
+		})
+
+		It("does not mark the VM as restarted when its boot time is unchanged", func() {
+			Expect(vmCtx.VM.Status.BootTime).To(Equal(&prevBootTime))
+			Expect(conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionHARestarted)).To(BeNil())
+		})
+
+		When("the VM is booted for the first time", func() {
+			BeforeEach(func() {
+				vmCtx.VM.Status.BootTime = nil
+			})
+
+			It("records the boot time", func() {
+				Expect(vmCtx.VM.Status.BootTime).To(Equal(&prevBootTime))
+				Expect(conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionHARestarted)).To(BeNil())
+			})
+		})
+
+		When("vSphere HA restarted the VM on another host", func() {
+			var srcHostName string
+
+			BeforeEach(func() {
+				var o mo.VirtualMachine
+				Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"summary"}, &o)).To(Succeed())
+				srcHost := object.NewHostSystem(vcVM.Client(), *o.Summary.Runtime.Host)
+				srcHostName, err = srcHost.ObjectName(ctx)
+				Expect(err).ToNot(HaveOccurred())
+
+				ctx.SimulateHARestart(vcVM.Reference())
+				Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"summary"}, &o)).To(Succeed())
+				vmMO.Summary.Runtime = o.Summary.Runtime
+			})
+
+			It("marks the VM as restarted by HA", func() {
+				Expect(vmCtx.VM.Status.BootTime.After(prevBootTime.Time)).To(BeTrue())
+
+				c := conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionHARestarted)
+				Expect(c).ToNot(BeNil())
+				Expect(c.Status).To(Equal(metav1.ConditionTrue))
+				Expect(c.Reason).To(Equal(vmopv1.VirtualMachineHARestartedHostFailureReason))
+				Expect(c.Message).To(ContainSubstring(vmCtx.VM.Status.Host))
+				Expect(c.Message).To(ContainSubstring(srcHostName))
+
+				By("removes the condition once it has been observed", func() {
+					Expect(vmlifecycle.UpdateStatus(vmCtx, ctx.Client, vcVM, vmMO)).To(Succeed())
+					Expect(conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionHARestarted)).To(BeNil())
+				})
+			})
+		})
+
+		When("the VM was booted again without vSphere HA", func() {
+			BeforeEach(func() {
+				conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionHARestarted)
+
+				bootTime := time.Now()
+				vmMO.Summary.Runtime.BootTime = &bootTime
+			})
+
+			It("does not mark the VM as restarted by HA", func() {
+				Expect(vmCtx.VM.Status.BootTime.After(prevBootTime.Time)).To(BeTrue())
+				Expect(conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionHARestarted)).To(BeNil())
+			})
+		})
+
+		When("the VM is powered off", func() {
+			BeforeEach(func() {
+				vmMO.Summary.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOff
+				vmMO.Summary.Runtime.BootTime = nil
+			})
+
+			It("keeps the boot time", func() {
+				Expect(vmCtx.VM.Status.BootTime).To(Equal(&prevBootTime))
+			})
+		})
+	})
+})
+
+var _ = Describe("UpdatePowerStateAndGuestStatus", func() {
+
+	var (
+		ctx   *builder.TestContextForVCSim
+		vmCtx context.VirtualMachineContextA2
+		vcVM  *object.VirtualMachine
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true})
+
+		vm := builder.DummyVirtualMachineA2()
+		vm.Name = "update-power-state-test"
+
+		vmCtx = context.VirtualMachineContextA2{
+			Context: ctx,
+			Logger:  suite.GetLogger().WithValues("vmName", vm.Name),
+			VM:      vm,
+		}
+
+		var err error
+		vcVM, err = ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+			guest.ToolsRunningStatus = string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+			guest.IpAddress = "10.0.0.2"
+		})
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	It("updates the power state and VMware Tools condition", func() {
+		vmCtx.VM.Status.Network = &vmopv1.VirtualMachineNetworkStatus{PrimaryIP4: "10.0.0.2"}
+
+		ipChanged, err := vmlifecycle.UpdatePowerStateAndGuestStatus(vmCtx, vcVM)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ipChanged).To(BeFalse())
+		Expect(vmCtx.VM.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+		Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineToolsCondition)).To(BeTrue())
+	})
+
+	It("returns true when the primary IP differs from the status", func() {
+		vmCtx.VM.Status.Network = &vmopv1.VirtualMachineNetworkStatus{PrimaryIP4: "10.0.0.1"}
+
+		ipChanged, err := vmlifecycle.UpdatePowerStateAndGuestStatus(vmCtx, vcVM)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ipChanged).To(BeTrue())
+		Expect(vmCtx.VM.Status.Network.PrimaryIP4).To(Equal("10.0.0.1"))
+	})
 })
 
 var _ = Describe("VirtualMachineTools Status to VM Status Condition", func() {
@@ -233,6 +494,72 @@ var _ = Describe("VirtualMachineTools Status to VM Status Condition", func() {
 	})
 })
 
+var _ = Describe("Guest OS family to VM Status Condition", func() {
+	Context("markBootstrapGuestOSFamilyCondition", func() {
+		var (
+			vm        *vmopv1.VirtualMachine
+			guestInfo *types.GuestInfo
+		)
+
+		BeforeEach(func() {
+			vm = &vmopv1.VirtualMachine{
+				Spec: vmopv1.VirtualMachineSpec{
+					Bootstrap: &vmopv1.VirtualMachineBootstrapSpec{
+						Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{},
+					},
+				},
+			}
+			guestInfo = &types.GuestInfo{
+				GuestFamily: string(types.VirtualMachineGuestOsFamilyWindowsGuest),
+			}
+		})
+
+		JustBeforeEach(func() {
+			vmlifecycle.MarkBootstrapGuestOSFamilyCondition(vm, guestInfo)
+		})
+
+		Context("guest OS family is Windows", func() {
+			It("sets condition true", func() {
+				expectedConditions := []metav1.Condition{
+					*conditions.TrueCondition(vmopv1.VirtualMachineConditionBootstrapGuestOSFamily),
+				}
+				Expect(vm.Status.Conditions).To(conditions.MatchConditions(expectedConditions))
+			})
+		})
+		Context("guest OS family is Linux", func() {
+			BeforeEach(func() {
+				guestInfo.GuestFamily = string(types.VirtualMachineGuestOsFamilyLinuxGuest)
+			})
+			It("sets condition false", func() {
+				expectedConditions := []metav1.Condition{
+					*conditions.FalseCondition(vmopv1.VirtualMachineConditionBootstrapGuestOSFamily,
+						vmopv1.VirtualMachineGuestOSFamilyMismatchReason,
+						"Sysprep requires a Windows guest OS but the guest OS family is linuxGuest"),
+				}
+				Expect(vm.Status.Conditions).To(conditions.MatchConditions(expectedConditions))
+			})
+		})
+		Context("guest OS family is not reported", func() {
+			BeforeEach(func() {
+				guestInfo.GuestFamily = ""
+				conditions.MarkTrue(vm, vmopv1.VirtualMachineConditionBootstrapGuestOSFamily)
+			})
+			It("removes the condition", func() {
+				Expect(conditions.Get(vm, vmopv1.VirtualMachineConditionBootstrapGuestOSFamily)).To(BeNil())
+			})
+		})
+		Context("VM is not bootstrapped with Sysprep", func() {
+			BeforeEach(func() {
+				vm.Spec.Bootstrap = nil
+				guestInfo.GuestFamily = string(types.VirtualMachineGuestOsFamilyLinuxGuest)
+			})
+			It("does not set the condition", func() {
+				Expect(conditions.Get(vm, vmopv1.VirtualMachineConditionBootstrapGuestOSFamily)).To(BeNil())
+			})
+		})
+	})
+})
+
 var _ = Describe("VSphere Customization Status to VM Status Condition", func() {
 	Context("markCustomizationInfoCondition", func() {
 		var (
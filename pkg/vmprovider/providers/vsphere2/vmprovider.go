@@ -21,23 +21,28 @@ import (
 	"github.com/vmware/govmomi/vapi/library"
 	"github.com/vmware/govmomi/vim25/types"
 	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
 	ctrlruntime "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	imgregv1a1 "github.com/vmware-tanzu/image-registry-operator-api/api/v1alpha1"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	topologyv1 "github.com/vmware-tanzu/vm-operator/external/tanzu-topology/api/v1alpha1"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	metrics "github.com/vmware-tanzu/vm-operator/pkg/metrics2"
 	"github.com/vmware-tanzu/vm-operator/pkg/record"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/imageresolver"
 	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
 	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/contentlibrary"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
 )
 
 const (
@@ -53,6 +58,10 @@ const (
 
 var log = logf.Log.WithName(VsphereVMProviderName)
 
+// ErrReadOnly is returned by the provider methods that would change vCenter when the provider is
+// read-only.
+var ErrReadOnly = errors.New("provider is read-only")
+
 type VersionedOVFEnvelope struct {
 	OvfEnvelope    *ovf.Envelope
 	ContentVersion string
@@ -65,6 +74,11 @@ type vSphereVMProvider struct {
 	minCPUFreq        uint64
 	ovfCache          *util.Cache[VersionedOVFEnvelope]
 	ovfCacheLockPool  *util.LockPool[string, *sync.RWMutex]
+	reconcileMetrics  *metrics.VMReconcileMetrics
+	tagCache          *virtualmachine.TagCache
+	reconciledVMs     *reconciledVMStates
+	inFlightOps       *inFlightVMOperations
+	imageCache        *imageCache
 
 	vcClientLock sync.Mutex
 	vcClient     *vcclient.Client
@@ -83,6 +97,11 @@ func NewVSphereVMProviderFromClient(
 		globalExtraConfig: getExtraConfig(),
 		ovfCache:          ovfCache,
 		ovfCacheLockPool:  ovfLockPool,
+		reconcileMetrics:  metrics.NewVMReconcileMetrics(),
+		tagCache:          virtualmachine.NewTagCache(),
+		reconciledVMs:     newReconciledVMStates(),
+		inFlightOps:       newInFlightVMOperations(),
+		imageCache:        newImageCache(),
 	}
 }
 
@@ -250,6 +269,27 @@ func (vs *vSphereVMProvider) getOvfEnvelope(
 	return cacheItem.OvfEnvelope, nil
 }
 
+// ListContentLibraries returns the content libraries that are visible to the configured vCenter
+// user, ex. to find the UUID of the library to use as the provider's ContentSource.
+func (vs *vSphereVMProvider) ListContentLibraries(ctx goctx.Context) ([]library.Library, error) {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ContentLibClient().ListLibraries(ctx)
+}
+
+// DoesContentLibraryExist checks if a content library with the UUID exists.
+func (vs *vSphereVMProvider) DoesContentLibraryExist(ctx goctx.Context, clUUID string) (bool, error) {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return client.ContentLibClient().DoesLibraryExist(ctx, clUUID)
+}
+
 // GetItemFromLibraryByName get the library item from specified content library by its name.
 // Do not return error if the item doesn't exist in the content library.
 func (vs *vSphereVMProvider) GetItemFromLibraryByName(ctx goctx.Context,
@@ -265,6 +305,31 @@ func (vs *vSphereVMProvider) GetItemFromLibraryByName(ctx goctx.Context,
 	return client.ContentLibClient().GetLibraryItem(ctx, contentLibrary, itemName, false)
 }
 
+// ResolveImage returns the content library item and storage backing of the image with the name
+// that is available in the namespace, either a namespace-scoped or a cluster-scoped image. An
+// image that is not available in the namespace returns a NotFound error.
+func (vs *vSphereVMProvider) ResolveImage(ctx goctx.Context,
+	imageName, namespace string) (vmprovider.ResolvedImage, error) {
+	log.V(4).Info("Resolve image", "imageName", imageName, "namespace", namespace)
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return vmprovider.ResolvedImage{}, err
+	}
+
+	result, err := imageresolver.Resolve(ctx, vs.k8sClient, client.RestClient(), imageName, namespace,
+		client.Config().ImageNameResolution)
+	if err != nil {
+		return vmprovider.ResolvedImage{}, err
+	}
+
+	return vmprovider.ResolvedImage{
+		LibraryID: result.LibraryUUID,
+		ItemID:    result.ItemID,
+		Storage:   result.Storage,
+	}, nil
+}
+
 func (vs *vSphereVMProvider) UpdateContentLibraryItem(ctx goctx.Context, itemID, newName string, newDescription *string) error {
 	log.V(4).Info("Update Content Library Item", "itemID", itemID)
 
@@ -273,6 +338,10 @@ func (vs *vSphereVMProvider) UpdateContentLibraryItem(ctx goctx.Context, itemID,
 		return err
 	}
 
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
 	return client.ContentLibClient().UpdateLibraryItem(ctx, itemID, newName, newDescription)
 }
 
@@ -324,6 +393,34 @@ func (vs *vSphereVMProvider) getOrComputeCPUMinFrequency(ctx goctx.Context) (uin
 	return minFreq, nil
 }
 
+// GetMissingPrivileges returns the privileges the provider's vCenter user is missing on the configured
+// Datacenter, Folder, ResourcePool, and Datastore, so a misconfigured role can be reported up front
+// instead of as a confusing failure later.
+func (vs *vSphereVMProvider) GetMissingPrivileges(ctx goctx.Context) (map[types.ManagedObjectReference][]string, error) {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := client.Config()
+	entities := []types.ManagedObjectReference{client.Datacenter().Reference()}
+	if config.Folder != "" {
+		entities = append(entities, types.ManagedObjectReference{Type: "Folder", Value: config.Folder})
+	}
+	if config.ResourcePool != "" {
+		entities = append(entities, types.ManagedObjectReference{Type: "ResourcePool", Value: config.ResourcePool})
+	}
+	if config.Datastore != "" {
+		datastore, err := client.Finder().Datastore(ctx, config.Datastore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find Datastore %q: %w", config.Datastore, err)
+		}
+		entities = append(entities, datastore.Reference())
+	}
+
+	return vcenter.GetMissingPrivileges(ctx, client.VimClient(), entities)
+}
+
 func (vs *vSphereVMProvider) ComputeCPUMinFrequency(ctx goctx.Context) error {
 	minFreq, err := vs.computeCPUMinFrequency(ctx)
 	if err != nil {
@@ -339,22 +436,191 @@ func (vs *vSphereVMProvider) ComputeCPUMinFrequency(ctx goctx.Context) error {
 }
 
 func (vs *vSphereVMProvider) computeCPUMinFrequency(ctx goctx.Context) (uint64, error) {
-	// Get all the availability zones in order to calculate the minimum
-	// CPU frequencies for each of the zones' vSphere clusters.
-	availabilityZones, err := topology.GetAvailabilityZones(ctx, vs.k8sClient)
+	client, err := vs.getVcClient(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	client, err := vs.getVcClient(ctx)
+	// Calculate the minimum CPU frequencies for each of the zones' vSphere clusters.
+	clusterMoIDs, err := vs.getAvailabilityZoneClusterMoIDs(ctx, client)
 	if err != nil {
 		return 0, err
 	}
 
+	var errs []error
+
+	var minFreq uint64
+	for _, moID := range clusterMoIDs {
+		ccr := object.NewClusterComputeResource(client.VimClient(),
+			types.ManagedObjectReference{Type: "ClusterComputeResource", Value: moID})
+
+		freq, err := vcenter.ClusterMinCPUFreq(ctx, ccr)
+		if err != nil {
+			errs = append(errs, err)
+		} else if minFreq == 0 || freq < minFreq {
+			minFreq = freq
+		}
+	}
+
+	return minFreq, k8serrors.NewAggregate(errs)
+}
+
+// GetClusterSettings returns the DRS and vSphere HA settings of each of the availability zones'
+// vSphere clusters.
+func (vs *vSphereVMProvider) GetClusterSettings(ctx goctx.Context) ([]vmprovider.ClusterSettings, error) {
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterMoIDs, err := vs.getAvailabilityZoneClusterMoIDs(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+
+	settings := make([]vmprovider.ClusterSettings, 0, len(clusterMoIDs))
+	for _, moID := range clusterMoIDs {
+		ccr := object.NewClusterComputeResource(client.VimClient(),
+			types.ManagedObjectReference{Type: "ClusterComputeResource", Value: moID})
+
+		config, err := vcenter.GetClusterConfigInfoEx(ctx, ccr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get cluster %s configuration: %w", moID, err))
+			continue
+		}
+
+		s := vmprovider.ClusterSettings{
+			ClusterMoID:        moID,
+			DRSEnabled:         pointer.BoolDeref(config.DrsConfig.Enabled, false),
+			DRSAutomationLevel: config.DrsConfig.DefaultVmBehavior,
+			HAEnabled:          pointer.BoolDeref(config.DasConfig.Enabled, false),
+		}
+
+		log.V(4).Info("Cluster settings", "clusterMoID", moID,
+			"drsEnabled", s.DRSEnabled, "drsAutomationLevel", s.DRSAutomationLevel, "haEnabled", s.HAEnabled)
+		settings = append(settings, s)
+	}
+
+	return settings, k8serrors.NewAggregate(errs)
+}
+
+// ResolveClusterForRP returns the vSphere cluster that owns the ResourcePool, which may be nested
+// under any number of other ResourcePools.
+func (vs *vSphereVMProvider) ResolveClusterForRP(
+	ctx goctx.Context,
+	rpMoID string) (types.ManagedObjectReference, error) {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return types.ManagedObjectReference{}, err
+	}
+
+	return vcenter.GetResourcePoolOwnerMoRef(ctx, client.VimClient(), rpMoID)
+}
+
+// GetImageZoneCompatibility returns whether each availability zone, or just the named zone, can
+// create VMs with the hardware version that the image requires. Every zone is compatible with an
+// image that does not report its hardware version.
+func (vs *vSphereVMProvider) GetImageZoneCompatibility(
+	ctx goctx.Context,
+	imageStatus *vmopv1.VirtualMachineImageStatus,
+	zoneName string) ([]vmprovider.ZoneCompatibility, error) {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hardwareVersion int32
+	if imageStatus != nil && imageStatus.HardwareVersion != nil {
+		hardwareVersion = *imageStatus.HardwareVersion
+	}
+
+	return vs.getZoneCompatibility(ctx, client, hardwareVersion, zoneName)
+}
+
+func (vs *vSphereVMProvider) getZoneCompatibility(
+	ctx goctx.Context,
+	client *vcclient.Client,
+	hardwareVersion int32,
+	zoneName string) ([]vmprovider.ZoneCompatibility, error) {
+
+	zoneClusterMoIDs, err := vs.getAvailabilityZonesClusterMoIDs(ctx, client, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cluster's max hardware version is only looked up once even if it is in multiple zones.
+	clusterMaxVersions := map[string]int32{}
+
+	compatibility := make([]vmprovider.ZoneCompatibility, 0, len(zoneClusterMoIDs))
+	for _, zone := range zoneClusterMoIDs {
+		c := vmprovider.ZoneCompatibility{ZoneName: zone.name, Compatible: true}
+
+		for _, moID := range zone.clusterMoIDs {
+			if hardwareVersion == 0 {
+				break
+			}
+
+			maxVersion, ok := clusterMaxVersions[moID]
+			if !ok {
+				ccr := object.NewClusterComputeResource(client.VimClient(),
+					types.ManagedObjectReference{Type: "ClusterComputeResource", Value: moID})
+
+				maxVersion, err = vcenter.GetClusterMaxHardwareVersion(ctx, ccr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get cluster %s hardware versions: %w", moID, err)
+				}
+				clusterMaxVersions[moID] = maxVersion
+			}
+
+			if maxVersion != 0 && maxVersion < hardwareVersion {
+				c.Compatible = false
+				c.IncompatibleClusterMoIDs = append(c.IncompatibleClusterMoIDs, moID)
+			}
+		}
+
+		log.V(4).Info("Zone hardware compatibility", "zone", c.ZoneName, "hardwareVersion", hardwareVersion,
+			"compatible", c.Compatible, "incompatibleClusterMoIDs", c.IncompatibleClusterMoIDs)
+		compatibility = append(compatibility, c)
+	}
+
+	return compatibility, nil
+}
+
+type zoneClusterMoIDs struct {
+	name         string
+	clusterMoIDs []string
+}
+
+// getAvailabilityZonesClusterMoIDs returns the MoIDs of the vSphere clusters of each of the
+// availability zones, or of just the named zone.
+func (vs *vSphereVMProvider) getAvailabilityZonesClusterMoIDs(
+	ctx goctx.Context,
+	client *vcclient.Client,
+	zoneName string) ([]zoneClusterMoIDs, error) {
+
+	var availabilityZones []topologyv1.AvailabilityZone
+	if zoneName != "" {
+		az, err := topology.GetAvailabilityZone(ctx, vs.k8sClient, zoneName)
+		if err != nil {
+			return nil, err
+		}
+		availabilityZones = append(availabilityZones, az)
+	} else {
+		azs, err := topology.GetAvailabilityZones(ctx, vs.k8sClient)
+		if err != nil {
+			return nil, err
+		}
+		availabilityZones = azs
+	}
+
 	if !lib.IsWcpFaultDomainsFSSEnabled() {
 		ccr, err := vcenter.GetResourcePoolOwnerMoRef(ctx, client.VimClient(), client.Config().ResourcePool)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		// Only expect 1 AZ in this case.
@@ -363,29 +629,34 @@ func (vs *vSphereVMProvider) computeCPUMinFrequency(ctx goctx.Context) (uint64,
 		}
 	}
 
-	var errs []error
-
-	var minFreq uint64
+	zones := make([]zoneClusterMoIDs, 0, len(availabilityZones))
 	for _, az := range availabilityZones {
 		moIDs := az.Spec.ClusterComputeResourceMoIDs
 		if len(moIDs) == 0 {
 			moIDs = []string{az.Spec.ClusterComputeResourceMoId} // HA TEMP
 		}
+		zones = append(zones, zoneClusterMoIDs{name: az.Name, clusterMoIDs: moIDs})
+	}
 
-		for _, moID := range moIDs {
-			ccr := object.NewClusterComputeResource(client.VimClient(),
-				types.ManagedObjectReference{Type: "ClusterComputeResource", Value: moID})
+	return zones, nil
+}
 
-			freq, err := vcenter.ClusterMinCPUFreq(ctx, ccr)
-			if err != nil {
-				errs = append(errs, err)
-			} else if minFreq == 0 || freq < minFreq {
-				minFreq = freq
-			}
-		}
+// getAvailabilityZoneClusterMoIDs returns the MoIDs of all the availability zones' vSphere clusters.
+func (vs *vSphereVMProvider) getAvailabilityZoneClusterMoIDs(
+	ctx goctx.Context,
+	client *vcclient.Client) ([]string, error) {
+
+	zones, err := vs.getAvailabilityZonesClusterMoIDs(ctx, client, "")
+	if err != nil {
+		return nil, err
 	}
 
-	return minFreq, k8serrors.NewAggregate(errs)
+	var clusterMoIDs []string
+	for _, zone := range zones {
+		clusterMoIDs = append(clusterMoIDs, zone.clusterMoIDs...)
+	}
+
+	return clusterMoIDs, nil
 }
 
 func (vs *vSphereVMProvider) GetTasksByActID(ctx goctx.Context, actID string) (_ []types.TaskInfo, retErr error) {
@@ -0,0 +1,201 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+)
+
+// EvacuateHost relocates the VMs managed by VM Operator that are on the host to the other hosts in
+// the host's cluster, so the VMs remain in the same zone. A VM is not relocated to a host that has
+// another member of any of the VM's cluster modules, so the VM-VM anti-affinity is not violated.
+// The returned results have an entry for each VM that was on the host.
+func (vs *vSphereVMProvider) EvacuateHost(
+	ctx context.Context,
+	hostMoID string) ([]vmprovider.HostEvacuationResult, error) {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Config().ReadOnly {
+		return nil, ErrReadOnly
+	}
+
+	vimClient := client.VimClient()
+	hostRef := vimtypes.ManagedObjectReference{Type: "HostSystem", Value: hostMoID}
+
+	var host mo.HostSystem
+	if err := property.DefaultCollector(vimClient).RetrieveOne(ctx, hostRef, []string{"parent"}, &host); err != nil {
+		return nil, fmt.Errorf("failed to get host %s: %w", hostMoID, err)
+	}
+
+	if host.Parent == nil || host.Parent.Type != "ClusterComputeResource" {
+		return nil, fmt.Errorf("host %s is not in a cluster", hostMoID)
+	}
+	clusterRef := *host.Parent
+
+	var cluster mo.ClusterComputeResource
+	if err := property.DefaultCollector(vimClient).RetrieveOne(ctx, clusterRef, []string{"host"}, &cluster); err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", clusterRef.Value, err)
+	}
+
+	var hosts []mo.HostSystem
+	if len(cluster.Host) > 0 {
+		if err := property.DefaultCollector(vimClient).Retrieve(ctx, cluster.Host, []string{"runtime"}, &hosts); err != nil {
+			return nil, fmt.Errorf("failed to get cluster %s hosts: %w", clusterRef.Value, err)
+		}
+	}
+
+	var dstHostRefs []vimtypes.ManagedObjectReference
+	for _, h := range hosts {
+		if h.Self != hostRef &&
+			h.Runtime.ConnectionState == vimtypes.HostSystemConnectionStateConnected &&
+			!h.Runtime.InMaintenanceMode {
+			dstHostRefs = append(dstHostRefs, h.Self)
+		}
+	}
+
+	// The host's "vm" property is not used because we need to know where each of the cluster's VMs
+	// is running to honor the cluster modules.
+	v, err := view.NewManager(vimClient).CreateContainerView(ctx, clusterRef, []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = v.Destroy(ctx)
+	}()
+
+	var vms []mo.VirtualMachine
+	if err := v.Retrieve(ctx, []string{"VirtualMachine"}, []string{"config.managedBy", "runtime.host"}, &vms); err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s VMs: %w", clusterRef.Value, err)
+	}
+
+	moduleMembers, err := client.ClusterModuleClient().ListModuleMembers(ctx, clusterRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s module members: %w", clusterRef.Value, err)
+	}
+
+	vmHost := map[vimtypes.ManagedObjectReference]vimtypes.ManagedObjectReference{}
+	hostVMCount := map[vimtypes.ManagedObjectReference]int{}
+	var evacuateVMRefs []vimtypes.ManagedObjectReference
+
+	for _, vm := range vms {
+		if vm.Runtime.Host == nil {
+			continue
+		}
+
+		vmHost[vm.Self] = *vm.Runtime.Host
+		hostVMCount[*vm.Runtime.Host]++
+
+		if *vm.Runtime.Host == hostRef && isManagedVM(vm.Config) {
+			evacuateVMRefs = append(evacuateVMRefs, vm.Self)
+		}
+	}
+
+	results := make([]vmprovider.HostEvacuationResult, 0, len(evacuateVMRefs))
+	for _, vmRef := range evacuateVMRefs {
+		result := vmprovider.HostEvacuationResult{VM: vmRef}
+
+		dstHostRef, ok := getEvacuationHost(vmRef, dstHostRefs, vmHost, hostVMCount, moduleMembers)
+		if !ok {
+			result.Err = fmt.Errorf("no host is available to relocate VM %s to", vmRef.Value)
+			results = append(results, result)
+			continue
+		}
+
+		if err := relocateVMToHost(ctx, object.NewVirtualMachine(vimClient, vmRef), dstHostRef); err != nil {
+			result.Err = fmt.Errorf("failed to relocate VM %s to host %s: %w", vmRef.Value, dstHostRef.Value, err)
+			results = append(results, result)
+			continue
+		}
+
+		vmHost[vmRef] = dstHostRef
+		hostVMCount[hostRef]--
+		hostVMCount[dstHostRef]++
+
+		result.Host = dstHostRef
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func isManagedVM(config *vimtypes.VirtualMachineConfigInfo) bool {
+	return config != nil && config.ManagedBy != nil && config.ManagedBy.ExtensionKey == vmopv1.ManagedByExtensionKey
+}
+
+// getEvacuationHost returns the host with the fewest VMs that does not have another member of any
+// of the VM's cluster modules.
+func getEvacuationHost(
+	vmRef vimtypes.ManagedObjectReference,
+	hostRefs []vimtypes.ManagedObjectReference,
+	vmHost map[vimtypes.ManagedObjectReference]vimtypes.ManagedObjectReference,
+	hostVMCount map[vimtypes.ManagedObjectReference]int,
+	moduleMembers map[string][]vimtypes.ManagedObjectReference) (vimtypes.ManagedObjectReference, bool) {
+
+	antiAffinityHosts := map[vimtypes.ManagedObjectReference]struct{}{}
+	for _, members := range moduleMembers {
+		isMember := false
+		for _, member := range members {
+			if member == vmRef {
+				isMember = true
+				break
+			}
+		}
+
+		if !isMember {
+			continue
+		}
+
+		for _, member := range members {
+			if h, ok := vmHost[member]; ok && member != vmRef {
+				antiAffinityHosts[h] = struct{}{}
+			}
+		}
+	}
+
+	var dstHostRef vimtypes.ManagedObjectReference
+	found := false
+	for _, hostRef := range hostRefs {
+		if _, ok := antiAffinityHosts[hostRef]; ok {
+			continue
+		}
+
+		if !found || hostVMCount[hostRef] < hostVMCount[dstHostRef] {
+			dstHostRef = hostRef
+			found = true
+		}
+	}
+
+	return dstHostRef, found
+}
+
+func relocateVMToHost(
+	ctx context.Context,
+	vcVM *object.VirtualMachine,
+	hostRef vimtypes.ManagedObjectReference) error {
+
+	relocateSpec := vimtypes.VirtualMachineRelocateSpec{
+		Host: &hostRef,
+	}
+
+	task, err := vcVM.Relocate(ctx, relocateSpec, vimtypes.VirtualMachineMovePriorityDefaultPriority)
+	if err != nil {
+		return err
+	}
+
+	return task.Wait(ctx)
+}
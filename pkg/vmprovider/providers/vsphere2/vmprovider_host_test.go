@@ -0,0 +1,145 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere_test
+
+import (
+	goctx "context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/vapi/cluster"
+	"github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	vsphere "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func hostTests() {
+
+	var (
+		ctx        *builder.TestContextForVCSim
+		vmProvider vmprovider.VirtualMachineProviderInterfaceA2
+		nsInfo     builder.WorkloadNamespaceInfo
+
+		clusterRef types.ManagedObjectReference
+		hostRefs   []types.ManagedObjectReference
+		vmRefs     []types.ManagedObjectReference
+		otherVMRef types.ManagedObjectReference
+	)
+
+	createVM := func(name string) types.ManagedObjectReference {
+		vmClass := builder.DummyVirtualMachineClassA2()
+		vmClass.Name = name
+		vmClass.Namespace = nsInfo.Namespace
+		Expect(ctx.Client.Create(ctx, vmClass)).To(Succeed())
+		vmClass.Status.Ready = true
+		Expect(ctx.Client.Status().Update(ctx, vmClass)).To(Succeed())
+
+		vm := builder.DummyBasicVirtualMachineA2(name, nsInfo.Namespace)
+		vm.Spec.ClassName = vmClass.Name
+		vm.Spec.ImageName = ctx.ContentLibraryImageName
+		vm.Spec.StorageClass = ctx.StorageClassName
+		vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+		if vm.Spec.Network == nil {
+			vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{}
+		}
+		vm.Spec.Network.Disabled = true
+
+		Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+		Expect(vm.Status.UniqueID).ToNot(BeEmpty())
+		return types.ManagedObjectReference{Type: "VirtualMachine", Value: vm.Status.UniqueID}
+	}
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true, WithContentLibrary: true})
+		ctx.Context = goctx.WithValue(ctx.Context, context.MaxDeployThreadsContextKey, 1)
+		vmProvider = vsphere.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+		nsInfo = ctx.CreateWorkloadNamespace()
+
+		clusterRef = ctx.GetSingleClusterCompute().Reference()
+		hostRefs = ctx.GetHostsForCluster(clusterRef)
+		Expect(hostRefs).To(HaveLen(3))
+
+		vmRefs = []types.ManagedObjectReference{createVM("test-vm-1"), createVM("test-vm-2")}
+		for _, vmRef := range vmRefs {
+			ctx.RelocateVirtualMachine(vmRef, hostRefs[0])
+		}
+
+		// A VM that is not managed by VM Operator.
+		otherVM, err := ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		Expect(err).ToNot(HaveOccurred())
+		otherVMRef = otherVM.Reference()
+		ctx.RelocateVirtualMachine(otherVMRef, hostRefs[0])
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		vmProvider = nil
+		nsInfo = builder.WorkloadNamespaceInfo{}
+		hostRefs = nil
+		vmRefs = nil
+	})
+
+	Context("EvacuateHost", func() {
+
+		It("relocates the managed VMs to the other hosts", func() {
+			results, err := vmProvider.EvacuateHost(ctx, hostRefs[0].Value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+
+			for _, r := range results {
+				Expect(r.Err).ToNot(HaveOccurred())
+				Expect(vmRefs).To(ContainElement(r.VM))
+				Expect(r.Host).ToNot(Equal(hostRefs[0]))
+				Expect(ctx.GetVirtualMachineRuntimeHost(r.VM)).To(Equal(r.Host))
+			}
+
+			By("does not relocate the unmanaged VM", func() {
+				Expect(ctx.GetVirtualMachineRuntimeHost(otherVMRef)).To(Equal(hostRefs[0]))
+			})
+		})
+
+		Context("VMs are in a cluster module", func() {
+
+			var peerVMRef types.ManagedObjectReference
+
+			BeforeEach(func() {
+				peerVMRef = createVM("test-vm-3")
+				ctx.RelocateVirtualMachine(peerVMRef, hostRefs[1])
+
+				m := cluster.NewManager(ctx.RestClient)
+				moduleID, err := m.CreateModule(ctx, clusterRef)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = m.AddModuleMembers(ctx, moduleID, vmRefs[0], vmRefs[1], peerVMRef)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("does not relocate a VM to a host with another member of the module", func() {
+				results, err := vmProvider.EvacuateHost(ctx, hostRefs[0].Value)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results).To(HaveLen(2))
+
+				Expect(results[0].VM).To(Equal(vmRefs[0]))
+				Expect(results[0].Err).ToNot(HaveOccurred())
+				Expect(results[0].Host).To(Equal(hostRefs[2]))
+				Expect(ctx.GetVirtualMachineRuntimeHost(vmRefs[0])).To(Equal(hostRefs[2]))
+
+				Expect(results[1].VM).To(Equal(vmRefs[1]))
+				Expect(results[1].Err).To(MatchError(fmt.Sprintf("no host is available to relocate VM %s to", vmRefs[1].Value)))
+				Expect(ctx.GetVirtualMachineRuntimeHost(vmRefs[1])).To(Equal(hostRefs[0]))
+			})
+		})
+
+		It("returns an error when the host does not exist", func() {
+			_, err := vmProvider.EvacuateHost(ctx, "does-not-exist")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	goctx "context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/topology"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/resources"
+)
+
+type imageCacheKey struct {
+	itemID        string
+	datastoreMoID string
+}
+
+type imageCacheState string
+
+const (
+	imageCacheStateCaching imageCacheState = "Caching"
+	imageCacheStateReady   imageCacheState = "Ready"
+)
+
+type imageCacheEntry struct {
+	state  imageCacheState
+	vmMoID string
+}
+
+// imageCache is the VMs the content library items have been cached to. It is loaded from the
+// inventory the first time it is used, so the VMs cached by a previous instance of the provider
+// are still used after a restart.
+type imageCache struct {
+	mu      sync.Mutex
+	loaded  bool
+	entries map[imageCacheKey]*imageCacheEntry
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{
+		entries: map[imageCacheKey]*imageCacheEntry{},
+	}
+}
+
+// CacheImage copies the image's content library item to a VM on the datastore ahead of time.
+// A VM that is later created from the image on the datastore is cloned from that VM instead
+// of deploying the item, which is slow the first time the item is deployed to a datastore.
+func (vs *vSphereVMProvider) CacheImage(
+	ctx goctx.Context,
+	imageName, namespace, datastoreMoID string) error {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
+	resolved, err := vs.ResolveImage(ctx, imageName, namespace)
+	if err != nil {
+		return err
+	}
+
+	key := imageCacheKey{itemID: resolved.ItemID, datastoreMoID: datastoreMoID}
+	logger := log.WithValues("imageName", imageName, "itemID", key.itemID, "datastore", key.datastoreMoID)
+
+	// This also drops the entry when its VM no longer exists so the image is cached again.
+	if vmMoID, ok := vs.getCachedImageVMMoIDs(ctx, client, key.itemID)[key.datastoreMoID]; ok {
+		logger.V(4).Info("Image is already cached", "cacheVM", vmMoID)
+		return nil
+	}
+
+	cache := vs.imageCache
+	cache.mu.Lock()
+	if entry, ok := cache.entries[key]; ok {
+		cache.mu.Unlock()
+		logger.V(4).Info("Image is already cached", "state", entry.state)
+		return nil
+	}
+	entry := &imageCacheEntry{state: imageCacheStateCaching}
+	cache.entries[key] = entry
+	cache.mu.Unlock()
+
+	logger.Info("Caching image to datastore")
+
+	vmMoID, err := vs.cacheImage(ctx, key, namespace)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if err != nil {
+		delete(cache.entries, key)
+		return err
+	}
+
+	entry.state = imageCacheStateReady
+	entry.vmMoID = vmMoID
+	logger.Info("Cached image to datastore", "cacheVM", vmMoID)
+
+	return nil
+}
+
+// cacheImage deploys the content library item to a VM on the datastore, under the namespace's
+// Folder and ResourcePool, and returns the VM's MoID.
+func (vs *vSphereVMProvider) cacheImage(
+	ctx goctx.Context,
+	key imageCacheKey,
+	namespace string) (string, error) {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	folderMoID, rpMoIDs, err := topology.GetNamespaceFolderAndRPMoIDs(ctx, vs.k8sClient, namespace)
+	if err != nil {
+		return "", err
+	}
+	if folderMoID == "" || len(rpMoIDs) == 0 {
+		return "", fmt.Errorf("no Folder and ResourcePool found for namespace %s", namespace)
+	}
+
+	deploy := vcenter.Deploy{
+		DeploymentSpec: vcenter.DeploymentSpec{
+			Name:               fmt.Sprintf("vmi-cache-%s-%s", key.itemID, key.datastoreMoID),
+			DefaultDatastoreID: key.datastoreMoID,
+			AcceptAllEULA:      true,
+		},
+		Target: vcenter.Target{
+			ResourcePoolID: rpMoIDs[0],
+			FolderID:       folderMoID,
+		},
+	}
+
+	ref, err := vcenter.NewManager(client.RestClient()).DeployLibraryItem(ctx, key.itemID, deploy)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to deploy library item %s to datastore %s", key.itemID, key.datastoreMoID)
+	}
+
+	vcVM := resources.NewVMFromObject(object.NewVirtualMachine(client.VimClient(), *ref))
+	configSpec := &types.VirtualMachineConfigSpec{
+		Annotation: constants.VCVMImageCacheAnnotation,
+		ExtraConfig: []types.BaseOptionValue{
+			&types.OptionValue{Key: constants.ImageCacheItemIDExtraConfigKey, Value: key.itemID},
+		},
+	}
+	if err := vcVM.Reconfigure(ctx, configSpec); err != nil {
+		return "", err
+	}
+
+	return ref.Value, nil
+}
+
+// getCachedImageVMMoIDs returns the MoIDs, keyed by datastore MoID, of the VMs the content
+// library item has been cached to. A cached VM that no longer exists, ex. it was deleted from
+// the inventory, is removed from the cache so the image is cached again on the next request.
+func (vs *vSphereVMProvider) getCachedImageVMMoIDs(
+	ctx goctx.Context,
+	vcClient *vcclient.Client,
+	itemID string) map[string]string {
+
+	cache := vs.imageCache
+	finder := vcClient.Finder()
+
+	cache.mu.Lock()
+	if !cache.loaded {
+		if err := cache.load(ctx, vcClient.VimClient(), finder); err != nil {
+			// The cache is loaded again on its next use.
+			log.Error(err, "Failed to load the cached image VMs")
+		}
+	}
+	candidates := map[imageCacheKey]string{}
+	for key, entry := range cache.entries {
+		if key.itemID == itemID && entry.state == imageCacheStateReady {
+			candidates[key] = entry.vmMoID
+		}
+	}
+	cache.mu.Unlock()
+
+	var vmMoIDs map[string]string
+	for key, vmMoID := range candidates {
+		ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: vmMoID}
+		if _, err := finder.ObjectReference(ctx, ref); err != nil {
+			log.Info("Removing cached image VM that no longer exists",
+				"itemID", key.itemID, "datastore", key.datastoreMoID, "cacheVM", vmMoID)
+
+			cache.mu.Lock()
+			if entry, ok := cache.entries[key]; ok && entry.vmMoID == vmMoID {
+				delete(cache.entries, key)
+			}
+			cache.mu.Unlock()
+			continue
+		}
+
+		if vmMoIDs == nil {
+			vmMoIDs = map[string]string{}
+		}
+		vmMoIDs[key.datastoreMoID] = vmMoID
+	}
+
+	return vmMoIDs
+}
+
+// load adds the VMs in the inventory that an image was cached to, which are identified by their
+// ImageCacheItemIDExtraConfigKey, to the cache. The caller must hold the lock.
+func (c *imageCache) load(
+	ctx goctx.Context,
+	vimClient *vim25.Client,
+	finder *find.Finder) error {
+
+	vms, err := finder.VirtualMachineList(ctx, "vmi-cache-*")
+	if err != nil {
+		var notFoundErr *find.NotFoundError
+		if !errors.As(err, &notFoundErr) {
+			return err
+		}
+	}
+
+	if len(vms) > 0 {
+		refs := make([]types.ManagedObjectReference, 0, len(vms))
+		for _, vm := range vms {
+			refs = append(refs, vm.Reference())
+		}
+
+		var moVMs []mo.VirtualMachine
+		if err := property.DefaultCollector(vimClient).Retrieve(ctx, refs,
+			[]string{"config.extraConfig", "datastore"}, &moVMs); err != nil {
+			return err
+		}
+
+		for _, moVM := range moVMs {
+			if moVM.Config == nil || len(moVM.Datastore) == 0 {
+				continue
+			}
+
+			itemID, ok := util.ExtraConfigToMap(moVM.Config.ExtraConfig)[constants.ImageCacheItemIDExtraConfigKey]
+			if !ok || itemID == "" {
+				continue
+			}
+
+			key := imageCacheKey{itemID: itemID, datastoreMoID: moVM.Datastore[0].Value}
+			if _, ok := c.entries[key]; !ok {
+				c.entries[key] = &imageCacheEntry{state: imageCacheStateReady, vmMoID: moVM.Self.Value}
+			}
+		}
+	}
+
+	c.loaded = true
+	log.Info("Loaded the cached image VMs", "count", len(c.entries))
+
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/retry"
+	vmutil "github.com/vmware-tanzu/vm-operator/pkg/util/vsphere/vm"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
+)
+
+// ListManagedVirtualMachines returns the VMs in the datacenter's VM folder that VM Operator created
+// for a VirtualMachine CR. Only the VMs that are managed by VM Operator and that have the ExtraConfig
+// with their VirtualMachine CR are returned, so a VM that VM Operator did not create is never
+// returned.
+func (vs *vSphereVMProvider) ListManagedVirtualMachines(
+	ctx context.Context) ([]vmprovider.ManagedVirtualMachine, error) {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vimClient := client.VimClient()
+
+	folders, err := client.Datacenter().Folders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := view.NewManager(vimClient).CreateContainerView(ctx, folders.VmFolder.Reference(), []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = v.Destroy(ctx)
+	}()
+
+	var vms []mo.VirtualMachine
+	if err := v.Retrieve(ctx, []string{"VirtualMachine"}, []string{"config.managedBy"}, &vms); err != nil {
+		return nil, fmt.Errorf("failed to get VMs: %w", err)
+	}
+
+	// The ExtraConfig is only retrieved for the managed VMs since it can be large.
+	var managedVMRefs []vimtypes.ManagedObjectReference
+	for _, vm := range vms {
+		if isManagedVM(vm.Config) {
+			managedVMRefs = append(managedVMRefs, vm.Self)
+		}
+	}
+
+	if len(managedVMRefs) == 0 {
+		return nil, nil
+	}
+
+	var managedVMs []mo.VirtualMachine
+	if err := property.DefaultCollector(vimClient).Retrieve(ctx, managedVMRefs, []string{"name", "config.managedBy", "config.extraConfig"}, &managedVMs); err != nil {
+		return nil, fmt.Errorf("failed to get managed VMs: %w", err)
+	}
+
+	var results []vmprovider.ManagedVirtualMachine
+	for _, vm := range managedVMs {
+		namespace, name, ok := createdForVirtualMachine(vm.Config)
+		if !ok {
+			continue
+		}
+
+		results = append(results, vmprovider.ManagedVirtualMachine{
+			VM:          vm.Self,
+			Name:        vm.Name,
+			Namespace:   namespace,
+			VMName:      name,
+			DestroyTime: destroyTime(vm.Config),
+		})
+	}
+
+	return results, nil
+}
+
+// DeleteOrphanedVirtualMachine powers off and destroys the VM that VM Operator created for a
+// VirtualMachine CR that no longer exists. The VM is checked again to still be managed by VM
+// Operator and to have the ExtraConfig with its VirtualMachine CR before it is destroyed.
+func (vs *vSphereVMProvider) DeleteOrphanedVirtualMachine(
+	ctx context.Context,
+	vmMoID string) error {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
+	vcVM := object.NewVirtualMachine(client.VimClient(), vimtypes.ManagedObjectReference{Type: "VirtualMachine", Value: vmMoID})
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(ctx, vcVM.Reference(), []string{"config.managedBy", "config.extraConfig", "summary.runtime.powerState"}, &o); err != nil {
+		return fmt.Errorf("failed to get VM %s: %w", vmMoID, err)
+	}
+
+	if !isManagedVM(o.Config) {
+		return fmt.Errorf("VM %s is not managed by VM Operator", vmMoID)
+	}
+	if _, _, ok := createdForVirtualMachine(o.Config); !ok {
+		return fmt.Errorf("VM %s was not created by VM Operator for a VirtualMachine", vmMoID)
+	}
+
+	// The orphaned VM does not have a power off mode, so it is not waited on to shut down.
+	if _, err := vmutil.SetAndWaitOnPowerState(
+		logr.NewContext(ctx, log),
+		client.VimClient(),
+		o,
+		false,
+		vimtypes.VirtualMachinePowerStatePoweredOff,
+		vmutil.PowerOpBehaviorHard); err != nil {
+
+		return err
+	}
+
+	return retry.OnTransientFault(func() error {
+		t, err := vcVM.Destroy(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := t.WaitForResult(ctx); err != nil {
+			return errors.Wrapf(err, "destroy VM task failed")
+		}
+
+		return nil
+	})
+}
+
+// destroyTime returns the time after which the VM of a VirtualMachine CR that was deleted with a
+// deletion grace period is destroyed, or the zero time if the VM does not have one.
+func destroyTime(config *vimtypes.VirtualMachineConfigInfo) time.Time {
+	if config == nil {
+		return time.Time{}
+	}
+
+	for _, ec := range config.ExtraConfig {
+		ov := ec.GetOptionValue()
+		if ov.Key != constants.VMDestroyTimeExtraConfigKey {
+			continue
+		}
+
+		if value, ok := ov.Value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t
+			}
+		}
+		break
+	}
+
+	return time.Time{}
+}
+
+// createdForVirtualMachine returns the namespace and name of the VirtualMachine CR that VM Operator
+// created the VM for.
+func createdForVirtualMachine(config *vimtypes.VirtualMachineConfigInfo) (string, string, bool) {
+	if config == nil {
+		return "", "", false
+	}
+
+	for _, ec := range config.ExtraConfig {
+		ov := ec.GetOptionValue()
+		if ov.Key != constants.VMNamespacedNameExtraConfigKey {
+			continue
+		}
+
+		value, ok := ov.Value.(string)
+		if !ok {
+			return "", "", false
+		}
+
+		namespace, name, ok := strings.Cut(value, "/")
+		return namespace, name, ok && namespace != "" && name != ""
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere_test
+
+import (
+	goctx "context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	vsphere "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func orphanedVMTests() {
+
+	var (
+		ctx        *builder.TestContextForVCSim
+		vmProvider vmprovider.VirtualMachineProviderInterfaceA2
+		nsInfo     builder.WorkloadNamespaceInfo
+
+		vm         *vmopv1.VirtualMachine
+		vmRef      types.ManagedObjectReference
+		otherVMRef types.ManagedObjectReference
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true, WithContentLibrary: true})
+		ctx.Context = goctx.WithValue(ctx.Context, context.MaxDeployThreadsContextKey, 1)
+		vmProvider = vsphere.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+		nsInfo = ctx.CreateWorkloadNamespace()
+
+		vmClass := builder.DummyVirtualMachineClassA2()
+		vmClass.Namespace = nsInfo.Namespace
+		Expect(ctx.Client.Create(ctx, vmClass)).To(Succeed())
+		vmClass.Status.Ready = true
+		Expect(ctx.Client.Status().Update(ctx, vmClass)).To(Succeed())
+
+		vm = builder.DummyBasicVirtualMachineA2("test-vm", nsInfo.Namespace)
+		vm.Spec.ClassName = vmClass.Name
+		vm.Spec.ImageName = ctx.ContentLibraryImageName
+		vm.Spec.StorageClass = ctx.StorageClassName
+		vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+		if vm.Spec.Network == nil {
+			vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{}
+		}
+		vm.Spec.Network.Disabled = true
+
+		Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+		Expect(vm.Status.UniqueID).ToNot(BeEmpty())
+		vmRef = types.ManagedObjectReference{Type: "VirtualMachine", Value: vm.Status.UniqueID}
+
+		// A VM that is managed by VM Operator but that VM Operator did not create.
+		otherVM, err := ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		Expect(err).ToNot(HaveOccurred())
+		otherVMRef = otherVM.Reference()
+		task, err := otherVM.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ManagedBy: &types.ManagedByInfo{
+				ExtensionKey: vmopv1.ManagedByExtensionKey,
+				Type:         vmopv1.ManagedByExtensionType,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(task.Wait(ctx)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		vmProvider = nil
+		nsInfo = builder.WorkloadNamespaceInfo{}
+		vm = nil
+	})
+
+	Context("ListManagedVirtualMachines", func() {
+		It("returns only the VMs created for a VirtualMachine", func() {
+			managedVMs, err := vmProvider.ListManagedVirtualMachines(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(managedVMs).To(ConsistOf(vmprovider.ManagedVirtualMachine{
+				VM:        vmRef,
+				Name:      vm.Name,
+				Namespace: vm.Namespace,
+				VMName:    vm.Name,
+			}))
+		})
+
+		It("returns the destroy time of a VM kept for the deletion grace period of its VirtualMachine", func() {
+			destroyTime := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+			vm.Status.DestroyTime = &destroyTime
+			Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+
+			managedVMs, err := vmProvider.ListManagedVirtualMachines(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(managedVMs).To(HaveLen(1))
+			Expect(managedVMs[0].VM).To(Equal(vmRef))
+			Expect(managedVMs[0].DestroyTime.Equal(destroyTime.Time)).To(BeTrue())
+		})
+	})
+
+	Context("DeleteOrphanedVirtualMachine", func() {
+		It("deletes the VM", func() {
+			Expect(vmProvider.DeleteOrphanedVirtualMachine(ctx, vmRef.Value)).To(Succeed())
+
+			managedVMs, err := vmProvider.ListManagedVirtualMachines(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(managedVMs).To(BeEmpty())
+		})
+
+		It("does not delete a VM that was not created for a VirtualMachine", func() {
+			err := vmProvider.DeleteOrphanedVirtualMachine(ctx, otherVMRef.Value)
+			Expect(err).To(MatchError(ContainSubstring("was not created by VM Operator")))
+
+			var state types.VirtualMachinePowerState
+			state, err = object.NewVirtualMachine(ctx.VCClient.Client, otherVMRef).PowerState(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+		})
+	})
+}
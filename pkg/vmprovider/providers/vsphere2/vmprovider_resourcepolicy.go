@@ -76,6 +76,10 @@ func (vs *vSphereVMProvider) CreateOrUpdateVirtualMachineSetResourcePolicy(
 		return err
 	}
 
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
 	vimClient := client.VimClient()
 	var errs []error
 
@@ -117,6 +121,10 @@ func (vs *vSphereVMProvider) DeleteVirtualMachineSetResourcePolicy(
 		return err
 	}
 
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
 	vimClient := client.VimClient()
 	var errs []error
 
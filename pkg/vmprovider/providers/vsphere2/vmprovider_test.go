@@ -4,15 +4,21 @@
 package vsphere_test
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/utils/pointer"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere"
+	vsphere2 "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
 
@@ -46,6 +52,218 @@ func cpuFreqTests() {
 	})
 }
 
+func clusterSettingsTests() {
+
+	var (
+		testConfig builder.VCSimTestConfig
+		ctx        *builder.TestContextForVCSim
+		vmProvider vmprovider.VirtualMachineProviderInterfaceA2
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithV1A2: true}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig)
+		vmProvider = vsphere2.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		vmProvider = nil
+	})
+
+	Context("GetClusterSettings", func() {
+		It("returns the cluster's DRS and HA settings", func() {
+			ccr := ctx.GetSingleClusterCompute()
+			ctx.SetClusterDRSAndHA(ccr.Reference(), true, types.DrsBehaviorPartiallyAutomated, true)
+
+			settings, err := vmProvider.GetClusterSettings(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings).To(ConsistOf(vmprovider.ClusterSettings{
+				ClusterMoID:        ccr.Reference().Value,
+				DRSEnabled:         true,
+				DRSAutomationLevel: types.DrsBehaviorPartiallyAutomated,
+				HAEnabled:          true,
+			}))
+
+			By("the cluster's DRS and HA are disabled", func() {
+				ctx.SetClusterDRSAndHA(ccr.Reference(), false, "", false)
+
+				settings, err := vmProvider.GetClusterSettings(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(settings).To(ConsistOf(vmprovider.ClusterSettings{
+					ClusterMoID: ccr.Reference().Value,
+				}))
+			})
+		})
+
+		Context("with fault domains", func() {
+			BeforeEach(func() {
+				testConfig.WithFaultDomains = true
+			})
+
+			It("returns the settings of each zone's clusters", func() {
+				var expected []vmprovider.ClusterSettings
+				for i := 0; i < ctx.ZoneCount; i++ {
+					for _, ccr := range ctx.GetAZClusterComputes(fmt.Sprintf("az-%d", i)) {
+						ctx.SetClusterDRSAndHA(ccr.Reference(), true, types.DrsBehaviorFullyAutomated, i%2 == 0)
+						expected = append(expected, vmprovider.ClusterSettings{
+							ClusterMoID:        ccr.Reference().Value,
+							DRSEnabled:         true,
+							DRSAutomationLevel: types.DrsBehaviorFullyAutomated,
+							HAEnabled:          i%2 == 0,
+						})
+					}
+				}
+				Expect(expected).ToNot(BeEmpty())
+
+				settings, err := vmProvider.GetClusterSettings(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(settings).To(ConsistOf(expected))
+			})
+		})
+	})
+
+	Context("ResolveClusterForRP", func() {
+		It("returns the cluster of the namespace ResourcePool", func() {
+			nsInfo := ctx.CreateWorkloadNamespace()
+			nsRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", "")
+
+			ccrRef, err := vmProvider.ResolveClusterForRP(ctx, nsRP.Reference().Value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ccrRef).To(Equal(ctx.GetSingleClusterCompute().Reference()))
+		})
+
+		It("returns error when the ResourcePool does not exist", func() {
+			_, err := vmProvider.ResolveClusterForRP(ctx, "does-not-exist")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+}
+
+func imageZoneCompatibilityTests() {
+
+	var (
+		ctx         *builder.TestContextForVCSim
+		vmProvider  vmprovider.VirtualMachineProviderInterfaceA2
+		imageStatus *vmopv1.VirtualMachineImageStatus
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true, WithFaultDomains: true})
+		vmProvider = vsphere2.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+		imageStatus = &vmopv1.VirtualMachineImageStatus{HardwareVersion: pointer.Int32(17)}
+
+		ctx.SetHardwareVersions("vmx-15", "vmx-17")
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		vmProvider = nil
+	})
+
+	It("returns every zone as compatible", func() {
+		compatibility, err := vmProvider.GetImageZoneCompatibility(ctx, imageStatus, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(compatibility).To(HaveLen(len(ctx.ZoneNames)))
+		for _, c := range compatibility {
+			Expect(c.Compatible).To(BeTrue())
+		}
+	})
+
+	When("a zone's cluster does not support the image's hardware version", func() {
+		var incompatibleCCR types.ManagedObjectReference
+
+		BeforeEach(func() {
+			incompatibleCCR = ctx.GetAZClusterComputes(ctx.ZoneNames[1])[0].Reference()
+			ctx.SetClusterHardwareVersions(incompatibleCCR, "vmx-15")
+		})
+
+		It("returns the zone as incompatible", func() {
+			compatibility, err := vmProvider.GetImageZoneCompatibility(ctx, imageStatus, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(compatibility).To(HaveLen(len(ctx.ZoneNames)))
+			for _, c := range compatibility {
+				if c.ZoneName == ctx.ZoneNames[1] {
+					Expect(c.Compatible).To(BeFalse())
+					Expect(c.IncompatibleClusterMoIDs).To(ConsistOf(incompatibleCCR.Value))
+				} else {
+					Expect(c.Compatible).To(BeTrue(), "zone %s", c.ZoneName)
+				}
+			}
+		})
+
+		It("returns just the target zone", func() {
+			compatibility, err := vmProvider.GetImageZoneCompatibility(ctx, imageStatus, ctx.ZoneNames[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(compatibility).To(HaveLen(1))
+			Expect(compatibility[0].ZoneName).To(Equal(ctx.ZoneNames[1]))
+			Expect(compatibility[0].Compatible).To(BeFalse())
+		})
+
+		It("returns every zone as compatible when the image does not report its hardware version", func() {
+			compatibility, err := vmProvider.GetImageZoneCompatibility(ctx, &vmopv1.VirtualMachineImageStatus{}, "")
+			Expect(err).ToNot(HaveOccurred())
+			for _, c := range compatibility {
+				Expect(c.Compatible).To(BeTrue())
+			}
+		})
+	})
+}
+
+func privilegesTests() {
+
+	var (
+		testConfig builder.VCSimTestConfig
+		ctx        *builder.TestContextForVCSim
+		vmProvider vmprovider.VirtualMachineProviderInterfaceA2
+	)
+
+	BeforeEach(func() {
+		testConfig = builder.VCSimTestConfig{WithV1A2: true}
+	})
+
+	JustBeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(testConfig)
+		vmProvider = vsphere2.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		vmProvider = nil
+	})
+
+	Context("GetMissingPrivileges", func() {
+		It("returns no missing privileges", func() {
+			missing, err := vmProvider.GetMissingPrivileges(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(BeEmpty())
+		})
+
+		When("the vCenter user has a restricted role", func() {
+			JustBeforeEach(func() {
+				ctx.SetRestrictedRole("VirtualMachine.Inventory.Create", "Datastore.AllocateSpace")
+			})
+
+			It("reports the missing privileges on the configured objects", func() {
+				missing, err := vmProvider.GetMissingPrivileges(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(missing).To(HaveLen(1))
+
+				for entity, privileges := range missing {
+					Expect(entity.Type).To(Equal("ResourcePool"))
+					Expect(privileges).To(ConsistOf("Resource.AssignVMToPool", "Resource.CreatePool"))
+				}
+			})
+		})
+	})
+}
+
 func initOvfCacheAndLockPoolTests() {
 
 	var (
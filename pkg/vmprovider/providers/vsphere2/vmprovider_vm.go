@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/session"
 
@@ -16,7 +17,10 @@ import (
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	imgregv1a1 "github.com/vmware-tanzu/image-registry-operator-api/api/v1alpha1"
@@ -26,13 +30,19 @@ import (
 	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	metrics "github.com/vmware-tanzu/vm-operator/pkg/metrics2"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
 	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
+	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/contentlibrary"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/placement"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/resources"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/storage"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
@@ -76,6 +86,20 @@ func (vs *vSphereVMProvider) CreateOrUpdateVirtualMachine(
 	ctx goctx.Context,
 	vm *vmopv1.VirtualMachine) error {
 
+	return providererrors.Classify(vs.createOrUpdateVirtualMachine(ctx, vm))
+}
+
+func (vs *vSphereVMProvider) DeleteVirtualMachine(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) error {
+
+	return providererrors.Classify(vs.deleteVirtualMachine(ctx, vm))
+}
+
+func (vs *vSphereVMProvider) createOrUpdateVirtualMachine(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) error {
+
 	vmCtx := context.VirtualMachineContextA2{
 		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "createOrUpdateVM")),
 		Logger:  log.WithValues("vmName", vm.NamespacedName()),
@@ -87,14 +111,35 @@ func (vs *vSphereVMProvider) CreateOrUpdateVirtualMachine(
 		return err
 	}
 
+	// A dry run of the VM's reconfigure always records the reconfigure it would make.
+	_, dryRunReconfigure := vmCtx.VM.Annotations[constants.DryRunReconfigureAnnotation]
+
+	if !client.Config().ReadOnly && !dryRunReconfigure && vs.fastPathUpdateVirtualMachine(vmCtx, client) {
+		vmCtx.Logger.V(4).Info("VirtualMachine is unchanged so skipping full reconcile")
+		vs.reconcileMetrics.RegisterReconcile(metrics.ReconcileFastPath)
+		return nil
+	}
+
 	vcVM, err := vs.getVM(vmCtx, client, false)
 	if err != nil {
 		return err
 	}
 
+	// A VM with a dry run of its reconfigure is not created since it does not exist to reconfigure yet.
+	if client.Config().ReadOnly || (vcVM == nil && dryRunReconfigure) {
+		return vs.dryRunVirtualMachine(vmCtx, vcVM, client)
+	}
+	conditions.Delete(vmCtx.VM, vmopv1.VirtualMachineConditionDryRun)
+
 	if vcVM == nil {
 		var createArgs *VMCreateArgs
 
+		allowed, opDoneFn := vs.beginVMOperation(vmCtx, vmOperationCreate)
+		if !allowed {
+			return providererrors.NewTransient(fmt.Errorf("create of VirtualMachine %s is already in progress", vmCtx.VM.NamespacedName()))
+		}
+		defer opDoneFn()
+
 		vcVM, createArgs, err = vs.createVirtualMachine(vmCtx, client)
 		if err != nil {
 			return err
@@ -106,18 +151,610 @@ func (vs *vSphereVMProvider) CreateOrUpdateVirtualMachine(
 			return nil
 		}
 
-		return vs.createdVirtualMachineFallthroughUpdate(vmCtx, vcVM, client, createArgs)
+		vs.reconcileMetrics.RegisterReconcile(metrics.ReconcileFull)
+		if err := vs.createdVirtualMachineFallthroughUpdate(vmCtx, vcVM, client, createArgs); err != nil {
+			vs.forgetReconciledVM(vmCtx.VM)
+			return err
+		}
+
+		vs.recordFullReconcile(vmCtx, client)
+		return nil
 	}
 
-	return vs.updateVirtualMachine(vmCtx, vcVM, client, nil)
+	allowed, opDoneFn := vs.beginVMOperation(vmCtx, vmOperationUpdate)
+	if !allowed {
+		return providererrors.NewTransient(fmt.Errorf("update of VirtualMachine %s is already in progress", vmCtx.VM.NamespacedName()))
+	}
+	defer opDoneFn()
+
+	vs.reconcileMetrics.RegisterReconcile(metrics.ReconcileFull)
+	if err := vs.updateVirtualMachine(vmCtx, vcVM, client, nil); err != nil {
+		vs.forgetReconciledVM(vmCtx.VM)
+		return err
+	}
+
+	if dryRunReconfigure {
+		// The VM was not reconciled to match its spec.
+		vs.forgetReconciledVM(vmCtx.VM)
+		return nil
+	}
+
+	vs.recordFullReconcile(vmCtx, client)
+	return nil
 }
 
-func (vs *vSphereVMProvider) DeleteVirtualMachine(
+func (vs *vSphereVMProvider) deleteVirtualMachine(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) error {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "deleteVM")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	vs.forgetReconciledVM(vm)
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		// Fail the delete so the VirtualMachine's finalizer is kept, and the delete is retried
+		// once the provider is no longer read-only.
+		vmCtx.Logger.Info("Provider is read-only so skipping delete of VirtualMachine")
+		return ErrReadOnly
+	}
+
+	orphan := vm.Annotations[vmopv1.DeletePolicyAnnotation] == vmopv1.DeletePolicyOrphan
+
+	if !orphan {
+		// If the VM is still being created, cancel the create instead of leaving it to finish
+		// and orphan the VM.
+		vs.cancelInFlightVMCreate(vmCtx, client)
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, false)
+	if err != nil {
+		return err
+	}
+
+	if vcVM != nil {
+		if orphan {
+			vmCtx.Logger.Info("Releasing VM instead of deleting it per its delete policy")
+			return virtualmachine.ReleaseVirtualMachine(vmCtx, vcVM)
+		}
+
+		if destroyTime := vm.Status.DestroyTime; destroyTime != nil && time.Now().Before(destroyTime.Time) {
+			vmCtx.Logger.Info("Powering off VM until its deletion grace period expires",
+				"destroyTime", destroyTime.Format(time.RFC3339))
+			return virtualmachine.PowerOffVirtualMachineUntilDestroy(vmCtx, vcVM, destroyTime.Time)
+		}
+
+		if err := virtualmachine.DeleteVirtualMachine(vmCtx, vcVM); err != nil {
+			return err
+		}
+	}
+
+	if gracePeriod := lib.GetNetworkInterfaceDeleteGracePeriod(); gracePeriod > 0 && !orphan {
+		// Any CRs that fail to be retained are still garbage collected with the VM.
+		if err := network.RetainNetworkInterfaces(vmCtx, vs.k8sClient, gracePeriod); err != nil {
+			vmCtx.Logger.Error(err, "Failed to retain network interfaces")
+		}
+	}
+
+	return nil
+}
+
+func (vs *vSphereVMProvider) PublishVirtualMachine(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	vmPub *vmopv1.VirtualMachinePublishRequest,
+	cl *imgregv1a1.ContentLibrary,
+	actID string) (string, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: ctx,
+		// Update logger info
+		Logger: log.WithValues("vmName", vm.NamespacedName()).
+			WithValues("clName", fmt.Sprintf("%s/%s", cl.Namespace, cl.Name)).
+			WithValues("vmPubName", fmt.Sprintf("%s/%s", vmPub.Namespace, vmPub.Name)),
+		VM: vm,
+	}
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get vCenter client")
+	}
+
+	if client.Config().ReadOnly {
+		return "", ErrReadOnly
+	}
+
+	itemID, err := virtualmachine.CreateOVF(vmCtx, client.RestClient(), vmPub, cl, actID)
+	if err != nil {
+		return "", err
+	}
+
+	return itemID, nil
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineGuestHeartbeat(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (vmopv1.GuestHeartbeatStatus, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "heartbeat")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return "", err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := virtualmachine.GetGuestHeartBeatStatus(vmCtx, vcVM)
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineWebMKSTicket(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	pubKey string) (string, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "webconsole")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return "", err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return "", err
+	}
+
+	ticket, err := virtualmachine.GetWebConsoleTicket(vmCtx, vcVM, pubKey)
+	if err != nil {
+		return "", err
+	}
+
+	return ticket, nil
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineHardwareVersion(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (int32, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "hardware-version")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return 0, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var o mo.VirtualMachine
+	err = vcVM.Properties(vmCtx, vcVM.Reference(), []string{"config.version"}, &o)
+	if err != nil {
+		return 0, err
+	}
+
+	return contentlibrary.ParseVirtualHardwareVersion(o.Config.Version), nil
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineHardwareVersionUpgradeTargets(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (int32, []int32, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "hardware-version-upgrade")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return virtualmachine.GetHardwareVersionUpgradeTargets(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineStatusProperties(
+	ctx goctx.Context,
+	vmRefs []types.ManagedObjectReference) (map[types.ManagedObjectReference]mo.VirtualMachine, error) {
+
+	client, err := vs.getVcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return vmlifecycle.GetStatusProperties(ctx, client.VimClient(), vmRefs)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineCryptoKeyProvider(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (string, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "crypto")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return "", err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return "", err
+	}
+
+	return virtualmachine.GetCryptoKeyProvider(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineDeviceConnectionStatus(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineDeviceStatus, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "devices")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return virtualmachine.GetVirtualMachineDeviceConnectionStatus(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineTags(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineTagStatus, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "tags")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return virtualmachine.GetTagStatus(vmCtx, client.RestClient(), vcVM.Reference())
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineResourceAllocation(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (*vmopv1.VirtualMachineResourceAllocationStatus, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "resourceAllocation")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return virtualmachine.GetResourceAllocationStatus(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineGuestDisks(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) ([]vmopv1.VirtualMachineGuestDiskStatus, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "guestDisks")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return virtualmachine.GetVirtualMachineGuestDiskStatus(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineIdentity(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineIdentity, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "identity")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return vmprovider.VirtualMachineIdentity{}, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return vmprovider.VirtualMachineIdentity{}, err
+	}
+
+	biosUUID, instanceUUID, err := virtualmachine.GetUUIDs(vmCtx, vcVM)
+	if err != nil {
+		return vmprovider.VirtualMachineIdentity{}, err
+	}
+
+	return vmprovider.VirtualMachineIdentity{
+		VM:           vcVM.Reference(),
+		BiosUUID:     biosUUID,
+		InstanceUUID: instanceUUID,
+	}, nil
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineFileLayout(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (string, []types.VirtualMachineFileLayoutExFileInfo, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "fileLayout")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return virtualmachine.GetFileLayout(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) GetVirtualMachineHotPlug(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (vmprovider.HotPlug, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "getHotPlug")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return vmprovider.HotPlug{}, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return vmprovider.HotPlug{}, err
+	}
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), vmHotPlugProperties, &o); err != nil {
+		return vmprovider.HotPlug{}, err
+	}
+
+	return getHotPlug(o.Config), nil
+}
+
+// SetVirtualMachineHotPlug changes whether CPUs and memory can be added to, and CPUs removed from,
+// the VM while it is powered on. The VM must be powered off for the change.
+func (vs *vSphereVMProvider) SetVirtualMachineHotPlug(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	hotPlug vmprovider.HotPlug) error {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "setHotPlug")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return err
+	}
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), append(vmHotPlugProperties, "runtime.powerState"), &o); err != nil {
+		return err
+	}
+
+	current := getHotPlug(o.Config)
+	if current == hotPlug {
+		return nil
+	}
+
+	if o.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+		return fmt.Errorf("hot plug can only be changed while the VM is powered off")
+	}
+
+	configSpec := &types.VirtualMachineConfigSpec{}
+	if current.CPUHotAdd != hotPlug.CPUHotAdd {
+		configSpec.CpuHotAddEnabled = &hotPlug.CPUHotAdd
+	}
+	if current.CPUHotRemove != hotPlug.CPUHotRemove {
+		configSpec.CpuHotRemoveEnabled = &hotPlug.CPUHotRemove
+	}
+	if current.MemoryHotAdd != hotPlug.MemoryHotAdd {
+		configSpec.MemoryHotAddEnabled = &hotPlug.MemoryHotAdd
+	}
+
+	vmCtx.Logger.Info("Setting VM hot plug", "configSpec", configSpec)
+	return resources.NewVMFromObject(vcVM).Reconfigure(vmCtx, configSpec)
+}
+
+func (vs *vSphereVMProvider) MarkAsTemplate(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) error {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "markAsTemplate")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return err
+	}
+
+	return virtualmachine.MarkAsTemplate(vmCtx, vcVM)
+}
+
+func (vs *vSphereVMProvider) MarkAsVirtualMachine(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	resourcePoolMoID string) error {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "markAsVirtualMachine")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return err
+	}
+
+	rp, err := vcenter.GetResourcePoolByMoID(vmCtx, client.Finder(), resourcePoolMoID)
+	if err != nil {
+		return fmt.Errorf("failed to get ResourcePool %s: %w", resourcePoolMoID, err)
+	}
+
+	return virtualmachine.MarkAsVirtualMachine(vmCtx, vcVM, rp)
+}
+
+var vmHotPlugProperties = []string{"config.cpuHotAddEnabled", "config.cpuHotRemoveEnabled", "config.memoryHotAddEnabled"}
+
+func getHotPlug(config *types.VirtualMachineConfigInfo) vmprovider.HotPlug {
+	if config == nil {
+		return vmprovider.HotPlug{}
+	}
+
+	return vmprovider.HotPlug{
+		CPUHotAdd:    pointer.BoolDeref(config.CpuHotAddEnabled, false),
+		CPUHotRemove: pointer.BoolDeref(config.CpuHotRemoveEnabled, false),
+		MemoryHotAdd: pointer.BoolDeref(config.MemoryHotAddEnabled, false),
+	}
+}
+
+func (vs *vSphereVMProvider) ConnectVirtualMachineDevice(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	deviceKey int32) error {
+
+	return vs.setVirtualMachineDeviceConnected(ctx, vm, deviceKey, true)
+}
+
+func (vs *vSphereVMProvider) DisconnectVirtualMachineDevice(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	deviceKey int32) error {
+
+	return vs.setVirtualMachineDeviceConnected(ctx, vm, deviceKey, false)
+}
+
+// DetachAndDeleteVirtualMachineDisk removes the VM's disk with the device key from the VM and,
+// when deleteFile is true, deletes the disk's backing file.
+func (vs *vSphereVMProvider) DetachAndDeleteVirtualMachineDisk(
 	ctx goctx.Context,
-	vm *vmopv1.VirtualMachine) error {
+	vm *vmopv1.VirtualMachine,
+	diskKey int32,
+	deleteFile bool) error {
 
 	vmCtx := context.VirtualMachineContextA2{
-		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "deleteVM")),
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "deleteDisk")),
 		Logger:  log.WithValues("vmName", vm.NamespacedName()),
 		VM:      vm,
 	}
@@ -127,130 +764,230 @@ func (vs *vSphereVMProvider) DeleteVirtualMachine(
 		return err
 	}
 
-	vcVM, err := vs.getVM(vmCtx, client, false)
+	if client.Config().ReadOnly {
+		return ErrReadOnly
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
 	if err != nil {
 		return err
-	} else if vcVM == nil {
-		// VM does not exist.
-		return nil
 	}
 
-	return virtualmachine.DeleteVirtualMachine(vmCtx, vcVM)
+	vmCtx.Logger.Info("Detaching VM disk", "diskKey", diskKey, "deleteFile", deleteFile)
+	return virtualmachine.DetachAndDeleteDisk(vmCtx, vcVM, diskKey, deleteFile)
 }
 
-func (vs *vSphereVMProvider) PublishVirtualMachine(
+func (vs *vSphereVMProvider) UpdateVirtualMachineTaskStatus(
 	ctx goctx.Context,
-	vm *vmopv1.VirtualMachine,
-	vmPub *vmopv1.VirtualMachinePublishRequest,
-	cl *imgregv1a1.ContentLibrary,
-	actID string) (string, error) {
+	vm *vmopv1.VirtualMachine) error {
 
 	vmCtx := context.VirtualMachineContextA2{
-		Context: ctx,
-		// Update logger info
-		Logger: log.WithValues("vmName", vm.NamespacedName()).
-			WithValues("clName", fmt.Sprintf("%s/%s", cl.Namespace, cl.Name)).
-			WithValues("vmPubName", fmt.Sprintf("%s/%s", vmPub.Namespace, vmPub.Name)),
-		VM: vm,
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "taskStatus")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
 	}
 
-	client, err := vs.getVcClient(ctx)
+	client, err := vs.getVcClient(vmCtx)
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to get vCenter client")
+		return err
 	}
 
-	itemID, err := virtualmachine.CreateOVF(vmCtx, client.RestClient(), vmPub, cl, actID)
+	taskStatus, err := vs.getInFlightVMTaskStatus(vmCtx, client)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return itemID, nil
+	vm.Status.Task = taskStatus
+	return nil
 }
 
-func (vs *vSphereVMProvider) GetVirtualMachineGuestHeartbeat(
+func (vs *vSphereVMProvider) ReapplyVirtualMachineCustomization(
 	ctx goctx.Context,
-	vm *vmopv1.VirtualMachine) (vmopv1.GuestHeartbeatStatus, error) {
+	vm *vmopv1.VirtualMachine) error {
 
 	vmCtx := context.VirtualMachineContextA2{
-		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "heartbeat")),
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "reapplyCustomization")),
 		Logger:  log.WithValues("vmName", vm.NamespacedName()),
 		VM:      vm,
 	}
 
 	client, err := vs.getVcClient(vmCtx)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	if client.Config().ReadOnly {
+		return ErrReadOnly
 	}
 
 	vcVM, err := vs.getVM(vmCtx, client, true)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	status, err := virtualmachine.GetGuestHeartBeatStatus(vmCtx, vcVM)
+	cluster, err := virtualmachine.GetVMClusterComputeResource(vmCtx, vcVM)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return status, nil
+	ses := &session.Session{
+		K8sClient: vs.k8sClient,
+		Client:    client,
+		Finder:    client.Finder(),
+		Recorder:  vs.eventRecorder,
+		TagCache:  vs.tagCache,
+		Cluster:   cluster,
+	}
+
+	getUpdateArgsFn := func() (*vmUpdateArgs, error) {
+		return vs.vmUpdateGetArgs(vmCtx, client)
+	}
+
+	return ses.ReapplyCustomization(vmCtx, vcVM, getUpdateArgsFn)
 }
 
-func (vs *vSphereVMProvider) GetVirtualMachineWebMKSTicket(
+func (vs *vSphereVMProvider) setVirtualMachineDeviceConnected(
 	ctx goctx.Context,
 	vm *vmopv1.VirtualMachine,
-	pubKey string) (string, error) {
+	deviceKey int32,
+	connected bool) error {
 
 	vmCtx := context.VirtualMachineContextA2{
-		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "webconsole")),
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "connectDevice")),
 		Logger:  log.WithValues("vmName", vm.NamespacedName()),
 		VM:      vm,
 	}
 
 	client, err := vs.getVcClient(vmCtx)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	vcVM, err := vs.getVM(vmCtx, client, true)
-	if err != nil {
-		return "", err
+	if client.Config().ReadOnly {
+		return ErrReadOnly
 	}
 
-	ticket, err := virtualmachine.GetWebConsoleTicket(vmCtx, vcVM, pubKey)
+	vcVM, err := vs.getVM(vmCtx, client, true)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return ticket, nil
+	vmCtx.Logger.Info("Setting VM device connected state", "deviceKey", deviceKey, "connected", connected)
+	return virtualmachine.SetDeviceConnected(vmCtx, vcVM, deviceKey, connected)
 }
 
-func (vs *vSphereVMProvider) GetVirtualMachineHardwareVersion(
-	ctx goctx.Context,
-	vm *vmopv1.VirtualMachine) (int32, error) {
+// dryRunVirtualMachine validates the VM when the provider is read-only, or when the VM does not exist
+// yet and has a dry run of its reconfigure, and records the change that would have been made to the
+// VM instead of making it. Only the VM's status is updated.
+func (vs *vSphereVMProvider) dryRunVirtualMachine(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	vcClient *vcclient.Client) error {
 
-	vmCtx := context.VirtualMachineContextA2{
-		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "hardware-version")),
-		Logger:  log.WithValues("vmName", vm.NamespacedName()),
-		VM:      vm,
+	if vcVM == nil {
+		if _, err := vs.vmCreateGetDryRunArgs(vmCtx, vcClient); err != nil {
+			return err
+		}
+
+		vmCtx.Logger.Info("Dry run so skipping create of VirtualMachine", "readOnly", vcClient.Config().ReadOnly,
+			"imageName", vmCtx.VM.Spec.ImageName, "className", vmCtx.VM.Spec.ClassName)
+		conditions.Set(vmCtx.VM, &metav1.Condition{
+			Type:    vmopv1.VirtualMachineConditionDryRun,
+			Status:  metav1.ConditionTrue,
+			Reason:  vmopv1.VirtualMachineDryRunWouldCreateReason,
+			Message: fmt.Sprintf("VM would be created from image %s with class %s", vmCtx.VM.Spec.ImageName, vmCtx.VM.Spec.ClassName),
+		})
+		return nil
 	}
 
-	client, err := vs.getVcClient(vmCtx)
+	cluster, err := virtualmachine.GetVMClusterComputeResource(vmCtx, vcVM)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	vcVM, err := vs.getVM(vmCtx, client, true)
-	if err != nil {
-		return 0, err
+	ses := &session.Session{
+		K8sClient: vs.k8sClient,
+		Client:    vcClient,
+		Finder:    vcClient.Finder(),
+		Recorder:  vs.eventRecorder,
+		TagCache:  vs.tagCache,
+		Cluster:   cluster,
+		ReadOnly:  true,
 	}
 
-	var o mo.VirtualMachine
-	err = vcVM.Properties(vmCtx, vcVM.Reference(), []string{"config.version"}, &o)
-	if err != nil {
-		return 0, err
+	getUpdateArgsFn := func() (*vmUpdateArgs, error) {
+		return vs.vmUpdateGetArgs(vmCtx, vcClient)
 	}
 
-	return contentlibrary.ParseVirtualHardwareVersion(o.Config.Version), nil
+	// The dry run of the update records the changes it would make in the VM's status.
+	if err := ses.UpdateVirtualMachine(vmCtx, vcVM, getUpdateArgsFn); err != nil {
+		return err
+	}
+
+	changes := dryRunPlanChanges(vmCtx.VM.Status.ReconfigurePlan)
+	vmCtx.Logger.Info("Provider is read-only so skipping update of VirtualMachine", "changes", changes)
+
+	if len(changes) == 0 {
+		conditions.Set(vmCtx.VM, &metav1.Condition{
+			Type:    vmopv1.VirtualMachineConditionDryRun,
+			Status:  metav1.ConditionTrue,
+			Reason:  vmopv1.VirtualMachineDryRunNoChangeReason,
+			Message: "VM already matches its spec",
+		})
+		return nil
+	}
+
+	conditions.Set(vmCtx.VM, &metav1.Condition{
+		Type:    vmopv1.VirtualMachineConditionDryRun,
+		Status:  metav1.ConditionTrue,
+		Reason:  vmopv1.VirtualMachineDryRunWouldUpdateReason,
+		Message: fmt.Sprintf("VM would be updated: %s", strings.Join(changes, ", ")),
+	})
+	return nil
+}
+
+// dryRunPlanChanges returns the descriptions of the changes in the plan of the dry run of the update.
+func dryRunPlanChanges(plan *vmopv1.VirtualMachineReconfigurePlan) []string {
+	if plan == nil {
+		return nil
+	}
+
+	var changes []string
+	if plan.PowerState != "" {
+		changes = append(changes, fmt.Sprintf("power state %s", plan.PowerState))
+	}
+	if len(plan.ConfigKeys) > 0 {
+		changes = append(changes, fmt.Sprintf("config %s", strings.Join(plan.ConfigKeys, " ")))
+	}
+	if len(plan.ExtraConfigKeys) > 0 {
+		changes = append(changes, fmt.Sprintf("extraConfig %s", strings.Join(plan.ExtraConfigKeys, " ")))
+	}
+	if n := len(plan.AddedDevices); n > 0 {
+		changes = append(changes, fmt.Sprintf("%d added devices", n))
+	}
+	if n := len(plan.RemovedDevices); n > 0 {
+		changes = append(changes, fmt.Sprintf("%d removed devices", n))
+	}
+	if n := len(plan.EditedDevices); n > 0 {
+		changes = append(changes, fmt.Sprintf("%d edited devices", n))
+	}
+	if plan.HardwareVersion != 0 {
+		changes = append(changes, fmt.Sprintf("hardware version %d", plan.HardwareVersion))
+	}
+	if len(plan.ClusterConfigSpecs) > 0 {
+		changes = append(changes, "cluster config")
+	}
+	if plan.DisplayName != "" {
+		changes = append(changes, fmt.Sprintf("display name %s", plan.DisplayName))
+	}
+	if plan.BootDiskStorageProfileID != "" {
+		changes = append(changes, fmt.Sprintf("boot disk storage profile %s", plan.BootDiskStorageProfileID))
+	}
+	if n := len(plan.AttachedTags) + len(plan.DetachedTags); n > 0 {
+		changes = append(changes, fmt.Sprintf("%d tag changes", n))
+	}
+
+	return changes
 }
 
 func (vs *vSphereVMProvider) createVirtualMachine(
@@ -267,9 +1004,11 @@ func (vs *vSphereVMProvider) createVirtualMachine(
 		return nil, nil, err
 	}
 
-	err = vs.vmCreateGetFolderAndRPMoIDs(vmCtx, vcClient, createArgs)
-	if err != nil {
-		return nil, nil, err
+	if createArgs.ClusterMoRef.Value == "" {
+		err = vs.vmCreateGetFolderAndRPMoIDs(vmCtx, vcClient, createArgs)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	err = vs.vmCreateFixupConfigSpec(vmCtx, vcClient, createArgs)
@@ -293,9 +1032,32 @@ func (vs *vSphereVMProvider) createVirtualMachine(
 	}
 	defer createDeferFn()
 
+	if createArgs.UseContentLibrary {
+		createArgs.CachedImageVMMoIDs = vs.getCachedImageVMMoIDs(vmCtx, vcClient, createArgs.ProviderItemID)
+	}
+
+	createArgs.TrackTaskFn = func(task types.ManagedObjectReference) func() {
+		untrackFn := vs.trackVMOperationTask(vmCtx, vmOperationCreate, task)
+		unwatchFn := vs.watchVMOperationTask(vmCtx, vcClient, vmOperationCreate, task)
+		return func() {
+			unwatchFn()
+			untrackFn()
+		}
+	}
+
+	createArgs.TrackDeployFn = func(description string) func() {
+		untrackFn := vs.trackVMOperationDescription(vmCtx, vmOperationCreate, description)
+		unreportFn := vs.reportVMOperation(vmCtx, vmOperationCreate, description)
+		return func() {
+			unreportFn()
+			untrackFn()
+		}
+	}
+
 	moRef, err := vmlifecycle.CreateVirtualMachine(
 		vmCtx,
 		vcClient.ContentLibClient(),
+		vcClient.VimClient(),
 		vcClient.RestClient(),
 		vcClient.Finder(),
 		&createArgs.CreateArgs)
@@ -305,7 +1067,31 @@ func (vs *vSphereVMProvider) createVirtualMachine(
 		return nil, nil, err
 	}
 
+	if createArgs.CachedImageDatastoreMoID != "" {
+		vs.eventRecorder.Eventf(vmCtx.VM, "ImageCacheHit",
+			"Created VM from image cached to datastore %s", createArgs.CachedImageDatastoreMoID)
+	}
+
+	if vs.isVMOperationCancelled(vmCtx, vmOperationCreate) {
+		// The VM was deleted while it was being created, and the create could not be cancelled.
+		vmCtx.Logger.Info("Destroying VM that was deleted while it was being created", "moRef", moRef.Value)
+		if err := virtualmachine.DeleteVirtualMachine(vmCtx, object.NewVirtualMachine(vcClient.VimClient(), *moRef)); err != nil {
+			return nil, nil, fmt.Errorf("failed to destroy VM %s that was deleted while it was being created: %w", moRef.Value, err)
+		}
+		return nil, nil, fmt.Errorf("VM was deleted while it was being created")
+	}
+
 	vmCtx.VM.Status.UniqueID = moRef.Reference().Value
+
+	// Report the VM's identity right away so that volumes can be attached even if the update
+	// that follows the create fails before the status is updated.
+	vcVM := object.NewVirtualMachine(vcClient.VimClient(), *moRef)
+	if biosUUID, instanceUUID, err := virtualmachine.GetUUIDs(vmCtx, vcVM); err != nil {
+		vmCtx.Logger.Error(err, "Failed to get VM UUIDs after create")
+	} else {
+		vmCtx.VM.Status.BiosUUID = biosUUID
+		vmCtx.VM.Status.InstanceUUID = instanceUUID
+	}
 	vmCtx.VM.Status.Image = &common.LocalObjectRef{
 		APIVersion: vmopv1.SchemeGroupVersion.String(),
 		Kind:       createArgs.ImageObj.GetObjectKind().GroupVersionKind().Kind,
@@ -318,7 +1104,7 @@ func (vs *vSphereVMProvider) createVirtualMachine(
 	}
 	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionCreated)
 
-	return object.NewVirtualMachine(vcClient.VimClient(), *moRef), createArgs, nil
+	return vcVM, createArgs, nil
 }
 
 func (vs *vSphereVMProvider) createdVirtualMachineFallthroughUpdate(
@@ -333,6 +1119,54 @@ func (vs *vSphereVMProvider) createdVirtualMachineFallthroughUpdate(
 	return vs.updateVirtualMachine(vmCtx, vcVM, vcClient, createArgs)
 }
 
+// placeAdoptedVM moves the VM into the adopted VM Folder when the VM is not in its namespace's
+// Folder, and the provider is configured to move adopted VMs. Otherwise, the VM is left in place.
+func (vs *vSphereVMProvider) placeAdoptedVM(
+	vmCtx context.VirtualMachineContextA2,
+	vcVM *object.VirtualMachine,
+	vcClient *vcclient.Client) error {
+
+	config := vcClient.Config()
+	if config.AdoptedVMPlacement != vcconfig.AdoptedVMPlacementMoveToFolder {
+		return nil
+	}
+
+	nsFolderMoID, err := topology.GetNamespaceFolderMoID(vmCtx, vs.k8sClient, vmCtx.VM.Namespace)
+	if err != nil {
+		return err
+	}
+
+	vimClient := vcClient.VimClient()
+	ancestors, err := mo.Ancestors(vmCtx, vimClient, vimClient.ServiceContent.PropertyCollector, vcVM.Reference())
+	if err != nil {
+		return err
+	}
+
+	for _, ancestor := range ancestors {
+		if ancestor.Self.Value == nsFolderMoID || ancestor.Self.Value == config.AdoptedVMFolder {
+			return nil
+		}
+	}
+
+	folder, err := vcenter.GetFolderByMoID(vmCtx, vcClient.Finder(), config.AdoptedVMFolder)
+	if err != nil {
+		return fmt.Errorf("failed to get adopted VM Folder %s: %w", config.AdoptedVMFolder, err)
+	}
+
+	vmCtx.Logger.Info("Moving adopted VM into Folder", "folderMoID", config.AdoptedVMFolder)
+
+	task, err := folder.MoveInto(vmCtx, []types.ManagedObjectReference{vcVM.Reference()})
+	if err != nil {
+		return err
+	}
+
+	if err := task.Wait(vmCtx); err != nil {
+		return fmt.Errorf("failed to move adopted VM into Folder %s: %w", config.AdoptedVMFolder, err)
+	}
+
+	return nil
+}
+
 func (vs *vSphereVMProvider) updateVirtualMachine(
 	vmCtx context.VirtualMachineContextA2,
 	vcVM *object.VirtualMachine,
@@ -341,6 +1175,10 @@ func (vs *vSphereVMProvider) updateVirtualMachine(
 
 	vmCtx.Logger.V(4).Info("Updating VirtualMachine")
 
+	if err := vs.placeAdoptedVM(vmCtx, vcVM, vcClient); err != nil {
+		return err
+	}
+
 	{
 		// Hack - create just enough of the Session that's needed for update
 
@@ -349,17 +1187,33 @@ func (vs *vSphereVMProvider) updateVirtualMachine(
 			return err
 		}
 
+		// The update of a VM that was just created is part of the in-flight create.
+		op := vmOperationUpdate
+		if createArgs != nil {
+			op = vmOperationCreate
+		}
+
 		ses := &session.Session{
 			K8sClient: vs.k8sClient,
 			Client:    vcClient,
 			Finder:    vcClient.Finder(),
+			Recorder:  vs.eventRecorder,
+			TagCache:  vs.tagCache,
 			Cluster:   cluster,
+			TrackTaskFn: func(task types.ManagedObjectReference) func() {
+				untrackFn := vs.trackVMOperationTask(vmCtx, op, task)
+				unwatchFn := vs.watchVMOperationTask(vmCtx, vcClient, op, task)
+				return func() {
+					unwatchFn()
+					untrackFn()
+				}
+			},
 		}
 
 		getUpdateArgsFn := func() (*vmUpdateArgs, error) {
 			// TODO: Use createArgs if we already got them
 			_ = createArgs
-			return vs.vmUpdateGetArgs(vmCtx)
+			return vs.vmUpdateGetArgs(vmCtx, vcClient)
 		}
 
 		err = ses.UpdateVirtualMachine(vmCtx, vcVM, getUpdateArgsFn)
@@ -372,7 +1226,7 @@ func (vs *vSphereVMProvider) updateVirtualMachine(
 	if lib.IsVMServiceBackupRestoreFSSEnabled() {
 		vmCtx.Logger.V(4).Info("Backing up VirtualMachine")
 		// TODO: Support backing up vAppConfig bootstrap data.
-		data, _, _, err := GetVirtualMachineBootstrap(vmCtx, vs.k8sClient)
+		bsData, err := GetVirtualMachineBootstrap(vmCtx, vs.k8sClient)
 		if err != nil {
 			vmCtx.Logger.Error(err, "Failed to get VM's bootstrap data for backup")
 			return err
@@ -386,7 +1240,7 @@ func (vs *vSphereVMProvider) updateVirtualMachine(
 		backupVMCtx := context.BackupVirtualMachineContextA2{
 			VMCtx:         vmCtx,
 			VcVM:          vcVM,
-			BootstrapData: data,
+			BootstrapData: bsData.Data,
 			DiskUUIDToPVC: diskUUIDToPVC,
 		}
 		if err := virtualmachine.BackupVirtualMachine(backupVMCtx); err != nil {
@@ -398,12 +1252,84 @@ func (vs *vSphereVMProvider) updateVirtualMachine(
 	return nil
 }
 
+// vmCreateCheckZoneCompatibility checks that the zones the VM may be placed in can create VMs with
+// the hardware version that the VM's image requires. The incompatible zones are returned so the VM
+// is only placed in a compatible zone. An error is returned if none of the zones are compatible.
+func (vs *vSphereVMProvider) vmCreateCheckZoneCompatibility(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client,
+	createArgs *VMCreateArgs) (map[string]struct{}, error) {
+
+	if !lib.IsWcpFaultDomainsFSSEnabled() || createArgs.ImageStatus == nil || createArgs.ImageStatus.HardwareVersion == nil {
+		return nil, nil
+	}
+
+	hardwareVersion := *createArgs.ImageStatus.HardwareVersion
+	zoneName := vmCtx.VM.Labels[topology.KubernetesTopologyZoneLabelKey]
+
+	compatibility, err := vs.getZoneCompatibility(vmCtx, vcClient, hardwareVersion, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	var compatibleCount int
+	var incompatible []string
+	excludedZones := map[string]struct{}{}
+
+	for _, c := range compatibility {
+		if c.Compatible {
+			compatibleCount++
+			continue
+		}
+
+		excludedZones[c.ZoneName] = struct{}{}
+		incompatible = append(incompatible,
+			fmt.Sprintf("%s (clusters %s)", c.ZoneName, strings.Join(c.IncompatibleClusterMoIDs, ", ")))
+	}
+
+	if len(incompatible) == 0 {
+		conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionZoneCompatible)
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("Zones cannot create VMs with the image's hardware version %d: %s",
+		hardwareVersion, strings.Join(incompatible, "; "))
+
+	if compatibleCount == 0 {
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionZoneCompatible,
+			vmopv1.VirtualMachineZoneIncompatibleReason, "%s", msg)
+		return nil, errors.New(msg)
+	}
+
+	conditions.Set(vmCtx.VM, &metav1.Condition{
+		Type:    vmopv1.VirtualMachineConditionZoneCompatible,
+		Status:  metav1.ConditionTrue,
+		Reason:  vmopv1.VirtualMachineZoneIncompatibleReason,
+		Message: msg,
+	})
+
+	return excludedZones, nil
+}
+
 // vmCreateDoPlacement determines placement of the VM prior to creating the VM on VC.
 func (vs *vSphereVMProvider) vmCreateDoPlacement(
 	vmCtx context.VirtualMachineContextA2,
 	vcClient *vcclient.Client,
 	createArgs *VMCreateArgs) error {
 
+	excludedZones, err := vs.vmCreateCheckZoneCompatibility(vmCtx, vcClient, createArgs)
+	if err != nil {
+		return err
+	}
+
+	// The instance storage volumes already failed to be placed on every candidate host, so don't
+	// retry placement until the failed hosts annotation is removed.
+	failedHostMoIDs := instancestorage.FailedHostMoIDs(vmCtx.VM)
+	if len(failedHostMoIDs) > 0 && conditions.GetReason(vmCtx.VM, vmopv1.VirtualMachineConditionInstanceStoragePlacement) ==
+		vmopv1.VirtualMachineInstanceStorageHostsExhaustedReason {
+		return placement.ErrInstanceStorageHostsExhausted
+	}
+
 	placementConfigSpec := virtualmachine.CreateConfigSpecForPlacement(
 		vmCtx,
 		createArgs.ConfigSpec,
@@ -414,8 +1340,14 @@ func (vs *vSphereVMProvider) vmCreateDoPlacement(
 		vs.k8sClient,
 		vcClient.VimClient(),
 		placementConfigSpec,
-		createArgs.ChildResourcePoolName)
+		createArgs.ChildResourcePoolName,
+		excludedZones)
 	if err != nil {
+		if errors.Is(err, placement.ErrInstanceStorageHostsExhausted) {
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionInstanceStoragePlacement,
+				vmopv1.VirtualMachineInstanceStorageHostsExhaustedReason,
+				"Instance storage volumes failed to be placed on all candidate hosts: %s", strings.Join(failedHostMoIDs, ", "))
+		}
 		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionPlacementReady, "NotReady", err.Error())
 		return err
 	}
@@ -544,7 +1476,7 @@ func (vs *vSphereVMProvider) vmCreateFixupConfigSpec(
 
 	if fixedUp {
 		// Now that the backing is resolved for this CCR, re-zip to update the ConfigSpec. What a mess.
-		err = vs.vmCreateGenConfigSpecZipNetworkInterfaces(vmCtx, createArgs)
+		err = vs.vmCreateGenConfigSpecZipNetworkInterfaces(vmCtx, vcClient, createArgs)
 		if err != nil {
 			return err
 		}
@@ -611,12 +1543,53 @@ func (vs *vSphereVMProvider) vmCreateGetArgs(
 		return nil, err
 	}
 
+	// When the VM does not need placement, its ResourcePool - and so its CCR - is already
+	// determined by its namespace and zone. Look it up before networking so the NSX-T backings
+	// are resolved by the first CreateAndWaitForNetworkInterfaces() call instead of post placement.
+	if !placement.IsPlacementNeeded(vmCtx) {
+		err = vs.vmCreateGetFolderAndRPMoIDs(vmCtx, vcClient, createArgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = vs.vmCreateDoNetworking(vmCtx, vcClient, createArgs)
 	if err != nil {
 		return nil, err
 	}
 
-	err = vs.vmCreateGenConfigSpec(vmCtx, createArgs)
+	err = vs.vmCreateGenConfigSpec(vmCtx, vcClient, createArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = vs.vmCreateValidateArgs(vmCtx, vcClient, createArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return createArgs, nil
+}
+
+// vmCreateGetDryRunArgs returns the VMCreateArgs like vmCreateGetArgs does but without the
+// networking step, since that creates the VM's network interface CRs.
+func (vs *vSphereVMProvider) vmCreateGetDryRunArgs(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) (*VMCreateArgs, error) {
+
+	createArgs, err := vs.vmCreateGetPrereqs(vmCtx, vcClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if !placement.IsPlacementNeeded(vmCtx) {
+		err = vs.vmCreateGetFolderAndRPMoIDs(vmCtx, vcClient, createArgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = vs.vmCreateGenConfigSpec(vmCtx, vcClient, createArgs)
 	if err != nil {
 		return nil, err
 	}
@@ -642,7 +1615,7 @@ func (vs *vSphereVMProvider) vmCreateGetPrereqs(
 		prereqErrs = append(prereqErrs, err)
 	}
 
-	if err := vs.vmCreateGetVirtualMachineImage(vmCtx, createArgs); err != nil {
+	if err := vs.vmCreateGetVirtualMachineImage(vmCtx, vcClient, createArgs); err != nil {
 		prereqErrs = append(prereqErrs, err)
 	}
 
@@ -699,9 +1672,10 @@ func (vs *vSphereVMProvider) vmCreateGetVirtualMachineClass(
 
 func (vs *vSphereVMProvider) vmCreateGetVirtualMachineImage(
 	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client,
 	createArgs *VMCreateArgs) error {
 
-	imageObj, imageSpec, imageStatus, err := GetVirtualMachineImageSpecAndStatus(vmCtx, vs.k8sClient)
+	imageObj, imageSpec, imageStatus, err := GetVirtualMachineImageSpecAndStatus(vmCtx, vs.k8sClient, vcClient.Config().ImageNameResolution)
 	if err != nil {
 		return err
 	}
@@ -716,6 +1690,18 @@ func (vs *vSphereVMProvider) vmCreateGetVirtualMachineImage(
 	case "ClusterContentLibraryItem", "ContentLibraryItem":
 		createArgs.UseContentLibrary = true
 		createArgs.ProviderItemID = imageStatus.ProviderItemID
+	case "VirtualMachine":
+		// The image was created from a VM template in the inventory, which is only supported
+		// when the inventory is used as the content source.
+		if !vcClient.Config().UseInventoryAsContentSource {
+			err := fmt.Errorf("image provider kind %s requires the inventory to be used as the content source",
+				imageSpec.ProviderRef.Kind)
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady, "NotSupported", err.Error())
+			return err
+		}
+		createArgs.UseContentLibrary = false
+		createArgs.ProviderItemID = imageStatus.ProviderItemID
+		createArgs.InventoryTemplateMoID = imageStatus.ProviderItemID
 	default:
 		if !SkipVMImageCLProviderCheck {
 			err := fmt.Errorf("unsupported image provider kind: %s", imageSpec.ProviderRef.Kind)
@@ -753,14 +1739,12 @@ func (vs *vSphereVMProvider) vmCreateGetBootstrap(
 	vmCtx context.VirtualMachineContextA2,
 	createArgs *VMCreateArgs) error {
 
-	data, vAppData, vAppExData, err := GetVirtualMachineBootstrap(vmCtx, vs.k8sClient)
+	bsData, err := GetVirtualMachineBootstrap(vmCtx, vs.k8sClient)
 	if err != nil {
 		return err
 	}
 
-	createArgs.BootstrapData.Data = data
-	createArgs.BootstrapData.VAppData = vAppData
-	createArgs.BootstrapData.VAppExData = vAppExData
+	createArgs.BootstrapData = bsData
 
 	return nil
 }
@@ -802,6 +1786,9 @@ func (vs *vSphereVMProvider) vmCreateGetStoragePrereqs(
 	createArgs.StorageClassesToIDs = storageClassesToIDs
 	createArgs.StorageProvisioning = provisioningType
 	createArgs.StorageProfileID = vmStorageProfileID
+	if adv := vmCtx.VM.Spec.Advanced; adv != nil && adv.BootDiskStorageClass != "" {
+		createArgs.BootDiskStorageProfileID = storageClassesToIDs[adv.BootDiskStorageClass]
+	}
 	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionStorageReady)
 
 	return nil
@@ -817,14 +1804,30 @@ func (vs *vSphereVMProvider) vmCreateDoNetworking(
 		return nil
 	}
 
+	// Unless the VM does not need placement, we don't know the CCR yet (needed to resolve
+	// backings for NSX-T) so those backings are resolved post placement.
+	var clusterMoRef *types.ManagedObjectReference
+	if createArgs.ClusterMoRef.Value != "" {
+		clusterMoRef = &createArgs.ClusterMoRef
+	}
+
 	results, err := network.CreateAndWaitForNetworkInterfaces(
 		vmCtx,
 		vs.k8sClient,
 		vcClient.VimClient(),
 		vcClient.Finder(),
-		nil, // Don't know the CCR yet (needed to resolve backings for NSX-T)
+		clusterMoRef,
 		networkSpec.Interfaces)
 	if err != nil {
+		var interfaceErrs network.NetworkInterfaceErrors
+		if errors.As(err, &interfaceErrs) {
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionNetworkReady,
+				vmopv1.VirtualMachineNetworkInterfaceNotReadyReason, "%s", err.Error())
+			vmCtx.Logger.Error(err, "Failed to resolve the network of the VM's network interfaces",
+				"failedInterfaces", interfaceErrs.InterfaceNames(), "readyInterfaces", len(results.Results))
+			return err
+		}
+
 		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionNetworkReady, "NotReady", err.Error())
 		return err
 	}
@@ -837,6 +1840,7 @@ func (vs *vSphereVMProvider) vmCreateDoNetworking(
 
 func (vs *vSphereVMProvider) vmCreateGenConfigSpec(
 	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client,
 	createArgs *VMCreateArgs) error {
 
 	// TODO: This is a partial dupe of what's done in the update path in the remaining Session code. I got
@@ -894,7 +1898,7 @@ func (vs *vSphereVMProvider) vmCreateGenConfigSpec(
 		return err
 	}
 
-	err = vs.vmCreateGenConfigSpecZipNetworkInterfaces(vmCtx, createArgs)
+	err = vs.vmCreateGenConfigSpecZipNetworkInterfaces(vmCtx, vcClient, createArgs)
 	if err != nil {
 		return err
 	}
@@ -928,6 +1932,9 @@ func (vs *vSphereVMProvider) vmCreateGenConfigSpecExtraConfig(
 
 	hasPassthroughDevices := len(util.SelectVirtualPCIPassthrough(util.DevicesFromConfigSpec(createArgs.ConfigSpec))) > 0
 
+	// Mark the VM as created by VM Operator for the VM so it can be found if the VM is orphaned.
+	ecMap[constants.VMNamespacedNameExtraConfigKey] = vmCtx.VM.NamespacedName()
+
 	if hasPassthroughDevices || createArgs.HasInstanceStorage {
 		ecMap[constants.MMPowerOffVMExtraConfigKey] = constants.ExtraConfigTrue
 	}
@@ -969,6 +1976,7 @@ func (vs *vSphereVMProvider) vmCreateGenConfigSpecChangeBootDiskSize(
 
 func (vs *vSphereVMProvider) vmCreateGenConfigSpecZipNetworkInterfaces(
 	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client,
 	createArgs *VMCreateArgs) error {
 
 	if vmCtx.VM.Spec.Network.Disabled {
@@ -1016,7 +2024,8 @@ func (vs *vSphereVMProvider) vmCreateGenConfigSpecZipNetworkInterfaces(
 	// Any remaining VM Spec network interfaces were not matched with a device in the ConfigSpec, so
 	// create a default virtual ethernet card for them.
 	for i := resultsIdx; i < len(createArgs.NetworkResults.Results); i++ {
-		ethCardDev, err := network.CreateDefaultEthCard(vmCtx, &createArgs.NetworkResults.Results[i])
+		ethCardDev, err := network.CreateDefaultEthCard(vmCtx, &createArgs.NetworkResults.Results[i],
+			vcClient.Config().DefaultEthernetCardType)
 		if err != nil {
 			return err
 		}
@@ -1068,11 +2077,34 @@ func (vs *vSphereVMProvider) vmCreateValidateArgs(
 		createArgs.DatastoreMoID = datastore.Reference().Value
 	}
 
+	if providerID := vmCtx.VM.Annotations[constants.CryptoKeyProviderAnnotation]; providerID != "" {
+		if err := vcenter.ValidateCryptoKeyProvider(vmCtx, vcClient.VimClient(), providerID); err != nil {
+			return err
+		}
+	}
+
+	if errs := validateVAppPropertiesAgainstImage(field.NewPath("spec"), vmCtx.VM.Spec.VAppProperties, createArgs.ImageStatus); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	if networkSpec := vmCtx.VM.Spec.Network; networkSpec != nil && !networkSpec.Disabled {
+		// The guest may still have a driver for a type its image does not declare, so a mismatch is
+		// only reported and does not fail the create.
+		if err := ValidateImageNetworkInterfaceTypes(createArgs.ImageStatus.NetworkInterfaceTypes, createArgs.ConfigSpec); err != nil {
+			vmCtx.Logger.Info("Network interface type is not declared by the image", "reason", err.Error())
+			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionNetworkInterfaceCompatible,
+				vmopv1.VirtualMachineNetworkInterfaceIncompatibleReason, err.Error())
+		} else {
+			conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionNetworkInterfaceCompatible)
+		}
+	}
+
 	return nil
 }
 
 func (vs *vSphereVMProvider) vmUpdateGetArgs(
-	vmCtx context.VirtualMachineContextA2) (*vmUpdateArgs, error) {
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) (*vmUpdateArgs, error) {
 
 	vmClass, err := GetVirtualMachineClass(vmCtx, vs.k8sClient)
 	if err != nil {
@@ -1084,7 +2116,7 @@ func (vs *vSphereVMProvider) vmUpdateGetArgs(
 		return nil, err
 	}
 
-	data, vAppData, vAppExData, err := GetVirtualMachineBootstrap(vmCtx, vs.k8sClient)
+	bsData, err := GetVirtualMachineBootstrap(vmCtx, vs.k8sClient)
 	if err != nil {
 		return nil, err
 	}
@@ -1092,9 +2124,7 @@ func (vs *vSphereVMProvider) vmUpdateGetArgs(
 	updateArgs := &vmUpdateArgs{}
 	updateArgs.VMClass = vmClass
 	updateArgs.ResourcePolicy = resourcePolicy
-	updateArgs.BootstrapData.Data = data
-	updateArgs.BootstrapData.VAppData = vAppData
-	updateArgs.BootstrapData.VAppExData = vAppExData
+	updateArgs.BootstrapData = bsData
 
 	if res := vmClass.Spec.Policies.Resources; !res.Requests.Cpu.IsZero() || !res.Limits.Cpu.IsZero() {
 		freq, err := vs.getOrComputeCPUMinFrequency(vmCtx)
@@ -1119,7 +2149,7 @@ func (vs *vSphereVMProvider) vmUpdateGetArgs(
 	// Only get VM image when this is the VM first boot.
 	if isVMFirstBoot(vmCtx) {
 		var err error
-		_, _, vmImageStatus, err = GetVirtualMachineImageSpecAndStatus(vmCtx, vs.k8sClient)
+		_, _, vmImageStatus, err = GetVirtualMachineImageSpecAndStatus(vmCtx, vs.k8sClient, vcClient.Config().ImageNameResolution)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,196 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	goctx "context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
+)
+
+const (
+	diagnosticWaitingForIPReason       = "WaitingForIP"
+	diagnosticTaskInProgressReason     = "TaskInProgress"
+	diagnosticTaskFailedReason         = "TaskFailed"
+	diagnosticPowerStateMismatchReason = "PowerStateMismatch"
+)
+
+// GetVirtualMachineDiagnostic returns the result of checking each of the signals of the VM's
+// readiness. The VM itself is not modified.
+func (vs *vSphereVMProvider) GetVirtualMachineDiagnostic(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine) (vmprovider.VirtualMachineDiagnostic, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "diagnostic")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		// The checks may set conditions on the VM, so use a copy.
+		VM: vm.DeepCopy(),
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return vmprovider.VirtualMachineDiagnostic{}, err
+	}
+
+	taskResult, err := diagnoseTask(vmCtx, client)
+	if err != nil {
+		return vmprovider.VirtualMachineDiagnostic{}, err
+	}
+
+	return vmprovider.VirtualMachineDiagnostic{
+		Results: []vmprovider.DiagnosticResult{
+			vs.diagnoseImage(vmCtx, client),
+			diagnosePlacement(vmCtx.VM),
+			diagnoseNetwork(vmCtx.VM),
+			taskResult,
+			diagnosePowerState(vmCtx.VM),
+		},
+	}, nil
+}
+
+// diagnoseImage checks that the VM's image is ready. The image is only required to create the VM.
+func (vs *vSphereVMProvider) diagnoseImage(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) vmprovider.DiagnosticResult {
+
+	result := vmprovider.DiagnosticResult{Check: vmprovider.DiagnosticCheckImage, Ready: true}
+
+	if vmCtx.VM.Status.UniqueID != "" || vmCtx.VM.Spec.ImageName == "" {
+		return result
+	}
+
+	_, _, _, err := GetVirtualMachineImageSpecAndStatus(vmCtx, vs.k8sClient, vcClient.Config().ImageNameResolution)
+	if err != nil {
+		result.Ready = false
+		result.Reason = conditions.GetReason(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady)
+		result.Message = conditions.GetMessage(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady)
+		if result.Reason == "" {
+			result.Reason, result.Message = vmopv1.VirtualMachineImageNotReadyReason, err.Error()
+		}
+	}
+
+	return result
+}
+
+// diagnosePlacement checks the conditions that the VM's placement depends on.
+func diagnosePlacement(vm *vmopv1.VirtualMachine) vmprovider.DiagnosticResult {
+	return diagnoseConditions(vm, vmprovider.DiagnosticCheckPlacement,
+		vmopv1.VirtualMachineConditionClassReady,
+		vmopv1.VirtualMachineConditionStorageReady,
+		vmopv1.VirtualMachineConditionVMSetResourcePolicyReady,
+		vmopv1.VirtualMachineConditionPlacementReady)
+}
+
+// diagnoseNetwork checks that the VM's network interfaces are ready, and that a powered on VM has
+// an IP address.
+func diagnoseNetwork(vm *vmopv1.VirtualMachine) vmprovider.DiagnosticResult {
+	result := diagnoseConditions(vm, vmprovider.DiagnosticCheckNetwork,
+		vmopv1.VirtualMachineConditionNetworkReady)
+	if !result.Ready {
+		return result
+	}
+
+	if vm.Status.PowerState == vmopv1.VirtualMachinePowerStateOn && (vm.Spec.Network == nil || !vm.Spec.Network.Disabled) {
+		network := vm.Status.Network
+		if network == nil || (network.PrimaryIP4 == "" && network.PrimaryIP6 == "") {
+			result.Ready = false
+			result.Reason = diagnosticWaitingForIPReason
+			result.Message = "VM is powered on but the guest has not reported an IP address"
+		}
+	}
+
+	return result
+}
+
+// diagnoseTask checks for a vSphere task that is in-flight for the VM, or a recent task of the VM
+// that failed.
+func diagnoseTask(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) (vmprovider.DiagnosticResult, error) {
+
+	result := vmprovider.DiagnosticResult{Check: vmprovider.DiagnosticCheckTask, Ready: true}
+
+	if task := vmCtx.VM.Status.Task; task != nil {
+		result.Ready = false
+		result.Reason = diagnosticTaskInProgressReason
+		result.Message = fmt.Sprintf("The %s task is %s (%d%% complete)", task.Operation, task.Phase, task.Progress)
+		return result, nil
+	}
+
+	if vmCtx.VM.Status.UniqueID == "" {
+		return result, nil
+	}
+
+	vmRef := types.ManagedObjectReference{Type: "VirtualMachine", Value: vmCtx.VM.Status.UniqueID}
+	vcVM := object.NewVirtualMachine(vcClient.VimClient(), vmRef)
+
+	var moVM mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vmRef, []string{"recentTask"}, &moVM); err != nil {
+		return result, fmt.Errorf("failed to get the VM's recent tasks: %w", err)
+	}
+
+	for _, taskRef := range moVM.RecentTask {
+		_, info, err := getTaskInfo(vmCtx, vcClient, taskRef)
+		if err != nil {
+			return result, fmt.Errorf("failed to get the info of task %s: %w", taskRef.Value, err)
+		}
+
+		if info.State == types.TaskInfoStateError && info.Error != nil {
+			result.Ready = false
+			result.Reason = diagnosticTaskFailedReason
+			result.Message = fmt.Sprintf("The %s task failed: %s", info.DescriptionId, info.Error.LocalizedMessage)
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// diagnosePowerState checks that the VM's power state matches its spec.
+func diagnosePowerState(vm *vmopv1.VirtualMachine) vmprovider.DiagnosticResult {
+	result := vmprovider.DiagnosticResult{Check: vmprovider.DiagnosticCheckPowerState, Ready: true}
+
+	if vm.Spec.PowerState != "" && vm.Spec.PowerState != vm.Status.PowerState {
+		result.Ready = false
+		result.Reason = diagnosticPowerStateMismatchReason
+		result.Message = fmt.Sprintf("VM power state is %q but the desired power state is %q",
+			vm.Status.PowerState, vm.Spec.PowerState)
+	}
+
+	return result
+}
+
+// diagnoseConditions returns a result that is not ready with the reason and message of the first
+// of the VM's conditions that is false.
+func diagnoseConditions(
+	vm *vmopv1.VirtualMachine,
+	check vmprovider.DiagnosticCheck,
+	conditionTypes ...string) vmprovider.DiagnosticResult {
+
+	result := vmprovider.DiagnosticResult{Check: check, Ready: true}
+
+	for _, t := range conditionTypes {
+		if conditions.IsFalse(vm, t) {
+			result.Ready = false
+			result.Reason = conditions.GetReason(vm, t)
+			result.Message = conditions.GetMessage(vm, t)
+			if result.Reason == "" {
+				result.Reason = t
+			}
+			break
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,312 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
+	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vmlifecycle"
+)
+
+// reconciledVMState is the state of a VM after its last successful full reconcile.
+type reconciledVMState struct {
+	generation int64
+	hash       string
+	// fastPathCount is the number of fast-path reconciles since the last full reconcile.
+	fastPathCount int
+}
+
+// reconciledVMStates is the state of the VMs after their last successful full reconcile, keyed
+// by the VM's namespaced name.
+type reconciledVMStates struct {
+	mu     sync.Mutex
+	states map[string]*reconciledVMState
+}
+
+func newReconciledVMStates() *reconciledVMStates {
+	return &reconciledVMStates{
+		states: map[string]*reconciledVMState{},
+	}
+}
+
+// observedOnlyConditionTypes are the VM conditions that only report the state of the guest or of
+// vSphere, or that are only informational, so the full reconcile does not act on them when they
+// are not true.
+var observedOnlyConditionTypes = map[string]struct{}{
+	vmopv1.ReadyConditionType:                                {},
+	vmopv1.GuestCustomizationCondition:                       {},
+	vmopv1.VirtualMachineToolsCondition:                      {},
+	vmopv1.VirtualMachineConditionHARestarted:                {},
+	vmopv1.VirtualMachineConditionNetworkInterfaceCompatible: {},
+}
+
+// hashReconciledVM returns a hash of the parts of the VM that the reconcile depends on. This
+// includes the labels and annotations since those can change without the VM's generation changing,
+// and the resource versions of the objects the VM references, like its bootstrap Secrets.
+func (vs *vSphereVMProvider) hashReconciledVM(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) (string, error) {
+
+	vm := vmCtx.VM
+
+	dependencies, err := vs.reconciledVMDependencies(vmCtx, vcClient.Config().ImageNameResolution)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(struct {
+		Labels       map[string]string
+		Annotations  map[string]string
+		Spec         vmopv1.VirtualMachineSpec
+		Status       vmopv1.VirtualMachineStatus
+		Dependencies map[string]string
+	}{
+		Labels:       vm.Labels,
+		Annotations:  vm.Annotations,
+		Spec:         vm.Spec,
+		Status:       vm.Status,
+		Dependencies: dependencies,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// reconciledVMDependencies returns the resource versions of the VM's image, StorageClass, and
+// bootstrap Secrets, and the hash of the VM's resolved class, keyed by their kind and name, since a
+// change to them does not change the VM. An object that does not exist is left out.
+func (vs *vSphereVMProvider) reconciledVMDependencies(
+	vmCtx context.VirtualMachineContextA2,
+	resolution vcconfig.ImageNameResolution) (map[string]string, error) {
+
+	vm := vmCtx.VM
+	dependencies := map[string]string{}
+
+	addDependency := func(kind string, key ctrlclient.ObjectKey, obj ctrlclient.Object) (bool, error) {
+		if err := vs.k8sClient.Get(vmCtx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		dependencies[kind+"/"+key.Name] = obj.GetResourceVersion()
+		return true, nil
+	}
+
+	if name := vm.Spec.ImageName; name != "" {
+		found, err := addDependency("VirtualMachineImage",
+			ctrlclient.ObjectKey{Namespace: vm.Namespace, Name: name}, &vmopv1.VirtualMachineImage{})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			found, err = addDependency("ClusterVirtualMachineImage",
+				ctrlclient.ObjectKey{Name: name}, &vmopv1.ClusterVirtualMachineImage{})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !found && resolution.ResolvesItemNames() {
+			// The name may be of the content library item an image was created from.
+			img, err := resolveVirtualMachineImageByName(vmCtx, vs.k8sClient, vcconfig.ImageNameResolutionPreferBindingOrder)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			if img != nil {
+				dependencies[img.GetObjectKind().GroupVersionKind().Kind+"/"+img.GetName()] = img.GetResourceVersion()
+			}
+		}
+	}
+
+	if name := vm.Spec.ClassName; name != "" {
+		// The class's resource version would not change when a class it inherits from is changed.
+		hash, err := resolvedVirtualMachineClassHash(vmCtx, vs.k8sClient)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if hash != "" {
+			dependencies["VirtualMachineClass/"+name] = hash
+		}
+	}
+
+	if name := vm.Spec.StorageClass; name != "" {
+		if _, err := addDependency("StorageClass", ctrlclient.ObjectKey{Name: name}, &storagev1.StorageClass{}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range bootstrapSecretNames(vm) {
+		key := ctrlclient.ObjectKey{Namespace: vm.Namespace, Name: name}
+		found, err := addDependency("Secret", key, &corev1.Secret{})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			if _, err := addDependency("ConfigMap", key, &corev1.ConfigMap{}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return dependencies, nil
+}
+
+// recordFullReconcile records the VM's state after a successful full reconcile so the following
+// reconciles of the VM can take the fast-path while the VM remains unchanged.
+func (vs *vSphereVMProvider) recordFullReconcile(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) {
+
+	vmCtx.VM.Status.ObservedGeneration = vmCtx.VM.Generation
+
+	hash, err := vs.hashReconciledVM(vmCtx, vcClient)
+	if err != nil {
+		vmCtx.Logger.Error(err, "Failed to hash the reconciled VM")
+		vs.forgetReconciledVM(vmCtx.VM)
+		return
+	}
+
+	vs.reconciledVMs.mu.Lock()
+	defer vs.reconciledVMs.mu.Unlock()
+
+	vs.reconciledVMs.states[vmCtx.VM.NamespacedName()] = &reconciledVMState{
+		generation: vmCtx.VM.Generation,
+		hash:       hash,
+	}
+}
+
+// forgetReconciledVM removes the VM's recorded state so its next reconcile is a full reconcile.
+func (vs *vSphereVMProvider) forgetReconciledVM(vm *vmopv1.VirtualMachine) {
+	vs.reconciledVMs.mu.Lock()
+	defer vs.reconciledVMs.mu.Unlock()
+
+	delete(vs.reconciledVMs.states, vm.NamespacedName())
+}
+
+// isVMConverged returns true if the VM's observed state matches its spec.
+func isVMConverged(vm *vmopv1.VirtualMachine) bool {
+	if vm.Status.ObservedGeneration != vm.Generation || vm.Status.UniqueID == "" {
+		return false
+	}
+
+	if !conditions.IsTrue(vm, vmopv1.VirtualMachineConditionCreated) {
+		return false
+	}
+
+	// A condition that is not true may be waiting on a change that the full reconcile applies,
+	// like the changes that are deferred until the VM is powered off.
+	for _, c := range vm.Status.Conditions {
+		if _, ok := observedOnlyConditionTypes[c.Type]; !ok && c.Status != metav1.ConditionTrue {
+			return false
+		}
+	}
+
+	// The guest of a VM that is still being customized is still converging.
+	switch conditions.GetReason(vm, vmopv1.GuestCustomizationCondition) {
+	case vmopv1.GuestCustomizationPendingReason, vmopv1.GuestCustomizationRunningReason:
+		return false
+	}
+
+	if vm.Spec.PowerState != "" && vm.Spec.PowerState != vm.Status.PowerState {
+		return false
+	}
+
+	if vm.Status.PowerState == vmopv1.VirtualMachinePowerStateOn {
+		// The guest of a powered on VM is still starting until VMware Tools is running.
+		if !conditions.IsTrue(vm, vmopv1.VirtualMachineToolsCondition) {
+			return false
+		}
+
+		// The full reconcile is what discovers the VM's IP addresses.
+		network := vm.Status.Network
+		if network == nil || (network.PrimaryIP4 == "" && network.PrimaryIP6 == "") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fastPathUpdateVirtualMachine returns true if the VM is unchanged since its last full reconcile
+// and it remains converged after its status is updated from vSphere, so the full reconcile can be
+// skipped. Every lib.GetVMFullReconcileInterval reconciles of a VM are still full reconciles to
+// catch the changes made to the VM outside of VM Operator.
+func (vs *vSphereVMProvider) fastPathUpdateVirtualMachine(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) bool {
+
+	vm := vmCtx.VM
+	if !isVMConverged(vm) {
+		return false
+	}
+
+	hash, err := vs.hashReconciledVM(vmCtx, vcClient)
+	if err != nil {
+		return false
+	}
+
+	key := vm.NamespacedName()
+	interval := lib.GetVMFullReconcileInterval()
+
+	vs.reconciledVMs.mu.Lock()
+	state, ok := vs.reconciledVMs.states[key]
+	ok = ok && state.generation == vm.Generation && state.hash == hash && state.fastPathCount+1 < interval
+	vs.reconciledVMs.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	// Only the power state and VMware Tools status are refreshed, and the primary IP checked, so
+	// they do not go stale between the full reconciles. The rest of the status is refreshed by the
+	// full reconcile.
+	vcVM := object.NewVirtualMachine(vcClient.VimClient(),
+		types.ManagedObjectReference{Type: "VirtualMachine", Value: vm.Status.UniqueID})
+	ipChanged, err := vmlifecycle.UpdatePowerStateAndGuestStatus(vmCtx, vcVM)
+	if err != nil {
+		vmCtx.Logger.V(4).Info("Failed to update VM status for the fast-path", "error", err.Error())
+		return false
+	}
+
+	if ipChanged || !isVMConverged(vm) {
+		vmCtx.Logger.Info("VM changed outside of VM Operator", "powerState", vm.Status.PowerState)
+		return false
+	}
+
+	// Record the updated status so the next reconcile compares against it.
+	hash, err = vs.hashReconciledVM(vmCtx, vcClient)
+	if err != nil {
+		return false
+	}
+
+	vs.reconciledVMs.mu.Lock()
+	defer vs.reconciledVMs.mu.Unlock()
+
+	if state, ok := vs.reconciledVMs.states[key]; ok {
+		state.hash = hash
+		state.fastPathCount++
+	}
+
+	return true
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	goctx "context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/topology"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vcenter"
+)
+
+// ReconcileVirtualMachineResourcePool checks that the VM is in the ResourcePool dictated by its
+// namespace, zone, and VirtualMachineSetResourcePolicy, ex. the VM may have been manually moved
+// or its policy changed after it was created. When move is true, a VM that has diverged is moved
+// into the expected ResourcePool in its cluster. The VM is never moved to another cluster.
+func (vs *vSphereVMProvider) ReconcileVirtualMachineResourcePool(
+	ctx goctx.Context,
+	vm *vmopv1.VirtualMachine,
+	move bool) (vmprovider.ResourcePoolMembership, error) {
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: goctx.WithValue(ctx, types.ID{}, vs.getOpID(vm, "reconcileResourcePool")),
+		Logger:  log.WithValues("vmName", vm.NamespacedName()),
+		VM:      vm,
+	}
+
+	client, err := vs.getVcClient(vmCtx)
+	if err != nil {
+		return vmprovider.ResourcePoolMembership{}, err
+	}
+
+	vcVM, err := vs.getVM(vmCtx, client, true)
+	if err != nil {
+		return vmprovider.ResourcePoolMembership{}, err
+	}
+
+	var o mo.VirtualMachine
+	if err := vcVM.Properties(vmCtx, vcVM.Reference(), []string{"resourcePool"}, &o); err != nil {
+		return vmprovider.ResourcePoolMembership{}, err
+	}
+
+	if o.ResourcePool == nil {
+		return vmprovider.ResourcePoolMembership{}, fmt.Errorf("VM %s does not have a ResourcePool", vcVM.Reference().Value)
+	}
+
+	membership := vmprovider.ResourcePoolMembership{
+		ResourcePool: *o.ResourcePool,
+	}
+
+	expectedRPRefs, err := vs.getExpectedResourcePools(vmCtx, client)
+	if err != nil {
+		return membership, err
+	}
+
+	for _, rpRef := range expectedRPRefs {
+		if rpRef == membership.ResourcePool {
+			membership.ExpectedResourcePool = rpRef
+			return membership, nil
+		}
+	}
+
+	membership.Diverged = true
+
+	// The expected ResourcePool is the one in the VM's cluster, since moving the VM into a
+	// ResourcePool in another cluster would require a migration.
+	clusterRef, err := vcenter.GetResourcePoolOwnerMoRef(vmCtx, client.VimClient(), membership.ResourcePool.Value)
+	if err != nil {
+		return membership, err
+	}
+
+	for _, rpRef := range expectedRPRefs {
+		rpClusterRef, err := vcenter.GetResourcePoolOwnerMoRef(vmCtx, client.VimClient(), rpRef.Value)
+		if err != nil {
+			return membership, err
+		}
+
+		if rpClusterRef == clusterRef {
+			membership.ExpectedResourcePool = rpRef
+			break
+		}
+	}
+
+	vmCtx.Logger.Info("VM is not in its expected ResourcePool",
+		"resourcePool", membership.ResourcePool.Value, "expectedResourcePool", membership.ExpectedResourcePool.Value)
+
+	if !move {
+		return membership, nil
+	}
+
+	if client.Config().ReadOnly {
+		return membership, ErrReadOnly
+	}
+
+	if membership.ExpectedResourcePool.Value == "" {
+		return membership, fmt.Errorf("none of the expected ResourcePools are in the VM's cluster %s", clusterRef.Value)
+	}
+
+	rp := object.NewResourcePool(client.VimClient(), membership.ExpectedResourcePool)
+	if err := vcenter.MoveIntoResourcePool(vmCtx, rp, vcVM.Reference()); err != nil {
+		return membership, fmt.Errorf("failed to move VM into ResourcePool %s: %w", membership.ExpectedResourcePool.Value, err)
+	}
+
+	vmCtx.Logger.Info("Moved VM into its expected ResourcePool", "resourcePool", membership.ExpectedResourcePool.Value)
+	membership.Moved = true
+
+	return membership, nil
+}
+
+// getExpectedResourcePools returns the ResourcePools the VM may be in: the namespace's
+// ResourcePools in the VM's zone or, when the VM has a VirtualMachineSetResourcePolicy, the
+// policy's child ResourcePool under each of them.
+func (vs *vSphereVMProvider) getExpectedResourcePools(
+	vmCtx context.VirtualMachineContextA2,
+	client *vcclient.Client) ([]types.ManagedObjectReference, error) {
+
+	zoneName := vmCtx.VM.Labels[topology.KubernetesTopologyZoneLabelKey]
+	availabilityZone, err := topology.GetAvailabilityZone(vmCtx, vs.k8sClient, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	nsInfo, ok := availabilityZone.Spec.Namespaces[vmCtx.VM.Namespace]
+	if !ok {
+		return nil, fmt.Errorf("availability zone %q missing info for namespace %s",
+			availabilityZone.Name, vmCtx.VM.Namespace)
+	}
+
+	rpMoIDs := nsInfo.PoolMoIDs
+	if len(rpMoIDs) == 0 && nsInfo.PoolMoId != "" {
+		rpMoIDs = []string{nsInfo.PoolMoId}
+	}
+
+	var childRPName string
+	if reserved := vmCtx.VM.Spec.Reserved; reserved != nil && reserved.ResourcePolicyName != "" {
+		resourcePolicy := &vmopv1.VirtualMachineSetResourcePolicy{}
+		key := ctrlclient.ObjectKey{Name: reserved.ResourcePolicyName, Namespace: vmCtx.VM.Namespace}
+		if err := vs.k8sClient.Get(vmCtx, key, resourcePolicy); err != nil {
+			return nil, err
+		}
+		childRPName = resourcePolicy.Spec.ResourcePool.Name
+	}
+
+	rpRefs := make([]types.ManagedObjectReference, 0, len(rpMoIDs))
+	for _, rpMoID := range rpMoIDs {
+		rp := object.NewResourcePool(client.VimClient(), types.ManagedObjectReference{Type: "ResourcePool", Value: rpMoID})
+
+		if childRPName != "" {
+			childRP, err := vcenter.GetChildResourcePool(vmCtx, rp, childRPName)
+			if err != nil {
+				return nil, err
+			}
+			rp = childRP
+		}
+
+		rpRefs = append(rpRefs, rp.Reference())
+	}
+
+	return rpRefs, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere_test
+
+import (
+	goctx "context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	vsphere "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func vmResourcePoolTests() {
+
+	var (
+		ctx        *builder.TestContextForVCSim
+		vmProvider vmprovider.VirtualMachineProviderInterfaceA2
+		nsInfo     builder.WorkloadNamespaceInfo
+
+		vm     *vmopv1.VirtualMachine
+		vcVM   *object.VirtualMachine
+		nsRP   *object.ResourcePool
+		rootRP *object.ResourcePool
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true, WithContentLibrary: true})
+		ctx.Context = goctx.WithValue(ctx.Context, context.MaxDeployThreadsContextKey, 1)
+		vmProvider = vsphere.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+		nsInfo = ctx.CreateWorkloadNamespace()
+
+		vmClass := builder.DummyVirtualMachineClassA2()
+		vmClass.Namespace = nsInfo.Namespace
+		Expect(ctx.Client.Create(ctx, vmClass)).To(Succeed())
+		vmClass.Status.Ready = true
+		Expect(ctx.Client.Status().Update(ctx, vmClass)).To(Succeed())
+
+		vm = builder.DummyBasicVirtualMachineA2("test-vm", nsInfo.Namespace)
+		vm.Spec.ClassName = vmClass.Name
+		vm.Spec.ImageName = ctx.ContentLibraryImageName
+		vm.Spec.StorageClass = ctx.StorageClassName
+		if vm.Spec.Network == nil {
+			vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{}
+		}
+		vm.Spec.Network.Disabled = true
+
+		Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+		Expect(vm.Status.UniqueID).ToNot(BeEmpty())
+		vcVM = object.NewVirtualMachine(ctx.VCClient.Client,
+			types.ManagedObjectReference{Type: "VirtualMachine", Value: vm.Status.UniqueID})
+
+		nsRP = ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", "")
+
+		var err error
+		rootRP, err = ctx.GetSingleClusterCompute().ResourcePool(ctx)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+		vmProvider = nil
+		nsInfo = builder.WorkloadNamespaceInfo{}
+		vm = nil
+		vcVM = nil
+		nsRP = nil
+		rootRP = nil
+	})
+
+	It("returns the VM is in its expected ResourcePool", func() {
+		membership, err := vmProvider.ReconcileVirtualMachineResourcePool(ctx, vm, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(membership.Diverged).To(BeFalse())
+		Expect(membership.Moved).To(BeFalse())
+		Expect(membership.ResourcePool).To(Equal(nsRP.Reference()))
+		Expect(membership.ExpectedResourcePool).To(Equal(nsRP.Reference()))
+	})
+
+	When("the VM is moved to a sibling ResourcePool", func() {
+		var siblingRP *object.ResourcePool
+
+		BeforeEach(func() {
+			var err error
+			siblingRP, err = rootRP.Create(ctx, "sibling-rp", types.DefaultResourceConfigSpec())
+			Expect(err).ToNot(HaveOccurred())
+
+			poolRef := siblingRP.Reference()
+			task, err := vcVM.Relocate(ctx, types.VirtualMachineRelocateSpec{Pool: &poolRef}, types.VirtualMachineMovePriorityDefaultPriority)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(task.Wait(ctx)).To(Succeed())
+		})
+
+		It("returns the VM has diverged from its expected ResourcePool", func() {
+			membership, err := vmProvider.ReconcileVirtualMachineResourcePool(ctx, vm, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(membership.Diverged).To(BeTrue())
+			Expect(membership.Moved).To(BeFalse())
+			Expect(membership.ResourcePool).To(Equal(siblingRP.Reference()))
+			Expect(membership.ExpectedResourcePool).To(Equal(nsRP.Reference()))
+		})
+	})
+}
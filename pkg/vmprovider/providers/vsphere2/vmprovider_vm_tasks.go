@@ -0,0 +1,412 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	goctx "context"
+	"sync"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	vcclient "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/client"
+)
+
+type vmOperation string
+
+const (
+	vmOperationCreate vmOperation = "create"
+	vmOperationUpdate vmOperation = "update"
+)
+
+// inFlightOperation is an operation that is in-flight for a VM, along with the vSphere task
+// of the operation once that has been started. An operation without a vSphere task, like the
+// deploy of a content library item, instead has the description of what it is doing.
+type inFlightOperation struct {
+	task        *types.ManagedObjectReference
+	description string
+	// cancelled is true once the VM has been deleted while the operation was in-flight.
+	cancelled bool
+}
+
+// inFlightVMOperations is the operations that are in-flight for the VMs, keyed by the VM's
+// namespaced name. Only operations started by this provider are tracked.
+type inFlightVMOperations struct {
+	mu  sync.Mutex
+	ops map[string]map[vmOperation]*inFlightOperation
+}
+
+func newInFlightVMOperations() *inFlightVMOperations {
+	return &inFlightVMOperations{
+		ops: map[string]map[vmOperation]*inFlightOperation{},
+	}
+}
+
+// beginVMOperation records the operation as in-flight for the VM. If an operation of the same
+// type is already in-flight for the VM, false is returned and the caller should requeue the
+// request. Otherwise, the returned func must be called once the operation has completed.
+func (vs *vSphereVMProvider) beginVMOperation(vmCtx context.VirtualMachineContextA2, op vmOperation) (bool, func()) {
+	key := vmCtx.VM.NamespacedName()
+	inFlightOps := vs.inFlightOps
+
+	inFlightOps.mu.Lock()
+	defer inFlightOps.mu.Unlock()
+
+	ops, ok := inFlightOps.ops[key]
+	if !ok {
+		ops = map[vmOperation]*inFlightOperation{}
+		inFlightOps.ops[key] = ops
+	}
+
+	if _, ok := ops[op]; ok {
+		return false, nil
+	}
+
+	inFlight := &inFlightOperation{}
+	ops[op] = inFlight
+
+	return true, func() {
+		inFlightOps.mu.Lock()
+		defer inFlightOps.mu.Unlock()
+
+		if ops, ok := inFlightOps.ops[key]; ok && ops[op] == inFlight {
+			delete(ops, op)
+			if len(ops) == 0 {
+				delete(inFlightOps.ops, key)
+			}
+		}
+	}
+}
+
+// trackVMOperationTask records the task of the in-flight operation for the VM, and returns a
+// func to call once the task has completed.
+func (vs *vSphereVMProvider) trackVMOperationTask(
+	vmCtx context.VirtualMachineContextA2,
+	op vmOperation,
+	task types.ManagedObjectReference) func() {
+
+	key := vmCtx.VM.NamespacedName()
+	inFlightOps := vs.inFlightOps
+
+	inFlightOps.mu.Lock()
+	defer inFlightOps.mu.Unlock()
+
+	inFlight, ok := inFlightOps.ops[key][op]
+	if !ok {
+		return func() {}
+	}
+	inFlight.task = &task
+
+	return func() {
+		inFlightOps.mu.Lock()
+		inFlight.task = nil
+		inFlightOps.mu.Unlock()
+	}
+}
+
+// trackVMOperationDescription records what the in-flight operation for the VM is doing when the
+// operation does not have a vSphere task, and returns a func to call once it is done.
+func (vs *vSphereVMProvider) trackVMOperationDescription(
+	vmCtx context.VirtualMachineContextA2,
+	op vmOperation,
+	description string) func() {
+
+	key := vmCtx.VM.NamespacedName()
+	inFlightOps := vs.inFlightOps
+
+	inFlightOps.mu.Lock()
+	defer inFlightOps.mu.Unlock()
+
+	inFlight, ok := inFlightOps.ops[key][op]
+	if !ok {
+		return func() {}
+	}
+	inFlight.description = description
+
+	return func() {
+		inFlightOps.mu.Lock()
+		inFlight.description = ""
+		inFlightOps.mu.Unlock()
+	}
+}
+
+// getInFlightVMOperationDescription returns the description of the in-flight operation for the
+// VM that does not have a vSphere task, if any.
+func (vs *vSphereVMProvider) getInFlightVMOperationDescription(
+	vmCtx context.VirtualMachineContextA2,
+	op vmOperation) (string, bool) {
+
+	vs.inFlightOps.mu.Lock()
+	defer vs.inFlightOps.mu.Unlock()
+
+	if inFlight, ok := vs.inFlightOps.ops[vmCtx.VM.NamespacedName()][op]; ok && inFlight.description != "" {
+		return inFlight.description, true
+	}
+
+	return "", false
+}
+
+// getInFlightVMTask returns the task of the in-flight operation for the VM, if any.
+func (vs *vSphereVMProvider) getInFlightVMTask(
+	vmCtx context.VirtualMachineContextA2,
+	op vmOperation) (types.ManagedObjectReference, bool) {
+
+	vs.inFlightOps.mu.Lock()
+	defer vs.inFlightOps.mu.Unlock()
+
+	if inFlight, ok := vs.inFlightOps.ops[vmCtx.VM.NamespacedName()][op]; ok && inFlight.task != nil {
+		return *inFlight.task, true
+	}
+
+	return types.ManagedObjectReference{}, false
+}
+
+// cancelInFlightVMOperation marks the operation that is in-flight for the VM, if any, as
+// cancelled, and returns true if there was such an operation.
+func (vs *vSphereVMProvider) cancelInFlightVMOperation(vmCtx context.VirtualMachineContextA2, op vmOperation) bool {
+	vs.inFlightOps.mu.Lock()
+	defer vs.inFlightOps.mu.Unlock()
+
+	inFlight, ok := vs.inFlightOps.ops[vmCtx.VM.NamespacedName()][op]
+	if ok {
+		inFlight.cancelled = true
+	}
+
+	return ok
+}
+
+// isVMOperationCancelled returns true if the VM was deleted while the operation was in-flight.
+func (vs *vSphereVMProvider) isVMOperationCancelled(vmCtx context.VirtualMachineContextA2, op vmOperation) bool {
+	vs.inFlightOps.mu.Lock()
+	defer vs.inFlightOps.mu.Unlock()
+
+	inFlight, ok := vs.inFlightOps.ops[vmCtx.VM.NamespacedName()][op]
+	return ok && inFlight.cancelled
+}
+
+func getTaskInfo(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client,
+	taskRef types.ManagedObjectReference) (*object.Task, *types.TaskInfo, error) {
+
+	task := object.NewTask(vcClient.VimClient(), taskRef)
+
+	var moTask mo.Task
+	if err := task.Properties(vmCtx, taskRef, []string{"info"}, &moTask); err != nil {
+		return nil, nil, err
+	}
+
+	return task, &moTask.Info, nil
+}
+
+func isTaskInFlight(info *types.TaskInfo) bool {
+	return info.State == types.TaskInfoStateQueued || info.State == types.TaskInfoStateRunning
+}
+
+// getInFlightVMTaskStatus returns the status of the task that is in-flight for the VM, or nil
+// if there is no such task. An in-flight operation without a vSphere task is reported as running,
+// without its progress.
+func (vs *vSphereVMProvider) getInFlightVMTaskStatus(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) (*vmopv1.VirtualMachineTaskStatus, error) {
+
+	for _, op := range []vmOperation{vmOperationCreate, vmOperationUpdate} {
+		if description, ok := vs.getInFlightVMOperationDescription(vmCtx, op); ok {
+			return newVMOperationStatus(op, description), nil
+		}
+
+		taskRef, ok := vs.getInFlightVMTask(vmCtx, op)
+		if !ok {
+			continue
+		}
+
+		_, info, err := getTaskInfo(vmCtx, vcClient, taskRef)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTaskInFlight(info) {
+			continue
+		}
+
+		return newVMTaskStatus(op, info), nil
+	}
+
+	return nil, nil
+}
+
+func newVMOperationStatus(op vmOperation, description string) *vmopv1.VirtualMachineTaskStatus {
+	return &vmopv1.VirtualMachineTaskStatus{
+		Operation:   string(op),
+		Phase:       string(types.TaskInfoStateRunning),
+		Description: description,
+	}
+}
+
+func newVMTaskStatus(op vmOperation, info *types.TaskInfo) *vmopv1.VirtualMachineTaskStatus {
+	return &vmopv1.VirtualMachineTaskStatus{
+		Operation:   string(op),
+		Phase:       string(info.State),
+		Description: info.DescriptionId,
+		Progress:    info.Progress,
+	}
+}
+
+// watchVMOperationTask periodically writes the progress of the operation's vSphere task to the
+// VM's status while the task is in-flight, since the VM's status is otherwise not updated until
+// the operation has completed. The updates stop once the task has completed, the context is done,
+// or the VM no longer exists. The returned func must be called once the task has completed and
+// clears the task from the VM's status.
+func (vs *vSphereVMProvider) watchVMOperationTask(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client,
+	op vmOperation,
+	taskRef types.ManagedObjectReference) func() {
+
+	key := ctrlclient.ObjectKeyFromObject(vmCtx.VM)
+	logger := vmCtx.Logger.WithValues("task", taskRef.Value)
+
+	watchCtx := vmCtx
+	ctx, cancel := goctx.WithCancel(vmCtx)
+	watchCtx.Context = ctx
+
+	var patched bool
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		wait.UntilWithContext(ctx, func(_ goctx.Context) {
+			_, info, err := getTaskInfo(watchCtx, vcClient, taskRef)
+			if err != nil {
+				logger.V(4).Info("Failed to get task info", "err", err)
+				return
+			}
+
+			if !isTaskInFlight(info) {
+				cancel()
+				return
+			}
+
+			if err := vs.patchVMTaskStatus(ctx, key, newVMTaskStatus(op, info)); err != nil {
+				if apierrors.IsNotFound(err) {
+					cancel()
+					return
+				}
+				logger.V(4).Info("Failed to update task status", "err", err)
+				return
+			}
+
+			patched = true
+		}, lib.GetVMTaskProgressInterval())
+	}()
+
+	return func() {
+		cancel()
+		<-stopped
+
+		vmCtx.VM.Status.Task = nil
+		if patched {
+			if err := vs.patchVMTaskStatus(vmCtx, key, nil); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to clear task status")
+			}
+		}
+	}
+}
+
+// reportVMOperation writes the in-flight operation that does not have a vSphere task to the VM's
+// status, since the VM's status is otherwise not updated until the operation has completed. The
+// returned func must be called once the operation has completed and clears the task from the VM's
+// status.
+func (vs *vSphereVMProvider) reportVMOperation(
+	vmCtx context.VirtualMachineContextA2,
+	op vmOperation,
+	description string) func() {
+
+	key := ctrlclient.ObjectKeyFromObject(vmCtx.VM)
+	logger := vmCtx.Logger.WithValues("description", description)
+
+	err := vs.patchVMTaskStatus(vmCtx, key, newVMOperationStatus(op, description))
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.V(4).Info("Failed to update task status", "err", err)
+	}
+	patched := err == nil
+
+	return func() {
+		vmCtx.VM.Status.Task = nil
+		if patched {
+			if err := vs.patchVMTaskStatus(vmCtx, key, nil); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to clear task status")
+			}
+		}
+	}
+}
+
+// patchVMTaskStatus sets, or clears when nil, the task in the VM's status. The VM's status is
+// patched instead of updated since the VM is concurrently being reconciled.
+func (vs *vSphereVMProvider) patchVMTaskStatus(
+	ctx goctx.Context,
+	key ctrlclient.ObjectKey,
+	taskStatus *vmopv1.VirtualMachineTaskStatus) error {
+
+	vm := &vmopv1.VirtualMachine{}
+	vm.Namespace, vm.Name = key.Namespace, key.Name
+
+	base := vm.DeepCopy()
+	if taskStatus == nil {
+		// So the patch removes the task from the VM's status.
+		base.Status.Task = &vmopv1.VirtualMachineTaskStatus{}
+	}
+	vm.Status.Task = taskStatus
+
+	return vs.k8sClient.Status().Patch(ctx, vm, ctrlclient.MergeFrom(base))
+}
+
+// cancelInFlightVMCreate cancels the create that is in-flight for the VM, if any. The create is
+// marked as cancelled so the VM is destroyed once it has been created, and its task, when vSphere
+// allows it, is cancelled so the VM is not created at all. Not every create has a task that can be
+// cancelled, for example deploying an OVF from a content library. Failing to cancel the task is not
+// fatal: the create still destroys the VM once it has been created.
+func (vs *vSphereVMProvider) cancelInFlightVMCreate(
+	vmCtx context.VirtualMachineContextA2,
+	vcClient *vcclient.Client) {
+
+	if !vs.cancelInFlightVMOperation(vmCtx, vmOperationCreate) {
+		return
+	}
+
+	taskRef, ok := vs.getInFlightVMTask(vmCtx, vmOperationCreate)
+	if !ok {
+		vmCtx.Logger.Info("In-flight create does not have a task so the VM is destroyed once it is created")
+		return
+	}
+
+	task, info, err := getTaskInfo(vmCtx, vcClient, taskRef)
+	if err != nil {
+		vmCtx.Logger.Error(err, "Failed to get in-flight task info", "task", taskRef.Value)
+		return
+	}
+
+	if !isTaskInFlight(info) {
+		return
+	}
+
+	if !info.Cancelable {
+		vmCtx.Logger.Info("In-flight task cannot be cancelled so the VM is destroyed once it is created",
+			"task", taskRef.Value, "descriptionId", info.DescriptionId)
+		return
+	}
+
+	vmCtx.Logger.Info("Cancelling in-flight task", "task", taskRef.Value, "descriptionId", info.DescriptionId)
+	if err := task.Cancel(vmCtx); err != nil {
+		vmCtx.Logger.Error(err, "Failed to cancel in-flight task", "task", taskRef.Value)
+	}
+}
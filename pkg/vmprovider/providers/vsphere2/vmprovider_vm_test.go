@@ -7,14 +7,20 @@ import (
 	"bytes"
 	goctx "context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"os"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -22,6 +28,7 @@ import (
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vapi/cluster"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 
@@ -29,11 +36,15 @@ import (
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
 	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	providererrors "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/errors"
 	vsphere "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/placement"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/virtualmachine"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -244,6 +255,157 @@ func vmTests() {
 				})
 			})
 
+			Context("VM Class CPU and memory are changed after the VM is created", func() {
+				var (
+					newCPUs     int64
+					newMemoryMB int64
+				)
+
+				var (
+					oldClassGeneration int64
+					oldCPUs            int64
+					oldMemoryMB        int64
+					memoryDeltaMB      int64
+				)
+
+				BeforeEach(func() {
+					memoryDeltaMB = 1024
+				})
+
+				JustBeforeEach(func() {
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					oldClassGeneration = vm.Status.ObservedClassGeneration
+					Expect(oldClassGeneration).To(Equal(vmClass.Generation))
+
+					oldCPUs = vmClass.Spec.Hardware.Cpus
+					oldMemoryMB = vmClass.Spec.Hardware.Memory.Value() / 1024 / 1024
+					newCPUs = oldCPUs + 2
+					newMemoryMB = oldMemoryMB + memoryDeltaMB
+					vmClass = ctx.UpdateVirtualMachineClass(vmClass.Name, func(vmClass *vmopv1.VirtualMachineClass) {
+						vmClass.Spec.Hardware.Cpus = newCPUs
+						vmClass.Spec.Hardware.Memory = resource.MustParse(fmt.Sprintf("%dMi", newMemoryMB))
+					})
+				})
+
+				// assertPendingPowerCycle asserts the condition reports the changes are deferred.
+				assertPendingPowerCycle := func(deferred string) {
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionClassConfigurationSynced)
+					ExpectWithOffset(1, c).ToNot(BeNil())
+					ExpectWithOffset(1, c.Status).To(Equal(metav1.ConditionFalse))
+					ExpectWithOffset(1, c.Reason).To(Equal(vmopv1.VirtualMachineClassConfigurationPendingPowerCycleReason))
+					ExpectWithOffset(1, c.Message).To(Equal(fmt.Sprintf("VM Class %s changes will be applied when the VM is power cycled", deferred)))
+					ExpectWithOffset(1, vm.Status.ObservedClassGeneration).To(Equal(oldClassGeneration))
+				}
+
+				When("VM has hot add enabled", func() {
+					var (
+						cpuHotAdd    bool
+						memoryHotAdd bool
+					)
+
+					BeforeEach(func() {
+						cpuHotAdd, memoryHotAdd = true, true
+					})
+
+					JustBeforeEach(func() {
+						task, err := vcVM.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+							CpuHotAddEnabled:    pointer.Bool(cpuHotAdd),
+							MemoryHotAddEnabled: pointer.Bool(memoryHotAdd),
+						})
+						Expect(err).ToNot(HaveOccurred())
+						Expect(task.Wait(ctx)).To(Succeed())
+
+						ctx.SetGuestOSHotAddSupported(true, true)
+					})
+
+					It("reconfigures the powered on VM", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+						Expect(o.Runtime.PowerState).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+						Expect(o.Summary.Config.NumCpu).To(BeEquivalentTo(newCPUs))
+						Expect(o.Summary.Config.MemorySizeMB).To(BeEquivalentTo(newMemoryMB))
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionClassConfigurationSynced)).To(BeTrue())
+						Expect(vm.Status.ObservedClassGeneration).To(Equal(vmClass.Generation))
+						Expect(vm.Status.ObservedClassGeneration).To(BeNumerically(">", oldClassGeneration))
+					})
+
+					When("the guest OS does not support hot add", func() {
+						JustBeforeEach(func() {
+							ctx.SetGuestOSHotAddSupported(false, false)
+						})
+
+						It("defers the reconfigure until the VM is power cycled", func() {
+							Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+							var o mo.VirtualMachine
+							Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+							Expect(o.Summary.Config.NumCpu).To(BeEquivalentTo(oldCPUs))
+							Expect(o.Summary.Config.MemorySizeMB).To(BeEquivalentTo(oldMemoryMB))
+							assertPendingPowerCycle("CPU and memory")
+						})
+					})
+
+					When("only CPU hot add is enabled", func() {
+						BeforeEach(func() {
+							memoryHotAdd = false
+						})
+
+						It("hot adds the CPUs and defers the memory change", func() {
+							Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+							var o mo.VirtualMachine
+							Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+							Expect(o.Runtime.PowerState).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+							Expect(o.Summary.Config.NumCpu).To(BeEquivalentTo(newCPUs))
+							Expect(o.Summary.Config.MemorySizeMB).To(BeEquivalentTo(oldMemoryMB))
+							assertPendingPowerCycle("memory")
+						})
+					})
+
+					When("the VM Class memory is decreased", func() {
+						BeforeEach(func() {
+							memoryDeltaMB = -512
+						})
+
+						It("hot adds the CPUs and defers the memory change", func() {
+							Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+							var o mo.VirtualMachine
+							Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+							Expect(o.Summary.Config.NumCpu).To(BeEquivalentTo(newCPUs))
+							Expect(o.Summary.Config.MemorySizeMB).To(BeEquivalentTo(oldMemoryMB))
+							assertPendingPowerCycle("memory")
+						})
+					})
+				})
+
+				When("VM does not have hot add enabled", func() {
+					It("defers the reconfigure until the VM is power cycled", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+						Expect(o.Summary.Config.NumCpu).ToNot(BeEquivalentTo(newCPUs))
+						Expect(o.Summary.Config.MemorySizeMB).ToNot(BeEquivalentTo(newMemoryMB))
+						assertPendingPowerCycle("CPU and memory")
+
+						vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+						Expect(o.Runtime.PowerState).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+						Expect(o.Summary.Config.NumCpu).To(BeEquivalentTo(newCPUs))
+						Expect(o.Summary.Config.MemorySizeMB).To(BeEquivalentTo(newMemoryMB))
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionClassConfigurationSynced)).To(BeTrue())
+						Expect(vm.Status.ObservedClassGeneration).To(Equal(vmClass.Generation))
+					})
+				})
+			})
+
 			Context("VM Class spec CPU reservation & limits are non-zero and ConfigSpec specifies CPU reservation", func() {
 				BeforeEach(func() {
 					vmClass.Spec.Policies.Resources.Requests.Cpu = resource.MustParse("2")
@@ -989,6 +1151,7 @@ func vmTests() {
 				By("has expected Status values", func() {
 					Expect(vm.Status.PowerState).To(Equal(vm.Spec.PowerState))
 					Expect(vm.Status.Host).ToNot(BeEmpty())
+					Expect(vm.Status.HostMoID).To(Equal(o.Runtime.Host.Value))
 					Expect(vm.Status.InstanceUUID).To(And(Not(BeEmpty()), Equal(o.Config.InstanceUuid)))
 					Expect(vm.Status.BiosUUID).To(And(Not(BeEmpty()), Equal(o.Config.Uuid)))
 
@@ -1123,6 +1286,240 @@ func vmTests() {
 				})
 			})
 
+			Context("Boot disk storage class", func() {
+				const bootDiskStorageProfileID = "boot-disk-storage-profile-id"
+
+				relocateCalls := func(vcVM *object.VirtualMachine) int {
+					var n int
+					for _, method := range ctx.MethodCalls(vcVM.Reference()) {
+						if method == "RelocateVM_Task" {
+							n++
+						}
+					}
+					return n
+				}
+
+				JustBeforeEach(func() {
+					storageClass := &storagev1.StorageClass{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "boot-disk-storageclass",
+						},
+						Parameters: map[string]string{
+							"storagePolicyID": bootDiskStorageProfileID,
+						},
+					}
+					Expect(ctx.Client.Create(ctx, storageClass)).To(Succeed())
+
+					if vm.Spec.Advanced == nil {
+						vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+					}
+					vm.Spec.Advanced.BootDiskStorageClass = storageClass.Name
+				})
+
+				It("Relocates the deployed VM's boot disk to the storage policy", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(ctx.MethodCalls(vcVM.Reference())).To(ContainElement("RelocateVM_Task"))
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.extraConfig"}, &o)).To(Succeed())
+					ecMap := util.ExtraConfigToMap(o.Config.ExtraConfig)
+					Expect(ecMap).To(HaveKeyWithValue(constants.BootDiskStorageProfileIDExtraConfigKey, bootDiskStorageProfileID))
+
+					By("Does not relocate the boot disk again on update", func() {
+						relocates := relocateCalls(vcVM)
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(relocateCalls(vcVM)).To(Equal(relocates))
+					})
+				})
+
+				It("Relocates the boot disk on update when it was not placed on the storage policy", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					// Simulate the relocate after the deploy failing.
+					task, err := vcVM.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+						ExtraConfig: []types.BaseOptionValue{
+							&types.OptionValue{Key: constants.BootDiskStorageProfileIDExtraConfigKey, Value: ""},
+						},
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(task.Wait(ctx)).To(Succeed())
+
+					relocates := relocateCalls(vcVM)
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(relocateCalls(vcVM)).To(Equal(relocates + 1))
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.extraConfig"}, &o)).To(Succeed())
+					ecMap := util.ExtraConfigToMap(o.Config.ExtraConfig)
+					Expect(ecMap).To(HaveKeyWithValue(constants.BootDiskStorageProfileIDExtraConfigKey, bootDiskStorageProfileID))
+				})
+
+				Context("Without Content Library", func() {
+					BeforeEach(func() {
+						testConfig.WithContentLibrary = false
+					})
+
+					It("Clones the VM with the boot disk on the storage policy", func() {
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(ctx.MethodCalls(vcVM.Reference())).ToNot(ContainElement("RelocateVM_Task"))
+
+						cloneSpec := ctx.LastCloneSpec()
+						Expect(cloneSpec).ToNot(BeNil())
+						Expect(cloneSpec.Location.Disk).ToNot(BeEmpty())
+
+						bootDiskProfile := cloneSpec.Location.Disk[0].Profile
+						Expect(bootDiskProfile).To(HaveLen(1))
+						Expect(bootDiskProfile[0].(*types.VirtualMachineDefinedProfileSpec).ProfileId).To(Equal(bootDiskStorageProfileID))
+
+						for _, locator := range cloneSpec.Location.Disk[1:] {
+							Expect(locator.Profile).To(HaveLen(1))
+							Expect(locator.Profile[0].(*types.VirtualMachineDefinedProfileSpec).ProfileId).To(Equal(ctx.StorageProfileID))
+						}
+
+						ecMap := util.ExtraConfigToMap(cloneSpec.Config.ExtraConfig)
+						Expect(ecMap).To(HaveKeyWithValue(constants.BootDiskStorageProfileIDExtraConfigKey, bootDiskStorageProfileID))
+
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(ctx.MethodCalls(vcVM.Reference())).ToNot(ContainElement("RelocateVM_Task"))
+					})
+				})
+			})
+
+			Context("Image cached to the datastore", func() {
+				BeforeEach(func() {
+					testConfig.WithoutStorageClass = true
+				})
+
+				It("Clones VM from the cached image", func() {
+					datastore, err := ctx.Finder.DefaultDatastore(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					dsMoID := datastore.Reference().Value
+
+					clusterVMImage := &vmopv1.ClusterVirtualMachineImage{}
+					Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: vm.Spec.ImageName}, clusterVMImage)).To(Succeed())
+					itemID := clusterVMImage.Status.ProviderItemID
+
+					Expect(ctx.GetImageCacheVM(itemID, dsMoID)).To(BeNil())
+					Expect(vmProvider.CacheImage(ctx, vm.Spec.ImageName, vm.Namespace, dsMoID)).To(Succeed())
+					cacheVM := ctx.GetImageCacheVM(itemID, dsMoID)
+					Expect(cacheVM).ToNot(BeNil())
+
+					By("caching the image again is a no-op", func() {
+						Expect(vmProvider.CacheImage(ctx, vm.Spec.ImageName, vm.Namespace, dsMoID)).To(Succeed())
+						vms, err := ctx.Finder.VirtualMachineList(ctx, "vmi-cache-*")
+						Expect(err).ToNot(HaveOccurred())
+						Expect(vms).To(HaveLen(1))
+					})
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vcVM.Reference()).ToNot(Equal(cacheVM.Reference()))
+
+					Expect(ctx.CloneRequestCount()).To(Equal(1))
+					Expect(ctx.Events).To(Receive(ContainSubstring("ImageCacheHit")))
+				})
+
+				It("Clones VM from the image cached before the provider was restarted", func() {
+					datastore, err := ctx.Finder.DefaultDatastore(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					dsMoID := datastore.Reference().Value
+
+					Expect(vmProvider.CacheImage(ctx, vm.Spec.ImageName, vm.Namespace, dsMoID)).To(Succeed())
+
+					vmProvider = vsphere.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+
+					By("caching the image again is a no-op", func() {
+						Expect(vmProvider.CacheImage(ctx, vm.Spec.ImageName, vm.Namespace, dsMoID)).To(Succeed())
+						vms, err := ctx.Finder.VirtualMachineList(ctx, "vmi-cache-*")
+						Expect(err).ToNot(HaveOccurred())
+						Expect(vms).To(HaveLen(1))
+					})
+
+					_, err = createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(ctx.CloneRequestCount()).To(Equal(1))
+					Expect(ctx.Events).To(Receive(ContainSubstring("ImageCacheHit")))
+				})
+
+				It("Deploys the image when the cached image VM no longer exists", func() {
+					datastore, err := ctx.Finder.DefaultDatastore(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					dsMoID := datastore.Reference().Value
+
+					clusterVMImage := &vmopv1.ClusterVirtualMachineImage{}
+					Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: vm.Spec.ImageName}, clusterVMImage)).To(Succeed())
+					itemID := clusterVMImage.Status.ProviderItemID
+
+					Expect(vmProvider.CacheImage(ctx, vm.Spec.ImageName, vm.Namespace, dsMoID)).To(Succeed())
+					cacheVM := ctx.GetImageCacheVM(itemID, dsMoID)
+					Expect(cacheVM).ToNot(BeNil())
+
+					task, err := cacheVM.Destroy(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(task.Wait(ctx)).To(Succeed())
+
+					_, err = createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(ctx.CloneRequestCount()).To(BeZero())
+
+					By("caching the image again", func() {
+						Expect(vmProvider.CacheImage(ctx, vm.Spec.ImageName, vm.Namespace, dsMoID)).To(Succeed())
+						Expect(ctx.GetImageCacheVM(itemID, dsMoID)).ToNot(BeNil())
+					})
+				})
+			})
+
+			Context("VM deploy is in-flight", func() {
+				It("writes the deploy to the VM's status until the deploy completes", func() {
+					Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
+					unblockFn := ctx.BlockMethod("ImportVApp")
+					defer unblockFn()
+
+					createVM := vm.DeepCopy()
+					createErr := make(chan error, 1)
+					go func() {
+						defer GinkgoRecover()
+						createErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, createVM)
+					}()
+
+					getTaskStatus := func() *vmopv1.VirtualMachineTaskStatus {
+						obj := &vmopv1.VirtualMachine{}
+						Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(vm), obj)).To(Succeed())
+						return obj.Status.Task
+					}
+
+					Eventually(getTaskStatus).ShouldNot(BeNil())
+					taskStatus := getTaskStatus()
+					Expect(taskStatus.Operation).To(Equal("create"))
+					Expect(taskStatus.Phase).To(Equal(string(types.TaskInfoStateRunning)))
+					Expect(taskStatus.Description).To(Equal("vcenter.ovf.library_item.deploy"))
+					Expect(taskStatus.Progress).To(BeZero())
+
+					Expect(vmProvider.UpdateVirtualMachineTaskStatus(ctx, vm)).To(Succeed())
+					Expect(vm.Status.Task).To(Equal(taskStatus))
+
+					By("a concurrent create returns a transient error", func() {
+						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm.DeepCopy())
+						Expect(err).To(HaveOccurred())
+						Expect(errors.Is(err, providererrors.ErrTransient)).To(BeTrue())
+					})
+
+					By("status is cleared once the deploy completes", func() {
+						unblockFn()
+						Eventually(createErr).Should(Receive(BeNil()))
+						Expect(getTaskStatus()).To(BeNil())
+						Expect(createVM.Status.Task).To(BeNil())
+
+						Expect(vmProvider.UpdateVirtualMachineTaskStatus(ctx, vm)).To(Succeed())
+						Expect(vm.Status.Task).To(BeNil())
+					})
+				})
+			})
+
 			Context("Without Content Library", func() {
 				BeforeEach(func() {
 					testConfig.WithContentLibrary = false
@@ -1176,95 +1573,492 @@ func vmTests() {
 
 					// TODO: More assertions!
 				})
-			})
-
-			// BMV: I don't think this is actually supported.
-			XIt("Create VM from VMTX in ContentLibrary", func() {
-				imageName := "test-vm-vmtx"
-
-				ctx.ContentLibraryItemTemplate("DC0_C0_RP0_VM0", imageName)
-				vm.Spec.ImageName = imageName
 
-				_, err := createOrUpdateAndGetVcVM(ctx, vm)
-				Expect(err).ToNot(HaveOccurred())
-			})
+				Context("Image is an inventory template", func() {
+					var templateImage *vmopv1.ClusterVirtualMachineImage
 
-			Context("When fault domains is enabled", func() {
-				BeforeEach(func() {
-					testConfig.WithFaultDomains = true
-				})
+					JustBeforeEach(func() {
+						templateImage = ctx.CreateInventoryTemplateImageA2("DC0_C0_RP0_VM0", "inventory-template")
+						vm.Spec.ImageName = templateImage.Name
+					})
 
-				It("creates VM in placement selected zone", func() {
-					Expect(vm.Labels).ToNot(HaveKey(topology.KubernetesTopologyZoneLabelKey))
-					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
+					It("Clones VM from the template", func() {
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
 
-					azName, ok := vm.Labels[topology.KubernetesTopologyZoneLabelKey]
-					Expect(ok).To(BeTrue())
-					Expect(azName).To(BeElementOf(ctx.ZoneNames))
+						templateRef := types.ManagedObjectReference{Type: "VirtualMachine", Value: templateImage.Status.ProviderItemID}
+						Expect(ctx.MethodCalls(templateRef)).To(ContainElement("CloneVM_Task"))
 
-					By("VM is created in the zone's ResourcePool", func() {
-						rp, err := vcVM.ResourcePool(ctx)
-						Expect(err).ToNot(HaveOccurred())
-						nsRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, azName, "")
-						Expect(nsRP).ToNot(BeNil())
-						Expect(rp.Reference().Value).To(Equal(nsRP.Reference().Value))
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.template"}, &o)).To(Succeed())
+						Expect(o.Config.Template).To(BeFalse())
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionCreated)).To(BeTrue())
 					})
-				})
 
-				It("creates VM in assigned zone", func() {
-					azName := ctx.ZoneNames[rand.Intn(len(ctx.ZoneNames))] //nolint:gosec
-					vm.Labels[topology.KubernetesTopologyZoneLabelKey] = azName
+					Context("Template has two NICs", func() {
+						const networkName = "VM Network"
 
-					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
+						BeforeEach(func() {
+							testConfig.WithNetworkEnv = builder.NetworkEnvNamed
 
-					By("VM is created in the zone's ResourcePool", func() {
-						rp, err := vcVM.ResourcePool(ctx)
-						Expect(err).ToNot(HaveOccurred())
-						nsRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, azName, "")
-						Expect(nsRP).ToNot(BeNil())
-						Expect(rp.Reference().Value).To(Equal(nsRP.Reference().Value))
-					})
-				})
-			})
+							vm.Spec.Network.Disabled = false
+							vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+								{
+									Name:    "eth0",
+									Network: common.PartialObjectRef{Name: networkName},
+								},
+								{
+									Name:    "eth1",
+									Network: common.PartialObjectRef{Name: dvpgName},
+								},
+							}
+						})
 
-			Context("When Instance Storage FSS is enabled", func() {
-				BeforeEach(func() {
-					testConfig.WithInstanceStorage = true
-				})
+						JustBeforeEach(func() {
+							// Add a second NIC to the VM the template is cloned from. Both of the
+							// source's NICs are connected to the DVPG.
+							srcVM, err := ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+							Expect(err).ToNot(HaveOccurred())
+							network, _ := getDVPG(ctx, dvpgName)
+							backing, err := network.EthernetCardBackingInfo(ctx)
+							Expect(err).ToNot(HaveOccurred())
+							nic, err := object.EthernetCardTypes().CreateEthernetCard("vmxnet3", backing)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(srcVM.AddDevice(ctx, nic)).To(Succeed())
 
-				expectInstanceStorageVolumes := func(
-					vm *vmopv1.VirtualMachine,
-					isStorage vmopv1.InstanceStorage) {
+							templateImage = ctx.CreateInventoryTemplateImageA2("DC0_C0_RP0_VM0", "inventory-template-2nics")
+							vm.Spec.ImageName = templateImage.Name
+						})
 
-					ExpectWithOffset(1, isStorage.Volumes).ToNot(BeEmpty())
-					isVolumes := instancestorage.FilterVolumes(vm)
-					ExpectWithOffset(1, isVolumes).To(HaveLen(len(isStorage.Volumes)))
+						It("maps each of the template's NICs to the network interface's network", func() {
+							vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+							Expect(err).ToNot(HaveOccurred())
 
-					for _, isVol := range isStorage.Volumes {
-						found := false
+							devices, err := vcVM.Device(ctx)
+							Expect(err).ToNot(HaveOccurred())
+							nics := devices.SelectByType((*types.VirtualEthernetCard)(nil))
+							Expect(nics).To(HaveLen(2))
 
-						for idx, vol := range isVolumes {
-							claim := vol.PersistentVolumeClaim.InstanceVolumeClaim
-							if claim.StorageClass == isStorage.StorageClass && claim.Size == isVol.Size {
-								isVolumes = append(isVolumes[:idx], isVolumes[idx+1:]...)
-								found = true
-								break
-							}
-						}
+							network, err := ctx.Finder.Network(ctx, networkName)
+							Expect(err).ToNot(HaveOccurred())
+							backing0, ok := nics[0].GetVirtualDevice().Backing.(*types.VirtualEthernetCardNetworkBackingInfo)
+							Expect(ok).To(BeTrue(), "NIC 1 backing is %T", nics[0].GetVirtualDevice().Backing)
+							Expect(backing0.Network).ToNot(BeNil())
+							Expect(*backing0.Network).To(Equal(network.Reference()))
+
+							_, dvpg := getDVPG(ctx, dvpgName)
+							backing1, ok := nics[1].GetVirtualDevice().Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+							Expect(ok).To(BeTrue(), "NIC 2 backing is %T", nics[1].GetVirtualDevice().Backing)
+							Expect(backing1.Port.PortgroupKey).To(Equal(dvpg.Reference().Value))
+
+							// The template's first NIC is an E1000, so both of its NICs are replaced
+							// to keep the NICs in the order of the network interfaces.
+							Expect(nics[0]).To(BeAssignableToTypeOf(&types.VirtualVmxnet3{}))
+							Expect(nics[1]).To(BeAssignableToTypeOf(&types.VirtualVmxnet3{}))
+						})
 
-						ExpectWithOffset(1, found).To(BeTrue(), "failed to find instance storage volume for %v", isVol)
-					}
-				}
+						It("removes the template's NIC that does not have a network interface", func() {
+							vm.Spec.Network.Interfaces = vm.Spec.Network.Interfaces[1:]
 
-				It("creates VM without instance storage", func() {
-					_, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
-				})
+							vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+							Expect(err).ToNot(HaveOccurred())
 
-				It("create VM with instance storage", func() {
-					Expect(vm.Spec.Volumes).To(BeEmpty())
+							devices, err := vcVM.Device(ctx)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(devices.SelectByType((*types.VirtualEthernetCard)(nil))).To(HaveLen(1))
+						})
+					})
+
+					When("the inventory is not used as the content source", func() {
+						JustBeforeEach(func() {
+							cm := &corev1.ConfigMap{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{
+								Namespace: ctx.PodNamespace,
+								Name:      "vsphere.provider.config.vmoperator.vmware.com",
+							}, cm)).To(Succeed())
+							cm.Data["UseInventoryAsContentSource"] = "false"
+							Expect(ctx.Client.Update(ctx, cm)).To(Succeed())
+							vmProvider.ResetVcClient(ctx)
+						})
+
+						It("returns error", func() {
+							_, err := createOrUpdateAndGetVcVM(ctx, vm)
+							Expect(err).To(MatchError(ContainSubstring("requires the inventory to be used as the content source")))
+							Expect(conditions.IsFalse(vm, vmopv1.VirtualMachineConditionImageReady)).To(BeTrue())
+						})
+					})
+				})
+
+				Context("VM has a network interface", func() {
+					BeforeEach(func() {
+						testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+
+						vm.Spec.Network.Disabled = false
+						vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+							{
+								Name:    "eth0",
+								Network: common.PartialObjectRef{Name: dvpgName},
+							},
+						}
+					})
+
+					cloneSpecNICChanges := func() []*types.VirtualDeviceConfigSpec {
+						cloneSpec := ctx.LastCloneSpec()
+						Expect(cloneSpec).ToNot(BeNil())
+						Expect(cloneSpec.Config).ToNot(BeNil())
+
+						var nicChanges []*types.VirtualDeviceConfigSpec
+						for _, dc := range cloneSpec.Config.DeviceChange {
+							spec := dc.GetVirtualDeviceConfigSpec()
+							if _, ok := spec.Device.(types.BaseVirtualEthernetCard); ok {
+								nicChanges = append(nicChanges, spec)
+							}
+						}
+						return nicChanges
+					}
+
+					It("CloneSpec replaces the source VM's NIC device of another type", func() {
+						_, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						// The source VM's NIC is an E1000, so it is removed and a NIC of the
+						// spec'd type is added.
+						nicChanges := cloneSpecNICChanges()
+						Expect(nicChanges).To(HaveLen(2))
+						Expect(nicChanges[0].Operation).To(Equal(types.VirtualDeviceConfigSpecOperationAdd))
+						Expect(nicChanges[0].Device).To(BeAssignableToTypeOf(&types.VirtualVmxnet3{}))
+						Expect(nicChanges[1].Operation).To(Equal(types.VirtualDeviceConfigSpecOperationRemove))
+						Expect(nicChanges[1].Device).To(BeAssignableToTypeOf(&types.VirtualE1000{}))
+
+						backing, ok := nicChanges[0].Device.GetVirtualDevice().Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+						Expect(ok).To(BeTrue())
+						_, dvpg := getDVPG(ctx, dvpgName)
+						Expect(backing.Port.PortgroupKey).To(Equal(dvpg.Reference().Value))
+					})
+
+					When("the source VM's NIC is of the spec'd type", func() {
+						JustBeforeEach(func() {
+							srcVM, err := ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+							Expect(err).ToNot(HaveOccurred())
+							devices, err := srcVM.Device(ctx)
+							Expect(err).ToNot(HaveOccurred())
+							srcNICs := devices.SelectByType((*types.VirtualEthernetCard)(nil))
+							Expect(srcNICs).To(HaveLen(1))
+							Expect(srcVM.RemoveDevice(ctx, false, srcNICs...)).To(Succeed())
+
+							nic, err := object.EthernetCardTypes().CreateEthernetCard("vmxnet3", srcNICs[0].GetVirtualDevice().Backing)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(srcVM.AddDevice(ctx, nic)).To(Succeed())
+						})
+
+						It("CloneSpec maps the source VM's NIC device to the network interface", func() {
+							_, err := createOrUpdateAndGetVcVM(ctx, vm)
+							Expect(err).ToNot(HaveOccurred())
+
+							// The source VM has a NIC of the same type so it is edited instead of a
+							// NIC being added.
+							nicChanges := cloneSpecNICChanges()
+							Expect(nicChanges).To(HaveLen(1))
+							Expect(nicChanges[0].Operation).To(Equal(types.VirtualDeviceConfigSpecOperationEdit))
+
+							backing, ok := nicChanges[0].Device.GetVirtualDevice().Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+							Expect(ok).To(BeTrue())
+							_, dvpg := getDVPG(ctx, dvpgName)
+							Expect(backing.Port.PortgroupKey).To(Equal(dvpg.Reference().Value))
+						})
+					})
+				})
+
+				Context("VM is deleted while the clone is running", func() {
+					It("cancels the clone task", func() {
+						ctx.SimulateLongRunningCloneTask()
+
+						createVM := vm.DeepCopy()
+						createErr := make(chan error, 1)
+						go func() {
+							defer GinkgoRecover()
+							createErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, createVM)
+						}()
+
+						Eventually(ctx.LongRunningTasks).Should(HaveLen(1))
+						task := ctx.LongRunningTasks()[0]
+						Expect(ctx.IsTaskCancelled(task)).To(BeFalse())
+
+						Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(ctx.IsTaskCancelled(task)).To(BeTrue())
+
+						var err error
+						Eventually(createErr).Should(Receive(&err))
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("clone VM task failed"))
+					})
+
+					It("destroys the VM once a clone that cannot be cancelled completes", func() {
+						ctx.SimulateLongRunningCloneTask()
+
+						createVM := vm.DeepCopy()
+						createErr := make(chan error, 1)
+						go func() {
+							defer GinkgoRecover()
+							createErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, createVM)
+						}()
+
+						Eventually(ctx.LongRunningTasks).Should(HaveLen(1))
+						task := ctx.LongRunningTasks()[0]
+						ctx.SetLongRunningTaskNotCancelable(task)
+
+						Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(ctx.IsTaskCancelled(task)).To(BeFalse())
+
+						// Complete the clone with a VM that stands in for the clone.
+						clonedVM, err := ctx.Finder.VirtualMachine(ctx, "DC0_C0_RP0_VM1")
+						Expect(err).ToNot(HaveOccurred())
+						ctx.CompleteLongRunningTask(task, clonedVM.Reference())
+
+						Eventually(createErr).Should(Receive(&err))
+						Expect(err).To(MatchError("VM was deleted while it was being created"))
+						Expect(ctx.MethodCalls(clonedVM.Reference())).To(ContainElement("Destroy_Task"))
+						Expect(createVM.Status.UniqueID).To(BeEmpty())
+					})
+				})
+
+				Context("VM clone is in-flight", func() {
+					It("reports the clone task progress on status", func() {
+						ctx.SimulateLongRunningCloneTask()
+
+						createErr := make(chan error, 1)
+						go func() {
+							defer GinkgoRecover()
+							createErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, vm.DeepCopy())
+						}()
+
+						Eventually(ctx.LongRunningTasks).Should(HaveLen(1))
+						task := ctx.LongRunningTasks()[0]
+
+						Expect(vmProvider.UpdateVirtualMachineTaskStatus(ctx, vm)).To(Succeed())
+						Expect(vm.Status.Task).ToNot(BeNil())
+						Expect(vm.Status.Task.Operation).To(Equal("create"))
+						Expect(vm.Status.Task.Phase).To(Equal(string(types.TaskInfoStateRunning)))
+						Expect(vm.Status.Task.Progress).To(BeZero())
+
+						ctx.SetLongRunningTaskProgress(task, 42)
+						Expect(vmProvider.UpdateVirtualMachineTaskStatus(ctx, vm)).To(Succeed())
+						Expect(vm.Status.Task).ToNot(BeNil())
+						Expect(vm.Status.Task.Progress).To(BeEquivalentTo(42))
+
+						By("status is cleared once the task completes", func() {
+							Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+							Eventually(createErr).Should(Receive(HaveOccurred()))
+							Expect(vmProvider.UpdateVirtualMachineTaskStatus(ctx, vm)).To(Succeed())
+							Expect(vm.Status.Task).To(BeNil())
+						})
+					})
+
+					Context("VM exists in the cluster", func() {
+						BeforeEach(func() {
+							Expect(os.Setenv(lib.VMTaskProgressIntervalEnv, "10ms")).To(Succeed())
+						})
+
+						AfterEach(func() {
+							Expect(os.Unsetenv(lib.VMTaskProgressIntervalEnv)).To(Succeed())
+						})
+
+						It("writes the clone task progress to the VM's status until the task completes", func() {
+							Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
+							ctx.SimulateLongRunningCloneTask()
+
+							createVM := vm.DeepCopy()
+							createErr := make(chan error, 1)
+							go func() {
+								defer GinkgoRecover()
+								createErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, createVM)
+							}()
+
+							Eventually(ctx.LongRunningTasks).Should(HaveLen(1))
+							task := ctx.LongRunningTasks()[0]
+
+							getTaskStatus := func() *vmopv1.VirtualMachineTaskStatus {
+								obj := &vmopv1.VirtualMachine{}
+								Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(vm), obj)).To(Succeed())
+								return obj.Status.Task
+							}
+
+							Eventually(getTaskStatus).ShouldNot(BeNil())
+							taskStatus := getTaskStatus()
+							Expect(taskStatus.Operation).To(Equal("create"))
+							Expect(taskStatus.Phase).To(Equal(string(types.TaskInfoStateRunning)))
+							Expect(taskStatus.Description).To(HavePrefix("VirtualMachine.clone"))
+
+							ctx.SetLongRunningTaskProgress(task, 42)
+							Eventually(func() int32 {
+								return getTaskStatus().Progress
+							}).Should(BeEquivalentTo(42))
+
+							By("status is cleared once the task completes", func() {
+								Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+								Eventually(createErr).Should(Receive(HaveOccurred()))
+								Expect(getTaskStatus()).To(BeNil())
+								Expect(createVM.Status.Task).To(BeNil())
+							})
+						})
+					})
+				})
+
+				Context("VM is created concurrently", func() {
+					It("only clones the VM once", func() {
+						ctx.SimulateLongRunningCloneTask()
+
+						createVM := vm.DeepCopy()
+						createErr := make(chan error, 1)
+						go func() {
+							defer GinkgoRecover()
+							createErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, createVM)
+						}()
+
+						Eventually(ctx.LongRunningTasks).Should(HaveLen(1))
+
+						By("second create returns a transient error while the first is in-flight", func() {
+							err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm.DeepCopy())
+							Expect(errors.Is(err, providererrors.ErrTransient)).To(BeTrue())
+							Expect(ctx.CloneRequestCount()).To(Equal(1))
+						})
+
+						By("first create completes once its clone is cancelled", func() {
+							Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+							Eventually(createErr).Should(Receive(HaveOccurred()))
+							Expect(ctx.CloneRequestCount()).To(Equal(1))
+						})
+					})
+				})
+			})
+
+			// BMV: I don't think this is actually supported.
+			XIt("Create VM from VMTX in ContentLibrary", func() {
+				imageName := "test-vm-vmtx"
+
+				ctx.ContentLibraryItemTemplate("DC0_C0_RP0_VM0", imageName)
+				vm.Spec.ImageName = imageName
+
+				_, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("When fault domains is enabled", func() {
+				BeforeEach(func() {
+					testConfig.WithFaultDomains = true
+				})
+
+				It("creates VM in placement selected zone", func() {
+					Expect(vm.Labels).ToNot(HaveKey(topology.KubernetesTopologyZoneLabelKey))
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					azName, ok := vm.Labels[topology.KubernetesTopologyZoneLabelKey]
+					Expect(ok).To(BeTrue())
+					Expect(azName).To(BeElementOf(ctx.ZoneNames))
+
+					By("VM is created in the zone's ResourcePool", func() {
+						rp, err := vcVM.ResourcePool(ctx)
+						Expect(err).ToNot(HaveOccurred())
+						nsRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, azName, "")
+						Expect(nsRP).ToNot(BeNil())
+						Expect(rp.Reference().Value).To(Equal(nsRP.Reference().Value))
+					})
+				})
+
+				It("creates VM in assigned zone", func() {
+					azName := ctx.ZoneNames[rand.Intn(len(ctx.ZoneNames))] //nolint:gosec
+					vm.Labels[topology.KubernetesTopologyZoneLabelKey] = azName
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					By("VM is created in the zone's ResourcePool", func() {
+						rp, err := vcVM.ResourcePool(ctx)
+						Expect(err).ToNot(HaveOccurred())
+						nsRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, azName, "")
+						Expect(nsRP).ToNot(BeNil())
+						Expect(rp.Reference().Value).To(Equal(nsRP.Reference().Value))
+					})
+				})
+
+				When("the image's hardware version is not supported by a zone", func() {
+					var incompatibleZone string
+
+					JustBeforeEach(func() {
+						image := &vmopv1.ClusterVirtualMachineImage{}
+						Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: vm.Spec.ImageName}, image)).To(Succeed())
+						image.Status.HardwareVersion = pointer.Int32(15)
+						Expect(ctx.Client.Status().Update(ctx, image)).To(Succeed())
+
+						incompatibleZone = ctx.ZoneNames[0]
+						ctx.SetHardwareVersions("vmx-13", "vmx-15")
+						for _, ccr := range ctx.GetAZClusterComputes(incompatibleZone) {
+							ctx.SetClusterHardwareVersions(ccr.Reference(), "vmx-13")
+						}
+					})
+
+					It("creates VM in a compatible zone", func() {
+						_, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						Expect(vm.Labels).To(HaveKey(topology.KubernetesTopologyZoneLabelKey))
+						Expect(vm.Labels[topology.KubernetesTopologyZoneLabelKey]).ToNot(Equal(incompatibleZone))
+
+						c := conditions.Get(vm, vmopv1.VirtualMachineConditionZoneCompatible)
+						Expect(c).ToNot(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionTrue))
+						Expect(c.Reason).To(Equal(vmopv1.VirtualMachineZoneIncompatibleReason))
+						Expect(c.Message).To(ContainSubstring(incompatibleZone))
+					})
+
+					It("returns error when the assigned zone is not compatible", func() {
+						vm.Labels[topology.KubernetesTopologyZoneLabelKey] = incompatibleZone
+
+						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+						Expect(err).To(MatchError(ContainSubstring("cannot create VMs with the image's hardware version 15")))
+						Expect(conditions.IsFalse(vm, vmopv1.VirtualMachineConditionZoneCompatible)).To(BeTrue())
+						Expect(conditions.GetMessage(vm, vmopv1.VirtualMachineConditionZoneCompatible)).To(ContainSubstring(incompatibleZone))
+					})
+				})
+			})
+
+			Context("When Instance Storage FSS is enabled", func() {
+				BeforeEach(func() {
+					testConfig.WithInstanceStorage = true
+				})
+
+				expectInstanceStorageVolumes := func(
+					vm *vmopv1.VirtualMachine,
+					isStorage vmopv1.InstanceStorage) {
+
+					ExpectWithOffset(1, isStorage.Volumes).ToNot(BeEmpty())
+					isVolumes := instancestorage.FilterVolumes(vm)
+					ExpectWithOffset(1, isVolumes).To(HaveLen(len(isStorage.Volumes)))
+
+					for _, isVol := range isStorage.Volumes {
+						found := false
+
+						for idx, vol := range isVolumes {
+							claim := vol.PersistentVolumeClaim.InstanceVolumeClaim
+							if claim.StorageClass == isStorage.StorageClass && claim.Size == isVol.Size {
+								isVolumes = append(isVolumes[:idx], isVolumes[idx+1:]...)
+								found = true
+								break
+							}
+						}
+
+						ExpectWithOffset(1, found).To(BeTrue(), "failed to find instance storage volume for %v", isVol)
+					}
+				}
+
+				It("creates VM without instance storage", func() {
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("create VM with instance storage", func() {
+					Expect(vm.Spec.Volumes).To(BeEmpty())
 
 					vmClass.Spec.Hardware.InstanceStorage = vmopv1.InstanceStorage{
 						StorageClass: vm.Spec.StorageClass,
@@ -1320,6 +2114,70 @@ func vmTests() {
 						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionCreated)).To(BeTrue())
 					})
 				})
+
+				expectInstanceStorageHostsExhausted := func(hostMoIDs []string) {
+					vmClass.Spec.Hardware.InstanceStorage = vmopv1.InstanceStorage{
+						StorageClass: vm.Spec.StorageClass,
+						Volumes: []vmopv1.InstanceStorageVolume{
+							{
+								Size: resource.MustParse("256Gi"),
+							},
+						},
+					}
+					Expect(ctx.Client.Update(ctx, vmClass)).To(Succeed())
+
+					// Simulate what would be set by the volume controller after the volumes failed
+					// to be placed on each of the hosts.
+					ExpectWithOffset(1, hostMoIDs).ToNot(BeEmpty())
+					if vm.Annotations == nil {
+						vm.Annotations = map[string]string{}
+					}
+					vm.Annotations[constants.InstanceStorageFailedNodeMOIDsAnnotationKey] = strings.Join(hostMoIDs, ",")
+
+					err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+					ExpectWithOffset(1, err).To(MatchError(placement.ErrInstanceStorageHostsExhausted))
+					ExpectWithOffset(1, vm.Annotations).ToNot(HaveKey(constants.InstanceStorageSelectedNodeMOIDAnnotationKey))
+
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionInstanceStoragePlacement)
+					ExpectWithOffset(1, c).ToNot(BeNil())
+					ExpectWithOffset(1, c.Status).To(Equal(metav1.ConditionFalse))
+					ExpectWithOffset(1, c.Reason).To(Equal(vmopv1.VirtualMachineInstanceStorageHostsExhaustedReason))
+					for _, hostMoID := range hostMoIDs {
+						ExpectWithOffset(1, c.Message).To(ContainSubstring(hostMoID))
+					}
+
+					By("placement is not retried", func() {
+						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+						ExpectWithOffset(1, err).To(MatchError(placement.ErrInstanceStorageHostsExhausted))
+						ExpectWithOffset(1, vm.Annotations).ToNot(HaveKey(constants.InstanceStorageSelectedNodeMOIDAnnotationKey))
+					})
+				}
+
+				It("does not retry placement once instance storage failed on every host", func() {
+					var hostMoIDs []string
+					for _, hostRef := range ctx.GetHostsForCluster(ctx.GetSingleClusterCompute().Reference()) {
+						hostMoIDs = append(hostMoIDs, hostRef.Value)
+					}
+					expectInstanceStorageHostsExhausted(hostMoIDs)
+				})
+
+				When("fault domains is enabled", func() {
+					BeforeEach(func() {
+						testConfig.WithFaultDomains = true
+					})
+
+					It("does not retry placement once instance storage failed on every host of every zone", func() {
+						var hostMoIDs []string
+						for _, zoneName := range ctx.ZoneNames {
+							for _, cluster := range ctx.GetAZClusterComputes(zoneName) {
+								for _, hostRef := range ctx.GetHostsForCluster(cluster.Reference()) {
+									hostMoIDs = append(hostMoIDs, hostRef.Value)
+								}
+							}
+						}
+						expectInstanceStorageHostsExhausted(hostMoIDs)
+					})
+				})
 			})
 
 			It("Powers VM off", func() {
@@ -1336,253 +2194,1539 @@ func vmTests() {
 				Expect(state).To(Equal(types.VirtualMachinePowerStatePoweredOff))
 			})
 
-			It("returns error when StorageClass is required but none specified", func() {
-				vm.Spec.StorageClass = ""
-				err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
-				Expect(err).To(MatchError("StorageClass is required but not specified"))
-			})
+			Context("Guest host name", func() {
 
-			It("Can be called multiple times", func() {
-				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-				Expect(err).ToNot(HaveOccurred())
+				BeforeEach(func() {
+					vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+						LinuxPrep: &vmopv1.VirtualMachineBootstrapLinuxPrepSpec{
+							HardwareClockIsUTC: true,
+						},
+					}
+				})
 
-				var o mo.VirtualMachine
-				Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
-				modified := o.Config.Modified
+				It("Defaults to the VM name", func() {
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
 
-				_, err = createOrUpdateAndGetVcVM(ctx, vm)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+					customSpec := ctx.LastCustomizeSpec()
+					Expect(customSpec).ToNot(BeNil())
+					linuxPrep := customSpec.Identity.(*types.CustomizationLinuxPrep)
+					Expect(linuxPrep.HostName.(*types.CustomizationFixedName).Name).To(Equal(vm.Name))
+					Expect(linuxPrep.Domain).To(BeEmpty())
+				})
 
-				// Try to assert nothing changed.
-				Expect(o.Config.Modified).To(Equal(modified))
+				It("Uses the FQDN from the spec", func() {
+					vm.Spec.Network.HostName = "my-vm.domain.local"
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					customSpec := ctx.LastCustomizeSpec()
+					Expect(customSpec).ToNot(BeNil())
+					linuxPrep := customSpec.Identity.(*types.CustomizationLinuxPrep)
+					Expect(linuxPrep.HostName.(*types.CustomizationFixedName).Name).To(Equal("my-vm"))
+					Expect(linuxPrep.Domain).To(Equal("domain.local"))
+				})
 			})
 
-			Context("VM Metadata", func() {
+			Context("Reapply customization", func() {
 
-				Context("ExtraConfig Transport", func() {
-					var ec map[string]interface{}
+				BeforeEach(func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+						LinuxPrep: &vmopv1.VirtualMachineBootstrapLinuxPrepSpec{
+							HardwareClockIsUTC: true,
+						},
+					}
+				})
 
-					JustBeforeEach(func() {
-						configMap := &corev1.ConfigMap{
-							ObjectMeta: metav1.ObjectMeta{
-								GenerateName: "md-configmap-",
-								Namespace:    vm.Namespace,
-							},
-							Data: map[string]string{
-								"foo.bar":       "should-be-ignored",
-								"guestinfo.Foo": "foo",
-							},
-						}
-						Expect(ctx.Client.Create(ctx, configMap)).To(Succeed())
+				It("Customizes the powered off VM and removes the annotation", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(ctx.MethodCalls(vcVM.Reference())).ToNot(ContainElement("CustomizeVM_Task"))
 
-						/*
-							vm.Spec.VmMetadata = &vmopv1.VirtualMachineMetadata{
-								ConfigMapName: configMap.Name,
-								Transport:     vmopv1.VirtualMachineMetadataExtraConfigTransport,
-							}
-						*/
+					vm.Annotations[constants.ReapplyCustomizationAnnotation] = ""
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+					Expect(ctx.MethodCalls(vcVM.Reference())).To(ContainElement("CustomizeVM_Task"))
+					customSpec := ctx.LastCustomizeSpec()
+					Expect(customSpec).ToNot(BeNil())
+					Expect(customSpec.Identity).To(BeAssignableToTypeOf(&types.CustomizationLinuxPrep{}))
+					Expect(vm.Annotations).ToNot(HaveKey(constants.ReapplyCustomizationAnnotation))
+				})
+
+				It("Keeps the annotation until the powered on VM is powered off", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					calls := len(ctx.MethodCalls(vcVM.Reference()))
+
+					vm.Annotations[constants.ReapplyCustomizationAnnotation] = ""
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.MethodCalls(vcVM.Reference())[calls:]).ToNot(ContainElement("CustomizeVM_Task"))
+					Expect(vm.Annotations).To(HaveKey(constants.ReapplyCustomizationAnnotation))
+
+					By("ReapplyVirtualMachineCustomization returns an error for the powered on VM", func() {
+						err := vmProvider.ReapplyVirtualMachineCustomization(ctx, vm)
+						Expect(err).To(MatchError(ContainSubstring("customization can only be re-applied to a powered off VM")))
+					})
+				})
+			})
+
+			Context("Adopted VM", func() {
+
+				getParent := func(vcVM *object.VirtualMachine) types.ManagedObjectReference {
+					var o mo.VirtualMachine
+					ExpectWithOffset(1, vcVM.Properties(ctx, vcVM.Reference(), []string{"parent"}, &o)).To(Succeed())
+					ExpectWithOffset(1, o.Parent).ToNot(BeNil())
+					return *o.Parent
+				}
+
+				// adoptVM moves the VM out of its namespace Folder, like a VM that predates the operator.
+				adoptVM := func(vcVM *object.VirtualMachine) *object.Folder {
+					folder, err := ctx.Finder.DefaultFolder(ctx)
+					ExpectWithOffset(1, err).ToNot(HaveOccurred())
+					task, err := folder.MoveInto(ctx, []types.ManagedObjectReference{vcVM.Reference()})
+					ExpectWithOffset(1, err).ToNot(HaveOccurred())
+					ExpectWithOffset(1, task.Wait(ctx)).To(Succeed())
+					return folder
+				}
+
+				It("Leaves the adopted VM in place", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					folder := adoptVM(vcVM)
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(getParent(vcVM)).To(Equal(folder.Reference()))
+				})
+
+				When("the provider moves adopted VMs", func() {
+					BeforeEach(func() {
+						testConfig.WithAdoptedVMFolder = true
+					})
+
+					It("Moves the adopted VM into the adopted VM Folder", func() {
 						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
 						Expect(err).ToNot(HaveOccurred())
+						Expect(getParent(vcVM)).To(Equal(nsInfo.Folder.Reference()))
 
-						var o mo.VirtualMachine
-						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+						adoptVM(vcVM)
 
-						ec = map[string]interface{}{}
-						for _, option := range o.Config.ExtraConfig {
-							if val := option.GetOptionValue(); val != nil {
-								ec[val.Key] = val.Value.(string)
-							}
-						}
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(getParent(vcVM)).To(Equal(ctx.AdoptedVMFolder.Reference()))
 					})
+				})
+			})
 
-					AfterEach(func() {
-						ec = nil
+			Context("Read-only provider", func() {
+
+				// setReadOnly updates the provider ConfigMap, and resets the provider's client so
+				// the updated config is used.
+				setReadOnly := func() {
+					cm := &corev1.ConfigMap{}
+					ExpectWithOffset(1, ctx.Client.Get(ctx, client.ObjectKey{
+						Namespace: ctx.PodNamespace,
+						Name:      "vsphere.provider.config.vmoperator.vmware.com",
+					}, cm)).To(Succeed())
+					cm.Data["ReadOnly"] = "true"
+					ExpectWithOffset(1, ctx.Client.Update(ctx, cm)).To(Succeed())
+					vmProvider.ResetVcClient(ctx)
+				}
+
+				When("the provider is read-only", func() {
+					BeforeEach(func() {
+						testConfig.WithReadOnly = true
 					})
 
-					// TODO: As is we can't really honor "guestinfo.*" prefix
-					XIt("Metadata data is included in ExtraConfig", func() {
-						Expect(ec).ToNot(HaveKey("foo.bar"))
-						Expect(ec).To(HaveKeyWithValue("guestinfo.Foo", "foo"))
+					It("Does not create the VM", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(ctx.LastCloneSpec()).To(BeNil())
+						Expect(vm.Status.UniqueID).To(BeEmpty())
 
-						By("Should include default keys and values", func() {
-							Expect(ec).To(HaveKeyWithValue("disk.enableUUID", "TRUE"))
-							Expect(ec).To(HaveKeyWithValue("vmware.tools.gosc.ignoretoolscheck", "TRUE"))
-						})
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionClassReady)).To(BeTrue())
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionImageReady)).To(BeTrue())
+						Expect(conditions.Has(vm, vmopv1.VirtualMachineConditionCreated)).To(BeFalse())
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionDryRun)).To(BeTrue())
+						Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionDryRun)).To(Equal(vmopv1.VirtualMachineDryRunWouldCreateReason))
 					})
+				})
 
-					Context("JSON_EXTRA_CONFIG is specified", func() {
-						BeforeEach(func() {
-							b, err := json.Marshal(
-								struct {
-									Foo string
-									Bar string
-								}{
-									Foo: "f00",
-									Bar: "42",
-								},
-							)
-							Expect(err).ToNot(HaveOccurred())
-							testConfig.WithJSONExtraConfig = string(b)
-						})
+				It("Does not update the existing VM", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					Expect(conditions.Has(vm, vmopv1.VirtualMachineConditionDryRun)).To(BeFalse())
+					calls := len(ctx.MethodCalls(vcVM.Reference()))
 
-						It("Global config is included in ExtraConfig", func() {
-							Expect(ec).To(HaveKeyWithValue("Foo", "f00"))
-							Expect(ec).To(HaveKeyWithValue("Bar", "42"))
-						})
+					setReadOnly()
+
+					By("Reports no change when the VM matches its spec", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionDryRun)).To(BeTrue())
+						Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionDryRun)).To(Equal(vmopv1.VirtualMachineDryRunNoChangeReason))
+					})
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					newCalls := ctx.MethodCalls(vcVM.Reference())[calls:]
+					Expect(newCalls).ToNot(ContainElement("PowerOffVM_Task"))
+					Expect(newCalls).ToNot(ContainElement("ReconfigVM_Task"))
+
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionDryRun)).To(BeTrue())
+					Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionDryRun)).To(Equal(vmopv1.VirtualMachineDryRunWouldUpdateReason))
+					Expect(conditions.GetMessage(vm, vmopv1.VirtualMachineConditionDryRun)).To(Equal("VM would be updated: power state PoweredOff"))
+					Expect(vm.Status.ReconfigurePlan).ToNot(BeNil())
+					Expect(vm.Status.ReconfigurePlan.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+
+					By("Does not delete the VM", func() {
+						Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(MatchError(vsphere.ErrReadOnly))
+						Expect(ctx.GetVMFromMoID(vm.Status.UniqueID)).ToNot(BeNil())
+					})
+
+					By("Does not change the VM", func() {
+						Expect(vmProvider.SetVirtualMachineHotPlug(ctx, vm, vmprovider.HotPlug{CPUHotAdd: true})).To(MatchError(vsphere.ErrReadOnly))
+						Expect(vmProvider.MarkAsTemplate(ctx, vm)).To(MatchError(vsphere.ErrReadOnly))
+						Expect(vmProvider.ReapplyVirtualMachineCustomization(ctx, vm)).To(MatchError(vsphere.ErrReadOnly))
+						Expect(ctx.MethodCalls(vcVM.Reference())[calls:]).ToNot(ContainElement("ReconfigVM_Task"))
 					})
 				})
 			})
 
-			Context("Network", func() {
+			Context("CPU affinity", func() {
 
-				It("Should not have a nic", func() {
-					Expect(vm.Spec.Network.Disabled).To(BeTrue())
+				It("Pins the VM's virtual CPUs to the host's physical CPUs", func() {
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						CPUAffinity: []int32{0, 1},
+					}
 
 					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
 					Expect(err).ToNot(HaveOccurred())
+					Expect(ctx.GetVirtualMachineCPUAffinity(vcVM.Reference())).To(ConsistOf(int32(0), int32(1)))
 
-					var o mo.VirtualMachine
-					Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
-
-					devList := object.VirtualDeviceList(o.Config.Hardware.Device)
-					l := devList.SelectByType(&types.VirtualEthernetCard{})
-					Expect(l).To(BeEmpty())
+					By("VM is powered on the host the affinity was validated against", func() {
+						host, err := vcVM.HostSystem(ctx)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+						Expect(ctx.LastPowerOnHost()).To(HaveValue(Equal(host.Reference())))
+					})
 				})
 
-				Context("Multiple NICs are specified", func() {
-					BeforeEach(func() {
-						testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+				It("Returns an error when an index is not one of the host's physical CPUs", func() {
+					// The vcsim hosts have 2 physical CPUs.
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						CPUAffinity: []int32{0, 2},
+					}
 
-						vm.Spec.Network.Disabled = false
-						vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
-							{
-								Name:    "eth0",
-								Network: common.PartialObjectRef{Name: "VM Network"},
-							},
-							{
-								Name:    "eth1",
-								Network: common.PartialObjectRef{Name: dvpgName},
-							},
-						}
-					})
+					err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+					Expect(err).To(MatchError(ContainSubstring("CPU affinity index 2 is not within the 2 physical CPUs of host")))
+					Expect(vm.Status.PowerState).ToNot(Equal(vmopv1.VirtualMachinePowerStateOn))
+				})
+			})
 
-					It("Has expected devices", func() {
-						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-						Expect(err).ToNot(HaveOccurred())
+			Context("Swap placement", func() {
 
-						var o mo.VirtualMachine
-						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+				BeforeEach(func() {
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						SwapPlacement: vmopv1.VirtualMachineSwapPlacementHostLocal,
+					}
+				})
 
-						devList := object.VirtualDeviceList(o.Config.Hardware.Device)
-						l := devList.SelectByType(&types.VirtualEthernetCard{})
-						Expect(l).To(HaveLen(2))
+				It("Places the swap file on the host's swap datastore", func() {
+					ctx.SetHostLocalSwapDatastore()
 
-						dev1 := l[0].GetVirtualDevice()
-						backing1, ok := dev1.Backing.(*types.VirtualEthernetCardNetworkBackingInfo)
-						Expect(ok).Should(BeTrue())
-						Expect(backing1.DeviceName).To(Equal("VM Network"))
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(ctx.GetVirtualMachineSwapPlacement(vcVM.Reference())).To(Equal(string(types.VirtualMachineConfigInfoSwapPlacementTypeHostLocal)))
+				})
 
-						dev2 := l[1].GetVirtualDevice()
-						backing2, ok := dev2.Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
-						Expect(ok).Should(BeTrue())
-						_, dvpg := getDVPG(ctx, dvpgName)
-						Expect(backing2.Port.PortgroupKey).To(Equal(dvpg.Reference().Value))
-					})
+				It("Returns an error when the host does not have a swap datastore", func() {
+					err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+					Expect(err).To(MatchError(MatchRegexp("host .* does not have a swap datastore")))
+					Expect(vm.Status.PowerState).ToNot(Equal(vmopv1.VirtualMachinePowerStateOn))
 				})
 			})
 
-			Context("Disks", func() {
+			Context("VMware Tools", func() {
 
-				Context("VM has thin provisioning", func() {
-					BeforeEach(func() {
-						if vm.Spec.Advanced == nil {
-							vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
-						}
-						vm.Spec.Advanced.DefaultVolumeProvisioningMode = vmopv1.VirtualMachineVolumeProvisioningModeThin
-					})
+				BeforeEach(func() {
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						ToolsUpgradePolicy: vmopv1.VirtualMachineToolsUpgradePolicyUpgradeAtPowerCycle,
+					}
+				})
 
-					It("Succeeds", func() {
-						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-						Expect(err).ToNot(HaveOccurred())
+				It("Applies the upgrade policy", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
 
-						var o mo.VirtualMachine
-						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.tools"}, &o)).To(Succeed())
+					Expect(o.Config.Tools).ToNot(BeNil())
+					Expect(o.Config.Tools.ToolsUpgradePolicy).To(Equal(string(types.UpgradePolicyUpgradeAtPowerCycle)))
 
-						_, backing := getVMHomeDisk(ctx, vcVM, o)
-						Expect(backing.ThinProvisioned).To(PointTo(BeTrue()))
+					By("Policy is changed while powered on", func() {
+						vm.Spec.Advanced.ToolsUpgradePolicy = vmopv1.VirtualMachineToolsUpgradePolicyManual
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.tools"}, &o)).To(Succeed())
+						Expect(o.Config.Tools.ToolsUpgradePolicy).To(Equal(string(types.UpgradePolicyManual)))
 					})
 				})
 
-				XContext("VM has thick provisioning", func() {
-					BeforeEach(func() {
-						vm.Spec.Advanced.DefaultVolumeProvisioningMode = vmopv1.VirtualMachineVolumeProvisioningModeThick
-					})
+				It("Reports when the tools version is behind the host's version", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
 
-					It("Succeeds", func() {
-						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-						Expect(err).ToNot(HaveOccurred())
+					ctx.SetVirtualMachineToolsVersion(vcVM.Reference(), "12352",
+						types.VirtualMachineToolsVersionStatusGuestToolsCurrent)
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent)).To(BeTrue())
 
-						var o mo.VirtualMachine
-						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+					ctx.SetVirtualMachineToolsVersion(vcVM.Reference(), "11269",
+						types.VirtualMachineToolsVersionStatusGuestToolsNeedUpgrade)
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
 
-						/* vcsim CL deploy has "thick" but that isn't reflected for this disk. */
-						_, backing := getVMHomeDisk(ctx, vcVM, o)
-						Expect(backing.ThinProvisioned).To(PointTo(BeFalse()))
-					})
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionToolsVersionCurrent)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineToolsUpgradeAvailableReason))
+					Expect(c.Message).To(ContainSubstring("11269"))
 				})
+			})
 
-				XContext("VM has eager zero provisioning", func() {
-					BeforeEach(func() {
-						if vm.Spec.Advanced == nil {
-							vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
-						}
-						vm.Spec.Advanced.DefaultVolumeProvisioningMode = vmopv1.VirtualMachineVolumeProvisioningModeThickEagerZero
-					})
+			It("Updates the Status host after the VM is migrated", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+				srcHost := vm.Status.Host
+				Expect(srcHost).ToNot(BeEmpty())
 
-					It("Succeeds", func() {
-						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-						Expect(err).ToNot(HaveOccurred())
+				dstHostRef := ctx.SetVirtualMachineRuntimeHost(vcVM.Reference())
+				Expect(dstHostRef.Value).ToNot(Equal(vm.Status.HostMoID))
+				dstHost, err := object.NewHostSystem(vcVM.Client(), dstHostRef).ObjectName(ctx)
+				Expect(err).ToNot(HaveOccurred())
 
-						var o mo.VirtualMachine
-						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+				Expect(vm.Status.HostMoID).To(Equal(dstHostRef.Value))
+				Expect(vm.Status.Host).To(Equal(dstHost))
+				Expect(vm.Status.Host).ToNot(Equal(srcHost))
+			})
 
-						/* vcsim CL deploy has "eagerZeroedThick" but that isn't reflected for this disk. */
-						_, backing := getVMHomeDisk(ctx, vcVM, o)
-						Expect(backing.EagerlyScrub).To(PointTo(BeTrue()))
-					})
-				})
+			It("Sets the HA restarted condition after the VM is restarted on another host", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conditions.Get(vm, vmopv1.VirtualMachineConditionHARestarted)).To(BeNil())
 
-				Context("Should resize root disk", func() {
-					It("Succeeds", func() {
-						newSize := resource.MustParse("4242Gi")
+				Expect(vm.Status.BootTime).ToNot(BeNil())
 
-						if vm.Spec.Advanced == nil {
-							vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
-						}
-						vm.Spec.Advanced.BootDiskCapacity = &newSize
-						vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
-						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
-						Expect(err).ToNot(HaveOccurred())
+				dstHostRef := ctx.SimulateHARestart(vcVM.Reference())
 
-						var o mo.VirtualMachine
-						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
-						disk, _ := getVMHomeDisk(ctx, vcVM, o)
-						Expect(disk.CapacityInBytes).To(BeEquivalentTo(newSize.Value()))
-					})
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+				Expect(vm.Status.HostMoID).To(Equal(dstHostRef.Value))
+
+				c := conditions.Get(vm, vmopv1.VirtualMachineConditionHARestarted)
+				Expect(c).ToNot(BeNil())
+				Expect(c.Status).To(Equal(metav1.ConditionTrue))
+				Expect(c.Reason).To(Equal(vmopv1.VirtualMachineHARestartedHostFailureReason))
+
+				By("removes the condition once it has been observed", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(conditions.Get(vm, vmopv1.VirtualMachineConditionHARestarted)).To(BeNil())
 				})
 			})
 
-			Context("CNS Volumes", func() {
-				cnsVolumeName := "cns-volume-1"
+			Context("Boot order", func() {
 
-				It("CSI Volumes workflow", func() {
-					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
-					_, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
+				BeforeEach(func() {
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						BootOrder: []vmopv1.VirtualMachineBootDeviceType{
+							vmopv1.VirtualMachineBootDeviceTypeCDROM,
+							vmopv1.VirtualMachineBootDeviceTypeDisk,
+						},
+					}
+				})
 
-					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
-					By("Add CNS volume to VM", func() {
+				getBootOrder := func(vcVM *object.VirtualMachine) []types.BaseVirtualMachineBootOptionsBootableDevice {
+					var o mo.VirtualMachine
+					ExpectWithOffset(1, vcVM.Properties(ctx, vcVM.Reference(), []string{"config.bootOptions"}, &o)).To(Succeed())
+					ExpectWithOffset(1, o.Config.BootOptions).ToNot(BeNil())
+					return o.Config.BootOptions.BootOrder
+				}
+
+				It("Sets the boot order to the VM's disk", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					devices, err := vcVM.Device(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					disks := devices.SelectByType((*types.VirtualDisk)(nil))
+					Expect(disks).To(HaveLen(1))
+					Expect(devices.SelectByType((*types.VirtualCdrom)(nil))).To(BeEmpty())
+
+					Expect(getBootOrder(vcVM)).To(Equal([]types.BaseVirtualMachineBootOptionsBootableDevice{
+						&types.VirtualMachineBootOptionsBootableDiskDevice{DeviceKey: disks[0].GetVirtualDevice().Key},
+					}))
+
+					By("the VM does not have a CD-ROM to boot from", func() {
+						c := conditions.Get(vm, vmopv1.VirtualMachineConditionBootOrderSynced)
+						Expect(c).ToNot(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionFalse))
+						Expect(c.Reason).To(Equal(vmopv1.VirtualMachineBootOrderDeviceNotFoundReason))
+						Expect(c.Message).To(Equal("VM does not have a CDROM device to boot from"))
+					})
+				})
+
+				It("Defers the boot order change until the VM is powered off", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					bootOrder := getBootOrder(vcVM)
+
+					vm.Spec.Advanced.BootOrder = nil
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(conditions.Get(vm, vmopv1.VirtualMachineConditionBootOrderSynced)).To(BeNil())
+
+					vm.Spec.Advanced.BootOrder = []vmopv1.VirtualMachineBootDeviceType{vmopv1.VirtualMachineBootDeviceTypeNetwork}
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(getBootOrder(vcVM)).To(Equal(bootOrder))
+
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionBootOrderSynced)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineBootOrderPendingPowerOffReason))
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+
+					By("the VM does not have a network interface to boot from", func() {
+						Expect(getBootOrder(vcVM)).To(BeEmpty())
+						c := conditions.Get(vm, vmopv1.VirtualMachineConditionBootOrderSynced)
+						Expect(c).ToNot(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionFalse))
+						Expect(c.Reason).To(Equal(vmopv1.VirtualMachineBootOrderDeviceNotFoundReason))
+					})
+				})
+			})
+
+			Context("Serial ports", func() {
+
+				BeforeEach(func() {
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						SerialPorts: []vmopv1.VirtualMachineSerialPortSpec{
+							{
+								Name: "console",
+								URI:  "telnet://:13370",
+							},
+						},
+					}
+				})
+
+				getSerialPorts := func(vcVM *object.VirtualMachine) object.VirtualDeviceList {
+					devices, err := vcVM.Device(ctx)
+					ExpectWithOffset(1, err).ToNot(HaveOccurred())
+					return devices.SelectByType((*types.VirtualSerialPort)(nil))
+				}
+
+				It("Adds the serial port to the VM", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					serialPorts := getSerialPorts(vcVM)
+					Expect(serialPorts).To(HaveLen(1))
+					backing, ok := serialPorts[0].GetVirtualDevice().Backing.(*types.VirtualSerialPortURIBackingInfo)
+					Expect(ok).To(BeTrue())
+					Expect(backing.ServiceURI).To(Equal("telnet://:13370"))
+					Expect(backing.Direction).To(Equal("client"))
+
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionSerialPortsSynced)).To(BeTrue())
+					Expect(vm.Status.SerialPorts).To(HaveLen(1))
+					Expect(vm.Status.SerialPorts[0].Name).To(Equal("console"))
+					Expect(vm.Status.SerialPorts[0].Key).To(Equal(serialPorts[0].GetVirtualDevice().Key))
+				})
+
+				It("Defers the serial port change until the VM is powered off", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					Expect(getSerialPorts(vcVM)).To(HaveLen(1))
+
+					vm.Spec.Advanced.SerialPorts = append(vm.Spec.Advanced.SerialPorts, vmopv1.VirtualMachineSerialPortSpec{
+						Name: "debug",
+						URI:  "tcp://console.example.com:13371",
+					})
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(getSerialPorts(vcVM)).To(HaveLen(1))
+
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionSerialPortsSynced)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineSerialPortsPendingPowerOffReason))
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+
+					Expect(getSerialPorts(vcVM)).To(HaveLen(2))
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionSerialPortsSynced)).To(BeTrue())
+					Expect(vm.Status.SerialPorts).To(HaveLen(2))
+				})
+			})
+
+			Context("Events", func() {
+
+				receiveEvents := func() []string {
+					var events []string
+					for {
+						select {
+						case e := <-ctx.Events:
+							events = append(events, e)
+						default:
+							return events
+						}
+					}
+				}
+
+				It("records events for the power on with the vCenter task MoIDs", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					events := receiveEvents()
+					Expect(events).To(ContainElement(MatchRegexp(`^Normal ReconfigureStarted Reconfigure VM task task-\d+ started$`)))
+					Expect(events).To(ContainElement(MatchRegexp(`^Normal PowerOnStarted Power on VM task task-\d+ started$`)))
+				})
+
+				It("records a warning event when the power on fails", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					_ = receiveEvents()
+
+					ctx.InjectMethodFault("PowerOnVM_Task", &types.NotSupported{}, 1)
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).ToNot(Succeed())
+
+					events := receiveEvents()
+					Expect(events).To(ContainElement(HavePrefix("Warning PowerOnFailed Power on VM failed")))
+					Expect(events).ToNot(ContainElement(ContainSubstring("PowerOnStarted")))
+				})
+
+				It("records events when the guest heartbeat is detected and the customization completes", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+						guest.CustomizationInfo = &types.GuestInfoCustomizationInfo{
+							CustomizationStatus: string(types.GuestInfoCustomizationStatusTOOLSDEPLOYPKG_RUNNING),
+						}
+					})
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(receiveEvents()).ToNot(ContainElement(ContainSubstring("CustomizationCompleted")))
+
+					ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+						guest.ToolsRunningStatus = string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+						guest.CustomizationInfo = &types.GuestInfoCustomizationInfo{
+							CustomizationStatus: string(types.GuestInfoCustomizationStatusTOOLSDEPLOYPKG_SUCCEEDED),
+						}
+					})
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+					events := receiveEvents()
+					Expect(events).To(ContainElement("Normal GuestHeartbeatDetected VMware Tools is running in the guest"))
+					Expect(events).To(ContainElement("Normal CustomizationCompleted Guest customization completed"))
+
+					By("the events are not recorded again", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(receiveEvents()).To(BeEmpty())
+					})
+				})
+
+				It("records a warning event when the customization fails", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					_ = receiveEvents()
+
+					ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+						guest.CustomizationInfo = &types.GuestInfoCustomizationInfo{
+							CustomizationStatus: string(types.GuestInfoCustomizationStatusTOOLSDEPLOYPKG_FAILED),
+							ErrorMsg:            "some error",
+						}
+					})
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(receiveEvents()).To(ContainElement("Warning CustomizationFailed Guest customization failed: some error"))
+				})
+			})
+
+			Context("vSphere HA override", func() {
+
+				BeforeEach(func() {
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						HARestartPriority:   vmopv1.VirtualMachineHARestartPriorityHigh,
+						HAIsolationResponse: vmopv1.VirtualMachineHAIsolationResponseShutdown,
+					}
+				})
+
+				It("creates the cluster's DAS VM override with the requested settings", func() {
+					clusterRef := ctx.GetSingleClusterCompute().Reference()
+					ctx.SetClusterDRSAndHA(clusterRef, true, types.DrsBehaviorFullyAutomated, true)
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionHAOverrideSynced)).To(BeTrue())
+
+					dasVMConfig := ctx.GetClusterDasVmConfig(clusterRef, vcVM.Reference())
+					Expect(dasVMConfig).ToNot(BeNil())
+					Expect(dasVMConfig.DasSettings).ToNot(BeNil())
+					Expect(dasVMConfig.DasSettings.RestartPriority).To(Equal(string(types.ClusterDasVmSettingsRestartPriorityHigh)))
+					Expect(dasVMConfig.DasSettings.IsolationResponse).To(Equal(string(types.ClusterDasVmSettingsIsolationResponseShutdown)))
+
+					By("updates the override when the restart priority is changed", func() {
+						vm.Spec.Advanced.HARestartPriority = vmopv1.VirtualMachineHARestartPriorityLowest
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+						dasVMConfig := ctx.GetClusterDasVmConfig(clusterRef, vcVM.Reference())
+						Expect(dasVMConfig).ToNot(BeNil())
+						Expect(dasVMConfig.DasSettings.RestartPriority).To(Equal(string(types.ClusterDasVmSettingsRestartPriorityLowest)))
+					})
+				})
+
+				It("reports the failure in the condition when the cluster does not have HA enabled", func() {
+					clusterRef := ctx.GetSingleClusterCompute().Reference()
+					ctx.SetClusterDRSAndHA(clusterRef, true, types.DrsBehaviorFullyAutomated, false)
+
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+
+					Expect(conditions.IsFalse(vm, vmopv1.VirtualMachineConditionHAOverrideSynced)).To(BeTrue())
+					Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionHAOverrideSynced)).To(
+						Equal(vmopv1.VirtualMachineHAOverrideSyncFailedReason))
+					Expect(conditions.GetMessage(vm, vmopv1.VirtualMachineConditionHAOverrideSynced)).To(
+						Equal(fmt.Sprintf("cluster %s does not have vSphere HA enabled", clusterRef.Value)))
+
+					By("the condition is removed when the HA settings are removed from the spec", func() {
+						vm.Spec.Advanced = nil
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(conditions.Has(vm, vmopv1.VirtualMachineConditionHAOverrideSynced)).To(BeFalse())
+					})
+				})
+			})
+
+			Context("Label tags", func() {
+				BeforeEach(func() {
+					Expect(os.Setenv(lib.LabelTagCategoriesEnv, "backup-policy")).To(Succeed())
+				})
+
+				AfterEach(func() {
+					Expect(os.Unsetenv(lib.LabelTagCategoriesEnv)).To(Succeed())
+				})
+
+				It("Syncs the labels as vSphere tags", func() {
+					vm.Labels = map[string]string{"backup-policy": "daily"}
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionTagsSynced)).To(BeTrue())
+
+					m := tags.NewManager(ctx.RestClient)
+					attached, err := m.GetAttachedTags(ctx, vcVM.Reference())
+					Expect(err).ToNot(HaveOccurred())
+					Expect(attached).To(HaveLen(1))
+					Expect(attached[0].Name).To(Equal("daily"))
+				})
+
+				It("Reports the failure to sync the tags in the condition", func() {
+					vm.Labels = map[string]string{"backup-policy": "daily"}
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					m := tags.NewManager(ctx.RestClient)
+					category, err := m.GetCategory(ctx, "backup-policy")
+					Expect(err).ToNot(HaveOccurred())
+					tag, err := m.GetTagForCategory(ctx, "daily", category.ID)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(m.DeleteTag(ctx, tag)).To(Succeed())
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+					Expect(conditions.IsFalse(vm, vmopv1.VirtualMachineConditionTagsSynced)).To(BeTrue())
+					Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionTagsSynced)).To(Equal(vmopv1.VirtualMachineTagsSyncFailedReason))
+
+					By("Syncs the tags on the next update", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionTagsSynced)).To(BeTrue())
+
+						attached, err := m.GetAttachedTags(ctx, vcVM.Reference())
+						Expect(err).ToNot(HaveOccurred())
+						Expect(attached).To(HaveLen(1))
+					})
+				})
+			})
+
+			Context("Display name", func() {
+				It("Renames the vSphere VM to the display name", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					vm.Annotations[constants.DisplayNameAnnotation] = "my-display-name"
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionDisplayNameSynced)).To(BeTrue())
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"name"}, &o)).To(Succeed())
+					Expect(o.Name).To(Equal("my-display-name"))
+					Expect(vm.Name).ToNot(Equal("my-display-name"))
+
+					By("VM is still found after it is renamed", func() {
+						vm.Status.UniqueID = ""
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(vm.Status.UniqueID).To(Equal(vcVM.Reference().Value))
+					})
+				})
+
+				It("Does not rename the vSphere VM when the display name is already in use", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"parent"}, &o)).To(Succeed())
+					folder := object.NewFolder(ctx.VCClient.Client, *o.Parent)
+					_, err = folder.CreateFolder(ctx, "my-display-name")
+					Expect(err).ToNot(HaveOccurred())
+
+					vm.Annotations[constants.DisplayNameAnnotation] = "my-display-name"
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(conditions.IsFalse(vm, vmopv1.VirtualMachineConditionDisplayNameSynced)).To(BeTrue())
+					Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionDisplayNameSynced)).To(
+						Equal(vmopv1.VirtualMachineDisplayNameConflictReason))
+
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"name"}, &o)).To(Succeed())
+					Expect(o.Name).To(Equal(vm.Name))
+				})
+			})
+
+			Context("VM reconfigure is in-flight", func() {
+				BeforeEach(func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(os.Setenv(lib.VMTaskProgressIntervalEnv, "10ms")).To(Succeed())
+				})
+
+				AfterEach(func() {
+					Expect(os.Unsetenv(lib.VMTaskProgressIntervalEnv)).To(Succeed())
+				})
+
+				It("writes the reconfigure task progress to the VM's status until the task completes", func() {
+					Expect(ctx.Client.Create(ctx, vm)).To(Succeed())
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(vmClass), vmClass)).To(Succeed())
+					vmClass.Spec.Hardware.Cpus++
+					Expect(ctx.Client.Update(ctx, vmClass)).To(Succeed())
+					ctx.SimulateLongRunningReconfigureTask()
+
+					// The VM is reconfigured before it is powered on.
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					updateVM := vm.DeepCopy()
+					updateErr := make(chan error, 1)
+					go func() {
+						defer GinkgoRecover()
+						updateErr <- vmProvider.CreateOrUpdateVirtualMachine(ctx, updateVM)
+					}()
+
+					Eventually(ctx.LongRunningTasks).Should(HaveLen(1))
+					task := ctx.LongRunningTasks()[0]
+
+					getTaskStatus := func() *vmopv1.VirtualMachineTaskStatus {
+						obj := &vmopv1.VirtualMachine{}
+						Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(vm), obj)).To(Succeed())
+						return obj.Status.Task
+					}
+
+					Eventually(getTaskStatus).ShouldNot(BeNil())
+					taskStatus := getTaskStatus()
+					Expect(taskStatus.Operation).To(Equal("update"))
+					Expect(taskStatus.Phase).To(Equal(string(types.TaskInfoStateRunning)))
+
+					By("a concurrent update returns a transient error", func() {
+						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm.DeepCopy())
+						Expect(errors.Is(err, providererrors.ErrTransient)).To(BeTrue())
+
+						Expect(vmProvider.UpdateVirtualMachineTaskStatus(ctx, vm)).To(Succeed())
+						Expect(vm.Status.Task).ToNot(BeNil())
+						Expect(vm.Status.Task.Operation).To(Equal("update"))
+					})
+
+					By("status is cleared once the task completes", func() {
+						ctx.CompleteLongRunningTask(task, nil)
+						Eventually(updateErr).Should(Receive(BeNil()))
+						Expect(getTaskStatus()).To(BeNil())
+						Expect(updateVM.Status.Task).To(BeNil())
+					})
+				})
+			})
+
+			Context("Dry run reconfigure", func() {
+
+				BeforeEach(func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+				})
+
+				It("Records the reconfigure in the status instead of applying it", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.ReconfigurePlan).To(BeNil())
+
+					Expect(ctx.Client.Get(ctx, client.ObjectKeyFromObject(vmClass), vmClass)).To(Succeed())
+					vmClass.Spec.Hardware.Cpus++
+					Expect(ctx.Client.Update(ctx, vmClass)).To(Succeed())
+
+					vm.Annotations[constants.DryRunReconfigureAnnotation] = ""
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					calls := len(ctx.MethodCalls(vcVM.Reference()))
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+					newCalls := ctx.MethodCalls(vcVM.Reference())[calls:]
+					Expect(newCalls).ToNot(ContainElement("ReconfigVM_Task"))
+					Expect(newCalls).ToNot(ContainElement("PowerOnVM_Task"))
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+
+					plan := vm.Status.ReconfigurePlan
+					Expect(plan).ToNot(BeNil())
+					Expect(plan.ConfigKeys).To(ContainElement("numCPUs"))
+					Expect(plan.ConfigSpecs).To(HaveLen(1))
+
+					By("Plan matches the reconfigure once the annotation is removed", func() {
+						delete(vm.Annotations, constants.DryRunReconfigureAnnotation)
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+						Expect(vm.Status.ReconfigurePlan).To(BeNil())
+
+						configSpec := ctx.LastReconfigureSpec()
+						Expect(configSpec).ToNot(BeNil())
+						var w bytes.Buffer
+						Expect(types.NewJSONEncoder(&w).Encode(configSpec)).To(Succeed())
+						Expect(plan.ConfigSpecs[0]).To(MatchJSON(w.Bytes()))
+					})
+				})
+
+				It("Records the power state, display name, and tag changes instead of making them", func() {
+					Expect(os.Setenv(lib.LabelTagCategoriesEnv, "backup-policy")).To(Succeed())
+					defer func() {
+						Expect(os.Unsetenv(lib.LabelTagCategoriesEnv)).To(Succeed())
+					}()
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					vm.Annotations[constants.DryRunReconfigureAnnotation] = ""
+					vm.Annotations[constants.DisplayNameAnnotation] = "my-display-name"
+					vm.Labels = map[string]string{"backup-policy": "daily"}
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateSuspended
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+					plan := vm.Status.ReconfigurePlan
+					Expect(plan).ToNot(BeNil())
+					Expect(plan.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateSuspended))
+					Expect(plan.DisplayName).To(Equal("my-display-name"))
+					Expect(plan.AttachedTags).To(Equal([]vmopv1.VirtualMachineTagStatus{
+						{Category: "backup-policy", Name: "daily"},
+					}))
+
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					Expect(conditions.Has(vm, vmopv1.VirtualMachineConditionDisplayNameSynced)).To(BeFalse())
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"name"}, &o)).To(Succeed())
+					Expect(o.Name).To(Equal(vm.Name))
+
+					attached, err := tags.NewManager(ctx.RestClient).GetAttachedTags(ctx, vcVM.Reference())
+					Expect(err).ToNot(HaveOccurred())
+					Expect(attached).To(BeEmpty())
+				})
+
+				It("Does not create the VM", func() {
+					vm.Annotations[constants.DryRunReconfigureAnnotation] = ""
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.LastCloneSpec()).To(BeNil())
+					Expect(vm.Status.UniqueID).To(BeEmpty())
+					Expect(conditions.GetReason(vm, vmopv1.VirtualMachineConditionDryRun)).To(Equal(vmopv1.VirtualMachineDryRunWouldCreateReason))
+				})
+
+				It("Records the vSphere HA override in the plan instead of reconfiguring the cluster", func() {
+					clusterRef := ctx.GetSingleClusterCompute().Reference()
+					ctx.SetClusterDRSAndHA(clusterRef, true, types.DrsBehaviorFullyAutomated, true)
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					vm.Annotations[constants.DryRunReconfigureAnnotation] = ""
+					vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+						HARestartPriority: vmopv1.VirtualMachineHARestartPriorityHigh,
+					}
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.GetClusterDasVmConfig(clusterRef, vcVM.Reference())).To(BeNil())
+
+					plan := vm.Status.ReconfigurePlan
+					Expect(plan).ToNot(BeNil())
+					Expect(plan.ClusterConfigSpecs).To(HaveLen(1))
+					Expect(plan.ClusterConfigSpecs[0]).To(ContainSubstring(string(types.ClusterDasVmSettingsRestartPriorityHigh)))
+				})
+			})
+
+			It("Diagnoses a VM whose image is not ready", func() {
+				image := builder.DummyClusterVirtualMachineImageA2("not-ready-image")
+				Expect(ctx.Client.Create(ctx, image)).To(Succeed())
+				conditions.MarkFalse(image, vmopv1.ReadyConditionType, "Importing", "image is importing")
+				Expect(ctx.Client.Status().Update(ctx, image)).To(Succeed())
+				vm.Spec.ImageName = image.Name
+
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).ToNot(Succeed())
+
+				diagnostic, err := vmProvider.GetVirtualMachineDiagnostic(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				blocking := diagnostic.Blocking()
+				Expect(blocking).ToNot(BeNil())
+				Expect(blocking.Check).To(Equal(vmprovider.DiagnosticCheckImage))
+				Expect(blocking.Reason).To(Equal(vmopv1.VirtualMachineImageNotReadyReason))
+				Expect(blocking.Message).To(Equal("image is importing"))
+			})
+
+			It("Diagnoses a VM that is not powered on", func() {
+				vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+				_, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				diagnostic, err := vmProvider.GetVirtualMachineDiagnostic(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(diagnostic.Blocking()).To(BeNil())
+
+				vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+				diagnostic, err = vmProvider.GetVirtualMachineDiagnostic(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				blocking := diagnostic.Blocking()
+				Expect(blocking).ToNot(BeNil())
+				Expect(blocking.Check).To(Equal(vmprovider.DiagnosticCheckPowerState))
+			})
+
+			It("Reports the attached vSphere tags", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vm.Status.Tags).To(BeEmpty())
+
+				ctx.TagObject(vcVM.Reference(), "compliance", "pci")
+
+				expected := []vmopv1.VirtualMachineTagStatus{{Category: "compliance", Name: "pci"}}
+				Expect(vmProvider.GetVirtualMachineTags(ctx, vm)).To(Equal(expected))
+
+				By("Status is not fetched again while the synced labels are unchanged", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.Tags).To(BeEmpty())
+				})
+
+				By("Status is updated when the synced labels change", func() {
+					Expect(os.Setenv(lib.LabelTagCategoriesEnv, "backup-policy")).To(Succeed())
+					defer func() {
+						Expect(os.Unsetenv(lib.LabelTagCategoriesEnv)).To(Succeed())
+					}()
+
+					vm.Labels = map[string]string{"backup-policy": "daily"}
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.Tags).To(Equal([]vmopv1.VirtualMachineTagStatus{
+						{Category: "backup-policy", Name: "daily"},
+						{Category: "compliance", Name: "pci"},
+					}))
+				})
+			})
+
+			Context("Reconcile fast-path", func() {
+
+				BeforeEach(func() {
+					Expect(os.Setenv(lib.VMFullReconcileIntervalEnv, "3")).To(Succeed())
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					vm.Annotations[constants.DisplayNameAnnotation] = "display-name"
+				})
+
+				AfterEach(func() {
+					Expect(os.Unsetenv(lib.VMFullReconcileIntervalEnv)).To(Succeed())
+				})
+
+				// changeOutOfBand renames the VM outside of VM Operator. Only a full reconcile renames
+				// the VM back to its display name.
+				changeOutOfBand := func(vcVM *object.VirtualMachine) {
+					task, err := vcVM.Rename(ctx, "renamed")
+					ExpectWithOffset(1, err).ToNot(HaveOccurred())
+					ExpectWithOffset(1, task.Wait(ctx)).To(Succeed())
+				}
+
+				isFullyReconciled := func(vcVM *object.VirtualMachine) bool {
+					name, err := vcVM.ObjectName(ctx)
+					ExpectWithOffset(1, err).ToNot(HaveOccurred())
+					return name == "display-name"
+				}
+
+				It("Skips the full reconcile while the VM is unchanged", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.ObservedGeneration).To(Equal(vm.Generation))
+
+					changeOutOfBand(vcVM)
+
+					By("the VMware Tools status is still updated", func() {
+						ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+							guest.ToolsRunningStatus = string(types.VirtualMachineToolsRunningStatusGuestToolsNotRunning)
+						})
+
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(isFullyReconciled(vcVM)).To(BeFalse())
+						Expect(conditions.GetReason(vm, vmopv1.VirtualMachineToolsCondition)).To(Equal(vmopv1.VirtualMachineToolsNotRunningReason))
+					})
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(isFullyReconciled(vcVM)).To(BeFalse())
+
+					By("every third reconcile is a full reconcile", func() {
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(isFullyReconciled(vcVM)).To(BeTrue())
+					})
+				})
+
+				It("Does a full reconcile when the VM is changed", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					changeOutOfBand(vcVM)
+
+					vm.Labels = map[string]string{"foo": "bar"}
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(isFullyReconciled(vcVM)).To(BeTrue())
+				})
+
+				It("Does a full reconcile when the StorageClass is changed", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					changeOutOfBand(vcVM)
+
+					storageClass := &storagev1.StorageClass{}
+					Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: vm.Spec.StorageClass}, storageClass)).To(Succeed())
+					storageClass.Labels = map[string]string{"foo": "bar"}
+					Expect(ctx.Client.Update(ctx, storageClass)).To(Succeed())
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(isFullyReconciled(vcVM)).To(BeTrue())
+				})
+
+				It("Does a full reconcile when the primary IP is changed", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					changeOutOfBand(vcVM)
+
+					ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+						guest.IpAddress = "10.0.0.3"
+					})
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(isFullyReconciled(vcVM)).To(BeTrue())
+					Expect(vm.Status.Network).ToNot(BeNil())
+					Expect(vm.Status.Network.PrimaryIP4).To(Equal("10.0.0.3"))
+				})
+
+				It("Does a full reconcile when a class the VM's class inherits from is changed", func() {
+					baseClass := builder.DummyVirtualMachineClass2A2("base-vm-class")
+					baseClass.Namespace = vmClass.Namespace
+					Expect(ctx.Client.Create(ctx, baseClass)).To(Succeed())
+					vmClass = ctx.UpdateVirtualMachineClass(vmClass.Name, func(vmClass *vmopv1.VirtualMachineClass) {
+						vmClass.Spec.InheritsFrom = baseClass.Name
+					})
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					changeOutOfBand(vcVM)
+
+					configSpec, err := util.MarshalConfigSpecToJSON(&types.VirtualMachineConfigSpec{
+						ExtraConfig: []types.BaseOptionValue{&types.OptionValue{Key: "base-key", Value: "base"}},
+					})
+					Expect(err).ToNot(HaveOccurred())
+					ctx.UpdateVirtualMachineClass(baseClass.Name, func(baseClass *vmopv1.VirtualMachineClass) {
+						baseClass.Spec.ConfigSpec = configSpec
+					})
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(isFullyReconciled(vcVM)).To(BeTrue())
+				})
+
+				It("Does a full reconcile when the power state is changed outside of VM Operator", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					changeOutOfBand(vcVM)
+
+					task, err := vcVM.PowerOn(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(task.Wait(ctx)).To(Succeed())
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+				})
+
+				It("Does a full reconcile while the guest is being customized", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					ctx.UpdateVirtualMachineGuestInfo(vcVM.Reference(), func(guest *types.GuestInfo) {
+						guest.ToolsRunningStatus = string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+						guest.IpAddress = "10.0.0.2"
+						guest.CustomizationInfo = &types.GuestInfoCustomizationInfo{
+							CustomizationStatus: string(types.GuestInfoCustomizationStatusTOOLSDEPLOYPKG_RUNNING),
+						}
+					})
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.Network).ToNot(BeNil())
+					Expect(vm.Status.Network.PrimaryIP4).ToNot(BeEmpty())
+					Expect(conditions.GetReason(vm, vmopv1.GuestCustomizationCondition)).To(Equal(vmopv1.GuestCustomizationRunningReason))
+
+					changeOutOfBand(vcVM)
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(isFullyReconciled(vcVM)).To(BeTrue())
+				})
+			})
+
+			Context("First class disks", func() {
+
+				var diskID string
+
+				BeforeEach(func() {
+					vm.Spec.Volumes = append(vm.Spec.Volumes, vmopv1.VirtualMachineVolume{
+						Name: "fcd-volume",
+					})
+				})
+
+				JustBeforeEach(func() {
+					diskID = ctx.CreateFirstClassDisk("test-fcd", 10)
+					vm.Spec.Volumes[0].FirstClassDisk = &vmopv1.FirstClassDiskVolumeSource{DiskID: diskID}
+				})
+
+				getFirstClassDisk := func(vcVM *object.VirtualMachine) *types.VirtualDisk {
+					devices, err := vcVM.Device(ctx)
+					ExpectWithOffset(1, err).ToNot(HaveOccurred())
+					return virtualmachine.GetFirstClassDisks(devices)[diskID]
+				}
+
+				It("Attaches the first class disk and detaches it when the volume is removed", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					disk := getFirstClassDisk(vcVM)
+					Expect(disk).ToNot(BeNil())
+					Expect(disk.UnitNumber).ToNot(BeNil())
+
+					Expect(vm.Status.Volumes).To(HaveLen(1))
+					volStatus := vm.Status.Volumes[0]
+					Expect(volStatus.Name).To(Equal("fcd-volume"))
+					Expect(volStatus.Attached).To(BeTrue())
+					Expect(volStatus.DiskID).To(Equal(diskID))
+					Expect(volStatus.DiskUUID).ToNot(BeEmpty())
+					Expect(volStatus.ControllerKey).To(Equal(disk.ControllerKey))
+					Expect(volStatus.UnitNumber).To(Equal(disk.UnitNumber))
+					Expect(volStatus.Capacity).ToNot(BeNil())
+					Expect(volStatus.Capacity.Value()).To(BeEquivalentTo(10 * 1024 * 1024))
+
+					By("the volume is removed", func() {
+						vm.Spec.Volumes = nil
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(getFirstClassDisk(vcVM)).To(BeNil())
+						Expect(vm.Status.Volumes).To(BeEmpty())
+					})
+
+					By("the first class disk is not deleted", func() {
+						datastores, err := ctx.Finder.DatastoreList(ctx, "*")
+						Expect(err).ToNot(HaveOccurred())
+						_, err = virtualmachine.FindFirstClassDisk(ctx, ctx.VCClient.Client, datastores, diskID)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
+
+				It("Does not delete the first class disk when the VM is deleted", func() {
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+
+					datastores, err := ctx.Finder.DatastoreList(ctx, "*")
+					Expect(err).ToNot(HaveOccurred())
+					_, err = virtualmachine.FindFirstClassDisk(ctx, ctx.VCClient.Client, datastores, diskID)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("Returns an error when the first class disk does not exist", func() {
+					vm.Spec.Volumes[0].FirstClassDisk.DiskID = "does-not-exist"
+					err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+					Expect(err).To(MatchError("first class disk does-not-exist not found"))
+				})
+
+				It("Returns an error when the first class disk is attached to another VM", func() {
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					vm2 := builder.DummyBasicVirtualMachineA2("test-vm-2", nsInfo.Namespace)
+					vm2.Spec.ClassName = vm.Spec.ClassName
+					vm2.Spec.ImageName = vm.Spec.ImageName
+					vm2.Spec.StorageClass = vm.Spec.StorageClass
+					vm2.Spec.Network = vm.Spec.Network
+					vm2.Spec.Volumes = vm.Spec.Volumes
+					err = vmProvider.CreateOrUpdateVirtualMachine(ctx, vm2)
+					Expect(err).To(MatchError(fmt.Sprintf("first class disk %s is already attached to VM %s", diskID, vcVM.Reference().Value)))
+				})
+			})
+
+			Context("Hardware version upgrade", func() {
+
+				BeforeEach(func() {
+					// vcsim always upgrades a VM to vmx-13, and the test VMs are created at vmx-09.
+					testConfig.WithHardwareVersionUpgradeTarget = 13
+				})
+
+				getHardwareVersion := func(vcVM *object.VirtualMachine) string {
+					var o mo.VirtualMachine
+					ExpectWithOffset(1, vcVM.Properties(ctx, vcVM.Reference(), []string{"config.version"}, &o)).To(Succeed())
+					return o.Config.Version
+				}
+
+				It("Upgrades the VM before it is first powered on", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					calls := ctx.MethodCalls(vcVM.Reference())
+					Expect(calls).To(ContainElement("UpgradeVM_Task"))
+					Expect(calls[len(calls)-1]).To(Equal("PowerOnVM_Task"))
+					Expect(getHardwareVersion(vcVM)).To(Equal("vmx-13"))
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)).To(BeTrue())
+				})
+
+				It("Upgrades a powered on VM only once it is powered off", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+
+					ctx.SetVirtualMachineHardwareVersion(vcVM.Reference(), "vmx-09")
+					calls := len(ctx.MethodCalls(vcVM.Reference()))
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.MethodCalls(vcVM.Reference())[calls:]).ToNot(ContainElement("UpgradeVM_Task"))
+					Expect(getHardwareVersion(vcVM)).To(Equal("vmx-09"))
+					c := conditions.Get(vm, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)
+					Expect(c).ToNot(BeNil())
+					Expect(c.Status).To(Equal(metav1.ConditionFalse))
+					Expect(c.Reason).To(Equal(vmopv1.VirtualMachineHardwareVersionUpgradePendingPowerOffReason))
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+					Expect(ctx.MethodCalls(vcVM.Reference())[calls:]).To(ContainElement("UpgradeVM_Task"))
+					Expect(getHardwareVersion(vcVM)).To(Equal("vmx-13"))
+					Expect(conditions.IsTrue(vm, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)).To(BeTrue())
+				})
+
+				When("the VM is already at the target hardware version", func() {
+					BeforeEach(func() {
+						testConfig.WithHardwareVersionUpgradeTarget = 9
+					})
+
+					It("Does not upgrade the VM", func() {
+						vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+						Expect(ctx.MethodCalls(vcVM.Reference())).ToNot(ContainElement("UpgradeVM_Task"))
+						Expect(getHardwareVersion(vcVM)).To(Equal("vmx-09"))
+						Expect(conditions.Has(vm, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)).To(BeFalse())
+					})
+				})
+
+				When("the VM's cluster does not support the target hardware version", func() {
+					BeforeEach(func() {
+						testConfig.WithHardwareVersionUpgradeTarget = 99
+					})
+
+					It("Reports the upgrade is not supported without failing the update", func() {
+						vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+
+						Expect(ctx.MethodCalls(vcVM.Reference())).ToNot(ContainElement("UpgradeVM_Task"))
+						c := conditions.Get(vm, vmopv1.VirtualMachineConditionHardwareVersionUpgraded)
+						Expect(c).ToNot(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionFalse))
+						Expect(c.Reason).To(Equal(vmopv1.VirtualMachineHardwareVersionUpgradeNotSupportedReason))
+					})
+				})
+			})
+
+			It("returns error when StorageClass is required but none specified", func() {
+				vm.Spec.StorageClass = ""
+				err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+				Expect(err).To(MatchError("StorageClass is required but not specified"))
+			})
+
+			It("Can be called multiple times", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				var o mo.VirtualMachine
+				Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+				modified := o.Config.Modified
+
+				_, err = createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+				// Try to assert nothing changed.
+				Expect(o.Config.Modified).To(Equal(modified))
+			})
+
+			Context("VM Metadata", func() {
+
+				Context("ExtraConfig Transport", func() {
+					var ec map[string]interface{}
+
+					JustBeforeEach(func() {
+						configMap := &corev1.ConfigMap{
+							ObjectMeta: metav1.ObjectMeta{
+								GenerateName: "md-configmap-",
+								Namespace:    vm.Namespace,
+							},
+							Data: map[string]string{
+								"foo.bar":       "should-be-ignored",
+								"guestinfo.Foo": "foo",
+							},
+						}
+						Expect(ctx.Client.Create(ctx, configMap)).To(Succeed())
+
+						/*
+							vm.Spec.VmMetadata = &vmopv1.VirtualMachineMetadata{
+								ConfigMapName: configMap.Name,
+								Transport:     vmopv1.VirtualMachineMetadataExtraConfigTransport,
+							}
+						*/
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+						ec = map[string]interface{}{}
+						for _, option := range o.Config.ExtraConfig {
+							if val := option.GetOptionValue(); val != nil {
+								ec[val.Key] = val.Value.(string)
+							}
+						}
+					})
+
+					AfterEach(func() {
+						ec = nil
+					})
+
+					// TODO: As is we can't really honor "guestinfo.*" prefix
+					XIt("Metadata data is included in ExtraConfig", func() {
+						Expect(ec).ToNot(HaveKey("foo.bar"))
+						Expect(ec).To(HaveKeyWithValue("guestinfo.Foo", "foo"))
+
+						By("Should include default keys and values", func() {
+							Expect(ec).To(HaveKeyWithValue("disk.enableUUID", "TRUE"))
+							Expect(ec).To(HaveKeyWithValue("vmware.tools.gosc.ignoretoolscheck", "TRUE"))
+						})
+					})
+
+					Context("JSON_EXTRA_CONFIG is specified", func() {
+						BeforeEach(func() {
+							b, err := json.Marshal(
+								struct {
+									Foo string
+									Bar string
+								}{
+									Foo: "f00",
+									Bar: "42",
+								},
+							)
+							Expect(err).ToNot(HaveOccurred())
+							testConfig.WithJSONExtraConfig = string(b)
+						})
+
+						It("Global config is included in ExtraConfig", func() {
+							Expect(ec).To(HaveKeyWithValue("Foo", "f00"))
+							Expect(ec).To(HaveKeyWithValue("Bar", "42"))
+						})
+					})
+				})
+			})
+
+			Context("Network", func() {
+
+				It("Should not have a nic", func() {
+					Expect(vm.Spec.Network.Disabled).To(BeTrue())
+
+					vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+					devList := object.VirtualDeviceList(o.Config.Hardware.Device)
+					l := devList.SelectByType(&types.VirtualEthernetCard{})
+					Expect(l).To(BeEmpty())
+				})
+
+				Context("Multiple NICs are specified", func() {
+					BeforeEach(func() {
+						testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+
+						vm.Spec.Network.Disabled = false
+						vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+							{
+								Name:    "eth0",
+								Network: common.PartialObjectRef{Name: "VM Network"},
+							},
+							{
+								Name:    "eth1",
+								Network: common.PartialObjectRef{Name: dvpgName},
+							},
+						}
+					})
+
+					It("Has expected devices", func() {
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+						devList := object.VirtualDeviceList(o.Config.Hardware.Device)
+						l := devList.SelectByType(&types.VirtualEthernetCard{})
+						Expect(l).To(HaveLen(2))
+
+						dev1 := l[0].GetVirtualDevice()
+						backing1, ok := dev1.Backing.(*types.VirtualEthernetCardNetworkBackingInfo)
+						Expect(ok).Should(BeTrue())
+						Expect(backing1.DeviceName).To(Equal("VM Network"))
+
+						dev2 := l[1].GetVirtualDevice()
+						backing2, ok := dev2.Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+						Expect(ok).Should(BeTrue())
+						_, dvpg := getDVPG(ctx, dvpgName)
+						Expect(backing2.Port.PortgroupKey).To(Equal(dvpg.Reference().Value))
+					})
+				})
+			})
+
+			Context("Disks", func() {
+
+				Context("VM has thin provisioning", func() {
+					BeforeEach(func() {
+						if vm.Spec.Advanced == nil {
+							vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+						}
+						vm.Spec.Advanced.DefaultVolumeProvisioningMode = vmopv1.VirtualMachineVolumeProvisioningModeThin
+					})
+
+					It("Succeeds", func() {
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+						_, backing := getVMHomeDisk(ctx, vcVM, o)
+						Expect(backing.ThinProvisioned).To(PointTo(BeTrue()))
+					})
+				})
+
+				XContext("VM has thick provisioning", func() {
+					BeforeEach(func() {
+						vm.Spec.Advanced.DefaultVolumeProvisioningMode = vmopv1.VirtualMachineVolumeProvisioningModeThick
+					})
+
+					It("Succeeds", func() {
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+						/* vcsim CL deploy has "thick" but that isn't reflected for this disk. */
+						_, backing := getVMHomeDisk(ctx, vcVM, o)
+						Expect(backing.ThinProvisioned).To(PointTo(BeFalse()))
+					})
+				})
+
+				XContext("VM has eager zero provisioning", func() {
+					BeforeEach(func() {
+						if vm.Spec.Advanced == nil {
+							vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+						}
+						vm.Spec.Advanced.DefaultVolumeProvisioningMode = vmopv1.VirtualMachineVolumeProvisioningModeThickEagerZero
+					})
+
+					It("Succeeds", func() {
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+
+						/* vcsim CL deploy has "eagerZeroedThick" but that isn't reflected for this disk. */
+						_, backing := getVMHomeDisk(ctx, vcVM, o)
+						Expect(backing.EagerlyScrub).To(PointTo(BeTrue()))
+					})
+				})
+
+				Context("Should resize root disk", func() {
+					It("Succeeds", func() {
+						newSize := resource.MustParse("4242Gi")
+
+						if vm.Spec.Advanced == nil {
+							vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+						}
+						vm.Spec.Advanced.BootDiskCapacity = &newSize
+						vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+						vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+						Expect(err).ToNot(HaveOccurred())
+
+						var o mo.VirtualMachine
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), nil, &o)).To(Succeed())
+						disk, _ := getVMHomeDisk(ctx, vcVM, o)
+						Expect(disk.CapacityInBytes).To(BeEquivalentTo(newSize.Value()))
+					})
+				})
+			})
+
+			Context("CNS Volumes", func() {
+				cnsVolumeName := "cns-volume-1"
+
+				It("CSI Volumes workflow", func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+					By("Add CNS volume to VM", func() {
 						vm.Spec.Volumes = []vmopv1.VirtualMachineVolume{
 							{
 								Name: cnsVolumeName,
@@ -1594,228 +3738,1058 @@ func vmTests() {
 									},
 								},
 							},
-						}
+						}
+
+						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("status update pending for persistent volume: %s on VM", cnsVolumeName)))
+						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+					})
+
+					By("CNS volume is not attached", func() {
+						errMsg := "blah blah blah not attached"
+
+						vm.Status.Volumes = []vmopv1.VirtualMachineVolumeStatus{
+							{
+								Name:     cnsVolumeName,
+								Attached: false,
+								Error:    errMsg,
+							},
+						}
+
+						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("persistent volume: %s not attached to VM", cnsVolumeName)))
+						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+					})
+
+					By("CNS volume is attached", func() {
+						vm.Status.Volumes = []vmopv1.VirtualMachineVolumeStatus{
+							{
+								Name:     cnsVolumeName,
+								Attached: true,
+							},
+						}
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					})
+				})
+			})
+
+			Context("When fault domains is enabled", func() {
+				const zoneName = "az-1"
+
+				BeforeEach(func() {
+					testConfig.WithFaultDomains = true
+					// Explicitly place the VM into one of the zones that the test context will create.
+					vm.Labels[topology.KubernetesTopologyZoneLabelKey] = zoneName
+				})
+
+				It("Reverse lookups existing VM into correct zone", func() {
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(vm.Labels).To(HaveKeyWithValue(topology.KubernetesTopologyZoneLabelKey, zoneName))
+					Expect(vm.Status.Zone).To(Equal(zoneName))
+					delete(vm.Labels, topology.KubernetesTopologyZoneLabelKey)
+
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Labels).To(HaveKeyWithValue(topology.KubernetesTopologyZoneLabelKey, zoneName))
+					Expect(vm.Status.Zone).To(Equal(zoneName))
+				})
+			})
+		})
+
+		Context("VM SetResourcePolicy", func() {
+			var resourcePolicy *vmopv1.VirtualMachineSetResourcePolicy
+
+			JustBeforeEach(func() {
+				resourcePolicyName := "test-policy"
+				resourcePolicy = getVirtualMachineSetResourcePolicy(resourcePolicyName, nsInfo.Namespace)
+				Expect(vmProvider.CreateOrUpdateVirtualMachineSetResourcePolicy(ctx, resourcePolicy)).To(Succeed())
+				Expect(ctx.Client.Create(ctx, resourcePolicy)).To(Succeed())
+
+				vm.Annotations["vsphere-cluster-module-group"] = resourcePolicy.Spec.ClusterModuleGroups[0]
+				if vm.Spec.Reserved == nil {
+					vm.Spec.Reserved = &vmopv1.VirtualMachineReservedSpec{}
+				}
+				vm.Spec.Reserved.ResourcePolicyName = resourcePolicy.Name
+			})
+
+			AfterEach(func() {
+				resourcePolicy = nil
+			})
+
+			It("VM is created in child Folder and ResourcePool", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("has expected inventory path", func() {
+					Expect(vcVM.InventoryPath).To(HaveSuffix(
+						fmt.Sprintf("/%s/%s/%s", nsInfo.Namespace, resourcePolicy.Spec.Folder, vm.Name)))
+				})
+
+				By("has expected namespace resource pool", func() {
+					rp, err := vcVM.ResourcePool(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					childRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", resourcePolicy.Spec.ResourcePool.Name)
+					Expect(childRP).ToNot(BeNil())
+					Expect(rp.Reference().Value).To(Equal(childRP.Reference().Value))
+				})
+			})
+
+			It("Cluster Modules", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				members, err := cluster.NewManager(ctx.RestClient).ListModuleMembers(ctx, resourcePolicy.Status.ClusterModules[0].ModuleUuid)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(members).To(ContainElements(vcVM.Reference()))
+			})
+
+			It("Returns error with non-existence cluster module", func() {
+				vm.Annotations["vsphere-cluster-module-group"] = "bogusClusterMod"
+				err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+				Expect(err).To(MatchError("ClusterModule bogusClusterMod not found"))
+			})
+		})
+
+		Context("Delete VM", func() {
+			JustBeforeEach(func() {
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+			})
+
+			Context("when the VM is off", func() {
+				BeforeEach(func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+				})
+
+				It("deletes the VM", func() {
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+
+					uniqueID := vm.Status.UniqueID
+					Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
+				})
+			})
+
+			It("when the VM is on", func() {
+				Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+
+				uniqueID := vm.Status.UniqueID
+				Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+
+				// This checks that we power off the VM prior to deletion.
+				Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+				Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
+			})
+
+			Context("when the VM is on and the power off mode is TrySoft", func() {
+				powerOffCalls := func(vmRef types.ManagedObjectReference) []string {
+					var calls []string
+					for _, name := range ctx.MethodCalls(vmRef) {
+						switch name {
+						case "ShutdownGuest", "PowerOffVM_Task", "Destroy_Task":
+							calls = append(calls, name)
+						}
+					}
+					return calls
+				}
+
+				BeforeEach(func() {
+					vm.Spec.PowerOffMode = vmopv1.VirtualMachinePowerOpModeTrySoft
+				})
+
+				It("shuts down the guest before destroying the VM", func() {
+					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+					vcVM := ctx.GetVMFromMoID(vm.Status.UniqueID)
+					Expect(vcVM).ToNot(BeNil())
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.GetVMFromMoID(vm.Status.UniqueID)).To(BeNil())
+					Expect(powerOffCalls(vcVM.Reference())).To(Equal([]string{"ShutdownGuest", "Destroy_Task"}))
+				})
+
+				Context("when the guest does not shut down", func() {
+					BeforeEach(func() {
+						Expect(os.Setenv(lib.VMDeletePowerOffTimeoutEnv, "1s")).To(Succeed())
+					})
+
+					AfterEach(func() {
+						Expect(os.Unsetenv(lib.VMDeletePowerOffTimeoutEnv)).To(Succeed())
+					})
+
+					It("hard powers off the VM after the timeout before destroying it", func() {
+						ctx.SimulateUnresponsiveGuest()
+
+						vcVM := ctx.GetVMFromMoID(vm.Status.UniqueID)
+						Expect(vcVM).ToNot(BeNil())
+
+						Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+						Expect(ctx.GetVMFromMoID(vm.Status.UniqueID)).To(BeNil())
+						Expect(powerOffCalls(vcVM.Reference())).To(Equal([]string{"ShutdownGuest", "PowerOffVM_Task", "Destroy_Task"}))
+					})
+				})
+			})
+
+			It("returns success when VM does not exist", func() {
+				Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+				Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+			})
+
+			Context("when destroying the VM fails with a fault", func() {
+				destroyCalls := func(vmRef types.ManagedObjectReference) int {
+					count := 0
+					for _, name := range ctx.MethodCalls(vmRef) {
+						if name == "Destroy_Task" {
+							count++
+						}
+					}
+					return count
+				}
+
+				BeforeEach(func() {
+					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+					Expect(os.Setenv(lib.TransientFaultRetryDelayEnv, "10ms")).To(Succeed())
+				})
+
+				AfterEach(func() {
+					Expect(os.Unsetenv(lib.TransientFaultRetryDelayEnv)).To(Succeed())
+				})
+
+				It("retries a transient fault", func() {
+					vcVM := ctx.GetVMFromMoID(vm.Status.UniqueID)
+					Expect(vcVM).ToNot(BeNil())
+
+					ctx.InjectMethodFault("Destroy_Task", &types.ResourceInUse{}, 1)
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.GetVMFromMoID(vm.Status.UniqueID)).To(BeNil())
+					Expect(destroyCalls(vcVM.Reference())).To(Equal(2))
+				})
+
+				It("returns a non-transient fault without retrying", func() {
+					vcVM := ctx.GetVMFromMoID(vm.Status.UniqueID)
+					Expect(vcVM).ToNot(BeNil())
+
+					ctx.InjectMethodFault("Destroy_Task", &types.NotSupported{}, 1)
+					err := vmProvider.DeleteVirtualMachine(ctx, vm)
+					Expect(err).To(HaveOccurred())
+					Expect(ctx.GetVMFromMoID(vm.Status.UniqueID)).ToNot(BeNil())
+					Expect(destroyCalls(vcVM.Reference())).To(Equal(1))
+				})
+
+				It("returns a classified fault", func() {
+					ctx.InjectMethodFault("Destroy_Task", &types.NoPermission{}, 1)
+					err := vmProvider.DeleteVirtualMachine(ctx, vm)
+					Expect(err).To(HaveOccurred())
+					Expect(errors.Is(err, providererrors.ErrNoPermission)).To(BeTrue())
+					Expect(ctx.GetVMFromMoID(vm.Status.UniqueID)).ToNot(BeNil())
+				})
+			})
+
+			Context("when the VM has a deletion grace period", func() {
+				var destroyTime metav1.Time
+
+				BeforeEach(func() {
+					destroyTime = metav1.NewTime(time.Now().Add(time.Hour))
+				})
+
+				It("powers off the VM instead of deleting it until the grace period expires", func() {
+					uniqueID := vm.Status.UniqueID
+					vm.Status.DestroyTime = &destroyTime
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+
+					vcVM := ctx.GetVMFromMoID(uniqueID)
+					Expect(vcVM).ToNot(BeNil())
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.extraConfig", "runtime.powerState"}, &o)).To(Succeed())
+					Expect(o.Runtime.PowerState).To(Equal(types.VirtualMachinePowerStatePoweredOff))
+					ecMap := util.ExtraConfigToMap(o.Config.ExtraConfig)
+					Expect(ecMap).To(HaveKeyWithValue(constants.VMDestroyTimeExtraConfigKey, destroyTime.UTC().Format(time.RFC3339)))
+
+					By("reports the VM with its destroy time to the orphaned VM collector", func() {
+						managedVMs, err := vmProvider.ListManagedVirtualMachines(ctx)
+						Expect(err).ToNot(HaveOccurred())
+
+						var found bool
+						for _, managedVM := range managedVMs {
+							if managedVM.VM.Value == uniqueID {
+								found = true
+								Expect(managedVM.DestroyTime.Equal(destroyTime.Truncate(time.Second))).To(BeTrue())
+							}
+						}
+						Expect(found).To(BeTrue())
+					})
+
+					By("reattaches the VM when the VirtualMachine is created again with the same name", func() {
+						recreatedVM := builder.DummyBasicVirtualMachineA2(vm.Name, vm.Namespace)
+						recreatedVM.Spec = *vm.Spec.DeepCopy()
+						Expect(recreatedVM.Status.UniqueID).To(BeEmpty())
+
+						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, recreatedVM)).To(Succeed())
+						Expect(recreatedVM.Status.UniqueID).To(Equal(uniqueID))
+						Expect(recreatedVM.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+
+						Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config.extraConfig"}, &o)).To(Succeed())
+						Expect(util.ExtraConfigToMap(o.Config.ExtraConfig)).ToNot(HaveKey(constants.VMDestroyTimeExtraConfigKey))
+					})
+				})
+
+				It("deletes the VM after the grace period expires", func() {
+					uniqueID := vm.Status.UniqueID
+					destroyTime = metav1.NewTime(time.Now().Add(-time.Minute))
+					vm.Status.DestroyTime = &destroyTime
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
+				})
+			})
+
+			Context("when the delete policy is orphan", func() {
+				BeforeEach(func() {
+					vm.Annotations[vmopv1.DeletePolicyAnnotation] = vmopv1.DeletePolicyOrphan
+				})
+
+				It("releases the VM instead of deleting it", func() {
+					uniqueID := vm.Status.UniqueID
+					Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+
+					vcVM := ctx.GetVMFromMoID(uniqueID)
+					Expect(vcVM).ToNot(BeNil())
+
+					var o mo.VirtualMachine
+					Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config"}, &o)).To(Succeed())
+					Expect(o.Config.Annotation).To(Equal(constants.VCVMReleasedAnnotation))
+					Expect(o.Config.ManagedBy).ToNot(BeNil())
+					Expect(o.Config.ManagedBy.ExtensionKey).To(BeEmpty())
+				})
+			})
+
+			Context("When fault domains is enabled", func() {
+				const zoneName = "az-1"
+
+				BeforeEach(func() {
+					testConfig.WithFaultDomains = true
+					// Explicitly place the VM into one of the zones that the test context will create.
+					vm.Labels[topology.KubernetesTopologyZoneLabelKey] = zoneName
+				})
+
+				It("returns NotFound when VM does not exist", func() {
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+					delete(vm.Labels, topology.KubernetesTopologyZoneLabelKey)
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+				})
+
+				It("Deletes existing VM when zone info is missing", func() {
+					_, err := createOrUpdateAndGetVcVM(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+
+					uniqueID := vm.Status.UniqueID
+					Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+
+					Expect(vm.Labels).To(HaveKeyWithValue(topology.KubernetesTopologyZoneLabelKey, zoneName))
+					delete(vm.Labels, topology.KubernetesTopologyZoneLabelKey)
+
+					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
+				})
+			})
+		})
+
+		Context("Guest Heartbeat", func() {
+			JustBeforeEach(func() {
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+			})
+
+			It("return guest heartbeat", func() {
+				heartbeat, err := vmProvider.GetVirtualMachineGuestHeartbeat(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				// Just testing for property query: field not set in vcsim.
+				Expect(heartbeat).To(BeEmpty())
+			})
+		})
+
+		Context("Batch status properties", func() {
+			var vm2 *vmopv1.VirtualMachine
+
+			JustBeforeEach(func() {
+				vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOn
+				vm2 = vm.DeepCopy()
+				vm2.Name += "-2"
+
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm2)).To(Succeed())
+			})
+
+			It("returns the status properties of every VM from one query", func() {
+				vmRef1 := ctx.GetVMFromMoID(vm.Status.UniqueID).Reference()
+				vmRef2 := ctx.GetVMFromMoID(vm2.Status.UniqueID).Reference()
+
+				pcRef := ctx.VCClient.ServiceContent.PropertyCollector
+				calls := len(ctx.MethodCalls(pcRef))
+
+				props, err := vmProvider.GetVirtualMachineStatusProperties(ctx, []types.ManagedObjectReference{vmRef1, vmRef2})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ctx.MethodCalls(pcRef)[calls:]).To(Equal([]string{"RetrieveProperties"}))
+
+				Expect(props).To(HaveLen(2))
+				Expect(props).To(HaveKey(vmRef1))
+				Expect(props).To(HaveKey(vmRef2))
+				for _, vmMO := range props {
+					Expect(vmMO.Summary.Runtime.PowerState).To(Equal(types.VirtualMachinePowerStatePoweredOn))
+					Expect(vmMO.Guest).ToNot(BeNil())
+				}
+			})
+
+			It("skips the VMs that no longer exist", func() {
+				vmRef1 := ctx.GetVMFromMoID(vm.Status.UniqueID).Reference()
+				vmRef2 := ctx.GetVMFromMoID(vm2.Status.UniqueID).Reference()
+				Expect(vmProvider.DeleteVirtualMachine(ctx, vm2)).To(Succeed())
+
+				props, err := vmProvider.GetVirtualMachineStatusProperties(ctx, []types.ManagedObjectReference{vmRef1, vmRef2})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(props).To(HaveLen(1))
+				Expect(props).To(HaveKey(vmRef1))
+			})
+
+			It("returns no properties for no VMs", func() {
+				props, err := vmProvider.GetVirtualMachineStatusProperties(ctx, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(props).To(BeEmpty())
+			})
+		})
+
+		Context("Web console ticket", func() {
+			JustBeforeEach(func() {
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+			})
+
+			It("return ticket", func() {
+				// vcsim doesn't implement this yet so expect an error.
+				_, err := vmProvider.GetVirtualMachineWebMKSTicket(ctx, vm, "foo")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("does not implement: AcquireTicket"))
+			})
+		})
+
+		Context("VM hardware version", func() {
+			JustBeforeEach(func() {
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+			})
+
+			It("return version", func() {
+				version, err := vmProvider.GetVirtualMachineHardwareVersion(ctx, vm)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal(int32(9)))
+			})
+
+			It("returns the upgrade targets that vcsim reports", func() {
+				current, targets, err := vmProvider.GetVirtualMachineHardwareVersionUpgradeTargets(ctx, vm)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(current).To(Equal(int32(9)))
+				Expect(targets).To(Equal([]int32{13}))
+			})
+
+			It("returns the higher upgrade targets the cluster supports", func() {
+				vcVM := ctx.GetVMFromMoID(vm.Status.UniqueID)
+				Expect(vcVM).ToNot(BeNil())
+				ctx.SetVirtualMachineHardwareVersion(vcVM.Reference(), "vmx-15")
+				ctx.SetHardwareVersions("vmx-21", "vmx-13", "vmx-15", "vmx-19")
+
+				current, targets, err := vmProvider.GetVirtualMachineHardwareVersionUpgradeTargets(ctx, vm)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(current).To(Equal(int32(15)))
+				Expect(targets).To(Equal([]int32{19, 21}))
+			})
+		})
+
+		Context("VM crypto", func() {
+			const providerID = "my-key-provider"
+
+			BeforeEach(func() {
+				vm.Annotations[constants.CryptoKeyProviderAnnotation] = providerID
+			})
+
+			When("crypto key provider exists", func() {
+				JustBeforeEach(func() {
+					ctx.RegisterCryptoKeyProvider(providerID)
+				})
+
+				It("creates VM", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+					keyProvider, err := vmProvider.GetVirtualMachineCryptoKeyProvider(ctx, vm)
+					Expect(err).ToNot(HaveOccurred())
+					// Just testing for property query: vcsim does not encrypt the VM.
+					Expect(keyProvider).To(BeEmpty())
+				})
+			})
+
+			When("crypto key provider does not exist", func() {
+				JustBeforeEach(func() {
+					ctx.RegisterCryptoKeyProvider("other-key-provider")
+				})
+
+				It("returns error", func() {
+					err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+					Expect(err).To(MatchError(`crypto key provider "my-key-provider" does not exist`))
+				})
+			})
+		})
+
+		Context("Default ethernet card type", func() {
+
+			BeforeEach(func() {
+				testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+				testConfig.WithDefaultEthernetCardType = "e1000e"
+
+				vm.Spec.Network.Disabled = false
+				vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name:    "eth0",
+						Network: common.PartialObjectRef{Name: dvpgName},
+					},
+				}
+			})
+
+			It("creates the NIC with the configured default type", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				devList, err := vcVM.Device(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				nics := devList.SelectByType((*types.VirtualEthernetCard)(nil))
+				Expect(nics).To(HaveLen(1))
+				Expect(nics[0]).To(BeAssignableToTypeOf(&types.VirtualE1000e{}))
+			})
+		})
+
+		Context("Image network interface compatibility", func() {
+
+			BeforeEach(func() {
+				testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+				testConfig.WithVMClassAsConfigDaynDate = true
+
+				// The class only allows VMXNET3 network interfaces.
+				configSpec := &types.VirtualMachineConfigSpec{
+					DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+						&types.VirtualDeviceConfigSpec{
+							Operation: types.VirtualDeviceConfigSpecOperationAdd,
+							Device: &types.VirtualVmxnet3{
+								VirtualVmxnet: types.VirtualVmxnet{
+									VirtualEthernetCard: types.VirtualEthernetCard{
+										VirtualDevice: types.VirtualDevice{
+											Key: 4000,
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+				var w bytes.Buffer
+				enc := types.NewJSONEncoder(&w)
+				Expect(enc.Encode(configSpec)).To(Succeed())
+				vmClass.Spec.ConfigSpec = w.Bytes()
+
+				vm.Spec.Network.Disabled = false
+				vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name:    "eth0",
+						Network: common.PartialObjectRef{Name: dvpgName},
+					},
+				}
+			})
+
+			JustBeforeEach(func() {
+				// Image that declares only an E1000 network interface.
+				image := &vmopv1.ClusterVirtualMachineImage{}
+				Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: vm.Spec.ImageName}, image)).To(Succeed())
+				image.Status.NetworkInterfaceTypes = []string{"e1000"}
+				Expect(ctx.Client.Status().Update(ctx, image)).To(Succeed())
+			})
+
+			It("creates the VM and marks the condition false", func() {
+				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+
+				c := conditions.Get(vm, vmopv1.VirtualMachineConditionNetworkInterfaceCompatible)
+				Expect(c).ToNot(BeNil())
+				Expect(c.Status).To(Equal(metav1.ConditionFalse))
+				Expect(c.Reason).To(Equal(vmopv1.VirtualMachineNetworkInterfaceIncompatibleReason))
+				Expect(c.Message).To(ContainSubstring(`network interface type "vmxnet3" is not supported by the image`))
+				Expect(vm.Status.UniqueID).ToNot(BeEmpty())
+			})
+		})
+
+		Context("One of the network interfaces fails", func() {
+
+			BeforeEach(func() {
+				testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+
+				vm.Spec.Network.Disabled = false
+				vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name:    "eth0",
+						Network: common.PartialObjectRef{Name: dvpgName},
+					},
+					{
+						Name:    "eth1",
+						Network: common.PartialObjectRef{Name: "does-not-exist"},
+					},
+				}
+			})
+
+			It("returns error and marks the condition false naming the interface", func() {
+				err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
+				Expect(err).To(MatchError(ContainSubstring(`network interface "eth1" error`)))
+				Expect(err.Error()).ToNot(ContainSubstring(`"eth0"`))
+
+				c := conditions.Get(vm, vmopv1.VirtualMachineConditionNetworkReady)
+				Expect(c).ToNot(BeNil())
+				Expect(c.Status).To(Equal(metav1.ConditionFalse))
+				Expect(c.Reason).To(Equal(vmopv1.VirtualMachineNetworkInterfaceNotReadyReason))
+				Expect(c.Message).To(HavePrefix(`network interface "eth1" error: unable to find named network "does-not-exist"`))
+				Expect(c.Message).ToNot(ContainSubstring(`"eth0"`))
+				Expect(vm.Status.UniqueID).To(BeEmpty())
+			})
+		})
+
+		Context("VM device connection status", func() {
+
+			BeforeEach(func() {
+				testConfig.WithNetworkEnv = builder.NetworkEnvNamed
+
+				vm.Spec.Network.Disabled = false
+				vm.Spec.Network.Interfaces = []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name:    "eth0",
+						Network: common.PartialObjectRef{Name: dvpgName},
+					},
+				}
+			})
 
-						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
-						Expect(err).To(HaveOccurred())
-						Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("status update pending for persistent volume: %s on VM", cnsVolumeName)))
-						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
-					})
+			It("reports a disconnected NIC", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-					By("CNS volume is not attached", func() {
-						errMsg := "blah blah blah not attached"
+				devList, err := vcVM.Device(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				nics := devList.SelectByType((*types.VirtualEthernetCard)(nil))
+				Expect(nics).ToNot(BeEmpty())
+				nicKey := nics[0].GetVirtualDevice().Key
+
+				By("NIC is initially connected", func() {
+					Expect(vm.Status.Devices).To(ContainElement(And(
+						HaveField("Key", nicKey),
+						HaveField("Connected", true))))
+				})
 
-						vm.Status.Volumes = []vmopv1.VirtualMachineVolumeStatus{
-							{
-								Name:     cnsVolumeName,
-								Attached: false,
-								Error:    errMsg,
-							},
-						}
+				ctx.SetVirtualMachineDeviceConnected(vcVM.Reference(), nicKey, false)
 
-						err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
-						Expect(err).To(HaveOccurred())
-						Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("persistent volume: %s not attached to VM", cnsVolumeName)))
-						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
-					})
+				devices, err := vmProvider.GetVirtualMachineDeviceConnectionStatus(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(devices).To(ContainElement(And(
+					HaveField("Key", nicKey),
+					HaveField("Connected", false))))
 
-					By("CNS volume is attached", func() {
-						vm.Status.Volumes = []vmopv1.VirtualMachineVolumeStatus{
-							{
-								Name:     cnsVolumeName,
-								Attached: true,
-							},
-						}
-						Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
-						Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
-					})
+				By("Status is updated", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.Devices).To(ContainElement(And(
+						HaveField("Key", nicKey),
+						HaveField("Connected", false))))
 				})
 			})
 
-			Context("When fault domains is enabled", func() {
-				const zoneName = "az-1"
+			It("disconnects and reconnects a NIC", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-				BeforeEach(func() {
-					testConfig.WithFaultDomains = true
-					// Explicitly place the VM into one of the zones that the test context will create.
-					vm.Labels[topology.KubernetesTopologyZoneLabelKey] = zoneName
-				})
+				devList, err := vcVM.Device(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				nics := devList.SelectByType((*types.VirtualEthernetCard)(nil))
+				Expect(nics).ToNot(BeEmpty())
+				nicKey := nics[0].GetVirtualDevice().Key
 
-				It("Reverse lookups existing VM into correct zone", func() {
-					_, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
+				Expect(vmProvider.DisconnectVirtualMachineDevice(ctx, vm, nicKey)).To(Succeed())
+				ctx.AssertVirtualMachineDeviceConnected(vcVM.Reference(), nicKey, false)
 
-					Expect(vm.Labels).To(HaveKeyWithValue(topology.KubernetesTopologyZoneLabelKey, zoneName))
-					Expect(vm.Status.Zone).To(Equal(zoneName))
-					delete(vm.Labels, topology.KubernetesTopologyZoneLabelKey)
+				Expect(vmProvider.ConnectVirtualMachineDevice(ctx, vm, nicKey)).To(Succeed())
+				ctx.AssertVirtualMachineDeviceConnected(vcVM.Reference(), nicKey, true)
+			})
 
-					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
-					Expect(vm.Labels).To(HaveKeyWithValue(topology.KubernetesTopologyZoneLabelKey, zoneName))
-					Expect(vm.Status.Zone).To(Equal(zoneName))
-				})
+			It("returns error for a device that does not support runtime connect", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				devList, err := vcVM.Device(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				disks := devList.SelectByType((*types.VirtualDisk)(nil))
+				Expect(disks).ToNot(BeEmpty())
+				diskKey := disks[0].GetVirtualDevice().Key
+
+				err = vmProvider.DisconnectVirtualMachineDevice(ctx, vm, diskKey)
+				Expect(err).To(MatchError(fmt.Sprintf("device %d does not support runtime connect", diskKey)))
 			})
 		})
 
-		Context("VM SetResourcePolicy", func() {
-			var resourcePolicy *vmopv1.VirtualMachineSetResourcePolicy
+		Context("Detach and delete disk", func() {
+			var (
+				vcVM          *object.VirtualMachine
+				controllerKey int32
+				bootDiskKey   int32
+				dataDiskKey   int32
+				dataDiskFile  string
+			)
 
 			JustBeforeEach(func() {
-				resourcePolicyName := "test-policy"
-				resourcePolicy = getVirtualMachineSetResourcePolicy(resourcePolicyName, nsInfo.Namespace)
-				Expect(vmProvider.CreateOrUpdateVirtualMachineSetResourcePolicy(ctx, resourcePolicy)).To(Succeed())
-				Expect(ctx.Client.Create(ctx, resourcePolicy)).To(Succeed())
+				var err error
+				vcVM, err = createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-				vm.Annotations["vsphere-cluster-module-group"] = resourcePolicy.Spec.ClusterModuleGroups[0]
-				if vm.Spec.Reserved == nil {
-					vm.Spec.Reserved = &vmopv1.VirtualMachineReservedSpec{}
+				devList, err := vcVM.Device(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				disks := devList.SelectByType((*types.VirtualDisk)(nil))
+				Expect(disks).To(HaveLen(1))
+				bootDiskKey = disks[0].GetVirtualDevice().Key
+
+				controller, err := virtualmachine.FindFirstClassDiskController(devList, false)
+				Expect(err).ToNot(HaveOccurred())
+				controllerKey = controller.GetVirtualController().Key
+				datastore, err := ctx.Finder.DefaultDatastore(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				dataDisk := devList.CreateDisk(controller, datastore.Reference(), datastore.Path(""))
+				dataDisk.CapacityInKB = 1024
+				Expect(vcVM.AddDevice(ctx, dataDisk)).To(Succeed())
+
+				files := ctx.GetVirtualMachineDiskFiles(vcVM.Reference())
+				Expect(files).To(HaveLen(2))
+				for key, file := range files {
+					if key != bootDiskKey {
+						dataDiskKey, dataDiskFile = key, file
+					}
 				}
-				vm.Spec.Reserved.ResourcePolicyName = resourcePolicy.Name
+				Expect(ctx.DatastoreFileExists(dataDiskFile)).To(BeTrue())
 			})
 
-			AfterEach(func() {
-				resourcePolicy = nil
+			It("detaches the disk and keeps its file", func() {
+				Expect(vmProvider.DetachAndDeleteVirtualMachineDisk(ctx, vm, dataDiskKey, false)).To(Succeed())
+
+				files := ctx.GetVirtualMachineDiskFiles(vcVM.Reference())
+				Expect(files).To(HaveLen(1))
+				Expect(files).ToNot(HaveKey(dataDiskKey))
+				Expect(ctx.DatastoreFileExists(dataDiskFile)).To(BeTrue())
 			})
 
-			It("VM is created in child Folder and ResourcePool", func() {
+			It("detaches the disk and deletes its file", func() {
+				Expect(vmProvider.DetachAndDeleteVirtualMachineDisk(ctx, vm, dataDiskKey, true)).To(Succeed())
+
+				files := ctx.GetVirtualMachineDiskFiles(vcVM.Reference())
+				Expect(files).To(HaveLen(1))
+				Expect(files).ToNot(HaveKey(dataDiskKey))
+				Expect(ctx.DatastoreFileExists(dataDiskFile)).To(BeFalse())
+			})
+
+			It("returns error for the boot disk", func() {
+				err := vmProvider.DetachAndDeleteVirtualMachineDisk(ctx, vm, bootDiskKey, true)
+				Expect(err).To(MatchError(fmt.Sprintf("disk %d is the boot disk", bootDiskKey)))
+				Expect(ctx.GetVirtualMachineDiskFiles(vcVM.Reference())).To(HaveKey(bootDiskKey))
+			})
+
+			It("returns error for a device that is not a disk", func() {
+				err := vmProvider.DetachAndDeleteVirtualMachineDisk(ctx, vm, controllerKey, false)
+				Expect(err).To(MatchError(fmt.Sprintf("device %d is not a disk", controllerKey)))
+			})
+		})
+
+		Context("VM hot plug", func() {
+
+			It("changes the hot plug of the powered off VM", func() {
+				vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
+				_, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				hotPlug, err := vmProvider.GetVirtualMachineHotPlug(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hotPlug).To(Equal(vmprovider.HotPlug{}))
+
+				enabled := vmprovider.HotPlug{CPUHotAdd: true, CPUHotRemove: true, MemoryHotAdd: true}
+				Expect(vmProvider.SetVirtualMachineHotPlug(ctx, vm, enabled)).To(Succeed())
+
+				hotPlug, err = vmProvider.GetVirtualMachineHotPlug(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hotPlug).To(Equal(enabled))
+			})
+
+			It("returns error for the powered on VM", func() {
 				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
 				Expect(err).ToNot(HaveOccurred())
+				Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+				calls := len(ctx.MethodCalls(vcVM.Reference()))
 
-				By("has expected inventory path", func() {
-					Expect(vcVM.InventoryPath).To(HaveSuffix(
-						fmt.Sprintf("/%s/%s/%s", nsInfo.Namespace, resourcePolicy.Spec.Folder, vm.Name)))
-				})
+				err = vmProvider.SetVirtualMachineHotPlug(ctx, vm, vmprovider.HotPlug{MemoryHotAdd: true})
+				Expect(err).To(MatchError("hot plug can only be changed while the VM is powered off"))
+				Expect(ctx.MethodCalls(vcVM.Reference())[calls:]).ToNot(ContainElement("ReconfigVM_Task"))
 
-				By("has expected namespace resource pool", func() {
-					rp, err := vcVM.ResourcePool(ctx)
-					Expect(err).ToNot(HaveOccurred())
-					childRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", resourcePolicy.Spec.ResourcePool.Name)
-					Expect(childRP).ToNot(BeNil())
-					Expect(rp.Reference().Value).To(Equal(childRP.Reference().Value))
+				By("unchanged hot plug is not an error", func() {
+					Expect(vmProvider.SetVirtualMachineHotPlug(ctx, vm, vmprovider.HotPlug{})).To(Succeed())
 				})
 			})
+		})
 
-			It("Cluster Modules", func() {
+		Context("VM template", func() {
+
+			It("marks the powered off VM as a template and back", func() {
+				vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
 				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
 				Expect(err).ToNot(HaveOccurred())
+				Expect(ctx.IsVirtualMachineTemplate(vcVM.Reference())).To(BeFalse())
 
-				members, err := cluster.NewManager(ctx.RestClient).ListModuleMembers(ctx, resourcePolicy.Status.ClusterModules[0].ModuleUuid)
+				Expect(vmProvider.MarkAsTemplate(ctx, vm)).To(Succeed())
+				Expect(ctx.IsVirtualMachineTemplate(vcVM.Reference())).To(BeTrue())
+
+				By("marking the template again is not an error", func() {
+					Expect(vmProvider.MarkAsTemplate(ctx, vm)).To(Succeed())
+				})
+
+				nsRP := ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", "")
+				Expect(nsRP).ToNot(BeNil())
+				Expect(vmProvider.MarkAsVirtualMachine(ctx, vm, nsRP.Reference().Value)).To(Succeed())
+				Expect(ctx.IsVirtualMachineTemplate(vcVM.Reference())).To(BeFalse())
+
+				rp, err := vcVM.ResourcePool(ctx)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(members).To(ContainElements(vcVM.Reference()))
+				Expect(rp.Reference()).To(Equal(nsRP.Reference()))
 			})
 
-			It("Returns error with non-existence cluster module", func() {
-				vm.Annotations["vsphere-cluster-module-group"] = "bogusClusterMod"
-				err := vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)
-				Expect(err).To(MatchError("ClusterModule bogusClusterMod not found"))
+			It("returns error for the powered on VM", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+
+				Expect(vmProvider.MarkAsTemplate(ctx, vm)).To(MatchError("VM must be powered off to be marked as a template"))
+				Expect(ctx.IsVirtualMachineTemplate(vcVM.Reference())).To(BeFalse())
 			})
 		})
 
-		Context("Delete VM", func() {
-			JustBeforeEach(func() {
-				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+		Context("VM file layout", func() {
+
+			It("reports the vmx path and the disk files", func() {
+				_, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				vmxPath, files, err := vmProvider.GetVirtualMachineFileLayout(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vmxPath).To(HavePrefix("["))
+				Expect(vmxPath).To(HaveSuffix(".vmx"))
+				Expect(files).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type": Equal(string(types.VirtualMachineFileLayoutExFileTypeDiskDescriptor)),
+				})))
 			})
 
-			Context("when the VM is off", func() {
-				BeforeEach(func() {
-					vm.Spec.PowerState = vmopv1.VirtualMachinePowerStateOff
-				})
+			It("reports the seeded file layout", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-				It("deletes the VM", func() {
-					Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOff))
+				file := types.VirtualMachineFileLayoutExFileInfo{
+					Key:  42,
+					Name: "[LocalDS_0] my-vm/my-vm_1.vmdk",
+					Type: string(types.VirtualMachineFileLayoutExFileTypeDiskDescriptor),
+					Size: 1024,
+				}
+				ctx.SetVirtualMachineFileLayout(vcVM.Reference(), file)
 
-					uniqueID := vm.Status.UniqueID
-					Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+				_, files, err := vmProvider.GetVirtualMachineFileLayout(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(files).To(ConsistOf(file))
+			})
+		})
 
-					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
-					Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
+		Context("VM resource allocation", func() {
+
+			It("reports a lower effective allocation under a constrained resource pool", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+
+				var o mo.VirtualMachine
+				Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config"}, &o)).To(Succeed())
+
+				configured := vm.Status.ResourceAllocation
+				Expect(configured).ToNot(BeNil())
+				Expect(configured.ConfiguredCPU.Reservation).To(Equal(*o.Config.CpuAllocation.Reservation))
+				Expect(configured.ConfiguredCPU.Limit).To(HaveValue(Equal(*o.Config.CpuAllocation.Limit)))
+				Expect(configured.ConfiguredMemory.Reservation).To(Equal(*o.Config.MemoryAllocation.Reservation))
+				Expect(configured.ConfiguredMemory.Limit).To(HaveValue(Equal(*o.Config.MemoryAllocation.Limit)))
+
+				// Limit the namespace resource pool to less than the VM's reservations.
+				const cpuLimit, memoryLimit = 100, 512
+				Expect(configured.ConfiguredCPU.Reservation).To(BeNumerically(">", cpuLimit))
+				Expect(configured.ConfiguredMemory.Reservation).To(BeNumerically(">", memoryLimit))
+				ctx.SetResourcePoolLimits(ctx.GetResourcePoolForNamespace(nsInfo.Namespace, "", ""), cpuLimit, memoryLimit)
+
+				allocation, err := vmProvider.GetVirtualMachineResourceAllocation(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allocation).ToNot(BeNil())
+				Expect(allocation.ConfiguredCPU).To(Equal(configured.ConfiguredCPU))
+				Expect(allocation.ConfiguredMemory).To(Equal(configured.ConfiguredMemory))
+
+				Expect(allocation.EffectiveCPU).ToNot(Equal(allocation.ConfiguredCPU))
+				Expect(allocation.EffectiveCPU.Limit).To(HaveValue(BeEquivalentTo(cpuLimit)))
+				Expect(allocation.EffectiveCPU.Reservation).To(BeEquivalentTo(cpuLimit))
+				Expect(allocation.EffectiveCPU.Shares).To(Equal(allocation.ConfiguredCPU.Shares))
+				Expect(allocation.EffectiveMemory).ToNot(Equal(allocation.ConfiguredMemory))
+				Expect(allocation.EffectiveMemory.Limit).To(HaveValue(BeEquivalentTo(memoryLimit)))
+				Expect(allocation.EffectiveMemory.Reservation).To(BeEquivalentTo(memoryLimit))
+
+				By("Status is updated", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.ResourceAllocation).To(Equal(allocation))
 				})
 			})
+		})
 
-			It("when the VM is on", func() {
-				Expect(vm.Status.PowerState).To(Equal(vmopv1.VirtualMachinePowerStateOn))
+		Context("VM identity", func() {
 
-				uniqueID := vm.Status.UniqueID
-				Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+			It("returns the identity that the VM's status reports", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-				// This checks that we power off the VM prior to deletion.
-				Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
-				Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
-			})
+				var o mo.VirtualMachine
+				Expect(vcVM.Properties(ctx, vcVM.Reference(), []string{"config"}, &o)).To(Succeed())
+				Expect(o.Config.Uuid).ToNot(BeEmpty())
+				Expect(o.Config.InstanceUuid).ToNot(BeEmpty())
 
-			It("returns success when VM does not exist", func() {
-				Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
-				Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+				identity, err := vmProvider.GetVirtualMachineIdentity(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(identity.VM).To(Equal(vcVM.Reference()))
+				Expect(identity.BiosUUID).To(Equal(o.Config.Uuid))
+				Expect(identity.InstanceUUID).To(Equal(o.Config.InstanceUuid))
+
+				Expect(vm.Status.UniqueID).To(Equal(identity.VM.Value))
+				Expect(vm.Status.BiosUUID).To(Equal(identity.BiosUUID))
+				Expect(vm.Status.InstanceUUID).To(Equal(identity.InstanceUUID))
 			})
 
-			Context("When fault domains is enabled", func() {
-				const zoneName = "az-1"
+			It("returns the identity of the vSphere VM when the VM's status is stale", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-				BeforeEach(func() {
-					testConfig.WithFaultDomains = true
-					// Explicitly place the VM into one of the zones that the test context will create.
-					vm.Labels[topology.KubernetesTopologyZoneLabelKey] = zoneName
-				})
+				builder.SetDummyVirtualMachineIdentityA2(vm)
 
-				It("returns NotFound when VM does not exist", func() {
-					_, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
+				identity, err := vmProvider.GetVirtualMachineIdentity(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(identity.VM).To(Equal(vcVM.Reference()))
+				Expect(identity.BiosUUID).ToNot(Equal(builder.DummyBiosUUID))
+				Expect(identity.InstanceUUID).ToNot(Equal(builder.DummyInstanceUUID))
 
-					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
-					delete(vm.Labels, topology.KubernetesTopologyZoneLabelKey)
-					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
+				By("refreshes the identity on the VM's status", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.UniqueID).To(Equal(identity.VM.Value))
+					Expect(vm.Status.BiosUUID).To(Equal(identity.BiosUUID))
+					Expect(vm.Status.InstanceUUID).To(Equal(identity.InstanceUUID))
 				})
+			})
+		})
 
-				It("Deletes existing VM when zone info is missing", func() {
-					_, err := createOrUpdateAndGetVcVM(ctx, vm)
-					Expect(err).ToNot(HaveOccurred())
+		Context("VM guest disks", func() {
 
-					uniqueID := vm.Status.UniqueID
-					Expect(ctx.GetVMFromMoID(uniqueID)).ToNot(BeNil())
+			It("reports the seeded guest filesystem usage", func() {
+				vcVM, err := createOrUpdateAndGetVcVM(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
 
-					Expect(vm.Labels).To(HaveKeyWithValue(topology.KubernetesTopologyZoneLabelKey, zoneName))
-					delete(vm.Labels, topology.KubernetesTopologyZoneLabelKey)
+				disks, err := vmProvider.GetVirtualMachineGuestDisks(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(disks).To(BeEmpty())
+
+				ctx.SetVirtualMachineGuestDisks(vcVM.Reference(),
+					types.GuestDiskInfo{
+						DiskPath:       "/",
+						Capacity:       20 * 1024 * 1024 * 1024,
+						FreeSpace:      5 * 1024 * 1024 * 1024,
+						FilesystemType: "ext4",
+					},
+					types.GuestDiskInfo{
+						DiskPath:  "/data",
+						Capacity:  100 * 1024 * 1024 * 1024,
+						FreeSpace: 80 * 1024 * 1024 * 1024,
+					},
+				)
 
-					Expect(vmProvider.DeleteVirtualMachine(ctx, vm)).To(Succeed())
-					Expect(ctx.GetVMFromMoID(uniqueID)).To(BeNil())
+				disks, err = vmProvider.GetVirtualMachineGuestDisks(ctx, vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(disks).To(HaveLen(2))
+				Expect(disks[0].DiskPath).To(Equal("/"))
+				Expect(disks[0].FilesystemType).To(Equal("ext4"))
+				Expect(disks[0].Capacity.Value()).To(BeEquivalentTo(20 * 1024 * 1024 * 1024))
+				Expect(disks[0].FreeSpace.Value()).To(BeEquivalentTo(5 * 1024 * 1024 * 1024))
+				Expect(disks[1].DiskPath).To(Equal("/data"))
+				Expect(disks[1].FilesystemType).To(BeEmpty())
+				Expect(disks[1].Capacity.Value()).To(BeEquivalentTo(100 * 1024 * 1024 * 1024))
+				Expect(disks[1].FreeSpace.Value()).To(BeEquivalentTo(80 * 1024 * 1024 * 1024))
+
+				By("Status is updated", func() {
+					Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+					Expect(vm.Status.GuestDisks).To(Equal(disks))
 				})
 			})
 		})
+	})
 
-		Context("Guest Heartbeat", func() {
-			JustBeforeEach(func() {
-				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
-			})
+	Context("ResolveImage", func() {
+		var (
+			itemID string
+		)
 
-			It("return guest heartbeat", func() {
-				heartbeat, err := vmProvider.GetVirtualMachineGuestHeartbeat(ctx, vm)
-				Expect(err).ToNot(HaveOccurred())
-				// Just testing for property query: field not set in vcsim.
-				Expect(heartbeat).To(BeEmpty())
-			})
+		BeforeEach(func() {
+			testConfig.WithContentLibrary = true
 		})
 
-		Context("Web console ticket", func() {
-			JustBeforeEach(func() {
-				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
-			})
+		JustBeforeEach(func() {
+			clusterVMImage := &vmopv1.ClusterVirtualMachineImage{}
+			Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: ctx.ContentLibraryImageName}, clusterVMImage)).To(Succeed())
+			itemID = clusterVMImage.Status.ProviderItemID
+			Expect(itemID).ToNot(BeEmpty())
+		})
 
-			It("return ticket", func() {
-				// vcsim doesn't implement this yet so expect an error.
-				_, err := vmProvider.GetVirtualMachineWebMKSTicket(ctx, vm, "foo")
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("does not implement: AcquireTicket"))
-			})
+		It("resolves a cluster scoped image", func() {
+			resolved, err := vmProvider.ResolveImage(ctx, ctx.ContentLibraryImageName, nsInfo.Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.LibraryID).To(Equal(ctx.ContentLibraryID))
+			Expect(resolved.ItemID).To(Equal(itemID))
+			Expect(resolved.Storage).To(HaveLen(1))
+			Expect(resolved.Storage[0].Type).To(Equal("DATASTORE"))
+			Expect(resolved.Storage[0].DatastoreID).ToNot(BeEmpty())
 		})
 
-		Context("VM hardware version", func() {
+		When("the image is namespace scoped", func() {
+			const imageName = "ns-scoped-image"
+
 			JustBeforeEach(func() {
-				Expect(vmProvider.CreateOrUpdateVirtualMachine(ctx, vm)).To(Succeed())
+				vmImage := builder.DummyVirtualMachineImageA2(imageName)
+				vmImage.Namespace = nsInfo.Namespace
+				Expect(ctx.Client.Create(ctx, vmImage)).To(Succeed())
+				vmImage.Status.ProviderItemID = itemID
+				Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
 			})
 
-			It("return version", func() {
-				version, err := vmProvider.GetVirtualMachineHardwareVersion(ctx, vm)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(version).To(Equal(int32(9)))
+			It("resolves the image in its namespace", func() {
+				resolved, err := vmProvider.ResolveImage(ctx, imageName, nsInfo.Namespace)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resolved.LibraryID).To(Equal(ctx.ContentLibraryID))
+				Expect(resolved.ItemID).To(Equal(itemID))
+			})
+
+			It("returns NotFound in a namespace the image is not bound to", func() {
+				_, err := vmProvider.ResolveImage(ctx, imageName, nsInfo.Namespace+"-other")
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
 			})
 		})
 	})
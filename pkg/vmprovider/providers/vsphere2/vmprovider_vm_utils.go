@@ -4,7 +4,10 @@
 package vsphere
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -17,8 +20,12 @@ import (
 	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/imageresolver"
+	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/network"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/vmlifecycle"
 )
 
 // TODO: This mostly just a placeholder until we spend time on something better. Individual types
@@ -56,14 +63,143 @@ func GetVirtualMachineClass(
 		return nil, fmt.Errorf("VirtualMachineClass is not Ready")
 	}
 
+	vmClass, err := resolveVirtualMachineClassInheritance(vmCtx, k8sClient, vmClass)
+	if err != nil {
+		reason, msg := "InheritanceCycle", err.Error()
+		if !errors.Is(err, errVMClassInheritanceCycle) {
+			reason, msg = errToConditionReasonAndMessage(err)
+		}
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionClassReady, reason, msg)
+		return nil, err
+	}
+
 	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionClassReady)
 
 	return vmClass, nil
 }
 
+var errVMClassInheritanceCycle = errors.New("VirtualMachineClass inherits from itself")
+
+// resolveVirtualMachineClassInheritance returns the VM class with the classes that it inherits
+// from merged beneath it. The class is returned as is when it does not inherit from a class.
+func resolveVirtualMachineClassInheritance(
+	vmCtx context.VirtualMachineContextA2,
+	k8sClient ctrlclient.Client,
+	vmClass *vmopv1.VirtualMachineClass) (*vmopv1.VirtualMachineClass, error) {
+
+	if vmClass.Spec.InheritsFrom == "" {
+		return vmClass, nil
+	}
+
+	resolved := vmClass.DeepCopy()
+	visited := map[string]struct{}{vmClass.Name: {}}
+
+	for baseName := vmClass.Spec.InheritsFrom; baseName != ""; {
+		if _, ok := visited[baseName]; ok {
+			return nil, fmt.Errorf("%w: %s inherits from %s", errVMClassInheritanceCycle, vmClass.Name, baseName)
+		}
+		visited[baseName] = struct{}{}
+
+		baseClass := &vmopv1.VirtualMachineClass{}
+		key := ctrlclient.ObjectKey{Name: baseName, Namespace: vmClass.Namespace}
+		if err := k8sClient.Get(vmCtx, key, baseClass); err != nil {
+			return nil, fmt.Errorf("failed to get base VirtualMachineClass %s: %w", baseName, err)
+		}
+
+		if err := mergeBaseVirtualMachineClassSpec(&resolved.Spec, baseClass.Spec); err != nil {
+			return nil, fmt.Errorf("failed to merge base VirtualMachineClass %s: %w", baseName, err)
+		}
+
+		baseName = baseClass.Spec.InheritsFrom
+	}
+
+	return resolved, nil
+}
+
+// resolvedVirtualMachineClassHash returns a hash of the spec of the VM's class with the classes it
+// inherits from merged beneath it. A change to a base class does not change the generation of the
+// VM's class, so the hash is what detects that the class the VM resolves to has changed.
+func resolvedVirtualMachineClassHash(
+	vmCtx context.VirtualMachineContextA2,
+	k8sClient ctrlclient.Client) (string, error) {
+
+	key := ctrlclient.ObjectKey{Name: vmCtx.VM.Spec.ClassName, Namespace: vmCtx.VM.Namespace}
+	vmClass := &vmopv1.VirtualMachineClass{}
+	if err := k8sClient.Get(vmCtx, key, vmClass); err != nil {
+		return "", err
+	}
+
+	vmClass, err := resolveVirtualMachineClassInheritance(vmCtx, k8sClient, vmClass)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(vmClass.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// mergeBaseVirtualMachineClassSpec merges the hardware, policies, and ConfigSpec of the base class
+// beneath the class's own, so only the values that the class does not specify are taken from the
+// base class.
+func mergeBaseVirtualMachineClassSpec(spec *vmopv1.VirtualMachineClassSpec, base vmopv1.VirtualMachineClassSpec) error {
+	hw, baseHW := &spec.Hardware, base.Hardware
+	if hw.Cpus == 0 {
+		hw.Cpus = baseHW.Cpus
+	}
+	if hw.Memory.IsZero() {
+		hw.Memory = baseHW.Memory
+	}
+	if len(hw.Devices.VGPUDevices) == 0 && len(hw.Devices.DynamicDirectPathIODevices) == 0 {
+		hw.Devices = baseHW.Devices
+	}
+	if hw.InstanceStorage.StorageClass == "" && len(hw.InstanceStorage.Volumes) == 0 {
+		hw.InstanceStorage = baseHW.InstanceStorage
+	}
+
+	res, baseRes := &spec.Policies.Resources, base.Policies.Resources
+	if res.Requests.Cpu.IsZero() {
+		res.Requests.Cpu = baseRes.Requests.Cpu
+	}
+	if res.Requests.Memory.IsZero() {
+		res.Requests.Memory = baseRes.Requests.Memory
+	}
+	if res.Limits.Cpu.IsZero() {
+		res.Limits.Cpu = baseRes.Limits.Cpu
+	}
+	if res.Limits.Memory.IsZero() {
+		res.Limits.Memory = baseRes.Limits.Memory
+	}
+
+	if len(base.ConfigSpec) == 0 {
+		return nil
+	}
+	if len(spec.ConfigSpec) == 0 {
+		spec.ConfigSpec = base.ConfigSpec
+		return nil
+	}
+
+	configSpec, err := util.UnmarshalConfigSpecFromJSON(spec.ConfigSpec)
+	if err != nil {
+		return err
+	}
+	baseConfigSpec, err := util.UnmarshalConfigSpecFromJSON(base.ConfigSpec)
+	if err != nil {
+		return err
+	}
+
+	spec.ConfigSpec, err = util.MarshalConfigSpecToJSON(util.MergeBaseClassConfigSpec(configSpec, baseConfigSpec))
+	return err
+}
+
 func GetVirtualMachineImageSpecAndStatus(
 	vmCtx context.VirtualMachineContextA2,
-	k8sClient ctrlclient.Client) (ctrlclient.Object, *vmopv1.VirtualMachineImageSpec, *vmopv1.VirtualMachineImageStatus, error) {
+	k8sClient ctrlclient.Client,
+	resolution vcconfig.ImageNameResolution) (ctrlclient.Object, *vmopv1.VirtualMachineImageSpec, *vmopv1.VirtualMachineImageStatus, error) {
 
 	var obj conditions.Getter
 	var spec *vmopv1.VirtualMachineImageSpec
@@ -79,15 +215,46 @@ func GetVirtualMachineImageSpecAndStatus(
 			err = k8sClient.Get(vmCtx, key, clusterVMImage)
 		}
 
+		var resolved ctrlclient.Object
+		if apierrors.IsNotFound(err) && resolution.ResolvesItemNames() {
+			// The name is not of an image resource, so try it as the name of the content library
+			// item an image was created from.
+			// When no image has the name, the NotFound error from the Get is kept as is.
+			var resolveErr error
+			resolved, resolveErr = resolveVirtualMachineImageByName(vmCtx, k8sClient, resolution)
+			switch {
+			case resolveErr == nil:
+				err = nil
+			case errors.Is(resolveErr, ErrAmbiguousImageName):
+				conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady,
+					vmopv1.VirtualMachineImageAmbiguousReason, resolveErr.Error())
+				return nil, nil, nil, resolveErr
+			case !apierrors.IsNotFound(resolveErr):
+				err = resolveErr
+			}
+		}
+
 		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				// The image may exist, so leave its condition as is.
+				return nil, nil, nil, err
+			}
+
 			// Don't use the k8s error as-is as we don't know to prefer the NS or cluster scoped error message.
 			// This is the same error/message that the prior code used.
-			reason, msg := "NotFound", fmt.Sprintf("Failed to get the VM's image: %s", key.Name)
+			reason, msg := vmopv1.VirtualMachineImageNotFoundReason, fmt.Sprintf("Failed to get the VM's image: %s", key.Name)
 			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady, reason, msg)
 			return nil, nil, nil, fmt.Errorf("%s: %w", msg, err)
 		}
 
-		obj, spec, status = clusterVMImage, &clusterVMImage.Spec, &clusterVMImage.Status
+		switch img := resolved.(type) {
+		case *vmopv1.VirtualMachineImage:
+			obj, spec, status = img, &img.Spec, &img.Status
+		case *vmopv1.ClusterVirtualMachineImage:
+			obj, spec, status = img, &img.Spec, &img.Status
+		default:
+			obj, spec, status = clusterVMImage, &clusterVMImage.Spec, &clusterVMImage.Status
+		}
 	} else {
 		obj, spec, status = vmImage, &vmImage.Spec, &vmImage.Status
 	}
@@ -96,16 +263,42 @@ func GetVirtualMachineImageSpecAndStatus(
 
 	conditions.SetMirror(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady, obj,
 		conditions.WithFallbackValue(false,
-			"NotReady",
+			vmopv1.VirtualMachineImageNotReadyReason,
 			vmiNotReadyMessage),
 	)
 	if conditions.IsFalse(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady) {
+		// Keep the image's message but use our reason so that a not ready image is distinct
+		// from one that does not exist.
+		conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady, vmopv1.VirtualMachineImageNotReadyReason,
+			"%s", conditions.GetMessage(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady))
 		return nil, nil, nil, fmt.Errorf(vmiNotReadyMessage)
 	}
 
 	return obj, spec, status, nil
 }
 
+// ErrAmbiguousImageName is returned when a VM's image name matches more than one image.
+var ErrAmbiguousImageName = imageresolver.ErrAmbiguousImageName
+
+// resolveVirtualMachineImageByName returns the namespace or cluster image that was created
+// from the content library item with the VM's image name. It returns a NotFound error if
+// no image matches, and ErrAmbiguousImageName if more than one image matches and the
+// resolution does not pick one.
+func resolveVirtualMachineImageByName(
+	vmCtx context.VirtualMachineContextA2,
+	k8sClient ctrlclient.Client,
+	resolution vcconfig.ImageNameResolution) (ctrlclient.Object, error) {
+
+	img, err := imageresolver.ResolveByItemName(vmCtx, k8sClient, vmCtx.VM.Spec.ImageName, vmCtx.VM.Namespace, resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	vmCtx.Logger.V(4).Info("Resolved VM image name", "imageName", vmCtx.VM.Spec.ImageName,
+		"image", img.GetObjectKind().GroupVersionKind().Kind+"/"+img.GetName())
+	return img, nil
+}
+
 func getSecretData(
 	vmCtx context.VirtualMachineContextA2,
 	name string,
@@ -146,78 +339,156 @@ func getSecretData(
 
 func GetVirtualMachineBootstrap(
 	vmCtx context.VirtualMachineContextA2,
-	k8sClient ctrlclient.Client) (map[string]string, map[string]string, map[string]map[string]string, error) {
+	k8sClient ctrlclient.Client) (vmlifecycle.BootstrapData, error) {
+
+	var bsData vmlifecycle.BootstrapData
 
 	bootstrapSpec := vmCtx.VM.Spec.Bootstrap
-	if bootstrapSpec == nil {
+	vAppProperties := vmCtx.VM.Spec.VAppProperties
+	if bootstrapSpec == nil && len(vAppProperties) == 0 {
 		conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)
-		return nil, nil, nil, nil
+		return bsData, nil
 	}
 
 	var secretSelector *corev1.SecretKeySelector
-	var data, vAppData map[string]string
-	var vAppExData map[string]map[string]string
 
-	if cloudInit := bootstrapSpec.CloudInit; cloudInit != nil {
-		secretSelector = cloudInit.RawCloudConfig
-	} else if sysprep := bootstrapSpec.Sysprep; sysprep != nil {
-		secretSelector = sysprep.RawSysprep
-	}
+	// addSecretData carries along the entire data of the Secret a value is from.
+	addSecretData := func(exData *map[string]map[string]string, from *corev1.SecretKeySelector) error {
+		if from == nil || from.Name == "" {
+			return nil
+		}
 
-	if secretSelector != nil {
-		var err error
+		if _, ok := (*exData)[from.Name]; !ok {
+			// Do the easy thing here and carry along each Secret's entire data. We could instead
+			// shoehorn this in the Data with a concat key using an invalid k8s name delimiter.
+			// TODO: Check that key exists. Too many options.
+			fromData, err := getSecretData(vmCtx, from.Name, false, k8sClient)
+			if err != nil {
+				if apierrors.IsNotFound(err) && from.Optional != nil && *from.Optional {
+					// The value is left unset like when the Secret does not have the key.
+					return nil
+				}
 
-		data, err = getSecretData(vmCtx, secretSelector.Name, true, k8sClient)
-		if err != nil {
-			reason, msg := errToConditionReasonAndMessage(err)
-			conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady, reason, msg)
-			return nil, nil, nil, err
+				reason, msg := errToConditionReasonAndMessage(err)
+				conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady, reason, msg)
+				return err
+			}
+
+			if *exData == nil {
+				*exData = make(map[string]map[string]string)
+			}
+			(*exData)[from.Name] = fromData
 		}
+
+		return nil
 	}
 
-	// vApp bootstrap can be used alongside LinuxPrep/Sysprep.
-	if vApp := bootstrapSpec.VAppConfig; vApp != nil {
+	if bootstrapSpec != nil {
+		if cloudInit := bootstrapSpec.CloudInit; cloudInit != nil {
+			secretSelector = cloudInit.RawCloudConfig
+		} else if sysprep := bootstrapSpec.Sysprep; sysprep != nil {
+			secretSelector = sysprep.RawSysprep
+
+			// The passwords and product ID of an inlined Sysprep are always from Secrets.
+			if inlined := sysprep.Sysprep; inlined != nil {
+				for _, from := range []*corev1.SecretKeySelector{
+					&inlined.GUIUnattended.Password,
+					&inlined.Identification.DomainAdminPassword,
+					&inlined.UserData.ProductID,
+				} {
+					if err := addSecretData(&bsData.SysprepSecretData, from); err != nil {
+						return vmlifecycle.BootstrapData{}, err
+					}
+				}
+			}
+		}
 
-		if vApp.RawProperties != "" {
+		if secretSelector != nil {
 			var err error
 
-			vAppData, err = getSecretData(vmCtx, vApp.RawProperties, true, k8sClient)
+			bsData.Data, err = getSecretData(vmCtx, secretSelector.Name, true, k8sClient)
 			if err != nil {
 				reason, msg := errToConditionReasonAndMessage(err)
 				conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady, reason, msg)
-				return nil, nil, nil, err
+				return vmlifecycle.BootstrapData{}, err
 			}
+		}
 
-		} else {
-			for _, p := range vApp.Properties {
-				from := p.Value.From
-				if from == nil {
-					continue
-				}
+		// vApp bootstrap can be used alongside LinuxPrep/Sysprep.
+		if vApp := bootstrapSpec.VAppConfig; vApp != nil {
 
-				if _, ok := vAppExData[from.Name]; !ok {
-					// Do the easy thing here and carry along each Secret's entire data. We could instead
-					// shoehorn this in the vAppData with a concat key using an invalid k8s name delimiter.
-					// TODO: Check that key exists, and/or deal with from.Optional. Too many options.
-					fromData, err := getSecretData(vmCtx, from.Name, false, k8sClient)
-					if err != nil {
-						reason, msg := errToConditionReasonAndMessage(err)
-						conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady, reason, msg)
-						return nil, nil, nil, err
-					}
+			if vApp.RawProperties != "" {
+				var err error
+
+				bsData.VAppData, err = getSecretData(vmCtx, vApp.RawProperties, true, k8sClient)
+				if err != nil {
+					reason, msg := errToConditionReasonAndMessage(err)
+					conditions.MarkFalse(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady, reason, msg)
+					return vmlifecycle.BootstrapData{}, err
+				}
 
-					if vAppExData == nil {
-						vAppExData = make(map[string]map[string]string)
+			} else {
+				for _, p := range vApp.Properties {
+					if err := addSecretData(&bsData.VAppExData, p.Value.From); err != nil {
+						return vmlifecycle.BootstrapData{}, err
 					}
-					vAppExData[from.Name] = fromData
 				}
 			}
 		}
 	}
 
+	// The VM's vApp properties are applied regardless of the bootstrap provider.
+	for _, value := range vAppProperties {
+		if err := addSecretData(&bsData.VAppExData, value.From); err != nil {
+			return vmlifecycle.BootstrapData{}, err
+		}
+	}
+
 	conditions.MarkTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)
 
-	return data, vAppData, vAppExData, nil
+	return bsData, nil
+}
+
+// bootstrapSecretNames returns the names of the Secrets, or the ConfigMaps they fall back to,
+// that GetVirtualMachineBootstrap reads for the VM.
+func bootstrapSecretNames(vm *vmopv1.VirtualMachine) []string {
+	var names []string
+
+	addSelector := func(from *corev1.SecretKeySelector) {
+		if from != nil && from.Name != "" {
+			names = append(names, from.Name)
+		}
+	}
+
+	if bootstrapSpec := vm.Spec.Bootstrap; bootstrapSpec != nil {
+		if cloudInit := bootstrapSpec.CloudInit; cloudInit != nil {
+			addSelector(cloudInit.RawCloudConfig)
+		} else if sysprep := bootstrapSpec.Sysprep; sysprep != nil {
+			addSelector(sysprep.RawSysprep)
+
+			if inlined := sysprep.Sysprep; inlined != nil {
+				addSelector(&inlined.GUIUnattended.Password)
+				addSelector(&inlined.Identification.DomainAdminPassword)
+				addSelector(&inlined.UserData.ProductID)
+			}
+		}
+
+		if vApp := bootstrapSpec.VAppConfig; vApp != nil {
+			if vApp.RawProperties != "" {
+				names = append(names, vApp.RawProperties)
+			} else {
+				for _, p := range vApp.Properties {
+					addSelector(p.Value.From)
+				}
+			}
+		}
+	}
+
+	for _, value := range vm.Spec.VAppProperties {
+		addSelector(value.From)
+	}
+
+	return names
 }
 
 func GetVMSetResourcePolicy(
@@ -344,6 +615,48 @@ func HardwareVersionForPVCandPCIDevices(imageHWVersion int32, configSpec *types.
 	return configSpecHWVersion
 }
 
+// ValidateImageNetworkInterfaceTypes returns an error if any ethernet card in the ConfigSpec is connected
+// to a network whose backing type none of the image's network interface types can be connected to, ex.
+// an SR-IOV only image on an NSX network, or if the ethernet card is of a type that the image does not
+// declare. An image that does not declare any types is compatible with every type and backing.
+func ValidateImageNetworkInterfaceTypes(imageTypes []string, configSpec *types.VirtualMachineConfigSpec) error {
+	if len(imageTypes) == 0 {
+		return nil
+	}
+
+	supported := make(map[string]struct{}, len(imageTypes))
+	for _, t := range imageTypes {
+		supported[t] = struct{}{}
+	}
+
+	for _, dev := range util.DevicesFromConfigSpec(configSpec) {
+		if !util.IsEthernetCard(dev) {
+			continue
+		}
+
+		if backingType := network.EthernetCardBackingType(dev); backingType != "" {
+			compatible := false
+			for _, t := range imageTypes {
+				if network.EthernetCardTypeSupportsBacking(t, backingType) {
+					compatible = true
+					break
+				}
+			}
+			if !compatible {
+				return fmt.Errorf("network backing type %q is not supported by the image, supported types: %v", backingType, imageTypes)
+			}
+		}
+
+		if t := network.EthernetCardType(dev); t != "" {
+			if _, ok := supported[t]; !ok {
+				return fmt.Errorf("network interface type %q is not supported by the image, supported types: %v", t, imageTypes)
+			}
+		}
+	}
+
+	return nil
+}
+
 // GetAttachedDiskUUIDToPVC returns a map of disk UUID to PVC object for all
 // attached disks by checking the VM's spec and status of volumes.
 func GetAttachedDiskUUIDToPVC(
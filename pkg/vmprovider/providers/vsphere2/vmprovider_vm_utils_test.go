@@ -5,22 +5,29 @@ package vsphere_test
 
 import (
 	goctx "context"
+	"errors"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/vmware/govmomi/vim25/types"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/sysprep"
 	conditions "github.com/vmware-tanzu/vm-operator/pkg/conditions2"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	vsphere "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/instancestorage"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
@@ -127,6 +134,107 @@ func vmUtilTests() {
 						Expect(err).ToNot(HaveOccurred())
 						Expect(class).ToNot(BeNil())
 					})
+
+					When("Inherits from a base class", func() {
+						var (
+							baseClass *vmopv1.VirtualMachineClass
+						)
+
+						configSpecToJSON := func(configSpec *types.VirtualMachineConfigSpec) []byte {
+							raw, err := util.MarshalConfigSpecToJSON(configSpec)
+							Expect(err).ToNot(HaveOccurred())
+							return raw
+						}
+
+						BeforeEach(func() {
+							baseClass = builder.DummyVirtualMachineClass2A2("dummy-base-vm-class")
+							baseClass.Namespace = vmClass.Namespace
+							baseClass.Spec.Hardware.Cpus = 8
+							baseClass.Spec.Hardware.Devices.VGPUDevices = []vmopv1.VGPUDevice{{ProfileName: "profile"}}
+							baseClass.Spec.ConfigSpec = configSpecToJSON(&types.VirtualMachineConfigSpec{
+								Firmware: "efi",
+								NumCPUs:  8,
+								ExtraConfig: []types.BaseOptionValue{
+									&types.OptionValue{Key: "base-key", Value: "base"},
+									&types.OptionValue{Key: "shared-key", Value: "base"},
+								},
+							})
+							initObjects = append(initObjects, baseClass)
+
+							vmClass.Spec.InheritsFrom = baseClass.Name
+							vmClass.Spec.ConfigSpec = configSpecToJSON(&types.VirtualMachineConfigSpec{
+								NumCPUs: 2,
+								ExtraConfig: []types.BaseOptionValue{
+									&types.OptionValue{Key: "shared-key", Value: "class"},
+								},
+							})
+						})
+
+						It("returns the class merged over the base class", func() {
+							class, err := vsphere.GetVirtualMachineClass(vmCtx, k8sClient)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(class.Spec.Hardware.Cpus).To(BeEquivalentTo(2))
+							Expect(class.Spec.Hardware.Devices).To(Equal(baseClass.Spec.Hardware.Devices))
+
+							configSpec, err := util.UnmarshalConfigSpecFromJSON(class.Spec.ConfigSpec)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(configSpec.Firmware).To(Equal("efi"))
+							Expect(configSpec.NumCPUs).To(BeEquivalentTo(2))
+							Expect(util.ExtraConfigToMap(configSpec.ExtraConfig)).To(Equal(map[string]string{
+								"base-key":   "base",
+								"shared-key": "class",
+							}))
+						})
+
+						When("Base class inherits from another class", func() {
+							BeforeEach(func() {
+								rootClass := builder.DummyVirtualMachineClass2A2("dummy-root-vm-class")
+								rootClass.Namespace = vmClass.Namespace
+								rootClass.Spec.Hardware.InstanceStorage.StorageClass = "root-storage-class"
+								initObjects = append(initObjects, rootClass)
+
+								baseClass.Spec.InheritsFrom = rootClass.Name
+							})
+
+							It("returns the class merged over the chain of base classes", func() {
+								class, err := vsphere.GetVirtualMachineClass(vmCtx, k8sClient)
+								Expect(err).ToNot(HaveOccurred())
+								Expect(class.Spec.Hardware.Cpus).To(BeEquivalentTo(2))
+								Expect(class.Spec.Hardware.InstanceStorage.StorageClass).To(Equal("root-storage-class"))
+							})
+						})
+
+						When("Base class inherits from the class", func() {
+							BeforeEach(func() {
+								baseClass.Spec.InheritsFrom = vmClass.Name
+							})
+
+							It("returns an error and sets condition", func() {
+								_, err := vsphere.GetVirtualMachineClass(vmCtx, k8sClient)
+								Expect(err).To(MatchError(ContainSubstring("VirtualMachineClass inherits from itself")))
+
+								c := conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionClassReady)
+								Expect(c).ToNot(BeNil())
+								Expect(c.Status).To(Equal(metav1.ConditionFalse))
+								Expect(c.Reason).To(Equal("InheritanceCycle"))
+							})
+						})
+
+						When("Base class does not exist", func() {
+							BeforeEach(func() {
+								vmClass.Spec.InheritsFrom = "does-not-exist"
+							})
+
+							It("returns an error and sets condition", func() {
+								_, err := vsphere.GetVirtualMachineClass(vmCtx, k8sClient)
+								Expect(err).To(MatchError(ContainSubstring("failed to get base VirtualMachineClass does-not-exist")))
+
+								c := conditions.Get(vmCtx.VM, vmopv1.VirtualMachineConditionClassReady)
+								Expect(c).ToNot(BeNil())
+								Expect(c.Reason).To(Equal("NotFound"))
+							})
+						})
+					})
 				})
 			})
 		})
@@ -157,13 +265,51 @@ func vmUtilTests() {
 			When("Neither cluster or namespace scoped VM image exists", func() {
 
 				It("returns error and sets condition", func() {
-					_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient)
+					_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
 					Expect(err).To(HaveOccurred())
 					expectedErrMsg := fmt.Sprintf("Failed to get the VM's image: %s", vmCtx.VM.Spec.ImageName)
 					Expect(err.Error()).To(ContainSubstring(expectedErrMsg))
+					Expect(apierrors.IsNotFound(errors.Unwrap(err))).To(BeTrue())
+					Expect(err.Error()).To(ContainSubstring("clustervirtualmachineimages"))
+
+					expectedCondition := []metav1.Condition{
+						*conditions.FalseCondition(vmopv1.VirtualMachineConditionImageReady,
+							vmopv1.VirtualMachineImageNotFoundReason, expectedErrMsg),
+					}
+					Expect(vmCtx.VM.Status.Conditions).To(conditions.MatchConditions(expectedCondition))
+				})
+			})
+
+			When("Getting the VM image fails", func() {
+				getErr := errors.New("get error")
+
+				JustBeforeEach(func() {
+					k8sClient = getErrorClient{Client: k8sClient, err: getErr}
+				})
+
+				It("returns the error and does not set the condition", func() {
+					_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
+					Expect(err).To(MatchError(getErr))
+					Expect(conditions.Has(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady)).To(BeFalse())
+				})
+			})
+
+			When("Namespace scoped VM image exists but is not bound to the VM's namespace", func() {
+				BeforeEach(func() {
+					nsVMImage.Namespace = vmCtx.VM.Namespace + "-other"
+					initObjects = append(initObjects, nsVMImage)
+					vmCtx.VM.Spec.ImageName = nsVMImage.Name
+				})
+
+				It("returns error and sets condition with the not found reason", func() {
+					_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
+					Expect(err).To(HaveOccurred())
+					expectedErrMsg := fmt.Sprintf("Failed to get the VM's image: %s", nsVMImage.Name)
+					Expect(err.Error()).To(ContainSubstring(expectedErrMsg))
 
 					expectedCondition := []metav1.Condition{
-						*conditions.FalseCondition(vmopv1.VirtualMachineConditionImageReady, "NotFound", expectedErrMsg),
+						*conditions.FalseCondition(vmopv1.VirtualMachineConditionImageReady,
+							vmopv1.VirtualMachineImageNotFoundReason, expectedErrMsg),
 					}
 					Expect(vmCtx.VM.Status.Conditions).To(conditions.MatchConditions(expectedCondition))
 				})
@@ -173,35 +319,34 @@ func vmUtilTests() {
 
 				const expectedErrMsg = "VirtualMachineImage is not ready"
 
-				Context("VM image has the Ready condition set to False", func() {
-					reason := vmopv1.VirtualMachineImageProviderNotReadyReason
+				Context("VM image has the Ready condition set to False because it is still importing", func() {
 					errMsg := "Provider item is not in ready condition"
 
 					BeforeEach(func() {
 						conditions.MarkFalse(nsVMImage,
 							vmopv1.ReadyConditionType,
-							reason,
+							vmopv1.VirtualMachineImageProviderNotReadyReason,
 							errMsg)
 						initObjects = append(initObjects, nsVMImage)
 						vmCtx.VM.Spec.ImageName = nsVMImage.Name
 					})
 
-					It("returns error and sets VM condition with reason and message from the image", func() {
-						_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient)
+					It("returns error and sets VM condition with the not ready reason and message from the image", func() {
+						_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
 						Expect(err).To(HaveOccurred())
 
 						Expect(err.Error()).To(ContainSubstring(expectedErrMsg))
 
 						expectedCondition := []metav1.Condition{
 							*conditions.FalseCondition(
-								vmopv1.VirtualMachineConditionImageReady, reason, errMsg),
+								vmopv1.VirtualMachineConditionImageReady, vmopv1.VirtualMachineImageNotReadyReason, errMsg),
 						}
 						Expect(vmCtx.VM.Status.Conditions).To(conditions.MatchConditions(expectedCondition))
 					})
 				})
 
 				Context("VM image does not have the Ready condition", func() {
-					reason := "NotReady"
+					reason := vmopv1.VirtualMachineImageNotReadyReason
 
 					BeforeEach(func() {
 						conditions.Delete(nsVMImage, vmopv1.ReadyConditionType)
@@ -210,7 +355,7 @@ func vmUtilTests() {
 					})
 
 					It("returns error and sets VM condition with reason and message from the image", func() {
-						_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient)
+						_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
 						Expect(err).To(HaveOccurred())
 						Expect(err.Error()).To(ContainSubstring(expectedErrMsg))
 
@@ -231,7 +376,7 @@ func vmUtilTests() {
 				})
 
 				It("returns success", func() {
-					imgObj, spec, status, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient)
+					imgObj, spec, status, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(imgObj).ToNot(BeNil())
 					Expect(imgObj.GetObjectKind().GroupVersionKind().Kind).To(Equal("VirtualMachineImage"))
@@ -248,7 +393,7 @@ func vmUtilTests() {
 				})
 
 				It("returns success", func() {
-					imgObj, spec, status, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient)
+					imgObj, spec, status, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, config.ImageNameResolutionErrorOnAmbiguous)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(imgObj).ToNot(BeNil())
 					Expect(imgObj.GetObjectKind().GroupVersionKind().Kind).To(Equal("ClusterVirtualMachineImage"))
@@ -257,6 +402,97 @@ func vmUtilTests() {
 					Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady)).To(BeTrue())
 				})
 			})
+
+			When("Namespace and cluster scoped images have the VM's image name", func() {
+				const imageName = "dup-image"
+
+				var (
+					otherNSVMImage *vmopv1.VirtualMachineImage
+					resolution     config.ImageNameResolution
+				)
+
+				BeforeEach(func() {
+					nsVMImage.Status.Name = imageName
+					nsVMImage.CreationTimestamp = metav1.NewTime(time.Now())
+					otherNSVMImage = builder.DummyVirtualMachineImageA2("dummy-other-ns-vm-image")
+					otherNSVMImage.Namespace = vmCtx.VM.Namespace
+					otherNSVMImage.Status.Name = imageName
+					otherNSVMImage.CreationTimestamp = metav1.NewTime(nsVMImage.CreationTimestamp.Add(-time.Hour))
+					conditions.MarkTrue(otherNSVMImage, vmopv1.ReadyConditionType)
+					clusterVMImage.Status.Name = imageName
+					clusterVMImage.CreationTimestamp = metav1.NewTime(nsVMImage.CreationTimestamp.Add(-2 * time.Hour))
+					initObjects = append(initObjects, nsVMImage, otherNSVMImage, clusterVMImage)
+					vmCtx.VM.Spec.ImageName = imageName
+				})
+
+				Context("with error-on-ambiguous resolution", func() {
+					BeforeEach(func() {
+						resolution = config.ImageNameResolutionErrorOnAmbiguous
+					})
+
+					It("returns an ambiguity error and sets condition", func() {
+						_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, resolution)
+						Expect(err).To(MatchError(vsphere.ErrAmbiguousImageName))
+						Expect(err.Error()).To(ContainSubstring("VirtualMachineImage " + otherNSVMImage.Name))
+						Expect(err.Error()).To(ContainSubstring("VirtualMachineImage " + nsVMImage.Name))
+						Expect(err.Error()).To(ContainSubstring("ClusterVirtualMachineImage " + clusterVMImage.Name))
+
+						expectedCondition := []metav1.Condition{
+							*conditions.FalseCondition(vmopv1.VirtualMachineConditionImageReady,
+								vmopv1.VirtualMachineImageAmbiguousReason, err.Error()),
+						}
+						Expect(vmCtx.VM.Status.Conditions).To(conditions.MatchConditions(expectedCondition))
+					})
+
+					When("only one image has the VM's image name", func() {
+						BeforeEach(func() {
+							initObjects = []client.Object{clusterVMImage}
+						})
+
+						It("returns success", func() {
+							imgObj, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, resolution)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(imgObj.GetName()).To(Equal(clusterVMImage.Name))
+							Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady)).To(BeTrue())
+						})
+					})
+				})
+
+				Context("with prefer-binding-order resolution", func() {
+					BeforeEach(func() {
+						resolution = config.ImageNameResolutionPreferBindingOrder
+					})
+
+					It("returns the oldest namespace scoped image", func() {
+						imgObj, _, status, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, resolution)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(imgObj.GetObjectKind().GroupVersionKind().Kind).To(Equal("VirtualMachineImage"))
+						Expect(imgObj.GetName()).To(Equal(otherNSVMImage.Name))
+						Expect(status.Name).To(Equal(imageName))
+						Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionImageReady)).To(BeTrue())
+					})
+				})
+
+				Context("with require-fully-qualified resolution", func() {
+					BeforeEach(func() {
+						resolution = config.ImageNameResolutionRequireFullyQualified
+					})
+
+					It("returns a NotFound error", func() {
+						_, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, resolution)
+						Expect(err).To(HaveOccurred())
+						Expect(err).ToNot(MatchError(vsphere.ErrAmbiguousImageName))
+						Expect(err.Error()).To(ContainSubstring("Failed to get the VM's image: " + imageName))
+					})
+
+					It("returns the image with the fully qualified name", func() {
+						vmCtx.VM.Spec.ImageName = nsVMImage.Name
+						imgObj, _, _, err := vsphere.GetVirtualMachineImageSpecAndStatus(vmCtx, k8sClient, resolution)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(imgObj.GetName()).To(Equal(nsVMImage.Name))
+					})
+				})
+			})
 		})
 	})
 
@@ -312,7 +548,7 @@ func vmUtilTests() {
 			})
 
 			It("return an error when resources does not exist", func() {
-				_, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+				_, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 				Expect(err).To(HaveOccurred())
 				Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeFalse())
 			})
@@ -323,9 +559,9 @@ func vmUtilTests() {
 				})
 
 				It("returns success", func() {
-					data, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+					bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 					Expect(err).ToNot(HaveOccurred())
-					Expect(data).To(HaveKeyWithValue("foo", "bar"))
+					Expect(bsData.Data).To(HaveKeyWithValue("foo", "bar"))
 					Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
 				})
 			})
@@ -337,10 +573,10 @@ func vmUtilTests() {
 
 				When("Prefers Secret over ConfigMap", func() {
 					It("returns success", func() {
-						data, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+						bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 						Expect(err).ToNot(HaveOccurred())
 						// Prefer Secret over ConfigMap.
-						Expect(data).To(HaveKeyWithValue("foo1", "bar1"))
+						Expect(bsData.Data).To(HaveKeyWithValue("foo1", "bar1"))
 						Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
 					})
 				})
@@ -357,7 +593,7 @@ func vmUtilTests() {
 			})
 
 			It("return an error when resource does not exist", func() {
-				_, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+				_, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 				Expect(err).To(HaveOccurred())
 				Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeFalse())
 			})
@@ -368,9 +604,9 @@ func vmUtilTests() {
 				})
 
 				It("returns success", func() {
-					data, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+					bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 					Expect(err).ToNot(HaveOccurred())
-					Expect(data).To(HaveKeyWithValue("foo", "bar"))
+					Expect(bsData.Data).To(HaveKeyWithValue("foo", "bar"))
 					Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
 				})
 			})
@@ -382,15 +618,66 @@ func vmUtilTests() {
 
 				When("Prefers Secret over ConfigMap", func() {
 					It("returns success", func() {
-						data, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+						bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 						Expect(err).ToNot(HaveOccurred())
-						Expect(data).To(HaveKeyWithValue("foo1", "bar1"))
+						Expect(bsData.Data).To(HaveKeyWithValue("foo1", "bar1"))
 						Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
 					})
 				})
 			})
 		})
 
+		When("Bootstrap via inlined Sysprep", func() {
+			BeforeEach(func() {
+				vmCtx.VM.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+					Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
+						Sysprep: &sysprep.Sysprep{
+							GUIUnattended: sysprep.GUIUnattended{
+								Password: corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: dataName},
+									Key:                  "foo1",
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("return an error when the Secret does not exist", func() {
+				_, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+				Expect(err).To(HaveOccurred())
+				Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeFalse())
+			})
+
+			When("the Secret is optional", func() {
+				BeforeEach(func() {
+					vmCtx.VM.Spec.Bootstrap.Sysprep.Sysprep.GUIUnattended.Password.Optional = pointer.Bool(true)
+				})
+
+				It("does not return an error when the Secret does not exist", func() {
+					bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(bsData.SysprepSecretData).ToNot(HaveKey(dataName))
+					Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
+				})
+			})
+
+			When("Secret exists", func() {
+				BeforeEach(func() {
+					initObjects = append(initObjects, bootstrapSecret)
+				})
+
+				It("returns the Secret data", func() {
+					bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(bsData.Data).To(BeEmpty())
+					Expect(bsData.SysprepSecretData).To(HaveKey(dataName))
+					Expect(bsData.SysprepSecretData[dataName]).To(HaveKeyWithValue("foo1", "bar1"))
+					Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
+				})
+			})
+		})
+
 		When("Bootstrap with vAppConfig", func() {
 
 			BeforeEach(func() {
@@ -401,7 +688,7 @@ func vmUtilTests() {
 			})
 
 			It("return an error when resource does not exist", func() {
-				_, _, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+				_, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 				Expect(err).To(HaveOccurred())
 				Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeFalse())
 			})
@@ -412,9 +699,9 @@ func vmUtilTests() {
 				})
 
 				It("returns success", func() {
-					_, data, _, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+					bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 					Expect(err).ToNot(HaveOccurred())
-					Expect(data).To(HaveKeyWithValue("foo-vapp", "bar-vapp"))
+					Expect(bsData.VAppData).To(HaveKeyWithValue("foo-vapp", "bar-vapp"))
 					Expect(conditions.IsTrue(vmCtx.VM, vmopv1.VirtualMachineConditionBootstrapReady)).To(BeTrue())
 				})
 			})
@@ -435,10 +722,10 @@ func vmUtilTests() {
 					}
 
 					It("returns success", func() {
-						_, _, exData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
+						bsData, err := vsphere.GetVirtualMachineBootstrap(vmCtx, k8sClient)
 						Expect(err).ToNot(HaveOccurred())
-						Expect(exData).To(HaveKey(vAppDataName))
-						data := exData[vAppDataName]
+						Expect(bsData.VAppExData).To(HaveKey(vAppDataName))
+						data := bsData.VAppExData[vAppDataName]
 						Expect(data).To(HaveKeyWithValue("foo-vapp", "bar-vapp"))
 					})
 				})
@@ -669,6 +956,70 @@ func vmUtilTests() {
 		})
 	})
 
+	Context("ValidateImageNetworkInterfaceTypes", func() {
+		var (
+			configSpec *types.VirtualMachineConfigSpec
+		)
+
+		BeforeEach(func() {
+			configSpec = &types.VirtualMachineConfigSpec{
+				DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+					&types.VirtualDeviceConfigSpec{
+						Operation: types.VirtualDeviceConfigSpecOperationAdd,
+						Device:    &types.VirtualSriovEthernetCard{},
+					},
+				},
+			}
+		})
+
+		It("image does not declare any types", func() {
+			Expect(vsphere.ValidateImageNetworkInterfaceTypes(nil, configSpec)).To(Succeed())
+		})
+
+		It("image declares the ConfigSpec type", func() {
+			Expect(vsphere.ValidateImageNetworkInterfaceTypes([]string{"e1000", "sriov"}, configSpec)).To(Succeed())
+		})
+
+		It("image does not declare the ConfigSpec type", func() {
+			err := vsphere.ValidateImageNetworkInterfaceTypes([]string{"e1000"}, configSpec)
+			Expect(err).To(MatchError(`network interface type "sriov" is not supported by the image, supported types: [e1000]`))
+		})
+
+		Context("network backing", func() {
+			setBacking := func(backing types.BaseVirtualDeviceBackingInfo) {
+				dev := configSpec.DeviceChange[0].GetVirtualDeviceConfigSpec().Device
+				dev.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard().Backing = backing
+			}
+
+			It("image type can be connected to the backing", func() {
+				setBacking(&types.VirtualEthernetCardDistributedVirtualPortBackingInfo{})
+				Expect(vsphere.ValidateImageNetworkInterfaceTypes([]string{"sriov"}, configSpec)).To(Succeed())
+
+				setBacking(&types.VirtualEthernetCardNetworkBackingInfo{})
+				Expect(vsphere.ValidateImageNetworkInterfaceTypes([]string{"sriov"}, configSpec)).To(Succeed())
+			})
+
+			It("no image type can be connected to the backing", func() {
+				setBacking(&types.VirtualEthernetCardOpaqueNetworkBackingInfo{})
+				err := vsphere.ValidateImageNetworkInterfaceTypes([]string{"sriov"}, configSpec)
+				Expect(err).To(MatchError(`network backing type "OpaqueNetwork" is not supported by the image, supported types: [sriov]`))
+			})
+
+			It("another image type can be connected to the backing", func() {
+				configSpec.DeviceChange[0].GetVirtualDeviceConfigSpec().Device = &types.VirtualVmxnet3{
+					VirtualVmxnet: types.VirtualVmxnet{
+						VirtualEthernetCard: types.VirtualEthernetCard{
+							VirtualDevice: types.VirtualDevice{
+								Backing: &types.VirtualEthernetCardOpaqueNetworkBackingInfo{},
+							},
+						},
+					},
+				}
+				Expect(vsphere.ValidateImageNetworkInterfaceTypes([]string{"sriov", "vmxnet3"}, configSpec)).To(Succeed())
+			})
+		})
+	})
+
 	Context("GetAttachedDiskUuidToPVC", func() {
 		const (
 			attachedDiskUUID = "dummy-uuid"
@@ -738,3 +1089,13 @@ func vmUtilTests() {
 		})
 	})
 }
+
+// getErrorClient is a client whose Get always fails with the error.
+type getErrorClient struct {
+	client.Client
+	err error
+}
+
+func (c getErrorClient) Get(_ goctx.Context, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	return c.err
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere
+
+import (
+	goctx "context"
+	"fmt"
+	"sort"
+
+	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
+	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	vcconfig "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
+)
+
+const (
+	// maxNetworkInterfaces is the maximum number of network interfaces of a vSphere VM.
+	maxNetworkInterfaces = 10
+
+	// maxDisks is the maximum number of disks of a vSphere VM: the disks of its four SCSI
+	// controllers, which have 15 disks each.
+	maxDisks = 60
+)
+
+// ValidateVMAgainstImage returns the ways the VM spec is not compatible with the image: its disks,
+// network interfaces, and vApp properties. The firmware is not validated since the class firmware
+// overrides the image's. Only the image and class resources are read so this does not touch
+// vCenter, and can be used before the VM is created.
+func (vs *vSphereVMProvider) ValidateVMAgainstImage(
+	ctx goctx.Context,
+	namespace string,
+	spec vmopv1.VirtualMachineSpec,
+	imageName string) (field.ErrorList, error) {
+
+	vm := &vmopv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	vm.Spec.ImageName = imageName
+
+	vmCtx := context.VirtualMachineContextA2{
+		Context: ctx,
+		Logger:  log.WithValues("namespace", namespace, "imageName", imageName),
+		VM:      vm,
+	}
+
+	// Only the ConfigMap is read, so the provider config is used without connecting to vCenter.
+	config, err := vcconfig.GetProviderConfig(vmCtx, vs.k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, imageStatus, err := GetVirtualMachineImageSpecAndStatus(vmCtx, vs.k8sClient, config.ImageNameResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var classConfigSpec *types.VirtualMachineConfigSpec
+	if vm.Spec.ClassName != "" {
+		vmClass, err := GetVirtualMachineClass(vmCtx, vs.k8sClient)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(vmClass.Spec.ConfigSpec) > 0 {
+			classConfigSpec, err = GetVMClassConfigSpec(vmClass.Spec.ConfigSpec)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateImageDisks(specPath, vm.Spec, imageStatus, classConfigSpec)...)
+	allErrs = append(allErrs, validateImageNetworkInterfaces(specPath, vm.Spec, imageStatus, classConfigSpec)...)
+	allErrs = append(allErrs, validateImageVAppProperties(specPath, vm.Spec, imageStatus)...)
+
+	return allErrs, nil
+}
+
+// validateImageDisks validates the VM does not have more disks than a VM can have: the image's
+// disks, the disks the class adds, and the VM's volumes. The check is skipped when the image's
+// disks are not known.
+func validateImageDisks(
+	specPath *field.Path,
+	spec vmopv1.VirtualMachineSpec,
+	imageStatus *vmopv1.VirtualMachineImageStatus,
+	classConfigSpec *types.VirtualMachineConfigSpec) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	if len(imageStatus.Disks) == 0 {
+		return allErrs
+	}
+
+	numDisks := len(imageStatus.Disks) + len(spec.Volumes)
+	if classConfigSpec != nil {
+		for _, dc := range classConfigSpec.DeviceChange {
+			if devSpec := dc.GetVirtualDeviceConfigSpec(); devSpec.Operation == types.VirtualDeviceConfigSpecOperationAdd {
+				if _, ok := devSpec.Device.(*types.VirtualDisk); ok {
+					numDisks++
+				}
+			}
+		}
+	}
+
+	if numDisks > maxDisks {
+		allErrs = append(allErrs, field.TooMany(specPath.Child("volumes"), len(spec.Volumes),
+			maxDisks-(numDisks-len(spec.Volumes))))
+	}
+
+	if spec.Advanced != nil && spec.Advanced.BootDiskCapacity != nil {
+		bootDiskCapacity := spec.Advanced.BootDiskCapacity
+		if imageCapacity := imageStatus.Disks[0].Capacity; imageCapacity != nil && bootDiskCapacity.Cmp(*imageCapacity) < 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("advanced", "bootDiskCapacity"), bootDiskCapacity.String(),
+				fmt.Sprintf("must not be less than the image's boot disk capacity %s", imageCapacity.String())))
+		}
+	}
+
+	return allErrs
+}
+
+func validateImageNetworkInterfaces(
+	specPath *field.Path,
+	spec vmopv1.VirtualMachineSpec,
+	imageStatus *vmopv1.VirtualMachineImageStatus,
+	classConfigSpec *types.VirtualMachineConfigSpec) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	if spec.Network != nil {
+		if n := len(spec.Network.Interfaces); n > maxNetworkInterfaces {
+			allErrs = append(allErrs, field.TooMany(specPath.Child("network", "interfaces"), n, maxNetworkInterfaces))
+		}
+	}
+
+	if classConfigSpec != nil {
+		if err := ValidateImageNetworkInterfaceTypes(imageStatus.NetworkInterfaceTypes, classConfigSpec); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("className"), spec.ClassName, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateImageVAppProperties(
+	specPath *field.Path,
+	spec vmopv1.VirtualMachineSpec,
+	imageStatus *vmopv1.VirtualMachineImageStatus) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateVAppPropertiesAgainstImage(specPath, spec.VAppProperties, imageStatus)...)
+
+	if spec.Bootstrap == nil || spec.Bootstrap.VAppConfig == nil {
+		return allErrs
+	}
+
+	imageProperties := make(map[string]struct{}, len(imageStatus.OVFProperties))
+	for _, prop := range imageStatus.OVFProperties {
+		imageProperties[prop.Key] = struct{}{}
+	}
+
+	propertiesPath := specPath.Child("bootstrap", "vAppConfig", "properties")
+	for i, prop := range spec.Bootstrap.VAppConfig.Properties {
+		if _, ok := imageProperties[prop.Key]; !ok {
+			allErrs = append(allErrs, field.NotFound(propertiesPath.Index(i).Child("key"), prop.Key))
+		}
+	}
+
+	return allErrs
+}
+
+// validateVAppPropertiesAgainstImage validates the VM's vApp properties are user configurable
+// properties of the image, and that the values of the password properties are from a Secret.
+func validateVAppPropertiesAgainstImage(
+	specPath *field.Path,
+	vAppProperties map[string]common.ValueOrSecretKeySelector,
+	imageStatus *vmopv1.VirtualMachineImageStatus) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	if len(vAppProperties) == 0 {
+		return allErrs
+	}
+
+	imageProperties := make(map[string]vmopv1.OVFProperty, len(imageStatus.OVFProperties))
+	for _, prop := range imageStatus.OVFProperties {
+		imageProperties[prop.Key] = prop
+	}
+
+	keys := make([]string, 0, len(vAppProperties))
+	for key := range vAppProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	propertiesPath := specPath.Child("vAppProperties")
+	for _, key := range keys {
+		imageProp, ok := imageProperties[key]
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(propertiesPath.Key(key), key))
+			continue
+		}
+
+		if imageProp.Password && vAppProperties[key].Value != nil {
+			allErrs = append(allErrs, field.Forbidden(propertiesPath.Key(key).Child("value"),
+				"the OVF property is a password so its value must be from a Secret"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsphere_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/common"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider"
+	vsphere "github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2"
+	"github.com/vmware-tanzu/vm-operator/test/builder"
+)
+
+func validateVMAgainstImageTests() {
+
+	var (
+		ctx        *builder.TestContextForVCSim
+		nsInfo     builder.WorkloadNamespaceInfo
+		vmProvider vmprovider.VirtualMachineProviderInterfaceA2
+
+		vmImage *vmopv1.VirtualMachineImage
+		vmClass *vmopv1.VirtualMachineClass
+		spec    vmopv1.VirtualMachineSpec
+	)
+
+	BeforeEach(func() {
+		ctx = suite.NewTestContextForVCSim(builder.VCSimTestConfig{WithV1A2: true, WithContentLibrary: true})
+		vmProvider = vsphere.NewVSphereVMProviderFromClient(ctx.Client, ctx.Recorder)
+		nsInfo = ctx.CreateWorkloadNamespace()
+
+		vmImage = ctx.CreateNamespaceVMImageA2(nsInfo.Namespace, "validate-image")
+		vmImage.Status.Firmware = "efi"
+		vmImage.Status.NetworkInterfaceTypes = []string{"vmxnet3"}
+		vmImage.Status.Disks = []vmopv1.VirtualMachineImageDiskInfo{
+			{
+				Capacity: resource.NewQuantity(10*1024*1024*1024, resource.BinarySI),
+			},
+		}
+		vmImage.Status.OVFProperties = []vmopv1.OVFProperty{
+			{
+				Key:  "hostname",
+				Type: "string",
+			},
+			{
+				Key:      "password",
+				Type:     "string",
+				Password: true,
+			},
+		}
+		Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
+
+		configSpec := &types.VirtualMachineConfigSpec{
+			Firmware: "efi",
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{
+					Operation: types.VirtualDeviceConfigSpecOperationAdd,
+					Device:    &types.VirtualVmxnet3{},
+				},
+			},
+		}
+		raw, err := util.MarshalConfigSpecToJSON(configSpec)
+		Expect(err).ToNot(HaveOccurred())
+
+		vmClass = builder.DummyVirtualMachineClassA2()
+		vmClass.Namespace = nsInfo.Namespace
+		vmClass.Spec.ConfigSpec = raw
+		Expect(ctx.Client.Create(ctx, vmClass)).To(Succeed())
+		vmClass.Status.Ready = true
+		Expect(ctx.Client.Status().Update(ctx, vmClass)).To(Succeed())
+
+		spec = vmopv1.VirtualMachineSpec{
+			ClassName: vmClass.Name,
+			Advanced: &vmopv1.VirtualMachineAdvancedSpec{
+				BootDiskCapacity: resource.NewQuantity(20*1024*1024*1024, resource.BinarySI),
+			},
+			Network: &vmopv1.VirtualMachineNetworkSpec{
+				Interfaces: []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name: "eth0",
+					},
+				},
+			},
+			Bootstrap: &vmopv1.VirtualMachineBootstrapSpec{
+				VAppConfig: &vmopv1.VirtualMachineBootstrapVAppConfigSpec{
+					Properties: []common.KeyValueOrSecretKeySelectorPair{
+						{
+							Key: "hostname",
+							Value: common.ValueOrSecretKeySelector{
+								Value: pointer.String("my-vm"),
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctx.AfterEach()
+		ctx = nil
+	})
+
+	validate := func() field.ErrorList {
+		allErrs, err := vmProvider.ValidateVMAgainstImage(ctx, nsInfo.Namespace, spec, vmImage.Name)
+		Expect(err).ToNot(HaveOccurred())
+		return allErrs
+	}
+
+	It("returns no issues for a matching spec", func() {
+		Expect(validate()).To(BeEmpty())
+	})
+
+	It("returns an error when the image does not exist", func() {
+		_, err := vmProvider.ValidateVMAgainstImage(ctx, nsInfo.Namespace, spec, "does-not-exist")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an issue when the boot disk is smaller than the image disk", func() {
+		spec.Advanced.BootDiskCapacity = resource.NewQuantity(5*1024*1024*1024, resource.BinarySI)
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeInvalid))
+		Expect(allErrs[0].Field).To(Equal("spec.advanced.bootDiskCapacity"))
+		Expect(allErrs[0].Detail).To(ContainSubstring("must not be less than the image's boot disk capacity 10Gi"))
+	})
+
+	It("returns no issues when the image's disks are not known", func() {
+		vmImage.Status.Disks = nil
+		Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
+
+		spec.Advanced.BootDiskCapacity = resource.NewQuantity(5*1024*1024*1024, resource.BinarySI)
+		for i := 0; i < 60; i++ {
+			spec.Volumes = append(spec.Volumes, vmopv1.VirtualMachineVolume{Name: fmt.Sprintf("vol-%d", i)})
+		}
+
+		Expect(validate()).To(BeEmpty())
+	})
+
+	It("returns an issue when there are too many disks", func() {
+		for i := 0; i < 59; i++ {
+			spec.Volumes = append(spec.Volumes, vmopv1.VirtualMachineVolume{Name: fmt.Sprintf("vol-%d", i)})
+		}
+		Expect(validate()).To(BeEmpty())
+
+		spec.Volumes = append(spec.Volumes, vmopv1.VirtualMachineVolume{Name: "vol-59"})
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeTooMany))
+		Expect(allErrs[0].Field).To(Equal("spec.volumes"))
+	})
+
+	It("returns an issue when there are too many network interfaces", func() {
+		for i := 0; i < 10; i++ {
+			spec.Network.Interfaces = append(spec.Network.Interfaces, vmopv1.VirtualMachineNetworkInterfaceSpec{})
+		}
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeTooMany))
+		Expect(allErrs[0].Field).To(Equal("spec.network.interfaces"))
+	})
+
+	It("returns an issue when the class network interface type does not match the image", func() {
+		vmImage.Status.NetworkInterfaceTypes = []string{"e1000"}
+		Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Field).To(Equal("spec.className"))
+		Expect(allErrs[0].Detail).To(ContainSubstring(`network interface type "vmxnet3" is not supported by the image`))
+	})
+
+	It("returns no issues when the class firmware overrides the image firmware", func() {
+		vmImage.Status.Firmware = "bios"
+		Expect(ctx.Client.Status().Update(ctx, vmImage)).To(Succeed())
+
+		Expect(validate()).To(BeEmpty())
+	})
+
+	It("returns an issue for a vApp property the image does not declare", func() {
+		spec.Bootstrap.VAppConfig.Properties = append(spec.Bootstrap.VAppConfig.Properties,
+			common.KeyValueOrSecretKeySelectorPair{Key: "not-a-property"})
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeNotFound))
+		Expect(allErrs[0].Field).To(Equal("spec.bootstrap.vAppConfig.properties[1].key"))
+		Expect(allErrs[0].BadValue).To(Equal("not-a-property"))
+	})
+	It("returns an issue for a spec vApp property the image does not declare", func() {
+		spec.Bootstrap = nil
+		spec.VAppProperties = map[string]common.ValueOrSecretKeySelector{
+			"hostname":       {Value: pointer.String("my-vm")},
+			"not-a-property": {Value: pointer.String("value")},
+		}
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeNotFound))
+		Expect(allErrs[0].Field).To(Equal("spec.vAppProperties[not-a-property]"))
+	})
+
+	It("returns an issue for a spec vApp password property that is not from a Secret", func() {
+		spec.Bootstrap = nil
+		spec.VAppProperties = map[string]common.ValueOrSecretKeySelector{
+			"password": {Value: pointer.String("plaintext")},
+		}
+
+		allErrs := validate()
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(allErrs[0].Field).To(Equal("spec.vAppProperties[password].value"))
+
+		By("returns no issues when from a Secret", func() {
+			spec.VAppProperties["password"] = common.ValueOrSecretKeySelector{
+				From: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+					Key:                  "password",
+				},
+			}
+			Expect(validate()).To(BeEmpty())
+		})
+	})
+}
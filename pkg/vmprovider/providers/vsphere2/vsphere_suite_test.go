@@ -14,11 +14,18 @@ import (
 var suite = builder.NewTestSuite()
 
 func vcSimTests() {
+	Describe("ClusterSettings", clusterSettingsTests)
 	Describe("CPUFreq", cpuFreqTests)
+	Describe("Host", hostTests)
+	Describe("OrphanedVM", orphanedVMTests)
+	Describe("ImageZoneCompatibility", imageZoneCompatibilityTests)
 	Describe("InitOvfCacheAndLockPool", initOvfCacheAndLockPoolTests)
+	Describe("Privileges", privilegesTests)
 	Describe("ResourcePolicyTests", resourcePolicyTests)
+	Describe("ValidateVMAgainstImage", validateVMAgainstImageTests)
 	Describe("VirtualMachine", vmTests)
 	Describe("VirtualMachineE2E", vmE2ETests)
+	Describe("VirtualMachineResourcePool", vmResourcePoolTests)
 	Describe("VirtualMachineUtilsTest", vmUtilTests)
 }
 
@@ -27,6 +27,14 @@ func NewFakeClient(objs ...client.Object) client.Client {
 		WithScheme(scheme).
 		WithObjects(objs...).
 		WithStatusSubresource(KnownObjectTypes()...).
+		WithIndex(&v1alpha2.VirtualMachineImage{}, "status.name",
+			func(rawObj client.Object) []string {
+				return []string{rawObj.(*v1alpha2.VirtualMachineImage).Status.Name}
+			}).
+		WithIndex(&v1alpha2.ClusterVirtualMachineImage{}, "status.name",
+			func(rawObj client.Object) []string {
+				return []string{rawObj.(*v1alpha2.ClusterVirtualMachineImage).Status.Name}
+			}).
 		Build()
 }
 
@@ -48,6 +56,7 @@ func KnownObjectTypes() []client.Object {
 		&v1alpha2.ClusterVirtualMachineImage{},
 		&v1alpha1.VirtualMachineImage{},
 		&v1alpha2.VirtualMachineImage{},
+		&v1alpha1.ContentSource{},
 		&cnsv1alpha1.CnsNodeVmAttachment{},
 		&ncpv1alpha1.VirtualNetworkInterface{},
 		&netopv1alpha1.NetworkInterface{},
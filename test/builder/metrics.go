@@ -0,0 +1,49 @@
+// Copyright (c) 2023 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	. "github.com/onsi/gomega"
+
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// GatherMetric returns the value of the metric with the name and labels from the controller-runtime
+// metrics registry, or nil if there is no such metric. The value of a histogram is its sample count.
+func GatherMetric(name string, labels map[string]string) *float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+	metricLoop:
+		for _, m := range family.GetMetric() {
+			metricLabels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				metricLabels[l.GetName()] = l.GetValue()
+			}
+			for k, v := range labels {
+				if metricLabels[k] != v {
+					continue metricLoop
+				}
+			}
+
+			var value float64
+			switch {
+			case m.Counter != nil:
+				value = m.GetCounter().GetValue()
+			case m.Gauge != nil:
+				value = m.GetGauge().GetValue()
+			case m.Histogram != nil:
+				value = float64(m.GetHistogram().GetSampleCount())
+			}
+			return &value
+		}
+	}
+
+	return nil
+}
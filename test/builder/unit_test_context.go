@@ -6,11 +6,15 @@ package builder
 import (
 	goctx "context"
 
+	. "github.com/onsi/gomega"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha1"
+
 	"github.com/vmware-tanzu/vm-operator/pkg/builder"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/context/fake"
@@ -90,6 +94,23 @@ func (ctx *UnitTestContextForController) AfterEach() {
 	// Nothing yet to do.
 }
 
+// ExpectContentSourceBindings asserts that a ContentSourceBinding for the content library exists in each
+// of the namespaces, and in no other namespace.
+func (ctx *UnitTestContextForController) ExpectContentSourceBindings(clUUID string, namespaces ...string) {
+	bindingList := &v1alpha1.ContentSourceBindingList{}
+	ExpectWithOffset(1, ctx.Client.List(ctx, bindingList)).To(Succeed())
+
+	var bindingNamespaces []string
+	for _, binding := range bindingList.Items {
+		if binding.Name == clUUID {
+			bindingNamespaces = append(bindingNamespaces, binding.Namespace)
+		}
+	}
+
+	ExpectWithOffset(1, bindingNamespaces).To(ConsistOf(namespaces),
+		"ContentSourceBindings for content library %s", clUUID)
+}
+
 // NewUnitTestContextForValidatingWebhook returns a new
 // UnitTestContextForValidatingWebhook for unit testing validating webhooks.
 func NewUnitTestContextForValidatingWebhook(
@@ -47,6 +47,9 @@ const (
 	DummyStorageClassName     = "dummy-storage-class"
 	DummyResourceQuotaName    = "dummy-resource-quota"
 	DummyAvailabilityZoneName = "dummy-availability-zone"
+	DummyVMMoID               = "vm-dummy"
+	DummyBiosUUID             = "42185a9c-3f8e-4d2b-9c6e-0e2f8b1d7a10"
+	DummyInstanceUUID         = "50185a9c-3f8e-4d2b-9c6e-0e2f8b1d7a10"
 )
 
 var (
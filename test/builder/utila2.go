@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	cnsv1alpha1 "github.com/vmware-tanzu/vm-operator/external/vsphere-csi-driver/pkg/syncer/cnsoperator/apis/cnsnodevmattachment/v1alpha1"
 )
 
 func DummyVirtualMachineClass2A2(name string) *vmopv1.VirtualMachineClass {
@@ -172,6 +173,24 @@ func AddDummyInstanceStorageVolumeA2(vm *vmopv1.VirtualMachine) {
 	vm.Spec.Volumes = append(vm.Spec.Volumes, DummyInstanceStorageVirtualMachineVolumesA2()...)
 }
 
+// SetDummyVirtualMachineIdentityA2 seeds the VM's status with the identity of its vSphere VM, as
+// if the VM had been created by the provider.
+func SetDummyVirtualMachineIdentityA2(vm *vmopv1.VirtualMachine) {
+	vm.Status.UniqueID = DummyVMMoID
+	vm.Status.BiosUUID = DummyBiosUUID
+	vm.Status.InstanceUUID = DummyInstanceUUID
+}
+
+// AttachVolumeToVM updates the CnsNodeVmAttachment the way the CNS attachment controller does
+// once the attachment's volume is attached to the VM as the disk with the UUID.
+func AttachVolumeToVM(attachment *cnsv1alpha1.CnsNodeVmAttachment, diskUUID string) {
+	attachment.Status.Attached = true
+	attachment.Status.Error = ""
+	attachment.Status.AttachmentMetadata = map[string]string{
+		"diskUUID": diskUUID,
+	}
+}
+
 func DummyVirtualMachineServiceA2() *vmopv1.VirtualMachineService {
 	return &vmopv1.VirtualMachineService{
 		ObjectMeta: metav1.ObjectMeta{
@@ -14,29 +14,43 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math/big"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/event"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vapi/library"
 	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -77,6 +91,16 @@ type VCSimTestConfig struct {
 	// name available in the TestContextForVCSim.ContentLibraryImageName.
 	WithContentLibrary bool
 
+	// WithSubscribedContentLibrary also configures a subscribed Content Library,
+	// available in the TestContextForVCSim.SubscribedContentLibraryID, that
+	// subscribes to the Content Library. Requires WithContentLibrary.
+	WithSubscribedContentLibrary bool
+
+	// WithContentLibraryUploadAttempts is the number of times the upload of a
+	// Content Library item's file is attempted before failing. Defaults to
+	// DefaultContentLibraryUploadAttempts.
+	WithContentLibraryUploadAttempts int
+
 	// WithInstanceStorage enables the WCP_INSTANCE_STORAGE FSS.
 	WithInstanceStorage bool
 
@@ -94,6 +118,22 @@ type VCSimTestConfig struct {
 	// limitations of gce2e.
 	WithDefaultNetwork string
 
+	// WithDefaultEthernetCardType sets the provider's default ethernet card
+	// type, ex. "e1000e".
+	WithDefaultEthernetCardType string
+
+	// WithHardwareVersionUpgradeTarget sets the provider's hardware version
+	// upgrade target, ex. 13.
+	WithHardwareVersionUpgradeTarget int32
+
+	// WithAdoptedVMFolder creates a Folder, available in the
+	// TestContextForVCSim.AdoptedVMFolder, that the provider moves adopted VMs into.
+	WithAdoptedVMFolder bool
+
+	// WithReadOnly puts the provider in read-only mode, where it does not make
+	// any changes to vCenter.
+	WithReadOnly bool
+
 	// WithVMClassAsConfig enables the WCP_VM_CLASS_AS_CONFIG FSS.
 	WithVMClassAsConfig bool
 
@@ -105,6 +145,10 @@ type VCSimTestConfig struct {
 
 	// WithNetworkEnv is the network environment type.
 	WithNetworkEnv NetworkEnv
+
+	// WithNamedNetworkCrossDatacenterLookup allows the NAMED network provider to
+	// look up networks in other datacenters.
+	WithNamedNetworkCrossDatacenterLookup bool
 }
 
 type TestContextForVCSim struct {
@@ -119,6 +163,11 @@ type TestContextForVCSim struct {
 	Finder       *find.Finder
 	RestClient   *rest.Client
 	Recorder     record.Recorder
+	// Events is a channel that Recorder records events to.
+	Events chan string
+
+	// When WithAdoptedVMFolder is true, the Folder adopted VMs are moved into.
+	AdoptedVMFolder *object.Folder
 
 	// When WithFaultDomains is true:
 	ZoneCount       int
@@ -126,8 +175,12 @@ type TestContextForVCSim struct {
 	ZoneNames       []string
 
 	// When WithContentLibrary is true:
-	ContentLibraryImageName string
-	ContentLibraryID        string
+	ContentLibraryImageName   string
+	ContentLibraryID          string
+	ContentLibraryImageItemID string
+
+	// When WithSubscribedContentLibrary is true:
+	SubscribedContentLibraryID string
 
 	// When WithoutStorageClass is false:
 	StorageClassName string
@@ -148,6 +201,30 @@ type TestContextForVCSim struct {
 
 	singleCCR *object.ClusterComputeResource
 	azCCRs    map[string][]*object.ClusterComputeResource
+
+	// State of the vcsim method handler: the specs captured from the requests sent to
+	// vcsim, and the simulated long-running tasks.
+	handlerLock         sync.Mutex
+	cloneRequests       int
+	lastCloneSpec       *types.VirtualMachineCloneSpec
+	lastReconfigureSpec *types.VirtualMachineConfigSpec
+	lastCustomizeSpec   *types.CustomizationSpec
+	lastPowerOnHost     *types.ManagedObjectReference
+	hardwareVersions    []string
+	guestOSHotAdd       *guestOSHotAddHandler
+	grantedPrivileges   []string
+	restrictedRole      bool
+	longRunningClone    bool
+	longRunningReconfig bool
+	unresponsiveGuest   bool
+	methodCalls         map[types.ManagedObjectReference][]string
+	methodFaults        map[string][]types.BaseMethodFault
+	blockedMethods      map[string]chan struct{}
+	longRunningTasks    map[types.ManagedObjectReference]bool // Value is if the task was cancelled.
+
+	// envBrowserHardwareVersions are the hardware versions of a cluster, keyed by the
+	// cluster's EnvironmentBrowser, that override hardwareVersions.
+	envBrowserHardwareVersions map[types.ManagedObjectReference][]string
 }
 
 type WorkloadNamespaceInfo struct {
@@ -181,12 +258,13 @@ func newTestContextForVCSim(
 	config VCSimTestConfig,
 	initObjects []client.Object) *TestContextForVCSim {
 
-	fakeRecorder, _ := NewFakeRecorder()
+	fakeRecorder, events := NewFakeRecorder()
 
 	ctx := &TestContextForVCSim{
 		UnitTestContext:  NewUnitTestContext(initObjects...),
 		PodNamespace:     "vmop-pod-test",
 		Recorder:         fakeRecorder,
+		Events:           events,
 		withFaultDomains: config.WithFaultDomains,
 		withV1A2:         config.WithV1A2,
 	}
@@ -339,6 +417,12 @@ func (c *TestContextForVCSim) setupEnv(config VCSimTestConfig) {
 		Expect(os.Unsetenv(lib.NetworkProviderType)).To(Succeed())
 	}
 
+	namedNetworkCrossDatacenterLookup := "false"
+	if config.WithNamedNetworkCrossDatacenterLookup {
+		namedNetworkCrossDatacenterLookup = "true"
+	}
+	Expect(os.Setenv(lib.NamedNetworkCrossDatacenterLookupEnv, namedNetworkCrossDatacenterLookup)).To(Succeed())
+
 	v1a2 := "false"
 	if config.WithV1A2 {
 		v1a2 = "true"
@@ -407,6 +491,7 @@ func (c *TestContextForVCSim) setupVCSim(config VCSimTestConfig) {
 	}
 
 	c.model = vcModel
+	simulator.Map.Handler = c.methodHandler
 	c.server = c.model.Service.NewServer()
 
 	vcClient, err := govmomi.NewClient(c, c.server.URL, true)
@@ -500,8 +585,31 @@ func (c *TestContextForVCSim) setupContentLibrary(config VCSimTestConfig) {
 	}
 	c.ContentLibraryImageName = libraryItem.Name
 
+	uploadAttempts := config.WithContentLibraryUploadAttempts
+	if uploadAttempts <= 0 {
+		uploadAttempts = DefaultContentLibraryUploadAttempts
+	}
+
 	itemID := createContentLibraryItem(libMgr, libraryItem,
-		path.Join(testutil.GetRootDirOrDie(), "images", "ttylinux-pc_i486-16.1.ovf"))
+		path.Join(testutil.GetRootDirOrDie(), "images", "ttylinux-pc_i486-16.1.ovf"), uploadAttempts)
+	c.ContentLibraryImageItemID = itemID
+
+	if config.WithSubscribedContentLibrary {
+		// vcsim does not fetch from the subscription URL, it only needs the URL to end with the publisher's ID.
+		subLibSpec := library.Library{
+			Name:    "vmop-subscribed-content-library",
+			Type:    "SUBSCRIBED",
+			Storage: libSpec.Storage,
+			Subscription: &library.Subscription{
+				SubscriptionURL: "http://" + c.RestClient.URL().Host + "/cls/vcsp/lib/" + clID,
+			},
+		}
+
+		subClID, err := libMgr.CreateLibrary(c, subLibSpec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subClID).ToNot(BeEmpty())
+		c.SubscribedContentLibraryID = subClID
+	}
 
 	// Not the exact right FFS, but it's what we've plumbed and is otherwise implied.
 	if c.withV1A2 {
@@ -546,6 +654,13 @@ func (c *TestContextForVCSim) setupContentLibrary(config VCSimTestConfig) {
 	}
 }
 
+// ContentLibraryLastSyncTime returns when the content library last synced, or nil if it never has.
+func (c *TestContextForVCSim) ContentLibraryLastSyncTime(clID string) *time.Time {
+	cl, err := library.NewManager(c.RestClient).GetLibraryByID(c, clID)
+	Expect(err).ToNot(HaveOccurred())
+	return cl.LastSyncTime
+}
+
 func (c *TestContextForVCSim) ContentLibraryItemTemplate(srcVMName, templateName string) {
 	clID := c.ContentLibraryID
 	Expect(clID).ToNot(BeEmpty())
@@ -589,10 +704,57 @@ func (c *TestContextForVCSim) ContentLibraryItemTemplate(srcVMName, templateName
 	}
 }
 
+// CreateInventoryTemplateImageA2 creates a VM template in the inventory by cloning the source VM
+// as a template, and a Ready ClusterVirtualMachineImage for it.
+func (c *TestContextForVCSim) CreateInventoryTemplateImageA2(srcVMName, templateName string) *v1alpha2.ClusterVirtualMachineImage {
+	vm, err := c.Finder.VirtualMachine(c, srcVMName)
+	Expect(err).ToNot(HaveOccurred())
+
+	folder, err := c.Finder.DefaultFolder(c)
+	Expect(err).ToNot(HaveOccurred())
+
+	task, err := vm.Clone(c, folder, templateName, types.VirtualMachineCloneSpec{Template: true})
+	Expect(err).ToNot(HaveOccurred())
+	info, err := task.WaitForResult(c, nil)
+	Expect(err).ToNot(HaveOccurred())
+	templateRef := info.Result.(types.ManagedObjectReference)
+
+	clusterVMImage := DummyClusterVirtualMachineImageA2(templateName)
+	clusterVMImage.Spec.ProviderRef.Kind = "VirtualMachine"
+	clusterVMImage.Spec.ProviderRef.Name = templateName
+	Expect(c.Client.Create(c, clusterVMImage)).To(Succeed())
+	clusterVMImage.Status.ProviderItemID = templateRef.Value
+	conditions2.MarkTrue(clusterVMImage, v1alpha2.ReadyConditionType)
+	Expect(c.Client.Status().Update(c, clusterVMImage)).To(Succeed())
+
+	return clusterVMImage
+}
+
+// CreateNamespaceVMImageA2 creates a Ready namespace scoped VirtualMachineImage that is backed by
+// the content library item of the ContentLibraryImageName image.
+func (c *TestContextForVCSim) CreateNamespaceVMImageA2(namespace, name string) *v1alpha2.VirtualMachineImage {
+	Expect(c.ContentLibraryImageItemID).ToNot(BeEmpty())
+
+	vmImage := DummyVirtualMachineImageA2(name)
+	vmImage.Namespace = namespace
+	vmImage.Spec.ProviderRef.Kind = "ContentLibraryItem"
+	Expect(c.Client.Create(c, vmImage)).To(Succeed())
+	vmImage.Status.ProviderItemID = c.ContentLibraryImageItemID
+	conditions2.MarkTrue(vmImage, v1alpha2.ReadyConditionType)
+	Expect(c.Client.Status().Update(c, vmImage)).To(Succeed())
+
+	return vmImage
+}
+
+// DefaultContentLibraryUploadAttempts is the default number of times the upload of a Content
+// Library item's file is attempted.
+const DefaultContentLibraryUploadAttempts = 5
+
 func createContentLibraryItem(
 	libMgr *library.Manager,
 	libraryItem library.Item,
-	itemPath string) string {
+	itemPath string,
+	uploadAttempts int) string {
 
 	ctx := goctx.Background()
 
@@ -637,12 +799,60 @@ func createContentLibraryItem(
 
 		return libMgr.Client.Upload(ctx, f, u, &p)
 	}
-	Expect(uploadFunc(itemPath)).To(Succeed())
+
+	backoff := wait.Backoff{
+		Steps:    uploadAttempts,
+		Duration: 100 * time.Millisecond,
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
+
+	attempts := 0
+	err = retry.OnError(backoff, isRetriableUploadError, func() error {
+		attempts++
+		if attempts > 1 {
+			// Keep the session alive across the retries, or recreate the session if it
+			// cannot be, so the file transfer is retried in an active session.
+			if err := libMgr.KeepAliveLibraryItemUpdateSession(ctx, sessionID); err != nil {
+				_ = libMgr.CancelLibraryItemUpdateSession(ctx, sessionID)
+
+				sessionID, err = libMgr.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: itemID})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return uploadFunc(itemPath)
+	})
+	if err != nil {
+		_ = libMgr.FailLibraryItemUpdateSession(ctx, sessionID)
+	}
+	Expect(err).ToNot(HaveOccurred(), "upload of %s to library item %s failed after %d attempt(s)",
+		itemPath, itemID, attempts)
+
 	Expect(libMgr.CompleteLibraryItemUpdateSession(ctx, sessionID)).To(Succeed())
 
 	return itemID
 }
 
+// isRetriableUploadError returns false for the errors that retrying the upload will not fix, like
+// the file not existing or the client not being authorized to upload it.
+func isRetriableUploadError(err error) bool {
+	if errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+
+	msg := err.Error()
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		if strings.Contains(msg, fmt.Sprintf("%d %s", code, http.StatusText(code))) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (c *TestContextForVCSim) setupK8sConfig(config VCSimTestConfig) {
 	password, _ := simulator.DefaultLogin.Password()
 	secret := &corev1.Secret{
@@ -702,6 +912,27 @@ func (c *TestContextForVCSim) setupK8sConfig(config VCSimTestConfig) {
 		data["Network"] = config.WithDefaultNetwork
 	}
 
+	if config.WithDefaultEthernetCardType != "" {
+		data["DefaultEthernetCardType"] = config.WithDefaultEthernetCardType
+	}
+
+	if config.WithHardwareVersionUpgradeTarget != 0 {
+		data["HardwareVersionUpgradeTarget"] = fmt.Sprint(config.WithHardwareVersionUpgradeTarget)
+	}
+
+	if config.WithAdoptedVMFolder {
+		folder, err := c.folder.CreateFolder(c, "adopted-vms")
+		Expect(err).ToNot(HaveOccurred())
+		c.AdoptedVMFolder = folder
+
+		data["AdoptedVMPlacement"] = "move-to-folder"
+		data["AdoptedVMFolder"] = folder.Reference().Value
+	}
+
+	if config.WithReadOnly {
+		data["ReadOnly"] = "true"
+	}
+
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "vsphere.provider.config.vmoperator.vmware.com",
@@ -880,6 +1111,763 @@ func (c *TestContextForVCSim) GetResourcePoolForNamespace(namespace, azName, chi
 	return nsRP
 }
 
+// SetResourcePoolLimits sets the CPU, in MHz, and memory, in MiB, limits of the ResourcePool, and
+// the upper bound on its usage that vSphere computes from them.
+// A negative limit removes the limit.
+func (c *TestContextForVCSim) SetResourcePoolLimits(rp *object.ResourcePool, cpuLimit, memoryLimit int64) {
+	spec := &types.ResourceConfigSpec{
+		CpuAllocation: types.ResourceAllocationInfo{
+			Limit: &cpuLimit,
+		},
+		MemoryAllocation: types.ResourceAllocationInfo{
+			Limit: &memoryLimit,
+		},
+	}
+	Expect(rp.UpdateConfig(c, "", spec)).To(Succeed())
+
+	// vcsim does not compute the upper bound on the usage of the ResourcePool from its limits.
+	obj := simulator.Map.Get(rp.Reference())
+	Expect(obj).To(BeAssignableToTypeOf(&simulator.ResourcePool{}))
+	runtime := obj.(*simulator.ResourcePool).Runtime
+	runtime.Cpu.MaxUsage = cpuLimit
+	runtime.Memory.MaxUsage = memoryLimit * 1024 * 1024
+	simulator.Map.AtomicUpdate(simulator.SpoofContext(), obj, []types.PropertyChange{
+		{Name: "runtime", Val: runtime},
+	})
+}
+
+// SetVirtualMachineFileLayout replaces the files in the VM's detailed file layout.
+func (c *TestContextForVCSim) SetVirtualMachineFileLayout(
+	ref types.ManagedObjectReference,
+	files ...types.VirtualMachineFileLayoutExFileInfo) {
+
+	obj := simulator.Map.Get(ref)
+	Expect(obj).To(BeAssignableToTypeOf(&simulator.VirtualMachine{}))
+
+	var layoutEx types.VirtualMachineFileLayoutEx
+	if l := obj.(*simulator.VirtualMachine).LayoutEx; l != nil {
+		layoutEx = *l
+	}
+	layoutEx.File = files
+	simulator.Map.AtomicUpdate(simulator.SpoofContext(), obj, []types.PropertyChange{
+		{Name: "layoutEx", Val: &layoutEx},
+	})
+}
+
+// UpdateVirtualMachineGuestInfo applies the mutation to the VM's guest info, to simulate changes
+// reported by VMware Tools in the guest.
+func (c *TestContextForVCSim) UpdateVirtualMachineGuestInfo(
+	ref types.ManagedObjectReference,
+	mutate func(*types.GuestInfo)) {
+
+	obj := simulator.Map.Get(ref)
+	Expect(obj).To(BeAssignableToTypeOf(&simulator.VirtualMachine{}))
+
+	var guest types.GuestInfo
+	if g := obj.(*simulator.VirtualMachine).Guest; g != nil {
+		guest = *g
+	}
+	mutate(&guest)
+	simulator.Map.AtomicUpdate(simulator.SpoofContext(), obj, []types.PropertyChange{
+		{Name: "guest", Val: &guest},
+	})
+}
+
+// SetVirtualMachineGuestDisks replaces the guest filesystems in the VM's guest info, to simulate
+// the disk usage reported by VMware Tools in the guest.
+func (c *TestContextForVCSim) SetVirtualMachineGuestDisks(
+	ref types.ManagedObjectReference,
+	disks ...types.GuestDiskInfo) {
+
+	c.UpdateVirtualMachineGuestInfo(ref, func(guest *types.GuestInfo) {
+		guest.Disk = disks
+	})
+}
+
+// SetVirtualMachineToolsVersion sets the version of VMware Tools in the VM's guest info, and its
+// status relative to the version available on the VM's host.
+func (c *TestContextForVCSim) SetVirtualMachineToolsVersion(
+	ref types.ManagedObjectReference,
+	version string,
+	status types.VirtualMachineToolsVersionStatus) {
+
+	c.UpdateVirtualMachineGuestInfo(ref, func(guest *types.GuestInfo) {
+		guest.ToolsVersion = version
+		guest.ToolsVersionStatus = string(status)
+		guest.ToolsVersionStatus2 = string(status)
+	})
+}
+
+// UpdateVirtualMachineClass applies the mutation to the VirtualMachineClass with the name and
+// returns the updated object. The name must be unique across the namespaces. Like the API
+// server, the generation is incremented when the spec is changed.
+func (c *TestContextForVCSim) UpdateVirtualMachineClass(
+	name string,
+	mutate func(*v1alpha2.VirtualMachineClass)) *v1alpha2.VirtualMachineClass {
+
+	vmClassList := &v1alpha2.VirtualMachineClassList{}
+	Expect(c.Client.List(c, vmClassList)).To(Succeed())
+
+	var vmClass *v1alpha2.VirtualMachineClass
+	for i := range vmClassList.Items {
+		if vmClassList.Items[i].Name == name {
+			Expect(vmClass).To(BeNil(), "VirtualMachineClass %s exists in multiple namespaces", name)
+			vmClass = &vmClassList.Items[i]
+		}
+	}
+	Expect(vmClass).ToNot(BeNil(), "VirtualMachineClass %s not found", name)
+
+	oldSpec := vmClass.Spec.DeepCopy()
+	mutate(vmClass)
+	if !apiequality.Semantic.DeepEqual(oldSpec, &vmClass.Spec) {
+		vmClass.Generation++
+	}
+	Expect(c.Client.Update(c, vmClass)).To(Succeed())
+
+	return vmClass
+}
+
+// methodHandler is the vcsim method handler that records the methods invoked, the specs
+// of the Clone and Reconfigure requests, and the host of the PowerOn requests, so tests
+// can assert what was requested, independent of what vcsim ultimately stores. It only
+// fails a method when a fault was injected, and only overrides the method's handler to
+// simulate a long-running clone or an unresponsive guest.
+func (c *TestContextForVCSim) methodHandler(
+	ctx *simulator.Context,
+	method *simulator.Method) (mo.Reference, types.BaseMethodFault) {
+
+	c.handlerLock.Lock()
+	blocked := c.blockedMethods[method.Name]
+	c.handlerLock.Unlock()
+
+	if blocked != nil {
+		<-blocked
+	}
+
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+
+	if c.methodCalls == nil {
+		c.methodCalls = map[types.ManagedObjectReference][]string{}
+	}
+	c.methodCalls[method.This] = append(c.methodCalls[method.This], method.Name)
+
+	if faults := c.methodFaults[method.Name]; len(faults) > 0 {
+		c.methodFaults[method.Name] = faults[1:]
+		return nil, faults[0]
+	}
+
+	switch req := method.Body.(type) {
+	case *types.CloneVM_Task:
+		spec := req.Spec
+		c.cloneRequests++
+		c.lastCloneSpec = &spec
+
+		if c.longRunningClone {
+			c.longRunningClone = false
+
+			return overrideHandler(ctx, &longRunningCloneHandler{self: method.This, c: c}), nil
+		}
+	case *types.ShutdownGuest:
+		if c.unresponsiveGuest {
+			return overrideHandler(ctx, &unresponsiveGuestHandler{self: method.This}), nil
+		}
+	case *types.ReconfigVM_Task:
+		spec := req.Spec
+		c.lastReconfigureSpec = &spec
+
+		if c.longRunningReconfig {
+			c.longRunningReconfig = false
+
+			return overrideHandler(ctx, &longRunningReconfigureHandler{self: method.This, c: c}), nil
+		}
+
+		if spec.SwapPlacement != "" {
+			// vcsim does not store the swap placement of the reconfigure.
+			ctx.Map.Update(ctx.Map.Get(method.This), []types.PropertyChange{
+				{Name: "config.swapPlacement", Val: spec.SwapPlacement},
+			})
+		}
+	case *types.CustomizeVM_Task:
+		spec := req.Spec
+		c.lastCustomizeSpec = &spec
+	case *types.PowerOnVM_Task:
+		c.lastPowerOnHost = req.Host
+	case *types.QueryConfigOptionDescriptor:
+		if versions, ok := c.envBrowserHardwareVersions[method.This]; ok {
+			return overrideHandler(ctx, &configOptionDescriptorHandler{self: method.This, versions: versions}), nil
+		}
+		if c.hardwareVersions != nil {
+			return overrideHandler(ctx, &configOptionDescriptorHandler{self: method.This, versions: c.hardwareVersions}), nil
+		}
+	case *types.QueryConfigOptionEx:
+		if c.guestOSHotAdd != nil {
+			h := *c.guestOSHotAdd
+			h.self = method.This
+			return overrideHandler(ctx, &h), nil
+		}
+	case *types.HasPrivilegeOnEntities:
+		if c.restrictedRole {
+			return overrideHandler(ctx, &restrictedRoleHandler{self: method.This, granted: c.grantedPrivileges}), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// overrideHandler returns the handler to override the method's handler with. The
+// session's objects take precedence over the handler returned to vcsim, so the
+// handler is also put in the session until the handler's method removes it with
+// removeOverrideHandler.
+func overrideHandler(ctx *simulator.Context, h mo.Reference) mo.Reference {
+	if ctx.Session != nil {
+		ctx.Session.Registry.Put(h)
+	}
+	return h
+}
+
+func removeOverrideHandler(ctx *simulator.Context, h mo.Reference) {
+	if ctx.Session != nil {
+		ctx.Session.Registry.Remove(ctx, h.Reference())
+	}
+}
+
+// unresponsiveGuestHandler is a vcsim handler for a VM whose guest accepts but
+// never completes a shutdown.
+type unresponsiveGuestHandler struct {
+	self types.ManagedObjectReference
+}
+
+func (h *unresponsiveGuestHandler) Reference() types.ManagedObjectReference {
+	return h.self
+}
+
+func (h *unresponsiveGuestHandler) ShutdownGuest(ctx *simulator.Context, _ *types.ShutdownGuest) soap.HasFault {
+	removeOverrideHandler(ctx, h)
+	return &methods.ShutdownGuestBody{Res: &types.ShutdownGuestResponse{}}
+}
+
+// configOptionDescriptorHandler is a vcsim EnvironmentBrowser handler that reports the
+// hardware versions as the ones VMs can be created with and upgraded to.
+type configOptionDescriptorHandler struct {
+	self     types.ManagedObjectReference
+	versions []string
+}
+
+func (h *configOptionDescriptorHandler) Reference() types.ManagedObjectReference {
+	return h.self
+}
+
+func (h *configOptionDescriptorHandler) QueryConfigOptionDescriptor(
+	ctx *simulator.Context,
+	_ *types.QueryConfigOptionDescriptor) soap.HasFault {
+
+	removeOverrideHandler(ctx, h)
+
+	descriptors := make([]types.VirtualMachineConfigOptionDescriptor, 0, len(h.versions))
+	for _, version := range h.versions {
+		descriptors = append(descriptors, types.VirtualMachineConfigOptionDescriptor{
+			Key:              version,
+			Description:      version,
+			CreateSupported:  types.NewBool(true),
+			RunSupported:     types.NewBool(true),
+			UpgradeSupported: types.NewBool(true),
+		})
+	}
+
+	return &methods.QueryConfigOptionDescriptorBody{
+		Res: &types.QueryConfigOptionDescriptorResponse{Returnval: descriptors},
+	}
+}
+
+// restrictedRoleHandler is a vcsim AuthorizationManager handler that only grants the
+// privileges of a restricted role, instead of vcsim granting every privilege.
+type restrictedRoleHandler struct {
+	self    types.ManagedObjectReference
+	granted []string
+}
+
+func (h *restrictedRoleHandler) Reference() types.ManagedObjectReference {
+	return h.self
+}
+
+func (h *restrictedRoleHandler) HasPrivilegeOnEntities(
+	ctx *simulator.Context,
+	req *types.HasPrivilegeOnEntities) soap.HasFault {
+
+	removeOverrideHandler(ctx, h)
+
+	granted := sets.New(h.granted...)
+
+	var res []types.EntityPrivilege
+	for _, entity := range req.Entity {
+		entityPriv := types.EntityPrivilege{Entity: entity}
+		for _, id := range req.PrivId {
+			entityPriv.PrivAvailability = append(entityPriv.PrivAvailability, types.PrivilegeAvailability{
+				PrivId:    id,
+				IsGranted: granted.Has(id),
+			})
+		}
+		res = append(res, entityPriv)
+	}
+
+	return &methods.HasPrivilegeOnEntitiesBody{
+		Res: &types.HasPrivilegeOnEntitiesResponse{Returnval: res},
+	}
+}
+
+// guestOSHotAddHandler is a vcsim EnvironmentBrowser handler that reports the guest OSes
+// as supporting CPU and memory hot add, instead of vcsim reporting neither is supported.
+type guestOSHotAddHandler struct {
+	self   types.ManagedObjectReference
+	cpu    bool
+	memory bool
+}
+
+func (h *guestOSHotAddHandler) Reference() types.ManagedObjectReference {
+	return h.self
+}
+
+func (h *guestOSHotAddHandler) QueryConfigOptionEx(
+	ctx *simulator.Context,
+	req *types.QueryConfigOptionEx) soap.HasFault {
+
+	removeOverrideHandler(ctx, h)
+
+	eb := simulator.Map.Get(h.self).(*simulator.EnvironmentBrowser)
+	body := eb.QueryConfigOptionEx(req).(*methods.QueryConfigOptionExBody)
+
+	opt := *body.Res.Returnval
+	opt.GuestOSDescriptor = append([]types.GuestOsDescriptor(nil), opt.GuestOSDescriptor...)
+	for i := range opt.GuestOSDescriptor {
+		opt.GuestOSDescriptor[i].SupportsCpuHotAdd = types.NewBool(h.cpu)
+		opt.GuestOSDescriptor[i].SupportsMemoryHotAdd = types.NewBool(h.memory)
+	}
+	body.Res.Returnval = &opt
+
+	return body
+}
+
+// longRunningCloneHandler is a vcsim handler that, instead of cloning the VM, starts
+// a cancellable task that runs until it is cancelled.
+type longRunningCloneHandler struct {
+	self types.ManagedObjectReference
+	c    *TestContextForVCSim
+}
+
+func (h *longRunningCloneHandler) Reference() types.ManagedObjectReference {
+	return h.self
+}
+
+func (h *longRunningCloneHandler) CloneVMTask(ctx *simulator.Context, _ *types.CloneVM_Task) soap.HasFault {
+	removeOverrideHandler(ctx, h)
+
+	task := h.c.startLongRunningTask(h.self, "cloneVm")
+	return &methods.CloneVM_TaskBody{
+		Res: &types.CloneVM_TaskResponse{Returnval: task.Self},
+	}
+}
+
+// longRunningReconfigureHandler is a vcsim handler that, instead of reconfiguring the VM,
+// starts a cancellable task that runs until it is cancelled.
+type longRunningReconfigureHandler struct {
+	self types.ManagedObjectReference
+	c    *TestContextForVCSim
+}
+
+func (h *longRunningReconfigureHandler) Reference() types.ManagedObjectReference {
+	return h.self
+}
+
+func (h *longRunningReconfigureHandler) ReconfigVMTask(ctx *simulator.Context, _ *types.ReconfigVM_Task) soap.HasFault {
+	removeOverrideHandler(ctx, h)
+
+	task := h.c.startLongRunningTask(h.self, "reconfigVm")
+	return &methods.ReconfigVM_TaskBody{
+		Res: &types.ReconfigVM_TaskResponse{Returnval: task.Self},
+	}
+}
+
+// longRunningTask is a vcsim task that runs until it is cancelled.
+type longRunningTask struct {
+	simulator.Task
+
+	c *TestContextForVCSim
+}
+
+func (t *longRunningTask) CancelTask(ctx *simulator.Context, _ *types.CancelTask) soap.HasFault {
+	t.c.handlerLock.Lock()
+	t.c.longRunningTasks[t.Self] = true
+	t.c.handlerLock.Unlock()
+
+	// The task's lock is held while this method is called.
+	ctx.Map.Update(t, []types.PropertyChange{
+		{Name: "info.state", Val: types.TaskInfoStateError},
+		{Name: "info.error", Val: &types.LocalizedMethodFault{
+			Fault:            &types.RequestCanceled{},
+			LocalizedMessage: "The task was canceled by a user.",
+		}},
+		{Name: "info.completeTime", Val: time.Now()},
+	})
+
+	return &methods.CancelTaskBody{Res: &types.CancelTaskResponse{}}
+}
+
+func (c *TestContextForVCSim) startLongRunningTask(entity types.ManagedObjectReference, name string) *longRunningTask {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+
+	task := &longRunningTask{
+		Task: *simulator.CreateTask(entity, name, nil),
+		c:    c,
+	}
+
+	// The task isn't visible to clients yet so update it directly.
+	now := time.Now()
+	task.Info.State = types.TaskInfoStateRunning
+	task.Info.StartTime = &now
+	task.Info.Cancelable = true
+
+	// Replace the registered Task with our wrapper since vcsim does not implement CancelTask.
+	simulator.Map.Put(task)
+
+	if c.longRunningTasks == nil {
+		c.longRunningTasks = map[types.ManagedObjectReference]bool{}
+	}
+	c.longRunningTasks[task.Self] = false
+
+	return task
+}
+
+// SimulateLongRunningCloneTask makes the next CloneVM_Task request start a cancellable
+// task that runs until it is cancelled, instead of cloning the VM.
+func (c *TestContextForVCSim) SimulateLongRunningCloneTask() {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.longRunningClone = true
+}
+
+// SimulateLongRunningReconfigureTask makes the next ReconfigVM_Task request start a cancellable
+// task that runs until it is cancelled, instead of reconfiguring the VM.
+func (c *TestContextForVCSim) SimulateLongRunningReconfigureTask() {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.longRunningReconfig = true
+}
+
+// LongRunningTasks returns the simulated long-running tasks that have been started.
+func (c *TestContextForVCSim) LongRunningTasks() []types.ManagedObjectReference {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+
+	tasks := make([]types.ManagedObjectReference, 0, len(c.longRunningTasks))
+	for ref := range c.longRunningTasks {
+		tasks = append(tasks, ref)
+	}
+	return tasks
+}
+
+// InjectMethodFault makes the next count requests of the method, ex. Destroy_Task, fail
+// with the fault instead of being invoked.
+func (c *TestContextForVCSim) InjectMethodFault(methodName string, fault types.BaseMethodFault, count int) {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+
+	if c.methodFaults == nil {
+		c.methodFaults = map[string][]types.BaseMethodFault{}
+	}
+	for i := 0; i < count; i++ {
+		c.methodFaults[methodName] = append(c.methodFaults[methodName], fault)
+	}
+}
+
+// BlockMethod makes the requests of the method, ex. ImportVApp, wait until the returned func is
+// called before they are invoked. The returned func may be called more than once.
+func (c *TestContextForVCSim) BlockMethod(methodName string) func() {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+
+	if c.blockedMethods == nil {
+		c.blockedMethods = map[string]chan struct{}{}
+	}
+	blocked := make(chan struct{})
+	c.blockedMethods[methodName] = blocked
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.handlerLock.Lock()
+			defer c.handlerLock.Unlock()
+
+			if c.blockedMethods[methodName] == blocked {
+				delete(c.blockedMethods, methodName)
+			}
+			close(blocked)
+		})
+	}
+}
+
+// SimulateUnresponsiveGuest makes the guest of every VM accept, but never complete, a shutdown.
+func (c *TestContextForVCSim) SimulateUnresponsiveGuest() {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.unresponsiveGuest = true
+}
+
+// SetHardwareVersions sets the hardware versions, ex. "vmx-19", that the clusters report VMs
+// can be created with and upgraded to, instead of the single version vcsim reports.
+func (c *TestContextForVCSim) SetHardwareVersions(versions ...string) {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.hardwareVersions = versions
+}
+
+// SetClusterHardwareVersions sets the hardware versions that just the cluster reports VMs can be
+// created with and upgraded to.
+func (c *TestContextForVCSim) SetClusterHardwareVersions(
+	clusterRef types.ManagedObjectReference,
+	versions ...string) {
+
+	cluster, ok := simulator.Map.Get(clusterRef).(*simulator.ClusterComputeResource)
+	Expect(ok).To(BeTrue(), "vcsim cluster %s not found", clusterRef.Value)
+	Expect(cluster.EnvironmentBrowser).ToNot(BeNil())
+
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	if c.envBrowserHardwareVersions == nil {
+		c.envBrowserHardwareVersions = map[types.ManagedObjectReference][]string{}
+	}
+	c.envBrowserHardwareVersions[*cluster.EnvironmentBrowser] = versions
+}
+
+// SetGuestOSHotAddSupported sets whether the clusters report the guest OSes support CPU and
+// memory hot add, instead of vcsim reporting neither is supported.
+func (c *TestContextForVCSim) SetGuestOSHotAddSupported(cpu, memory bool) {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.guestOSHotAdd = &guestOSHotAddHandler{cpu: cpu, memory: memory}
+}
+
+// SetRestrictedRole restricts the privileges granted to the session to only the privileges,
+// ex. "VirtualMachine.Inventory.Create", instead of vcsim granting every privilege.
+func (c *TestContextForVCSim) SetRestrictedRole(privileges ...string) {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.restrictedRole = true
+	c.grantedPrivileges = privileges
+}
+
+// MethodCalls returns the names of the methods invoked on the managed object, in the order
+// they were sent to vcsim.
+func (c *TestContextForVCSim) MethodCalls(ref types.ManagedObjectReference) []string {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return append([]string(nil), c.methodCalls[ref]...)
+}
+
+// SetLongRunningTaskProgress sets the progress percentage of the simulated long-running task.
+func (c *TestContextForVCSim) SetLongRunningTaskProgress(task types.ManagedObjectReference, progress int32) {
+	obj := simulator.Map.Get(task)
+	Expect(obj).To(BeAssignableToTypeOf(&longRunningTask{}))
+
+	simulator.Map.AtomicUpdate(simulator.SpoofContext(), obj, []types.PropertyChange{
+		{Name: "info.progress", Val: progress},
+	})
+}
+
+// SetLongRunningTaskNotCancelable makes the simulated long-running task report that it cannot be
+// cancelled.
+func (c *TestContextForVCSim) SetLongRunningTaskNotCancelable(task types.ManagedObjectReference) {
+	obj := simulator.Map.Get(task)
+	Expect(obj).To(BeAssignableToTypeOf(&longRunningTask{}))
+
+	simulator.Map.AtomicUpdate(simulator.SpoofContext(), obj, []types.PropertyChange{
+		{Name: "info.cancelable", Val: false},
+	})
+}
+
+// CompleteLongRunningTask makes the simulated long-running task succeed with the result.
+func (c *TestContextForVCSim) CompleteLongRunningTask(task types.ManagedObjectReference, result types.AnyType) {
+	obj := simulator.Map.Get(task)
+	Expect(obj).To(BeAssignableToTypeOf(&longRunningTask{}))
+
+	simulator.Map.AtomicUpdate(simulator.SpoofContext(), obj, []types.PropertyChange{
+		{Name: "info.result", Val: result},
+		{Name: "info.state", Val: types.TaskInfoStateSuccess},
+		{Name: "info.completeTime", Val: time.Now()},
+	})
+}
+
+// IsTaskCancelled returns true if CancelTask was invoked on the simulated long-running task.
+func (c *TestContextForVCSim) IsTaskCancelled(task types.ManagedObjectReference) bool {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return c.longRunningTasks[task]
+}
+
+// GetImageCacheVM returns the VM the content library item has been cached to on the
+// datastore, or nil if the item is not cached there.
+func (c *TestContextForVCSim) GetImageCacheVM(itemID, datastoreMoID string) *object.VirtualMachine {
+	vms, err := c.Finder.VirtualMachineList(c, "*")
+	if err != nil {
+		return nil
+	}
+
+	for _, vm := range vms {
+		var o mo.VirtualMachine
+		Expect(vm.Properties(c, vm.Reference(), []string{"config.extraConfig", "datastore"}, &o)).To(Succeed())
+		if o.Config == nil {
+			continue
+		}
+
+		for _, ec := range o.Config.ExtraConfig {
+			if ov := ec.GetOptionValue(); ov.Key != "vmservice.imagecache.itemID" || ov.Value != itemID {
+				continue
+			}
+
+			for _, ds := range o.Datastore {
+				if ds.Value == datastoreMoID {
+					return vm
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CloneRequestCount returns the number of CloneVM_Task requests sent to vcsim.
+func (c *TestContextForVCSim) CloneRequestCount() int {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return c.cloneRequests
+}
+
+// LastCloneSpec returns the CloneSpec of the last CloneVM_Task request sent to vcsim, or nil if none.
+func (c *TestContextForVCSim) LastCloneSpec() *types.VirtualMachineCloneSpec {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return c.lastCloneSpec
+}
+
+// LastReconfigureSpec returns the ConfigSpec of the last ReconfigVM_Task request sent to vcsim, or nil if none.
+func (c *TestContextForVCSim) LastReconfigureSpec() *types.VirtualMachineConfigSpec {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return c.lastReconfigureSpec
+}
+
+// LastCustomizeSpec returns the CustomizationSpec of the last CustomizeVM_Task request sent to vcsim, or nil if none.
+func (c *TestContextForVCSim) LastCustomizeSpec() *types.CustomizationSpec {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return c.lastCustomizeSpec
+}
+
+// LastPowerOnHost returns the host of the last PowerOnVM_Task request sent to vcsim, or nil if none
+// or if the request left the host to be selected by DRS.
+func (c *TestContextForVCSim) LastPowerOnHost() *types.ManagedObjectReference {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	return c.lastPowerOnHost
+}
+
+// RegisterCryptoKeyProvider registers a KMS key provider with the vcsim CryptoManager.
+func (c *TestContextForVCSim) RegisterCryptoKeyProvider(providerID string) {
+	cmRef := c.VCClient.ServiceContent.CryptoManager
+	Expect(cmRef).ToNot(BeNil())
+
+	cm, ok := simulator.Map.Get(*cmRef).(*mo.CryptoManagerKmip)
+	if !ok {
+		cm = &mo.CryptoManagerKmip{}
+		cm.Self = *cmRef
+		simulator.Map.Put(cm)
+	}
+
+	cm.KmipServers = append(cm.KmipServers, types.KmipClusterInfo{
+		ClusterId: types.KeyProviderId{Id: providerID},
+	})
+}
+
+// SetVirtualMachineDeviceConnected sets the connected state of the vcsim VM's device with the key.
+func (c *TestContextForVCSim) SetVirtualMachineDeviceConnected(
+	vmRef types.ManagedObjectReference,
+	deviceKey int32,
+	connected bool) {
+
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	var found bool
+	simulator.Map.WithLock(simulator.SpoofContext(), vm, func() {
+		for _, device := range vm.Config.Hardware.Device {
+			vd := device.GetVirtualDevice()
+			if vd.Key != deviceKey {
+				continue
+			}
+
+			if vd.Connectable == nil {
+				vd.Connectable = &types.VirtualDeviceConnectInfo{}
+			}
+			vd.Connectable.Connected = connected
+			found = true
+		}
+	})
+	Expect(found).To(BeTrue(), "vcsim VM %s device %d not found", vmRef.Value, deviceKey)
+}
+
+// AssertVirtualMachineDeviceConnected asserts the connected state of the vcsim VM's device with the key.
+func (c *TestContextForVCSim) AssertVirtualMachineDeviceConnected(
+	vmRef types.ManagedObjectReference,
+	deviceKey int32,
+	connected bool) {
+
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	ExpectWithOffset(1, ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	device := object.VirtualDeviceList(vm.Config.Hardware.Device).FindByKey(deviceKey)
+	ExpectWithOffset(1, device).ToNot(BeNil(), "vcsim VM %s device %d not found", vmRef.Value, deviceKey)
+
+	connectable := device.GetVirtualDevice().Connectable
+	ExpectWithOffset(1, connectable).ToNot(BeNil())
+	ExpectWithOffset(1, connectable.Connected).To(Equal(connected))
+}
+
+// GetVirtualMachineDiskFiles returns the backing file of each of the vcsim VM's disks, keyed by the
+// disk's device key.
+func (c *TestContextForVCSim) GetVirtualMachineDiskFiles(vmRef types.ManagedObjectReference) map[int32]string {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	ExpectWithOffset(1, ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	files := map[int32]string{}
+	for _, device := range object.VirtualDeviceList(vm.Config.Hardware.Device).SelectByType((*types.VirtualDisk)(nil)) {
+		vd := device.GetVirtualDevice()
+		if backing, ok := vd.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+			files[vd.Key] = backing.GetVirtualDeviceFileBackingInfo().FileName
+		}
+	}
+
+	return files
+}
+
+// DatastoreFileExists returns true if the file with the datastore path, ex. "[LocalDS_0] vm/disk.vmdk",
+// exists.
+func (c *TestContextForVCSim) DatastoreFileExists(name string) bool {
+	var p object.DatastorePath
+	ExpectWithOffset(1, p.FromString(name)).To(BeTrue(), "invalid datastore path %q", name)
+
+	ds, err := c.Finder.Datastore(c, p.Datastore)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	if _, err := ds.Stat(c, p.Path); err != nil {
+		var notFound object.DatastoreNoSuchFileError
+		ExpectWithOffset(1, errors.As(err, &notFound)).To(BeTrue(), "stat %q: %v", name, err)
+		return false
+	}
+
+	return true
+}
+
 func generatePrivateKey() *rsa.PrivateKey {
 	reader := rand.Reader
 	bitSize := 2048
@@ -929,3 +1917,266 @@ func generateSelfSignedCert() (string, string) {
 
 	return keyOut.Name(), certOut.Name()
 }
+
+// SetVirtualMachineHardwareVersion sets the hardware version of the vcsim VM.
+func (c *TestContextForVCSim) SetVirtualMachineHardwareVersion(
+	vmRef types.ManagedObjectReference,
+	version string) {
+
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	simulator.Map.Update(vm, []types.PropertyChange{
+		{Name: "config.version", Val: version},
+	})
+}
+
+// SetClusterDRSAndHA sets the DRS and vSphere HA configuration of the vcsim cluster.
+func (c *TestContextForVCSim) SetClusterDRSAndHA(
+	clusterRef types.ManagedObjectReference,
+	drsEnabled bool,
+	drsAutomationLevel types.DrsBehavior,
+	haEnabled bool) {
+
+	cluster, ok := simulator.Map.Get(clusterRef).(*simulator.ClusterComputeResource)
+	Expect(ok).To(BeTrue(), "vcsim cluster %s not found", clusterRef.Value)
+
+	// vcsim does not reconfigure the DRS and HA of a cluster so set them directly.
+	simulator.Map.WithLock(simulator.SpoofContext(), cluster, func() {
+		config := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx)
+		config.DrsConfig.Enabled = &drsEnabled
+		config.DrsConfig.DefaultVmBehavior = drsAutomationLevel
+		config.DasConfig.Enabled = &haEnabled
+	})
+}
+
+// GetClusterDasVmConfig returns the vSphere HA override of the VM in the vcsim cluster's DAS VM
+// configuration, or nil if the VM does not have an override.
+func (c *TestContextForVCSim) GetClusterDasVmConfig(
+	clusterRef, vmRef types.ManagedObjectReference) *types.ClusterDasVmConfigInfo {
+
+	cluster, ok := simulator.Map.Get(clusterRef).(*simulator.ClusterComputeResource)
+	Expect(ok).To(BeTrue(), "vcsim cluster %s not found", clusterRef.Value)
+
+	var dasVMConfig *types.ClusterDasVmConfigInfo
+	simulator.Map.WithLock(simulator.SpoofContext(), cluster, func() {
+		config := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx)
+		for i := range config.DasVmConfig {
+			if config.DasVmConfig[i].Key == vmRef {
+				info := config.DasVmConfig[i]
+				dasVMConfig = &info
+				break
+			}
+		}
+	})
+	return dasVMConfig
+}
+
+// TagObject attaches to the vcsim object the tag with the name in the category with the name. The
+// category and tag are created if they do not exist.
+func (c *TestContextForVCSim) TagObject(ref types.ManagedObjectReference, categoryName, tagName string) {
+	m := tags.NewManager(c.RestClient)
+
+	category, err := m.GetCategory(c, categoryName)
+	if err != nil {
+		categoryID, err := m.CreateCategory(c, &tags.Category{
+			Name:            categoryName,
+			Cardinality:     "MULTIPLE",
+			AssociableTypes: []string{ref.Type},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		category = &tags.Category{ID: categoryID, Name: categoryName}
+	}
+
+	tagID := ""
+	categoryTags, err := m.GetTagsForCategory(c, category.ID)
+	Expect(err).ToNot(HaveOccurred())
+	for _, tag := range categoryTags {
+		if tag.Name == tagName {
+			tagID = tag.ID
+			break
+		}
+	}
+	if tagID == "" {
+		tagID, err = m.CreateTag(c, &tags.Tag{Name: tagName, CategoryID: category.ID})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(m.AttachTag(c, tagID, ref)).To(Succeed())
+}
+
+// GetHostsForCluster returns the hosts of the vcsim cluster.
+func (c *TestContextForVCSim) GetHostsForCluster(clusterRef types.ManagedObjectReference) []types.ManagedObjectReference {
+	cluster, ok := simulator.Map.Get(clusterRef).(*simulator.ClusterComputeResource)
+	Expect(ok).To(BeTrue(), "vcsim cluster %s not found", clusterRef.Value)
+
+	var hostRefs []types.ManagedObjectReference
+	simulator.Map.WithLock(simulator.SpoofContext(), cluster, func() {
+		hostRefs = append(hostRefs, cluster.Host...)
+	})
+	return hostRefs
+}
+
+// GetVirtualMachineRuntimeHost returns the host the vcsim VM is running on.
+func (c *TestContextForVCSim) GetVirtualMachineRuntimeHost(vmRef types.ManagedObjectReference) types.ManagedObjectReference {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	var hostRef types.ManagedObjectReference
+	simulator.Map.WithLock(simulator.SpoofContext(), vm, func() {
+		Expect(vm.Runtime.Host).ToNot(BeNil())
+		hostRef = *vm.Runtime.Host
+	})
+	return hostRef
+}
+
+// RelocateVirtualMachine simulates the migration of the vcsim VM to the host.
+func (c *TestContextForVCSim) RelocateVirtualMachine(vmRef, hostRef types.ManagedObjectReference) {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	srcHostRef := c.GetVirtualMachineRuntimeHost(vmRef)
+	srcHost, ok := simulator.Map.Get(srcHostRef).(*simulator.HostSystem)
+	Expect(ok).To(BeTrue(), "vcsim host %s not found", srcHostRef.Value)
+	dstHost, ok := simulator.Map.Get(hostRef).(*simulator.HostSystem)
+	Expect(ok).To(BeTrue(), "vcsim host %s not found", hostRef.Value)
+
+	ctx := simulator.SpoofContext()
+	simulator.Map.RemoveReference(ctx, srcHost, &srcHost.Vm, vmRef)
+	simulator.Map.AddReference(ctx, dstHost, &dstHost.Vm, vmRef)
+	simulator.Map.Update(vm, []types.PropertyChange{
+		{Name: "runtime.host", Val: &hostRef},
+		{Name: "summary.runtime.host", Val: &hostRef},
+	})
+}
+
+// SimulateHARestart simulates vSphere HA restarting the vcsim VM on another host in its cluster
+// after the VM's host failed: the VM is moved to the other host, it is booted again, and the HA
+// restart event is posted. The other host is returned.
+func (c *TestContextForVCSim) SimulateHARestart(vmRef types.ManagedObjectReference) types.ManagedObjectReference {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	srcHostRef := c.GetVirtualMachineRuntimeHost(vmRef)
+	srcHost, ok := simulator.Map.Get(srcHostRef).(*simulator.HostSystem)
+	Expect(ok).To(BeTrue(), "vcsim host %s not found", srcHostRef.Value)
+
+	dstHostRef := c.SetVirtualMachineRuntimeHost(vmRef)
+	dstHost, ok := simulator.Map.Get(dstHostRef).(*simulator.HostSystem)
+	Expect(ok).To(BeTrue(), "vcsim host %s not found", dstHostRef.Value)
+
+	bootTime := time.Now()
+	simulator.Map.Update(vm, []types.PropertyChange{
+		{Name: "runtime.bootTime", Val: &bootTime},
+		{Name: "summary.runtime.bootTime", Val: &bootTime},
+	})
+
+	restartEvent := &types.VmRestartedOnAlternateHostEvent{
+		VmPoweredOnEvent: types.VmPoweredOnEvent{
+			VmEvent: types.VmEvent{
+				Event: types.Event{
+					Vm:   &types.VmEventArgument{Vm: vmRef, EntityEventArgument: types.EntityEventArgument{Name: vm.Name}},
+					Host: &types.HostEventArgument{Host: dstHostRef, EntityEventArgument: types.EntityEventArgument{Name: dstHost.Name}},
+				},
+			},
+		},
+		SourceHost: types.HostEventArgument{Host: srcHostRef, EntityEventArgument: types.EntityEventArgument{Name: srcHost.Name}},
+	}
+	Expect(event.NewManager(c.VCClient.Client).PostEvent(c, restartEvent)).To(Succeed())
+
+	return dstHostRef
+}
+
+// SetVirtualMachineRuntimeHost simulates the migration of the vcsim VM to another host in its
+// cluster, and returns that host.
+func (c *TestContextForVCSim) SetVirtualMachineRuntimeHost(vmRef types.ManagedObjectReference) types.ManagedObjectReference {
+	srcHostRef := c.GetVirtualMachineRuntimeHost(vmRef)
+	srcHost, ok := simulator.Map.Get(srcHostRef).(*simulator.HostSystem)
+	Expect(ok).To(BeTrue(), "vcsim host %s not found", srcHostRef.Value)
+	Expect(srcHost.Parent).ToNot(BeNil())
+
+	var dstHostRef types.ManagedObjectReference
+	for _, hostRef := range c.GetHostsForCluster(*srcHost.Parent) {
+		if hostRef != srcHostRef {
+			dstHostRef = hostRef
+			break
+		}
+	}
+	Expect(dstHostRef.Value).ToNot(BeEmpty(), "vcsim cluster %s has no other hosts", srcHost.Parent.Value)
+
+	c.RelocateVirtualMachine(vmRef, dstHostRef)
+	return dstHostRef
+}
+
+// GetVirtualMachineSwapPlacement returns the swap placement of the vcsim VM.
+func (c *TestContextForVCSim) GetVirtualMachineSwapPlacement(vmRef types.ManagedObjectReference) string {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	var swapPlacement string
+	simulator.Map.WithLock(simulator.SpoofContext(), vm, func() {
+		swapPlacement = vm.Config.SwapPlacement
+	})
+	return swapPlacement
+}
+
+// IsVirtualMachineTemplate returns whether the vcsim VM is marked as a template.
+func (c *TestContextForVCSim) IsVirtualMachineTemplate(vmRef types.ManagedObjectReference) bool {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	var template bool
+	simulator.Map.WithLock(simulator.SpoofContext(), vm, func() {
+		template = vm.Config.Template
+	})
+	return template
+}
+
+// SetHostLocalSwapDatastore sets the swap datastore of the vcsim hosts, where the swap files of
+// VMs with a host local swap placement are placed, to the test datastore.
+func (c *TestContextForVCSim) SetHostLocalSwapDatastore() {
+	dsRef := c.datastore.Reference()
+	for _, host := range simulator.Map.All("HostSystem") {
+		simulator.Map.Update(host, []types.PropertyChange{
+			{Name: "config.localSwapDatastore", Val: &dsRef},
+		})
+	}
+}
+
+// CreateFirstClassDisk creates a First Class Disk on the test datastore and returns its ID.
+func (c *TestContextForVCSim) CreateFirstClassDisk(name string, capacityInMB int64) string {
+	spec := types.VslmCreateSpec{
+		Name:         name,
+		CapacityInMB: capacityInMB,
+		BackingSpec: &types.VslmCreateSpecDiskFileBackingSpec{
+			VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{
+				Datastore: c.datastore.Reference(),
+			},
+			ProvisioningType: string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeThin),
+		},
+	}
+
+	task, err := vslm.NewObjectManager(c.VCClient.Client).CreateDisk(c, spec)
+	Expect(err).ToNot(HaveOccurred())
+	result, err := task.WaitForResult(c)
+	Expect(err).ToNot(HaveOccurred())
+
+	obj, ok := result.Result.(types.VStorageObject)
+	Expect(ok).To(BeTrue(), "unexpected create disk result %T", result.Result)
+	return obj.Config.Id.Id
+}
+
+// GetVirtualMachineCPUAffinity returns the CPU affinity set of the vcsim VM, or nil if the
+// VM does not have a CPU affinity.
+func (c *TestContextForVCSim) GetVirtualMachineCPUAffinity(vmRef types.ManagedObjectReference) []int32 {
+	vm, ok := simulator.Map.Get(vmRef).(*simulator.VirtualMachine)
+	Expect(ok).To(BeTrue(), "vcsim VM %s not found", vmRef.Value)
+
+	var affinity []int32
+	simulator.Map.WithLock(simulator.SpoofContext(), vm, func() {
+		if vm.Config.CpuAffinity != nil {
+			affinity = append(affinity, vm.Config.CpuAffinity.AffinitySet...)
+		}
+	})
+	return affinity
+}
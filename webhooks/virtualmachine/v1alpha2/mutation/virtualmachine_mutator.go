@@ -30,6 +30,7 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/builder"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/imageresolver"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
 )
 
@@ -50,11 +51,12 @@ const (
 // AddToManager adds the webhook to the provided manager.
 func AddToManager(ctx *context.ControllerManagerContext, mgr ctrlmgr.Manager) error {
 	// Index the VirtualMachineImage and ClusterVirtualMachineImage objects by
-	// status.name field to allow efficient querying in ResolveImageName().
+	// status.name field to allow efficient querying in ResolveImageName() and in the resolution
+	// of a VM image name by the VM provider.
 	if err := mgr.GetFieldIndexer().IndexField(
 		ctx,
 		&vmopv1.VirtualMachineImage{},
-		"status.name",
+		imageresolver.StatusNameField,
 		func(rawObj client.Object) []string {
 			vmi := rawObj.(*vmopv1.VirtualMachineImage)
 			return []string{vmi.Status.Name}
@@ -64,7 +66,7 @@ func AddToManager(ctx *context.ControllerManagerContext, mgr ctrlmgr.Manager) er
 	if err := mgr.GetFieldIndexer().IndexField(
 		ctx,
 		&vmopv1.ClusterVirtualMachineImage{},
-		"status.name",
+		imageresolver.StatusNameField,
 		func(rawObj client.Object) []string {
 			cvmi := rawObj.(*vmopv1.ClusterVirtualMachineImage)
 			return []string{cvmi.Status.Name}
@@ -283,7 +285,7 @@ func ResolveImageName(
 	vmiList := &vmopv1.VirtualMachineImageList{}
 	if err := c.List(ctx, vmiList, client.InNamespace(vm.Namespace),
 		client.MatchingFields{
-			"status.name": imageName,
+			imageresolver.StatusNameField: imageName,
 		},
 	); err != nil {
 		return false, err
@@ -300,7 +302,7 @@ func ResolveImageName(
 	// Check if a single cluster scope image exists by the status name.
 	cvmiList := &vmopv1.ClusterVirtualMachineImageList{}
 	if err := c.List(ctx, cvmiList, client.MatchingFields{
-		"status.name": imageName,
+		imageresolver.StatusNameField: imageName,
 	}); err != nil {
 		return false, err
 	}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,12 +32,14 @@ import (
 	"github.com/pkg/errors"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	"github.com/vmware-tanzu/vm-operator/api/v1alpha2/sysprep"
 	volume "github.com/vmware-tanzu/vm-operator/controllers/volume/v1alpha2"
 	"github.com/vmware-tanzu/vm-operator/pkg/builder"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere/config"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/webhooks/common"
 )
 
@@ -43,13 +48,18 @@ const (
 	storageResourceQuotaStrPattern       = ".storageclass.storage.k8s.io/"
 	isRestrictedNetworkKey               = "IsRestrictedNetwork"
 	allowedRestrictedNetworkTCPProbePort = 6443
+	minNetworkInterfaceMTU               = 576
+	maxNetworkInterfaceMTU               = 9000
+	maxSysprepComputerNameLength         = 15
 
 	readinessProbeOnlyOneAction              = "only one action can be specified"
+	volumeOnlyOneSource                      = "only one volume source can be specified"
 	updatesNotAllowedWhenPowerOn             = "updates to this field is not allowed when VM power is on"
 	storageClassNotAssignedFmt               = "Storage policy is not associated with the namespace %s"
 	storageClassNotFoundFmt                  = "Storage policy is not associated with the namespace %s"
 	vSphereVolumeSizeNotMBMultiple           = "value must be a multiple of MB"
 	addingModifyingInstanceVolumesNotAllowed = "adding or modifying instance storage volume claim(s) is not allowed"
+	addingModifyingFCDVolumesNotAllowed      = "adding or modifying first class disk volume(s) is not allowed for non-admin users"
 	featureNotEnabled                        = "the %s feature is not enabled"
 	invalidPowerStateOnCreateFmt             = "cannot set a new VM's power state to %s"
 	invalidPowerStateOnUpdateFmt             = "cannot %s a VM that is %s"
@@ -58,6 +68,13 @@ const (
 	invalidNextRestartTimeOnUpdate           = "must be formatted as RFC3339Nano"
 	invalidNextRestartTimeOnUpdateNow        = "mutation webhooks are required to restart VM"
 	modifyAnnotationNotAllowedForNonAdmin    = "modifying this annotation is not allowed for non-admin users"
+	invalidDeletionGracePeriod               = "must be a non-negative duration, ex. 24h"
+	deletionGracePeriodExceedsMaxFmt         = "must not exceed the maximum deletion grace period of %s"
+	displayNameConflictFmt                   = "display name is already used by VirtualMachine %s"
+	invalidSerialPortURIFmt                  = "must be a URI with the telnet or tcp scheme: %v"
+	invalidSerialPortURIScheme               = "must be a URI with the telnet or tcp scheme"
+	addingModifyingSerialPortNotAllowed      = "adding or modifying a serial port's URI is not allowed for non-admin users"
+	sysprepComputerNameTooLongFmt            = "the computer name of an inlined Sysprep must be at most %d characters"
 )
 
 // +kubebuilder:webhook:verbs=create;update,path=/default-validate-vmoperator-vmware-com-v1alpha2-virtualmachine,mutating=false,failurePolicy=fail,groups=vmoperator.vmware.com,resources=virtualmachines,versions=v1alpha2,name=default.validating.virtualmachine.v1alpha2.vmoperator.vmware.com,sideEffects=None,admissionReviewVersions=v1;v1beta1
@@ -106,11 +123,13 @@ func (v validator) ValidateCreate(ctx *context.WebhookRequestContext) admission.
 	fieldErrs = append(fieldErrs, v.validateClass(ctx, vm)...)
 	fieldErrs = append(fieldErrs, v.validateStorageClass(ctx, vm)...)
 	fieldErrs = append(fieldErrs, v.validateBootstrap(ctx, vm)...)
-	fieldErrs = append(fieldErrs, v.validateNetwork(ctx, vm)...)
+	fieldErrs = append(fieldErrs, v.validateVAppProperties(ctx, vm)...)
+	fieldErrs = append(fieldErrs, v.validateNetwork(ctx, vm, nil)...)
 	fieldErrs = append(fieldErrs, v.validateVolumes(ctx, vm)...)
 	fieldErrs = append(fieldErrs, v.validateInstanceStorageVolumes(ctx, vm, nil)...)
+	fieldErrs = append(fieldErrs, v.validateFirstClassDiskVolumes(ctx, vm, nil)...)
 	fieldErrs = append(fieldErrs, v.validateReadinessProbe(ctx, vm)...)
-	fieldErrs = append(fieldErrs, v.validateAdvanced(ctx, vm)...)
+	fieldErrs = append(fieldErrs, v.validateAdvanced(ctx, vm, nil)...)
 	fieldErrs = append(fieldErrs, v.validatePowerStateOnCreate(ctx, vm)...)
 	fieldErrs = append(fieldErrs, v.validateNextRestartTimeOnCreate(ctx, vm)...)
 	fieldErrs = append(fieldErrs, v.validateAnnotation(ctx, vm, nil)...)
@@ -162,11 +181,13 @@ func (v validator) ValidateUpdate(ctx *context.WebhookRequestContext) admission.
 	// of whether the update is allowed or not.
 	fieldErrs = append(fieldErrs, v.validateAvailabilityZone(ctx, vm, oldVM)...)
 	fieldErrs = append(fieldErrs, v.validateBootstrap(ctx, vm)...)
-	fieldErrs = append(fieldErrs, v.validateNetwork(ctx, vm)...)
+	fieldErrs = append(fieldErrs, v.validateVAppProperties(ctx, vm)...)
+	fieldErrs = append(fieldErrs, v.validateNetwork(ctx, vm, oldVM)...)
 	fieldErrs = append(fieldErrs, v.validateVolumes(ctx, vm)...)
 	fieldErrs = append(fieldErrs, v.validateInstanceStorageVolumes(ctx, vm, oldVM)...)
+	fieldErrs = append(fieldErrs, v.validateFirstClassDiskVolumes(ctx, vm, oldVM)...)
 	fieldErrs = append(fieldErrs, v.validateReadinessProbe(ctx, vm)...)
-	fieldErrs = append(fieldErrs, v.validateAdvanced(ctx, vm)...)
+	fieldErrs = append(fieldErrs, v.validateAdvanced(ctx, vm, oldVM)...)
 	fieldErrs = append(fieldErrs, v.validateInstanceStorageVolumes(ctx, vm, oldVM)...)
 	fieldErrs = append(fieldErrs, v.validateNextRestartTimeOnUpdate(ctx, vm, oldVM)...)
 	fieldErrs = append(fieldErrs, v.validateAnnotation(ctx, vm, oldVM)...)
@@ -236,6 +257,10 @@ func (v validator) validateBootstrap(
 				allErrs = append(allErrs, field.Invalid(p, "sysPrep",
 					"sysprep and rawSysprep are mutually exclusive"))
 			}
+
+			if sysPrep.Sysprep != nil {
+				allErrs = append(allErrs, validateInlineSysprep(p.Child("sysprep"), vm, sysPrep.Sysprep)...)
+			}
 		} else {
 			allErrs = append(allErrs, field.Invalid(p, "Sysprep", fmt.Sprintf(featureNotEnabled, "Sysprep")))
 		}
@@ -270,6 +295,50 @@ func (v validator) validateBootstrap(
 	return allErrs
 }
 
+func (v validator) validateVAppProperties(
+	ctx *context.WebhookRequestContext,
+	vm *vmopv1.VirtualMachine) field.ErrorList {
+
+	if len(vm.Spec.VAppProperties) == 0 {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	p := field.NewPath("spec", "vAppProperties")
+
+	if vm.Spec.Bootstrap != nil && vm.Spec.Bootstrap.VAppConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(p,
+			"vAppProperties may not be used in conjunction with vAppConfig bootstrap provider"))
+	}
+
+	// The CloudInit bootstrap provider removes the VM's vApp configuration, and the properties
+	// with it.
+	if vm.Spec.Bootstrap != nil && vm.Spec.Bootstrap.CloudInit != nil {
+		allErrs = append(allErrs, field.Forbidden(p,
+			"vAppProperties may not be used in conjunction with CloudInit bootstrap provider"))
+	}
+
+	keys := make([]string, 0, len(vm.Spec.VAppProperties))
+	for key := range vm.Spec.VAppProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "" {
+			allErrs = append(allErrs, field.Invalid(p.Key(key), key,
+				"key is a required field in vAppProperties"))
+			continue
+		}
+		if value := vm.Spec.VAppProperties[key]; value.From != nil && value.Value != nil {
+			allErrs = append(allErrs, field.Invalid(p.Key(key), "value",
+				"from and value is mutually exclusive"))
+		}
+	}
+
+	return allErrs
+}
+
 func (v validator) validateImage(ctx *context.WebhookRequestContext, vm *vmopv1.VirtualMachine) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -293,12 +362,26 @@ func (v validator) validateClass(ctx *context.WebhookRequestContext, vm *vmopv1.
 func (v validator) validateStorageClass(ctx *context.WebhookRequestContext, vm *vmopv1.VirtualMachine) field.ErrorList {
 	var allErrs field.ErrorList
 
-	if vm.Spec.StorageClass == "" {
-		return allErrs
+	if vm.Spec.StorageClass != "" {
+		allErrs = append(allErrs, v.validateStorageClassName(ctx, vm, field.NewPath("spec", "storageClass"), vm.Spec.StorageClass)...)
 	}
 
-	scPath := field.NewPath("spec", "storageClass")
-	scName := vm.Spec.StorageClass
+	if advanced := vm.Spec.Advanced; advanced != nil && advanced.BootDiskStorageClass != "" {
+		scPath := field.NewPath("spec", "advanced", "bootDiskStorageClass")
+		allErrs = append(allErrs, v.validateStorageClassName(ctx, vm, scPath, advanced.BootDiskStorageClass)...)
+	}
+
+	return allErrs
+}
+
+// validateStorageClassName validates the StorageClass exists and is assigned to the VM's namespace.
+func (v validator) validateStorageClassName(
+	ctx *context.WebhookRequestContext,
+	vm *vmopv1.VirtualMachine,
+	scPath *field.Path,
+	scName string) field.ErrorList {
+
+	var allErrs field.ErrorList
 
 	// TODO: This validation shouldn't be done in the webhook.
 
@@ -324,7 +407,43 @@ func (v validator) validateStorageClass(ctx *context.WebhookRequestContext, vm *
 	return append(allErrs, field.Invalid(scPath, scName, fmt.Sprintf(storageClassNotAssignedFmt, vm.Namespace)))
 }
 
-func (v validator) validateNetwork(ctx *context.WebhookRequestContext, vm *vmopv1.VirtualMachine) field.ErrorList {
+// validateInlineSysprep checks that the domain join of an inlined Sysprep has the domain admin
+// credentials, which the password of must come from a Secret. The guest's computer name is the
+// VM's name when the VM's host name is unset, so it is checked against the NetBIOS limit. The
+// host name is checked by validateNetworkHostName.
+func validateInlineSysprep(p *field.Path, vm *vmopv1.VirtualMachine, sysPrep *sysprep.Sysprep) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if vm.Spec.Network == nil || vm.Spec.Network.HostName == "" {
+		if len(vm.Name) > maxSysprepComputerNameLength {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), vm.Name,
+				fmt.Sprintf(sysprepComputerNameTooLongFmt, maxSysprepComputerNameLength)))
+		}
+	}
+
+	identification := sysPrep.Identification
+	p = p.Child("identification")
+
+	if identification.JoinDomain != "" && identification.JoinWorkgroup != "" {
+		allErrs = append(allErrs, field.Invalid(p, "identification",
+			"joinDomain and joinWorkgroup are mutually exclusive"))
+	}
+
+	if identification.JoinDomain != "" {
+		if identification.DomainAdmin == "" {
+			allErrs = append(allErrs, field.Required(p.Child("domainAdmin"),
+				"domainAdmin is required when joining a domain"))
+		}
+		if identification.DomainAdminPassword.Name == "" || identification.DomainAdminPassword.Key == "" {
+			allErrs = append(allErrs, field.Required(p.Child("domainAdminPassword"),
+				"domainAdminPassword must reference a Secret key when joining a domain"))
+		}
+	}
+
+	return allErrs
+}
+
+func (v validator) validateNetwork(ctx *context.WebhookRequestContext, vm, oldVM *vmopv1.VirtualMachine) field.ErrorList {
 	var allErrs field.ErrorList
 
 	networkSpec := vm.Spec.Network
@@ -334,11 +453,22 @@ func (v validator) validateNetwork(ctx *context.WebhookRequestContext, vm *vmopv
 
 	networkPath := field.NewPath("spec", "network")
 
+	if hostName := networkSpec.HostName; hostName != "" {
+		allErrs = append(allErrs, v.validateNetworkHostName(networkPath.Child("hostName"), hostName, vm)...)
+	}
+
+	allErrs = append(allErrs, v.validateNetworkDNS(networkPath, networkSpec, vm)...)
+
 	if len(networkSpec.Interfaces) > 0 {
 		p := networkPath.Child("interfaces")
 
+		if maxInterfaces := lib.GetMaxNetworkInterfacesPerVM(); len(networkSpec.Interfaces) > maxInterfaces {
+			allErrs = append(allErrs, field.TooMany(p, len(networkSpec.Interfaces), maxInterfaces))
+		}
+
 		for i, interfaceSpec := range networkSpec.Interfaces {
 			allErrs = append(allErrs, v.validateNetworkInterfaceSpec(p.Index(i), interfaceSpec, vm.Name)...)
+			allErrs = append(allErrs, v.validateNetworkInterfaceMTU(p.Index(i).Child("mtu"), interfaceSpec, oldVM)...)
 			allErrs = append(allErrs, v.validateNetworkSpecWithBootstrap(p.Index(i), interfaceSpec, vm)...)
 		}
 	}
@@ -346,6 +476,122 @@ func (v validator) validateNetwork(ctx *context.WebhookRequestContext, vm *vmopv
 	return allErrs
 }
 
+// validateNetworkDNS validates the VM's nameservers and search domains that are used for the
+// interfaces that do not specify their own.
+func (v validator) validateNetworkDNS(
+	networkPath *field.Path,
+	networkSpec *vmopv1.VirtualMachineNetworkSpec,
+	vm *vmopv1.VirtualMachine) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	for i, n := range networkSpec.Nameservers {
+		if net.ParseIP(n) == nil {
+			allErrs = append(allErrs,
+				field.Invalid(networkPath.Child("nameservers").Index(i), n, "must be an IPv4 or IPv6 address"))
+		}
+	}
+
+	if len(networkSpec.Nameservers) == 0 && len(networkSpec.SearchDomains) == 0 {
+		return allErrs
+	}
+
+	var (
+		cloudInit *vmopv1.VirtualMachineBootstrapCloudInitSpec
+		linuxPrep *vmopv1.VirtualMachineBootstrapLinuxPrepSpec
+		sysPrep   *vmopv1.VirtualMachineBootstrapSysprepSpec
+	)
+
+	if vm.Spec.Bootstrap != nil {
+		cloudInit = vm.Spec.Bootstrap.CloudInit
+		linuxPrep = vm.Spec.Bootstrap.LinuxPrep
+		sysPrep = vm.Spec.Bootstrap.Sysprep
+	}
+
+	sysprepNotAllowed := !lib.IsWindowsSysprepFSSEnabled() || sysPrep == nil || sysPrep.RawSysprep != nil
+	if cloudInit != nil || linuxPrep != nil || !sysprepNotAllowed {
+		return allErrs
+	}
+
+	if nameservers := networkSpec.Nameservers; len(nameservers) > 0 {
+		allErrs = append(allErrs, field.Invalid(
+			networkPath.Child("nameservers"),
+			strings.Join(nameservers, ","),
+			"nameservers is available only with the following bootstrap providers: CloudInit LinuxPrep and Sysprep (except for RawSysprep)",
+		))
+	}
+
+	if searchDomains := networkSpec.SearchDomains; len(searchDomains) > 0 {
+		allErrs = append(allErrs, field.Invalid(
+			networkPath.Child("searchDomains"),
+			strings.Join(searchDomains, ","),
+			"searchDomains is available only with the following bootstrap providers: CloudInit LinuxPrep and Sysprep (except for RawSysprep)",
+		))
+	}
+
+	return allErrs
+}
+
+// validateNetworkHostName validates the guest host name is either a host name or a fully
+// qualified domain name: a DNS subdomain whose first label is a valid host name. The host name of
+// a VM with an inlined Sysprep is the guest's computer name, so is also checked against the
+// NetBIOS limit.
+func (v validator) validateNetworkHostName(
+	hostNamePath *field.Path,
+	hostName string,
+	vm *vmopv1.VirtualMachine) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	for _, msg := range utilvalidation.IsDNS1123Subdomain(hostName) {
+		allErrs = append(allErrs, field.Invalid(hostNamePath, hostName, msg))
+	}
+
+	if len(allErrs) == 0 {
+		label, _, _ := strings.Cut(hostName, ".")
+		for _, msg := range utilvalidation.IsDNS1123Label(label) {
+			allErrs = append(allErrs, field.Invalid(hostNamePath, hostName, "host name "+msg))
+		}
+
+		if bootstrap := vm.Spec.Bootstrap; bootstrap != nil && bootstrap.Sysprep != nil && bootstrap.Sysprep.Sysprep != nil {
+			if len(label) > maxSysprepComputerNameLength {
+				allErrs = append(allErrs, field.Invalid(hostNamePath, label,
+					fmt.Sprintf(sysprepComputerNameTooLongFmt, maxSysprepComputerNameLength)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateNetworkInterfaceMTU validates the interface's MTU is within range. On update, the MTU is
+// only validated when it changes so a VM created before the range was enforced can still be updated.
+func (v validator) validateNetworkInterfaceMTU(
+	mtuPath *field.Path,
+	interfaceSpec vmopv1.VirtualMachineNetworkInterfaceSpec,
+	oldVM *vmopv1.VirtualMachine) field.ErrorList {
+
+	mtu := interfaceSpec.MTU
+	if mtu == nil || (*mtu >= minNetworkInterfaceMTU && *mtu <= maxNetworkInterfaceMTU) {
+		return nil
+	}
+
+	if oldVM != nil && oldVM.Spec.Network != nil {
+		for _, oldInterfaceSpec := range oldVM.Spec.Network.Interfaces {
+			if oldInterfaceSpec.Name == interfaceSpec.Name {
+				if oldMTU := oldInterfaceSpec.MTU; oldMTU != nil && *oldMTU == *mtu {
+					return nil
+				}
+				break
+			}
+		}
+	}
+
+	return field.ErrorList{
+		field.Invalid(mtuPath, *mtu, fmt.Sprintf("must be between %d and %d", minNetworkInterfaceMTU, maxNetworkInterfaceMTU)),
+	}
+}
+
 func (v validator) validateNetworkInterfaceSpec(
 	interfacePath *field.Path,
 	interfaceSpec vmopv1.VirtualMachineNetworkInterfaceSpec,
@@ -553,10 +799,17 @@ func (v validator) validateVolumes(ctx *context.WebhookRequestContext, vm *vmopv
 			}
 		}
 
-		if vol.PersistentVolumeClaim == nil {
-			allErrs = append(allErrs, field.Required(volPath.Child("persistentVolumeClaim"), ""))
-		} else {
+		switch {
+		case vol.PersistentVolumeClaim != nil && vol.FirstClassDisk != nil:
+			allErrs = append(allErrs, field.Forbidden(volPath, volumeOnlyOneSource))
+		case vol.PersistentVolumeClaim != nil:
 			allErrs = append(allErrs, v.validateVolumeWithPVC(ctx, vol, volPath)...)
+		case vol.FirstClassDisk != nil:
+			if vol.FirstClassDisk.DiskID == "" {
+				allErrs = append(allErrs, field.Required(volPath.Child("firstClassDisk", "diskID"), ""))
+			}
+		default:
+			allErrs = append(allErrs, field.Required(volPath.Child("persistentVolumeClaim"), ""))
 		}
 	}
 
@@ -605,6 +858,41 @@ func (v validator) validateInstanceStorageVolumes(
 	return allErrs
 }
 
+// validateFirstClassDiskVolumes validates that only privileged users add or modify FirstClassDisk
+// volumes. A First Class Disk is looked up in every datastore by its ID, so the disk is not
+// necessarily one of the namespace's. Removing a FirstClassDisk volume is allowed.
+func (v validator) validateFirstClassDiskVolumes(
+	ctx *context.WebhookRequestContext, vm, oldVM *vmopv1.VirtualMachine) field.ErrorList {
+
+	var allErrs field.ErrorList
+
+	if ctx.IsPrivilegedAccount {
+		return allErrs
+	}
+
+	oldDiskIDs := map[string]string{}
+	if oldVM != nil {
+		for _, vol := range oldVM.Spec.Volumes {
+			if vol.FirstClassDisk != nil {
+				oldDiskIDs[vol.Name] = vol.FirstClassDisk.DiskID
+			}
+		}
+	}
+
+	volumesPath := field.NewPath("spec", "volumes")
+	for i, vol := range vm.Spec.Volumes {
+		if vol.FirstClassDisk == nil {
+			continue
+		}
+
+		if diskID, ok := oldDiskIDs[vol.Name]; !ok || diskID != vol.FirstClassDisk.DiskID {
+			allErrs = append(allErrs, field.Forbidden(volumesPath.Index(i).Child("firstClassDisk"), addingModifyingFCDVolumesNotAllowed))
+		}
+	}
+
+	return allErrs
+}
+
 func (v validator) validateReadinessProbe(ctx *context.WebhookRequestContext, vm *vmopv1.VirtualMachine) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -640,7 +928,9 @@ func (v validator) validateReadinessProbe(ctx *context.WebhookRequestContext, vm
 
 var megaByte = resource.MustParse("1Mi")
 
-func (v validator) validateAdvanced(ctx *context.WebhookRequestContext, vm *vmopv1.VirtualMachine) field.ErrorList {
+func (v validator) validateAdvanced(
+	ctx *context.WebhookRequestContext,
+	vm, oldVM *vmopv1.VirtualMachine) field.ErrorList {
 	var allErrs field.ErrorList
 	advanced := vm.Spec.Advanced
 
@@ -657,9 +947,54 @@ func (v validator) validateAdvanced(ctx *context.WebhookRequestContext, vm *vmop
 		}
 	}
 
+	// A serial port's URIs direct the host to listen on, or connect to, an arbitrary network
+	// endpoint, so only privileged users may add or change them.
+	oldSerialPorts := map[string]vmopv1.VirtualMachineSerialPortSpec{}
+	if oldVM != nil && oldVM.Spec.Advanced != nil {
+		for _, port := range oldVM.Spec.Advanced.SerialPorts {
+			oldSerialPorts[port.Name] = port
+		}
+	}
+
+	serialPortNames := map[string]struct{}{}
+	for i, port := range advanced.SerialPorts {
+		portPath := advancedPath.Child("serialPorts").Index(i)
+
+		if _, ok := serialPortNames[port.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(portPath.Child("name"), port.Name))
+		}
+		serialPortNames[port.Name] = struct{}{}
+
+		allErrs = append(allErrs, validateSerialPortURI(portPath.Child("uri"), port.URI)...)
+		if port.ProxyURI != "" {
+			allErrs = append(allErrs, validateSerialPortURI(portPath.Child("proxyURI"), port.ProxyURI)...)
+		}
+
+		if !ctx.IsPrivilegedAccount {
+			if oldPort, ok := oldSerialPorts[port.Name]; !ok || oldPort.URI != port.URI || oldPort.ProxyURI != port.ProxyURI {
+				allErrs = append(allErrs, field.Forbidden(portPath, addingModifyingSerialPortNotAllowed))
+			}
+		}
+	}
+
 	return allErrs
 }
 
+// validateSerialPortURI validates that a network serial port URI has a scheme that vSphere
+// supports for the serial port's network backing.
+func validateSerialPortURI(uriPath *field.Path, uri string) field.ErrorList {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return field.ErrorList{field.Invalid(uriPath, uri, fmt.Sprintf(invalidSerialPortURIFmt, err))}
+	}
+
+	if u.Scheme != "telnet" && u.Scheme != "tcp" {
+		return field.ErrorList{field.Invalid(uriPath, uri, invalidSerialPortURIScheme)}
+	}
+
+	return nil
+}
+
 func (v validator) validateNextRestartTimeOnCreate(
 	ctx *context.WebhookRequestContext,
 	vm *vmopv1.VirtualMachine) field.ErrorList {
@@ -825,6 +1160,18 @@ func (v validator) validateImmutableFields(_ *context.WebhookRequestContext, vm,
 	}
 	allErrs = append(allErrs, validation.ValidateImmutableField(newResourcePolicyName, oldResourcePolicyName, specPath.Child("reserved", "resourcePolicyName"))...)
 
+	var (
+		oldBootDiskStorageClass string
+		newBootDiskStorageClass string
+	)
+	if advanced := oldVM.Spec.Advanced; advanced != nil {
+		oldBootDiskStorageClass = advanced.BootDiskStorageClass
+	}
+	if advanced := vm.Spec.Advanced; advanced != nil {
+		newBootDiskStorageClass = advanced.BootDiskStorageClass
+	}
+	allErrs = append(allErrs, validation.ValidateImmutableField(newBootDiskStorageClass, oldBootDiskStorageClass, specPath.Child("advanced", "bootDiskStorageClass"))...)
+
 	return allErrs
 }
 
@@ -877,6 +1224,20 @@ func (v validator) isNetworkRestrictedForReadinessProbe(ctx *context.WebhookRequ
 func (v validator) validateAnnotation(ctx *context.WebhookRequestContext, vm, oldVM *vmopv1.VirtualMachine) field.ErrorList {
 	var allErrs field.ErrorList
 
+	annotationPath := field.NewPath("metadata", "annotations")
+
+	if value, ok := vm.Annotations[vmopv1.DeletionGracePeriodAnnotation]; ok {
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			allErrs = append(allErrs, field.Invalid(annotationPath.Child(vmopv1.DeletionGracePeriodAnnotation),
+				value, invalidDeletionGracePeriod))
+		} else if maxGracePeriod := lib.GetMaxDeletionGracePeriod(); maxGracePeriod > 0 && d > maxGracePeriod {
+			allErrs = append(allErrs, field.Invalid(annotationPath.Child(vmopv1.DeletionGracePeriodAnnotation),
+				value, fmt.Sprintf(deletionGracePeriodExceedsMaxFmt, maxGracePeriod)))
+		}
+	}
+
+	allErrs = append(allErrs, v.validateDisplayName(ctx, vm, oldVM)...)
+
 	if ctx.IsPrivilegedAccount {
 		return allErrs
 	}
@@ -886,8 +1247,6 @@ func (v validator) validateAnnotation(ctx *context.WebhookRequestContext, vm, ol
 		oldVM = &vmopv1.VirtualMachine{}
 	}
 
-	annotationPath := field.NewPath("metadata", "annotations")
-
 	if vm.Annotations[vmopv1.InstanceIDAnnotation] != oldVM.Annotations[vmopv1.InstanceIDAnnotation] {
 		allErrs = append(allErrs, field.Forbidden(annotationPath.Child(vmopv1.InstanceIDAnnotation), modifyAnnotationNotAllowedForNonAdmin))
 	}
@@ -898,3 +1257,31 @@ func (v validator) validateAnnotation(ctx *context.WebhookRequestContext, vm, ol
 
 	return allErrs
 }
+
+// validateDisplayName validates that a new or changed display name annotation does not collide
+// with the name or display name of another VM in the namespace, since the vSphere VM would then
+// be indistinguishable from the other VM's vSphere VM.
+func (v validator) validateDisplayName(ctx *context.WebhookRequestContext, vm, oldVM *vmopv1.VirtualMachine) field.ErrorList {
+	displayName := vm.Annotations[constants.DisplayNameAnnotation]
+	if displayName == "" || (oldVM != nil && oldVM.Annotations[constants.DisplayNameAnnotation] == displayName) {
+		return nil
+	}
+
+	displayNamePath := field.NewPath("metadata", "annotations").Key(constants.DisplayNameAnnotation)
+
+	vmList := &vmopv1.VirtualMachineList{}
+	if err := v.client.List(ctx, vmList, client.InNamespace(vm.Namespace)); err != nil {
+		return field.ErrorList{field.InternalError(displayNamePath, err)}
+	}
+
+	for _, other := range vmList.Items {
+		if other.Name == vm.Name {
+			continue
+		}
+		if other.Name == displayName || other.Annotations[constants.DisplayNameAnnotation] == displayName {
+			return field.ErrorList{field.Invalid(displayNamePath, displayName, fmt.Sprintf(displayNameConflictFmt, other.Name))}
+		}
+	}
+
+	return nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/vmware-tanzu/vm-operator/pkg/lib"
 	"github.com/vmware-tanzu/vm-operator/pkg/topology"
 	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/config"
+	"github.com/vmware-tanzu/vm-operator/pkg/vmprovider/providers/vsphere2/constants"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
 
@@ -91,11 +92,17 @@ func unitTestsValidateCreate() {
 		invalidVolumeName                 bool
 		dupVolumeName                     bool
 		invalidVolumeSource               bool
+		withFCDVolume                     bool
+		withFCDAndPVCVolume               bool
+		invalidFCDDiskID                  bool
 		invalidPVCName                    bool
 		invalidPVCReadOnly                bool
 		invalidStorageClass               bool
 		notFoundStorageClass              bool
 		validStorageClass                 bool
+		invalidBootDiskStorageClass       bool
+		notFoundBootDiskStorageClass      bool
+		validBootDiskStorageClass         bool
 		withInstanceStorageVolumes        bool
 		invalidReadinessProbe             bool
 		isRestrictedNetworkEnv            bool
@@ -109,6 +116,9 @@ func unitTestsValidateCreate() {
 		nextRestartTime                   string
 		adminOnlyAnnotations              bool
 		isPrivilegedUser                  bool
+		deletionGracePeriod               string
+		maxDeletionGracePeriod            string
+		displayName                       string
 	}
 
 	validateCreate := func(args createArgs, expectedAllowed bool, expectedReason string, expectedErr error) {
@@ -133,6 +143,17 @@ func unitTestsValidateCreate() {
 		if args.invalidVolumeSource {
 			ctx.vm.Spec.Volumes[0].PersistentVolumeClaim = nil
 		}
+		if args.withFCDVolume || args.withFCDAndPVCVolume || args.invalidFCDDiskID {
+			if !args.withFCDAndPVCVolume {
+				ctx.vm.Spec.Volumes[0].PersistentVolumeClaim = nil
+			}
+			ctx.vm.Spec.Volumes[0].FirstClassDisk = &vmopv1.FirstClassDiskVolumeSource{
+				DiskID: "fcd-disk-id",
+			}
+			if args.invalidFCDDiskID {
+				ctx.vm.Spec.Volumes[0].FirstClassDisk.DiskID = ""
+			}
+		}
 		if args.invalidPVCName {
 			ctx.vm.Spec.Volumes[0].PersistentVolumeClaim.ClaimName = ""
 		}
@@ -161,6 +182,26 @@ func unitTestsValidateCreate() {
 			Expect(ctx.Client.Create(ctx, resourceQuota)).To(Succeed())
 		}
 
+		if args.invalidBootDiskStorageClass || args.notFoundBootDiskStorageClass || args.validBootDiskStorageClass {
+			if ctx.vm.Spec.Advanced == nil {
+				ctx.vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+			}
+			ctx.vm.Spec.Advanced.BootDiskStorageClass = builder.DummyStorageClassName
+		}
+		if args.invalidBootDiskStorageClass {
+			// Boot disk StorageClass specified but not assigned to ResourceQuota.
+			Expect(ctx.Client.Create(ctx, builder.DummyStorageClass())).To(Succeed())
+		}
+		if args.validBootDiskStorageClass {
+			// Boot disk StorageClass specified and is assigned to ResourceQuota.
+			storageClass := builder.DummyStorageClass()
+			Expect(ctx.Client.Create(ctx, storageClass)).To(Succeed())
+
+			rlName := storageClass.Name + ".storageclass.storage.k8s.io/persistentvolumeclaims"
+			resourceQuota := builder.DummyResourceQuota(ctx.vm.Namespace, rlName)
+			Expect(ctx.Client.Create(ctx, resourceQuota)).To(Succeed())
+		}
+
 		if args.withInstanceStorageVolumes {
 			instanceStorageVolumes := builder.DummyInstanceStorageVirtualMachineVolumesA2()
 			ctx.vm.Spec.Volumes = append(ctx.vm.Spec.Volumes, instanceStorageVolumes...)
@@ -218,6 +259,22 @@ func unitTestsValidateCreate() {
 			ctx.vm.Annotations[vmopv1.FirstBootDoneAnnotation] = updateSuffix
 		}
 
+		if args.deletionGracePeriod != "" {
+			ctx.vm.Annotations[vmopv1.DeletionGracePeriodAnnotation] = args.deletionGracePeriod
+		}
+		if args.maxDeletionGracePeriod != "" {
+			Expect(os.Setenv(lib.MaxDeletionGracePeriodEnv, args.maxDeletionGracePeriod)).To(Succeed())
+		}
+
+		if args.displayName != "" {
+			otherVM := builder.DummyVirtualMachineA2()
+			otherVM.Name = "other-vm"
+			otherVM.Namespace = ctx.vm.Namespace
+			Expect(ctx.Client.Create(ctx, otherVM)).To(Succeed())
+
+			ctx.vm.Annotations[constants.DisplayNameAnnotation] = args.displayName
+		}
+
 		if args.isPrivilegedUser {
 			lib.IsVMServiceBackupRestoreFSSEnabled = func() bool {
 				return true
@@ -258,6 +315,7 @@ func unitTestsValidateCreate() {
 	AfterEach(func() {
 		Expect(os.Unsetenv(lib.WcpFaultDomainsFSS)).To(Succeed())
 		Expect(os.Unsetenv(lib.WindowsSysprepFSS)).To(Succeed())
+		Expect(os.Unsetenv(lib.MaxDeletionGracePeriodEnv)).To(Succeed())
 		lib.IsVMServiceBackupRestoreFSSEnabled = oldVMServiceBackupRestoreFunc
 		ctx = nil
 	})
@@ -285,6 +343,13 @@ func unitTestsValidateCreate() {
 			field.Duplicate(volPath.Index(1).Child("name"), "duplicate-name").Error(), nil),
 		Entry("should deny invalid volume source spec", createArgs{invalidVolumeSource: true}, false,
 			field.Required(volPath.Index(0).Child("persistentVolumeClaim"), "").Error(), nil),
+		Entry("should allow FirstClassDisk volume for service user", createArgs{isServiceUser: true, withFCDVolume: true}, true, nil, nil),
+		Entry("should deny FirstClassDisk volume for non-admin user", createArgs{withFCDVolume: true}, false,
+			field.Forbidden(volPath.Index(0).Child("firstClassDisk"), "adding or modifying first class disk volume(s) is not allowed for non-admin users").Error(), nil),
+		Entry("should deny volume with FirstClassDisk and PVC", createArgs{isServiceUser: true, withFCDAndPVCVolume: true}, false,
+			field.Forbidden(volPath.Index(0), "only one volume source can be specified").Error(), nil),
+		Entry("should deny invalid FirstClassDisk disk ID", createArgs{isServiceUser: true, invalidFCDDiskID: true}, false,
+			field.Required(volPath.Index(0).Child("firstClassDisk", "diskID"), "").Error(), nil),
 		Entry("should deny invalid PVC name", createArgs{invalidPVCName: true}, false,
 			field.Required(volPath.Index(0).Child("persistentVolumeClaim", "claimName"), "").Error(), nil),
 		Entry("should deny invalid PVC read only", createArgs{invalidPVCReadOnly: true}, false,
@@ -294,6 +359,11 @@ func unitTestsValidateCreate() {
 		Entry("should deny a StorageClass that is not associated with the namespace", createArgs{invalidStorageClass: true}, false,
 			field.Invalid(specPath.Child("storageClass"), builder.DummyStorageClassName, fmt.Sprintf("Storage policy is not associated with the namespace %s", "")).Error(), nil),
 		Entry("should allow valid storage class and resource quota", createArgs{validStorageClass: true}, true, nil, nil),
+		Entry("should deny a boot disk StorageClass that does not exist", createArgs{notFoundBootDiskStorageClass: true}, false,
+			field.Invalid(specPath.Child("advanced", "bootDiskStorageClass"), builder.DummyStorageClassName, fmt.Sprintf("Storage policy is not associated with the namespace %s", "")).Error(), nil),
+		Entry("should deny a boot disk StorageClass that is not associated with the namespace", createArgs{invalidBootDiskStorageClass: true}, false,
+			field.Invalid(specPath.Child("advanced", "bootDiskStorageClass"), builder.DummyStorageClassName, fmt.Sprintf("Storage policy is not associated with the namespace %s", "")).Error(), nil),
+		Entry("should allow valid boot disk storage class and resource quota", createArgs{validBootDiskStorageClass: true}, true, nil, nil),
 		Entry("should deny when there are instance storage volumes and user is SSO user", createArgs{withInstanceStorageVolumes: true}, false,
 			field.Forbidden(volPath, "adding or modifying instance storage volume claim(s) is not allowed").Error(), nil),
 		Entry("should allow when there are instance storage volumes and user is service user", createArgs{isServiceUser: true, withInstanceStorageVolumes: true}, true, nil, nil),
@@ -337,6 +407,19 @@ func unitTestsValidateCreate() {
 		Entry("should allow creating VM with admin-only annotations set by service user", createArgs{isServiceUser: true, adminOnlyAnnotations: true}, true, nil, nil),
 
 		Entry("should allow creating VM with admin-only annotations set by WCP user when the Backup/Restore FSS is enabled", createArgs{adminOnlyAnnotations: true, isPrivilegedUser: true}, true, nil, nil),
+
+		Entry("should allow creating VM with a valid deletion grace period", createArgs{deletionGracePeriod: "24h"}, true, nil, nil),
+		Entry("should disallow creating VM with an invalid deletion grace period", createArgs{deletionGracePeriod: "1 day"}, false,
+			field.Invalid(annotationPath.Child(vmopv1.DeletionGracePeriodAnnotation), "1 day", "must be a non-negative duration, ex. 24h").Error(), nil),
+		Entry("should disallow creating VM with a negative deletion grace period", createArgs{deletionGracePeriod: "-1h"}, false,
+			field.Invalid(annotationPath.Child(vmopv1.DeletionGracePeriodAnnotation), "-1h", "must be a non-negative duration, ex. 24h").Error(), nil),
+		Entry("should allow creating VM with a deletion grace period within the maximum", createArgs{deletionGracePeriod: "24h", maxDeletionGracePeriod: "48h"}, true, nil, nil),
+		Entry("should disallow creating VM with a deletion grace period that exceeds the maximum", createArgs{deletionGracePeriod: "72h", maxDeletionGracePeriod: "48h"}, false,
+			field.Invalid(annotationPath.Child(vmopv1.DeletionGracePeriodAnnotation), "72h", "must not exceed the maximum deletion grace period of 48h0m0s").Error(), nil),
+
+		Entry("should allow creating VM with a unique display name", createArgs{displayName: "my-display-name"}, true, nil, nil),
+		Entry("should disallow creating VM with the display name of another VM", createArgs{displayName: "other-vm"}, false,
+			field.Invalid(annotationPath.Key(constants.DisplayNameAnnotation), "other-vm", "display name is already used by VirtualMachine other-vm").Error(), nil),
 	)
 
 	Context("Bootstrap", func() {
@@ -526,6 +609,7 @@ func unitTestsValidateCreate() {
 				testParams{
 					setup: func(ctx *unitValidatingWebhookContext) {
 						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "win-vm"
 						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
 							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
 								Sysprep:    &sysprep.Sysprep{},
@@ -538,6 +622,107 @@ func unitTestsValidateCreate() {
 					),
 				},
 			),
+			Entry("allow inline Sysprep joining a domain with the domain admin password from a Secret",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "win-vm.example.com"
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
+								Sysprep: &sysprep.Sysprep{
+									Identification: sysprep.Identification{
+										JoinDomain:  "example.com",
+										DomainAdmin: "administrator",
+										DomainAdminPassword: corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "domain-secret"},
+											Key:                  "password",
+										},
+									},
+								},
+							},
+						}
+					},
+					expectAllowed: true,
+				},
+			),
+			Entry("disallow inline Sysprep joining a domain without the domain admin credentials",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "win-vm"
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
+								Sysprep: &sysprep.Sysprep{
+									Identification: sysprep.Identification{
+										JoinDomain: "example.com",
+									},
+								},
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.bootstrap.sysprep.sysprep.identification.domainAdmin: Required value: domainAdmin is required when joining a domain`,
+						`spec.bootstrap.sysprep.sysprep.identification.domainAdminPassword: Required value: domainAdminPassword must reference a Secret key when joining a domain`,
+					),
+				},
+			),
+			Entry("disallow inline Sysprep joining both a domain and a workgroup",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "win-vm"
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
+								Sysprep: &sysprep.Sysprep{
+									Identification: sysprep.Identification{
+										JoinDomain:    "example.com",
+										JoinWorkgroup: "WORKGROUP",
+										DomainAdmin:   "administrator",
+										DomainAdminPassword: corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "domain-secret"},
+											Key:                  "password",
+										},
+									},
+								},
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.bootstrap.sysprep.sysprep.identification: Invalid value: "identification": joinDomain and joinWorkgroup are mutually exclusive`,
+					),
+				},
+			),
+			Entry("disallow inline Sysprep when the VM's name is too long for the computer name",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
+								Sysprep: &sysprep.Sysprep{},
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`metadata.name: Invalid value: "dummy-vm-for-webhook-validation": the computer name of an inlined Sysprep must be at most 15 characters`,
+					),
+				},
+			),
+			Entry("disallow inline Sysprep when the host name is too long for the computer name",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "windows-server-2022.example.com"
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
+								Sysprep: &sysprep.Sysprep{},
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.network.hostName: Invalid value: "windows-server-2022": the computer name of an inlined Sysprep must be at most 15 characters`,
+					),
+				},
+			),
 			Entry("disallow vAppConfig mixing inline Properties and RawProperties",
 				testParams{
 					setup: func(ctx *unitValidatingWebhookContext) {
@@ -604,6 +789,74 @@ func unitTestsValidateCreate() {
 					),
 				},
 			),
+
+			Entry("allow vAppProperties",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.VAppProperties = map[string]common.ValueOrSecretKeySelector{
+							"key": {
+								Value: pointer.String("value"),
+							},
+						}
+					},
+					expectAllowed: true,
+				},
+			),
+
+			Entry("disallow vAppProperties and vAppConfig specified at the same time",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							VAppConfig: &vmopv1.VirtualMachineBootstrapVAppConfigSpec{},
+						}
+						ctx.vm.Spec.VAppProperties = map[string]common.ValueOrSecretKeySelector{
+							"key": {
+								Value: pointer.String("value"),
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.vAppProperties: Forbidden: vAppProperties may not be used in conjunction with vAppConfig bootstrap provider`,
+					),
+				},
+			),
+
+			Entry("disallow vAppProperties and CloudInit specified at the same time",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							CloudInit: &vmopv1.VirtualMachineBootstrapCloudInitSpec{},
+						}
+						ctx.vm.Spec.VAppProperties = map[string]common.ValueOrSecretKeySelector{
+							"key": {
+								Value: pointer.String("value"),
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.vAppProperties: Forbidden: vAppProperties may not be used in conjunction with CloudInit bootstrap provider`,
+					),
+				},
+			),
+
+			Entry("disallow vAppProperties mixing Value From Secret and direct String pointer",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.VAppProperties = map[string]common.ValueOrSecretKeySelector{
+							"key": {
+								From: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "secret-name"},
+									Key:                  "key",
+								},
+								Value: pointer.String("value"),
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.vAppProperties[key]: Invalid value: "value": from and value is mutually exclusive`,
+					),
+				},
+			),
 		)
 	})
 
@@ -615,6 +868,10 @@ func unitTestsValidateCreate() {
 			expectAllowed bool
 		}
 
+		AfterEach(func() {
+			Expect(os.Unsetenv(lib.MaxNetworkInterfacesPerVMEnv)).To(Succeed())
+		})
+
 		doTest := func(args testParams) {
 			args.setup(ctx)
 
@@ -641,6 +898,16 @@ func unitTestsValidateCreate() {
 			}
 		}
 
+		networkInterfaces := func(n int) []vmopv1.VirtualMachineNetworkInterfaceSpec {
+			interfaces := make([]vmopv1.VirtualMachineNetworkInterfaceSpec, 0, n)
+			for i := 0; i < n; i++ {
+				interfaces = append(interfaces, vmopv1.VirtualMachineNetworkInterfaceSpec{
+					Name: fmt.Sprintf("eth%d", i),
+				})
+			}
+			return interfaces
+		}
+
 		DescribeTable("network create", doTest,
 			Entry("allow default",
 				testParams{
@@ -651,6 +918,44 @@ func unitTestsValidateCreate() {
 				},
 			),
 
+			Entry("allow the maximum number of interfaces",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							Interfaces: networkInterfaces(10),
+						}
+					},
+					expectAllowed: true,
+				},
+			),
+
+			Entry("disallow more than the maximum number of interfaces",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							Interfaces: networkInterfaces(11),
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.network.interfaces: Too many: 11: must have at most 10 items`,
+					),
+				},
+			),
+
+			Entry("disallow more than the configured maximum number of interfaces",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						Expect(os.Setenv(lib.MaxNetworkInterfacesPerVMEnv, "2")).To(Succeed())
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							Interfaces: networkInterfaces(3),
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.network.interfaces: Too many: 3: must have at most 2 items`,
+					),
+				},
+			),
+
 			Entry("allow static",
 				testParams{
 					setup: func(ctx *unitValidatingWebhookContext) {
@@ -737,6 +1042,45 @@ func unitTestsValidateCreate() {
 				},
 			),
 
+			Entry("allow fully qualified domain name host name",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							HostName: "my-vm.domain.local",
+						}
+					},
+					expectAllowed: true,
+				},
+			),
+
+			Entry("disallow invalid host name",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							HostName: "My_VM",
+						}
+					},
+					validate: func(response admission.Response) {
+						Expect(string(response.Result.Reason)).To(HavePrefix(
+							`spec.network.hostName: Invalid value: "My_VM": a lowercase RFC 1123 subdomain must consist of`))
+					},
+				},
+			),
+
+			Entry("disallow host name longer than a DNS label",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							HostName: strings.Repeat("a", validation.DNS1123LabelMaxLength+1) + ".domain.local",
+						}
+					},
+					validate: doValidateWithMsg(
+						fmt.Sprintf(`spec.network.hostName: Invalid value: "%s.domain.local": host name must be no more than 63 characters`,
+							strings.Repeat("a", validation.DNS1123LabelMaxLength+1)),
+					),
+				},
+			),
+
 			Entry("disallow mixing static and dhcp",
 				testParams{
 					setup: func(ctx *unitValidatingWebhookContext) {
@@ -852,6 +1196,50 @@ func unitTestsValidateCreate() {
 				},
 			),
 
+			Entry("validate mtu is too small",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							CloudInit: &vmopv1.VirtualMachineBootstrapCloudInitSpec{},
+						}
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							HostName: "my-vm",
+							Interfaces: []vmopv1.VirtualMachineNetworkInterfaceSpec{
+								{
+									Name: "eth0",
+									MTU:  pointer.Int64(575),
+								},
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.network.interfaces[0].mtu: Invalid value: 575: must be between 576 and 9000`,
+					),
+				},
+			),
+
+			Entry("validate mtu is too large",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							CloudInit: &vmopv1.VirtualMachineBootstrapCloudInitSpec{},
+						}
+						ctx.vm.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+							HostName: "my-vm",
+							Interfaces: []vmopv1.VirtualMachineNetworkInterfaceSpec{
+								{
+									Name: "eth0",
+									MTU:  pointer.Int64(9001),
+								},
+							},
+						}
+					},
+					validate: doValidateWithMsg(
+						`spec.network.interfaces[0].mtu: Invalid value: 9001: must be between 576 and 9000`,
+					),
+				},
+			),
+
 			// Please note nameservers is available only with the following bootstrap
 			// providers: CloudInit, LinuxPrep, and Sysprep (except for RawSysprep).
 			Entry("validate nameservers when bootstrap doesn't support nameservers",
@@ -880,6 +1268,7 @@ func unitTestsValidateCreate() {
 				testParams{
 					setup: func(ctx *unitValidatingWebhookContext) {
 						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "win-vm"
 						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
 							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
 								Sysprep: &sysprep.Sysprep{},
@@ -894,11 +1283,48 @@ func unitTestsValidateCreate() {
 				},
 			),
 
+			Entry("validate VM nameservers and searchDomains when bootstrap doesn't support them",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							VAppConfig: &vmopv1.VirtualMachineBootstrapVAppConfigSpec{},
+						}
+						ctx.vm.Spec.Network.Nameservers = []string{
+							"8.8.8.8",
+							"not-an-ip",
+						}
+						ctx.vm.Spec.Network.SearchDomains = []string{"dev.local"}
+					},
+					validate: doValidateWithMsg(
+						`spec.network.nameservers[1]: Invalid value: "not-an-ip": must be an IPv4 or IPv6 address`,
+						`spec.network.nameservers: Invalid value: "8.8.8.8,not-an-ip": nameservers is available only with the following bootstrap providers: CloudInit LinuxPrep and Sysprep (except for RawSysprep)`,
+						`spec.network.searchDomains: Invalid value: "dev.local": searchDomains is available only with the following bootstrap providers: CloudInit LinuxPrep and Sysprep (except for RawSysprep)`,
+					),
+				},
+			),
+
+			Entry("validate VM nameservers and searchDomains when bootstrap supports them",
+				testParams{
+					setup: func(ctx *unitValidatingWebhookContext) {
+						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
+							LinuxPrep: &vmopv1.VirtualMachineBootstrapLinuxPrepSpec{},
+						}
+						ctx.vm.Spec.Network.Nameservers = []string{
+							"8.8.8.8",
+							"2001:4860:4860::8888",
+						}
+						ctx.vm.Spec.Network.SearchDomains = []string{"dev.local"}
+					},
+					expectAllowed: true,
+				},
+			),
+
 			// Please note routes is available only with the following bootstrap providers: CloudInit
 			Entry("validate routes when bootstrap doesn't support routes",
 				testParams{
 					setup: func(ctx *unitValidatingWebhookContext) {
 						Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
+						ctx.vm.Spec.Network.HostName = "win-vm"
 						ctx.vm.Spec.Bootstrap = &vmopv1.VirtualMachineBootstrapSpec{
 							Sysprep: &vmopv1.VirtualMachineBootstrapSysprepSpec{
 								Sysprep: &sysprep.Sysprep{},
@@ -1009,6 +1435,126 @@ func unitTestsValidateCreate() {
 			),
 		)
 	})
+
+	Context("Serial Ports", func() {
+
+		type testParams struct {
+			ports         []vmopv1.VirtualMachineSerialPortSpec
+			isServiceUser bool
+			validate      func(response admission.Response)
+			expectAllowed bool
+		}
+
+		doTest := func(args testParams) {
+			ctx.IsPrivilegedAccount = args.isServiceUser
+			ctx.vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SerialPorts: args.ports,
+			}
+
+			var err error
+			ctx.WebhookRequestContext.Obj, err = builder.ToUnstructured(ctx.vm)
+			Expect(err).ToNot(HaveOccurred())
+
+			response := ctx.ValidateCreate(&ctx.WebhookRequestContext)
+			Expect(response.Allowed).To(Equal(args.expectAllowed))
+
+			if args.validate != nil {
+				args.validate(response)
+			}
+		}
+
+		doValidateWithMsg := func(msgs ...string) func(admission.Response) {
+			return func(response admission.Response) {
+				reasons := strings.Split(string(response.Result.Reason), ", ")
+				for _, m := range msgs {
+					Expect(reasons).To(ContainElement(m))
+				}
+				// This may be overly strict in some cases but catches missed assertions.
+				Expect(reasons).To(HaveLen(len(msgs)))
+			}
+		}
+
+		DescribeTable("serial ports create", doTest,
+			Entry("allow telnet and tcp URIs",
+				testParams{
+					isServiceUser: true,
+					ports: []vmopv1.VirtualMachineSerialPortSpec{
+						{
+							Name: "console",
+							URI:  "telnet://:13370",
+						},
+						{
+							Name:      "debug",
+							URI:       "tcp://console.example.com:13371",
+							Direction: vmopv1.VirtualMachineSerialPortDirectionServer,
+							ProxyURI:  "telnet://vspc.example.com:13370",
+						},
+					},
+					expectAllowed: true,
+				},
+			),
+			Entry("disallow non-admin user from adding a serial port",
+				testParams{
+					ports: []vmopv1.VirtualMachineSerialPortSpec{
+						{
+							Name: "console",
+							URI:  "telnet://:13370",
+						},
+					},
+					validate: doValidateWithMsg(
+						`spec.advanced.serialPorts[0]: Forbidden: adding or modifying a serial port's URI is not allowed for non-admin users`,
+					),
+				},
+			),
+			Entry("disallow URI with another scheme",
+				testParams{
+					isServiceUser: true,
+					ports: []vmopv1.VirtualMachineSerialPortSpec{
+						{
+							Name: "console",
+							URI:  "http://console.example.com:13370",
+						},
+					},
+					validate: doValidateWithMsg(
+						`spec.advanced.serialPorts[0].uri: Invalid value: "http://console.example.com:13370": must be a URI with the telnet or tcp scheme`,
+					),
+				},
+			),
+			Entry("disallow proxy URI without a scheme",
+				testParams{
+					isServiceUser: true,
+					ports: []vmopv1.VirtualMachineSerialPortSpec{
+						{
+							Name:     "console",
+							URI:      "telnet://:13370",
+							ProxyURI: "vspc.example.com:13370",
+						},
+					},
+					validate: doValidateWithMsg(
+						`spec.advanced.serialPorts[0].proxyURI: Invalid value: "vspc.example.com:13370": must be a URI with the telnet or tcp scheme`,
+					),
+				},
+			),
+			Entry("disallow duplicate names",
+				testParams{
+					isServiceUser: true,
+					ports: []vmopv1.VirtualMachineSerialPortSpec{
+						{
+							Name: "console",
+							URI:  "telnet://:13370",
+						},
+						{
+							Name: "console",
+							URI:  "telnet://:13371",
+						},
+					},
+					validate: doValidateWithMsg(
+						`spec.advanced.serialPorts[1].name: Duplicate value: "console"`,
+					),
+				},
+			),
+		)
+	})
 }
 
 func unitTestsValidateUpdate() {
@@ -1022,6 +1568,7 @@ func unitTestsValidateUpdate() {
 		changeClassName             bool
 		changeImageName             bool
 		changeStorageClass          bool
+		changeBootDiskStorageClass  bool
 		changeResourcePolicy        bool
 		assignZoneName              bool
 		changeZoneName              bool
@@ -1029,6 +1576,8 @@ func unitTestsValidateUpdate() {
 		isSysprepTransportUsed      bool
 		withInstanceStorageVolumes  bool
 		changeInstanceStorageVolume bool
+		withSerialPort              bool
+		changeSerialPortURI         bool
 		oldPowerState               vmopv1.VirtualMachinePowerState
 		newPowerState               vmopv1.VirtualMachinePowerState
 		newPowerStateEmptyAllowed   bool
@@ -1038,6 +1587,8 @@ func unitTestsValidateUpdate() {
 		updateAdminOnlyAnnotations  bool
 		removeAdminOnlyAnnotations  bool
 		isPrivilegedUser            bool
+		withOutOfRangeMTU           bool
+		changeOutOfRangeMTU         bool
 	}
 
 	validateUpdate := func(args updateArgs, expectedAllowed bool, expectedReason string, expectedErr error) {
@@ -1060,6 +1611,12 @@ func unitTestsValidateUpdate() {
 		if args.changeStorageClass {
 			ctx.vm.Spec.StorageClass += updateSuffix
 		}
+		if args.changeBootDiskStorageClass {
+			if ctx.vm.Spec.Advanced == nil {
+				ctx.vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+			}
+			ctx.vm.Spec.Advanced.BootDiskStorageClass += updateSuffix
+		}
 		if ctx.vm.Spec.Reserved == nil {
 			ctx.vm.Spec.Reserved = &vmopv1.VirtualMachineReservedSpec{}
 		}
@@ -1086,6 +1643,23 @@ func unitTestsValidateUpdate() {
 			ctx.vm.Spec.Volumes = append(ctx.vm.Spec.Volumes, instanceStorageVolumes...)
 		}
 
+		if args.withSerialPort || args.changeSerialPortURI {
+			port := vmopv1.VirtualMachineSerialPortSpec{
+				Name: "console",
+				URI:  "telnet://:13370",
+			}
+			ctx.oldVM.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{
+				SerialPorts: []vmopv1.VirtualMachineSerialPortSpec{port},
+			}
+			if args.changeSerialPortURI {
+				port.URI = "telnet://:13371"
+			}
+			if ctx.vm.Spec.Advanced == nil {
+				ctx.vm.Spec.Advanced = &vmopv1.VirtualMachineAdvancedSpec{}
+			}
+			ctx.vm.Spec.Advanced.SerialPorts = []vmopv1.VirtualMachineSerialPortSpec{port}
+		}
+
 		if args.isSysprepFeatureEnabled {
 			Expect(os.Setenv(lib.WindowsSysprepFSS, "true")).To(Succeed())
 		}
@@ -1119,6 +1693,46 @@ func unitTestsValidateUpdate() {
 			ctx.oldVM.Annotations[vmopv1.FirstBootDoneAnnotation] = dummyFirstBootDoneVal
 		}
 
+		if args.withOutOfRangeMTU || args.changeOutOfRangeMTU {
+			bootstrap := &vmopv1.VirtualMachineBootstrapSpec{
+				CloudInit: &vmopv1.VirtualMachineBootstrapCloudInitSpec{},
+			}
+			ctx.oldVM.Spec.Bootstrap = bootstrap
+			ctx.oldVM.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+				Interfaces: []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name: "eth0",
+						MTU:  pointer.Int64(9216),
+					},
+				},
+			}
+			ctx.vm.Spec.Bootstrap = bootstrap.DeepCopy()
+			ctx.vm.Spec.Network = ctx.oldVM.Spec.Network.DeepCopy()
+			if args.changeOutOfRangeMTU {
+				ctx.vm.Spec.Network.Interfaces[0].MTU = pointer.Int64(9217)
+			}
+		}
+
+		if args.withOutOfRangeMTU || args.changeOutOfRangeMTU {
+			bootstrap := &vmopv1.VirtualMachineBootstrapSpec{
+				CloudInit: &vmopv1.VirtualMachineBootstrapCloudInitSpec{},
+			}
+			ctx.oldVM.Spec.Bootstrap = bootstrap
+			ctx.oldVM.Spec.Network = &vmopv1.VirtualMachineNetworkSpec{
+				Interfaces: []vmopv1.VirtualMachineNetworkInterfaceSpec{
+					{
+						Name: "eth0",
+						MTU:  pointer.Int64(9216),
+					},
+				},
+			}
+			ctx.vm.Spec.Bootstrap = bootstrap.DeepCopy()
+			ctx.vm.Spec.Network = ctx.oldVM.Spec.Network.DeepCopy()
+			if args.changeOutOfRangeMTU {
+				ctx.vm.Spec.Network.Interfaces[0].MTU = pointer.Int64(9217)
+			}
+		}
+
 		if args.isPrivilegedUser {
 			lib.IsVMServiceBackupRestoreFSSEnabled = func() bool {
 				return true
@@ -1178,6 +1792,7 @@ func unitTestsValidateUpdate() {
 		Entry("should deny image name change", updateArgs{changeImageName: true}, false, msg, nil),
 		Entry("should deny class name change", updateArgs{changeClassName: true}, false, msg, nil),
 		Entry("should deny storageClass change", updateArgs{changeStorageClass: true}, false, msg, nil),
+		Entry("should deny bootDiskStorageClass change", updateArgs{changeBootDiskStorageClass: true}, false, msg, nil),
 		Entry("should deny resourcePolicy change", updateArgs{changeResourcePolicy: true}, false, msg, nil),
 
 		Entry("should allow initial zone assignment", updateArgs{assignZoneName: true}, true, nil, nil),
@@ -1190,6 +1805,11 @@ func unitTestsValidateUpdate() {
 		Entry("should allow adding new instance storage volume, when user type is service user", updateArgs{withInstanceStorageVolumes: true, isServiceUser: true}, true, nil, nil),
 		Entry("should allow instance storage volume name change, when user type is service user", updateArgs{changeInstanceStorageVolume: true, isServiceUser: true}, true, nil, nil),
 
+		Entry("should allow unchanged serial port, when user is SSO user", updateArgs{withSerialPort: true}, true, nil, nil),
+		Entry("should deny serial port URI change, when user is SSO user", updateArgs{changeSerialPortURI: true}, false,
+			field.Forbidden(field.NewPath("spec", "advanced", "serialPorts").Index(0), "adding or modifying a serial port's URI is not allowed for non-admin users").Error(), nil),
+		Entry("should allow serial port URI change, when user type is service user", updateArgs{changeSerialPortURI: true, isServiceUser: true}, true, nil, nil),
+
 		Entry("should allow sysprep when FSS is enabled", updateArgs{isSysprepFeatureEnabled: true, isSysprepTransportUsed: true}, true, nil, nil),
 		Entry("should disallow sysprep when FSS is disabled", updateArgs{isSysprepFeatureEnabled: false, isSysprepTransportUsed: true}, false,
 			field.Invalid(field.NewPath("spec", "bootstrap", "sysprep"), "Sysprep", "the Sysprep feature is not enabled").Error(), nil),
@@ -1238,6 +1858,10 @@ func unitTestsValidateUpdate() {
 		Entry("should allow adding admin-only annotations by privileged users", updateArgs{isPrivilegedUser: true, addAdminOnlyAnnotations: true}, true, nil, nil),
 		Entry("should allow updating admin-only annotations by privileged users", updateArgs{isPrivilegedUser: true, updateAdminOnlyAnnotations: true}, true, nil, nil),
 		Entry("should allow removing admin-only annotations by privileged users", updateArgs{isPrivilegedUser: true, removeAdminOnlyAnnotations: true}, true, nil, nil),
+
+		Entry("should allow an unchanged out of range mtu", updateArgs{withOutOfRangeMTU: true}, true, nil, nil),
+		Entry("should deny changing an out of range mtu", updateArgs{changeOutOfRangeMTU: true}, false,
+			field.Invalid(field.NewPath("spec", "network", "interfaces").Index(0).Child("mtu"), 9217, "must be between 576 and 9000").Error(), nil),
 	)
 
 	When("the update is performed while object deletion", func() {
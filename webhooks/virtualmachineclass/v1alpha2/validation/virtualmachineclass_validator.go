@@ -4,9 +4,13 @@
 package validation
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -23,6 +27,7 @@ import (
 
 	"github.com/vmware-tanzu/vm-operator/pkg/builder"
 	"github.com/vmware-tanzu/vm-operator/pkg/context"
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	"github.com/vmware-tanzu/vm-operator/webhooks/common"
 )
 
@@ -31,8 +36,36 @@ const (
 
 	invalidCPUReqMsg    = "CPU request must not be larger than the CPU limit"
 	invalidMemoryReqMsg = "memory request must not be larger than the memory limit"
+
+	configSpecFieldNotAllowedMsg  = "field is not allowed in a VM class ConfigSpec"
+	configSpecExtraConfigKeyMsg   = "extraConfig keys with the guestinfo. prefix are reserved"
+	guestInfoExtraConfigKeyPrefix = "guestinfo."
+
+	inheritanceCycleFmt = "VirtualMachineClass must not inherit from itself: %s"
 )
 
+// allowedConfigSpecFields are the JSON names of the ConfigSpec fields that a VM class may set. Other
+// fields are either unique to each VM, like the UUIDs and files, or are managed by VM operator.
+var allowedConfigSpecFields = map[string]struct{}{
+	"version":                      {},
+	"guestId":                      {},
+	"firmware":                     {},
+	"numCPUs":                      {},
+	"numCoresPerSocket":            {},
+	"memoryMB":                     {},
+	"cpuHotAddEnabled":             {},
+	"cpuHotRemoveEnabled":          {},
+	"memoryHotAddEnabled":          {},
+	"cpuAllocation":                {},
+	"memoryAllocation":             {},
+	"memoryReservationLockedToMax": {},
+	"latencySensitivity":           {},
+	"nestedHVEnabled":              {},
+	"vPMCEnabled":                  {},
+	"deviceChange":                 {},
+	"extraConfig":                  {},
+}
+
 // +kubebuilder:webhook:verbs=create;update,path=/default-validate-vmoperator-vmware-com-v1alpha2-virtualmachineclass,mutating=false,failurePolicy=fail,groups=vmoperator.vmware.com,resources=virtualmachineclasses,versions=v1alpha2,name=default.validating.virtualmachineclass.v1alpha2.vmoperator.vmware.com,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=virtualmachineclasses,verbs=get;list
 // +kubebuilder:rbac:groups=vmoperator.vmware.com,resources=virtualmachineclasses/status,verbs=get
@@ -49,13 +82,15 @@ func AddToManager(ctx *context.ControllerManagerContext, mgr ctrlmgr.Manager) er
 }
 
 // NewValidator returns the package's Validator.
-func NewValidator(_ client.Client) builder.Validator {
+func NewValidator(client client.Client) builder.Validator {
 	return validator{
+		client:    client,
 		converter: runtime.DefaultUnstructuredConverter,
 	}
 }
 
 type validator struct {
+	client    client.Client
 	converter runtime.UnstructuredConverter
 }
 
@@ -72,6 +107,8 @@ func (v validator) ValidateCreate(ctx *context.WebhookRequestContext) admission.
 	var fieldErrs field.ErrorList
 
 	fieldErrs = append(fieldErrs, v.validatePolicies(ctx, vmClass, field.NewPath("spec", "policies"))...)
+	fieldErrs = append(fieldErrs, v.validateConfigSpec(ctx, vmClass, field.NewPath("spec", "configSpec"))...)
+	fieldErrs = append(fieldErrs, v.validateInheritsFrom(ctx, vmClass, field.NewPath("spec", "inheritsFrom"))...)
 
 	validationErrs := make([]string, 0, len(fieldErrs))
 	for _, fieldErr := range fieldErrs {
@@ -86,7 +123,25 @@ func (v validator) ValidateDelete(*context.WebhookRequestContext) admission.Resp
 }
 
 func (v validator) ValidateUpdate(ctx *context.WebhookRequestContext) admission.Response {
+	vmClass, err := v.vmClassFromUnstructured(ctx.Obj)
+	if err != nil {
+		return webhook.Errored(http.StatusBadRequest, err)
+	}
+
+	oldVMClass, err := v.vmClassFromUnstructured(ctx.OldObj)
+	if err != nil {
+		return webhook.Errored(http.StatusBadRequest, err)
+	}
+
 	var fieldErrs field.ErrorList
+
+	// Only a changed ConfigSpec is validated so an existing class with fields that are no
+	// longer allowed can still be updated.
+	if !bytes.Equal(vmClass.Spec.ConfigSpec, oldVMClass.Spec.ConfigSpec) {
+		fieldErrs = append(fieldErrs, v.validateConfigSpec(ctx, vmClass, field.NewPath("spec", "configSpec"))...)
+	}
+	fieldErrs = append(fieldErrs, v.validateInheritsFrom(ctx, vmClass, field.NewPath("spec", "inheritsFrom"))...)
+
 	validationErrs := make([]string, 0, len(fieldErrs))
 	for _, fieldErr := range fieldErrs {
 		validationErrs = append(validationErrs, fieldErr.Error())
@@ -117,6 +172,79 @@ func (v validator) validatePolicies(ctx *context.WebhookRequestContext, vmClass
 	return allErrs
 }
 
+// validateConfigSpec validates that the class ConfigSpec only sets the allowed fields.
+func (v validator) validateConfigSpec(ctx *context.WebhookRequestContext, vmClass *vmopv1.VirtualMachineClass,
+	csPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	rawConfigSpec := vmClass.Spec.ConfigSpec
+	if len(rawConfigSpec) == 0 {
+		return allErrs
+	}
+
+	configSpec, err := util.UnmarshalConfigSpecFromJSON(rawConfigSpec)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(csPath, string(rawConfigSpec), err.Error()))
+		return allErrs
+	}
+
+	csValue := reflect.ValueOf(*configSpec)
+	csType := csValue.Type()
+	for i := 0; i < csType.NumField(); i++ {
+		f := csType.Field(i)
+		if f.Anonymous || csValue.Field(i).IsZero() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if _, ok := allowedConfigSpecFields[name]; !ok {
+			allErrs = append(allErrs, field.Forbidden(csPath.Child(name), configSpecFieldNotAllowedMsg))
+		}
+	}
+
+	ecPath := csPath.Child("extraConfig")
+	for i, ec := range configSpec.ExtraConfig {
+		if optValue := ec.GetOptionValue(); optValue != nil && strings.HasPrefix(optValue.Key, guestInfoExtraConfigKeyPrefix) {
+			allErrs = append(allErrs, field.Forbidden(ecPath.Index(i).Child("key"), configSpecExtraConfigKeyMsg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateInheritsFrom validates that the class does not, directly or through its base classes,
+// inherit from itself. A base class that does not exist is allowed since the inheritance is only
+// resolved when a VM is deployed with the class.
+func (v validator) validateInheritsFrom(ctx *context.WebhookRequestContext, vmClass *vmopv1.VirtualMachineClass,
+	inheritsFromPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	chain := []string{vmClass.Name}
+	visited := map[string]struct{}{vmClass.Name: {}}
+
+	for baseName := vmClass.Spec.InheritsFrom; baseName != ""; {
+		chain = append(chain, baseName)
+		if _, ok := visited[baseName]; ok {
+			allErrs = append(allErrs, field.Invalid(inheritsFromPath, vmClass.Spec.InheritsFrom,
+				fmt.Sprintf(inheritanceCycleFmt, strings.Join(chain, " -> "))))
+			break
+		}
+		visited[baseName] = struct{}{}
+
+		baseClass := &vmopv1.VirtualMachineClass{}
+		if err := v.client.Get(ctx, client.ObjectKey{Name: baseName, Namespace: vmClass.Namespace}, baseClass); err != nil {
+			if !apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.InternalError(inheritsFromPath, err))
+			}
+			break
+		}
+
+		baseName = baseClass.Spec.InheritsFrom
+	}
+
+	return allErrs
+}
+
 // vmClassFromUnstructured returns the VirtualMachineClass from the unstructured object.
 func (v validator) vmClassFromUnstructured(obj runtime.Unstructured) (*vmopv1.VirtualMachineClass, error) {
 	vmClass := &vmopv1.VirtualMachineClass{}
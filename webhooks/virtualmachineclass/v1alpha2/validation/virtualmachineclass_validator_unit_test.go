@@ -4,9 +4,12 @@
 package validation_test
 
 import (
+	"encoding/json"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,6 +19,7 @@ import (
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 
+	"github.com/vmware-tanzu/vm-operator/pkg/util"
 	"github.com/vmware-tanzu/vm-operator/test/builder"
 )
 
@@ -62,6 +66,13 @@ func unitTestsValidateCreate() {
 		invalidMemoryRequest bool
 		noCPULimit           bool
 		noMemoryLimit        bool
+		allowedConfigSpec    bool
+		forbiddenConfigSpec  bool
+		guestInfoExtraConfig bool
+		inheritsFromSelf     bool
+		inheritsFromBase     bool
+		inheritsFromCycle    bool
+		inheritsFromMissing  bool
 	}
 
 	validateCreate := func(args createArgs, expectedAllowed bool, expectedReason string, expectedErr error) {
@@ -81,6 +92,51 @@ func unitTestsValidateCreate() {
 		if args.noMemoryLimit {
 			ctx.vmClass.Spec.Policies.Resources.Limits.Memory = resource.MustParse("0")
 		}
+		if args.allowedConfigSpec {
+			ctx.vmClass.Spec.ConfigSpec = configSpecToJSON(&vimtypes.VirtualMachineConfigSpec{
+				NumCPUs:  4,
+				MemoryMB: 4096,
+				DeviceChange: []vimtypes.BaseVirtualDeviceConfigSpec{
+					&vimtypes.VirtualDeviceConfigSpec{
+						Operation: vimtypes.VirtualDeviceConfigSpecOperationAdd,
+						Device:    &vimtypes.VirtualVmxnet3{},
+					},
+				},
+				ExtraConfig: []vimtypes.BaseOptionValue{
+					&vimtypes.OptionValue{Key: "disk.enableUUID", Value: "TRUE"},
+				},
+			})
+		}
+		if args.forbiddenConfigSpec {
+			ctx.vmClass.Spec.ConfigSpec = configSpecToJSON(&vimtypes.VirtualMachineConfigSpec{
+				NumCPUs: 4,
+				Files: &vimtypes.VirtualMachineFileInfo{
+					VmPathName: "[datastore1] my-vm/my-vm.vmx",
+				},
+			})
+		}
+		if args.guestInfoExtraConfig {
+			ctx.vmClass.Spec.ConfigSpec = configSpecToJSON(&vimtypes.VirtualMachineConfigSpec{
+				ExtraConfig: []vimtypes.BaseOptionValue{
+					&vimtypes.OptionValue{Key: "guestinfo.userdata", Value: "foo"},
+				},
+			})
+		}
+
+		if args.inheritsFromSelf {
+			ctx.vmClass.Spec.InheritsFrom = ctx.vmClass.Name
+		}
+		if args.inheritsFromBase || args.inheritsFromCycle {
+			baseClass := builder.DummyVirtualMachineClass2A2("my-base-class")
+			if args.inheritsFromCycle {
+				baseClass.Spec.InheritsFrom = ctx.vmClass.Name
+			}
+			Expect(ctx.Client.Create(ctx, baseClass)).To(Succeed())
+			ctx.vmClass.Spec.InheritsFrom = baseClass.Name
+		}
+		if args.inheritsFromMissing {
+			ctx.vmClass.Spec.InheritsFrom = "does-not-exist"
+		}
 
 		ctx.WebhookRequestContext.Obj, err = builder.ToUnstructured(ctx.vmClass)
 		Expect(err).ToNot(HaveOccurred())
@@ -97,6 +153,7 @@ func unitTestsValidateCreate() {
 
 	BeforeEach(func() {
 		ctx = newUnitTestContextForValidatingWebhook(false)
+		ctx.vmClass.Name = "my-class"
 	})
 	AfterEach(func() {
 		ctx = nil
@@ -105,15 +162,46 @@ func unitTestsValidateCreate() {
 	reqPath := field.NewPath("spec", "policies", "resources", "requests")
 	invalidCPUField := field.Invalid(reqPath.Child("cpu"), "2Gi", "CPU request must not be larger than the CPU limit")
 	invalidMemField := field.Invalid(reqPath.Child("memory"), "2Gi", "memory request must not be larger than the memory limit")
+	csPath := field.NewPath("spec", "configSpec")
+	forbiddenFilesField := field.Forbidden(csPath.Child("files"), "field is not allowed in a VM class ConfigSpec")
+	forbiddenExtraConfigField := field.Forbidden(csPath.Child("extraConfig").Index(0).Child("key"),
+		"extraConfig keys with the guestinfo. prefix are reserved")
+	inheritsFromPath := field.NewPath("spec", "inheritsFrom")
+	inheritsFromSelfField := field.Invalid(inheritsFromPath, "my-class",
+		"VirtualMachineClass must not inherit from itself: my-class -> my-class")
+	inheritsFromCycleField := field.Invalid(inheritsFromPath, "my-base-class",
+		"VirtualMachineClass must not inherit from itself: my-class -> my-base-class -> my-class")
 	DescribeTable("create table", validateCreate,
 		Entry("should allow valid", createArgs{}, true, nil, nil),
 		Entry("should allow no cpu limit", createArgs{noCPULimit: true}, true, nil, nil),
 		Entry("should allow no memory limit", createArgs{noMemoryLimit: true}, true, nil, nil),
 		Entry("should deny invalid cpu request", createArgs{invalidCPURequest: true}, false, invalidCPUField.Error(), nil),
 		Entry("should deny invalid memory request", createArgs{invalidMemoryRequest: true}, false, invalidMemField.Error(), nil),
+		Entry("should allow ConfigSpec with allowed fields", createArgs{allowedConfigSpec: true}, true, nil, nil),
+		Entry("should deny ConfigSpec with forbidden field", createArgs{forbiddenConfigSpec: true}, false, forbiddenFilesField.Error(), nil),
+		Entry("should deny ConfigSpec with guestinfo ExtraConfig key", createArgs{guestInfoExtraConfig: true}, false, forbiddenExtraConfigField.Error(), nil),
+		Entry("should allow inheriting from a base class", createArgs{inheritsFromBase: true}, true, nil, nil),
+		Entry("should allow inheriting from a base class that does not exist", createArgs{inheritsFromMissing: true}, true, nil, nil),
+		Entry("should deny inheriting from itself", createArgs{inheritsFromSelf: true}, false, inheritsFromSelfField.Error(), nil),
+		Entry("should deny inheriting from a base class that inherits from it", createArgs{inheritsFromCycle: true}, false, inheritsFromCycleField.Error(), nil),
 	)
 }
 
+func configSpecToJSON(configSpec *vimtypes.VirtualMachineConfigSpec) json.RawMessage {
+	data, err := util.MarshalConfigSpecToJSON(configSpec)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return data
+}
+
+func forbiddenConfigSpec(numCPUs int32) json.RawMessage {
+	return configSpecToJSON(&vimtypes.VirtualMachineConfigSpec{
+		NumCPUs: numCPUs,
+		Files: &vimtypes.VirtualMachineFileInfo{
+			VmPathName: "[datastore1] my-vm/my-vm.vmx",
+		},
+	})
+}
+
 func unitTestsValidateUpdate() {
 	var (
 		ctx      *unitValidatingWebhookContext
@@ -121,10 +209,12 @@ func unitTestsValidateUpdate() {
 	)
 
 	type updateArgs struct {
-		changeHwCPU    bool
-		changeHwMemory bool
-		changeCPU      bool
-		changeMemory   bool
+		changeHwCPU            bool
+		changeHwMemory         bool
+		changeCPU              bool
+		changeMemory           bool
+		oldForbiddenConfigSpec bool
+		forbiddenConfigSpec    bool
 	}
 
 	validateUpdate := func(args updateArgs, expectedAllowed bool, expectedReason string, expectedErr error) {
@@ -144,6 +234,15 @@ func unitTestsValidateUpdate() {
 			ctx.vmClass.Spec.Policies.Resources.Requests.Memory = resource.MustParse("5Gi")
 			ctx.vmClass.Spec.Policies.Resources.Limits.Memory = resource.MustParse("10Gi")
 		}
+		if args.oldForbiddenConfigSpec {
+			ctx.oldVMClass.Spec.ConfigSpec = forbiddenConfigSpec(4)
+			ctx.vmClass.Spec.ConfigSpec = ctx.oldVMClass.Spec.ConfigSpec
+			ctx.WebhookRequestContext.OldObj, err = builder.ToUnstructured(ctx.oldVMClass)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		if args.forbiddenConfigSpec {
+			ctx.vmClass.Spec.ConfigSpec = forbiddenConfigSpec(8)
+		}
 
 		ctx.WebhookRequestContext.Obj, err = builder.ToUnstructured(ctx.vmClass)
 		Expect(err).ToNot(HaveOccurred())
@@ -175,6 +274,15 @@ func unitTestsValidateUpdate() {
 		Entry("should allow policy memory change", updateArgs{changeMemory: true}, true, nil, nil),
 	)
 
+	DescribeTable("update ConfigSpec", validateUpdate,
+		Entry("should allow unchanged ConfigSpec with forbidden field",
+			updateArgs{oldForbiddenConfigSpec: true, changeHwCPU: true}, true, nil, nil),
+		Entry("should deny changed ConfigSpec with forbidden field",
+			updateArgs{oldForbiddenConfigSpec: true, forbiddenConfigSpec: true}, false, nil, nil),
+		Entry("should deny new ConfigSpec with forbidden field",
+			updateArgs{forbiddenConfigSpec: true}, false, nil, nil),
+	)
+
 	DescribeTable("update table", validateUpdate,
 		Entry("should allow", updateArgs{}, true, nil, nil),
 		Entry("should deny hw cpu change", updateArgs{changeHwCPU: true}, true, nil, nil),